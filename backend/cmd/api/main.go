@@ -18,6 +18,7 @@ import (
 	"github.com/sanches/finance-tracker-cc/backend/internal/config"
 	"github.com/sanches/finance-tracker-cc/backend/internal/handler"
 	"github.com/sanches/finance-tracker-cc/backend/internal/middleware"
+	"github.com/sanches/finance-tracker-cc/backend/internal/priceapi"
 	"github.com/sanches/finance-tracker-cc/backend/internal/rateapi"
 	"github.com/sanches/finance-tracker-cc/backend/internal/server"
 	"github.com/sanches/finance-tracker-cc/backend/internal/service"
@@ -47,30 +48,76 @@ func main() {
 	queries := store.New(pool)
 
 	// Services
-	inviteCodes := parseInviteCodes(cfg.InviteCodes)
-	if len(inviteCodes) == 0 {
-		log.Fatal("INVITE_CODES must contain at least one valid code")
-	}
-	authSvc := service.NewAuth(queries, cfg.JWTSecret, inviteCodes)
-	accountSvc := service.NewAccount(queries)
+	authSvc := service.NewAuth(queries, pool, cfg.JWTSecret, time.Duration(cfg.LoginAttemptRetentionDays)*24*time.Hour)
+	currencyPrecision := service.NewCurrencyPrecision(queries)
+	rateResolver := service.NewRateResolver(queries, splitList(cfg.ExchangeRatePivots))
+	accountSvc := service.NewAccount(queries, rateResolver, currencyPrecision)
+	accountGroupSvc := service.NewAccountGroup(queries)
+	householdSvc := service.NewHousehold(queries, pool)
+	debtSvc := service.NewDebt(queries, pool, currencyPrecision)
 	categorySvc := service.NewCategory(queries)
-	transactionSvc := service.NewTransaction(queries, pool)
-	reportSvc := service.NewReport(queries)
-	importSvc := service.NewImport(queries)
-	importFullSvc := service.NewImportFull(queries, pool)
-	exchangeRateSvc := service.NewExchangeRate(queries)
-	rateFetcher := rateapi.NewClient()
-	exchangeRateSyncSvc := service.NewExchangeRateSync(queries, rateFetcher)
-	currencySvc := service.NewCurrency(queries)
-	exportSvc := service.NewExport(queries)
+	transactionSvc := service.NewTransaction(queries, pool, rateResolver, currencyPrecision)
+	reportSvc := service.NewReport(queries, rateResolver, currencyPrecision)
+	importSvc := service.NewImport(queries, pool, currencyPrecision)
+	importFullSvc := service.NewImportFull(queries, pool, currencyPrecision)
+	exchangeRateSvc := service.NewExchangeRate(queries, rateResolver, currencyPrecision)
+	ratePrefs, err := rateapi.ParsePairPreferences(cfg.ExchangeRatePairProviders)
+	if err != nil {
+		log.Fatal("invalid EXCHANGE_RATE_PAIR_PROVIDERS: ", err)
+	}
+	rateFetcher, err := rateapi.NewRegistry(splitList(cfg.ExchangeRateProviders), ratePrefs)
+	if err != nil {
+		log.Fatal("invalid EXCHANGE_RATE_PROVIDERS: ", err)
+	}
+	exchangeRateSyncSvc := service.NewExchangeRateSync(queries, rateFetcher, cfg.ExchangeRateBackfillDelay, cfg.ExchangeRateBackfillMaxFetches)
+	currencySvc := service.NewCurrency(queries, currencyPrecision)
+	exportSvc := service.NewExport(queries, currencyPrecision)
 	userSvc := service.NewUser(queries, pool)
+	reconciliationSvc := service.NewReconciliation(queries, pool, currencyPrecision)
+	balanceAssertionSvc := service.NewBalanceAssertion(queries, currencyPrecision, transactionSvc)
+	creditCardSvc := service.NewCreditCard(queries, currencyPrecision)
+	loanSvc := service.NewLoan(queries, pool, currencyPrecision)
+	var priceProvider priceapi.Provider
+	if cfg.PriceProvider != "" {
+		priceProvider, err = priceapi.NewProvider(cfg.PriceProvider)
+		if err != nil {
+			log.Fatal("invalid PRICE_PROVIDER: ", err)
+		}
+	}
+	securitySvc := service.NewSecurity(queries, priceProvider, currencyPrecision)
+	investmentSvc := service.NewInvestment(queries, pool, currencyPrecision)
+	interestSvc := service.NewInterest(queries, pool, currencyPrecision)
+	adminSvc := service.NewAdmin(queries)
+	auditSvc := service.NewAudit(queries, time.Duration(cfg.AuditLogRetentionDays)*24*time.Hour)
+	trashSvc := service.NewTrash(queries, currencyPrecision, time.Duration(cfg.TrashRetentionDays)*24*time.Hour)
+	inviteCodes := splitList(cfg.InviteCodes)
+	if len(inviteCodes) == 0 {
+		slog.Warn("INVITE_CODES is empty, only invite codes created by admins allow registration")
+	}
+	if err := adminSvc.EnsureInviteCodes(context.Background(), inviteCodes); err != nil {
+		log.Fatal("failed to store INVITE_CODES: ", err)
+	}
+	if admins := splitList(cfg.AdminUsernames); len(admins) > 0 {
+		n, err := adminSvc.PromoteAdmins(context.Background(), admins)
+		if err != nil {
+			log.Fatal("failed to promote ADMIN_USERNAMES: ", err)
+		}
+		if n > 0 {
+			slog.Info("promoted admins from ADMIN_USERNAMES", "count", n)
+		}
+	}
 
 	// Middleware
 	authMw := middleware.NewAuth(cfg.JWTSecret)
+	adminMw := middleware.NewAdmin(queries)
+	auditMw := middleware.NewAudit(queries)
 
 	// Handlers
 	authH := handler.NewAuth(authSvc, cfg.CookieSecure, cfg.BasePath)
 	accountH := handler.NewAccount(accountSvc)
+	accountGroupH := handler.NewAccountGroup(accountGroupSvc)
+	householdH := handler.NewHousehold(householdSvc)
+	debtH := handler.NewDebt(debtSvc)
 	categoryH := handler.NewCategory(categorySvc)
 	transactionH := handler.NewTransaction(transactionSvc)
 	reportH := handler.NewReport(reportSvc)
@@ -80,9 +127,19 @@ func main() {
 	currencyH := handler.NewCurrency(currencySvc)
 	exportH := handler.NewExport(exportSvc)
 	userH := handler.NewUser(userSvc)
+	reconciliationH := handler.NewReconciliation(reconciliationSvc)
+	balanceAssertionH := handler.NewBalanceAssertion(balanceAssertionSvc)
+	creditCardH := handler.NewCreditCard(creditCardSvc)
+	loanH := handler.NewLoan(loanSvc)
+	securityH := handler.NewSecurity(securitySvc)
+	investmentH := handler.NewInvestment(investmentSvc)
+	interestH := handler.NewInterest(interestSvc)
+	adminH := handler.NewAdmin(adminSvc)
+	auditH := handler.NewAudit(auditSvc)
+	trashH := handler.NewTrash(trashSvc)
 
 	// Router
-	router := server.NewRouter(authMw, authH, accountH, categoryH, transactionH, reportH, importH, importFullH, exchangeRateH, currencyH, exportH, userH)
+	router := server.NewRouter(authMw, adminMw, auditMw, authH, accountH, categoryH, transactionH, reportH, importH, importFullH, exchangeRateH, currencyH, exportH, userH, reconciliationH, balanceAssertionH, creditCardH, loanH, securityH, investmentH, interestH, accountGroupH, householdH, debtH, adminH, auditH, trashH)
 
 	// Server
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
@@ -90,14 +147,26 @@ func main() {
 
 	switch cfg.ExchangeRateSyncMode {
 	case "background":
-		go runBackgroundSync(ctx, exchangeRateSyncSvc.Sync)
+		go runDaily(ctx, "exchange rate sync", exchangeRateSyncSvc.Sync)
 	case "endpoint":
 		if cfg.ExchangeRateSyncToken == "" {
-			slog.Warn("EXCHANGE_RATE_SYNC_TOKEN is not set, POST /exchange-rates/sync will reject all requests")
+			slog.Warn("EXCHANGE_RATE_SYNC_TOKEN is not set, POST /exchange-rates/sync and /exchange-rates/backfill will reject all requests")
 		}
 	default:
 		log.Fatalf("invalid EXCHANGE_RATE_SYNC_MODE %q, must be \"background\" or \"endpoint\"", cfg.ExchangeRateSyncMode)
 	}
+	if cfg.InterestAccrual {
+		go runDaily(ctx, "interest accrual", interestSvc.AccrueAll)
+	}
+	if cfg.AuditLogRetentionDays > 0 {
+		go runDaily(ctx, "audit log pruning", auditSvc.Prune)
+	}
+	if cfg.LoginAttemptRetentionDays > 0 {
+		go runDaily(ctx, "login attempt pruning", authSvc.PruneLoginAttempts)
+	}
+	if cfg.TrashRetentionDays > 0 {
+		go runDaily(ctx, "trash purge", trashSvc.Purge)
+	}
 
 	srv := server.New(":"+cfg.Port, router)
 	if err := srv.Start(ctx); err != nil {
@@ -105,12 +174,13 @@ func main() {
 	}
 }
 
-func runBackgroundSync(ctx context.Context, fn func(context.Context) error) {
+// runDaily runs fn on startup and then every 24 hours until ctx is done.
+func runDaily(ctx context.Context, name string, fn func(context.Context) error) {
 	run := func() {
 		tickCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
 		defer cancel()
 		if err := fn(tickCtx); err != nil {
-			slog.Error("background exchange rate sync failed", "error", err)
+			slog.Error("background "+name+" failed", "error", err)
 		}
 	}
 
@@ -128,14 +198,15 @@ func runBackgroundSync(ctx context.Context, fn func(context.Context) error) {
 	}
 }
 
-func parseInviteCodes(raw string) []string {
-	var codes []string
+// splitList splits a comma-separated config value, dropping blank entries.
+func splitList(raw string) []string {
+	var items []string
 	for _, c := range strings.Split(raw, ",") {
 		if c = strings.TrimSpace(c); c != "" {
-			codes = append(codes, c)
+			items = append(items, c)
 		}
 	}
-	return codes
+	return items
 }
 
 func runMigrations(databaseURL string) error {
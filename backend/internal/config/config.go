@@ -3,6 +3,7 @@ package config
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/kelseyhightower/envconfig"
 )
@@ -16,12 +17,56 @@ type Config struct {
 	Port        string `envconfig:"PORT" default:"8080"`
 	DatabaseURL string `envconfig:"DATABASE_URL" required:"true"`
 	JWTSecret   string `envconfig:"JWT_SECRET" required:"true"`
-	InviteCodes string `envconfig:"INVITE_CODES" required:"true"`
+
+	// InviteCodes are added to the invite_codes table on startup
+	// (comma-separated), without limits. Admins create and revoke further
+	// codes through the API; a revoked code stays revoked.
+	InviteCodes string `envconfig:"INVITE_CODES"`
+
+	// AdminUsernames are promoted to admin on startup (comma-separated). Users
+	// registered later are promoted on the next restart; nobody is demoted.
+	AdminUsernames string `envconfig:"ADMIN_USERNAMES"`
 
 	// Exchange rate sync: "background" runs a daily goroutine, "endpoint" exposes only the HTTP trigger.
 	ExchangeRateSyncMode  string `envconfig:"EXCHANGE_RATE_SYNC_MODE" default:"endpoint"`
 	ExchangeRateSyncToken string `envconfig:"EXCHANGE_RATE_SYNC_TOKEN"`
 
+	// Rate providers: a comma-separated fallback chain (first that answers
+	// wins), plus per-pair overrides such as "RUB/AMD=cbr,*/RUB=cbr".
+	ExchangeRateProviders     string `envconfig:"EXCHANGE_RATE_PROVIDERS" default:"fawazahmed0"`
+	ExchangeRatePairProviders string `envconfig:"EXCHANGE_RATE_PAIR_PROVIDERS"`
+
+	// Pivot currencies, in order of preference, used to triangulate a rate when
+	// neither direction of a pair is stored.
+	ExchangeRatePivots string `envconfig:"EXCHANGE_RATE_PIVOTS" default:"USD,EUR"`
+
+	// Historical backfill throttling: pause between upstream requests and the
+	// maximum number of requests per run (0 = unlimited).
+	ExchangeRateBackfillDelay      time.Duration `envconfig:"EXCHANGE_RATE_BACKFILL_DELAY" default:"1s"`
+	ExchangeRateBackfillMaxFetches int           `envconfig:"EXCHANGE_RATE_BACKFILL_MAX_FETCHES" default:"500"`
+
+	// PriceProvider fetches security prices on POST /securities/prices/sync;
+	// empty disables the sync and leaves prices to manual entry.
+	PriceProvider string `envconfig:"PRICE_PROVIDER" default:"stooq"`
+
+	// InterestAccrual runs the daily job that credits interest on deposit
+	// accounts with interest terms.
+	InterestAccrual bool `envconfig:"INTEREST_ACCRUAL" default:"true"`
+
+	// AuditLogRetentionDays is how long audit log entries are kept; a daily
+	// job deletes older ones. 0 keeps them forever.
+	AuditLogRetentionDays int `envconfig:"AUDIT_LOG_RETENTION_DAYS" default:"365"`
+
+	// LoginAttemptRetentionDays is how long login attempts, and with them the
+	// login history, are kept; a daily job deletes older ones. 0 keeps them
+	// forever.
+	LoginAttemptRetentionDays int `envconfig:"LOGIN_ATTEMPT_RETENTION_DAYS" default:"90"`
+
+	// TrashRetentionDays is how long deleted accounts, categories and
+	// transactions stay restorable; a daily job purges older ones. 0 keeps
+	// them forever.
+	TrashRetentionDays int `envconfig:"TRASH_RETENTION_DAYS" default:"30"`
+
 	// CookieSecure controls the Secure flag on the refresh-token cookie. Defaults
 	// to true; set COOKIE_SECURE=false for local http:// development.
 	CookieSecure bool `envconfig:"COOKIE_SECURE" default:"true"`
@@ -50,5 +95,14 @@ func (c *Config) validate() error {
 	if c.JWTSecret == placeholderJWTValue {
 		return errors.New("JWT_SECRET is set to the placeholder value from .env.example; generate a real secret")
 	}
+	if c.AuditLogRetentionDays < 0 {
+		return errors.New("AUDIT_LOG_RETENTION_DAYS must not be negative")
+	}
+	if c.LoginAttemptRetentionDays < 0 {
+		return errors.New("LOGIN_ATTEMPT_RETENTION_DAYS must not be negative")
+	}
+	if c.TrashRetentionDays < 0 {
+		return errors.New("TRASH_RETENTION_DAYS must not be negative")
+	}
 	return nil
 }
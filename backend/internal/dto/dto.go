@@ -39,7 +39,7 @@ type RegisterRequest struct {
 }
 
 type LoginRequest struct {
-	Username string `json:"username" validate:"required"`
+	Username string `json:"username" validate:"required,max=50"`
 	Password string `json:"password" validate:"required"`
 }
 
@@ -53,6 +53,7 @@ type UserResponse struct {
 	Username     string    `json:"username"`
 	DisplayName  string    `json:"display_name"`
 	BaseCurrency string    `json:"base_currency"`
+	IsAdmin      bool      `json:"is_admin"`
 	CreatedAt    time.Time `json:"created_at"`
 }
 
@@ -67,42 +68,157 @@ type ChangePasswordRequest struct {
 	NewPassword     string `json:"new_password" validate:"required,min=10,max=128,notcommon"`
 }
 
+// LoginAttemptResponse is one entry of the login history. Outcome is
+// success, failure, locked or disabled.
+type LoginAttemptResponse struct {
+	IP        string    `json:"ip"`
+	UserAgent string    `json:"user_agent"`
+	Outcome   string    `json:"outcome"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AuditEntryResponse is one audit log entry. ActorID is the user's own ID,
+// or an admin's when the admin acted on the account. Metadata holds the
+// request's parameters, such as the ID of a deleted account.
+type AuditEntryResponse struct {
+	ID        uuid.UUID         `json:"id"`
+	Action    string            `json:"action"`
+	ActorID   uuid.UUID         `json:"actor_id"`
+	IP        string            `json:"ip"`
+	UserAgent string            `json:"user_agent"`
+	Metadata  map[string]string `json:"metadata"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// Admin
+type AdminUserResponse struct {
+	ID               uuid.UUID  `json:"id"`
+	Username         string     `json:"username"`
+	DisplayName      string     `json:"display_name"`
+	BaseCurrency     string     `json:"base_currency"`
+	IsAdmin          bool       `json:"is_admin"`
+	DisabledAt       *time.Time `json:"disabled_at"`
+	AccountCount     int64      `json:"account_count"`
+	TransactionCount int64      `json:"transaction_count"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+type ResetPasswordRequest struct {
+	NewPassword string `json:"new_password" validate:"required,min=10,max=128,notcommon"`
+}
+
+type InstanceStatsResponse struct {
+	Users               int64  `json:"users"`
+	DisabledUsers       int64  `json:"disabled_users"`
+	Admins              int64  `json:"admins"`
+	Accounts            int64  `json:"accounts"`
+	Transactions        int64  `json:"transactions"`
+	Currencies          int64  `json:"currencies"`
+	GlobalExchangeRates int64  `json:"global_exchange_rates"`
+	LatestRateDate      string `json:"latest_rate_date,omitempty"` // newest global rate, YYYY-MM-DD
+}
+
+// CreateInviteCodeRequest: Code is generated when empty; MaxUses and
+// ExpiresAt are unlimited when omitted.
+type CreateInviteCodeRequest struct {
+	Code      string     `json:"code" validate:"omitempty,min=6,max=100"`
+	MaxUses   *int32     `json:"max_uses" validate:"omitempty,min=1"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type InviteCodeResponse struct {
+	Code       string             `json:"code"`
+	Status     string             `json:"status"` // active, expired, used_up or revoked
+	CreatedBy  *uuid.UUID         `json:"created_by"`
+	MaxUses    *int32             `json:"max_uses"`
+	Uses       int32              `json:"uses"`
+	ExpiresAt  *time.Time         `json:"expires_at"`
+	RevokedAt  *time.Time         `json:"revoked_at"`
+	RedeemedBy []InviteRedemption `json:"redeemed_by"`
+	CreatedAt  time.Time          `json:"created_at"`
+}
+
+type InviteRedemption struct {
+	UserID     uuid.UUID `json:"user_id"`
+	Username   string    `json:"username"`
+	RedeemedAt time.Time `json:"redeemed_at"`
+}
+
 // Account
 type CreateAccountRequest struct {
-	Name           string `json:"name" validate:"required,max=100"`
-	Type           string `json:"type" validate:"required,oneof=deposit cash credit_card debit_card other"`
-	Currency       string `json:"currency" validate:"required,len=3"`
-	InitialBalance string `json:"initial_balance"` // decimal string
+	Name           string     `json:"name" validate:"required,max=100"`
+	Type           string     `json:"type" validate:"required,oneof=deposit cash credit_card debit_card loan investment other"`
+	Currency       string     `json:"currency" validate:"required,len=3"`
+	InitialBalance string     `json:"initial_balance"` // decimal string
+	GroupID        *uuid.UUID `json:"group_id"`
+	HouseholdID    *uuid.UUID `json:"household_id"` // share with a household
 }
 
 type UpdateAccountRequest struct {
-	Name           string `json:"name" validate:"required,max=100"`
-	Type           string `json:"type" validate:"required,oneof=deposit cash credit_card debit_card other"`
-	InitialBalance string `json:"initial_balance"`
+	Name           string     `json:"name" validate:"required,max=100"`
+	Type           string     `json:"type" validate:"required,oneof=deposit cash credit_card debit_card loan investment other"`
+	InitialBalance string     `json:"initial_balance"`
+	GroupID        *uuid.UUID `json:"group_id"`     // null = ungrouped
+	HouseholdID    *uuid.UUID `json:"household_id"` // null = not shared
 }
 
 type AccountResponse struct {
-	ID             uuid.UUID `json:"id"`
-	Name           string    `json:"name"`
-	Type           string    `json:"type"`
-	Currency       string    `json:"currency"`
-	InitialBalance string    `json:"initial_balance"`
-	Balance        string    `json:"balance"`
-	RecentTxCount  int       `json:"recent_tx_count"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
+	ID             uuid.UUID  `json:"id"`
+	Name           string     `json:"name"`
+	Type           string     `json:"type"`
+	Currency       string     `json:"currency"`
+	InitialBalance string     `json:"initial_balance"`
+	Balance        string     `json:"balance"`
+	RecentTxCount  int        `json:"recent_tx_count"`
+	GroupID        *uuid.UUID `json:"group_id"`
+	SortOrder      int        `json:"sort_order"`
+	HouseholdID    *uuid.UUID `json:"household_id"`
+	Role           string     `json:"role"`                   // owner, editor or viewer
+	ArchivedOn     *string    `json:"archived_on,omitempty"`  // YYYY-MM-DD
+	MarketValue    *string    `json:"market_value,omitempty"` // investment accounts; included in Balance
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	DeletedAt      *time.Time `json:"deleted_at,omitempty"` // only in the trash
+}
+
+// AccountListResponse is GET /accounts: the accounts in display order and
+// the balance of each group in base_currency.
+type AccountListResponse struct {
+	Data         []AccountResponse   `json:"data"`
+	BaseCurrency string              `json:"base_currency"`
+	Groups       []AccountGroupTotal `json:"groups"`
+}
+
+type ReorderAccountsRequest struct {
+	AccountIDs []uuid.UUID `json:"account_ids" validate:"required,min=1"`
+}
+
+type ArchiveAccountRequest struct {
+	Date string `json:"date" validate:"omitempty,datetime=2006-01-02"` // defaults to today
+}
+
+// BalanceCorrection is an account whose stored balance disagreed with its
+// transactions and was recomputed.
+type BalanceCorrection struct {
+	AccountID     uuid.UUID `json:"account_id"`
+	AccountName   string    `json:"account_name"`
+	Currency      string    `json:"currency"`
+	StoredBalance string    `json:"stored_balance"`
+	Balance       string    `json:"balance"`
 }
 
 // Category
 type CreateCategoryRequest struct {
-	Name     string     `json:"name" validate:"required,max=100"`
-	Type     string     `json:"type" validate:"required,oneof=income expense"`
-	ParentID *uuid.UUID `json:"parent_id"`
+	Name        string     `json:"name" validate:"required,max=100"`
+	Type        string     `json:"type" validate:"required,oneof=income expense"`
+	ParentID    *uuid.UUID `json:"parent_id"`
+	HouseholdID *uuid.UUID `json:"household_id"` // share with a household
 }
 
 type UpdateCategoryRequest struct {
-	Name     string     `json:"name" validate:"required,max=100"`
-	ParentID *uuid.UUID `json:"parent_id"`
+	Name        string     `json:"name" validate:"required,max=100"`
+	ParentID    *uuid.UUID `json:"parent_id"`
+	HouseholdID *uuid.UUID `json:"household_id"` // null = not shared
 }
 
 type CategoryResponse struct {
@@ -110,9 +226,11 @@ type CategoryResponse struct {
 	Name          string             `json:"name"`
 	Type          string             `json:"type"`
 	ParentID      *uuid.UUID         `json:"parent_id"`
+	HouseholdID   *uuid.UUID         `json:"household_id"`
 	Children      []CategoryResponse `json:"children,omitempty"`
 	RecentTxCount int                `json:"recent_tx_count"`
 	CreatedAt     time.Time          `json:"created_at"`
+	DeletedAt     *time.Time         `json:"deleted_at,omitempty"` // only in the trash
 }
 
 // Transaction
@@ -165,8 +283,458 @@ type TransactionResponse struct {
 	Date         string     `json:"date"`
 	TransferID   *uuid.UUID `json:"transfer_id,omitempty"`
 	ExchangeRate *string    `json:"exchange_rate,omitempty"`
+	Status       string     `json:"status"`
+	CreatedBy    uuid.UUID  `json:"created_by"` // the member who recorded it
 	CreatedAt    time.Time  `json:"created_at"`
 	UpdatedAt    time.Time  `json:"updated_at"`
+	DeletedAt    *time.Time `json:"deleted_at,omitempty"` // only in the trash
+}
+
+// SetTransactionStatusRequest marks a transaction as seen on a statement or
+// not. "reconciled" is only set by finishing a reconciliation.
+type SetTransactionStatusRequest struct {
+	Status string `json:"status" validate:"required,oneof=pending cleared"`
+}
+
+// TransactionVersionResponse is one change in a transaction's history. For
+// a transfer the history covers both legs, told apart by TransactionID.
+type TransactionVersionResponse struct {
+	ID            uuid.UUID     `json:"id"`
+	TransactionID uuid.UUID     `json:"transaction_id"`
+	Action        string        `json:"action"` // create, update, delete or restore
+	ActorID       uuid.UUID     `json:"actor_id"`
+	ActorUsername string        `json:"actor_username"` // empty once the user is gone
+	Changes       []FieldChange `json:"changes"`
+	CreatedAt     time.Time     `json:"created_at"`
+}
+
+// FieldChange is the old and new value of one field; Old is null on create
+// and either side is null for a cleared category or exchange rate.
+type FieldChange struct {
+	Field string  `json:"field"`
+	Old   *string `json:"old"`
+	New   *string `json:"new"`
+}
+
+// Trash
+// TrashResponse is GET /trash: what the user deleted, most recent first.
+// Transactions deleted along with their account are not listed; restoring
+// the account brings them back.
+type TrashResponse struct {
+	Accounts     []AccountResponse     `json:"accounts"`
+	Categories   []CategoryResponse    `json:"categories"`
+	Transactions []TransactionResponse `json:"transactions"`
+}
+
+// Reconciliation
+type StartReconciliationRequest struct {
+	AccountID        uuid.UUID `json:"account_id" validate:"required"`
+	StatementDate    string    `json:"statement_date" validate:"required"` // YYYY-MM-DD
+	StatementBalance string    `json:"statement_balance" validate:"required"`
+}
+
+type ReconciliationResponse struct {
+	ID               uuid.UUID  `json:"id"`
+	AccountID        uuid.UUID  `json:"account_id"`
+	Currency         string     `json:"currency"`
+	StatementDate    string     `json:"statement_date"`
+	StatementBalance string     `json:"statement_balance"`
+	Status           string     `json:"status"` // in_progress | finished
+	ClearedBalance   string     `json:"cleared_balance,omitempty"`
+	ClearedCount     int        `json:"cleared_count,omitempty"`
+	Difference       string     `json:"difference,omitempty"`
+	ReconciledCount  int        `json:"reconciled_count"`
+	FinishedAt       *time.Time `json:"finished_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+// Balance assertions
+type CreateBalanceAssertionRequest struct {
+	AccountID uuid.UUID `json:"account_id" validate:"required"`
+	Date      string    `json:"date" validate:"required"` // YYYY-MM-DD
+	Balance   string    `json:"balance" validate:"required"`
+	Note      string    `json:"note" validate:"max=255"`
+}
+
+type BalanceAssertionResponse struct {
+	ID              uuid.UUID `json:"id"`
+	AccountID       uuid.UUID `json:"account_id"`
+	Currency        string    `json:"currency"`
+	Date            string    `json:"date"`
+	Balance         string    `json:"balance"`
+	Note            string    `json:"note"`
+	ComputedBalance string    `json:"computed_balance"`
+	Difference      string    `json:"difference"` // balance - computed_balance
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// Credit cards
+type UpdateCreditCardRequest struct {
+	CreditLimit       string `json:"credit_limit"`
+	StatementDay      int    `json:"statement_day" validate:"required,min=1,max=31"`
+	PaymentDueDay     int    `json:"payment_due_day" validate:"required,min=1,max=31"`
+	MinPaymentPercent string `json:"min_payment_percent"` // defaults to 2
+	MinPaymentAmount  string `json:"min_payment_amount"`  // defaults to 0
+}
+
+// CreditCardStatement amounts are what is owed on the card, positive when
+// the card has a debt.
+type CreditCardStatement struct {
+	PeriodStart    string `json:"period_start"`
+	ClosingDate    string `json:"closing_date"`
+	DueDate        string `json:"due_date"`
+	Balance        string `json:"balance"`
+	MinimumPayment string `json:"minimum_payment"`
+}
+
+type CreditCardResponse struct {
+	AccountID          uuid.UUID           `json:"account_id"`
+	Currency           string              `json:"currency"`
+	CreditLimit        *string             `json:"credit_limit,omitempty"`
+	StatementDay       int                 `json:"statement_day"`
+	PaymentDueDay      int                 `json:"payment_due_day"`
+	MinPaymentPercent  string              `json:"min_payment_percent"`
+	MinPaymentAmount   string              `json:"min_payment_amount"`
+	Owed               string              `json:"owed"`
+	AvailableCredit    *string             `json:"available_credit,omitempty"`
+	CurrentStatement   CreditCardStatement `json:"current_statement"`
+	PreviousStatement  CreditCardStatement `json:"previous_statement"`
+	PaidSinceStatement string              `json:"paid_since_statement"`
+	Overdue            bool                `json:"overdue"`
+}
+
+// Loans
+type UpdateLoanRequest struct {
+	Principal          string     `json:"principal" validate:"required"`
+	AnnualRate         string     `json:"annual_rate" validate:"required"` // percent, e.g. "4.5"
+	TermMonths         int        `json:"term_months" validate:"required,min=1,max=1200"`
+	StartDate          string     `json:"start_date" validate:"required,datetime=2006-01-02"`
+	PaymentDay         int        `json:"payment_day" validate:"required,min=1,max=31"`
+	InterestCategoryID *uuid.UUID `json:"interest_category_id"`
+}
+
+// LoanResponse amounts are positive; the account balance itself is minus
+// RemainingPrincipal. The projection fields are empty once the loan is
+// paid off.
+type LoanResponse struct {
+	AccountID          uuid.UUID  `json:"account_id"`
+	Currency           string     `json:"currency"`
+	Principal          string     `json:"principal"`
+	AnnualRate         string     `json:"annual_rate"`
+	TermMonths         int        `json:"term_months"`
+	StartDate          string     `json:"start_date"`
+	PaymentDay         int        `json:"payment_day"`
+	InterestCategoryID *uuid.UUID `json:"interest_category_id"`
+	MonthlyPayment     string     `json:"monthly_payment"`
+	RemainingPrincipal string     `json:"remaining_principal"`
+	PrincipalPaid      string     `json:"principal_paid"`
+	InterestPaid       string     `json:"interest_paid"`
+	PaymentsSplit      int        `json:"payments_split"`
+	NextPaymentDate    string     `json:"next_payment_date,omitempty"`
+	RemainingPayments  int        `json:"remaining_payments"`
+	PayoffDate         string     `json:"payoff_date,omitempty"`
+	ProjectedInterest  string     `json:"projected_interest"`
+}
+
+type LoanScheduleItem struct {
+	Number    int    `json:"number"`
+	Date      string `json:"date"`
+	Payment   string `json:"payment"`
+	Principal string `json:"principal"`
+	Interest  string `json:"interest"`
+	Remaining string `json:"remaining"`
+}
+
+type LoanPaymentSplit struct {
+	PaymentID  uuid.UUID  `json:"payment_id"`
+	InterestID *uuid.UUID `json:"interest_id,omitempty"`
+	Date       string     `json:"date"`
+	Amount     string     `json:"amount"`
+	Principal  string     `json:"principal"`
+	Interest   string     `json:"interest"`
+}
+
+// Account groups
+type AccountGroupRequest struct {
+	Name string `json:"name" validate:"required,max=100"`
+}
+
+type ReorderAccountGroupsRequest struct {
+	GroupIDs []uuid.UUID `json:"group_ids" validate:"required,min=1"`
+}
+
+type AccountGroupResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	SortOrder int       `json:"sort_order"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// AccountGroupTotal is the balance of a group's accounts in base currency.
+// The entry with a null group_id covers the ungrouped accounts.
+type AccountGroupTotal struct {
+	GroupID               *uuid.UUID `json:"group_id"`
+	Name                  string     `json:"name"`
+	AccountCount          int        `json:"account_count"`
+	Balance               string     `json:"balance"`
+	UnconvertedCurrencies []string   `json:"unconverted_currencies"` // left out of balance (no rate)
+}
+
+// Households
+type HouseholdRequest struct {
+	Name string `json:"name" validate:"required,max=100"`
+}
+
+type AddHouseholdMemberRequest struct {
+	Username string `json:"username" validate:"required"`
+	Role     string `json:"role" validate:"required,oneof=owner editor viewer"`
+}
+
+type UpdateHouseholdMemberRequest struct {
+	Role string `json:"role" validate:"required,oneof=owner editor viewer"`
+}
+
+type HouseholdResponse struct {
+	ID        uuid.UUID                 `json:"id"`
+	Name      string                    `json:"name"`
+	Role      string                    `json:"role"`              // the caller's role
+	Members   []HouseholdMemberResponse `json:"members,omitempty"` // omitted in lists
+	CreatedAt time.Time                 `json:"created_at"`
+	UpdatedAt time.Time                 `json:"updated_at"`
+}
+
+type HouseholdMemberResponse struct {
+	UserID      uuid.UUID `json:"user_id"`
+	Username    string    `json:"username"`
+	DisplayName string    `json:"display_name"`
+	Role        string    `json:"role"`
+	JoinedAt    time.Time `json:"joined_at"`
+}
+
+// Shared expenses
+type ContactRequest struct {
+	Name string `json:"name" validate:"required,max=100"`
+}
+
+type ContactResponse struct {
+	ID        uuid.UUID     `json:"id"`
+	Name      string        `json:"name"`
+	Balances  []DebtBalance `json:"balances"` // non-zero only
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+// DebtBalance is what a contact owes in one currency: positive when the
+// contact owes the user, negative when the user owes the contact.
+type DebtBalance struct {
+	Currency string `json:"currency"`
+	Amount   string `json:"amount"`
+}
+
+// CreateSplitRequest splits an expense the user paid (TransactionID) or a
+// contact paid (PaidBy, with Amount, Currency and Date). Shares are
+// percentages for the percentage method, amounts for exact, and ignored
+// for equal.
+type CreateSplitRequest struct {
+	TransactionID *uuid.UUID         `json:"transaction_id"`
+	PaidBy        *uuid.UUID         `json:"paid_by"`
+	Amount        string             `json:"amount"`
+	Currency      string             `json:"currency"`
+	Date          string             `json:"date" validate:"omitempty,datetime=2006-01-02"`
+	Description   string             `json:"description" validate:"max=255"`
+	Method        string             `json:"method" validate:"required,oneof=equal percentage exact"`
+	Participants  []SplitParticipant `json:"participants" validate:"required,min=2,dive"`
+}
+
+type SplitParticipant struct {
+	ContactID *uuid.UUID `json:"contact_id"` // null = the user
+	Share     string     `json:"share"`
+}
+
+type SplitResponse struct {
+	ID            uuid.UUID            `json:"id"`
+	TransactionID *uuid.UUID           `json:"transaction_id"`
+	PaidBy        *uuid.UUID           `json:"paid_by"` // null = the user paid
+	Method        string               `json:"method"`
+	Description   string               `json:"description"`
+	Date          string               `json:"date"`
+	Currency      string               `json:"currency"`
+	Amount        string               `json:"amount"`
+	Shares        []SplitShareResponse `json:"shares"`
+	CreatedAt     time.Time            `json:"created_at"`
+}
+
+type SplitShareResponse struct {
+	ContactID   *uuid.UUID `json:"contact_id"` // null = the user
+	ContactName string     `json:"contact_name"`
+	Amount      string     `json:"amount"`
+}
+
+// SettleRequest records money received from or paid to a contact on one
+// of the user's accounts, in that account's currency. Amount defaults to
+// the whole balance in that currency.
+type SettleRequest struct {
+	AccountID   uuid.UUID `json:"account_id" validate:"required"`
+	Amount      string    `json:"amount"`
+	Date        string    `json:"date" validate:"required,datetime=2006-01-02"`
+	Description string    `json:"description" validate:"max=255"`
+}
+
+type SettlementResponse struct {
+	ID          uuid.UUID           `json:"id"`
+	ContactID   uuid.UUID           `json:"contact_id"`
+	Direction   string              `json:"direction"` // received | paid
+	Transaction TransactionResponse `json:"transaction"`
+}
+
+type DebtReportResponse struct {
+	Data   []DebtReportEntry `json:"data"`
+	Totals []DebtTotal       `json:"totals"`
+}
+
+type DebtReportEntry struct {
+	ContactID uuid.UUID `json:"contact_id"`
+	Name      string    `json:"name"`
+	Currency  string    `json:"currency"`
+	Balance   string    `json:"balance"`
+}
+
+// DebtTotal adds up the balances in one currency. OwedToYou and YouOwe are
+// both positive; Net is their difference.
+type DebtTotal struct {
+	Currency  string `json:"currency"`
+	OwedToYou string `json:"owed_to_you"`
+	YouOwe    string `json:"you_owe"`
+	Net       string `json:"net"`
+}
+
+// Interest
+type UpdateInterestRequest struct {
+	AnnualRate      string     `json:"annual_rate" validate:"required"` // percent, e.g. "3.25"
+	Compounding     string     `json:"compounding" validate:"required,oneof=monthly quarterly"`
+	StartDate       string     `json:"start_date" validate:"required,datetime=2006-01-02"`
+	PayoutAccountID *uuid.UUID `json:"payout_account_id"` // null = the account itself
+	CategoryID      *uuid.UUID `json:"category_id"`
+}
+
+type InterestResponse struct {
+	AccountID       uuid.UUID  `json:"account_id"`
+	Currency        string     `json:"currency"`
+	AnnualRate      string     `json:"annual_rate"`
+	Compounding     string     `json:"compounding"`
+	StartDate       string     `json:"start_date"`
+	PayoutAccountID *uuid.UUID `json:"payout_account_id"`
+	CategoryID      *uuid.UUID `json:"category_id"`
+	AccruedThrough  *string    `json:"accrued_through"`  // end of the last credited period
+	NextCreditDate  string     `json:"next_credit_date"` // end of the open period
+	AccruedToDate   string     `json:"accrued_to_date"`  // earned in the open period up to yesterday, not yet credited
+}
+
+type InterestAccrualResponse struct {
+	PeriodStart   string     `json:"period_start"`
+	PeriodEnd     string     `json:"period_end"`
+	Amount        string     `json:"amount"`
+	TransactionID *uuid.UUID `json:"transaction_id"` // null if nothing was earned or the transaction was deleted
+}
+
+// Investments
+type CreateSecurityRequest struct {
+	Symbol   string `json:"symbol" validate:"required,max=32"`
+	Name     string `json:"name" validate:"required,max=100"`
+	Currency string `json:"currency" validate:"required,len=3"`
+}
+
+type UpdateSecurityRequest struct {
+	Symbol string `json:"symbol" validate:"required,max=32"`
+	Name   string `json:"name" validate:"required,max=100"`
+}
+
+type SecurityResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Symbol    string    `json:"symbol"`
+	Name      string    `json:"name"`
+	Currency  string    `json:"currency"`
+	Price     *string   `json:"price"`      // latest known price, null if none
+	PriceDate *string   `json:"price_date"` // YYYY-MM-DD
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type SetSecurityPriceRequest struct {
+	Date  string `json:"date" validate:"required,datetime=2006-01-02"`
+	Price string `json:"price" validate:"required"`
+}
+
+type SecurityPriceResponse struct {
+	Date   string `json:"date"`
+	Price  string `json:"price"`
+	Source string `json:"source"` // "manual" or the price provider's name
+}
+
+type PriceSyncResponse struct {
+	Updated int      `json:"updated"`
+	Missing []string `json:"missing"` // symbols the provider had no price for
+}
+
+// CreateTradeRequest: buys and sells take quantity, price and an optional
+// fee; dividends take amount and an optional income category.
+type CreateTradeRequest struct {
+	AccountID  uuid.UUID  `json:"account_id" validate:"required"`
+	SecurityID uuid.UUID  `json:"security_id" validate:"required"`
+	Type       string     `json:"type" validate:"required,oneof=buy sell dividend"`
+	Date       string     `json:"date" validate:"required,datetime=2006-01-02"`
+	Quantity   string     `json:"quantity"`
+	Price      string     `json:"price"`
+	Fee        string     `json:"fee"`
+	Amount     string     `json:"amount"`
+	CategoryID *uuid.UUID `json:"category_id"`
+}
+
+type TradeResponse struct {
+	ID            uuid.UUID `json:"id"`
+	AccountID     uuid.UUID `json:"account_id"`
+	SecurityID    uuid.UUID `json:"security_id"`
+	Symbol        string    `json:"symbol"`
+	Type          string    `json:"type"`
+	Date          string    `json:"date"`
+	Quantity      string    `json:"quantity"`
+	Price         string    `json:"price"`
+	Fee           string    `json:"fee"`
+	Amount        string    `json:"amount"` // cash paid (buy) or received (sell, dividend)
+	TransactionID uuid.UUID `json:"transaction_id"`
+}
+
+// LotResponse is what is left of one buy after FIFO sells.
+type LotResponse struct {
+	TradeID  uuid.UUID `json:"trade_id"`
+	Date     string    `json:"date"`
+	Quantity string    `json:"quantity"`
+	Cost     string    `json:"cost"` // cost basis of the remaining quantity, fees included
+}
+
+type HoldingResponse struct {
+	SecurityID     uuid.UUID     `json:"security_id"`
+	Symbol         string        `json:"symbol"`
+	Name           string        `json:"name"`
+	Quantity       string        `json:"quantity"`
+	CostBasis      string        `json:"cost_basis"`
+	AverageCost    string        `json:"average_cost"`
+	Price          *string       `json:"price"`
+	PriceDate      *string       `json:"price_date"`
+	MarketValue    string        `json:"market_value"`
+	UnrealizedGain string        `json:"unrealized_gain"`
+	RealizedGain   string        `json:"realized_gain"`
+	Dividends      string        `json:"dividends"`
+	Lots           []LotResponse `json:"lots"`
+}
+
+type HoldingsResponse struct {
+	AccountID   uuid.UUID         `json:"account_id"`
+	Currency    string            `json:"currency"`
+	Cash        string            `json:"cash"`
+	MarketValue string            `json:"market_value"`
+	Balance     string            `json:"balance"` // cash + market_value
+	Holdings    []HoldingResponse `json:"holdings"`
 }
 
 // Import
@@ -186,6 +754,18 @@ type CSVConfirmRequest struct {
 	Rows        []CSVPreviewRow   `json:"rows" validate:"required"`
 }
 
+// CSVConfirmResponse reports the rows that were not imported by their
+// 1-based position in the request's rows.
+type CSVConfirmResponse struct {
+	Imported   int            `json:"imported"`
+	FailedRows []CSVFailedRow `json:"failed_rows"`
+}
+
+type CSVFailedRow struct {
+	RowNumber int    `json:"row_number"`
+	Error     string `json:"error"`
+}
+
 type CSVColumnMapping struct {
 	Date        string `json:"date" validate:"required"`
 	Amount      string `json:"amount" validate:"required"`
@@ -214,10 +794,14 @@ type BalanceHistoryItem struct {
 }
 
 type SummaryResponse struct {
-	TotalIncome  string            `json:"total_income"`
-	TotalExpense string            `json:"total_expense"`
-	NetIncome    string            `json:"net_income"`
-	Accounts     []AccountResponse `json:"accounts"`
+	TotalIncome           string              `json:"total_income"`
+	TotalExpense          string              `json:"total_expense"`
+	NetIncome             string              `json:"net_income"`
+	BaseCurrency          string              `json:"base_currency"`
+	NetWorth              string              `json:"net_worth"`              // sum of current account balances in base_currency at today's rates
+	UnconvertedCurrencies []string            `json:"unconverted_currencies"` // account currencies left out of net_worth (no rate)
+	Groups                []AccountGroupTotal `json:"groups"`                 // net_worth by account group
+	Accounts              []AccountResponse   `json:"accounts"`
 }
 
 type CashFlowCategoryItem struct {
@@ -248,11 +832,54 @@ type CashFlowResponse struct {
 	MonthlyChanges  []CashFlowAccountChange  `json:"monthly_changes"`
 }
 
+type FXGainLossAccount struct {
+	AccountID      uuid.UUID `json:"account_id"`
+	AccountName    string    `json:"account_name"`
+	Currency       string    `json:"currency"`
+	OpeningBalance string    `json:"opening_balance"` // in account currency
+	ClosingBalance string    `json:"closing_balance"`
+	OpeningRate    string    `json:"opening_rate"` // account currency -> base_currency
+	ClosingRate    string    `json:"closing_rate"`
+	OpeningValue   string    `json:"opening_value"` // in base_currency
+	ClosingValue   string    `json:"closing_value"`
+	NetFlows       string    `json:"net_flows"`  // inflows minus outflows, valued on their dates
+	Realized       string    `json:"realized"`   // gain/loss locked in by outflows
+	Unrealized     string    `json:"unrealized"` // revaluation of the closing balance
+	GainLoss       string    `json:"gain_loss"`  // realized + unrealized
+}
+
+type FXConversion struct {
+	TransactionID uuid.UUID `json:"transaction_id"`
+	TransferID    uuid.UUID `json:"transfer_id"`
+	AccountID     uuid.UUID `json:"account_id"`
+	Date          string    `json:"date"`
+	Type          string    `json:"type"` // "income" = bought, "expense" = sold
+	Amount        string    `json:"amount"`
+	Currency      string    `json:"currency"`
+	BaseAmount    string    `json:"base_amount"`
+	Rate          string    `json:"rate"`      // base_currency per unit, from the transfer
+	CostRate      string    `json:"cost_rate"` // average cost per unit before the transfer
+	Realized      string    `json:"realized"`
+}
+
+type FXGainLossResponse struct {
+	DateFrom              string              `json:"date_from"`
+	DateTo                string              `json:"date_to"`
+	BaseCurrency          string              `json:"base_currency"`
+	Accounts              []FXGainLossAccount `json:"accounts"`
+	Conversions           []FXConversion      `json:"conversions"`
+	TotalRealized         string              `json:"total_realized"`
+	TotalUnrealized       string              `json:"total_unrealized"`
+	TotalGainLoss         string              `json:"total_gain_loss"`
+	UnconvertedCurrencies []string            `json:"unconverted_currencies"` // account currencies left out (no rate)
+}
+
 // Currency
 type CreateCurrencyRequest struct {
-	Code   string `json:"code" validate:"required,len=3"`
-	Name   string `json:"name" validate:"required,max=50"`
-	Symbol string `json:"symbol" validate:"required,max=5"`
+	Code     string `json:"code" validate:"required,len=3"`
+	Name     string `json:"name" validate:"required,max=50"`
+	Symbol   string `json:"symbol" validate:"required,max=5"`
+	Decimals *int16 `json:"decimals" validate:"omitempty,min=0,max=8"` // default 2
 }
 
 type UpdateCurrencyRequest struct {
@@ -260,9 +887,10 @@ type UpdateCurrencyRequest struct {
 }
 
 type CurrencyResponse struct {
-	Code   string `json:"code"`
-	Name   string `json:"name"`
-	Symbol string `json:"symbol"`
+	Code     string `json:"code"`
+	Name     string `json:"name"`
+	Symbol   string `json:"symbol"`
+	Decimals int16  `json:"decimals"`
 }
 
 // Exchange Rate
@@ -271,6 +899,7 @@ type CreateExchangeRateRequest struct {
 	ToCurrency   string `json:"to_currency" validate:"required,len=3"`
 	Rate         string `json:"rate" validate:"required"`
 	Date         string `json:"date" validate:"required"`
+	Scope        string `json:"scope" validate:"omitempty,oneof=personal global"` // default: personal
 }
 
 type ExchangeRateResponse struct {
@@ -279,6 +908,40 @@ type ExchangeRateResponse struct {
 	ToCurrency   string    `json:"to_currency"`
 	Rate         string    `json:"rate"`
 	Date         string    `json:"date"`
+	Source       string    `json:"source"` // provider name, or "manual"
+	Scope        string    `json:"scope"`  // "global" | "personal"
+}
+
+// ConvertRequest is bound from the query string of GET /exchange-rates/convert.
+type ConvertRequest struct {
+	Amount string `json:"amount" validate:"required,numeric"`
+	From   string `json:"from" validate:"required,len=3"`
+	To     string `json:"to" validate:"required,len=3"`
+	Date   string `json:"date" validate:"omitempty,datetime=2006-01-02"` // default: today
+}
+
+type ConvertResponse struct {
+	Amount          string           `json:"amount"`
+	From            string           `json:"from"`
+	To              string           `json:"to"`
+	Date            string           `json:"date"`
+	ConvertedAmount string           `json:"converted_amount"`
+	Rate            string           `json:"rate"`
+	RateDate        string           `json:"rate_date,omitempty"` // oldest rate used; omitted when from == to
+	Method          string           `json:"method"`              // "identity" | "direct" | "inverse" | "triangulated"
+	Source          string           `json:"source"`              // e.g. "ecb", or "ecb+cbr" when triangulated
+	Via             string           `json:"via,omitempty"`       // pivot currency when triangulated
+	Legs            []ConvertRateLeg `json:"legs"`
+}
+
+type ConvertRateLeg struct {
+	FromCurrency string `json:"from_currency"`
+	ToCurrency   string `json:"to_currency"`
+	Rate         string `json:"rate"`
+	Date         string `json:"date"`
+	Source       string `json:"source"`
+	Scope        string `json:"scope"`    // "global" | "personal"
+	Inverted     bool   `json:"inverted"` // stored as to -> from and inverted
 }
 
 // Full Import
@@ -293,9 +956,10 @@ type FullImportRow struct {
 }
 
 type NewCurrency struct {
-	Code   string `json:"code" validate:"required,len=3"`
-	Name   string `json:"name" validate:"required"`
-	Symbol string `json:"symbol" validate:"required"`
+	Code     string `json:"code" validate:"required,len=3"`
+	Name     string `json:"name" validate:"required"`
+	Symbol   string `json:"symbol" validate:"required"`
+	Decimals *int16 `json:"decimals" validate:"omitempty,min=0,max=8"` // default 2
 }
 
 type FullImportRequest struct {
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"errors"
+	"io"
 	"log/slog"
 	"net/http"
 
@@ -24,13 +25,14 @@ func NewAccount(svc *service.Account) *Account {
 
 func (h *Account) List(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.UserID(r.Context())
-	accounts, err := h.svc.List(r.Context(), userID)
+	includeArchived := r.URL.Query().Get("include_archived") == "true"
+	accounts, err := h.svc.List(r.Context(), userID, includeArchived)
 	if err != nil {
 		slog.Error("failed to list accounts", "error", err, "user_id", userID)
 		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to list accounts")
 		return
 	}
-	respond.JSON(w, http.StatusOK, map[string]any{"data": accounts})
+	respond.JSON(w, http.StatusOK, accounts)
 }
 
 func (h *Account) Create(w http.ResponseWriter, r *http.Request) {
@@ -51,6 +53,11 @@ func (h *Account) Create(w http.ResponseWriter, r *http.Request) {
 			respond.Error(w, http.StatusConflict, "ACCOUNT_EXISTS", err.Error())
 			return
 		}
+		if errors.Is(err, service.ErrAmountPrecision) || errors.Is(err, service.ErrInvalidAccountGroup) ||
+			errors.Is(err, service.ErrInvalidHousehold) {
+			respond.Error(w, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+			return
+		}
 		slog.Error("failed to create account", "error", err, "user_id", userID)
 		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to create account")
 		return
@@ -107,6 +114,11 @@ func (h *Account) Update(w http.ResponseWriter, r *http.Request) {
 			respond.Error(w, http.StatusConflict, "ACCOUNT_EXISTS", err.Error())
 			return
 		}
+		if errors.Is(err, service.ErrAmountPrecision) || errors.Is(err, service.ErrInvalidAccountGroup) ||
+			errors.Is(err, service.ErrInvalidHousehold) {
+			respond.Error(w, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+			return
+		}
 		slog.Error("failed to update account", "error", err, "user_id", userID, "account_id", id)
 		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to update account")
 		return
@@ -129,3 +141,97 @@ func (h *Account) Delete(w http.ResponseWriter, r *http.Request) {
 	}
 	respond.NoContent(w)
 }
+
+// Archive accepts an empty body, which archives the account as of today.
+func (h *Account) Archive(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_ID", "invalid account ID")
+		return
+	}
+
+	var req dto.ArchiveAccountRequest
+	if err := decodeJSON(w, r, &req); err != nil && !errors.Is(err, io.EOF) {
+		respond.Error(w, http.StatusBadRequest, "INVALID_BODY", "invalid request body")
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		respond.Error(w, http.StatusBadRequest, "VALIDATION_ERROR", validationMessage(err))
+		return
+	}
+
+	acct, err := h.svc.Archive(r.Context(), userID, id, req.Date)
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			respond.Error(w, http.StatusNotFound, "NOT_FOUND", "account not found")
+			return
+		}
+		slog.Error("failed to archive account", "error", err, "user_id", userID, "account_id", id)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to archive account")
+		return
+	}
+	respond.JSON(w, http.StatusOK, acct)
+}
+
+func (h *Account) Unarchive(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_ID", "invalid account ID")
+		return
+	}
+
+	acct, err := h.svc.Unarchive(r.Context(), userID, id)
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			respond.Error(w, http.StatusNotFound, "NOT_FOUND", "account not found")
+			return
+		}
+		slog.Error("failed to unarchive account", "error", err, "user_id", userID, "account_id", id)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to unarchive account")
+		return
+	}
+	respond.JSON(w, http.StatusOK, acct)
+}
+
+func (h *Account) ReconcileBalances(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	corrections, err := h.svc.ReconcileBalances(r.Context(), userID)
+	if err != nil {
+		slog.Error("failed to reconcile account balances", "error", err, "user_id", userID)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to reconcile account balances")
+		return
+	}
+	if len(corrections) > 0 {
+		slog.Warn("account balances drifted", "user_id", userID, "accounts", len(corrections))
+	}
+	respond.JSON(w, http.StatusOK, map[string]any{"data": corrections})
+}
+
+// Reorder sets the display order of the caller's accounts from the order of
+// account_ids.
+func (h *Account) Reorder(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	var req dto.ReorderAccountsRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_BODY", "invalid request body")
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		respond.Error(w, http.StatusBadRequest, "VALIDATION_ERROR", validationMessage(err))
+		return
+	}
+
+	accounts, err := h.svc.Reorder(r.Context(), userID, req.AccountIDs)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidOrder) {
+			respond.Error(w, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+			return
+		}
+		slog.Error("failed to reorder accounts", "error", err, "user_id", userID)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to reorder accounts")
+		return
+	}
+	respond.JSON(w, http.StatusOK, accounts)
+}
@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/dto"
+	"github.com/sanches/finance-tracker-cc/backend/internal/handler/respond"
+	"github.com/sanches/finance-tracker-cc/backend/internal/middleware"
+	"github.com/sanches/finance-tracker-cc/backend/internal/service"
+)
+
+type AccountGroup struct {
+	svc *service.AccountGroup
+}
+
+func NewAccountGroup(svc *service.AccountGroup) *AccountGroup {
+	return &AccountGroup{svc: svc}
+}
+
+func (h *AccountGroup) List(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	groups, err := h.svc.List(r.Context(), userID)
+	if err != nil {
+		slog.Error("failed to list account groups", "error", err, "user_id", userID)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to list account groups")
+		return
+	}
+	respond.JSON(w, http.StatusOK, map[string]any{"data": groups})
+}
+
+func (h *AccountGroup) Create(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	var req dto.AccountGroupRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_BODY", "invalid request body")
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		respond.Error(w, http.StatusBadRequest, "VALIDATION_ERROR", validationMessage(err))
+		return
+	}
+
+	group, err := h.svc.Create(r.Context(), userID, req)
+	if err != nil {
+		if respondAccountGroupError(w, err) {
+			return
+		}
+		slog.Error("failed to create account group", "error", err, "user_id", userID)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to create account group")
+		return
+	}
+	respond.JSON(w, http.StatusCreated, group)
+}
+
+func (h *AccountGroup) Update(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_ID", "invalid account group ID")
+		return
+	}
+
+	var req dto.AccountGroupRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_BODY", "invalid request body")
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		respond.Error(w, http.StatusBadRequest, "VALIDATION_ERROR", validationMessage(err))
+		return
+	}
+
+	group, err := h.svc.Update(r.Context(), userID, id, req)
+	if err != nil {
+		if respondAccountGroupError(w, err) {
+			return
+		}
+		slog.Error("failed to update account group", "error", err, "user_id", userID, "group_id", id)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to update account group")
+		return
+	}
+	respond.JSON(w, http.StatusOK, group)
+}
+
+func (h *AccountGroup) Delete(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_ID", "invalid account group ID")
+		return
+	}
+
+	if err := h.svc.Delete(r.Context(), userID, id); err != nil {
+		if respondAccountGroupError(w, err) {
+			return
+		}
+		slog.Error("failed to delete account group", "error", err, "user_id", userID, "group_id", id)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to delete account group")
+		return
+	}
+	respond.NoContent(w)
+}
+
+// Reorder sets the display order of the caller's groups from the order of
+// group_ids.
+func (h *AccountGroup) Reorder(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	var req dto.ReorderAccountGroupsRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_BODY", "invalid request body")
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		respond.Error(w, http.StatusBadRequest, "VALIDATION_ERROR", validationMessage(err))
+		return
+	}
+
+	groups, err := h.svc.Reorder(r.Context(), userID, req.GroupIDs)
+	if err != nil {
+		if respondAccountGroupError(w, err) {
+			return
+		}
+		slog.Error("failed to reorder account groups", "error", err, "user_id", userID)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to reorder account groups")
+		return
+	}
+	respond.JSON(w, http.StatusOK, map[string]any{"data": groups})
+}
+
+// respondAccountGroupError maps an unknown or duplicate group and a reorder
+// that does not list exactly the user's groups.
+func respondAccountGroupError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, service.ErrAccountGroupNotFound):
+		respond.Error(w, http.StatusNotFound, "NOT_FOUND", err.Error())
+	case errors.Is(err, service.ErrAccountGroupExists):
+		respond.Error(w, http.StatusConflict, "ACCOUNT_GROUP_EXISTS", err.Error())
+	case errors.Is(err, service.ErrInvalidOrder):
+		respond.Error(w, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+	default:
+		return false
+	}
+	return true
+}
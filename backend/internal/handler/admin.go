@@ -0,0 +1,184 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/dto"
+	"github.com/sanches/finance-tracker-cc/backend/internal/handler/respond"
+	"github.com/sanches/finance-tracker-cc/backend/internal/middleware"
+	"github.com/sanches/finance-tracker-cc/backend/internal/service"
+)
+
+// Admin serves the /admin routes; middleware.Admin restricts them to
+// administrators.
+type Admin struct {
+	svc *service.Admin
+}
+
+func NewAdmin(svc *service.Admin) *Admin {
+	return &Admin{svc: svc}
+}
+
+func (h *Admin) ListUsers(w http.ResponseWriter, r *http.Request) {
+	users, err := h.svc.ListUsers(r.Context())
+	if err != nil {
+		slog.Error("failed to list users", "error", err)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to list users")
+		return
+	}
+	respond.JSON(w, http.StatusOK, map[string]any{"data": users})
+}
+
+func (h *Admin) DisableUser(w http.ResponseWriter, r *http.Request) {
+	h.setDisabled(w, r, true)
+}
+
+func (h *Admin) EnableUser(w http.ResponseWriter, r *http.Request) {
+	h.setDisabled(w, r, false)
+}
+
+func (h *Admin) setDisabled(w http.ResponseWriter, r *http.Request, disabled bool) {
+	adminID := middleware.UserID(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_ID", "invalid user ID")
+		return
+	}
+
+	if err := h.svc.SetDisabled(r.Context(), adminID, id, disabled); err != nil {
+		if respondAdminError(w, err) {
+			return
+		}
+		slog.Error("failed to update user", "error", err, "user_id", id, "disabled", disabled)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to update user")
+		return
+	}
+	respond.NoContent(w)
+}
+
+func (h *Admin) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_ID", "invalid user ID")
+		return
+	}
+
+	var req dto.ResetPasswordRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_BODY", "invalid request body")
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		respond.Error(w, http.StatusBadRequest, "VALIDATION_ERROR", validationMessage(err))
+		return
+	}
+
+	if err := h.svc.ResetPassword(r.Context(), id, req); err != nil {
+		if respondAdminError(w, err) {
+			return
+		}
+		slog.Error("failed to reset password", "error", err, "user_id", id)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to reset password")
+		return
+	}
+	respond.NoContent(w)
+}
+
+func (h *Admin) Stats(w http.ResponseWriter, r *http.Request) {
+	stats, err := h.svc.Stats(r.Context())
+	if err != nil {
+		slog.Error("failed to get instance stats", "error", err)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to get instance stats")
+		return
+	}
+	respond.JSON(w, http.StatusOK, stats)
+}
+
+func (h *Admin) DeleteExchangeRate(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_ID", "invalid exchange rate ID")
+		return
+	}
+
+	if err := h.svc.DeleteExchangeRate(r.Context(), id); err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			respond.Error(w, http.StatusNotFound, "NOT_FOUND", "global exchange rate not found")
+			return
+		}
+		slog.Error("failed to delete exchange rate", "error", err, "rate_id", id)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to delete exchange rate")
+		return
+	}
+	respond.NoContent(w)
+}
+
+func (h *Admin) ListInviteCodes(w http.ResponseWriter, r *http.Request) {
+	codes, err := h.svc.ListInviteCodes(r.Context())
+	if err != nil {
+		slog.Error("failed to list invite codes", "error", err)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to list invite codes")
+		return
+	}
+	respond.JSON(w, http.StatusOK, map[string]any{"data": codes})
+}
+
+func (h *Admin) CreateInviteCode(w http.ResponseWriter, r *http.Request) {
+	adminID := middleware.UserID(r.Context())
+	var req dto.CreateInviteCodeRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_BODY", "invalid request body")
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		respond.Error(w, http.StatusBadRequest, "VALIDATION_ERROR", validationMessage(err))
+		return
+	}
+
+	code, err := h.svc.CreateInviteCode(r.Context(), adminID, req)
+	if err != nil {
+		if respondAdminError(w, err) {
+			return
+		}
+		slog.Error("failed to create invite code", "error", err, "user_id", adminID)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to create invite code")
+		return
+	}
+	respond.JSON(w, http.StatusCreated, code)
+}
+
+func (h *Admin) RevokeInviteCode(w http.ResponseWriter, r *http.Request) {
+	code, err := h.svc.RevokeInviteCode(r.Context(), chi.URLParam(r, "code"))
+	if err != nil {
+		if respondAdminError(w, err) {
+			return
+		}
+		slog.Error("failed to revoke invite code", "error", err)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to revoke invite code")
+		return
+	}
+	respond.JSON(w, http.StatusOK, code)
+}
+
+// respondAdminError covers the user and invite code lookups, duplicate or
+// badly dated invite codes, and an admin disabling themselves.
+func respondAdminError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, service.ErrUserNotFound), errors.Is(err, service.ErrInviteCodeNotFound):
+		respond.Error(w, http.StatusNotFound, "NOT_FOUND", err.Error())
+	case errors.Is(err, service.ErrInviteCodeExists):
+		respond.Error(w, http.StatusConflict, "INVITE_CODE_EXISTS", err.Error())
+	case errors.Is(err, service.ErrInviteExpiry):
+		respond.Error(w, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+	case errors.Is(err, service.ErrCannotDisableSelf):
+		respond.Error(w, http.StatusConflict, "CANNOT_DISABLE_SELF", err.Error())
+	default:
+		return false
+	}
+	return true
+}
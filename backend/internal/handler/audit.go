@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/handler/respond"
+	"github.com/sanches/finance-tracker-cc/backend/internal/middleware"
+	"github.com/sanches/finance-tracker-cc/backend/internal/service"
+)
+
+type Audit struct {
+	svc *service.Audit
+}
+
+func NewAudit(svc *service.Audit) *Audit {
+	return &Audit{svc: svc}
+}
+
+func (h *Audit) List(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	q := r.URL.Query()
+
+	params := service.AuditListParams{Action: q.Get("action")}
+	if v := q.Get("page"); v != "" {
+		if p, err := strconv.Atoi(v); err == nil {
+			params.Page = p
+		}
+	}
+	if v := q.Get("per_page"); v != "" {
+		if p, err := strconv.Atoi(v); err == nil {
+			params.PerPage = p
+		}
+	}
+
+	result, err := h.svc.List(r.Context(), userID, params)
+	if err != nil {
+		slog.Error("failed to list audit log", "error", err, "user_id", userID)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to list audit log")
+		return
+	}
+	respond.JSON(w, http.StatusOK, result)
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"math"
 	"net/http"
 	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/go-playground/validator/v10"
@@ -14,6 +16,7 @@ import (
 
 	"github.com/sanches/finance-tracker-cc/backend/internal/dto"
 	"github.com/sanches/finance-tracker-cc/backend/internal/handler/respond"
+	"github.com/sanches/finance-tracker-cc/backend/internal/middleware"
 	"github.com/sanches/finance-tracker-cc/backend/internal/service"
 )
 
@@ -52,7 +55,7 @@ func refreshCookiePath(basePath string) string {
 
 type authService interface {
 	Register(ctx context.Context, req dto.RegisterRequest) (*service.AuthResult, error)
-	Login(ctx context.Context, req dto.LoginRequest) (*service.AuthResult, error)
+	Login(ctx context.Context, req dto.LoginRequest, client service.LoginClient) (*service.AuthResult, error)
 	Refresh(ctx context.Context, token string) (*service.AuthResult, error)
 }
 
@@ -107,13 +110,25 @@ func (h *Auth) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	res, err := h.svc.Login(r.Context(), req)
+	client := service.LoginClient{IP: middleware.ClientIP(r), UserAgent: r.UserAgent()}
+	res, err := h.svc.Login(r.Context(), req, client)
 	if err != nil {
 		h.clearRefreshCookie(w)
+		var locked *service.LoginLockedError
+		if errors.As(err, &locked) {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(locked.RetryAfter.Seconds()))))
+			respond.Error(w, http.StatusTooManyRequests, "LOGIN_LOCKED", "too many failed login attempts, please try again later")
+			return
+		}
 		if errors.Is(err, service.ErrInvalidCredentials) {
 			respond.Error(w, http.StatusUnauthorized, "INVALID_CREDENTIALS", err.Error())
 			return
 		}
+		if errors.Is(err, service.ErrAccountDisabled) {
+			respond.Error(w, http.StatusForbidden, "ACCOUNT_DISABLED", err.Error())
+			return
+		}
+		slog.Error("failed to login", "error", err)
 		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to login")
 		return
 	}
@@ -136,10 +151,16 @@ func (h *Auth) Refresh(w http.ResponseWriter, r *http.Request) {
 			respond.Error(w, http.StatusUnauthorized, "INVALID_TOKEN", err.Error())
 			return
 		}
+		if errors.Is(err, service.ErrAccountDisabled) {
+			h.clearRefreshCookie(w)
+			respond.Error(w, http.StatusForbidden, "ACCOUNT_DISABLED", err.Error())
+			return
+		}
 		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to refresh token")
 		return
 	}
 
+	middleware.SetAuditUser(r, res.User.ID)
 	h.setRefreshCookie(w, res.RefreshToken)
 	respond.JSON(w, http.StatusOK, authResponse(res))
 }
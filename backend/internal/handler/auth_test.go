@@ -17,7 +17,7 @@ import (
 
 type stubAuthService struct {
 	registerFn func(context.Context, dto.RegisterRequest) (*service.AuthResult, error)
-	loginFn    func(context.Context, dto.LoginRequest) (*service.AuthResult, error)
+	loginFn    func(context.Context, dto.LoginRequest, service.LoginClient) (*service.AuthResult, error)
 	refreshFn  func(context.Context, string) (*service.AuthResult, error)
 }
 
@@ -28,11 +28,11 @@ func (s *stubAuthService) Register(ctx context.Context, req dto.RegisterRequest)
 	return s.registerFn(ctx, req)
 }
 
-func (s *stubAuthService) Login(ctx context.Context, req dto.LoginRequest) (*service.AuthResult, error) {
+func (s *stubAuthService) Login(ctx context.Context, req dto.LoginRequest, client service.LoginClient) (*service.AuthResult, error) {
 	if s.loginFn == nil {
 		return nil, nil
 	}
-	return s.loginFn(ctx, req)
+	return s.loginFn(ctx, req, client)
 }
 
 func (s *stubAuthService) Refresh(ctx context.Context, token string) (*service.AuthResult, error) {
@@ -160,7 +160,7 @@ func TestRefreshCookiePath(t *testing.T) {
 
 func TestLogin_ClearsRefreshCookieOnFailure(t *testing.T) {
 	h := NewAuth(&stubAuthService{
-		loginFn: func(context.Context, dto.LoginRequest) (*service.AuthResult, error) {
+		loginFn: func(context.Context, dto.LoginRequest, service.LoginClient) (*service.AuthResult, error) {
 			return nil, service.ErrInvalidCredentials
 		},
 	}, false, "/")
@@ -178,6 +178,29 @@ func TestLogin_ClearsRefreshCookieOnFailure(t *testing.T) {
 	require.Equal(t, -1, cookie.MaxAge)
 }
 
+func TestLogin_LockedSetsRetryAfter(t *testing.T) {
+	var gotClient service.LoginClient
+	h := NewAuth(&stubAuthService{
+		loginFn: func(_ context.Context, _ dto.LoginRequest, client service.LoginClient) (*service.AuthResult, error) {
+			gotClient = client
+			return nil, &service.LoginLockedError{RetryAfter: 90*time.Second + time.Millisecond}
+		},
+	}, false, "/")
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", strings.NewReader(`{"username":"alice","password":"StrongPass123!"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "test-agent")
+	req.RemoteAddr = "203.0.113.7:51234"
+	rec := httptest.NewRecorder()
+
+	h.Login(rec, req)
+
+	require.Equal(t, http.StatusTooManyRequests, rec.Code)
+	require.Equal(t, "91", rec.Header().Get("Retry-After"))
+	require.Contains(t, rec.Body.String(), "LOGIN_LOCKED")
+	require.Equal(t, service.LoginClient{IP: "203.0.113.7", UserAgent: "test-agent"}, gotClient)
+}
+
 func TestRefresh_RotatesScopedCookieAndOmitsBodyRefreshToken(t *testing.T) {
 	result := testAuthResult()
 	h := NewAuth(&stubAuthService{
@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/dto"
+	"github.com/sanches/finance-tracker-cc/backend/internal/handler/respond"
+	"github.com/sanches/finance-tracker-cc/backend/internal/middleware"
+	"github.com/sanches/finance-tracker-cc/backend/internal/service"
+)
+
+type BalanceAssertion struct {
+	svc *service.BalanceAssertion
+}
+
+func NewBalanceAssertion(svc *service.BalanceAssertion) *BalanceAssertion {
+	return &BalanceAssertion{svc: svc}
+}
+
+func (h *BalanceAssertion) List(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, h.svc.List)
+}
+
+// Check lists only the assertions that do not hold.
+func (h *BalanceAssertion) Check(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, h.svc.Check)
+}
+
+func (h *BalanceAssertion) list(w http.ResponseWriter, r *http.Request, fetch func(ctx context.Context, userID uuid.UUID, accountID *uuid.UUID) ([]dto.BalanceAssertionResponse, error)) {
+	userID := middleware.UserID(r.Context())
+	var accountID *uuid.UUID
+	if v := r.URL.Query().Get("account_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			respond.Error(w, http.StatusBadRequest, "INVALID_ID", "invalid account_id")
+			return
+		}
+		accountID = &id
+	}
+
+	assertions, err := fetch(r.Context(), userID, accountID)
+	if err != nil {
+		slog.Error("failed to list balance assertions", "error", err, "user_id", userID)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to list balance assertions")
+		return
+	}
+	respond.JSON(w, http.StatusOK, map[string]any{"data": assertions})
+}
+
+func (h *BalanceAssertion) Create(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	var req dto.CreateBalanceAssertionRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_BODY", "invalid request body")
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		respond.Error(w, http.StatusBadRequest, "VALIDATION_ERROR", validationMessage(err))
+		return
+	}
+
+	a, err := h.svc.Create(r.Context(), userID, req)
+	if err != nil {
+		if respondBalanceAssertionError(w, err) {
+			return
+		}
+		slog.Error("failed to create balance assertion", "error", err, "user_id", userID)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to create balance assertion")
+		return
+	}
+	respond.JSON(w, http.StatusCreated, a)
+}
+
+func (h *BalanceAssertion) Delete(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_ID", "invalid balance assertion ID")
+		return
+	}
+
+	if err := h.svc.Delete(r.Context(), userID, id); err != nil {
+		if respondBalanceAssertionError(w, err) {
+			return
+		}
+		slog.Error("failed to delete balance assertion", "error", err, "user_id", userID, "balance_assertion_id", id)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to delete balance assertion")
+		return
+	}
+	respond.NoContent(w)
+}
+
+// Adjust books the difference of a failing assertion as a transaction.
+func (h *BalanceAssertion) Adjust(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_ID", "invalid balance assertion ID")
+		return
+	}
+
+	txn, err := h.svc.Adjust(r.Context(), userID, id)
+	if err != nil {
+		if respondBalanceAssertionError(w, err) || respondAmountError(w, err) {
+			return
+		}
+		slog.Error("failed to adjust balance assertion", "error", err, "user_id", userID, "balance_assertion_id", id)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to adjust balance assertion")
+		return
+	}
+	respond.JSON(w, http.StatusCreated, txn)
+}
+
+// respondBalanceAssertionError maps a missing assertion or account, an
+// archived account, a second assertion on the same date, and an adjustment
+// asked for when the assertion already holds.
+func respondBalanceAssertionError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, service.ErrNotFound):
+		respond.Error(w, http.StatusNotFound, "NOT_FOUND", "balance assertion not found")
+	case errors.Is(err, service.ErrAccountNotFound):
+		respond.Error(w, http.StatusNotFound, "NOT_FOUND", "account not found")
+	case errors.Is(err, service.ErrAccountArchived):
+		respond.Error(w, http.StatusConflict, "ACCOUNT_ARCHIVED", err.Error())
+	case errors.Is(err, service.ErrInvalidBalanceAssertion), errors.Is(err, service.ErrAmountPrecision):
+		respond.Error(w, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+	case errors.Is(err, service.ErrBalanceAssertionExists):
+		respond.Error(w, http.StatusConflict, "BALANCE_ASSERTION_EXISTS", err.Error())
+	case errors.Is(err, service.ErrBalanceAssertionHolds):
+		respond.Error(w, http.StatusConflict, "BALANCE_ASSERTION_HOLDS", err.Error())
+	default:
+		return false
+	}
+	return true
+}
@@ -49,6 +49,10 @@ func (h *Category) Create(w http.ResponseWriter, r *http.Request) {
 			respond.Error(w, http.StatusConflict, "CATEGORY_EXISTS", err.Error())
 			return
 		}
+		if errors.Is(err, service.ErrInvalidHousehold) {
+			respond.Error(w, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+			return
+		}
 		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to create category")
 		return
 	}
@@ -83,6 +87,10 @@ func (h *Category) Update(w http.ResponseWriter, r *http.Request) {
 			respond.Error(w, http.StatusConflict, "CATEGORY_EXISTS", err.Error())
 			return
 		}
+		if errors.Is(err, service.ErrInvalidHousehold) {
+			respond.Error(w, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+			return
+		}
 		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to update category")
 		return
 	}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/dto"
+	"github.com/sanches/finance-tracker-cc/backend/internal/handler/respond"
+	"github.com/sanches/finance-tracker-cc/backend/internal/middleware"
+	"github.com/sanches/finance-tracker-cc/backend/internal/service"
+)
+
+type CreditCard struct {
+	svc *service.CreditCard
+}
+
+func NewCreditCard(svc *service.CreditCard) *CreditCard {
+	return &CreditCard{svc: svc}
+}
+
+func (h *CreditCard) Get(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_ID", "invalid account ID")
+		return
+	}
+
+	card, err := h.svc.Get(r.Context(), userID, id)
+	if err != nil {
+		if respondCreditCardError(w, err) {
+			return
+		}
+		slog.Error("failed to get credit card", "error", err, "user_id", userID, "account_id", id)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to get credit card")
+		return
+	}
+	respond.JSON(w, http.StatusOK, card)
+}
+
+func (h *CreditCard) Update(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_ID", "invalid account ID")
+		return
+	}
+
+	var req dto.UpdateCreditCardRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_BODY", "invalid request body")
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		respond.Error(w, http.StatusBadRequest, "VALIDATION_ERROR", validationMessage(err))
+		return
+	}
+
+	card, err := h.svc.Update(r.Context(), userID, id, req)
+	if err != nil {
+		if respondCreditCardError(w, err) {
+			return
+		}
+		slog.Error("failed to update credit card", "error", err, "user_id", userID, "account_id", id)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to update credit card")
+		return
+	}
+	respond.JSON(w, http.StatusOK, card)
+}
+
+// respondCreditCardError sends 404 for a card without a statement set-up and
+// 400 when the account is not a credit card or the set-up is invalid.
+func respondCreditCardError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, service.ErrAccountNotFound):
+		respond.Error(w, http.StatusNotFound, "NOT_FOUND", "account not found")
+	case errors.Is(err, service.ErrCreditCardNotSetUp):
+		respond.Error(w, http.StatusNotFound, "NOT_FOUND", err.Error())
+	case errors.Is(err, service.ErrNotCreditCard):
+		respond.Error(w, http.StatusBadRequest, "NOT_A_CREDIT_CARD", err.Error())
+	case errors.Is(err, service.ErrInvalidCreditCard), errors.Is(err, service.ErrAmountPrecision):
+		respond.Error(w, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,263 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/dto"
+	"github.com/sanches/finance-tracker-cc/backend/internal/handler/respond"
+	"github.com/sanches/finance-tracker-cc/backend/internal/middleware"
+	"github.com/sanches/finance-tracker-cc/backend/internal/service"
+)
+
+type Debt struct {
+	svc *service.Debt
+}
+
+func NewDebt(svc *service.Debt) *Debt {
+	return &Debt{svc: svc}
+}
+
+func (h *Debt) ListContacts(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	contacts, err := h.svc.ListContacts(r.Context(), userID)
+	if err != nil {
+		slog.Error("failed to list contacts", "error", err, "user_id", userID)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to list contacts")
+		return
+	}
+	respond.JSON(w, http.StatusOK, map[string]any{"data": contacts})
+}
+
+func (h *Debt) CreateContact(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	var req dto.ContactRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_BODY", "invalid request body")
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		respond.Error(w, http.StatusBadRequest, "VALIDATION_ERROR", validationMessage(err))
+		return
+	}
+
+	contact, err := h.svc.CreateContact(r.Context(), userID, req)
+	if err != nil {
+		if respondDebtError(w, err) {
+			return
+		}
+		slog.Error("failed to create contact", "error", err, "user_id", userID)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to create contact")
+		return
+	}
+	respond.JSON(w, http.StatusCreated, contact)
+}
+
+func (h *Debt) UpdateContact(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_ID", "invalid contact ID")
+		return
+	}
+
+	var req dto.ContactRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_BODY", "invalid request body")
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		respond.Error(w, http.StatusBadRequest, "VALIDATION_ERROR", validationMessage(err))
+		return
+	}
+
+	contact, err := h.svc.UpdateContact(r.Context(), userID, id, req)
+	if err != nil {
+		if respondDebtError(w, err) {
+			return
+		}
+		slog.Error("failed to update contact", "error", err, "user_id", userID, "contact_id", id)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to update contact")
+		return
+	}
+	respond.JSON(w, http.StatusOK, contact)
+}
+
+func (h *Debt) DeleteContact(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_ID", "invalid contact ID")
+		return
+	}
+
+	if err := h.svc.DeleteContact(r.Context(), userID, id); err != nil {
+		if respondDebtError(w, err) {
+			return
+		}
+		slog.Error("failed to delete contact", "error", err, "user_id", userID, "contact_id", id)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to delete contact")
+		return
+	}
+	respond.NoContent(w)
+}
+
+func (h *Debt) Settle(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_ID", "invalid contact ID")
+		return
+	}
+
+	var req dto.SettleRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_BODY", "invalid request body")
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		respond.Error(w, http.StatusBadRequest, "VALIDATION_ERROR", validationMessage(err))
+		return
+	}
+
+	settlement, err := h.svc.Settle(r.Context(), userID, id, req)
+	if err != nil {
+		if respondDebtError(w, err) {
+			return
+		}
+		slog.Error("failed to settle debt", "error", err, "user_id", userID, "contact_id", id)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to settle debt")
+		return
+	}
+	respond.JSON(w, http.StatusCreated, settlement)
+}
+
+func (h *Debt) ListSplits(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	var contactID *uuid.UUID
+	if v := r.URL.Query().Get("contact_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			respond.Error(w, http.StatusBadRequest, "INVALID_ID", "invalid contact_id")
+			return
+		}
+		contactID = &id
+	}
+
+	splits, err := h.svc.ListSplits(r.Context(), userID, contactID)
+	if err != nil {
+		slog.Error("failed to list splits", "error", err, "user_id", userID)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to list splits")
+		return
+	}
+	respond.JSON(w, http.StatusOK, map[string]any{"data": splits})
+}
+
+func (h *Debt) CreateSplit(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	var req dto.CreateSplitRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_BODY", "invalid request body")
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		respond.Error(w, http.StatusBadRequest, "VALIDATION_ERROR", validationMessage(err))
+		return
+	}
+
+	split, err := h.svc.CreateSplit(r.Context(), userID, req)
+	if err != nil {
+		if respondDebtError(w, err) {
+			return
+		}
+		slog.Error("failed to create split", "error", err, "user_id", userID)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to create split")
+		return
+	}
+	respond.JSON(w, http.StatusCreated, split)
+}
+
+func (h *Debt) GetSplit(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_ID", "invalid split ID")
+		return
+	}
+
+	split, err := h.svc.GetSplit(r.Context(), userID, id)
+	if err != nil {
+		if respondDebtError(w, err) {
+			return
+		}
+		slog.Error("failed to get split", "error", err, "user_id", userID, "split_id", id)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to get split")
+		return
+	}
+	respond.JSON(w, http.StatusOK, split)
+}
+
+func (h *Debt) DeleteSplit(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_ID", "invalid split ID")
+		return
+	}
+
+	if err := h.svc.DeleteSplit(r.Context(), userID, id); err != nil {
+		if respondDebtError(w, err) {
+			return
+		}
+		slog.Error("failed to delete split", "error", err, "user_id", userID, "split_id", id)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to delete split")
+		return
+	}
+	respond.NoContent(w)
+}
+
+func (h *Debt) Report(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	report, err := h.svc.Report(r.Context(), userID)
+	if err != nil {
+		slog.Error("failed to get debts report", "error", err, "user_id", userID)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to get debts report")
+		return
+	}
+	respond.JSON(w, http.StatusOK, report)
+}
+
+// respondDebtError maps contact and split lookups, splits of a transaction
+// that is already split or paid by someone else, and settlements with
+// nothing left to settle.
+func respondDebtError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, service.ErrNotFound):
+		respond.Error(w, http.StatusNotFound, "NOT_FOUND", "transaction not found")
+	case errors.Is(err, service.ErrContactNotFound), errors.Is(err, service.ErrSplitNotFound),
+		errors.Is(err, service.ErrAccountNotFound):
+		respond.Error(w, http.StatusNotFound, "NOT_FOUND", err.Error())
+	case errors.Is(err, service.ErrContactExists):
+		respond.Error(w, http.StatusConflict, "CONTACT_EXISTS", err.Error())
+	case errors.Is(err, service.ErrContactInUse):
+		respond.Error(w, http.StatusConflict, "CONTACT_IN_USE", err.Error())
+	case errors.Is(err, service.ErrAlreadySplit):
+		respond.Error(w, http.StatusConflict, "ALREADY_SPLIT", err.Error())
+	case errors.Is(err, service.ErrNothingToSettle):
+		respond.Error(w, http.StatusConflict, "NOTHING_TO_SETTLE", err.Error())
+	case errors.Is(err, service.ErrAccountArchived):
+		respond.Error(w, http.StatusConflict, "ACCOUNT_ARCHIVED", err.Error())
+	case errors.Is(err, service.ErrSplitPayer), errors.Is(err, service.ErrSplitTransaction),
+		errors.Is(err, service.ErrInvalidSplit), errors.Is(err, service.ErrSplitParticipants),
+		errors.Is(err, service.ErrSplitShares), errors.Is(err, service.ErrInvalidSettlement),
+		errors.Is(err, service.ErrAmountPrecision):
+		respond.Error(w, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+	default:
+		return false
+	}
+	return true
+}
@@ -3,12 +3,17 @@ package handler
 import (
 	"context"
 	"crypto/subtle"
+	"errors"
 	"log/slog"
 	"net/http"
 	"time"
 
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
 	"github.com/sanches/finance-tracker-cc/backend/internal/dto"
 	"github.com/sanches/finance-tracker-cc/backend/internal/handler/respond"
+	"github.com/sanches/finance-tracker-cc/backend/internal/middleware"
 	"github.com/sanches/finance-tracker-cc/backend/internal/service"
 )
 
@@ -23,7 +28,8 @@ func NewExchangeRate(svc *service.ExchangeRate, syncSvc *service.ExchangeRateSyn
 }
 
 func (h *ExchangeRate) List(w http.ResponseWriter, r *http.Request) {
-	rates, err := h.svc.List(r.Context())
+	userID := middleware.UserID(r.Context())
+	rates, err := h.svc.List(r.Context(), userID)
 	if err != nil {
 		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to list exchange rates")
 		return
@@ -32,6 +38,7 @@ func (h *ExchangeRate) List(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *ExchangeRate) Create(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
 	var req dto.CreateExchangeRateRequest
 	if err := decodeJSON(w, r, &req); err != nil {
 		respond.Error(w, http.StatusBadRequest, "INVALID_BODY", "invalid request body")
@@ -42,22 +49,65 @@ func (h *ExchangeRate) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	rate, err := h.svc.Create(r.Context(), req)
+	rate, err := h.svc.Create(r.Context(), userID, req)
 	if err != nil {
+		if errors.Is(err, service.ErrAdminRequired) {
+			respond.Error(w, http.StatusForbidden, "FORBIDDEN", "only admins can set global exchange rates")
+			return
+		}
 		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to create exchange rate")
 		return
 	}
 	respond.JSON(w, http.StatusCreated, rate)
 }
 
-func (h *ExchangeRate) Sync(w http.ResponseWriter, r *http.Request) {
-	if h.token == "" {
-		respond.Error(w, http.StatusUnauthorized, "UNAUTHORIZED", "invalid or missing sync token")
+func (h *ExchangeRate) Delete(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_ID", "invalid exchange rate ID")
 		return
 	}
-	provided := r.Header.Get("X-Sync-Token")
-	if len(provided) != len(h.token) || subtle.ConstantTimeCompare([]byte(provided), []byte(h.token)) != 1 {
-		respond.Error(w, http.StatusUnauthorized, "UNAUTHORIZED", "invalid or missing sync token")
+
+	if err := h.svc.Delete(r.Context(), userID, id); err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			respond.Error(w, http.StatusNotFound, "NOT_FOUND", "personal exchange rate not found")
+			return
+		}
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to delete exchange rate")
+		return
+	}
+	respond.NoContent(w)
+}
+
+func (h *ExchangeRate) Convert(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	q := r.URL.Query()
+	req := dto.ConvertRequest{
+		Amount: q.Get("amount"),
+		From:   q.Get("from"),
+		To:     q.Get("to"),
+		Date:   q.Get("date"),
+	}
+	if err := validate.Struct(req); err != nil {
+		respond.Error(w, http.StatusBadRequest, "VALIDATION_ERROR", validationMessage(err))
+		return
+	}
+
+	result, err := h.svc.Convert(r.Context(), userID, req)
+	if err != nil {
+		if errors.Is(err, service.ErrRateNotFound) {
+			respond.Error(w, http.StatusNotFound, "RATE_NOT_FOUND", err.Error())
+			return
+		}
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to convert amount")
+		return
+	}
+	respond.JSON(w, http.StatusOK, result)
+}
+
+func (h *ExchangeRate) Sync(w http.ResponseWriter, r *http.Request) {
+	if !h.checkSyncToken(w, r) {
 		return
 	}
 
@@ -72,3 +122,41 @@ func (h *ExchangeRate) Sync(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusAccepted)
 }
+
+func (h *ExchangeRate) Backfill(w http.ResponseWriter, r *http.Request) {
+	if !h.checkSyncToken(w, r) {
+		return
+	}
+	if h.syncSvc.BackfillInProgress() {
+		respond.Error(w, http.StatusConflict, "BACKFILL_IN_PROGRESS", service.ErrBackfillInProgress.Error())
+		return
+	}
+
+	// A backfill can issue hundreds of throttled requests, so it always runs
+	// in the background. Progress is saved as it goes; a run cut short by the
+	// timeout is continued by the next call.
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+		defer cancel()
+		if _, err := h.syncSvc.Backfill(ctx); err != nil {
+			slog.Error("exchange rate backfill failed", "error", err)
+		}
+	}()
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// checkSyncToken verifies the X-Sync-Token header and writes a 401 response
+// when it is missing or wrong.
+func (h *ExchangeRate) checkSyncToken(w http.ResponseWriter, r *http.Request) bool {
+	if h.token == "" {
+		respond.Error(w, http.StatusUnauthorized, "UNAUTHORIZED", "invalid or missing sync token")
+		return false
+	}
+	provided := r.Header.Get("X-Sync-Token")
+	if len(provided) != len(h.token) || subtle.ConstantTimeCompare([]byte(provided), []byte(h.token)) != 1 {
+		respond.Error(w, http.StatusUnauthorized, "UNAUTHORIZED", "invalid or missing sync token")
+		return false
+	}
+	return true
+}
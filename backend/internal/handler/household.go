@@ -0,0 +1,233 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/dto"
+	"github.com/sanches/finance-tracker-cc/backend/internal/handler/respond"
+	"github.com/sanches/finance-tracker-cc/backend/internal/middleware"
+	"github.com/sanches/finance-tracker-cc/backend/internal/service"
+)
+
+type Household struct {
+	svc *service.Household
+}
+
+func NewHousehold(svc *service.Household) *Household {
+	return &Household{svc: svc}
+}
+
+func (h *Household) List(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	households, err := h.svc.List(r.Context(), userID)
+	if err != nil {
+		slog.Error("failed to list households", "error", err, "user_id", userID)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to list households")
+		return
+	}
+	respond.JSON(w, http.StatusOK, map[string]any{"data": households})
+}
+
+func (h *Household) Create(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	var req dto.HouseholdRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_BODY", "invalid request body")
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		respond.Error(w, http.StatusBadRequest, "VALIDATION_ERROR", validationMessage(err))
+		return
+	}
+
+	household, err := h.svc.Create(r.Context(), userID, req)
+	if err != nil {
+		slog.Error("failed to create household", "error", err, "user_id", userID)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to create household")
+		return
+	}
+	respond.JSON(w, http.StatusCreated, household)
+}
+
+func (h *Household) Get(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_ID", "invalid household ID")
+		return
+	}
+
+	household, err := h.svc.Get(r.Context(), userID, id)
+	if err != nil {
+		if respondHouseholdError(w, err) {
+			return
+		}
+		slog.Error("failed to get household", "error", err, "user_id", userID, "household_id", id)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to get household")
+		return
+	}
+	respond.JSON(w, http.StatusOK, household)
+}
+
+func (h *Household) Update(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_ID", "invalid household ID")
+		return
+	}
+
+	var req dto.HouseholdRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_BODY", "invalid request body")
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		respond.Error(w, http.StatusBadRequest, "VALIDATION_ERROR", validationMessage(err))
+		return
+	}
+
+	household, err := h.svc.Update(r.Context(), userID, id, req)
+	if err != nil {
+		if respondHouseholdError(w, err) {
+			return
+		}
+		slog.Error("failed to update household", "error", err, "user_id", userID, "household_id", id)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to update household")
+		return
+	}
+	respond.JSON(w, http.StatusOK, household)
+}
+
+func (h *Household) Delete(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_ID", "invalid household ID")
+		return
+	}
+
+	if err := h.svc.Delete(r.Context(), userID, id); err != nil {
+		if respondHouseholdError(w, err) {
+			return
+		}
+		slog.Error("failed to delete household", "error", err, "user_id", userID, "household_id", id)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to delete household")
+		return
+	}
+	respond.NoContent(w)
+}
+
+func (h *Household) AddMember(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_ID", "invalid household ID")
+		return
+	}
+
+	var req dto.AddHouseholdMemberRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_BODY", "invalid request body")
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		respond.Error(w, http.StatusBadRequest, "VALIDATION_ERROR", validationMessage(err))
+		return
+	}
+
+	household, err := h.svc.AddMember(r.Context(), userID, id, req)
+	if err != nil {
+		if respondHouseholdError(w, err) {
+			return
+		}
+		slog.Error("failed to add household member", "error", err, "user_id", userID, "household_id", id)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to add household member")
+		return
+	}
+	respond.JSON(w, http.StatusCreated, household)
+}
+
+func (h *Household) UpdateMember(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_ID", "invalid household ID")
+		return
+	}
+	memberID, err := uuid.Parse(chi.URLParam(r, "userID"))
+	if err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_ID", "invalid user ID")
+		return
+	}
+
+	var req dto.UpdateHouseholdMemberRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_BODY", "invalid request body")
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		respond.Error(w, http.StatusBadRequest, "VALIDATION_ERROR", validationMessage(err))
+		return
+	}
+
+	household, err := h.svc.UpdateMember(r.Context(), userID, id, memberID, req)
+	if err != nil {
+		if respondHouseholdError(w, err) {
+			return
+		}
+		slog.Error("failed to update household member", "error", err, "user_id", userID, "household_id", id)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to update household member")
+		return
+	}
+	respond.JSON(w, http.StatusOK, household)
+}
+
+func (h *Household) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_ID", "invalid household ID")
+		return
+	}
+	memberID, err := uuid.Parse(chi.URLParam(r, "userID"))
+	if err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_ID", "invalid user ID")
+		return
+	}
+
+	if err := h.svc.RemoveMember(r.Context(), userID, id, memberID); err != nil {
+		if respondHouseholdError(w, err) {
+			return
+		}
+		slog.Error("failed to remove household member", "error", err, "user_id", userID, "household_id", id)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to remove household member")
+		return
+	}
+	respond.NoContent(w)
+}
+
+// respondHouseholdError maps the membership errors: unknown households,
+// members or users, actions only an owner may take, and removing the last
+// owner.
+func respondHouseholdError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, service.ErrHouseholdNotFound), errors.Is(err, service.ErrMemberNotFound),
+		errors.Is(err, service.ErrUserNotFound):
+		respond.Error(w, http.StatusNotFound, "NOT_FOUND", err.Error())
+	case errors.Is(err, service.ErrNotHouseholdOwner):
+		respond.Error(w, http.StatusForbidden, "FORBIDDEN", err.Error())
+	case errors.Is(err, service.ErrAlreadyMember):
+		respond.Error(w, http.StatusConflict, "ALREADY_MEMBER", err.Error())
+	case errors.Is(err, service.ErrLastOwner):
+		respond.Error(w, http.StatusConflict, "LAST_OWNER", err.Error())
+	default:
+		return false
+	}
+	return true
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/sanches/finance-tracker-cc/backend/internal/dto"
@@ -52,11 +53,19 @@ func (h *Import) Confirm(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	count, err := h.svc.ConfirmImport(r.Context(), userID, req)
+	result, err := h.svc.ConfirmImport(r.Context(), userID, req)
 	if err != nil {
+		if errors.Is(err, service.ErrAccountNotFound) {
+			respond.Error(w, http.StatusNotFound, "NOT_FOUND", "account not found")
+			return
+		}
+		if errors.Is(err, service.ErrAccountArchived) {
+			respond.Error(w, http.StatusConflict, "ACCOUNT_ARCHIVED", err.Error())
+			return
+		}
 		respond.Error(w, http.StatusInternalServerError, "IMPORT_ERROR", "failed to import transactions")
 		return
 	}
 
-	respond.JSON(w, http.StatusOK, map[string]any{"imported": count})
+	respond.JSON(w, http.StatusOK, result)
 }
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
 
@@ -36,6 +37,10 @@ func (h *ImportFull) Execute(w http.ResponseWriter, r *http.Request) {
 
 	result, err := h.svc.Import(r.Context(), userID, req)
 	if err != nil {
+		if errors.Is(err, service.ErrAdminRequired) {
+			respond.Error(w, http.StatusForbidden, "FORBIDDEN", "only admins can create currencies")
+			return
+		}
 		slog.Error("full import failed", "error", err, "user_id", userID)
 		respond.Error(w, http.StatusInternalServerError, "IMPORT_ERROR", "failed to import data")
 		return
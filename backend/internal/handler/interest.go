@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/dto"
+	"github.com/sanches/finance-tracker-cc/backend/internal/handler/respond"
+	"github.com/sanches/finance-tracker-cc/backend/internal/middleware"
+	"github.com/sanches/finance-tracker-cc/backend/internal/service"
+)
+
+type Interest struct {
+	svc *service.Interest
+}
+
+func NewInterest(svc *service.Interest) *Interest {
+	return &Interest{svc: svc}
+}
+
+func (h *Interest) Get(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_ID", "invalid account ID")
+		return
+	}
+
+	resp, err := h.svc.Get(r.Context(), userID, id)
+	if err != nil {
+		if respondInterestError(w, err) {
+			return
+		}
+		slog.Error("failed to get interest terms", "error", err, "user_id", userID, "account_id", id)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to get interest terms")
+		return
+	}
+	respond.JSON(w, http.StatusOK, resp)
+}
+
+func (h *Interest) Update(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_ID", "invalid account ID")
+		return
+	}
+
+	var req dto.UpdateInterestRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_BODY", "invalid request body")
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		respond.Error(w, http.StatusBadRequest, "VALIDATION_ERROR", validationMessage(err))
+		return
+	}
+
+	resp, err := h.svc.Update(r.Context(), userID, id, req)
+	if err != nil {
+		if respondInterestError(w, err) {
+			return
+		}
+		slog.Error("failed to update interest terms", "error", err, "user_id", userID, "account_id", id)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to update interest terms")
+		return
+	}
+	respond.JSON(w, http.StatusOK, resp)
+}
+
+func (h *Interest) Delete(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_ID", "invalid account ID")
+		return
+	}
+
+	if err := h.svc.Delete(r.Context(), userID, id); err != nil {
+		if respondInterestError(w, err) {
+			return
+		}
+		slog.Error("failed to delete interest terms", "error", err, "user_id", userID, "account_id", id)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to delete interest terms")
+		return
+	}
+	respond.NoContent(w)
+}
+
+func (h *Interest) ListAccruals(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_ID", "invalid account ID")
+		return
+	}
+
+	accruals, err := h.svc.ListAccruals(r.Context(), userID, id)
+	if err != nil {
+		if respondInterestError(w, err) {
+			return
+		}
+		slog.Error("failed to list interest accruals", "error", err, "user_id", userID, "account_id", id)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to list interest accruals")
+		return
+	}
+	respond.JSON(w, http.StatusOK, map[string]any{"data": accruals})
+}
+
+func (h *Interest) Accrue(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_ID", "invalid account ID")
+		return
+	}
+
+	accruals, err := h.svc.Accrue(r.Context(), userID, id)
+	if err != nil {
+		if respondInterestError(w, err) {
+			return
+		}
+		slog.Error("failed to accrue interest", "error", err, "user_id", userID, "account_id", id)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to accrue interest")
+		return
+	}
+	respond.JSON(w, http.StatusOK, map[string]any{"data": accruals})
+}
+
+// respondInterestError sends 404 for deposits without an interest set-up,
+// 409 for archived accounts, and 400 for a bad rate, payout account or
+// income category.
+func respondInterestError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, service.ErrAccountNotFound):
+		respond.Error(w, http.StatusNotFound, "NOT_FOUND", "account not found")
+	case errors.Is(err, service.ErrInterestNotSetUp):
+		respond.Error(w, http.StatusNotFound, "NOT_FOUND", err.Error())
+	case errors.Is(err, service.ErrNotDeposit):
+		respond.Error(w, http.StatusBadRequest, "NOT_A_DEPOSIT_ACCOUNT", err.Error())
+	case errors.Is(err, service.ErrAccountArchived):
+		respond.Error(w, http.StatusConflict, "ACCOUNT_ARCHIVED", err.Error())
+	case errors.Is(err, service.ErrInvalidInterest), errors.Is(err, service.ErrInvalidPayoutAccount),
+		errors.Is(err, service.ErrInvalidIncomeCategory):
+		respond.Error(w, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+	default:
+		return false
+	}
+	return true
+}
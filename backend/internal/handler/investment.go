@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/dto"
+	"github.com/sanches/finance-tracker-cc/backend/internal/handler/respond"
+	"github.com/sanches/finance-tracker-cc/backend/internal/middleware"
+	"github.com/sanches/finance-tracker-cc/backend/internal/service"
+)
+
+type Investment struct {
+	svc *service.Investment
+}
+
+func NewInvestment(svc *service.Investment) *Investment {
+	return &Investment{svc: svc}
+}
+
+func (h *Investment) ListTrades(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	accountID, err := uuid.Parse(r.URL.Query().Get("account_id"))
+	if err != nil {
+		respond.Error(w, http.StatusBadRequest, "MISSING_PARAM", "account_id is required")
+		return
+	}
+
+	trades, err := h.svc.ListTrades(r.Context(), userID, accountID)
+	if err != nil {
+		if respondInvestmentError(w, err) {
+			return
+		}
+		slog.Error("failed to list trades", "error", err, "user_id", userID, "account_id", accountID)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to list trades")
+		return
+	}
+	respond.JSON(w, http.StatusOK, map[string]any{"data": trades})
+}
+
+func (h *Investment) CreateTrade(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+
+	var req dto.CreateTradeRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_BODY", "invalid request body")
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		respond.Error(w, http.StatusBadRequest, "VALIDATION_ERROR", validationMessage(err))
+		return
+	}
+
+	trade, err := h.svc.CreateTrade(r.Context(), userID, req)
+	if err != nil {
+		if respondInvestmentError(w, err) {
+			return
+		}
+		slog.Error("failed to create trade", "error", err, "user_id", userID, "account_id", req.AccountID)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to create trade")
+		return
+	}
+	respond.JSON(w, http.StatusCreated, trade)
+}
+
+func (h *Investment) DeleteTrade(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_ID", "invalid trade ID")
+		return
+	}
+
+	if err := h.svc.DeleteTrade(r.Context(), userID, id); err != nil {
+		if respondInvestmentError(w, err) {
+			return
+		}
+		slog.Error("failed to delete trade", "error", err, "user_id", userID, "trade_id", id)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to delete trade")
+		return
+	}
+	respond.NoContent(w)
+}
+
+func (h *Investment) Holdings(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_ID", "invalid account ID")
+		return
+	}
+
+	holdings, err := h.svc.Holdings(r.Context(), userID, id)
+	if err != nil {
+		if respondInvestmentError(w, err) {
+			return
+		}
+		slog.Error("failed to get holdings", "error", err, "user_id", userID, "account_id", id)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to get holdings")
+		return
+	}
+	respond.JSON(w, http.StatusOK, holdings)
+}
+
+// respondInvestmentError maps trades on a non-investment or archived account,
+// sales of more shares than are held, trades in the wrong currency, and
+// edits to a trade whose cash transaction is reconciled.
+func respondInvestmentError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, service.ErrAccountNotFound):
+		respond.Error(w, http.StatusNotFound, "NOT_FOUND", "account not found")
+	case errors.Is(err, service.ErrSecurityNotFound), errors.Is(err, service.ErrTradeNotFound):
+		respond.Error(w, http.StatusNotFound, "NOT_FOUND", err.Error())
+	case errors.Is(err, service.ErrNotInvestment):
+		respond.Error(w, http.StatusBadRequest, "NOT_AN_INVESTMENT_ACCOUNT", err.Error())
+	case errors.Is(err, service.ErrAccountArchived):
+		respond.Error(w, http.StatusConflict, "ACCOUNT_ARCHIVED", err.Error())
+	case errors.Is(err, service.ErrTransactionReconciled):
+		respond.Error(w, http.StatusConflict, "TRANSACTION_RECONCILED", err.Error())
+	case errors.Is(err, service.ErrInsufficientShares):
+		respond.Error(w, http.StatusConflict, "INSUFFICIENT_SHARES", err.Error())
+	case errors.Is(err, service.ErrInvalidTrade), errors.Is(err, service.ErrTradeCurrency),
+		errors.Is(err, service.ErrInvalidIncomeCategory), errors.Is(err, service.ErrAmountPrecision):
+		respond.Error(w, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/dto"
+	"github.com/sanches/finance-tracker-cc/backend/internal/handler/respond"
+	"github.com/sanches/finance-tracker-cc/backend/internal/middleware"
+	"github.com/sanches/finance-tracker-cc/backend/internal/service"
+)
+
+type Loan struct {
+	svc *service.Loan
+}
+
+func NewLoan(svc *service.Loan) *Loan {
+	return &Loan{svc: svc}
+}
+
+func (h *Loan) Get(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_ID", "invalid account ID")
+		return
+	}
+
+	loan, err := h.svc.Get(r.Context(), userID, id)
+	if err != nil {
+		if respondLoanError(w, err) {
+			return
+		}
+		slog.Error("failed to get loan", "error", err, "user_id", userID, "account_id", id)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to get loan")
+		return
+	}
+	respond.JSON(w, http.StatusOK, loan)
+}
+
+func (h *Loan) Update(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_ID", "invalid account ID")
+		return
+	}
+
+	var req dto.UpdateLoanRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_BODY", "invalid request body")
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		respond.Error(w, http.StatusBadRequest, "VALIDATION_ERROR", validationMessage(err))
+		return
+	}
+
+	loan, err := h.svc.Update(r.Context(), userID, id, req)
+	if err != nil {
+		if respondLoanError(w, err) {
+			return
+		}
+		slog.Error("failed to update loan", "error", err, "user_id", userID, "account_id", id)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to update loan")
+		return
+	}
+	respond.JSON(w, http.StatusOK, loan)
+}
+
+func (h *Loan) Schedule(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_ID", "invalid account ID")
+		return
+	}
+
+	schedule, err := h.svc.Schedule(r.Context(), userID, id)
+	if err != nil {
+		if respondLoanError(w, err) {
+			return
+		}
+		slog.Error("failed to build loan schedule", "error", err, "user_id", userID, "account_id", id)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to build loan schedule")
+		return
+	}
+	respond.JSON(w, http.StatusOK, map[string]any{"data": schedule})
+}
+
+func (h *Loan) SplitPayments(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_ID", "invalid account ID")
+		return
+	}
+
+	splits, err := h.svc.SplitPayments(r.Context(), userID, id)
+	if err != nil {
+		if respondLoanError(w, err) {
+			return
+		}
+		slog.Error("failed to split loan payments", "error", err, "user_id", userID, "account_id", id)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to split loan payments")
+		return
+	}
+	respond.JSON(w, http.StatusOK, map[string]any{"data": splits})
+}
+
+// respondLoanError sends 404 for loans without terms, 400 when the account
+// is not a loan or the terms are invalid, and 409 when it is archived.
+func respondLoanError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, service.ErrAccountNotFound):
+		respond.Error(w, http.StatusNotFound, "NOT_FOUND", "account not found")
+	case errors.Is(err, service.ErrLoanNotSetUp):
+		respond.Error(w, http.StatusNotFound, "NOT_FOUND", err.Error())
+	case errors.Is(err, service.ErrNotLoan):
+		respond.Error(w, http.StatusBadRequest, "NOT_A_LOAN", err.Error())
+	case errors.Is(err, service.ErrAccountArchived):
+		respond.Error(w, http.StatusConflict, "ACCOUNT_ARCHIVED", err.Error())
+	case errors.Is(err, service.ErrInvalidLoan), errors.Is(err, service.ErrInvalidInterestCategory), errors.Is(err, service.ErrAmountPrecision):
+		respond.Error(w, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/dto"
+	"github.com/sanches/finance-tracker-cc/backend/internal/handler/respond"
+	"github.com/sanches/finance-tracker-cc/backend/internal/middleware"
+	"github.com/sanches/finance-tracker-cc/backend/internal/service"
+)
+
+type Reconciliation struct {
+	svc *service.Reconciliation
+}
+
+func NewReconciliation(svc *service.Reconciliation) *Reconciliation {
+	return &Reconciliation{svc: svc}
+}
+
+func (h *Reconciliation) List(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	accountID, err := uuid.Parse(r.URL.Query().Get("account_id"))
+	if err != nil {
+		respond.Error(w, http.StatusBadRequest, "MISSING_PARAM", "account_id is required")
+		return
+	}
+
+	recs, err := h.svc.List(r.Context(), userID, accountID)
+	if err != nil {
+		if respondReconciliationError(w, err) {
+			return
+		}
+		slog.Error("failed to list reconciliations", "error", err, "user_id", userID)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to list reconciliations")
+		return
+	}
+	respond.JSON(w, http.StatusOK, map[string]any{"data": recs})
+}
+
+func (h *Reconciliation) Start(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	var req dto.StartReconciliationRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_BODY", "invalid request body")
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		respond.Error(w, http.StatusBadRequest, "VALIDATION_ERROR", validationMessage(err))
+		return
+	}
+
+	rec, err := h.svc.Start(r.Context(), userID, req)
+	if err != nil {
+		if respondReconciliationError(w, err) {
+			return
+		}
+		slog.Error("failed to start reconciliation", "error", err, "user_id", userID)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to start reconciliation")
+		return
+	}
+	respond.JSON(w, http.StatusCreated, rec)
+}
+
+func (h *Reconciliation) Get(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_ID", "invalid reconciliation ID")
+		return
+	}
+
+	rec, err := h.svc.Get(r.Context(), userID, id)
+	if err != nil {
+		if respondReconciliationError(w, err) {
+			return
+		}
+		slog.Error("failed to get reconciliation", "error", err, "user_id", userID, "reconciliation_id", id)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to get reconciliation")
+		return
+	}
+	respond.JSON(w, http.StatusOK, rec)
+}
+
+func (h *Reconciliation) Finish(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_ID", "invalid reconciliation ID")
+		return
+	}
+
+	rec, err := h.svc.Finish(r.Context(), userID, id)
+	if err != nil {
+		if respondReconciliationError(w, err) {
+			return
+		}
+		slog.Error("failed to finish reconciliation", "error", err, "user_id", userID, "reconciliation_id", id)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to finish reconciliation")
+		return
+	}
+	respond.JSON(w, http.StatusOK, rec)
+}
+
+func (h *Reconciliation) Cancel(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_ID", "invalid reconciliation ID")
+		return
+	}
+
+	if err := h.svc.Cancel(r.Context(), userID, id); err != nil {
+		if respondReconciliationError(w, err) {
+			return
+		}
+		slog.Error("failed to cancel reconciliation", "error", err, "user_id", userID, "reconciliation_id", id)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to cancel reconciliation")
+		return
+	}
+	respond.NoContent(w)
+}
+
+// respondReconciliationError maps the reconciliation workflow: a second
+// open reconciliation on an account, changes after it is finished, and
+// finishing while the cleared balance is off.
+func respondReconciliationError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, service.ErrNotFound):
+		respond.Error(w, http.StatusNotFound, "NOT_FOUND", "reconciliation not found")
+	case errors.Is(err, service.ErrAccountNotFound):
+		respond.Error(w, http.StatusNotFound, "NOT_FOUND", "account not found")
+	case errors.Is(err, service.ErrInvalidStatement), errors.Is(err, service.ErrAmountPrecision):
+		respond.Error(w, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+	case errors.Is(err, service.ErrReconciliationInProgress):
+		respond.Error(w, http.StatusConflict, "RECONCILIATION_IN_PROGRESS", err.Error())
+	case errors.Is(err, service.ErrReconciliationFinished):
+		respond.Error(w, http.StatusConflict, "RECONCILIATION_FINISHED", err.Error())
+	case errors.Is(err, service.ErrReconciliationUnbalanced):
+		respond.Error(w, http.StatusConflict, "RECONCILIATION_UNBALANCED", err.Error())
+	default:
+		return false
+	}
+	return true
+}
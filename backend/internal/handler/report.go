@@ -112,6 +112,18 @@ func (h *Report) CashFlow(w http.ResponseWriter, r *http.Request) {
 	respond.JSON(w, http.StatusOK, result)
 }
 
+func (h *Report) FXGainLoss(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	dateFrom, dateTo := getDateRange(r)
+
+	result, err := h.svc.FXGainLoss(r.Context(), userID, dateFrom, dateTo)
+	if err != nil {
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to get FX gain/loss report")
+		return
+	}
+	respond.JSON(w, http.StatusOK, result)
+}
+
 func getDateRange(r *http.Request) (string, string) {
 	q := r.URL.Query()
 	dateFrom := q.Get("date_from")
@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/dto"
+	"github.com/sanches/finance-tracker-cc/backend/internal/handler/respond"
+	"github.com/sanches/finance-tracker-cc/backend/internal/middleware"
+	"github.com/sanches/finance-tracker-cc/backend/internal/service"
+)
+
+type Security struct {
+	svc *service.Security
+}
+
+func NewSecurity(svc *service.Security) *Security {
+	return &Security{svc: svc}
+}
+
+func (h *Security) List(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+
+	securities, err := h.svc.List(r.Context(), userID)
+	if err != nil {
+		slog.Error("failed to list securities", "error", err, "user_id", userID)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to list securities")
+		return
+	}
+	respond.JSON(w, http.StatusOK, map[string]any{"data": securities})
+}
+
+func (h *Security) Create(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+
+	var req dto.CreateSecurityRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_BODY", "invalid request body")
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		respond.Error(w, http.StatusBadRequest, "VALIDATION_ERROR", validationMessage(err))
+		return
+	}
+
+	sec, err := h.svc.Create(r.Context(), userID, req)
+	if err != nil {
+		if respondSecurityError(w, err) {
+			return
+		}
+		slog.Error("failed to create security", "error", err, "user_id", userID)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to create security")
+		return
+	}
+	respond.JSON(w, http.StatusCreated, sec)
+}
+
+func (h *Security) Update(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_ID", "invalid security ID")
+		return
+	}
+
+	var req dto.UpdateSecurityRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_BODY", "invalid request body")
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		respond.Error(w, http.StatusBadRequest, "VALIDATION_ERROR", validationMessage(err))
+		return
+	}
+
+	sec, err := h.svc.Update(r.Context(), userID, id, req)
+	if err != nil {
+		if respondSecurityError(w, err) {
+			return
+		}
+		slog.Error("failed to update security", "error", err, "user_id", userID, "security_id", id)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to update security")
+		return
+	}
+	respond.JSON(w, http.StatusOK, sec)
+}
+
+func (h *Security) Delete(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_ID", "invalid security ID")
+		return
+	}
+
+	if err := h.svc.Delete(r.Context(), userID, id); err != nil {
+		if respondSecurityError(w, err) {
+			return
+		}
+		slog.Error("failed to delete security", "error", err, "user_id", userID, "security_id", id)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to delete security")
+		return
+	}
+	respond.NoContent(w)
+}
+
+func (h *Security) ListPrices(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_ID", "invalid security ID")
+		return
+	}
+
+	prices, err := h.svc.ListPrices(r.Context(), userID, id)
+	if err != nil {
+		if respondSecurityError(w, err) {
+			return
+		}
+		slog.Error("failed to list security prices", "error", err, "user_id", userID, "security_id", id)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to list security prices")
+		return
+	}
+	respond.JSON(w, http.StatusOK, map[string]any{"data": prices})
+}
+
+func (h *Security) SetPrice(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_ID", "invalid security ID")
+		return
+	}
+
+	var req dto.SetSecurityPriceRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_BODY", "invalid request body")
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		respond.Error(w, http.StatusBadRequest, "VALIDATION_ERROR", validationMessage(err))
+		return
+	}
+
+	price, err := h.svc.SetPrice(r.Context(), userID, id, req)
+	if err != nil {
+		if respondSecurityError(w, err) {
+			return
+		}
+		slog.Error("failed to set security price", "error", err, "user_id", userID, "security_id", id)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to set security price")
+		return
+	}
+	respond.JSON(w, http.StatusOK, price)
+}
+
+func (h *Security) SyncPrices(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+
+	result, err := h.svc.SyncPrices(r.Context(), userID)
+	if err != nil {
+		if respondSecurityError(w, err) {
+			return
+		}
+		slog.Error("failed to sync security prices", "error", err, "user_id", userID)
+		respond.Error(w, http.StatusBadGateway, "PRICE_SYNC_FAILED", "failed to fetch prices")
+		return
+	}
+	respond.JSON(w, http.StatusOK, result)
+}
+
+// respondSecurityError maps duplicate securities and ones still held or
+// traded, and reports price sync as unavailable when no provider is set.
+func respondSecurityError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, service.ErrSecurityNotFound):
+		respond.Error(w, http.StatusNotFound, "NOT_FOUND", err.Error())
+	case errors.Is(err, service.ErrSecurityExists):
+		respond.Error(w, http.StatusConflict, "SECURITY_EXISTS", err.Error())
+	case errors.Is(err, service.ErrSecurityInUse):
+		respond.Error(w, http.StatusConflict, "SECURITY_IN_USE", err.Error())
+	case errors.Is(err, service.ErrPriceSyncDisabled):
+		respond.Error(w, http.StatusServiceUnavailable, "PRICE_SYNC_DISABLED", err.Error())
+	case errors.Is(err, service.ErrInvalidSecurity), errors.Is(err, service.ErrInvalidPrice):
+		respond.Error(w, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+	default:
+		return false
+	}
+	return true
+}
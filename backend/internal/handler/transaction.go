@@ -2,6 +2,7 @@ package handler
 
 import (
 	"errors"
+	"log/slog"
 	"net/http"
 	"strconv"
 
@@ -35,6 +36,7 @@ func (h *Transaction) List(w http.ResponseWriter, r *http.Request) {
 		DateFrom:    q.Get("date_from"),
 		DateTo:      q.Get("date_to"),
 		Description: desc,
+		Status:      q.Get("status"),
 		Page:        1,
 		PerPage:     20,
 	}
@@ -51,6 +53,12 @@ func (h *Transaction) List(w http.ResponseWriter, r *http.Request) {
 			params.CategoryIDs = append(params.CategoryIDs, id)
 		}
 	}
+	for _, v := range q["group_id"] {
+		id, err := uuid.Parse(v)
+		if err == nil {
+			params.GroupIDs = append(params.GroupIDs, id)
+		}
+	}
 	if v := q.Get("page"); v != "" {
 		if p, err := strconv.Atoi(v); err == nil {
 			params.Page = p
@@ -84,6 +92,9 @@ func (h *Transaction) Create(w http.ResponseWriter, r *http.Request) {
 
 	txn, err := h.svc.Create(r.Context(), userID, req)
 	if err != nil {
+		if respondAmountError(w, err) {
+			return
+		}
 		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to create transaction")
 		return
 	}
@@ -104,12 +115,56 @@ func (h *Transaction) CreateTransfer(w http.ResponseWriter, r *http.Request) {
 
 	txns, err := h.svc.CreateTransfer(r.Context(), userID, req)
 	if err != nil {
+		if respondTransferError(w, err) {
+			return
+		}
 		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to create transfer")
 		return
 	}
 	respond.JSON(w, http.StatusCreated, map[string]any{"data": txns})
 }
 
+// respondAmountError writes the response for an unknown, archived or
+// read-only account or an amount too precise for the account's currency, and
+// reports whether err was one.
+func respondAmountError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, service.ErrAccountNotFound):
+		respond.Error(w, http.StatusNotFound, "NOT_FOUND", "account not found")
+	case errors.Is(err, service.ErrAccountArchived):
+		respond.Error(w, http.StatusConflict, "ACCOUNT_ARCHIVED", err.Error())
+	case errors.Is(err, service.ErrHouseholdReadOnly):
+		respond.Error(w, http.StatusForbidden, "FORBIDDEN", err.Error())
+	case errors.Is(err, service.ErrAmountPrecision), errors.Is(err, service.ErrOtherOwnerAccount):
+		respond.Error(w, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+	default:
+		return false
+	}
+	return true
+}
+
+// respondTransferError writes the response for errors from resolving transfer
+// amounts and reports whether err was one of them.
+func respondTransferError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, service.ErrTransferAccountNotFound):
+		respond.Error(w, http.StatusNotFound, "NOT_FOUND", "account not found")
+	case errors.Is(err, service.ErrAccountArchived):
+		respond.Error(w, http.StatusConflict, "ACCOUNT_ARCHIVED", err.Error())
+	case errors.Is(err, service.ErrInvalidTransferAmount):
+		respond.Error(w, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+	case errors.Is(err, service.ErrAmountPrecision):
+		respond.Error(w, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+	case errors.Is(err, service.ErrTransferRateRequired):
+		respond.Error(w, http.StatusBadRequest, "EXCHANGE_RATE_REQUIRED", err.Error())
+	case errors.Is(err, service.ErrTransferAmountMismatch):
+		respond.Error(w, http.StatusBadRequest, "AMOUNT_MISMATCH", err.Error())
+	default:
+		return false
+	}
+	return true
+}
+
 func (h *Transaction) UpdateTransfer(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.UserID(r.Context())
 	id, err := uuid.Parse(chi.URLParam(r, "id"))
@@ -138,6 +193,17 @@ func (h *Transaction) UpdateTransfer(w http.ResponseWriter, r *http.Request) {
 			respond.Error(w, http.StatusBadRequest, "NOT_A_TRANSFER", "transaction is not a transfer")
 			return
 		}
+		if errors.Is(err, service.ErrTransactionReconciled) {
+			respond.Error(w, http.StatusConflict, "TRANSACTION_RECONCILED", err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrLoanPaymentSplit) {
+			respond.Error(w, http.StatusConflict, "LOAN_PAYMENT_SPLIT", err.Error())
+			return
+		}
+		if respondTransferError(w, err) {
+			return
+		}
 		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to update transfer")
 		return
 	}
@@ -188,6 +254,27 @@ func (h *Transaction) Get(w http.ResponseWriter, r *http.Request) {
 	respond.JSON(w, http.StatusOK, txn)
 }
 
+func (h *Transaction) History(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_ID", "invalid transaction ID")
+		return
+	}
+
+	history, err := h.svc.History(r.Context(), userID, id)
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			respond.Error(w, http.StatusNotFound, "NOT_FOUND", "transaction not found")
+			return
+		}
+		slog.Error("failed to get transaction history", "error", err, "user_id", userID, "transaction_id", id)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to get transaction history")
+		return
+	}
+	respond.JSON(w, http.StatusOK, map[string]any{"data": history})
+}
+
 func (h *Transaction) Update(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.UserID(r.Context())
 	id, err := uuid.Parse(chi.URLParam(r, "id"))
@@ -212,6 +299,17 @@ func (h *Transaction) Update(w http.ResponseWriter, r *http.Request) {
 			respond.Error(w, http.StatusNotFound, "NOT_FOUND", "transaction not found")
 			return
 		}
+		if errors.Is(err, service.ErrTransactionReconciled) {
+			respond.Error(w, http.StatusConflict, "TRANSACTION_RECONCILED", err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrTradeTransaction) {
+			respond.Error(w, http.StatusConflict, "TRADE_TRANSACTION", err.Error())
+			return
+		}
+		if respondAmountError(w, err) {
+			return
+		}
 		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to update transaction")
 		return
 	}
@@ -239,8 +337,62 @@ func (h *Transaction) Delete(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.svc.Delete(r.Context(), userID, id); err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			respond.Error(w, http.StatusNotFound, "NOT_FOUND", "transaction not found")
+			return
+		}
+		if errors.Is(err, service.ErrTransactionReconciled) {
+			respond.Error(w, http.StatusConflict, "TRANSACTION_RECONCILED", err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrHouseholdReadOnly) {
+			respond.Error(w, http.StatusForbidden, "FORBIDDEN", err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrTradeTransaction) {
+			respond.Error(w, http.StatusConflict, "TRADE_TRANSACTION", err.Error())
+			return
+		}
 		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to delete transaction")
 		return
 	}
 	respond.NoContent(w)
 }
+
+func (h *Transaction) SetStatus(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_ID", "invalid transaction ID")
+		return
+	}
+
+	var req dto.SetTransactionStatusRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_BODY", "invalid request body")
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		respond.Error(w, http.StatusBadRequest, "VALIDATION_ERROR", validationMessage(err))
+		return
+	}
+
+	txn, err := h.svc.SetStatus(r.Context(), userID, id, req.Status)
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			respond.Error(w, http.StatusNotFound, "NOT_FOUND", "transaction not found")
+			return
+		}
+		if errors.Is(err, service.ErrTransactionReconciled) {
+			respond.Error(w, http.StatusConflict, "TRANSACTION_RECONCILED", err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrHouseholdReadOnly) {
+			respond.Error(w, http.StatusForbidden, "FORBIDDEN", err.Error())
+			return
+		}
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to update transaction status")
+		return
+	}
+	respond.JSON(w, http.StatusOK, txn)
+}
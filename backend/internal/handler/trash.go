@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/handler/respond"
+	"github.com/sanches/finance-tracker-cc/backend/internal/middleware"
+	"github.com/sanches/finance-tracker-cc/backend/internal/service"
+)
+
+type Trash struct {
+	svc *service.Trash
+}
+
+func NewTrash(svc *service.Trash) *Trash {
+	return &Trash{svc: svc}
+}
+
+func (h *Trash) List(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+	trash, err := h.svc.List(r.Context(), userID)
+	if err != nil {
+		slog.Error("failed to list trash", "error", err, "user_id", userID)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to list trash")
+		return
+	}
+	respond.JSON(w, http.StatusOK, trash)
+}
+
+func (h *Trash) RestoreAccount(w http.ResponseWriter, r *http.Request) {
+	h.restore(w, r, "account", h.svc.RestoreAccount)
+}
+
+func (h *Trash) RestoreCategory(w http.ResponseWriter, r *http.Request) {
+	h.restore(w, r, "category", h.svc.RestoreCategory)
+}
+
+func (h *Trash) RestoreTransaction(w http.ResponseWriter, r *http.Request) {
+	h.restore(w, r, "transaction", h.svc.RestoreTransaction)
+}
+
+func (h *Trash) restore(w http.ResponseWriter, r *http.Request, kind string, fn func(ctx context.Context, userID, id uuid.UUID) error) {
+	userID := middleware.UserID(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, http.StatusBadRequest, "INVALID_ID", "invalid "+kind+" ID")
+		return
+	}
+
+	if err := fn(r.Context(), userID, id); err != nil {
+		switch {
+		case errors.Is(err, service.ErrNotFound):
+			respond.Error(w, http.StatusNotFound, "NOT_FOUND", kind+" not found in trash")
+		case errors.Is(err, service.ErrRestoreBlocked):
+			respond.Error(w, http.StatusConflict, "RESTORE_BLOCKED", err.Error())
+		case errors.Is(err, service.ErrAccountExists):
+			respond.Error(w, http.StatusConflict, "ACCOUNT_EXISTS", err.Error())
+		case errors.Is(err, service.ErrCategoryExists):
+			respond.Error(w, http.StatusConflict, "CATEGORY_EXISTS", err.Error())
+		default:
+			slog.Error("failed to restore "+kind, "error", err, "user_id", userID, "id", id)
+			respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to restore "+kind)
+		}
+		return
+	}
+	respond.NoContent(w)
+}
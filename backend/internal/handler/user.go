@@ -46,6 +46,19 @@ func (h *User) Update(w http.ResponseWriter, r *http.Request) {
 	respond.JSON(w, http.StatusOK, res)
 }
 
+func (h *User) LoginHistory(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserID(r.Context())
+
+	attempts, err := h.svc.LoginHistory(r.Context(), userID)
+	if err != nil {
+		slog.Error("failed to list login history", "error", err, "user_id", userID)
+		respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to list login history")
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, map[string]any{"data": attempts})
+}
+
 func (h *User) Reset(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.UserID(r.Context())
 
@@ -61,3 +61,24 @@ func TestRegister_ReturnsSanitizedValidationError(t *testing.T) {
 	require.NotContains(t, body, "failed on the")
 	require.NotContains(t, body, "Key:")
 }
+
+func TestLogin_RejectsOverlongUsername(t *testing.T) {
+	h := NewAuth(&stubAuthService{
+		loginFn: func(context.Context, dto.LoginRequest, service.LoginClient) (*service.AuthResult, error) {
+			t.Fatal("login service should not be called for a username that can't be stored")
+			return nil, nil
+		},
+	}, false, "/")
+
+	body := `{"username":"` + strings.Repeat("a", 51) + `","password":"StrongPass123!"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	h.Login(rec, req)
+
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	var resp dto.ErrorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Equal(t, "VALIDATION_ERROR", resp.Error.Code)
+}
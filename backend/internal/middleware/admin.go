@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/handler/respond"
+	"github.com/sanches/finance-tracker-cc/backend/internal/store"
+)
+
+type adminStore interface {
+	GetUserByID(ctx context.Context, id uuid.UUID) (store.User, error)
+}
+
+// Admin restricts routes to instance administrators. It runs after
+// Authenticate and looks the user up on every request, so promoting,
+// demoting or disabling an admin takes effect without a new token.
+type Admin struct {
+	queries adminStore
+}
+
+func NewAdmin(queries *store.Queries) *Admin {
+	return &Admin{queries: queries}
+}
+
+func (a *Admin) RequireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID := UserID(r.Context())
+		user, err := a.queries.GetUserByID(r.Context(), userID)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			slog.Error("failed to load user for admin check", "error", err, "user_id", userID)
+			respond.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to check admin privileges")
+			return
+		}
+		if err != nil || !user.IsAdmin || user.DisabledAt.Valid {
+			respond.Error(w, http.StatusForbidden, "FORBIDDEN", "admin privileges required")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/store"
+)
+
+type mockAdminStore struct {
+	user store.User
+	err  error
+}
+
+func (m *mockAdminStore) GetUserByID(ctx context.Context, id uuid.UUID) (store.User, error) {
+	return m.user, m.err
+}
+
+func TestRequireAdmin(t *testing.T) {
+	disabled := pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	tests := []struct {
+		name string
+		user store.User
+		err  error
+		want int
+	}{
+		{"admin", store.User{IsAdmin: true}, nil, http.StatusOK},
+		{"regular user", store.User{}, nil, http.StatusForbidden},
+		{"disabled admin", store.User{IsAdmin: true, DisabledAt: disabled}, nil, http.StatusForbidden},
+		{"deleted user", store.User{}, pgx.ErrNoRows, http.StatusForbidden},
+		{"store error", store.User{}, errors.New("connection refused"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			admin := &Admin{queries: &mockAdminStore{user: tt.user, err: tt.err}}
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req = req.WithContext(context.WithValue(req.Context(), UserIDKey, uuid.New()))
+			rec := httptest.NewRecorder()
+
+			admin.RequireAdmin(next).ServeHTTP(rec, req)
+
+			require.Equal(t, tt.want, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/store"
+)
+
+type mockAuditStore struct {
+	entries []store.CreateAuditEntryParams
+}
+
+func (m *mockAuditStore) CreateAuditEntry(ctx context.Context, arg store.CreateAuditEntryParams) error {
+	m.entries = append(m.entries, arg)
+	return nil
+}
+
+func serveAudited(t *testing.T, mw func(http.Handler) http.Handler, pattern, target string, status int, actorID uuid.UUID) {
+	t.Helper()
+	r := chi.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			next.ServeHTTP(w, req.WithContext(context.WithValue(req.Context(), UserIDKey, actorID)))
+		})
+	})
+	r.With(mw).Delete(pattern, func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(status)
+	})
+
+	req := httptest.NewRequest(http.MethodDelete, target, nil)
+	req.RemoteAddr = "198.51.100.4:40000"
+	req.Header.Set("User-Agent", "test-agent")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	require.Equal(t, status, rec.Code)
+}
+
+func TestAuditRecord(t *testing.T) {
+	userID := uuid.New()
+	accountID := uuid.New()
+	m := &mockAuditStore{}
+	audit := &Audit{queries: m}
+
+	serveAudited(t, audit.Record("account.delete"), "/accounts/{id}", "/accounts/"+accountID.String()+"?force=true", http.StatusNoContent, userID)
+
+	require.Len(t, m.entries, 1)
+	e := m.entries[0]
+	require.Equal(t, userID, e.UserID)
+	require.Equal(t, userID, e.ActorID)
+	require.Equal(t, "account.delete", e.Action)
+	require.Equal(t, "198.51.100.4", e.Ip)
+	require.Equal(t, "test-agent", e.UserAgent)
+	var meta map[string]string
+	require.NoError(t, json.Unmarshal(e.Metadata, &meta))
+	require.Equal(t, map[string]string{"id": accountID.String(), "force": "true"}, meta)
+}
+
+func TestAuditRecord_SkipsFailedRequests(t *testing.T) {
+	m := &mockAuditStore{}
+	audit := &Audit{queries: m}
+
+	serveAudited(t, audit.Record("account.delete"), "/accounts/{id}", "/accounts/"+uuid.New().String(), http.StatusNotFound, uuid.New())
+
+	require.Empty(t, m.entries)
+}
+
+func TestAuditRecordOn(t *testing.T) {
+	adminID := uuid.New()
+	userID := uuid.New()
+	m := &mockAuditStore{}
+	audit := &Audit{queries: m}
+
+	serveAudited(t, audit.RecordOn("user.disable", "id"), "/users/{id}", "/users/"+userID.String(), http.StatusNoContent, adminID)
+
+	require.Len(t, m.entries, 1)
+	require.Equal(t, userID, m.entries[0].UserID)
+	require.Equal(t, adminID, m.entries[0].ActorID)
+}
+
+func TestAuditRecord_NamedBySetAuditUser(t *testing.T) {
+	userID := uuid.New()
+	m := &mockAuditStore{}
+	audit := &Audit{queries: m}
+
+	r := chi.NewRouter()
+	r.With(audit.Record("auth.refresh")).Post("/auth/refresh", func(w http.ResponseWriter, req *http.Request) {
+		SetAuditUser(req, userID)
+		w.WriteHeader(http.StatusOK)
+	})
+	r.With(audit.Record("auth.refresh")).Post("/auth/anonymous", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	for _, target := range []string{"/auth/refresh", "/auth/anonymous"} {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, target, nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+	}
+
+	require.Len(t, m.entries, 1, "a request without a user is not recorded")
+	require.Equal(t, userID, m.entries[0].UserID)
+	require.Equal(t, userID, m.entries[0].ActorID)
+}
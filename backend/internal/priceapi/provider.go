@@ -0,0 +1,61 @@
+package priceapi
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Quote is the latest closing price of a security, in the currency it
+// trades in.
+type Quote struct {
+	Symbol string
+	Date   string // YYYY-MM-DD
+	Price  string // decimal string, never float
+}
+
+// Fetcher retrieves the latest closing prices for symbols. Symbols the
+// source does not know are left out of the result instead of failing the
+// whole request.
+type Fetcher interface {
+	FetchPrices(ctx context.Context, symbols []string) ([]Quote, error)
+}
+
+// Provider is a single upstream price source.
+type Provider interface {
+	Fetcher
+	Name() string
+}
+
+// NewProvider returns the provider registered under name.
+func NewProvider(name string) (Provider, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case ProviderStooq:
+		return NewStooq(), nil
+	default:
+		return nil, fmt.Errorf("unknown price provider %q", name)
+	}
+}
+
+// httpGet performs a GET request and returns at most maxSize bytes of a 200
+// response body.
+func httpGet(ctx context.Context, client *http.Client, url string, maxSize int64) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d from %s", resp.StatusCode, url)
+	}
+
+	return io.ReadAll(io.LimitReader(resp.Body, maxSize))
+}
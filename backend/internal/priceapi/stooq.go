@@ -0,0 +1,101 @@
+package priceapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ProviderStooq is stooq.com's free quote service. Symbols use its
+// conventions, e.g. "aapl.us" or "vwce.de".
+const ProviderStooq = "stooq"
+
+// stooqURL returns symbol, date and close for '+'-separated symbols as CSV.
+const stooqURL = "https://stooq.com/q/l/?s=%s&f=sd2c&h&e=csv"
+
+// stooqBatch keeps request URLs short.
+const stooqBatch = 20
+
+type Stooq struct {
+	httpClient *http.Client
+	url        string
+}
+
+func NewStooq() *Stooq {
+	return &Stooq{
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		url:        stooqURL,
+	}
+}
+
+func (s *Stooq) Name() string {
+	return ProviderStooq
+}
+
+func (s *Stooq) FetchPrices(ctx context.Context, symbols []string) ([]Quote, error) {
+	var quotes []Quote
+	for start := 0; start < len(symbols); start += stooqBatch {
+		batch := symbols[start:min(start+stooqBatch, len(symbols))]
+		escaped := make([]string, len(batch))
+		for i, sym := range batch {
+			escaped[i] = url.QueryEscape(strings.ToLower(sym))
+		}
+
+		const maxResponseSize = 1024 * 1024
+		body, err := httpGet(ctx, s.httpClient, fmt.Sprintf(s.url, strings.Join(escaped, "+")), maxResponseSize)
+		if err != nil {
+			return nil, fmt.Errorf("stooq: %w", err)
+		}
+		parsed, err := parseStooq(body, batch)
+		if err != nil {
+			return nil, fmt.Errorf("stooq: %w", err)
+		}
+		quotes = append(quotes, parsed...)
+	}
+	return quotes, nil
+}
+
+// parseStooq reads the Symbol,Date,Close CSV. Unknown symbols come back with
+// "N/D" fields and are skipped; the rest are reported under the symbol as
+// requested.
+func parseStooq(body []byte, requested []string) ([]Quote, error) {
+	bySymbol := make(map[string]string, len(requested))
+	for _, sym := range requested {
+		bySymbol[strings.ToUpper(sym)] = sym
+	}
+
+	records, err := csv.NewReader(bytes.NewReader(body)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	if len(records) == 0 || len(records[0]) < 3 || !strings.EqualFold(records[0][0], "symbol") {
+		return nil, fmt.Errorf("unexpected response header")
+	}
+
+	var quotes []Quote
+	for _, rec := range records[1:] {
+		if len(rec) < 3 {
+			continue
+		}
+		sym, ok := bySymbol[strings.ToUpper(rec[0])]
+		if !ok {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", rec[1]); err != nil {
+			continue
+		}
+		price, err := decimal.NewFromString(rec[2])
+		if err != nil || !price.IsPositive() {
+			continue
+		}
+		quotes = append(quotes, Quote{Symbol: sym, Date: rec[1], Price: price.String()})
+	}
+	return quotes, nil
+}
@@ -0,0 +1,72 @@
+package priceapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newStooqServer(t *testing.T, body string, seen *[]string) *Stooq {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*seen = append(*seen, r.URL.Query().Get("s"))
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return &Stooq{
+		httpClient: srv.Client(),
+		url:        srv.URL + "/q/l/?s=%s&f=sd2c&h&e=csv",
+	}
+}
+
+func TestStooqFetchPrices(t *testing.T) {
+	const body = "Symbol,Date,Close\r\n" +
+		"AAPL.US,2026-03-06,231.4500\r\n" +
+		"VWCE.DE,2026-03-06,128.12\r\n" +
+		"NOPE.US,N/D,N/D\r\n"
+
+	var seen []string
+	s := newStooqServer(t, body, &seen)
+
+	quotes, err := s.FetchPrices(context.Background(), []string{"aapl.us", "VWCE.DE", "nope.us"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"aapl.us vwce.de nope.us"}, seen)
+	require.Equal(t, []Quote{
+		{Symbol: "aapl.us", Date: "2026-03-06", Price: "231.45"},
+		{Symbol: "VWCE.DE", Date: "2026-03-06", Price: "128.12"},
+	}, quotes)
+}
+
+func TestStooqFetchPrices_Batches(t *testing.T) {
+	var seen []string
+	s := newStooqServer(t, "Symbol,Date,Close\r\n", &seen)
+
+	symbols := make([]string, stooqBatch+1)
+	for i := range symbols {
+		symbols[i] = "x.us"
+	}
+	quotes, err := s.FetchPrices(context.Background(), symbols)
+	require.NoError(t, err)
+	require.Empty(t, quotes)
+	require.Len(t, seen, 2)
+}
+
+func TestStooqFetchPrices_BadResponse(t *testing.T) {
+	var seen []string
+	s := newStooqServer(t, "<html>rate limited</html>", &seen)
+
+	_, err := s.FetchPrices(context.Background(), []string{"aapl.us"})
+	require.Error(t, err)
+}
+
+func TestNewProvider(t *testing.T) {
+	p, err := NewProvider(" Stooq ")
+	require.NoError(t, err)
+	require.Equal(t, ProviderStooq, p.Name())
+
+	_, err = NewProvider("yahoo")
+	require.Error(t, err)
+}
@@ -0,0 +1,117 @@
+package rateapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// ProviderCBR is the Central Bank of Russia's official daily rates against
+// the ruble.
+const ProviderCBR = "cbr"
+
+const cbrDailyURL = "https://www.cbr.ru/scripts/XML_daily.asp"
+
+// CBR fetches the RUB-based official rates and converts them to the requested
+// base. For dates without a publication the bank returns the last one before.
+type CBR struct {
+	httpClient *http.Client
+	dailyURL   string
+}
+
+func NewCBR() *CBR {
+	return &CBR{
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		dailyURL:   cbrDailyURL,
+	}
+}
+
+func (c *CBR) Name() string {
+	return ProviderCBR
+}
+
+func (c *CBR) FetchRates(ctx context.Context, baseCurrency string, date time.Time) (*RatesResponse, error) {
+	url := c.dailyURL
+	if !date.IsZero() {
+		url += "?date_req=" + date.Format("02/01/2006")
+	}
+
+	const maxResponseSize = 1024 * 1024 // 1 MB
+	body, err := httpGet(ctx, c.httpClient, url, maxResponseSize)
+	if err != nil {
+		return nil, fmt.Errorf("cbr: %w", err)
+	}
+
+	day, rates, err := parseCBR(body)
+	if err != nil {
+		return nil, fmt.Errorf("cbr: %w", err)
+	}
+
+	converted, err := crossRates("RUB", rates, baseCurrency)
+	if err != nil {
+		return nil, fmt.Errorf("cbr: %w", err)
+	}
+	return &RatesResponse{Date: day, Rates: converted, Source: ProviderCBR}, nil
+}
+
+type cbrValCurs struct {
+	Date    string `xml:"Date,attr"`
+	Valutes []struct {
+		CharCode string `xml:"CharCode"`
+		Nominal  string `xml:"Nominal"`
+		Value    string `xml:"Value"`
+	} `xml:"Valute"`
+}
+
+// parseCBR returns the publication date (YYYY-MM-DD) and RUB->currency rates.
+// The feed quotes "Value rubles per Nominal units" with a decimal comma, and
+// is encoded in windows-1251.
+func parseCBR(body []byte) (string, map[string]decimal.Decimal, error) {
+	dec := xml.NewDecoder(bytes.NewReader(body))
+	dec.CharsetReader = func(label string, input io.Reader) (io.Reader, error) {
+		if strings.EqualFold(label, "windows-1251") {
+			return charmap.Windows1251.NewDecoder().Reader(input), nil
+		}
+		return nil, fmt.Errorf("unsupported charset %q", label)
+	}
+
+	var vc cbrValCurs
+	if err := dec.Decode(&vc); err != nil {
+		return "", nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	day, err := time.Parse("02.01.2006", vc.Date)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid date %q: %w", vc.Date, err)
+	}
+
+	rates := make(map[string]decimal.Decimal, len(vc.Valutes))
+	for _, v := range vc.Valutes {
+		code := strings.ToUpper(strings.TrimSpace(v.CharCode))
+		nominal, err := decimal.NewFromString(strings.TrimSpace(v.Nominal))
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid nominal %q for %s: %w", v.Nominal, code, err)
+		}
+		value, err := decimal.NewFromString(strings.ReplaceAll(strings.TrimSpace(v.Value), ",", "."))
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid value %q for %s: %w", v.Value, code, err)
+		}
+		if value.IsZero() {
+			continue
+		}
+		// Keep extra digits: crossRates divides again and rounds to rateScale.
+		rates[code] = nominal.DivRound(value, 2*rateScale)
+	}
+	if len(rates) == 0 {
+		return "", nil, fmt.Errorf("no rates in response")
+	}
+	return day.Format("2006-01-02"), rates, nil
+}
@@ -0,0 +1,67 @@
+package rateapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/charmap"
+)
+
+const cbrDaily = `<?xml version="1.0" encoding="windows-1251"?>
+<ValCurs Date="14.03.2025" name="Foreign Currency Market">
+	<Valute ID="R01060"><NumCode>051</NumCode><CharCode>AMD</CharCode><Nominal>100</Nominal><Name>Армянских драмов</Name><Value>22,1234</Value></Valute>
+	<Valute ID="R01235"><NumCode>840</NumCode><CharCode>USD</CharCode><Nominal>1</Nominal><Name>Доллар США</Name><Value>87,5000</Value></Valute>
+</ValCurs>`
+
+func newCBRServer(t *testing.T, seen *[]string) *CBR {
+	t.Helper()
+	body, err := charmap.Windows1251.NewEncoder().String(cbrDaily)
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*seen = append(*seen, r.URL.RequestURI())
+		w.Header().Set("Content-Type", "application/xml; charset=windows-1251")
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return &CBR{httpClient: srv.Client(), dailyURL: srv.URL + "/XML_daily.asp"}
+}
+
+func TestCBRFetchRates(t *testing.T) {
+	t.Run("RUB base inverts value per nominal", func(t *testing.T) {
+		var seen []string
+		c := newCBRServer(t, &seen)
+
+		resp, err := c.FetchRates(context.Background(), "RUB", time.Time{})
+		require.NoError(t, err)
+		require.Equal(t, []string{"/XML_daily.asp"}, seen)
+		require.Equal(t, "2025-03-14", resp.Date)
+		require.Equal(t, ProviderCBR, resp.Source)
+		require.Equal(t, "1", resp.Rates["rub"])
+		require.Equal(t, "0.011428571429", resp.Rates["usd"]) // 1 / 87.5
+		require.Equal(t, "4.520100888652", resp.Rates["amd"]) // 100 / 22.1234
+	})
+
+	t.Run("cross rate for non-RUB base", func(t *testing.T) {
+		var seen []string
+		c := newCBRServer(t, &seen)
+
+		resp, err := c.FetchRates(context.Background(), "USD", time.Time{})
+		require.NoError(t, err)
+		require.Equal(t, "87.5", resp.Rates["rub"])
+		require.Equal(t, "395.508827757036", resp.Rates["amd"]) // 87.5 * 100 / 22.1234
+	})
+
+	t.Run("dated request passes date_req", func(t *testing.T) {
+		var seen []string
+		c := newCBRServer(t, &seen)
+
+		_, err := c.FetchRates(context.Background(), "RUB", time.Date(2025, time.March, 14, 0, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+		require.Equal(t, []string{"/XML_daily.asp?date_req=14/03/2025"}, seen)
+	})
+}
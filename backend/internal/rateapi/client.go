@@ -10,6 +10,10 @@ import (
 	"time"
 )
 
+// ProviderFawazahmed0 is the free CDN-hosted currency API at
+// github.com/fawazahmed0/exchange-api.
+const ProviderFawazahmed0 = "fawazahmed0"
+
 const (
 	primaryURL  = "https://cdn.jsdelivr.net/npm/@fawazahmed0/currency-api@%s/v1/currencies/%s.min.json"
 	fallbackURL = "https://%s.currency-api.pages.dev/v1/currencies/%s.min.json"
@@ -18,44 +22,82 @@ const (
 type RatesResponse struct {
 	Date  string
 	Rates map[string]string // decimal strings, never float
+	// Source names the provider the rates came from. Sources overrides it for
+	// individual targets when a Registry merged rates from several providers.
+	Source  string
+	Sources map[string]string
+}
+
+// SourceFor returns the name of the provider that supplied the rate for target.
+func (r *RatesResponse) SourceFor(target string) string {
+	if src, ok := r.Sources[strings.ToLower(target)]; ok {
+		return src
+	}
+	return r.Source
 }
 
+// Fetcher retrieves rates from baseCurrency to every currency the source
+// knows about. A zero date requests the latest published rates; otherwise the
+// rates published for that calendar day (UTC) are returned.
 type Fetcher interface {
-	FetchRates(ctx context.Context, baseCurrency string) (*RatesResponse, error)
+	FetchRates(ctx context.Context, baseCurrency string, date time.Time) (*RatesResponse, error)
 }
 
 type Client struct {
-	httpClient *http.Client
+	httpClient  *http.Client
+	primaryURL  string
+	fallbackURL string
 }
 
 func NewClient() *Client {
 	return &Client{
-		httpClient: &http.Client{Timeout: 30 * time.Second},
+		httpClient:  &http.Client{Timeout: 30 * time.Second},
+		primaryURL:  primaryURL,
+		fallbackURL: fallbackURL,
 	}
 }
 
-func (c *Client) FetchRates(ctx context.Context, baseCurrency string) (*RatesResponse, error) {
+func (c *Client) Name() string {
+	return ProviderFawazahmed0
+}
+
+func (c *Client) FetchRates(ctx context.Context, baseCurrency string, date time.Time) (*RatesResponse, error) {
 	base := strings.ToLower(baseCurrency)
-	date := "latest"
+	version := "latest"
+	if !date.IsZero() {
+		version = date.UTC().Format("2006-01-02")
+	}
 
-	body, err := c.fetch(ctx, fmt.Sprintf(primaryURL, date, base))
+	body, err := c.fetch(ctx, fmt.Sprintf(c.primaryURL, version, base))
 	if err != nil {
-		body, err = c.fetch(ctx, fmt.Sprintf(fallbackURL, date, base))
+		body, err = c.fetch(ctx, fmt.Sprintf(c.fallbackURL, version, base))
 		if err != nil {
 			return nil, fmt.Errorf("both primary and fallback failed for %s: %w", baseCurrency, err)
 		}
 	}
 
-	return parseResponse(body, base)
+	resp, err := parseResponse(body, base)
+	if err != nil {
+		return nil, err
+	}
+	resp.Source = ProviderFawazahmed0
+	return resp, nil
 }
 
 func (c *Client) fetch(ctx context.Context, url string) ([]byte, error) {
+	const maxResponseSize = 5 * 1024 * 1024 // 5 MB
+	return httpGet(ctx, c.httpClient, url, maxResponseSize)
+}
+
+// httpGet performs a GET request and returns at most maxSize bytes of a 200
+// response body.
+func httpGet(ctx context.Context, client *http.Client, url string, maxSize int64) ([]byte, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -65,8 +107,7 @@ func (c *Client) fetch(ctx context.Context, url string) ([]byte, error) {
 		return nil, fmt.Errorf("unexpected status %d from %s", resp.StatusCode, url)
 	}
 
-	const maxResponseSize = 5 * 1024 * 1024 // 5 MB
-	return io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	return io.ReadAll(io.LimitReader(resp.Body, maxSize))
 }
 
 func parseResponse(body []byte, base string) (*RatesResponse, error) {
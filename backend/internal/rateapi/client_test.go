@@ -1,7 +1,11 @@
 package rateapi
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -73,3 +77,47 @@ func TestParseResponse(t *testing.T) {
 		require.Equal(t, "1", resp.Rates["a"])
 	})
 }
+
+func TestClientFetchRates(t *testing.T) {
+	newServer := func(t *testing.T, status int, seen *[]string) *httptest.Server {
+		t.Helper()
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			*seen = append(*seen, r.URL.Path)
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(rubResponse))
+		}))
+		t.Cleanup(srv.Close)
+		return srv
+	}
+
+	t.Run("zero date requests latest", func(t *testing.T) {
+		var seen []string
+		srv := newServer(t, http.StatusOK, &seen)
+		c := &Client{httpClient: srv.Client(), primaryURL: srv.URL + "/%s/%s.json", fallbackURL: srv.URL + "/fallback/%s/%s.json"}
+
+		resp, err := c.FetchRates(context.Background(), "RUB", time.Time{})
+		require.NoError(t, err)
+		require.Equal(t, "4.70255397", resp.Rates["amd"])
+		require.Equal(t, []string{"/latest/rub.json"}, seen)
+	})
+
+	t.Run("dated request uses the date as version", func(t *testing.T) {
+		var seen []string
+		srv := newServer(t, http.StatusOK, &seen)
+		c := &Client{httpClient: srv.Client(), primaryURL: srv.URL + "/%s/%s.json", fallbackURL: srv.URL + "/fallback/%s/%s.json"}
+
+		_, err := c.FetchRates(context.Background(), "RUB", time.Date(2025, time.March, 14, 0, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+		require.Equal(t, []string{"/2025-03-14/rub.json"}, seen)
+	})
+
+	t.Run("falls back when primary fails", func(t *testing.T) {
+		var seen []string
+		srv := newServer(t, http.StatusNotFound, &seen)
+		c := &Client{httpClient: srv.Client(), primaryURL: srv.URL + "/%s/%s.json", fallbackURL: srv.URL + "/fallback/%s/%s.json"}
+
+		_, err := c.FetchRates(context.Background(), "RUB", time.Date(2025, time.March, 14, 0, 0, 0, 0, time.UTC))
+		require.Error(t, err)
+		require.Equal(t, []string{"/2025-03-14/rub.json", "/fallback/2025-03-14/rub.json"}, seen)
+	})
+}
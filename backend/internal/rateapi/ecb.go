@@ -0,0 +1,170 @@
+package rateapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ProviderECB is the European Central Bank's euro foreign exchange reference
+// rates, published on TARGET working days around 16:00 CET.
+const ProviderECB = "ecb"
+
+const (
+	ecbDailyURL   = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml"
+	ecbHist90dURL = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-hist-90d.xml"
+	ecbHistURL    = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-hist.xml"
+)
+
+// ecbHistoryTTL is how long a downloaded history file is reused. A backfill
+// asks for one date at a time; without the cache every request would fetch
+// and parse the same multi-megabyte file.
+const ecbHistoryTTL = time.Hour
+
+// ECB fetches the EUR-based reference rates and converts them to the requested
+// base. Weekends and holidays have no publication, so a dated request returns
+// the most recent publication on or before that date.
+type ECB struct {
+	httpClient *http.Client
+	dailyURL   string
+	hist90dURL string
+	histURL    string
+	now        func() time.Time
+
+	mu      sync.Mutex
+	history map[string]ecbHistory // by URL
+}
+
+type ecbHistory struct {
+	env       *ecbEnvelope
+	fetchedAt time.Time
+}
+
+func NewECB() *ECB {
+	return &ECB{
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		dailyURL:   ecbDailyURL,
+		hist90dURL: ecbHist90dURL,
+		histURL:    ecbHistURL,
+		now:        time.Now,
+	}
+}
+
+func (e *ECB) Name() string {
+	return ProviderECB
+}
+
+func (e *ECB) FetchRates(ctx context.Context, baseCurrency string, date time.Time) (*RatesResponse, error) {
+	url := e.dailyURL
+	if !date.IsZero() {
+		// The 90-day file is ~100x smaller than the full history.
+		url = e.hist90dURL
+		if e.now().Sub(date) > 85*24*time.Hour {
+			url = e.histURL
+		}
+	}
+
+	env, err := e.envelope(ctx, url, !date.IsZero())
+	if err != nil {
+		return nil, fmt.Errorf("ecb: %w", err)
+	}
+
+	day, rates, err := pickECBDay(env, date)
+	if err != nil {
+		return nil, fmt.Errorf("ecb: %w", err)
+	}
+
+	converted, err := crossRates("EUR", rates, baseCurrency)
+	if err != nil {
+		return nil, fmt.Errorf("ecb: %w", err)
+	}
+	return &RatesResponse{Date: day, Rates: converted, Source: ProviderECB}, nil
+}
+
+type ecbEnvelope struct {
+	Days []struct {
+		Time  string `xml:"time,attr"`
+		Rates []struct {
+			Currency string `xml:"currency,attr"`
+			Rate     string `xml:"rate,attr"`
+		} `xml:"Cube"`
+	} `xml:"Cube>Cube"`
+}
+
+// envelope downloads and parses url. History files are kept for
+// ecbHistoryTTL; the daily file is always fetched fresh.
+func (e *ECB) envelope(ctx context.Context, url string, history bool) (*ecbEnvelope, error) {
+	if history {
+		e.mu.Lock()
+		cached, ok := e.history[url]
+		e.mu.Unlock()
+		if ok && e.now().Sub(cached.fetchedAt) < ecbHistoryTTL {
+			return cached.env, nil
+		}
+	}
+
+	const maxResponseSize = 16 * 1024 * 1024 // full history is several MB
+	body, err := httpGet(ctx, e.httpClient, url, maxResponseSize)
+	if err != nil {
+		return nil, err
+	}
+	env := &ecbEnvelope{}
+	if err := xml.NewDecoder(bytes.NewReader(body)).Decode(env); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if history {
+		e.mu.Lock()
+		if e.history == nil {
+			e.history = make(map[string]ecbHistory)
+		}
+		e.history[url] = ecbHistory{env: env, fetchedAt: e.now()}
+		e.mu.Unlock()
+	}
+	return env, nil
+}
+
+// pickECBDay returns the publication date and EUR->currency rates of the
+// latest day in env that is on or before date (or the latest day for a zero
+// date).
+func pickECBDay(env *ecbEnvelope, date time.Time) (string, map[string]decimal.Decimal, error) {
+	want := ""
+	if !date.IsZero() {
+		want = date.Format("2006-01-02")
+	}
+
+	// Days are listed newest first, but don't rely on it.
+	best := -1
+	for i, d := range env.Days {
+		if want != "" && d.Time > want {
+			continue
+		}
+		if best == -1 || d.Time > env.Days[best].Time {
+			best = i
+		}
+	}
+	if best == -1 {
+		if want != "" {
+			return "", nil, fmt.Errorf("no rates published on or before %s", want)
+		}
+		return "", nil, fmt.Errorf("no rates in response")
+	}
+
+	day := env.Days[best]
+	rates := make(map[string]decimal.Decimal, len(day.Rates))
+	for _, r := range day.Rates {
+		v, err := decimal.NewFromString(strings.TrimSpace(r.Rate))
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid rate %q for %s: %w", r.Rate, r.Currency, err)
+		}
+		rates[strings.ToUpper(r.Currency)] = v
+	}
+	return day.Time, rates, nil
+}
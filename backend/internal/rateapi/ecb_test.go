@@ -0,0 +1,136 @@
+package rateapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const ecbDaily = `<?xml version="1.0" encoding="UTF-8"?>
+<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01" xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref">
+	<gesmes:subject>Reference rates</gesmes:subject>
+	<gesmes:Sender><gesmes:name>European Central Bank</gesmes:name></gesmes:Sender>
+	<Cube>
+		<Cube time="2026-03-09">
+			<Cube currency="USD" rate="1.0800"/>
+			<Cube currency="GBP" rate="0.8400"/>
+		</Cube>
+	</Cube>
+</gesmes:Envelope>`
+
+const ecbHist = `<?xml version="1.0" encoding="UTF-8"?>
+<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01" xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref">
+	<Cube>
+		<Cube time="2025-03-17"><Cube currency="USD" rate="1.0900"/></Cube>
+		<Cube time="2025-03-14"><Cube currency="USD" rate="1.0850"/></Cube>
+		<Cube time="2025-03-13"><Cube currency="USD" rate="1.0820"/></Cube>
+	</Cube>
+</gesmes:Envelope>`
+
+func newECBServer(t *testing.T, seen *[]string) *ECB {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*seen = append(*seen, r.URL.Path)
+		switch r.URL.Path {
+		case "/daily.xml":
+			_, _ = w.Write([]byte(ecbDaily))
+		case "/hist-90d.xml", "/hist.xml":
+			_, _ = w.Write([]byte(ecbHist))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return &ECB{
+		httpClient: srv.Client(),
+		dailyURL:   srv.URL + "/daily.xml",
+		hist90dURL: srv.URL + "/hist-90d.xml",
+		histURL:    srv.URL + "/hist.xml",
+		now:        func() time.Time { return time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC) },
+	}
+}
+
+func TestECBFetchRates(t *testing.T) {
+	t.Run("latest rates for EUR base", func(t *testing.T) {
+		var seen []string
+		e := newECBServer(t, &seen)
+
+		resp, err := e.FetchRates(context.Background(), "EUR", time.Time{})
+		require.NoError(t, err)
+		require.Equal(t, []string{"/daily.xml"}, seen)
+		require.Equal(t, "2026-03-09", resp.Date)
+		require.Equal(t, ProviderECB, resp.Source)
+		require.Equal(t, "1.08", resp.Rates["usd"])
+		require.Equal(t, "1", resp.Rates["eur"])
+	})
+
+	t.Run("converts to a non-EUR base", func(t *testing.T) {
+		var seen []string
+		e := newECBServer(t, &seen)
+
+		resp, err := e.FetchRates(context.Background(), "USD", time.Time{})
+		require.NoError(t, err)
+		require.Equal(t, "1", resp.Rates["usd"])
+		require.Equal(t, "0.925925925926", resp.Rates["eur"]) // 1 / 1.08
+		require.Equal(t, "0.777777777778", resp.Rates["gbp"]) // 0.84 / 1.08
+	})
+
+	t.Run("dated request picks latest publication on or before the date", func(t *testing.T) {
+		var seen []string
+		e := newECBServer(t, &seen)
+
+		// 2025-03-15 is a Saturday: Friday's rates apply.
+		resp, err := e.FetchRates(context.Background(), "EUR", time.Date(2025, time.March, 15, 0, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+		require.Equal(t, []string{"/hist-90d.xml"}, seen)
+		require.Equal(t, "2025-03-14", resp.Date)
+		require.Equal(t, "1.085", resp.Rates["usd"])
+	})
+
+	t.Run("history file is reused across dated requests", func(t *testing.T) {
+		var seen []string
+		e := newECBServer(t, &seen)
+
+		for _, day := range []int{13, 14, 17} {
+			_, err := e.FetchRates(context.Background(), "EUR", time.Date(2025, time.March, day, 0, 0, 0, 0, time.UTC))
+			require.NoError(t, err)
+		}
+		require.Equal(t, []string{"/hist-90d.xml"}, seen)
+
+		e.now = func() time.Time { return time.Date(2025, time.April, 1, 2, 0, 0, 0, time.UTC) }
+		_, err := e.FetchRates(context.Background(), "EUR", time.Date(2025, time.March, 14, 0, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+		require.Len(t, seen, 2, "downloaded again once the cache expired")
+	})
+
+	t.Run("old dates use the full history", func(t *testing.T) {
+		var seen []string
+		e := newECBServer(t, &seen)
+		e.now = func() time.Time { return time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC) }
+
+		_, err := e.FetchRates(context.Background(), "EUR", time.Date(2025, time.March, 14, 0, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+		require.Equal(t, []string{"/hist.xml"}, seen)
+	})
+
+	t.Run("date before first publication returns error", func(t *testing.T) {
+		var seen []string
+		e := newECBServer(t, &seen)
+
+		_, err := e.FetchRates(context.Background(), "EUR", time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC))
+		require.Error(t, err)
+	})
+
+	t.Run("unknown base returns error", func(t *testing.T) {
+		var seen []string
+		e := newECBServer(t, &seen)
+
+		_, err := e.FetchRates(context.Background(), "AMD", time.Time{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "no rate for base currency")
+	})
+}
@@ -0,0 +1,222 @@
+package rateapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// rateScale is the number of decimal places kept when a provider has to
+// divide to produce a rate. The database stores 8; the extra digits keep
+// cross rates from compounding rounding errors.
+const rateScale = 12
+
+// Provider is a single upstream rate source.
+type Provider interface {
+	Fetcher
+	Name() string
+}
+
+// NewProvider returns the provider registered under name.
+func NewProvider(name string) (Provider, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case ProviderFawazahmed0:
+		return NewClient(), nil
+	case ProviderECB:
+		return NewECB(), nil
+	case ProviderCBR:
+		return NewCBR(), nil
+	default:
+		return nil, fmt.Errorf("unknown exchange rate provider %q", name)
+	}
+}
+
+// PairPreference routes a currency pair to a specific provider. From or To
+// may be "*" to match any currency.
+type PairPreference struct {
+	From     string
+	To       string
+	Provider string
+}
+
+func (p PairPreference) matchesBase(base string) bool {
+	return p.From == "*" || p.From == base
+}
+
+func (p PairPreference) matchesTarget(target string) bool {
+	return p.To == "*" || p.To == target
+}
+
+// ParsePairPreferences parses a comma-separated list of FROM/TO=provider
+// entries, e.g. "RUB/AMD=cbr,*/RUB=cbr,EUR/*=ecb".
+func ParsePairPreferences(raw string) ([]PairPreference, error) {
+	var prefs []PairPreference
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		pair, provider, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid pair preference %q: expected FROM/TO=provider", entry)
+		}
+		from, to, ok := strings.Cut(pair, "/")
+		if !ok {
+			return nil, fmt.Errorf("invalid pair preference %q: expected FROM/TO=provider", entry)
+		}
+		prefs = append(prefs, PairPreference{
+			From:     strings.ToUpper(strings.TrimSpace(from)),
+			To:       strings.ToUpper(strings.TrimSpace(to)),
+			Provider: strings.ToLower(strings.TrimSpace(provider)),
+		})
+	}
+	return prefs, nil
+}
+
+// Registry is a Fetcher that combines several providers. Every provider in
+// the fallback chain is asked, and each rate comes from the first one that
+// quotes the currency, so later providers fill in what earlier ones lack.
+// Pairs with a preference are then overridden with the preferred provider's
+// rate when it has one. If the preferred provider fails, the chain's rate is
+// kept.
+type Registry struct {
+	chain     []Provider
+	providers map[string]Provider
+	prefs     []PairPreference
+}
+
+// NewRegistry builds a registry from provider names in fallback order.
+// Providers referenced only by a preference are instantiated as well.
+func NewRegistry(chain []string, prefs []PairPreference) (*Registry, error) {
+	r := &Registry{providers: make(map[string]Provider), prefs: prefs}
+	for _, name := range chain {
+		p, err := r.provider(name)
+		if err != nil {
+			return nil, err
+		}
+		r.chain = append(r.chain, p)
+	}
+	if len(r.chain) == 0 {
+		return nil, errors.New("at least one exchange rate provider is required")
+	}
+	for _, pref := range prefs {
+		if _, err := r.provider(pref.Provider); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+func (r *Registry) provider(name string) (Provider, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if p, ok := r.providers[name]; ok {
+		return p, nil
+	}
+	p, err := NewProvider(name)
+	if err != nil {
+		return nil, err
+	}
+	r.providers[name] = p
+	return p, nil
+}
+
+func (r *Registry) FetchRates(ctx context.Context, baseCurrency string, date time.Time) (*RatesResponse, error) {
+	base := strings.ToUpper(baseCurrency)
+
+	resp := &RatesResponse{Rates: map[string]string{}}
+	var errs []error
+	for _, p := range r.chain {
+		res, err := p.FetchRates(ctx, base, date)
+		if err != nil {
+			slog.Warn("exchange rate provider failed, trying next", "provider", p.Name(), "base", base, "error", err)
+			errs = append(errs, fmt.Errorf("%s: %w", p.Name(), err))
+			continue
+		}
+		if resp.Source == "" {
+			resp.Date = res.Date
+			resp.Source = res.Source
+		}
+		for target, rate := range res.Rates {
+			key := strings.ToLower(target)
+			if _, ok := resp.Rates[key]; ok {
+				continue
+			}
+			resp.Rates[key] = rate
+			if res.Source != resp.Source {
+				if resp.Sources == nil {
+					resp.Sources = make(map[string]string)
+				}
+				resp.Sources[key] = res.Source
+			}
+		}
+	}
+
+	// Apply pair preferences, fetching each preferred provider at most once.
+	fetched := make(map[string]*RatesResponse)
+	for _, pref := range r.prefs {
+		if !pref.matchesBase(base) {
+			continue
+		}
+		preferred, ok := fetched[pref.Provider]
+		if !ok {
+			res, err := r.providers[pref.Provider].FetchRates(ctx, base, date)
+			if err != nil {
+				slog.Warn("preferred exchange rate provider failed, keeping fallback rates", "provider", pref.Provider, "base", base, "error", err)
+				errs = append(errs, fmt.Errorf("%s: %w", pref.Provider, err))
+			}
+			fetched[pref.Provider] = res
+			preferred = res
+		}
+		if preferred == nil {
+			continue
+		}
+		for target, rate := range preferred.Rates {
+			if !pref.matchesTarget(strings.ToUpper(target)) {
+				continue
+			}
+			key := strings.ToLower(target)
+			resp.Rates[key] = rate
+			if resp.Sources == nil {
+				resp.Sources = make(map[string]string)
+			}
+			resp.Sources[key] = preferred.Source
+		}
+		if resp.Date == "" {
+			resp.Date = preferred.Date
+		}
+	}
+
+	if len(resp.Rates) == 0 {
+		return nil, fmt.Errorf("all providers failed for %s: %w", base, errors.Join(errs...))
+	}
+	return resp, nil
+}
+
+// crossRates converts anchor->currency rates into base->currency rates, keyed
+// by lowercase currency code to match the fawazahmed0 format.
+func crossRates(anchor string, rates map[string]decimal.Decimal, base string) (map[string]string, error) {
+	anchor = strings.ToUpper(anchor)
+	base = strings.ToUpper(base)
+
+	all := make(map[string]decimal.Decimal, len(rates)+1)
+	for code, rate := range rates {
+		all[code] = rate
+	}
+	all[anchor] = decimal.NewFromInt(1)
+
+	baseRate, ok := all[base]
+	if !ok || baseRate.IsZero() {
+		return nil, fmt.Errorf("no rate for base currency %q", base)
+	}
+
+	out := make(map[string]string, len(all))
+	for code, rate := range all {
+		out[strings.ToLower(code)] = rate.DivRound(baseRate, rateScale).String()
+	}
+	return out, nil
+}
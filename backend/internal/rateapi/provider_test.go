@@ -0,0 +1,166 @@
+package rateapi
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type stubProvider struct {
+	name  string
+	calls int
+	resp  *RatesResponse
+	err   error
+}
+
+func (s *stubProvider) Name() string { return s.name }
+
+func (s *stubProvider) FetchRates(_ context.Context, _ string, _ time.Time) (*RatesResponse, error) {
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+	rates := make(map[string]string, len(s.resp.Rates))
+	for k, v := range s.resp.Rates {
+		rates[k] = v
+	}
+	return &RatesResponse{Date: s.resp.Date, Rates: rates, Source: s.name}, nil
+}
+
+func newTestRegistry(chain []*stubProvider, extra []*stubProvider, prefs []PairPreference) *Registry {
+	r := &Registry{providers: make(map[string]Provider), prefs: prefs}
+	for _, p := range chain {
+		r.chain = append(r.chain, p)
+		r.providers[p.name] = p
+	}
+	for _, p := range extra {
+		r.providers[p.name] = p
+	}
+	return r
+}
+
+func TestParsePairPreferences(t *testing.T) {
+	prefs, err := ParsePairPreferences(" rub/amd=CBR, */RUB=cbr ,,EUR/*=ecb")
+	require.NoError(t, err)
+	require.Equal(t, []PairPreference{
+		{From: "RUB", To: "AMD", Provider: "cbr"},
+		{From: "*", To: "RUB", Provider: "cbr"},
+		{From: "EUR", To: "*", Provider: "ecb"},
+	}, prefs)
+
+	prefs, err = ParsePairPreferences("")
+	require.NoError(t, err)
+	require.Empty(t, prefs)
+
+	_, err = ParsePairPreferences("RUB/AMD")
+	require.Error(t, err)
+	_, err = ParsePairPreferences("RUBAMD=cbr")
+	require.Error(t, err)
+}
+
+func TestNewRegistry(t *testing.T) {
+	_, err := NewRegistry(nil, nil)
+	require.Error(t, err)
+
+	_, err = NewRegistry([]string{"nope"}, nil)
+	require.Error(t, err)
+
+	_, err = NewRegistry([]string{"fawazahmed0"}, []PairPreference{{From: "*", To: "RUB", Provider: "nope"}})
+	require.Error(t, err)
+
+	r, err := NewRegistry([]string{"ecb", "fawazahmed0"}, []PairPreference{{From: "*", To: "RUB", Provider: "cbr"}})
+	require.NoError(t, err)
+	require.Len(t, r.chain, 2)
+	require.Len(t, r.providers, 3)
+}
+
+func TestRegistryFetchRates(t *testing.T) {
+	t.Run("falls back to next provider in chain", func(t *testing.T) {
+		first := &stubProvider{name: "a", err: errors.New("down")}
+		second := &stubProvider{name: "b", resp: &RatesResponse{Date: "2025-03-14", Rates: map[string]string{"usd": "1.08"}}}
+		r := newTestRegistry([]*stubProvider{first, second}, nil, nil)
+
+		resp, err := r.FetchRates(context.Background(), "eur", time.Time{})
+		require.NoError(t, err)
+		require.Equal(t, "b", resp.Source)
+		require.Equal(t, "b", resp.SourceFor("USD"))
+		require.Equal(t, 1, first.calls)
+		require.Equal(t, 1, second.calls)
+	})
+
+	t.Run("later providers fill in currencies earlier ones lack", func(t *testing.T) {
+		ecb := &stubProvider{name: "ecb", resp: &RatesResponse{Date: "2025-03-14", Rates: map[string]string{"usd": "1.08"}}}
+		fawaz := &stubProvider{name: "fawazahmed0", resp: &RatesResponse{Date: "2025-03-15", Rates: map[string]string{"usd": "1.09", "amd": "420.1"}}}
+		r := newTestRegistry([]*stubProvider{ecb, fawaz}, nil, nil)
+
+		resp, err := r.FetchRates(context.Background(), "EUR", time.Time{})
+		require.NoError(t, err)
+		require.Equal(t, "2025-03-14", resp.Date)
+		require.Equal(t, "1.08", resp.Rates["usd"])
+		require.Equal(t, "ecb", resp.SourceFor("USD"))
+		require.Equal(t, "420.1", resp.Rates["amd"])
+		require.Equal(t, "fawazahmed0", resp.SourceFor("AMD"))
+	})
+
+	t.Run("pair preference overrides matching targets only", func(t *testing.T) {
+		main := &stubProvider{name: "main", resp: &RatesResponse{Date: "2025-03-14", Rates: map[string]string{"amd": "4.6", "usd": "0.0114"}}}
+		cbr := &stubProvider{name: "cbr", resp: &RatesResponse{Date: "2025-03-14", Rates: map[string]string{"amd": "4.52", "usd": "0.0115"}}}
+		r := newTestRegistry([]*stubProvider{main}, []*stubProvider{cbr}, []PairPreference{
+			{From: "RUB", To: "AMD", Provider: "cbr"},
+			{From: "*", To: "GBP", Provider: "cbr"},
+		})
+
+		resp, err := r.FetchRates(context.Background(), "RUB", time.Time{})
+		require.NoError(t, err)
+		require.Equal(t, "4.52", resp.Rates["amd"])
+		require.Equal(t, "cbr", resp.SourceFor("AMD"))
+		require.Equal(t, "0.0114", resp.Rates["usd"])
+		require.Equal(t, "main", resp.SourceFor("USD"))
+		require.Equal(t, 1, cbr.calls, "preferred provider fetched once")
+	})
+
+	t.Run("preference for another base is ignored", func(t *testing.T) {
+		main := &stubProvider{name: "main", resp: &RatesResponse{Rates: map[string]string{"amd": "4.6"}}}
+		cbr := &stubProvider{name: "cbr", resp: &RatesResponse{Rates: map[string]string{"amd": "4.52"}}}
+		r := newTestRegistry([]*stubProvider{main}, []*stubProvider{cbr}, []PairPreference{{From: "RUB", To: "AMD", Provider: "cbr"}})
+
+		resp, err := r.FetchRates(context.Background(), "USD", time.Time{})
+		require.NoError(t, err)
+		require.Equal(t, "4.6", resp.Rates["amd"])
+		require.Equal(t, 0, cbr.calls)
+	})
+
+	t.Run("preferred provider failure keeps chain rate", func(t *testing.T) {
+		main := &stubProvider{name: "main", resp: &RatesResponse{Rates: map[string]string{"amd": "4.6"}}}
+		cbr := &stubProvider{name: "cbr", err: errors.New("down")}
+		r := newTestRegistry([]*stubProvider{main}, []*stubProvider{cbr}, []PairPreference{{From: "*", To: "AMD", Provider: "cbr"}})
+
+		resp, err := r.FetchRates(context.Background(), "RUB", time.Time{})
+		require.NoError(t, err)
+		require.Equal(t, "4.6", resp.Rates["amd"])
+		require.Equal(t, "main", resp.SourceFor("AMD"))
+	})
+
+	t.Run("preferred provider alone is enough when chain fails", func(t *testing.T) {
+		main := &stubProvider{name: "main", err: errors.New("down")}
+		cbr := &stubProvider{name: "cbr", resp: &RatesResponse{Date: "2025-03-14", Rates: map[string]string{"amd": "4.52"}}}
+		r := newTestRegistry([]*stubProvider{main}, []*stubProvider{cbr}, []PairPreference{{From: "*", To: "AMD", Provider: "cbr"}})
+
+		resp, err := r.FetchRates(context.Background(), "RUB", time.Time{})
+		require.NoError(t, err)
+		require.Equal(t, "2025-03-14", resp.Date)
+		require.Equal(t, "cbr", resp.SourceFor("AMD"))
+	})
+
+	t.Run("all providers failing returns error", func(t *testing.T) {
+		main := &stubProvider{name: "main", err: errors.New("down")}
+		r := newTestRegistry([]*stubProvider{main}, nil, nil)
+
+		_, err := r.FetchRates(context.Background(), "RUB", time.Time{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "main: down")
+	})
+}
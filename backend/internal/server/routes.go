@@ -11,6 +11,7 @@ import (
 	"github.com/sanches/finance-tracker-cc/backend/internal/handler"
 	"github.com/sanches/finance-tracker-cc/backend/internal/handler/respond"
 	"github.com/sanches/finance-tracker-cc/backend/internal/middleware"
+	"github.com/sanches/finance-tracker-cc/backend/internal/service"
 )
 
 func limitByIP(requests int, window time.Duration) func(http.Handler) http.Handler {
@@ -24,6 +25,8 @@ func limitByIP(requests int, window time.Duration) func(http.Handler) http.Handl
 
 func NewRouter(
 	authMw *middleware.Auth,
+	adminMw *middleware.Admin,
+	auditMw *middleware.Audit,
 	authH *handler.Auth,
 	accountH *handler.Account,
 	categoryH *handler.Category,
@@ -35,6 +38,19 @@ func NewRouter(
 	currencyH *handler.Currency,
 	exportH *handler.Export,
 	userH *handler.User,
+	reconciliationH *handler.Reconciliation,
+	balanceAssertionH *handler.BalanceAssertion,
+	creditCardH *handler.CreditCard,
+	loanH *handler.Loan,
+	securityH *handler.Security,
+	investmentH *handler.Investment,
+	interestH *handler.Interest,
+	accountGroupH *handler.AccountGroup,
+	householdH *handler.Household,
+	debtH *handler.Debt,
+	adminH *handler.Admin,
+	auditH *handler.Audit,
+	trashH *handler.Trash,
 ) http.Handler {
 	r := chi.NewRouter()
 
@@ -50,33 +66,86 @@ func NewRouter(
 		r.Route("/auth", func(r chi.Router) {
 			r.With(limitByIP(5, time.Minute)).Post("/login", authH.Login)
 			r.With(limitByIP(10, time.Minute)).Post("/register", authH.Register)
-			r.Post("/refresh", authH.Refresh)
+			r.With(auditMw.Record(service.AuditTokenRefresh)).Post("/refresh", authH.Refresh)
 			r.Post("/logout", authH.Logout)
 		})
 		r.Get("/currencies", currencyH.List)
 		r.With(limitByIP(20, time.Minute)).Post("/exchange-rates/sync", exchangeRateH.Sync)
+		r.With(limitByIP(20, time.Minute)).Post("/exchange-rates/backfill", exchangeRateH.Backfill)
 
 		// Protected routes
 		r.Group(func(r chi.Router) {
 			r.Use(authMw.Authenticate)
 
 			r.Put("/user", userH.Update)
-			r.Post("/user/reset", userH.Reset)
-			r.Post("/user/password", userH.ChangePassword)
+			r.With(auditMw.Record(service.AuditReset)).Post("/user/reset", userH.Reset)
+			r.With(auditMw.Record(service.AuditPasswordChange)).Post("/user/password", userH.ChangePassword)
+			r.Get("/user/login-history", userH.LoginHistory)
+			r.Get("/user/audit-log", auditH.List)
 
 			r.Route("/accounts", func(r chi.Router) {
 				r.Get("/", accountH.List)
 				r.Post("/", accountH.Create)
+				r.Post("/reconcile", accountH.ReconcileBalances)
+				r.Put("/order", accountH.Reorder)
 				r.Get("/{id}", accountH.Get)
 				r.Put("/{id}", accountH.Update)
-				r.Delete("/{id}", accountH.Delete)
+				r.With(auditMw.Record(service.AuditAccountDelete)).Delete("/{id}", accountH.Delete)
+				r.Post("/{id}/archive", accountH.Archive)
+				r.Post("/{id}/unarchive", accountH.Unarchive)
+				r.Get("/{id}/credit-card", creditCardH.Get)
+				r.Put("/{id}/credit-card", creditCardH.Update)
+				r.Get("/{id}/loan", loanH.Get)
+				r.Put("/{id}/loan", loanH.Update)
+				r.Get("/{id}/loan/schedule", loanH.Schedule)
+				r.Post("/{id}/loan/split-payments", loanH.SplitPayments)
+				r.Get("/{id}/holdings", investmentH.Holdings)
+				r.Get("/{id}/interest", interestH.Get)
+				r.Put("/{id}/interest", interestH.Update)
+				r.Delete("/{id}/interest", interestH.Delete)
+				r.Get("/{id}/interest/accruals", interestH.ListAccruals)
+				r.Post("/{id}/interest/accrue", interestH.Accrue)
+			})
+
+			r.Route("/account-groups", func(r chi.Router) {
+				r.Get("/", accountGroupH.List)
+				r.Post("/", accountGroupH.Create)
+				r.Put("/order", accountGroupH.Reorder)
+				r.Put("/{id}", accountGroupH.Update)
+				r.Delete("/{id}", accountGroupH.Delete)
+			})
+
+			r.Route("/households", func(r chi.Router) {
+				r.Get("/", householdH.List)
+				r.Post("/", householdH.Create)
+				r.Get("/{id}", householdH.Get)
+				r.Put("/{id}", householdH.Update)
+				r.Delete("/{id}", householdH.Delete)
+				r.Post("/{id}/members", householdH.AddMember)
+				r.Put("/{id}/members/{userID}", householdH.UpdateMember)
+				r.Delete("/{id}/members/{userID}", householdH.RemoveMember)
+			})
+
+			r.Route("/contacts", func(r chi.Router) {
+				r.Get("/", debtH.ListContacts)
+				r.Post("/", debtH.CreateContact)
+				r.Put("/{id}", debtH.UpdateContact)
+				r.Delete("/{id}", debtH.DeleteContact)
+				r.Post("/{id}/settle", debtH.Settle)
+			})
+
+			r.Route("/splits", func(r chi.Router) {
+				r.Get("/", debtH.ListSplits)
+				r.Post("/", debtH.CreateSplit)
+				r.Get("/{id}", debtH.GetSplit)
+				r.Delete("/{id}", debtH.DeleteSplit)
 			})
 
 			r.Route("/categories", func(r chi.Router) {
 				r.Get("/", categoryH.List)
 				r.Post("/", categoryH.Create)
 				r.Put("/{id}", categoryH.Update)
-				r.Delete("/{id}", categoryH.Delete)
+				r.With(auditMw.Record(service.AuditCategoryDelete)).Delete("/{id}", categoryH.Delete)
 			})
 
 			r.Route("/transactions", func(r chi.Router) {
@@ -87,10 +156,51 @@ func NewRouter(
 				r.Put("/transfer/{id}", transactionH.UpdateTransfer)
 				r.Get("/descriptions", transactionH.ListDescriptions)
 				r.Get("/{id}", transactionH.Get)
+				r.Get("/{id}/history", transactionH.History)
 				r.Put("/{id}", transactionH.Update)
+				r.Put("/{id}/status", transactionH.SetStatus)
 				r.Delete("/{id}", transactionH.Delete)
 			})
 
+			r.Route("/trash", func(r chi.Router) {
+				r.Get("/", trashH.List)
+				r.Post("/accounts/{id}/restore", trashH.RestoreAccount)
+				r.Post("/categories/{id}/restore", trashH.RestoreCategory)
+				r.Post("/transactions/{id}/restore", trashH.RestoreTransaction)
+			})
+
+			r.Route("/reconciliations", func(r chi.Router) {
+				r.Get("/", reconciliationH.List)
+				r.Post("/", reconciliationH.Start)
+				r.Get("/{id}", reconciliationH.Get)
+				r.Post("/{id}/finish", reconciliationH.Finish)
+				r.Delete("/{id}", reconciliationH.Cancel)
+			})
+
+			r.Route("/balance-assertions", func(r chi.Router) {
+				r.Get("/", balanceAssertionH.List)
+				r.Post("/", balanceAssertionH.Create)
+				r.Get("/check", balanceAssertionH.Check)
+				r.Delete("/{id}", balanceAssertionH.Delete)
+				r.Post("/{id}/adjust", balanceAssertionH.Adjust)
+			})
+
+			r.Route("/securities", func(r chi.Router) {
+				r.Get("/", securityH.List)
+				r.Post("/", securityH.Create)
+				r.Post("/prices/sync", securityH.SyncPrices)
+				r.Put("/{id}", securityH.Update)
+				r.Delete("/{id}", securityH.Delete)
+				r.Get("/{id}/prices", securityH.ListPrices)
+				r.Post("/{id}/prices", securityH.SetPrice)
+			})
+
+			r.Route("/trades", func(r chi.Router) {
+				r.Get("/", investmentH.ListTrades)
+				r.Post("/", investmentH.CreateTrade)
+				r.Delete("/{id}", investmentH.DeleteTrade)
+			})
+
 			r.Route("/reports", func(r chi.Router) {
 				r.Get("/spending", reportH.Spending)
 				r.Get("/income-expense", reportH.IncomeExpense)
@@ -98,24 +208,41 @@ func NewRouter(
 				r.Get("/summary", reportH.Summary)
 				r.Get("/cash-flow/years", reportH.CashFlowYears)
 				r.Get("/cash-flow", reportH.CashFlow)
+				r.Get("/fx-gain-loss", reportH.FXGainLoss)
+				r.Get("/debts", debtH.Report)
 			})
 
 			r.Route("/import", func(r chi.Router) {
 				r.Post("/csv", importH.Upload)
-				r.Post("/csv/confirm", importH.Confirm)
-				r.Post("/full", importFullH.Execute)
+				r.With(auditMw.Record(service.AuditImportCSV)).Post("/csv/confirm", importH.Confirm)
+				r.With(auditMw.Record(service.AuditImportFull)).Post("/full", importFullH.Execute)
 			})
 
-			r.Post("/currencies", currencyH.Create)
-			r.Put("/currencies/{code}", currencyH.Update)
+			r.With(adminMw.RequireAdmin).Post("/currencies", currencyH.Create)
+			r.With(adminMw.RequireAdmin).Put("/currencies/{code}", currencyH.Update)
 
 			r.Route("/exchange-rates", func(r chi.Router) {
 				r.Get("/", exchangeRateH.List)
 				r.Post("/", exchangeRateH.Create)
+				r.Get("/convert", exchangeRateH.Convert)
+				r.Delete("/{id}", exchangeRateH.Delete)
 			})
 
 			r.Route("/export", func(r chi.Router) {
-				r.Get("/csv", exportH.CSV)
+				r.With(auditMw.Record(service.AuditExportCSV)).Get("/csv", exportH.CSV)
+			})
+
+			r.Route("/admin", func(r chi.Router) {
+				r.Use(adminMw.RequireAdmin)
+				r.Get("/users", adminH.ListUsers)
+				r.With(auditMw.RecordOn(service.AuditUserDisable, "id")).Post("/users/{id}/disable", adminH.DisableUser)
+				r.With(auditMw.RecordOn(service.AuditUserEnable, "id")).Post("/users/{id}/enable", adminH.EnableUser)
+				r.With(auditMw.RecordOn(service.AuditUserPasswordReset, "id")).Post("/users/{id}/password", adminH.ResetPassword)
+				r.Get("/stats", adminH.Stats)
+				r.Get("/invite-codes", adminH.ListInviteCodes)
+				r.With(auditMw.Record(service.AuditInviteCodeCreate)).Post("/invite-codes", adminH.CreateInviteCode)
+				r.With(auditMw.Record(service.AuditInviteCodeRevoke)).Post("/invite-codes/{code}/revoke", adminH.RevokeInviteCode)
+				r.With(auditMw.Record(service.AuditExchangeRateDelete)).Delete("/exchange-rates/{id}", adminH.DeleteExchangeRate)
 			})
 
 		})
@@ -3,51 +3,103 @@ package service
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 
 	"github.com/sanches/finance-tracker-cc/backend/internal/dto"
 	"github.com/sanches/finance-tracker-cc/backend/internal/store"
 )
 
 var (
-	ErrNotFound      = errors.New("not found")
-	ErrAccountExists = errors.New("account with this name already exists")
+	ErrNotFound        = errors.New("not found")
+	ErrAccountExists   = errors.New("account with this name already exists")
+	ErrAccountNotFound = errors.New("account not found")
+	ErrAccountArchived = errors.New("account is archived")
 )
 
 type accountStore interface {
 	CreateAccount(ctx context.Context, arg store.CreateAccountParams) (store.Account, error)
-	ListAccounts(ctx context.Context, userID uuid.UUID) ([]store.ListAccountsRow, error)
+	ListAccounts(ctx context.Context, arg store.ListAccountsParams) ([]store.ListAccountsRow, error)
 	GetAccount(ctx context.Context, arg store.GetAccountParams) (store.Account, error)
+	GetAccessibleAccount(ctx context.Context, arg store.GetAccessibleAccountParams) (store.GetAccessibleAccountRow, error)
 	UpdateAccount(ctx context.Context, arg store.UpdateAccountParams) (store.Account, error)
-	DeleteAccount(ctx context.Context, arg store.DeleteAccountParams) error
-	GetAccountTransactionSums(ctx context.Context, accountID uuid.UUID) (store.GetAccountTransactionSumsRow, error)
+	TrashAccount(ctx context.Context, arg store.TrashAccountParams) (pgtype.Timestamptz, error)
+	SetAccountArchived(ctx context.Context, arg store.SetAccountArchivedParams) (store.Account, error)
+	ReconcileAccountBalances(ctx context.Context, userID uuid.UUID) ([]store.ReconcileAccountBalancesRow, error)
+	GetAccountMarketValue(ctx context.Context, arg store.GetAccountMarketValueParams) (pgtype.Numeric, error)
+	ReorderAccounts(ctx context.Context, arg store.ReorderAccountsParams) (int64, error)
+	GetAccountGroup(ctx context.Context, arg store.GetAccountGroupParams) (store.AccountGroup, error)
+	ListAccountGroups(ctx context.Context, userID uuid.UUID) ([]store.AccountGroup, error)
+	GetUserByID(ctx context.Context, id uuid.UUID) (store.User, error)
+	householdReader
 }
 
 type Account struct {
-	queries accountStore
+	queries    accountStore
+	rates      *RateResolver
+	currencies *CurrencyPrecision
 }
 
-func NewAccount(queries *store.Queries) *Account {
-	return &Account{queries: queries}
+func NewAccount(queries *store.Queries, rates *RateResolver, currencies *CurrencyPrecision) *Account {
+	return &Account{queries: queries, rates: rates, currencies: currencies}
 }
 
-func accountToResponse(a store.Account, sums store.GetAccountTransactionSumsRow) dto.AccountResponse {
-	balance := numericAdd(a.InitialBalance, numericSub(sums.TotalIncome, sums.TotalExpense))
+func accountToResponse(a store.Account, prec Precisions) dto.AccountResponse {
+	balance := MoneyFromNumeric(a.Balance, a.Currency)
 	return dto.AccountResponse{
 		ID:             a.ID,
 		Name:           a.Name,
 		Type:           a.Type,
 		Currency:       a.Currency,
-		InitialBalance: numericToString(a.InitialBalance),
-		Balance:        numericToString(balance),
+		InitialBalance: prec.Format(a.InitialBalance, a.Currency),
+		Balance:        balance.Format(prec),
+		GroupID:        nullableToUUID(a.GroupID),
+		SortOrder:      int(a.SortOrder),
+		HouseholdID:    nullableToUUID(a.HouseholdID),
+		Role:           RoleOwner,
+		ArchivedOn:     archivedOn(a.ArchivedOn),
 		CreatedAt:      a.CreatedAt.Time,
 		UpdatedAt:      a.UpdatedAt.Time,
+		DeletedAt:      deletedAt(a.DeletedAt),
 	}
 }
 
+// addMarketValue reports what an investment account's securities are worth
+// and counts it in the balance, which otherwise is the account's cash.
+func addMarketValue(resp *dto.AccountResponse, cash, marketValue pgtype.Numeric, prec Precisions) {
+	decimals := prec.Of(resp.Currency)
+	mv := numericToDecimal(marketValue)
+	value := mv.StringFixed(decimals)
+	resp.Balance = numericToDecimal(cash).Add(mv).StringFixed(decimals)
+	resp.MarketValue = &value
+}
+
+func archivedOn(d pgtype.Date) *string {
+	if !d.Valid {
+		return nil
+	}
+	s := dateToString(d)
+	return &s
+}
+
 func (s *Account) Create(ctx context.Context, userID uuid.UUID, req dto.CreateAccountRequest) (*dto.AccountResponse, error) {
+	prec, err := s.currencies.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := prec.Check(req.InitialBalance, req.Currency); err != nil {
+		return nil, err
+	}
+	if err := s.checkGroup(ctx, userID, req.GroupID); err != nil {
+		return nil, err
+	}
+	household, err := sharedWith(ctx, s.queries, userID, req.HouseholdID)
+	if err != nil {
+		return nil, err
+	}
 	balance := numericFromStringOrZero(req.InitialBalance)
 
 	acct, err := s.queries.CreateAccount(ctx, store.CreateAccountParams{
@@ -56,6 +108,8 @@ func (s *Account) Create(ctx context.Context, userID uuid.UUID, req dto.CreateAc
 		Type:           req.Type,
 		Currency:       req.Currency,
 		InitialBalance: balance,
+		GroupID:        uuidToNullable(req.GroupID),
+		HouseholdID:    household,
 	})
 	if err != nil {
 		if isDuplicateKey(err) {
@@ -64,38 +118,127 @@ func (s *Account) Create(ctx context.Context, userID uuid.UUID, req dto.CreateAc
 		return nil, err
 	}
 
-	return s.toResponse(ctx, acct)
+	return s.toResponse(ctx, acct, prec)
 }
 
-func (s *Account) List(ctx context.Context, userID uuid.UUID) ([]dto.AccountResponse, error) {
-	accounts, err := s.queries.ListAccounts(ctx, userID)
+// List returns the user's accounts and those shared with them through a
+// household in display order; archived ones only if includeArchived. Users
+// with account groups also get each group's balance in their base currency.
+func (s *Account) List(ctx context.Context, userID uuid.UUID, includeArchived bool) (*dto.AccountListResponse, error) {
+	accounts, err := s.queries.ListAccounts(ctx, store.ListAccountsParams{UserID: userID, IncludeArchived: includeArchived})
+	if err != nil {
+		return nil, err
+	}
+	groups, err := s.queries.ListAccountGroups(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
 
-	result := make([]dto.AccountResponse, 0, len(accounts))
+	prec, err := s.currencies.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.AccountListResponse{
+		Data:   make([]dto.AccountResponse, 0, len(accounts)),
+		Groups: []dto.AccountGroupTotal{},
+	}
 	for _, a := range accounts {
-		sums, err := s.queries.GetAccountTransactionSums(ctx, a.ID)
-		if err != nil {
-			return nil, err
-		}
-		result = append(result, listAccountToResponse(a, sums))
+		result.Data = append(result.Data, listAccountToResponse(a, prec))
+	}
+	if len(groups) == 0 {
+		return result, nil
+	}
+
+	user, err := s.queries.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	result.BaseCurrency = user.BaseCurrency
+	result.Groups, err = groupTotals(ctx, s.rates, userID, groups, accounts, user.BaseCurrency, time.Now(), prec)
+	if err != nil {
+		return nil, err
 	}
 	return result, nil
 }
 
+// Reorder puts the given accounts in that order within their groups.
+// Accounts left out keep their position number.
+func (s *Account) Reorder(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) (*dto.AccountListResponse, error) {
+	n, err := s.queries.ReorderAccounts(ctx, store.ReorderAccountsParams{AccountIds: ids, UserID: userID})
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, ErrInvalidOrder
+	}
+	return s.List(ctx, userID, false)
+}
+
+// checkGroup verifies that groupID, if set, is one of the user's groups.
+func (s *Account) checkGroup(ctx context.Context, userID uuid.UUID, groupID *uuid.UUID) error {
+	if groupID == nil {
+		return nil
+	}
+	if _, err := s.queries.GetAccountGroup(ctx, store.GetAccountGroupParams{ID: *groupID, UserID: userID}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrInvalidAccountGroup
+		}
+		return err
+	}
+	return nil
+}
+
+// Get returns an account the user owns or shares through a household.
+// Shared accounts of other members are shown without their owner's group.
 func (s *Account) Get(ctx context.Context, userID, accountID uuid.UUID) (*dto.AccountResponse, error) {
-	acct, err := s.queries.GetAccount(ctx, store.GetAccountParams{ID: accountID, UserID: userID})
+	row, err := s.queries.GetAccessibleAccount(ctx, store.GetAccessibleAccountParams{ID: accountID, UserID: userID})
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrNotFound
 		}
 		return nil, err
 	}
-	return s.toResponse(ctx, acct)
+	prec, err := s.currencies.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	acct := row.Account
+	if acct.UserID != userID {
+		acct.GroupID = pgtype.UUID{}
+	}
+	resp, err := s.toResponse(ctx, acct, prec)
+	if err != nil {
+		return nil, err
+	}
+	resp.Role = row.Role
+	return resp, nil
 }
 
 func (s *Account) Update(ctx context.Context, userID, accountID uuid.UUID, req dto.UpdateAccountRequest) (*dto.AccountResponse, error) {
+	prec, err := s.currencies.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.InitialBalance != "" {
+		current, err := s.queries.GetAccount(ctx, store.GetAccountParams{ID: accountID, UserID: userID})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, ErrNotFound
+			}
+			return nil, err
+		}
+		if err := prec.Check(req.InitialBalance, current.Currency); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.checkGroup(ctx, userID, req.GroupID); err != nil {
+		return nil, err
+	}
+	household, err := sharedWith(ctx, s.queries, userID, req.HouseholdID)
+	if err != nil {
+		return nil, err
+	}
 	balance := numericFromStringOrZero(req.InitialBalance)
 
 	acct, err := s.queries.UpdateAccount(ctx, store.UpdateAccountParams{
@@ -103,6 +246,8 @@ func (s *Account) Update(ctx context.Context, userID, accountID uuid.UUID, req d
 		Name:           req.Name,
 		Type:           req.Type,
 		InitialBalance: balance,
+		GroupID:        uuidToNullable(req.GroupID),
+		HouseholdID:    household,
 		UserID:         userID,
 	})
 	if err != nil {
@@ -114,34 +259,116 @@ func (s *Account) Update(ctx context.Context, userID, accountID uuid.UUID, req d
 		}
 		return nil, err
 	}
-	return s.toResponse(ctx, acct)
+	return s.toResponse(ctx, acct, prec)
 }
 
+// Delete moves the account to the trash together with its transactions;
+// Trash.RestoreAccount brings them back.
 func (s *Account) Delete(ctx context.Context, userID, accountID uuid.UUID) error {
-	return s.queries.DeleteAccount(ctx, store.DeleteAccountParams{ID: accountID, UserID: userID})
+	_, err := s.queries.TrashAccount(ctx, store.TrashAccountParams{ID: accountID, UserID: userID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	return err
 }
 
-func listAccountToResponse(a store.ListAccountsRow, sums store.GetAccountTransactionSumsRow) dto.AccountResponse {
-	balance := numericAdd(a.InitialBalance, numericSub(sums.TotalIncome, sums.TotalExpense))
-	return dto.AccountResponse{
+// Archive hides the account from default lists and the dashboard and blocks
+// new transactions into it. Its history stays in reports and exports. date
+// defaults to today.
+func (s *Account) Archive(ctx context.Context, userID, accountID uuid.UUID, date string) (*dto.AccountResponse, error) {
+	archived := pgtype.Date{Time: time.Now(), Valid: true}
+	if date != "" {
+		d, err := dateFromString(date)
+		if err != nil {
+			return nil, errors.New("invalid date format, use YYYY-MM-DD")
+		}
+		archived = d
+	}
+	return s.setArchived(ctx, userID, accountID, archived)
+}
+
+// Unarchive reverses Archive.
+func (s *Account) Unarchive(ctx context.Context, userID, accountID uuid.UUID) (*dto.AccountResponse, error) {
+	return s.setArchived(ctx, userID, accountID, pgtype.Date{})
+}
+
+func (s *Account) setArchived(ctx context.Context, userID, accountID uuid.UUID, date pgtype.Date) (*dto.AccountResponse, error) {
+	acct, err := s.queries.SetAccountArchived(ctx, store.SetAccountArchivedParams{
+		ID:         accountID,
+		UserID:     userID,
+		ArchivedOn: date,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	prec, err := s.currencies.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.toResponse(ctx, acct, prec)
+}
+
+func listAccountToResponse(a store.ListAccountsRow, prec Precisions) dto.AccountResponse {
+	balance := MoneyFromNumeric(a.Balance, a.Currency)
+	resp := dto.AccountResponse{
 		ID:             a.ID,
 		Name:           a.Name,
 		Type:           a.Type,
 		Currency:       a.Currency,
-		InitialBalance: numericToString(a.InitialBalance),
-		Balance:        numericToString(balance),
+		InitialBalance: prec.Format(a.InitialBalance, a.Currency),
+		Balance:        balance.Format(prec),
 		RecentTxCount:  int(a.RecentTxCount),
+		GroupID:        nullableToUUID(a.GroupID),
+		SortOrder:      int(a.SortOrder),
+		HouseholdID:    nullableToUUID(a.HouseholdID),
+		Role:           a.Role,
+		ArchivedOn:     archivedOn(a.ArchivedOn),
 		CreatedAt:      a.CreatedAt.Time,
 		UpdatedAt:      a.UpdatedAt.Time,
 	}
+	if a.Type == accountTypeInvestment {
+		addMarketValue(&resp, a.Balance, a.MarketValue, prec)
+	}
+	return resp
 }
 
-func (s *Account) toResponse(ctx context.Context, a store.Account) (*dto.AccountResponse, error) {
-	sums, err := s.queries.GetAccountTransactionSums(ctx, a.ID)
+func (s *Account) toResponse(ctx context.Context, a store.Account, prec Precisions) (*dto.AccountResponse, error) {
+	resp := accountToResponse(a, prec)
+	if a.Type == accountTypeInvestment {
+		mv, err := s.queries.GetAccountMarketValue(ctx, store.GetAccountMarketValueParams{AccountID: a.ID, UserID: a.UserID})
+		if err != nil {
+			return nil, err
+		}
+		addMarketValue(&resp, a.Balance, mv, prec)
+	}
+	return &resp, nil
+}
+
+// ReconcileBalances recomputes the stored balance of every account of the user
+// from its transactions and returns the accounts whose stored balance had
+// drifted, after correcting them. An empty result means all balances agreed.
+func (s *Account) ReconcileBalances(ctx context.Context, userID uuid.UUID) ([]dto.BalanceCorrection, error) {
+	rows, err := s.queries.ReconcileAccountBalances(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	prec, err := s.currencies.Load(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	resp := accountToResponse(a, sums)
-	return &resp, nil
+	result := make([]dto.BalanceCorrection, 0, len(rows))
+	for _, r := range rows {
+		result = append(result, dto.BalanceCorrection{
+			AccountID:     r.ID,
+			AccountName:   r.Name,
+			Currency:      r.Currency,
+			StoredBalance: prec.Format(r.StoredBalance, r.Currency),
+			Balance:       prec.Format(r.Balance, r.Currency),
+		})
+	}
+	return result, nil
 }
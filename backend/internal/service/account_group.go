@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/dto"
+	"github.com/sanches/finance-tracker-cc/backend/internal/store"
+)
+
+var (
+	ErrAccountGroupExists   = errors.New("account group with this name already exists")
+	ErrAccountGroupNotFound = errors.New("account group not found")
+	ErrInvalidAccountGroup  = errors.New("group_id must be one of your account groups")
+	ErrInvalidOrder         = errors.New("order must list distinct ids of your own")
+)
+
+type accountGroupStore interface {
+	CreateAccountGroup(ctx context.Context, arg store.CreateAccountGroupParams) (store.AccountGroup, error)
+	ListAccountGroups(ctx context.Context, userID uuid.UUID) ([]store.AccountGroup, error)
+	UpdateAccountGroup(ctx context.Context, arg store.UpdateAccountGroupParams) (store.AccountGroup, error)
+	DeleteAccountGroup(ctx context.Context, arg store.DeleteAccountGroupParams) (int64, error)
+	ReorderAccountGroups(ctx context.Context, arg store.ReorderAccountGroupsParams) (int64, error)
+}
+
+// AccountGroup manages the user-defined groups accounts are listed in.
+type AccountGroup struct {
+	queries accountGroupStore
+}
+
+func NewAccountGroup(queries *store.Queries) *AccountGroup {
+	return &AccountGroup{queries: queries}
+}
+
+func accountGroupToResponse(g store.AccountGroup) dto.AccountGroupResponse {
+	return dto.AccountGroupResponse{
+		ID:        g.ID,
+		Name:      g.Name,
+		SortOrder: int(g.SortOrder),
+		CreatedAt: g.CreatedAt.Time,
+		UpdatedAt: g.UpdatedAt.Time,
+	}
+}
+
+// Create adds a group at the end of the user's groups.
+func (s *AccountGroup) Create(ctx context.Context, userID uuid.UUID, req dto.AccountGroupRequest) (*dto.AccountGroupResponse, error) {
+	g, err := s.queries.CreateAccountGroup(ctx, store.CreateAccountGroupParams{UserID: userID, Name: req.Name})
+	if err != nil {
+		if isDuplicateKey(err) {
+			return nil, ErrAccountGroupExists
+		}
+		return nil, err
+	}
+	resp := accountGroupToResponse(g)
+	return &resp, nil
+}
+
+// List returns the user's groups in their order.
+func (s *AccountGroup) List(ctx context.Context, userID uuid.UUID) ([]dto.AccountGroupResponse, error) {
+	groups, err := s.queries.ListAccountGroups(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]dto.AccountGroupResponse, 0, len(groups))
+	for _, g := range groups {
+		result = append(result, accountGroupToResponse(g))
+	}
+	return result, nil
+}
+
+func (s *AccountGroup) Update(ctx context.Context, userID, id uuid.UUID, req dto.AccountGroupRequest) (*dto.AccountGroupResponse, error) {
+	g, err := s.queries.UpdateAccountGroup(ctx, store.UpdateAccountGroupParams{ID: id, UserID: userID, Name: req.Name})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrAccountGroupNotFound
+		}
+		if isDuplicateKey(err) {
+			return nil, ErrAccountGroupExists
+		}
+		return nil, err
+	}
+	resp := accountGroupToResponse(g)
+	return &resp, nil
+}
+
+// Delete removes a group; its accounts become ungrouped.
+func (s *AccountGroup) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	n, err := s.queries.DeleteAccountGroup(ctx, store.DeleteAccountGroupParams{ID: id, UserID: userID})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrAccountGroupNotFound
+	}
+	return nil
+}
+
+// Reorder puts the given groups in that order. Groups left out keep their
+// position number.
+func (s *AccountGroup) Reorder(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) ([]dto.AccountGroupResponse, error) {
+	n, err := s.queries.ReorderAccountGroups(ctx, store.ReorderAccountGroupsParams{GroupIds: ids, UserID: userID})
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, ErrInvalidOrder
+	}
+	return s.List(ctx, userID)
+}
+
+// groupTotals adds up accounts (balance plus market value) per group in
+// base currency at the rates in effect on date. Groups come in the groups'
+// order, with an entry for the ungrouped accounts last if there are any.
+func groupTotals(ctx context.Context, rates *RateResolver, userID uuid.UUID, groups []store.AccountGroup, accounts []store.ListAccountsRow, base string, date time.Time, prec Precisions) ([]dto.AccountGroupTotal, error) {
+	balances := make(map[uuid.UUID][]Money, len(groups))
+	var ungrouped []Money
+	for _, a := range accounts {
+		balance, err := MoneyFromNumeric(a.Balance, a.Currency).Add(MoneyFromNumeric(a.MarketValue, a.Currency))
+		if err != nil {
+			return nil, err
+		}
+		if a.GroupID.Valid {
+			balances[a.GroupID.Bytes] = append(balances[a.GroupID.Bytes], balance)
+		} else {
+			ungrouped = append(ungrouped, balance)
+		}
+	}
+
+	total := func(id *uuid.UUID, name string, members []Money) (dto.AccountGroupTotal, error) {
+		sum, unconverted, err := rates.Total(ctx, userID, members, base, date)
+		if err != nil {
+			return dto.AccountGroupTotal{}, err
+		}
+		return dto.AccountGroupTotal{
+			GroupID:               id,
+			Name:                  name,
+			AccountCount:          len(members),
+			Balance:               sum.Format(prec),
+			UnconvertedCurrencies: unconverted,
+		}, nil
+	}
+
+	result := make([]dto.AccountGroupTotal, 0, len(groups)+1)
+	for _, g := range groups {
+		t, err := total(&g.ID, g.Name, balances[g.ID])
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, t)
+	}
+	if len(ungrouped) > 0 {
+		t, err := total(nil, "", ungrouped)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, t)
+	}
+	return result, nil
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/dto"
+	"github.com/sanches/finance-tracker-cc/backend/internal/store"
+)
+
+func TestGroupTotals(t *testing.T) {
+	armenia := store.AccountGroup{ID: uuid.New(), Name: "Armenia"}
+	emergency := store.AccountGroup{ID: uuid.New(), Name: "Emergency fund"}
+	inGroup := func(g store.AccountGroup) pgtype.UUID { return pgtype.UUID{Bytes: g.ID, Valid: true} }
+
+	accounts := []store.ListAccountsRow{
+		{Currency: "USD", Balance: numericFromString("100"), MarketValue: numericFromString("50"), GroupID: inGroup(armenia)},
+		{Currency: "EUR", Balance: numericFromString("200"), MarketValue: numericFromString("0"), GroupID: inGroup(armenia)},
+		{Currency: "AMD", Balance: numericFromString("40000"), MarketValue: numericFromString("0"), GroupID: inGroup(armenia)},
+		{Currency: "EUR", Balance: numericFromString("10"), MarketValue: numericFromString("0")},
+	}
+	rates := &RateResolver{queries: &mockRateStore{rates: []store.ExchangeRate{
+		storedRate(t, "USD", "EUR", "0.90", "2026-01-31", "ecb"),
+	}}}
+
+	prec, err := testCurrencyPrecision().Load(context.Background())
+	require.NoError(t, err)
+
+	totals, err := groupTotals(context.Background(), rates, uuid.New(), []store.AccountGroup{armenia, emergency}, accounts, "EUR", day("2026-02-01"), prec)
+	require.NoError(t, err)
+	require.Equal(t, []dto.AccountGroupTotal{
+		{GroupID: &armenia.ID, Name: "Armenia", AccountCount: 3, Balance: "335.00", UnconvertedCurrencies: []string{"AMD"}},
+		{GroupID: &emergency.ID, Name: "Emergency fund", AccountCount: 0, Balance: "0.00", UnconvertedCurrencies: []string{}},
+		{GroupID: nil, Name: "", AccountCount: 1, Balance: "10.00", UnconvertedCurrencies: []string{}},
+	}, totals)
+}
+
+func TestAccountCreate_ForeignGroup(t *testing.T) {
+	mock := &mockAccountStore{
+		getAccountGroupFn: func(ctx context.Context, arg store.GetAccountGroupParams) (store.AccountGroup, error) {
+			return store.AccountGroup{}, pgx.ErrNoRows
+		},
+	}
+	svc := &Account{queries: mock}
+
+	groupID := uuid.New()
+	_, err := svc.Create(context.Background(), uuid.New(), dto.CreateAccountRequest{
+		Name: "Ameriabank", Type: "deposit", Currency: "AMD", GroupID: &groupID,
+	})
+	require.ErrorIs(t, err, ErrInvalidAccountGroup)
+}
+
+func TestAccountReorder_Rejected(t *testing.T) {
+	mock := &mockAccountStore{
+		reorderAccountsFn: func(ctx context.Context, arg store.ReorderAccountsParams) (int64, error) {
+			return 0, nil
+		},
+	}
+	svc := &Account{queries: mock}
+
+	_, err := svc.Reorder(context.Background(), uuid.New(), []uuid.UUID{uuid.New()})
+	require.ErrorIs(t, err, ErrInvalidOrder)
+}
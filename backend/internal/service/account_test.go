@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -16,30 +17,66 @@ import (
 
 type mockAccountStore struct {
 	createAccountFn            func(ctx context.Context, arg store.CreateAccountParams) (store.Account, error)
-	listAccountsFn             func(ctx context.Context, userID uuid.UUID) ([]store.ListAccountsRow, error)
+	listAccountsFn             func(ctx context.Context, arg store.ListAccountsParams) ([]store.ListAccountsRow, error)
 	getAccountFn               func(ctx context.Context, arg store.GetAccountParams) (store.Account, error)
+	getAccessibleAccountFn     func(ctx context.Context, arg store.GetAccessibleAccountParams) (store.GetAccessibleAccountRow, error)
 	updateAccountFn            func(ctx context.Context, arg store.UpdateAccountParams) (store.Account, error)
-	deleteAccountFn            func(ctx context.Context, arg store.DeleteAccountParams) error
-	getAccountTransactionSumsFn func(ctx context.Context, accountID uuid.UUID) (store.GetAccountTransactionSumsRow, error)
+	trashAccountFn             func(ctx context.Context, arg store.TrashAccountParams) (pgtype.Timestamptz, error)
+	setAccountArchivedFn       func(ctx context.Context, arg store.SetAccountArchivedParams) (store.Account, error)
+	reconcileAccountBalancesFn func(ctx context.Context, userID uuid.UUID) ([]store.ReconcileAccountBalancesRow, error)
+	getAccountMarketValueFn    func(ctx context.Context, arg store.GetAccountMarketValueParams) (pgtype.Numeric, error)
+	reorderAccountsFn          func(ctx context.Context, arg store.ReorderAccountsParams) (int64, error)
+	getAccountGroupFn          func(ctx context.Context, arg store.GetAccountGroupParams) (store.AccountGroup, error)
+	listAccountGroupsFn        func(ctx context.Context, userID uuid.UUID) ([]store.AccountGroup, error)
+	getUserByIDFn              func(ctx context.Context, id uuid.UUID) (store.User, error)
+	getHouseholdFn             func(ctx context.Context, arg store.GetHouseholdParams) (store.GetHouseholdRow, error)
 }
 
 func (m *mockAccountStore) CreateAccount(ctx context.Context, arg store.CreateAccountParams) (store.Account, error) {
 	return m.createAccountFn(ctx, arg)
 }
-func (m *mockAccountStore) ListAccounts(ctx context.Context, userID uuid.UUID) ([]store.ListAccountsRow, error) {
-	return m.listAccountsFn(ctx, userID)
+func (m *mockAccountStore) ListAccounts(ctx context.Context, arg store.ListAccountsParams) ([]store.ListAccountsRow, error) {
+	return m.listAccountsFn(ctx, arg)
 }
 func (m *mockAccountStore) GetAccount(ctx context.Context, arg store.GetAccountParams) (store.Account, error) {
 	return m.getAccountFn(ctx, arg)
 }
+func (m *mockAccountStore) GetAccessibleAccount(ctx context.Context, arg store.GetAccessibleAccountParams) (store.GetAccessibleAccountRow, error) {
+	if m.getAccessibleAccountFn != nil {
+		return m.getAccessibleAccountFn(ctx, arg)
+	}
+	acct, err := m.getAccountFn(ctx, store.GetAccountParams{ID: arg.ID, UserID: arg.UserID})
+	return store.GetAccessibleAccountRow{Account: acct, Role: RoleOwner}, err
+}
 func (m *mockAccountStore) UpdateAccount(ctx context.Context, arg store.UpdateAccountParams) (store.Account, error) {
 	return m.updateAccountFn(ctx, arg)
 }
-func (m *mockAccountStore) DeleteAccount(ctx context.Context, arg store.DeleteAccountParams) error {
-	return m.deleteAccountFn(ctx, arg)
+func (m *mockAccountStore) TrashAccount(ctx context.Context, arg store.TrashAccountParams) (pgtype.Timestamptz, error) {
+	return m.trashAccountFn(ctx, arg)
+}
+func (m *mockAccountStore) SetAccountArchived(ctx context.Context, arg store.SetAccountArchivedParams) (store.Account, error) {
+	return m.setAccountArchivedFn(ctx, arg)
+}
+func (m *mockAccountStore) ReconcileAccountBalances(ctx context.Context, userID uuid.UUID) ([]store.ReconcileAccountBalancesRow, error) {
+	return m.reconcileAccountBalancesFn(ctx, userID)
+}
+func (m *mockAccountStore) GetAccountMarketValue(ctx context.Context, arg store.GetAccountMarketValueParams) (pgtype.Numeric, error) {
+	return m.getAccountMarketValueFn(ctx, arg)
+}
+func (m *mockAccountStore) ReorderAccounts(ctx context.Context, arg store.ReorderAccountsParams) (int64, error) {
+	return m.reorderAccountsFn(ctx, arg)
+}
+func (m *mockAccountStore) GetAccountGroup(ctx context.Context, arg store.GetAccountGroupParams) (store.AccountGroup, error) {
+	return m.getAccountGroupFn(ctx, arg)
+}
+func (m *mockAccountStore) ListAccountGroups(ctx context.Context, userID uuid.UUID) ([]store.AccountGroup, error) {
+	return m.listAccountGroupsFn(ctx, userID)
+}
+func (m *mockAccountStore) GetUserByID(ctx context.Context, id uuid.UUID) (store.User, error) {
+	return m.getUserByIDFn(ctx, id)
 }
-func (m *mockAccountStore) GetAccountTransactionSums(ctx context.Context, accountID uuid.UUID) (store.GetAccountTransactionSumsRow, error) {
-	return m.getAccountTransactionSumsFn(ctx, accountID)
+func (m *mockAccountStore) GetHousehold(ctx context.Context, arg store.GetHouseholdParams) (store.GetHouseholdRow, error) {
+	return m.getHouseholdFn(ctx, arg)
 }
 
 func makeTimestamp() pgtype.Timestamptz {
@@ -59,16 +96,11 @@ func TestAccountGet_Balance(t *testing.T) {
 				Type:           "deposit",
 				Currency:       "USD",
 				InitialBalance: numericFromString("1000.00"),
+				Balance:        numericFromString("1300.00"),
 				CreatedAt:      makeTimestamp(),
 				UpdatedAt:      makeTimestamp(),
 			}, nil
 		},
-		getAccountTransactionSumsFn: func(ctx context.Context, id uuid.UUID) (store.GetAccountTransactionSumsRow, error) {
-			return store.GetAccountTransactionSumsRow{
-				TotalIncome:  numericFromString("500.00"),
-				TotalExpense: numericFromString("200.00"),
-			}, nil
-		},
 	}
 
 	svc := &Account{queries: mock}
@@ -76,7 +108,7 @@ func TestAccountGet_Balance(t *testing.T) {
 
 	require.NoError(t, err)
 	require.Equal(t, "1000.00", resp.InitialBalance)
-	require.Equal(t, "1300.00", resp.Balance) // 1000 + (500 - 200) = 1300
+	require.Equal(t, "1300.00", resp.Balance) // stored balance, not recomputed
 }
 
 func TestAccountGet_NotFound(t *testing.T) {
@@ -92,49 +124,76 @@ func TestAccountGet_NotFound(t *testing.T) {
 	require.ErrorIs(t, err, ErrNotFound)
 }
 
+func TestAccountGet_InvestmentMarketValue(t *testing.T) {
+	accountID := uuid.New()
+	userID := uuid.New()
+
+	mock := &mockAccountStore{
+		getAccountFn: func(ctx context.Context, arg store.GetAccountParams) (store.Account, error) {
+			return store.Account{
+				ID:             accountID,
+				UserID:         userID,
+				Name:           "Brokerage",
+				Type:           "investment",
+				Currency:       "USD",
+				InitialBalance: numericFromString("0"),
+				Balance:        numericFromString("250.00"),
+				CreatedAt:      makeTimestamp(),
+				UpdatedAt:      makeTimestamp(),
+			}, nil
+		},
+		getAccountMarketValueFn: func(ctx context.Context, arg store.GetAccountMarketValueParams) (pgtype.Numeric, error) {
+			require.Equal(t, accountID, arg.AccountID)
+			return numericFromString("1234.56789"), nil
+		},
+	}
+
+	svc := &Account{queries: mock}
+	resp, err := svc.Get(context.Background(), userID, accountID)
+
+	require.NoError(t, err)
+	require.NotNil(t, resp.MarketValue)
+	require.Equal(t, "1234.57", *resp.MarketValue)
+	require.Equal(t, "1484.57", resp.Balance) // cash + securities
+}
+
 func TestAccountList_MultipleAccounts(t *testing.T) {
 	userID := uuid.New()
 	acct1 := uuid.New()
 	acct2 := uuid.New()
 
 	mock := &mockAccountStore{
-		listAccountsFn: func(ctx context.Context, uid uuid.UUID) ([]store.ListAccountsRow, error) {
+		listAccountsFn: func(ctx context.Context, arg store.ListAccountsParams) ([]store.ListAccountsRow, error) {
 			return []store.ListAccountsRow{
 				{
 					ID: acct1, UserID: userID, Name: "Checking", Type: "deposit", Currency: "USD",
 					InitialBalance: numericFromString("500.00"),
+					Balance:        numericFromString("550.00"),
 					CreatedAt:      makeTimestamp(),
 					UpdatedAt:      makeTimestamp(),
 				},
 				{
 					ID: acct2, UserID: userID, Name: "Savings", Type: "other", Currency: "USD",
 					InitialBalance: numericFromString("2000.00"),
+					Balance:        numericFromString("2000.00"),
 					CreatedAt:      makeTimestamp(),
 					UpdatedAt:      makeTimestamp(),
 				},
 			}, nil
 		},
-		getAccountTransactionSumsFn: func(ctx context.Context, id uuid.UUID) (store.GetAccountTransactionSumsRow, error) {
-			if id == acct1 {
-				return store.GetAccountTransactionSumsRow{
-					TotalIncome:  numericFromString("100.00"),
-					TotalExpense: numericFromString("50.00"),
-				}, nil
-			}
-			return store.GetAccountTransactionSumsRow{
-				TotalIncome:  numericFromString("0.00"),
-				TotalExpense: numericFromString("0.00"),
-			}, nil
+		listAccountGroupsFn: func(ctx context.Context, userID uuid.UUID) ([]store.AccountGroup, error) {
+			return nil, nil
 		},
 	}
 
 	svc := &Account{queries: mock}
-	result, err := svc.List(context.Background(), userID)
+	result, err := svc.List(context.Background(), userID, false)
 
 	require.NoError(t, err)
-	require.Len(t, result, 2)
-	require.Equal(t, "550.00", result[0].Balance) // 500 + (100 - 50)
-	require.Equal(t, "2000.00", result[1].Balance) // 2000 + (0 - 0)
+	require.Len(t, result.Data, 2)
+	require.Equal(t, "550.00", result.Data[0].Balance) // 500 + (100 - 50)
+	require.Equal(t, "2000.00", result.Data[1].Balance) // 2000 + (0 - 0)
+	require.Empty(t, result.Groups)
 }
 
 func TestAccountCreate_DefaultBalance(t *testing.T) {
@@ -148,15 +207,10 @@ func TestAccountCreate_DefaultBalance(t *testing.T) {
 			return store.Account{
 				ID: accountID, UserID: userID, Name: arg.Name, Type: arg.Type,
 				Currency: arg.Currency, InitialBalance: arg.InitialBalance,
+				Balance:   arg.InitialBalance,
 				CreatedAt: makeTimestamp(), UpdatedAt: makeTimestamp(),
 			}, nil
 		},
-		getAccountTransactionSumsFn: func(ctx context.Context, id uuid.UUID) (store.GetAccountTransactionSumsRow, error) {
-			return store.GetAccountTransactionSumsRow{
-				TotalIncome:  numericFromString("0.00"),
-				TotalExpense: numericFromString("0.00"),
-			}, nil
-		},
 	}
 
 	svc := &Account{queries: mock}
@@ -205,3 +259,75 @@ func TestAccountUpdate_DuplicateName(t *testing.T) {
 
 	require.ErrorIs(t, err, ErrAccountExists)
 }
+
+func TestAccountReconcileBalances(t *testing.T) {
+	userID := uuid.New()
+	accountID := uuid.New()
+
+	mock := &mockAccountStore{
+		reconcileAccountBalancesFn: func(ctx context.Context, uid uuid.UUID) ([]store.ReconcileAccountBalancesRow, error) {
+			require.Equal(t, userID, uid)
+			return []store.ReconcileAccountBalancesRow{{
+				ID:            accountID,
+				Name:          "Checking",
+				Currency:      "USD",
+				StoredBalance: numericFromString("1250"),
+				Balance:       numericFromString("1300.5"),
+			}}, nil
+		},
+	}
+
+	svc := &Account{queries: mock}
+	result, err := svc.ReconcileBalances(context.Background(), userID)
+
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	require.Equal(t, accountID, result[0].AccountID)
+	require.Equal(t, "1250.00", result[0].StoredBalance)
+	require.Equal(t, "1300.50", result[0].Balance)
+}
+
+func TestAccountArchive(t *testing.T) {
+	userID := uuid.New()
+	accountID := uuid.New()
+
+	var captured store.SetAccountArchivedParams
+	mock := &mockAccountStore{
+		setAccountArchivedFn: func(ctx context.Context, arg store.SetAccountArchivedParams) (store.Account, error) {
+			captured = arg
+			return store.Account{ID: accountID, UserID: userID, Currency: "USD", ArchivedOn: arg.ArchivedOn}, nil
+		},
+	}
+	svc := &Account{queries: mock}
+
+	t.Run("explicit date", func(t *testing.T) {
+		resp, err := svc.Archive(context.Background(), userID, accountID, "2025-06-30")
+		require.NoError(t, err)
+		require.NotNil(t, resp.ArchivedOn)
+		require.Equal(t, "2025-06-30", *resp.ArchivedOn)
+	})
+
+	t.Run("defaults to today", func(t *testing.T) {
+		_, err := svc.Archive(context.Background(), userID, accountID, "")
+		require.NoError(t, err)
+		require.True(t, captured.ArchivedOn.Valid)
+		require.Equal(t, time.Now().Format("2006-01-02"), captured.ArchivedOn.Time.Format("2006-01-02"))
+	})
+
+	t.Run("unarchive", func(t *testing.T) {
+		resp, err := svc.Unarchive(context.Background(), userID, accountID)
+		require.NoError(t, err)
+		require.False(t, captured.ArchivedOn.Valid)
+		require.Nil(t, resp.ArchivedOn)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		svc := &Account{queries: &mockAccountStore{
+			setAccountArchivedFn: func(ctx context.Context, arg store.SetAccountArchivedParams) (store.Account, error) {
+				return store.Account{}, pgx.ErrNoRows
+			},
+		}}
+		_, err := svc.Unarchive(context.Background(), userID, accountID)
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+}
@@ -0,0 +1,308 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/dto"
+	"github.com/sanches/finance-tracker-cc/backend/internal/store"
+)
+
+// Invite code statuses, derived from the code's limits when it is read.
+const (
+	InviteActive  = "active"
+	InviteExpired = "expired"
+	InviteUsedUp  = "used_up"
+	InviteRevoked = "revoked"
+)
+
+var (
+	ErrCannotDisableSelf  = errors.New("admins can't disable their own account")
+	ErrInviteCodeExists   = errors.New("invite code already exists")
+	ErrInviteCodeNotFound = errors.New("invite code not found")
+	ErrInviteExpiry       = errors.New("expires_at must be in the future")
+)
+
+type adminStore interface {
+	ListUsers(ctx context.Context) ([]store.ListUsersRow, error)
+	GetUserByID(ctx context.Context, id uuid.UUID) (store.User, error)
+	SetUserDisabled(ctx context.Context, arg store.SetUserDisabledParams) (store.User, error)
+	UpdateUserPassword(ctx context.Context, arg store.UpdateUserPasswordParams) error
+	PromoteAdmins(ctx context.Context, usernames []string) (int64, error)
+	GetInstanceStats(ctx context.Context) (store.GetInstanceStatsRow, error)
+	DeleteGlobalExchangeRate(ctx context.Context, id uuid.UUID) (int64, error)
+	CreateInviteCode(ctx context.Context, arg store.CreateInviteCodeParams) (store.InviteCode, error)
+	EnsureInviteCode(ctx context.Context, code string) error
+	ListInviteCodes(ctx context.Context) ([]store.InviteCode, error)
+	ListInviteRedemptions(ctx context.Context, codes []string) ([]store.ListInviteRedemptionsRow, error)
+	RevokeInviteCode(ctx context.Context, code string) (store.InviteCode, error)
+}
+
+// Admin manages the instance: its users, invite codes and the global
+// exchange rates.
+// Callers are checked by middleware.Admin; the service itself trusts them.
+type Admin struct {
+	queries adminStore
+}
+
+func NewAdmin(queries *store.Queries) *Admin {
+	return &Admin{queries: queries}
+}
+
+// PromoteAdmins makes the named users admins, returning how many were not
+// admins yet. Usernames that don't exist are ignored.
+func (s *Admin) PromoteAdmins(ctx context.Context, usernames []string) (int64, error) {
+	normalized := make([]string, 0, len(usernames))
+	for _, u := range usernames {
+		normalized = append(normalized, normalizeUsername(u))
+	}
+	return s.queries.PromoteAdmins(ctx, normalized)
+}
+
+func (s *Admin) ListUsers(ctx context.Context) ([]dto.AdminUserResponse, error) {
+	users, err := s.queries.ListUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.AdminUserResponse, 0, len(users))
+	for _, u := range users {
+		resp := dto.AdminUserResponse{
+			ID:               u.ID,
+			Username:         u.Username,
+			DisplayName:      u.DisplayName,
+			BaseCurrency:     u.BaseCurrency,
+			IsAdmin:          u.IsAdmin,
+			AccountCount:     u.AccountCount,
+			TransactionCount: u.TransactionCount,
+			CreatedAt:        u.CreatedAt.Time,
+		}
+		if u.DisabledAt.Valid {
+			resp.DisabledAt = &u.DisabledAt.Time
+		}
+		result = append(result, resp)
+	}
+	return result, nil
+}
+
+// SetDisabled disables or re-enables a user. Disabled users can't log in
+// or refresh their session; access tokens already issued run out on their
+// own. An admin can't disable themselves, so the instance keeps at least
+// one way in.
+func (s *Admin) SetDisabled(ctx context.Context, adminID, userID uuid.UUID, disabled bool) error {
+	if disabled && adminID == userID {
+		return ErrCannotDisableSelf
+	}
+	_, err := s.queries.SetUserDisabled(ctx, store.SetUserDisabledParams{ID: userID, Disabled: disabled})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// ResetPassword sets a new password for a user who has lost theirs.
+func (s *Admin) ResetPassword(ctx context.Context, userID uuid.UUID, req dto.ResetPasswordRequest) error {
+	if _, err := s.queries.GetUserByID(ctx, userID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	return s.queries.UpdateUserPassword(ctx, store.UpdateUserPasswordParams{
+		ID:           userID,
+		PasswordHash: string(hash),
+	})
+}
+
+func (s *Admin) Stats(ctx context.Context) (*dto.InstanceStatsResponse, error) {
+	st, err := s.queries.GetInstanceStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.InstanceStatsResponse{
+		Users:               st.Users,
+		DisabledUsers:       st.DisabledUsers,
+		Admins:              st.Admins,
+		Accounts:            st.Accounts,
+		Transactions:        st.Transactions,
+		Currencies:          st.Currencies,
+		GlobalExchangeRates: st.GlobalExchangeRates,
+		LatestRateDate:      dateToString(st.LatestRateDate),
+	}, nil
+}
+
+// DeleteExchangeRate removes a global rate, e.g. a wrong manual one. A
+// synced rate comes back with the next sync or backfill.
+func (s *Admin) DeleteExchangeRate(ctx context.Context, id uuid.UUID) error {
+	n, err := s.queries.DeleteGlobalExchangeRate(ctx, id)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// EnsureInviteCodes adds the INVITE_CODES setting to the invite_codes
+// table. Codes already there keep their limits and revocation.
+func (s *Admin) EnsureInviteCodes(ctx context.Context, codes []string) error {
+	for _, code := range codes {
+		if err := s.queries.EnsureInviteCode(ctx, code); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Admin) ListInviteCodes(ctx context.Context) ([]dto.InviteCodeResponse, error) {
+	invites, err := s.queries.ListInviteCodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, len(invites))
+	for _, inv := range invites {
+		codes = append(codes, inv.Code)
+	}
+	redemptions, err := s.queries.ListInviteRedemptions(ctx, codes)
+	if err != nil {
+		return nil, err
+	}
+	byCode := redemptionsByCode(redemptions)
+
+	now := time.Now()
+	result := make([]dto.InviteCodeResponse, 0, len(invites))
+	for _, inv := range invites {
+		result = append(result, inviteCodeToResponse(inv, byCode[inv.Code], now))
+	}
+	return result, nil
+}
+
+// CreateInviteCode stores a new code, generating a random one when
+// req.Code is empty.
+func (s *Admin) CreateInviteCode(ctx context.Context, adminID uuid.UUID, req dto.CreateInviteCodeRequest) (*dto.InviteCodeResponse, error) {
+	now := time.Now()
+	var expiresAt pgtype.Timestamptz
+	if req.ExpiresAt != nil {
+		if !req.ExpiresAt.After(now) {
+			return nil, ErrInviteExpiry
+		}
+		expiresAt = pgtype.Timestamptz{Time: *req.ExpiresAt, Valid: true}
+	}
+	var maxUses pgtype.Int4
+	if req.MaxUses != nil {
+		maxUses = pgtype.Int4{Int32: *req.MaxUses, Valid: true}
+	}
+	code := req.Code
+	if code == "" {
+		code = generateInviteCode()
+	}
+
+	inv, err := s.queries.CreateInviteCode(ctx, store.CreateInviteCodeParams{
+		Code:      code,
+		CreatedBy: pgtype.UUID{Bytes: adminID, Valid: true},
+		MaxUses:   maxUses,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		if isDuplicateKey(err) {
+			return nil, ErrInviteCodeExists
+		}
+		return nil, err
+	}
+	resp := inviteCodeToResponse(inv, nil, now)
+	return &resp, nil
+}
+
+// RevokeInviteCode stops a code from being redeemed. Users who already
+// registered with it are not affected.
+func (s *Admin) RevokeInviteCode(ctx context.Context, code string) (*dto.InviteCodeResponse, error) {
+	inv, err := s.queries.RevokeInviteCode(ctx, code)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrInviteCodeNotFound
+		}
+		return nil, err
+	}
+	redemptions, err := s.queries.ListInviteRedemptions(ctx, []string{code})
+	if err != nil {
+		return nil, err
+	}
+	resp := inviteCodeToResponse(inv, redemptionsByCode(redemptions)[code], time.Now())
+	return &resp, nil
+}
+
+func redemptionsByCode(rows []store.ListInviteRedemptionsRow) map[string][]dto.InviteRedemption {
+	byCode := make(map[string][]dto.InviteRedemption)
+	for _, r := range rows {
+		byCode[r.InviteCode.String] = append(byCode[r.InviteCode.String], dto.InviteRedemption{
+			UserID:     r.ID,
+			Username:   r.Username,
+			RedeemedAt: r.CreatedAt.Time,
+		})
+	}
+	return byCode
+}
+
+// inviteStatus mirrors the conditions CreateUser checks when redeeming.
+func inviteStatus(inv store.InviteCode, now time.Time) string {
+	switch {
+	case inv.RevokedAt.Valid:
+		return InviteRevoked
+	case inv.ExpiresAt.Valid && !inv.ExpiresAt.Time.After(now):
+		return InviteExpired
+	case inv.MaxUses.Valid && inv.Uses >= inv.MaxUses.Int32:
+		return InviteUsedUp
+	default:
+		return InviteActive
+	}
+}
+
+func inviteCodeToResponse(inv store.InviteCode, redeemedBy []dto.InviteRedemption, now time.Time) dto.InviteCodeResponse {
+	if redeemedBy == nil {
+		redeemedBy = []dto.InviteRedemption{}
+	}
+	resp := dto.InviteCodeResponse{
+		Code:       inv.Code,
+		Status:     inviteStatus(inv, now),
+		CreatedBy:  nullableToUUID(inv.CreatedBy),
+		Uses:       inv.Uses,
+		RedeemedBy: redeemedBy,
+		CreatedAt:  inv.CreatedAt.Time,
+	}
+	if inv.MaxUses.Valid {
+		resp.MaxUses = &inv.MaxUses.Int32
+	}
+	if inv.ExpiresAt.Valid {
+		resp.ExpiresAt = &inv.ExpiresAt.Time
+	}
+	if inv.RevokedAt.Valid {
+		resp.RevokedAt = &inv.RevokedAt.Time
+	}
+	return resp
+}
+
+// generateInviteCode returns 16 random base32 characters (80 bits).
+func generateInviteCode() string {
+	b := make([]byte, 10)
+	_, _ = rand.Read(b) // never returns an error
+	return base32.StdEncoding.EncodeToString(b)
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/dto"
+	"github.com/sanches/finance-tracker-cc/backend/internal/store"
+)
+
+type mockAdminStore struct {
+	users    map[uuid.UUID]store.User
+	promoted []string
+	hashes   map[uuid.UUID]string
+	invites  []store.CreateInviteCodeParams
+}
+
+func (m *mockAdminStore) ListUsers(ctx context.Context) ([]store.ListUsersRow, error) {
+	return nil, nil
+}
+
+func (m *mockAdminStore) GetUserByID(ctx context.Context, id uuid.UUID) (store.User, error) {
+	u, ok := m.users[id]
+	if !ok {
+		return store.User{}, pgx.ErrNoRows
+	}
+	return u, nil
+}
+
+func (m *mockAdminStore) SetUserDisabled(ctx context.Context, arg store.SetUserDisabledParams) (store.User, error) {
+	u, ok := m.users[arg.ID]
+	if !ok {
+		return store.User{}, pgx.ErrNoRows
+	}
+	u.DisabledAt.Valid = arg.Disabled
+	m.users[arg.ID] = u
+	return u, nil
+}
+
+func (m *mockAdminStore) UpdateUserPassword(ctx context.Context, arg store.UpdateUserPasswordParams) error {
+	m.hashes[arg.ID] = arg.PasswordHash
+	return nil
+}
+
+func (m *mockAdminStore) PromoteAdmins(ctx context.Context, usernames []string) (int64, error) {
+	m.promoted = usernames
+	return int64(len(usernames)), nil
+}
+
+func (m *mockAdminStore) GetInstanceStats(ctx context.Context) (store.GetInstanceStatsRow, error) {
+	return store.GetInstanceStatsRow{}, nil
+}
+
+func (m *mockAdminStore) DeleteGlobalExchangeRate(ctx context.Context, id uuid.UUID) (int64, error) {
+	return 0, nil
+}
+
+func (m *mockAdminStore) CreateInviteCode(ctx context.Context, arg store.CreateInviteCodeParams) (store.InviteCode, error) {
+	m.invites = append(m.invites, arg)
+	return store.InviteCode{Code: arg.Code, CreatedBy: arg.CreatedBy, MaxUses: arg.MaxUses, ExpiresAt: arg.ExpiresAt}, nil
+}
+
+func (m *mockAdminStore) EnsureInviteCode(ctx context.Context, code string) error {
+	return nil
+}
+
+func (m *mockAdminStore) ListInviteCodes(ctx context.Context) ([]store.InviteCode, error) {
+	return nil, nil
+}
+
+func (m *mockAdminStore) ListInviteRedemptions(ctx context.Context, codes []string) ([]store.ListInviteRedemptionsRow, error) {
+	return nil, nil
+}
+
+func (m *mockAdminStore) RevokeInviteCode(ctx context.Context, code string) (store.InviteCode, error) {
+	return store.InviteCode{}, pgx.ErrNoRows
+}
+
+func TestAdminSetDisabled(t *testing.T) {
+	adminID, userID := uuid.New(), uuid.New()
+	mock := &mockAdminStore{users: map[uuid.UUID]store.User{
+		adminID: {ID: adminID, IsAdmin: true},
+		userID:  {ID: userID},
+	}}
+	svc := &Admin{queries: mock}
+	ctx := context.Background()
+
+	require.ErrorIs(t, svc.SetDisabled(ctx, adminID, adminID, true), ErrCannotDisableSelf)
+	require.False(t, mock.users[adminID].DisabledAt.Valid)
+
+	require.NoError(t, svc.SetDisabled(ctx, adminID, userID, true))
+	require.True(t, mock.users[userID].DisabledAt.Valid)
+	require.NoError(t, svc.SetDisabled(ctx, adminID, userID, false))
+	require.False(t, mock.users[userID].DisabledAt.Valid)
+
+	require.ErrorIs(t, svc.SetDisabled(ctx, adminID, uuid.New(), true), ErrUserNotFound)
+}
+
+func TestAdminResetPassword(t *testing.T) {
+	userID := uuid.New()
+	mock := &mockAdminStore{
+		users:  map[uuid.UUID]store.User{userID: {ID: userID}},
+		hashes: map[uuid.UUID]string{},
+	}
+	svc := &Admin{queries: mock}
+
+	err := svc.ResetPassword(context.Background(), userID, dto.ResetPasswordRequest{NewPassword: "a-brand-new-password"})
+	require.NoError(t, err)
+	require.NoError(t, bcrypt.CompareHashAndPassword([]byte(mock.hashes[userID]), []byte("a-brand-new-password")))
+
+	err = svc.ResetPassword(context.Background(), uuid.New(), dto.ResetPasswordRequest{NewPassword: "a-brand-new-password"})
+	require.ErrorIs(t, err, ErrUserNotFound)
+}
+
+func TestAdminPromoteAdmins_NormalizesUsernames(t *testing.T) {
+	mock := &mockAdminStore{}
+	svc := &Admin{queries: mock}
+
+	_, err := svc.PromoteAdmins(context.Background(), []string{"Alice", "ＢＯＢ"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"alice", "bob"}, mock.promoted)
+}
+
+func TestAdminCreateInviteCode(t *testing.T) {
+	adminID := uuid.New()
+	mock := &mockAdminStore{}
+	svc := &Admin{queries: mock}
+	ctx := context.Background()
+
+	t.Run("generated code", func(t *testing.T) {
+		resp, err := svc.CreateInviteCode(ctx, adminID, dto.CreateInviteCodeRequest{})
+		require.NoError(t, err)
+		require.Len(t, resp.Code, 16)
+		require.Equal(t, InviteActive, resp.Status)
+		require.Equal(t, &adminID, resp.CreatedBy)
+		require.Nil(t, resp.MaxUses)
+		require.Empty(t, resp.RedeemedBy)
+	})
+
+	t.Run("limits", func(t *testing.T) {
+		maxUses := int32(3)
+		expires := time.Now().Add(24 * time.Hour)
+		resp, err := svc.CreateInviteCode(ctx, adminID, dto.CreateInviteCodeRequest{Code: "family-2026", MaxUses: &maxUses, ExpiresAt: &expires})
+		require.NoError(t, err)
+		require.Equal(t, "family-2026", resp.Code)
+		require.Equal(t, int32(3), *resp.MaxUses)
+		require.True(t, expires.Equal(*resp.ExpiresAt))
+	})
+
+	t.Run("expiry in the past", func(t *testing.T) {
+		past := time.Now().Add(-time.Minute)
+		_, err := svc.CreateInviteCode(ctx, adminID, dto.CreateInviteCodeRequest{ExpiresAt: &past})
+		require.ErrorIs(t, err, ErrInviteExpiry)
+	})
+
+	_, err := svc.RevokeInviteCode(ctx, "missing")
+	require.ErrorIs(t, err, ErrInviteCodeNotFound)
+}
+
+func TestInviteStatus(t *testing.T) {
+	now := time.Now()
+	at := func(d time.Duration) pgtype.Timestamptz {
+		return pgtype.Timestamptz{Time: now.Add(d), Valid: true}
+	}
+	limit := func(n int32) pgtype.Int4 {
+		return pgtype.Int4{Int32: n, Valid: true}
+	}
+
+	tests := []struct {
+		name string
+		inv  store.InviteCode
+		want string
+	}{
+		{"unlimited", store.InviteCode{Uses: 40}, InviteActive},
+		{"uses left", store.InviteCode{MaxUses: limit(2), Uses: 1}, InviteActive},
+		{"used up", store.InviteCode{MaxUses: limit(2), Uses: 2}, InviteUsedUp},
+		{"not expired yet", store.InviteCode{ExpiresAt: at(time.Hour)}, InviteActive},
+		{"expired", store.InviteCode{ExpiresAt: at(-time.Hour)}, InviteExpired},
+		{"revoked wins", store.InviteCode{RevokedAt: at(-time.Minute), ExpiresAt: at(-time.Hour)}, InviteRevoked},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, inviteStatus(tt.inv, now))
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/dto"
+	"github.com/sanches/finance-tracker-cc/backend/internal/store"
+)
+
+// Audit log actions. Logins are recorded by Auth.Login, the rest by
+// middleware.Audit on their routes.
+const (
+	AuditLogin              = "auth.login"
+	AuditTokenRefresh       = "auth.refresh"
+	AuditPasswordChange     = "user.password_change"
+	AuditReset              = "user.reset"
+	AuditImportCSV          = "import.csv"
+	AuditImportFull         = "import.full"
+	AuditExportCSV          = "export.csv"
+	AuditAccountDelete      = "account.delete"
+	AuditCategoryDelete     = "category.delete"
+	AuditInviteCodeCreate   = "invite_code.create"
+	AuditInviteCodeRevoke   = "invite_code.revoke"
+	AuditUserDisable        = "user.disable"
+	AuditUserEnable         = "user.enable"
+	AuditUserPasswordReset  = "user.password_reset"
+	AuditExchangeRateDelete = "exchange_rate.delete"
+)
+
+type auditStore interface {
+	ListAuditEntries(ctx context.Context, arg store.ListAuditEntriesParams) ([]store.AuditLog, error)
+	CountAuditEntries(ctx context.Context, arg store.CountAuditEntriesParams) (int64, error)
+	PruneAuditLog(ctx context.Context, createdAt pgtype.Timestamptz) (int64, error)
+}
+
+// Audit reads and prunes the audit log. Entries are never changed; they
+// are only deleted once older than the retention period.
+type Audit struct {
+	queries   auditStore
+	retention time.Duration
+}
+
+// NewAudit keeps entries for retention; zero keeps them forever.
+func NewAudit(queries *store.Queries, retention time.Duration) *Audit {
+	return &Audit{queries: queries, retention: retention}
+}
+
+type AuditListParams struct {
+	Action  string
+	Page    int
+	PerPage int
+}
+
+// List returns the user's audit log, newest first, optionally filtered by
+// action.
+func (s *Audit) List(ctx context.Context, userID uuid.UUID, params AuditListParams) (*dto.PaginatedResponse, error) {
+	if params.Page < 1 {
+		params.Page = 1
+	}
+	if params.Page > 10000 {
+		params.Page = 10000
+	}
+	if params.PerPage < 1 || params.PerPage > 100 {
+		params.PerPage = 20
+	}
+	action := pgtype.Text{String: params.Action, Valid: params.Action != ""}
+
+	entries, err := s.queries.ListAuditEntries(ctx, store.ListAuditEntriesParams{
+		UserID: userID,
+		Action: action,
+		Off:    int32((params.Page - 1) * params.PerPage),
+		Lim:    int32(params.PerPage),
+	})
+	if err != nil {
+		return nil, err
+	}
+	total, err := s.queries.CountAuditEntries(ctx, store.CountAuditEntriesParams{UserID: userID, Action: action})
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]dto.AuditEntryResponse, 0, len(entries))
+	for _, e := range entries {
+		meta := map[string]string{}
+		if err := json.Unmarshal(e.Metadata, &meta); err != nil {
+			return nil, err
+		}
+		data = append(data, dto.AuditEntryResponse{
+			ID:        e.ID,
+			Action:    e.Action,
+			ActorID:   e.ActorID,
+			IP:        e.Ip,
+			UserAgent: e.UserAgent,
+			Metadata:  meta,
+			CreatedAt: e.CreatedAt.Time,
+		})
+	}
+
+	return &dto.PaginatedResponse{
+		Data: data,
+		Pagination: dto.Pagination{
+			Page:    params.Page,
+			PerPage: params.PerPage,
+			Total:   total,
+		},
+	}, nil
+}
+
+// Prune deletes entries older than the retention period. It runs daily.
+func (s *Audit) Prune(ctx context.Context) error {
+	if s.retention <= 0 {
+		return nil
+	}
+	n, err := s.queries.PruneAuditLog(ctx, pgtype.Timestamptz{Time: time.Now().Add(-s.retention), Valid: true})
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		slog.Info("pruned audit log", "deleted", n)
+	}
+	return nil
+}
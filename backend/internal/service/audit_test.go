@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/dto"
+	"github.com/sanches/finance-tracker-cc/backend/internal/store"
+)
+
+type mockAuditStore struct {
+	entries    []store.AuditLog
+	listParams store.ListAuditEntriesParams
+	prunedTo   *time.Time
+}
+
+func (m *mockAuditStore) ListAuditEntries(ctx context.Context, arg store.ListAuditEntriesParams) ([]store.AuditLog, error) {
+	m.listParams = arg
+	return m.entries, nil
+}
+
+func (m *mockAuditStore) CountAuditEntries(ctx context.Context, arg store.CountAuditEntriesParams) (int64, error) {
+	return int64(len(m.entries)), nil
+}
+
+func (m *mockAuditStore) PruneAuditLog(ctx context.Context, createdAt pgtype.Timestamptz) (int64, error) {
+	m.prunedTo = &createdAt.Time
+	return 0, nil
+}
+
+func TestAuditList(t *testing.T) {
+	userID := uuid.New()
+	m := &mockAuditStore{entries: []store.AuditLog{{
+		ID:       uuid.New(),
+		UserID:   userID,
+		ActorID:  userID,
+		Action:   AuditAccountDelete,
+		Metadata: []byte(`{"id":"abc"}`),
+	}}}
+	svc := &Audit{queries: m}
+
+	res, err := svc.List(context.Background(), userID, AuditListParams{Action: AuditAccountDelete, Page: 2, PerPage: 500})
+	require.NoError(t, err)
+
+	require.Equal(t, pgtype.Text{String: AuditAccountDelete, Valid: true}, m.listParams.Action)
+	require.Equal(t, int32(20), m.listParams.Lim, "out-of-range per_page falls back to the default")
+	require.Equal(t, int32(20), m.listParams.Off)
+	require.Equal(t, dto.Pagination{Page: 2, PerPage: 20, Total: 1}, res.Pagination)
+	entries := res.Data.([]dto.AuditEntryResponse)
+	require.Len(t, entries, 1)
+	require.Equal(t, map[string]string{"id": "abc"}, entries[0].Metadata)
+
+	_, err = svc.List(context.Background(), userID, AuditListParams{})
+	require.NoError(t, err)
+	require.False(t, m.listParams.Action.Valid, "no action filter")
+}
+
+func TestAuditPrune(t *testing.T) {
+	m := &mockAuditStore{}
+
+	require.NoError(t, (&Audit{queries: m}).Prune(context.Background()))
+	require.Nil(t, m.prunedTo, "zero retention keeps everything")
+
+	require.NoError(t, (&Audit{queries: m, retention: 30 * 24 * time.Hour}).Prune(context.Background()))
+	require.NotNil(t, m.prunedTo)
+	require.WithinDuration(t, time.Now().Add(-30*24*time.Hour), *m.prunedTo, time.Minute)
+}
@@ -3,7 +3,7 @@ package service
 import (
 	"context"
 	"errors"
-	"slices"
+	"log/slog"
 	"strings"
 	"time"
 
@@ -11,6 +11,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"golang.org/x/crypto/bcrypt"
 	"golang.org/x/text/unicode/norm"
 
@@ -29,6 +30,8 @@ var (
 	ErrInvalidCredentials = errors.New("invalid username or password")
 	ErrInvalidToken       = errors.New("invalid or expired token")
 	ErrInvalidInviteCode  = errors.New("invalid invite code")
+	ErrAccountDisabled    = errors.New("account is disabled")
+	ErrLoginLocked        = errors.New("too many failed login attempts")
 
 	// dummyHash is a pre-computed bcrypt hash used when the requested user does
 	// not exist.  Running CompareHashAndPassword against it ensures the login
@@ -42,12 +45,58 @@ type authStore interface {
 	GetUserByUsername(ctx context.Context, username string) (store.User, error)
 	GetUserByID(ctx context.Context, id uuid.UUID) (store.User, error)
 	CreateDefaultCategories(ctx context.Context, userID uuid.UUID) error
+	GetLoginFailures(ctx context.Context, arg store.GetLoginFailuresParams) (store.GetLoginFailuresRow, error)
+	RecordLoginAttempt(ctx context.Context, arg store.RecordLoginAttemptParams) error
+	CreateAuditEntry(ctx context.Context, arg store.CreateAuditEntryParams) error
+	PruneLoginAttempts(ctx context.Context, createdAt pgtype.Timestamptz) (int64, error)
+	WithTx(tx pgx.Tx) *store.Queries
+}
+
+// Login attempt outcomes, as stored in login_attempts.
+const (
+	LoginSuccess  = "success"
+	LoginFailure  = "failure"
+	LoginLocked   = "locked"
+	LoginDisabled = "disabled"
+)
+
+// Login throttling is per username, on top of the per-IP rate limit, so
+// guessing one account's password from many addresses is slowed down too.
+// The first loginFreeAttempts failures are free; after that each failure
+// locks the username for loginBaseDelay, doubling per further failure up to
+// loginMaxDelay. Failures older than loginFailureWindow or before the last
+// successful login don't count.
+const (
+	loginFreeAttempts  = 5
+	loginBaseDelay     = 30 * time.Second
+	loginMaxDelay      = time.Hour
+	loginFailureWindow = 24 * time.Hour
+)
+
+// LoginLockedError is returned by Login while a username is locked out.
+// It wraps ErrLoginLocked.
+type LoginLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginLockedError) Error() string { return ErrLoginLocked.Error() }
+
+func (e *LoginLockedError) Unwrap() error { return ErrLoginLocked }
+
+// LoginClient identifies where a login attempt came from, for the login
+// history.
+type LoginClient struct {
+	IP        string
+	UserAgent string
 }
 
 type Auth struct {
-	queries     authStore
-	secret      []byte
-	inviteCodes []string
+	queries authStore
+	pool    *pgxpool.Pool
+	secret  []byte
+	// loginRetention is how long login attempts are kept; zero keeps them
+	// forever.
+	loginRetention time.Duration
 }
 
 // AuthResult carries the output of a successful auth operation. The refresh
@@ -59,34 +108,31 @@ type AuthResult struct {
 	User         dto.UserResponse
 }
 
-func NewAuth(queries *store.Queries, secret string, inviteCodes []string) *Auth {
-	return &Auth{queries: queries, secret: []byte(secret), inviteCodes: inviteCodes}
+func NewAuth(queries *store.Queries, pool *pgxpool.Pool, secret string, loginRetention time.Duration) *Auth {
+	return &Auth{queries: queries, pool: pool, secret: []byte(secret), loginRetention: loginRetention}
 }
 
 func (s *Auth) Register(ctx context.Context, req dto.RegisterRequest) (*AuthResult, error) {
 	req.Username = normalizeUsername(req.Username)
 
-	validInvite := slices.Contains(s.inviteCodes, req.InviteCode)
-
-	// Always run bcrypt so the response time doesn't reveal whether the
-	// invite code was valid.
+	// Hash before the invite code is checked so the response time doesn't
+	// reveal whether it was valid.
 	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
 	if err != nil {
 		return nil, err
 	}
 
-	if !validInvite {
-		return nil, ErrInvalidInviteCode
-	}
-
 	user, err := s.queries.CreateUser(ctx, store.CreateUserParams{
+		InviteCode:   req.InviteCode,
 		Username:     req.Username,
 		PasswordHash: string(hash),
 		DisplayName:  req.DisplayName,
 		BaseCurrency: req.BaseCurrency,
-		InviteCode:   pgtype.Text{String: req.InviteCode, Valid: true},
 	})
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrInvalidInviteCode
+		}
 		if isDuplicateKey(err) {
 			return nil, ErrUserExists
 		}
@@ -99,27 +145,149 @@ func (s *Auth) Register(ctx context.Context, req dto.RegisterRequest) (*AuthResu
 	return s.generateAuthResponse(user)
 }
 
-func (s *Auth) Login(ctx context.Context, req dto.LoginRequest) (*AuthResult, error) {
+// Login checks the credentials and records the attempt. Attempts on one
+// username run one at a time under an advisory lock, so a burst of
+// concurrent guesses can't all pass the throttle before the first failure
+// is recorded.
+func (s *Auth) Login(ctx context.Context, req dto.LoginRequest, client LoginClient) (*AuthResult, error) {
 	req.Username = normalizeUsername(req.Username)
+	if s.pool == nil {
+		return s.login(ctx, s.queries, req, client)
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	q := s.queries.WithTx(tx)
+	if err := q.LockLoginUsername(ctx, req.Username); err != nil {
+		return nil, err
+	}
+
+	result, err := s.login(ctx, q, req, client)
+	// A refused login still commits, keeping its attempt on record.
+	if err != nil && !errors.Is(err, ErrInvalidCredentials) && !errors.Is(err, ErrAccountDisabled) && !errors.Is(err, ErrLoginLocked) {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return result, err
+}
 
-	user, err := s.queries.GetUserByUsername(ctx, req.Username)
+func (s *Auth) login(ctx context.Context, q authStore, req dto.LoginRequest, client LoginClient) (*AuthResult, error) {
+	now := time.Now()
+	failures, err := q.GetLoginFailures(ctx, store.GetLoginFailuresParams{
+		Username: req.Username,
+		Since:    pgtype.Timestamptz{Time: now.Add(-loginFailureWindow), Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if failures.LastFailure.Valid {
+		unlockAt := failures.LastFailure.Time.Add(loginDelay(failures.Failures))
+		if now.Before(unlockAt) {
+			// Skip the password check entirely, so a locked account can't be
+			// guessed at however fast the attempts come.
+			var userID pgtype.UUID
+			if user, err := q.GetUserByUsername(ctx, req.Username); err == nil {
+				userID = pgtype.UUID{Bytes: user.ID, Valid: true}
+			} else if !errors.Is(err, pgx.ErrNoRows) {
+				return nil, err
+			}
+			if err := recordLogin(ctx, q, req.Username, userID, client, LoginLocked); err != nil {
+				return nil, err
+			}
+			return nil, &LoginLockedError{RetryAfter: unlockAt.Sub(now)}
+		}
+	}
+
+	user, err := q.GetUserByUsername(ctx, req.Username)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			// Run a dummy compare so the response time doesn't reveal
 			// whether the username exists.
 			bcrypt.CompareHashAndPassword(dummyHash, []byte(req.Password)) //nolint:errcheck
+			// Unknown usernames are throttled like real ones, for the same
+			// reason.
+			if err := recordLogin(ctx, q, req.Username, pgtype.UUID{}, client, LoginFailure); err != nil {
+				return nil, err
+			}
 			return nil, ErrInvalidCredentials
 		}
 		return nil, err
 	}
+	userID := pgtype.UUID{Bytes: user.ID, Valid: true}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
+		if err := recordLogin(ctx, q, req.Username, userID, client, LoginFailure); err != nil {
+			return nil, err
+		}
 		return nil, ErrInvalidCredentials
 	}
+	if user.DisabledAt.Valid {
+		if err := recordLogin(ctx, q, req.Username, userID, client, LoginDisabled); err != nil {
+			return nil, err
+		}
+		return nil, ErrAccountDisabled
+	}
 
+	if err := recordLogin(ctx, q, req.Username, userID, client, LoginSuccess); err != nil {
+		return nil, err
+	}
+	if err := q.CreateAuditEntry(ctx, store.CreateAuditEntryParams{
+		UserID:    user.ID,
+		ActorID:   user.ID,
+		Action:    AuditLogin,
+		Ip:        client.IP,
+		UserAgent: client.UserAgent,
+		Metadata:  []byte("{}"),
+	}); err != nil {
+		return nil, err
+	}
 	return s.generateAuthResponse(user)
 }
 
+func recordLogin(ctx context.Context, q authStore, username string, userID pgtype.UUID, client LoginClient, outcome string) error {
+	return q.RecordLoginAttempt(ctx, store.RecordLoginAttemptParams{
+		Username:  username,
+		UserID:    userID,
+		Ip:        client.IP,
+		UserAgent: client.UserAgent,
+		Outcome:   outcome,
+	})
+}
+
+// PruneLoginAttempts deletes login attempts older than the retention period,
+// which is whole days and so always covers loginFailureWindow.
+func (s *Auth) PruneLoginAttempts(ctx context.Context) error {
+	if s.loginRetention <= 0 {
+		return nil
+	}
+	n, err := s.queries.PruneLoginAttempts(ctx, pgtype.Timestamptz{Time: time.Now().Add(-s.loginRetention), Valid: true})
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		slog.Info("pruned login attempts", "deleted", n)
+	}
+	return nil
+}
+
+// loginDelay is how long a username stays locked after its latest failure,
+// given the number of recent failures.
+func loginDelay(failures int64) time.Duration {
+	if failures < loginFreeAttempts {
+		return 0
+	}
+	delay := loginBaseDelay
+	for i := int64(loginFreeAttempts); i < failures && delay < loginMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, loginMaxDelay)
+}
+
 func (s *Auth) Refresh(ctx context.Context, tokenStr string) (*AuthResult, error) {
 	token, err := jwt.Parse(tokenStr, func(t *jwt.Token) (any, error) {
 		return s.secret, nil
@@ -148,6 +316,9 @@ func (s *Auth) Refresh(ctx context.Context, tokenStr string) (*AuthResult, error
 	if err != nil {
 		return nil, ErrInvalidToken
 	}
+	if user.DisabledAt.Valid {
+		return nil, ErrAccountDisabled
+	}
 
 	return s.generateAuthResponse(user)
 }
@@ -171,6 +342,7 @@ func (s *Auth) generateAuthResponse(user store.User) (*AuthResult, error) {
 			Username:     user.Username,
 			DisplayName:  user.DisplayName,
 			BaseCurrency: user.BaseCurrency,
+			IsAdmin:      user.IsAdmin,
 			CreatedAt:    user.CreatedAt.Time,
 		},
 	}, nil
@@ -9,6 +9,7 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/crypto/bcrypt"
 
@@ -18,13 +19,15 @@ import (
 
 const testAuthSecret = "test-auth-secret-at-least-32-chars!"
 
-var testInviteCodes = []string{"valid-code", "another-code"}
-
 type mockAuthStore struct {
 	createUserFn             func(ctx context.Context, arg store.CreateUserParams) (store.User, error)
 	getUserByUsernameFn      func(ctx context.Context, username string) (store.User, error)
 	getUserByIDFn            func(ctx context.Context, id uuid.UUID) (store.User, error)
 	createDefaultCategoriesFn func(ctx context.Context, userID uuid.UUID) error
+	getLoginFailuresFn       func(ctx context.Context, arg store.GetLoginFailuresParams) (store.GetLoginFailuresRow, error)
+	recordLoginAttemptFn     func(ctx context.Context, arg store.RecordLoginAttemptParams) error
+	createAuditEntryFn       func(ctx context.Context, arg store.CreateAuditEntryParams) error
+	prunedTo                 *time.Time
 }
 
 func (m *mockAuthStore) CreateUser(ctx context.Context, arg store.CreateUserParams) (store.User, error) {
@@ -39,6 +42,31 @@ func (m *mockAuthStore) GetUserByID(ctx context.Context, id uuid.UUID) (store.Us
 func (m *mockAuthStore) CreateDefaultCategories(ctx context.Context, userID uuid.UUID) error {
 	return m.createDefaultCategoriesFn(ctx, userID)
 }
+func (m *mockAuthStore) GetLoginFailures(ctx context.Context, arg store.GetLoginFailuresParams) (store.GetLoginFailuresRow, error) {
+	if m.getLoginFailuresFn == nil {
+		return store.GetLoginFailuresRow{}, nil
+	}
+	return m.getLoginFailuresFn(ctx, arg)
+}
+func (m *mockAuthStore) RecordLoginAttempt(ctx context.Context, arg store.RecordLoginAttemptParams) error {
+	if m.recordLoginAttemptFn == nil {
+		return nil
+	}
+	return m.recordLoginAttemptFn(ctx, arg)
+}
+func (m *mockAuthStore) PruneLoginAttempts(ctx context.Context, createdAt pgtype.Timestamptz) (int64, error) {
+	m.prunedTo = &createdAt.Time
+	return 0, nil
+}
+func (m *mockAuthStore) WithTx(tx pgx.Tx) *store.Queries {
+	return nil
+}
+func (m *mockAuthStore) CreateAuditEntry(ctx context.Context, arg store.CreateAuditEntryParams) error {
+	if m.createAuditEntryFn == nil {
+		return nil
+	}
+	return m.createAuditEntryFn(ctx, arg)
+}
 
 func testUser(password string) store.User {
 	hash, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
@@ -67,7 +95,7 @@ func TestLogin_Success(t *testing.T) {
 	resp, err := svc.Login(context.Background(), dto.LoginRequest{
 		Username: "testuser",
 		Password: password,
-	})
+	}, LoginClient{})
 
 	require.NoError(t, err)
 	require.NotEmpty(t, resp.AccessToken)
@@ -89,7 +117,7 @@ func TestLogin_WrongPassword(t *testing.T) {
 	_, err := svc.Login(context.Background(), dto.LoginRequest{
 		Username: "testuser",
 		Password: "wrong-password",
-	})
+	}, LoginClient{})
 
 	require.ErrorIs(t, err, ErrInvalidCredentials)
 }
@@ -105,7 +133,7 @@ func TestLogin_UserNotFound(t *testing.T) {
 	_, err := svc.Login(context.Background(), dto.LoginRequest{
 		Username: "nonexistent",
 		Password: "any",
-	})
+	}, LoginClient{})
 
 	require.ErrorIs(t, err, ErrInvalidCredentials)
 }
@@ -117,7 +145,7 @@ func TestRegister_DuplicateUser(t *testing.T) {
 		},
 	}
 
-	svc := &Auth{queries: mock, secret: []byte(testAuthSecret), inviteCodes: testInviteCodes}
+	svc := &Auth{queries: mock, secret: []byte(testAuthSecret)}
 	_, err := svc.Register(context.Background(), dto.RegisterRequest{
 		Username:     "existing",
 		Password:     "Str0ng-Pass!phrase",
@@ -130,9 +158,15 @@ func TestRegister_DuplicateUser(t *testing.T) {
 }
 
 func TestRegister_InvalidInviteCode(t *testing.T) {
-	mock := &mockAuthStore{}
+	// CreateUser returns no row when the code can't be redeemed.
+	mock := &mockAuthStore{
+		createUserFn: func(ctx context.Context, arg store.CreateUserParams) (store.User, error) {
+			require.Equal(t, "wrong-code", arg.InviteCode)
+			return store.User{}, pgx.ErrNoRows
+		},
+	}
 
-	svc := &Auth{queries: mock, secret: []byte(testAuthSecret), inviteCodes: testInviteCodes}
+	svc := &Auth{queries: mock, secret: []byte(testAuthSecret)}
 	_, err := svc.Register(context.Background(), dto.RegisterRequest{
 		Username:     "newuser",
 		Password:     "Str0ng-Pass!phrase",
@@ -156,7 +190,7 @@ func TestRegister_ValidInviteCode(t *testing.T) {
 		},
 	}
 
-	svc := &Auth{queries: mock, secret: []byte(testAuthSecret), inviteCodes: testInviteCodes}
+	svc := &Auth{queries: mock, secret: []byte(testAuthSecret)}
 	resp, err := svc.Register(context.Background(), dto.RegisterRequest{
 		Username:     "newuser",
 		Password:     "Str0ng-Pass!phrase",
@@ -191,6 +225,142 @@ func TestRefresh_ValidToken(t *testing.T) {
 	require.NotEmpty(t, resp.RefreshToken)
 }
 
+func TestDisabledUser(t *testing.T) {
+	password := "correct-password"
+	user := testUser(password)
+	user.DisabledAt = makeTimestamp()
+	mock := &mockAuthStore{
+		getUserByUsernameFn: func(ctx context.Context, username string) (store.User, error) {
+			return user, nil
+		},
+		getUserByIDFn: func(ctx context.Context, id uuid.UUID) (store.User, error) {
+			return user, nil
+		},
+	}
+	svc := &Auth{queries: mock, secret: []byte(testAuthSecret)}
+
+	t.Run("login", func(t *testing.T) {
+		_, err := svc.Login(context.Background(), dto.LoginRequest{Username: "testuser", Password: password}, LoginClient{})
+		require.ErrorIs(t, err, ErrAccountDisabled)
+	})
+
+	t.Run("wrong password is not told the account is disabled", func(t *testing.T) {
+		_, err := svc.Login(context.Background(), dto.LoginRequest{Username: "testuser", Password: "wrong-password"}, LoginClient{})
+		require.ErrorIs(t, err, ErrInvalidCredentials)
+	})
+
+	t.Run("refresh", func(t *testing.T) {
+		refreshToken, err := svc.generateToken(user.ID, "refresh", 7*24*time.Hour)
+		require.NoError(t, err)
+		_, err = svc.Refresh(context.Background(), refreshToken)
+		require.ErrorIs(t, err, ErrAccountDisabled)
+	})
+}
+
+func TestLogin_RecordsAttempts(t *testing.T) {
+	password := "correct-password"
+	user := testUser(password)
+	var outcomes, audited []string
+	mock := &mockAuthStore{
+		getUserByUsernameFn: func(ctx context.Context, username string) (store.User, error) {
+			if username != user.Username {
+				return store.User{}, pgx.ErrNoRows
+			}
+			return user, nil
+		},
+		recordLoginAttemptFn: func(_ context.Context, arg store.RecordLoginAttemptParams) error {
+			require.Equal(t, "192.0.2.1", arg.Ip)
+			require.Equal(t, "test-agent", arg.UserAgent)
+			require.Equal(t, arg.Username != "nobody", arg.UserID.Valid)
+			outcomes = append(outcomes, arg.Outcome)
+			return nil
+		},
+		createAuditEntryFn: func(_ context.Context, arg store.CreateAuditEntryParams) error {
+			require.Equal(t, user.ID, arg.UserID)
+			require.Equal(t, "192.0.2.1", arg.Ip)
+			audited = append(audited, arg.Action)
+			return nil
+		},
+	}
+	svc := &Auth{queries: mock, secret: []byte(testAuthSecret)}
+	client := LoginClient{IP: "192.0.2.1", UserAgent: "test-agent"}
+
+	_, err := svc.Login(context.Background(), dto.LoginRequest{Username: "testuser", Password: "wrong-password"}, client)
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+	_, err = svc.Login(context.Background(), dto.LoginRequest{Username: "nobody", Password: password}, client)
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+	_, err = svc.Login(context.Background(), dto.LoginRequest{Username: "testuser", Password: password}, client)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{LoginFailure, LoginFailure, LoginSuccess}, outcomes)
+	require.Equal(t, []string{AuditLogin}, audited, "only the successful login is audited")
+}
+
+func TestLogin_Locked(t *testing.T) {
+	password := "correct-password"
+	user := testUser(password)
+	lastFailure := time.Now().Add(-10 * time.Second)
+	var outcomes []string
+	mock := &mockAuthStore{
+		getUserByUsernameFn: func(ctx context.Context, username string) (store.User, error) {
+			return user, nil
+		},
+		getLoginFailuresFn: func(_ context.Context, arg store.GetLoginFailuresParams) (store.GetLoginFailuresRow, error) {
+			require.Equal(t, "testuser", arg.Username)
+			return store.GetLoginFailuresRow{
+				Failures:    loginFreeAttempts,
+				LastFailure: pgtype.Timestamptz{Time: lastFailure, Valid: true},
+			}, nil
+		},
+		recordLoginAttemptFn: func(_ context.Context, arg store.RecordLoginAttemptParams) error {
+			outcomes = append(outcomes, arg.Outcome)
+			return nil
+		},
+	}
+	svc := &Auth{queries: mock, secret: []byte(testAuthSecret)}
+
+	// Even the right password is refused while the username is locked.
+	_, err := svc.Login(context.Background(), dto.LoginRequest{Username: "TestUser", Password: password}, LoginClient{})
+	require.ErrorIs(t, err, ErrLoginLocked)
+	var locked *LoginLockedError
+	require.ErrorAs(t, err, &locked)
+	require.InDelta(t, (20 * time.Second).Seconds(), locked.RetryAfter.Seconds(), 1)
+	require.Equal(t, []string{LoginLocked}, outcomes)
+
+	// Once the delay has passed the next attempt is checked normally.
+	lastFailure = time.Now().Add(-time.Minute)
+	_, err = svc.Login(context.Background(), dto.LoginRequest{Username: "testuser", Password: password}, LoginClient{})
+	require.NoError(t, err)
+}
+
+func TestLoginDelay(t *testing.T) {
+	tests := []struct {
+		failures int64
+		want     time.Duration
+	}{
+		{0, 0},
+		{loginFreeAttempts - 1, 0},
+		{loginFreeAttempts, loginBaseDelay},
+		{loginFreeAttempts + 1, 2 * loginBaseDelay},
+		{loginFreeAttempts + 3, 8 * loginBaseDelay},
+		{loginFreeAttempts + 50, loginMaxDelay},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.want, loginDelay(tt.failures), "failures=%d", tt.failures)
+	}
+}
+
+func TestPruneLoginAttempts(t *testing.T) {
+	m := &mockAuthStore{}
+
+	require.NoError(t, (&Auth{queries: m}).PruneLoginAttempts(context.Background()))
+	require.Nil(t, m.prunedTo, "zero retention keeps everything")
+
+	require.NoError(t, (&Auth{queries: m, loginRetention: 90 * 24 * time.Hour}).PruneLoginAttempts(context.Background()))
+	require.NotNil(t, m.prunedTo)
+	require.WithinDuration(t, time.Now().Add(-90*24*time.Hour), *m.prunedTo, time.Minute)
+}
+
 func TestRegister_NormalizesUsername(t *testing.T) {
 	user := testUser("Str0ng-Pass!phrase")
 
@@ -205,7 +375,7 @@ func TestRegister_NormalizesUsername(t *testing.T) {
 		},
 	}
 
-	svc := &Auth{queries: mock, secret: []byte(testAuthSecret), inviteCodes: testInviteCodes}
+	svc := &Auth{queries: mock, secret: []byte(testAuthSecret)}
 	_, err := svc.Register(context.Background(), dto.RegisterRequest{
 		Username:     "AlIcE",
 		Password:     "Str0ng-Pass!phrase",
@@ -234,7 +404,7 @@ func TestLogin_NormalizesUsername(t *testing.T) {
 	_, err := svc.Login(context.Background(), dto.LoginRequest{
 		Username: "TestUser",
 		Password: password,
-	})
+	}, LoginClient{})
 
 	require.NoError(t, err)
 	require.Equal(t, "testuser", capturedUsername, "username should be normalized to lowercase")
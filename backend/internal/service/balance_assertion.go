@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/dto"
+	"github.com/sanches/finance-tracker-cc/backend/internal/store"
+)
+
+var (
+	ErrBalanceAssertionExists  = errors.New("account already has a balance assertion for that date")
+	ErrBalanceAssertionHolds   = errors.New("balance assertion already holds, nothing to adjust")
+	ErrInvalidBalanceAssertion = errors.New("date must be YYYY-MM-DD and balance a decimal number")
+)
+
+// BalanceAdjustmentDescription is the description of transactions created by
+// BalanceAssertion.Adjust.
+const BalanceAdjustmentDescription = "Balance adjustment"
+
+type balanceAssertionStore interface {
+	CreateBalanceAssertion(ctx context.Context, arg store.CreateBalanceAssertionParams) (store.BalanceAssertion, error)
+	GetBalanceAssertion(ctx context.Context, arg store.GetBalanceAssertionParams) (store.BalanceAssertion, error)
+	ListBalanceAssertions(ctx context.Context, arg store.ListBalanceAssertionsParams) ([]store.ListBalanceAssertionsRow, error)
+	DeleteBalanceAssertion(ctx context.Context, arg store.DeleteBalanceAssertionParams) (int64, error)
+	GetAccountBalanceAt(ctx context.Context, arg store.GetAccountBalanceAtParams) (pgtype.Numeric, error)
+	GetAccount(ctx context.Context, arg store.GetAccountParams) (store.Account, error)
+}
+
+// BalanceAssertion records checkpoints such as "on 2026-01-01 this account
+// held 12,345.67" and compares them with the balance computed from
+// transactions at the end of that day.
+type BalanceAssertion struct {
+	queries      balanceAssertionStore
+	currencies   *CurrencyPrecision
+	transactions *Transaction
+}
+
+func NewBalanceAssertion(queries *store.Queries, currencies *CurrencyPrecision, transactions *Transaction) *BalanceAssertion {
+	return &BalanceAssertion{queries: queries, currencies: currencies, transactions: transactions}
+}
+
+func (s *BalanceAssertion) Create(ctx context.Context, userID uuid.UUID, req dto.CreateBalanceAssertionRequest) (*dto.BalanceAssertionResponse, error) {
+	date, err := dateFromString(req.Date)
+	if err != nil {
+		return nil, ErrInvalidBalanceAssertion
+	}
+	balance := numericFromString(req.Balance)
+	if !balance.Valid {
+		return nil, ErrInvalidBalanceAssertion
+	}
+	acct, err := s.account(ctx, userID, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	prec, err := s.currencies.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := prec.Check(req.Balance, acct.Currency); err != nil {
+		return nil, err
+	}
+
+	a, err := s.queries.CreateBalanceAssertion(ctx, store.CreateBalanceAssertionParams{
+		UserID:    userID,
+		AccountID: acct.ID,
+		Date:      date,
+		Balance:   balance,
+		Note:      req.Note,
+	})
+	if err != nil {
+		if isDuplicateKey(err) {
+			return nil, ErrBalanceAssertionExists
+		}
+		return nil, err
+	}
+	computed, err := s.balanceAt(ctx, a)
+	if err != nil {
+		return nil, err
+	}
+	return s.toResponse(a, acct.Currency, computed, prec)
+}
+
+// List returns the user's assertions, optionally for one account, each with
+// the computed balance and the difference.
+func (s *BalanceAssertion) List(ctx context.Context, userID uuid.UUID, accountID *uuid.UUID) ([]dto.BalanceAssertionResponse, error) {
+	return s.list(ctx, userID, accountID, false)
+}
+
+// Check returns only the assertions whose computed balance disagrees.
+func (s *BalanceAssertion) Check(ctx context.Context, userID uuid.UUID, accountID *uuid.UUID) ([]dto.BalanceAssertionResponse, error) {
+	return s.list(ctx, userID, accountID, true)
+}
+
+func (s *BalanceAssertion) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	n, err := s.queries.DeleteBalanceAssertion(ctx, store.DeleteBalanceAssertionParams{ID: id, UserID: userID})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Adjust makes an assertion hold by booking the difference as an
+// uncategorized income or expense on the asserted date. Later assertions on
+// the same account shift by the same amount. The adjustment is created like
+// any other transaction, so it is subject to the same account checks.
+func (s *BalanceAssertion) Adjust(ctx context.Context, userID, id uuid.UUID) (*dto.TransactionResponse, error) {
+	a, err := s.queries.GetBalanceAssertion(ctx, store.GetBalanceAssertionParams{ID: id, UserID: userID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	acct, err := s.account(ctx, userID, a.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	computed, err := s.balanceAt(ctx, a)
+	if err != nil {
+		return nil, err
+	}
+	diff, err := MoneyFromNumeric(a.Balance, acct.Currency).Sub(MoneyFromNumeric(computed, acct.Currency))
+	if err != nil {
+		return nil, err
+	}
+	if diff.IsZero() {
+		return nil, ErrBalanceAssertionHolds
+	}
+
+	txnType := "income"
+	if diff.Sign() < 0 {
+		txnType = "expense"
+		diff = diff.Neg()
+	}
+	return s.transactions.Create(ctx, userID, dto.CreateTransactionRequest{
+		AccountID:   a.AccountID,
+		Type:        txnType,
+		Amount:      diff.Amount().String(),
+		Description: BalanceAdjustmentDescription,
+		Date:        dateToString(a.Date),
+	})
+}
+
+func (s *BalanceAssertion) list(ctx context.Context, userID uuid.UUID, accountID *uuid.UUID, failingOnly bool) ([]dto.BalanceAssertionResponse, error) {
+	params := store.ListBalanceAssertionsParams{UserID: userID}
+	if accountID != nil {
+		params.AccountID = pgtype.UUID{Bytes: *accountID, Valid: true}
+	}
+	rows, err := s.queries.ListBalanceAssertions(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	prec, err := s.currencies.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.BalanceAssertionResponse, 0, len(rows))
+	for _, r := range rows {
+		if failingOnly && numericToDecimal(r.Balance).Equal(numericToDecimal(r.ComputedBalance)) {
+			continue
+		}
+		resp, err := s.toResponse(store.BalanceAssertion{
+			ID:        r.ID,
+			UserID:    r.UserID,
+			AccountID: r.AccountID,
+			Date:      r.Date,
+			Balance:   r.Balance,
+			Note:      r.Note,
+			CreatedAt: r.CreatedAt,
+		}, r.Currency, r.ComputedBalance, prec)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *resp)
+	}
+	return result, nil
+}
+
+func (s *BalanceAssertion) balanceAt(ctx context.Context, a store.BalanceAssertion) (pgtype.Numeric, error) {
+	return s.queries.GetAccountBalanceAt(ctx, store.GetAccountBalanceAtParams{
+		Date:      a.Date,
+		AccountID: a.AccountID,
+		UserID:    a.UserID,
+	})
+}
+
+func (s *BalanceAssertion) account(ctx context.Context, userID, accountID uuid.UUID) (store.Account, error) {
+	acct, err := s.queries.GetAccount(ctx, store.GetAccountParams{ID: accountID, UserID: userID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return store.Account{}, ErrAccountNotFound
+		}
+		return store.Account{}, err
+	}
+	return acct, nil
+}
+
+func (s *BalanceAssertion) toResponse(a store.BalanceAssertion, currency string, computed pgtype.Numeric, prec Precisions) (*dto.BalanceAssertionResponse, error) {
+	diff, err := MoneyFromNumeric(a.Balance, currency).Sub(MoneyFromNumeric(computed, currency))
+	if err != nil {
+		return nil, err
+	}
+	return &dto.BalanceAssertionResponse{
+		ID:              a.ID,
+		AccountID:       a.AccountID,
+		Currency:        currency,
+		Date:            dateToString(a.Date),
+		Balance:         prec.Format(a.Balance, currency),
+		Note:            a.Note,
+		ComputedBalance: prec.Format(computed, currency),
+		Difference:      diff.Format(prec),
+		CreatedAt:       a.CreatedAt.Time,
+	}, nil
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/dto"
+	"github.com/sanches/finance-tracker-cc/backend/internal/store"
+)
+
+type mockBalanceAssertionStore struct {
+	createBalanceAssertionFn func(ctx context.Context, arg store.CreateBalanceAssertionParams) (store.BalanceAssertion, error)
+	getBalanceAssertionFn    func(ctx context.Context, arg store.GetBalanceAssertionParams) (store.BalanceAssertion, error)
+	listBalanceAssertionsFn  func(ctx context.Context, arg store.ListBalanceAssertionsParams) ([]store.ListBalanceAssertionsRow, error)
+	deleteBalanceAssertionFn func(ctx context.Context, arg store.DeleteBalanceAssertionParams) (int64, error)
+	getAccountBalanceAtFn    func(ctx context.Context, arg store.GetAccountBalanceAtParams) (pgtype.Numeric, error)
+	getAccountFn             func(ctx context.Context, arg store.GetAccountParams) (store.Account, error)
+}
+
+func (m *mockBalanceAssertionStore) CreateBalanceAssertion(ctx context.Context, arg store.CreateBalanceAssertionParams) (store.BalanceAssertion, error) {
+	return m.createBalanceAssertionFn(ctx, arg)
+}
+
+func (m *mockBalanceAssertionStore) GetBalanceAssertion(ctx context.Context, arg store.GetBalanceAssertionParams) (store.BalanceAssertion, error) {
+	return m.getBalanceAssertionFn(ctx, arg)
+}
+
+func (m *mockBalanceAssertionStore) ListBalanceAssertions(ctx context.Context, arg store.ListBalanceAssertionsParams) ([]store.ListBalanceAssertionsRow, error) {
+	return m.listBalanceAssertionsFn(ctx, arg)
+}
+
+func (m *mockBalanceAssertionStore) DeleteBalanceAssertion(ctx context.Context, arg store.DeleteBalanceAssertionParams) (int64, error) {
+	return m.deleteBalanceAssertionFn(ctx, arg)
+}
+
+func (m *mockBalanceAssertionStore) GetAccountBalanceAt(ctx context.Context, arg store.GetAccountBalanceAtParams) (pgtype.Numeric, error) {
+	return m.getAccountBalanceAtFn(ctx, arg)
+}
+
+func (m *mockBalanceAssertionStore) GetAccount(ctx context.Context, arg store.GetAccountParams) (store.Account, error) {
+	return m.getAccountFn(ctx, arg)
+}
+
+func TestBalanceAssertionCreate(t *testing.T) {
+	userID := uuid.New()
+	accountID := uuid.New()
+
+	mock := &mockBalanceAssertionStore{
+		getAccountFn: func(ctx context.Context, arg store.GetAccountParams) (store.Account, error) {
+			return store.Account{ID: accountID, UserID: userID, Currency: "USD"}, nil
+		},
+		createBalanceAssertionFn: func(ctx context.Context, arg store.CreateBalanceAssertionParams) (store.BalanceAssertion, error) {
+			return store.BalanceAssertion{ID: uuid.New(), UserID: arg.UserID, AccountID: arg.AccountID, Date: arg.Date, Balance: arg.Balance, Note: arg.Note}, nil
+		},
+		getAccountBalanceAtFn: func(ctx context.Context, arg store.GetAccountBalanceAtParams) (pgtype.Numeric, error) {
+			return numericFromString("12000"), nil
+		},
+	}
+	svc := &BalanceAssertion{queries: mock, currencies: testCurrencyPrecision()}
+
+	t.Run("success", func(t *testing.T) {
+		resp, err := svc.Create(context.Background(), userID, dto.CreateBalanceAssertionRequest{
+			AccountID: accountID,
+			Date:      "2026-01-01",
+			Balance:   "12345.67",
+			Note:      "January statement",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "2026-01-01", resp.Date)
+		require.Equal(t, "12345.67", resp.Balance)
+		require.Equal(t, "12000.00", resp.ComputedBalance)
+		require.Equal(t, "345.67", resp.Difference)
+		require.Equal(t, "January statement", resp.Note)
+	})
+
+	t.Run("invalid date", func(t *testing.T) {
+		_, err := svc.Create(context.Background(), userID, dto.CreateBalanceAssertionRequest{
+			AccountID: accountID,
+			Date:      "01/01/2026",
+			Balance:   "1",
+		})
+		require.ErrorIs(t, err, ErrInvalidBalanceAssertion)
+	})
+
+	t.Run("precision", func(t *testing.T) {
+		_, err := svc.Create(context.Background(), userID, dto.CreateBalanceAssertionRequest{
+			AccountID: accountID,
+			Date:      "2026-01-01",
+			Balance:   "1.001",
+		})
+		require.ErrorIs(t, err, ErrAmountPrecision)
+	})
+
+	t.Run("duplicate date", func(t *testing.T) {
+		dup := *mock
+		dup.createBalanceAssertionFn = func(ctx context.Context, arg store.CreateBalanceAssertionParams) (store.BalanceAssertion, error) {
+			return store.BalanceAssertion{}, errors.New("ERROR: duplicate key value violates unique constraint (SQLSTATE 23505)")
+		}
+		svc := &BalanceAssertion{queries: &dup}
+		_, err := svc.Create(context.Background(), userID, dto.CreateBalanceAssertionRequest{
+			AccountID: accountID,
+			Date:      "2026-01-01",
+			Balance:   "1",
+		})
+		require.ErrorIs(t, err, ErrBalanceAssertionExists)
+	})
+}
+
+func TestBalanceAssertionCheck(t *testing.T) {
+	userID := uuid.New()
+	accountID := uuid.New()
+	date := pgtype.Date{Time: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), Valid: true}
+
+	var captured store.ListBalanceAssertionsParams
+	svc := &BalanceAssertion{queries: &mockBalanceAssertionStore{
+		listBalanceAssertionsFn: func(ctx context.Context, arg store.ListBalanceAssertionsParams) ([]store.ListBalanceAssertionsRow, error) {
+			captured = arg
+			return []store.ListBalanceAssertionsRow{
+				{ID: uuid.New(), AccountID: accountID, Date: date, Balance: numericFromString("100"), Currency: "JPY", ComputedBalance: numericFromString("100.00")},
+				{ID: uuid.New(), AccountID: accountID, Date: date, Balance: numericFromString("100"), Currency: "JPY", ComputedBalance: numericFromString("130")},
+			}, nil
+		},
+	}, currencies: testCurrencyPrecision()}
+
+	result, err := svc.Check(context.Background(), userID, &accountID)
+	require.NoError(t, err)
+	require.Equal(t, pgtype.UUID{Bytes: accountID, Valid: true}, captured.AccountID)
+	require.Len(t, result, 1)
+	require.Equal(t, "130", result[0].ComputedBalance)
+	require.Equal(t, "-30", result[0].Difference)
+
+	all, err := svc.List(context.Background(), userID, nil)
+	require.NoError(t, err)
+	require.False(t, captured.AccountID.Valid)
+	require.Len(t, all, 2)
+}
+
+func TestBalanceAssertionAdjust(t *testing.T) {
+	userID := uuid.New()
+	accountID := uuid.New()
+	id := uuid.New()
+	date := pgtype.Date{Time: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), Valid: true}
+
+	newSvc := func(computed string, captured *store.CreateTransactionParams, role string) *BalanceAssertion {
+		transactions := &Transaction{queries: &mockTransactionStore{
+			getAccessibleAccountFn: func(ctx context.Context, arg store.GetAccessibleAccountParams) (store.GetAccessibleAccountRow, error) {
+				return store.GetAccessibleAccountRow{Account: store.Account{ID: accountID, UserID: userID, Currency: "USD"}, Role: role}, nil
+			},
+			getAccountFn: func(ctx context.Context, arg store.GetAccountParams) (store.Account, error) {
+				return store.Account{ID: accountID, UserID: userID, Currency: "USD"}, nil
+			},
+			createTransactionFn: func(ctx context.Context, arg store.CreateTransactionParams) (store.Transaction, error) {
+				*captured = arg
+				return store.Transaction{ID: uuid.New(), UserID: arg.UserID, AccountID: arg.AccountID, Type: arg.Type, Amount: arg.Amount, Description: arg.Description, Date: arg.Date}, nil
+			},
+		}, currencies: testCurrencyPrecision()}
+		return &BalanceAssertion{queries: &mockBalanceAssertionStore{
+			getBalanceAssertionFn: func(ctx context.Context, arg store.GetBalanceAssertionParams) (store.BalanceAssertion, error) {
+				return store.BalanceAssertion{ID: id, UserID: userID, AccountID: accountID, Date: date, Balance: numericFromString("500")}, nil
+			},
+			getAccountFn: func(ctx context.Context, arg store.GetAccountParams) (store.Account, error) {
+				return store.Account{ID: accountID, UserID: userID, Currency: "USD"}, nil
+			},
+			getAccountBalanceAtFn: func(ctx context.Context, arg store.GetAccountBalanceAtParams) (pgtype.Numeric, error) {
+				return numericFromString(computed), nil
+			},
+		}, currencies: testCurrencyPrecision(), transactions: transactions}
+	}
+
+	t.Run("computed too low books income", func(t *testing.T) {
+		var captured store.CreateTransactionParams
+		resp, err := newSvc("420.25", &captured, RoleOwner).Adjust(context.Background(), userID, id)
+		require.NoError(t, err)
+		require.Equal(t, "income", captured.Type)
+		require.Equal(t, date, captured.Date)
+		require.Equal(t, BalanceAdjustmentDescription, captured.Description)
+		require.False(t, captured.CategoryID.Valid)
+		require.Equal(t, "79.75", resp.Amount)
+	})
+
+	t.Run("computed too high books expense", func(t *testing.T) {
+		var captured store.CreateTransactionParams
+		resp, err := newSvc("510", &captured, RoleOwner).Adjust(context.Background(), userID, id)
+		require.NoError(t, err)
+		require.Equal(t, "expense", captured.Type)
+		require.Equal(t, "10.00", resp.Amount)
+	})
+
+	t.Run("assertion holds", func(t *testing.T) {
+		var captured store.CreateTransactionParams
+		_, err := newSvc("500", &captured, RoleOwner).Adjust(context.Background(), userID, id)
+		require.ErrorIs(t, err, ErrBalanceAssertionHolds)
+	})
+
+	t.Run("household viewer cannot adjust", func(t *testing.T) {
+		var captured store.CreateTransactionParams
+		_, err := newSvc("420.25", &captured, RoleViewer).Adjust(context.Background(), userID, id)
+		require.ErrorIs(t, err, ErrHouseholdReadOnly)
+		require.Zero(t, captured.Type, "nothing is booked")
+	})
+}
+
+func TestBalanceAssertionDelete_NotFound(t *testing.T) {
+	svc := &BalanceAssertion{queries: &mockBalanceAssertionStore{
+		deleteBalanceAssertionFn: func(ctx context.Context, arg store.DeleteBalanceAssertionParams) (int64, error) {
+			return 0, nil
+		},
+	}}
+	err := svc.Delete(context.Background(), uuid.New(), uuid.New())
+	require.ErrorIs(t, err, ErrNotFound)
+}
@@ -22,9 +22,10 @@ type categoryStore interface {
 	CreateCategory(ctx context.Context, arg store.CreateCategoryParams) (store.Category, error)
 	ListCategories(ctx context.Context, userID uuid.UUID) ([]store.ListCategoriesRow, error)
 	UpdateCategory(ctx context.Context, arg store.UpdateCategoryParams) (store.Category, error)
-	DeleteCategory(ctx context.Context, arg store.DeleteCategoryParams) error
+	TrashCategory(ctx context.Context, arg store.TrashCategoryParams) error
 	HasChildCategories(ctx context.Context, parentID pgtype.UUID) (bool, error)
 	HasCategoryTransactions(ctx context.Context, categoryID pgtype.UUID) (bool, error)
+	householdReader
 }
 
 type Category struct {
@@ -36,11 +37,16 @@ func NewCategory(queries *store.Queries) *Category {
 }
 
 func (s *Category) Create(ctx context.Context, userID uuid.UUID, req dto.CreateCategoryRequest) (*dto.CategoryResponse, error) {
+	household, err := sharedWith(ctx, s.queries, userID, req.HouseholdID)
+	if err != nil {
+		return nil, err
+	}
 	cat, err := s.queries.CreateCategory(ctx, store.CreateCategoryParams{
-		UserID:   userID,
-		ParentID: uuidToNullable(req.ParentID),
-		Name:     req.Name,
-		Type:     req.Type,
+		UserID:      userID,
+		ParentID:    uuidToNullable(req.ParentID),
+		Name:        req.Name,
+		Type:        req.Type,
+		HouseholdID: household,
 	})
 	if err != nil {
 		if isDuplicateKey(err) {
@@ -51,6 +57,8 @@ func (s *Category) Create(ctx context.Context, userID uuid.UUID, req dto.CreateC
 	return catToResponse(cat), nil
 }
 
+// List returns the user's categories and those shared with their households
+// as a tree.
 func (s *Category) List(ctx context.Context, userID uuid.UUID) ([]dto.CategoryResponse, error) {
 	cats, err := s.queries.ListCategories(ctx, userID)
 	if err != nil {
@@ -93,11 +101,16 @@ func (s *Category) List(ctx context.Context, userID uuid.UUID) ([]dto.CategoryRe
 }
 
 func (s *Category) Update(ctx context.Context, userID, categoryID uuid.UUID, req dto.UpdateCategoryRequest) (*dto.CategoryResponse, error) {
+	household, err := sharedWith(ctx, s.queries, userID, req.HouseholdID)
+	if err != nil {
+		return nil, err
+	}
 	cat, err := s.queries.UpdateCategory(ctx, store.UpdateCategoryParams{
-		ID:       categoryID,
-		Name:     req.Name,
-		ParentID: uuidToNullable(req.ParentID),
-		UserID:   userID,
+		ID:          categoryID,
+		Name:        req.Name,
+		ParentID:    uuidToNullable(req.ParentID),
+		HouseholdID: household,
+		UserID:      userID,
 	})
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -111,6 +124,8 @@ func (s *Category) Update(ctx context.Context, userID, categoryID uuid.UUID, req
 	return catToResponse(cat), nil
 }
 
+// Delete moves the category to the trash. Only categories without
+// subcategories or transactions outside the trash can be deleted.
 func (s *Category) Delete(ctx context.Context, userID, categoryID uuid.UUID) error {
 	pgID := pgtype.UUID{Bytes: categoryID, Valid: true}
 
@@ -130,7 +145,7 @@ func (s *Category) Delete(ctx context.Context, userID, categoryID uuid.UUID) err
 		return ErrCategoryHasTransactions
 	}
 
-	return s.queries.DeleteCategory(ctx, store.DeleteCategoryParams{ID: categoryID, UserID: userID})
+	return s.queries.TrashCategory(ctx, store.TrashCategoryParams{ID: categoryID, UserID: userID})
 }
 
 func listCatToResponse(c store.ListCategoriesRow) *dto.CategoryResponse {
@@ -139,6 +154,7 @@ func listCatToResponse(c store.ListCategoriesRow) *dto.CategoryResponse {
 		Name:          c.Name,
 		Type:          c.Type,
 		ParentID:      nullableToUUID(c.ParentID),
+		HouseholdID:   nullableToUUID(c.HouseholdID),
 		RecentTxCount: int(c.RecentTxCount),
 		CreatedAt:     c.CreatedAt.Time,
 	}
@@ -146,10 +162,12 @@ func listCatToResponse(c store.ListCategoriesRow) *dto.CategoryResponse {
 
 func catToResponse(c store.Category) *dto.CategoryResponse {
 	return &dto.CategoryResponse{
-		ID:        c.ID,
-		Name:      c.Name,
-		Type:      c.Type,
-		ParentID:  nullableToUUID(c.ParentID),
-		CreatedAt: c.CreatedAt.Time,
+		ID:          c.ID,
+		Name:        c.Name,
+		Type:        c.Type,
+		ParentID:    nullableToUUID(c.ParentID),
+		HouseholdID: nullableToUUID(c.HouseholdID),
+		CreatedAt:   c.CreatedAt.Time,
+		DeletedAt:   deletedAt(c.DeletedAt),
 	}
 }
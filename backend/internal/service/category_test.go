@@ -18,9 +18,10 @@ type mockCategoryStore struct {
 	createCategoryFn         func(ctx context.Context, arg store.CreateCategoryParams) (store.Category, error)
 	listCategoriesFn         func(ctx context.Context, userID uuid.UUID) ([]store.ListCategoriesRow, error)
 	updateCategoryFn         func(ctx context.Context, arg store.UpdateCategoryParams) (store.Category, error)
-	deleteCategoryFn         func(ctx context.Context, arg store.DeleteCategoryParams) error
+	trashCategoryFn          func(ctx context.Context, arg store.TrashCategoryParams) error
 	hasChildCategoriesFn     func(ctx context.Context, parentID pgtype.UUID) (bool, error)
 	hasCategoryTransactionsFn func(ctx context.Context, categoryID pgtype.UUID) (bool, error)
+	getHouseholdFn           func(ctx context.Context, arg store.GetHouseholdParams) (store.GetHouseholdRow, error)
 }
 
 func (m *mockCategoryStore) CreateCategory(ctx context.Context, arg store.CreateCategoryParams) (store.Category, error) {
@@ -32,8 +33,8 @@ func (m *mockCategoryStore) ListCategories(ctx context.Context, userID uuid.UUID
 func (m *mockCategoryStore) UpdateCategory(ctx context.Context, arg store.UpdateCategoryParams) (store.Category, error) {
 	return m.updateCategoryFn(ctx, arg)
 }
-func (m *mockCategoryStore) DeleteCategory(ctx context.Context, arg store.DeleteCategoryParams) error {
-	return m.deleteCategoryFn(ctx, arg)
+func (m *mockCategoryStore) TrashCategory(ctx context.Context, arg store.TrashCategoryParams) error {
+	return m.trashCategoryFn(ctx, arg)
 }
 func (m *mockCategoryStore) HasChildCategories(ctx context.Context, parentID pgtype.UUID) (bool, error) {
 	return m.hasChildCategoriesFn(ctx, parentID)
@@ -41,6 +42,9 @@ func (m *mockCategoryStore) HasChildCategories(ctx context.Context, parentID pgt
 func (m *mockCategoryStore) HasCategoryTransactions(ctx context.Context, categoryID pgtype.UUID) (bool, error) {
 	return m.hasCategoryTransactionsFn(ctx, categoryID)
 }
+func (m *mockCategoryStore) GetHousehold(ctx context.Context, arg store.GetHouseholdParams) (store.GetHouseholdRow, error) {
+	return m.getHouseholdFn(ctx, arg)
+}
 
 func TestCategoryList_Tree(t *testing.T) {
 	userID := uuid.New()
@@ -253,7 +257,7 @@ func TestCategoryDelete_Success(t *testing.T) {
 		hasCategoryTransactionsFn: func(ctx context.Context, categoryID pgtype.UUID) (bool, error) {
 			return false, nil
 		},
-		deleteCategoryFn: func(ctx context.Context, arg store.DeleteCategoryParams) error {
+		trashCategoryFn: func(ctx context.Context, arg store.TrashCategoryParams) error {
 			deleteCalled = true
 			return nil
 		},
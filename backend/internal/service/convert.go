@@ -1,11 +1,11 @@
 package service
 
 import (
-	"math/big"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
 )
 
 func numericFromString(s string) pgtype.Numeric {
@@ -27,48 +27,25 @@ func numericFromStringOrZero(s string) pgtype.Numeric {
 	return n
 }
 
+// numericToString formats with defaultCurrencyDecimals; amounts in a known
+// currency go through Precisions.Format instead.
 func numericToString(n pgtype.Numeric) string {
-	if !n.Valid {
-		return "0"
-	}
-	f := numericToBigFloat(n)
-	return f.Text('f', 2)
+	return formatAmount(n, defaultCurrencyDecimals)
 }
 
-func numericToBigFloat(n pgtype.Numeric) *big.Float {
+// numericToDecimal converts without rounding, for arithmetic and for values
+// such as exchange rates that carry more decimal places than an amount.
+func numericToDecimal(n pgtype.Numeric) decimal.Decimal {
 	if !n.Valid || n.Int == nil {
-		return new(big.Float)
-	}
-	f := new(big.Float).SetInt(n.Int)
-	if n.Exp < 0 {
-		divisor := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(-n.Exp)), nil))
-		f.Quo(f, divisor)
-	} else if n.Exp > 0 {
-		multiplier := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n.Exp)), nil))
-		f.Mul(f, multiplier)
+		return decimal.Zero
 	}
-	return f
-}
-
-func numericAdd(a, b pgtype.Numeric) pgtype.Numeric {
-	fa := numericToBigFloat(a)
-	fb := numericToBigFloat(b)
-	result := new(big.Float).Add(fa, fb)
-	return bigFloatToNumeric(result)
+	return decimal.NewFromBigInt(n.Int, n.Exp)
 }
 
-func numericSub(a, b pgtype.Numeric) pgtype.Numeric {
-	fa := numericToBigFloat(a)
-	fb := numericToBigFloat(b)
-	result := new(big.Float).Sub(fa, fb)
-	return bigFloatToNumeric(result)
-}
-
-func bigFloatToNumeric(f *big.Float) pgtype.Numeric {
-	var n pgtype.Numeric
-	text := f.Text('f', 2)
-	_ = n.Scan(text)
-	return n
+// numericFromDecimal converts without going through text, so no digits are
+// lost.
+func numericFromDecimal(d decimal.Decimal) pgtype.Numeric {
+	return pgtype.Numeric{Int: d.Coefficient(), Exp: d.Exponent(), Valid: true}
 }
 
 func dateFromString(s string) (pgtype.Date, error) {
@@ -5,6 +5,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/require"
 )
 
@@ -43,35 +44,17 @@ func TestNumericToString(t *testing.T) {
 	})
 }
 
-func TestNumericAdd(t *testing.T) {
-	t.Run("positive values", func(t *testing.T) {
-		a := numericFromString("100.50")
-		b := numericFromString("50.25")
-		result := numericAdd(a, b)
-		require.Equal(t, "150.75", numericToString(result))
-	})
-
-	t.Run("positive plus negative", func(t *testing.T) {
-		a := numericFromString("100.00")
-		b := numericFromString("-30.00")
-		result := numericAdd(a, b)
-		require.Equal(t, "70.00", numericToString(result))
-	})
-}
-
-func TestNumericSub(t *testing.T) {
-	t.Run("result positive", func(t *testing.T) {
-		a := numericFromString("100.00")
-		b := numericFromString("30.50")
-		result := numericSub(a, b)
-		require.Equal(t, "69.50", numericToString(result))
+func TestNumericFromDecimal(t *testing.T) {
+	t.Run("keeps every digit", func(t *testing.T) {
+		d := decimal.RequireFromString("123456789012.12345678")
+		n := numericFromDecimal(d)
+		require.True(t, n.Valid)
+		require.True(t, numericToDecimal(n).Equal(d))
 	})
 
-	t.Run("result negative", func(t *testing.T) {
-		a := numericFromString("30.00")
-		b := numericFromString("100.00")
-		result := numericSub(a, b)
-		require.Equal(t, "-70.00", numericToString(result))
+	t.Run("negative", func(t *testing.T) {
+		n := numericFromDecimal(decimal.RequireFromString("-70.5"))
+		require.Equal(t, "-70.50", numericToString(n))
 	})
 }
 
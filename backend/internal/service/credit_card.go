@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/dto"
+	"github.com/sanches/finance-tracker-cc/backend/internal/store"
+)
+
+var (
+	ErrNotCreditCard      = errors.New("account is not a credit card")
+	ErrCreditCardNotSetUp = errors.New("credit card has no statement settings")
+	ErrInvalidCreditCard  = errors.New("credit_limit, min_payment_percent and min_payment_amount must be non-negative numbers, min_payment_percent at most 100")
+)
+
+const accountTypeCreditCard = "credit_card"
+
+var defaultMinPaymentPercent = decimal.NewFromInt(2)
+
+type creditCardStore interface {
+	GetAccount(ctx context.Context, arg store.GetAccountParams) (store.Account, error)
+	GetCreditCard(ctx context.Context, arg store.GetCreditCardParams) (store.CreditCard, error)
+	UpsertCreditCard(ctx context.Context, arg store.UpsertCreditCardParams) (store.CreditCard, error)
+	GetAccountBalanceAt(ctx context.Context, arg store.GetAccountBalanceAtParams) (pgtype.Numeric, error)
+	GetCardPayments(ctx context.Context, arg store.GetCardPaymentsParams) (store.GetCardPaymentsRow, error)
+}
+
+// CreditCard tracks statement cycles of credit_card accounts. Card balances
+// are negative while money is owed, so amounts in responses are negated into
+// "owed" figures.
+type CreditCard struct {
+	queries    creditCardStore
+	currencies *CurrencyPrecision
+}
+
+func NewCreditCard(queries *store.Queries, currencies *CurrencyPrecision) *CreditCard {
+	return &CreditCard{queries: queries, currencies: currencies}
+}
+
+func (s *CreditCard) Update(ctx context.Context, userID, accountID uuid.UUID, req dto.UpdateCreditCardRequest) (*dto.CreditCardResponse, error) {
+	acct, err := s.account(ctx, userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+	prec, err := s.currencies.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	limit, err := parsePositiveDecimal(req.CreditLimit)
+	if err != nil {
+		return nil, ErrInvalidCreditCard
+	}
+	percent := defaultMinPaymentPercent
+	if req.MinPaymentPercent != "" {
+		percent, err = parsePositiveDecimal(req.MinPaymentPercent)
+		if err != nil || percent.GreaterThan(decimal.NewFromInt(100)) {
+			return nil, ErrInvalidCreditCard
+		}
+	}
+	minAmount, err := parsePositiveDecimal(req.MinPaymentAmount)
+	if err != nil {
+		return nil, ErrInvalidCreditCard
+	}
+	if err := prec.Check(req.CreditLimit, acct.Currency); err != nil {
+		return nil, err
+	}
+	if err := prec.Check(req.MinPaymentAmount, acct.Currency); err != nil {
+		return nil, err
+	}
+
+	params := store.UpsertCreditCardParams{
+		AccountID:         acct.ID,
+		UserID:            userID,
+		StatementDay:      int16(req.StatementDay),
+		PaymentDueDay:     int16(req.PaymentDueDay),
+		MinPaymentPercent: numericFromDecimal(percent),
+		MinPaymentAmount:  numericFromDecimal(minAmount),
+	}
+	if req.CreditLimit != "" {
+		params.CreditLimit = numericFromDecimal(limit)
+	}
+	card, err := s.queries.UpsertCreditCard(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return s.statement(ctx, acct, card, prec, time.Now())
+}
+
+// Get returns the card settings with the open and the last closed statement,
+// the minimum payment, available credit and whether the last statement is
+// overdue.
+func (s *CreditCard) Get(ctx context.Context, userID, accountID uuid.UUID) (*dto.CreditCardResponse, error) {
+	acct, err := s.account(ctx, userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+	card, err := s.queries.GetCreditCard(ctx, store.GetCreditCardParams{AccountID: accountID, UserID: userID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCreditCardNotSetUp
+		}
+		return nil, err
+	}
+	prec, err := s.currencies.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.statement(ctx, acct, card, prec, time.Now())
+}
+
+func (s *CreditCard) account(ctx context.Context, userID, accountID uuid.UUID) (store.Account, error) {
+	acct, err := s.queries.GetAccount(ctx, store.GetAccountParams{ID: accountID, UserID: userID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return store.Account{}, ErrAccountNotFound
+		}
+		return store.Account{}, err
+	}
+	if acct.Type != accountTypeCreditCard {
+		return store.Account{}, ErrNotCreditCard
+	}
+	return acct, nil
+}
+
+// statement works out the cycles around today. The statement closing today
+// is still open; the previous one is overdue once its due date has passed
+// with a debt and no transfer into the card since it closed.
+func (s *CreditCard) statement(ctx context.Context, acct store.Account, card store.CreditCard, prec Precisions, today time.Time) (*dto.CreditCardResponse, error) {
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+	cycle := statementCycle(today, int(card.StatementDay), int(card.PaymentDueDay))
+
+	closed, err := s.queries.GetAccountBalanceAt(ctx, store.GetAccountBalanceAtParams{
+		Date:      pgtype.Date{Time: cycle.prevClose, Valid: true},
+		AccountID: acct.ID,
+		UserID:    acct.UserID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	paid, err := s.queries.GetCardPayments(ctx, store.GetCardPaymentsParams{
+		AccountID: acct.ID,
+		UserID:    acct.UserID,
+		DateFrom:  pgtype.Date{Time: cycle.prevClose, Valid: true},
+		DateTo:    pgtype.Date{Time: today, Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	owedNow := numericToDecimal(acct.Balance).Neg()
+	owedAtClose := numericToDecimal(closed).Neg()
+
+	overdue := false
+	if today.After(cycle.prevDue) && owedAtClose.IsPositive() {
+		byDue, err := s.queries.GetCardPayments(ctx, store.GetCardPaymentsParams{
+			AccountID: acct.ID,
+			UserID:    acct.UserID,
+			DateFrom:  pgtype.Date{Time: cycle.prevClose, Valid: true},
+			DateTo:    pgtype.Date{Time: cycle.prevDue, Valid: true},
+		})
+		if err != nil {
+			return nil, err
+		}
+		overdue = byDue.Count == 0
+	}
+
+	decimals := prec.Of(acct.Currency)
+	percent := numericToDecimal(card.MinPaymentPercent)
+	minAmount := numericToDecimal(card.MinPaymentAmount)
+
+	resp := &dto.CreditCardResponse{
+		AccountID:         acct.ID,
+		Currency:          acct.Currency,
+		StatementDay:      int(card.StatementDay),
+		PaymentDueDay:     int(card.PaymentDueDay),
+		MinPaymentPercent: percent.String(),
+		MinPaymentAmount:  minAmount.StringFixed(decimals),
+		Owed:              owedNow.StringFixed(decimals),
+		CurrentStatement: dto.CreditCardStatement{
+			PeriodStart:    cycle.prevClose.AddDate(0, 0, 1).Format("2006-01-02"),
+			ClosingDate:    cycle.nextClose.Format("2006-01-02"),
+			DueDate:        cycle.nextDue.Format("2006-01-02"),
+			Balance:        owedNow.StringFixed(decimals),
+			MinimumPayment: minimumPayment(owedNow, percent, minAmount, decimals).StringFixed(decimals),
+		},
+		PreviousStatement: dto.CreditCardStatement{
+			PeriodStart:    cycle.prevPrevClose.AddDate(0, 0, 1).Format("2006-01-02"),
+			ClosingDate:    cycle.prevClose.Format("2006-01-02"),
+			DueDate:        cycle.prevDue.Format("2006-01-02"),
+			Balance:        owedAtClose.StringFixed(decimals),
+			MinimumPayment: minimumPayment(owedAtClose, percent, minAmount, decimals).StringFixed(decimals),
+		},
+		PaidSinceStatement: numericToDecimal(paid.Total).StringFixed(decimals),
+		Overdue:            overdue,
+	}
+	if card.CreditLimit.Valid {
+		limit := numericToDecimal(card.CreditLimit)
+		limitStr := limit.StringFixed(decimals)
+		available := decimal.Max(limit.Sub(owedNow), decimal.Zero).StringFixed(decimals)
+		resp.CreditLimit = &limitStr
+		resp.AvailableCredit = &available
+	}
+	return resp, nil
+}
+
+// minimumPayment is percent of the amount owed, at least minAmount, never
+// more than what is owed, rounded up to the currency's precision.
+func minimumPayment(owed, percent, minAmount decimal.Decimal, decimals int32) decimal.Decimal {
+	if !owed.IsPositive() {
+		return decimal.Zero
+	}
+	p := owed.Mul(percent).Div(decimal.NewFromInt(100)).RoundCeil(decimals)
+	return decimal.Min(decimal.Max(p, minAmount), owed)
+}
+
+type cycleDates struct {
+	prevPrevClose time.Time
+	prevClose     time.Time
+	prevDue       time.Time
+	nextClose     time.Time
+	nextDue       time.Time
+}
+
+// statementCycle returns the closing dates around today and the due date
+// following each. A closing day past the end of a month closes on its last
+// day.
+func statementCycle(today time.Time, statementDay, dueDay int) cycleDates {
+	nextClose := dayInMonth(today.Year(), today.Month(), statementDay)
+	if nextClose.Before(today) {
+		nextClose = dayInMonth(today.Year(), today.Month()+1, statementDay)
+	}
+	prevClose := dayInMonth(nextClose.Year(), nextClose.Month()-1, statementDay)
+	prevPrevClose := dayInMonth(prevClose.Year(), prevClose.Month()-1, statementDay)
+	return cycleDates{
+		prevPrevClose: prevPrevClose,
+		prevClose:     prevClose,
+		prevDue:       dueAfter(prevClose, dueDay),
+		nextClose:     nextClose,
+		nextDue:       dueAfter(nextClose, dueDay),
+	}
+}
+
+// dueAfter returns the first dueDay strictly after closing.
+func dueAfter(closing time.Time, dueDay int) time.Time {
+	due := dayInMonth(closing.Year(), closing.Month(), dueDay)
+	if !due.After(closing) {
+		due = dayInMonth(closing.Year(), closing.Month()+1, dueDay)
+	}
+	return due
+}
+
+// dayInMonth clamps day to the length of the month; month may overflow into
+// the next or previous year.
+func dayInMonth(year int, month time.Month, day int) time.Time {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	last := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(day, last)-1)
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/dto"
+	"github.com/sanches/finance-tracker-cc/backend/internal/store"
+)
+
+type mockCreditCardStore struct {
+	getAccountFn          func(ctx context.Context, arg store.GetAccountParams) (store.Account, error)
+	getCreditCardFn       func(ctx context.Context, arg store.GetCreditCardParams) (store.CreditCard, error)
+	upsertCreditCardFn    func(ctx context.Context, arg store.UpsertCreditCardParams) (store.CreditCard, error)
+	getAccountBalanceAtFn func(ctx context.Context, arg store.GetAccountBalanceAtParams) (pgtype.Numeric, error)
+	getCardPaymentsFn     func(ctx context.Context, arg store.GetCardPaymentsParams) (store.GetCardPaymentsRow, error)
+}
+
+func (m *mockCreditCardStore) GetAccount(ctx context.Context, arg store.GetAccountParams) (store.Account, error) {
+	return m.getAccountFn(ctx, arg)
+}
+
+func (m *mockCreditCardStore) GetCreditCard(ctx context.Context, arg store.GetCreditCardParams) (store.CreditCard, error) {
+	return m.getCreditCardFn(ctx, arg)
+}
+
+func (m *mockCreditCardStore) UpsertCreditCard(ctx context.Context, arg store.UpsertCreditCardParams) (store.CreditCard, error) {
+	return m.upsertCreditCardFn(ctx, arg)
+}
+
+func (m *mockCreditCardStore) GetAccountBalanceAt(ctx context.Context, arg store.GetAccountBalanceAtParams) (pgtype.Numeric, error) {
+	return m.getAccountBalanceAtFn(ctx, arg)
+}
+
+func (m *mockCreditCardStore) GetCardPayments(ctx context.Context, arg store.GetCardPaymentsParams) (store.GetCardPaymentsRow, error) {
+	return m.getCardPaymentsFn(ctx, arg)
+}
+
+func day(s string) time.Time {
+	t, _ := time.Parse("2006-01-02", s)
+	return t
+}
+
+func TestStatementCycle(t *testing.T) {
+	tests := []struct {
+		name                                             string
+		today                                            string
+		statementDay, dueDay                             int
+		prevPrevClose, prevClose, prevDue, next, nextDue string
+	}{
+		{"due next month", "2026-03-10", 15, 5, "2026-01-15", "2026-02-15", "2026-03-05", "2026-03-15", "2026-04-05"},
+		{"closing day is still open", "2026-03-15", 15, 5, "2026-01-15", "2026-02-15", "2026-03-05", "2026-03-15", "2026-04-05"},
+		{"day after closing", "2026-03-16", 15, 5, "2026-02-15", "2026-03-15", "2026-04-05", "2026-04-15", "2026-05-05"},
+		{"due same month", "2026-03-10", 1, 25, "2026-02-01", "2026-03-01", "2026-03-25", "2026-04-01", "2026-04-25"},
+		{"clamped to month end", "2026-03-05", 31, 20, "2026-01-31", "2026-02-28", "2026-03-20", "2026-03-31", "2026-04-20"},
+		{"across year end", "2026-01-03", 28, 15, "2025-11-28", "2025-12-28", "2026-01-15", "2026-01-28", "2026-02-15"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := statementCycle(day(tt.today), tt.statementDay, tt.dueDay)
+			require.Equal(t, day(tt.prevPrevClose), c.prevPrevClose)
+			require.Equal(t, day(tt.prevClose), c.prevClose)
+			require.Equal(t, day(tt.prevDue), c.prevDue)
+			require.Equal(t, day(tt.next), c.nextClose)
+			require.Equal(t, day(tt.nextDue), c.nextDue)
+		})
+	}
+}
+
+func TestMinimumPayment(t *testing.T) {
+	d := decimal.RequireFromString
+	require.Equal(t, "20.01", minimumPayment(d("1000.50"), d("2"), d("0"), 2).StringFixed(2), "rounded up")
+	require.Equal(t, "25.00", minimumPayment(d("300"), d("2"), d("25"), 2).StringFixed(2), "floor amount")
+	require.Equal(t, "10.00", minimumPayment(d("10"), d("2"), d("25"), 2).StringFixed(2), "never more than owed")
+	require.True(t, minimumPayment(d("-5"), d("2"), d("25"), 2).IsZero(), "card in credit")
+}
+
+func TestCreditCardStatement(t *testing.T) {
+	userID := uuid.New()
+	accountID := uuid.New()
+	acct := store.Account{ID: accountID, UserID: userID, Type: "credit_card", Currency: "USD", Balance: numericFromString("-750")}
+	card := store.CreditCard{
+		AccountID:         accountID,
+		UserID:            userID,
+		CreditLimit:       numericFromString("2000"),
+		StatementDay:      15,
+		PaymentDueDay:     5,
+		MinPaymentPercent: numericFromString("2"),
+		MinPaymentAmount:  numericFromString("25"),
+	}
+
+	newSvc := func(payments int32) *CreditCard {
+		return &CreditCard{queries: &mockCreditCardStore{
+			getAccountBalanceAtFn: func(ctx context.Context, arg store.GetAccountBalanceAtParams) (pgtype.Numeric, error) {
+				require.Equal(t, day("2026-02-15"), arg.Date.Time)
+				return numericFromString("-500"), nil
+			},
+			getCardPaymentsFn: func(ctx context.Context, arg store.GetCardPaymentsParams) (store.GetCardPaymentsRow, error) {
+				require.Equal(t, day("2026-02-15"), arg.DateFrom.Time)
+				return store.GetCardPaymentsRow{Total: numericFromString("100"), Count: payments}, nil
+			},
+		}}
+	}
+	prec, err := testCurrencyPrecision().Load(context.Background())
+	require.NoError(t, err)
+
+	t.Run("before the due date", func(t *testing.T) {
+		resp, err := newSvc(0).statement(context.Background(), acct, card, prec, day("2026-03-01"))
+		require.NoError(t, err)
+		require.Equal(t, "750.00", resp.Owed)
+		require.NotNil(t, resp.AvailableCredit)
+		require.Equal(t, "1250.00", *resp.AvailableCredit)
+		require.Equal(t, dto.CreditCardStatement{
+			PeriodStart:    "2026-01-16",
+			ClosingDate:    "2026-02-15",
+			DueDate:        "2026-03-05",
+			Balance:        "500.00",
+			MinimumPayment: "25.00",
+		}, resp.PreviousStatement)
+		require.Equal(t, "2026-03-15", resp.CurrentStatement.ClosingDate)
+		require.Equal(t, "750.00", resp.CurrentStatement.Balance)
+		require.False(t, resp.Overdue)
+	})
+
+	t.Run("overdue without a payment", func(t *testing.T) {
+		resp, err := newSvc(0).statement(context.Background(), acct, card, prec, day("2026-03-06"))
+		require.NoError(t, err)
+		require.True(t, resp.Overdue)
+	})
+
+	t.Run("paid by the due date", func(t *testing.T) {
+		resp, err := newSvc(1).statement(context.Background(), acct, card, prec, day("2026-03-06"))
+		require.NoError(t, err)
+		require.False(t, resp.Overdue)
+		require.Equal(t, "100.00", resp.PaidSinceStatement)
+	})
+}
+
+func TestCreditCard_NotACreditCard(t *testing.T) {
+	svc := &CreditCard{queries: &mockCreditCardStore{
+		getAccountFn: func(ctx context.Context, arg store.GetAccountParams) (store.Account, error) {
+			return store.Account{ID: arg.ID, Type: "deposit"}, nil
+		},
+	}}
+	_, err := svc.Get(context.Background(), uuid.New(), uuid.New())
+	require.ErrorIs(t, err, ErrNotCreditCard)
+}
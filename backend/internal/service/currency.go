@@ -19,11 +19,12 @@ type currencyStore interface {
 }
 
 type Currency struct {
-	queries currencyStore
+	queries    currencyStore
+	currencies *CurrencyPrecision
 }
 
-func NewCurrency(queries *store.Queries) *Currency {
-	return &Currency{queries: queries}
+func NewCurrency(queries *store.Queries, currencies *CurrencyPrecision) *Currency {
+	return &Currency{queries: queries, currencies: currencies}
 }
 
 func (s *Currency) List(ctx context.Context) ([]dto.CurrencyResponse, error) {
@@ -41,9 +42,10 @@ func (s *Currency) List(ctx context.Context) ([]dto.CurrencyResponse, error) {
 
 func (s *Currency) Create(ctx context.Context, req dto.CreateCurrencyRequest) (*dto.CurrencyResponse, error) {
 	c, err := s.queries.CreateCurrency(ctx, store.CreateCurrencyParams{
-		Code:   req.Code,
-		Name:   req.Name,
-		Symbol: req.Symbol,
+		Code:     req.Code,
+		Name:     req.Name,
+		Symbol:   req.Symbol,
+		Decimals: decimalsOrDefault(req.Decimals),
 	})
 	if err != nil {
 		if isDuplicateKey(err) {
@@ -51,6 +53,7 @@ func (s *Currency) Create(ctx context.Context, req dto.CreateCurrencyRequest) (*
 		}
 		return nil, err
 	}
+	s.currencies.Invalidate()
 	resp := currencyToResponse(c)
 	return &resp, nil
 }
@@ -72,8 +75,16 @@ func (s *Currency) Update(ctx context.Context, code string, req dto.UpdateCurren
 
 func currencyToResponse(c store.Currency) dto.CurrencyResponse {
 	return dto.CurrencyResponse{
-		Code:   c.Code,
-		Name:   c.Name,
-		Symbol: c.Symbol,
+		Code:     c.Code,
+		Name:     c.Name,
+		Symbol:   c.Symbol,
+		Decimals: c.Decimals,
 	}
 }
+
+func decimalsOrDefault(d *int16) int16 {
+	if d == nil {
+		return defaultCurrencyDecimals
+	}
+	return *d
+}
@@ -0,0 +1,629 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/dto"
+	"github.com/sanches/finance-tracker-cc/backend/internal/store"
+)
+
+// Split methods.
+const (
+	SplitEqual      = "equal"
+	SplitPercentage = "percentage"
+	SplitExact      = "exact"
+)
+
+var (
+	ErrContactExists     = errors.New("contact with this name already exists")
+	ErrContactNotFound   = errors.New("contact not found")
+	ErrContactInUse      = errors.New("contact has shared expenses or settlements")
+	ErrSplitNotFound     = errors.New("split not found")
+	ErrAlreadySplit      = errors.New("transaction is already split")
+	ErrSplitPayer        = errors.New("a split needs either transaction_id (you paid) or paid_by (a contact paid)")
+	ErrSplitTransaction  = errors.New("only expense transactions that are not transfers can be split")
+	ErrInvalidSplit      = errors.New("splits paid by a contact need a positive amount, a known currency and a date")
+	ErrSplitParticipants = errors.New("participants must be distinct and include a contact, and you when a contact paid")
+	ErrSplitShares       = errors.New("percentage shares must add up to 100 and exact shares to the amount")
+	ErrNothingToSettle   = errors.New("nothing is owed between you and this contact in the account's currency")
+	ErrInvalidSettlement = errors.New("amount must be positive and at most the balance owed")
+)
+
+type debtStore interface {
+	CreateContact(ctx context.Context, arg store.CreateContactParams) (store.Contact, error)
+	GetContact(ctx context.Context, arg store.GetContactParams) (store.Contact, error)
+	ListContacts(ctx context.Context, userID uuid.UUID) ([]store.Contact, error)
+	UpdateContact(ctx context.Context, arg store.UpdateContactParams) (store.Contact, error)
+	DeleteContact(ctx context.Context, arg store.DeleteContactParams) (int64, error)
+	HasContactDebts(ctx context.Context, contactID uuid.UUID) (bool, error)
+	GetExpenseSplit(ctx context.Context, arg store.GetExpenseSplitParams) (store.ExpenseSplit, error)
+	ListExpenseSplits(ctx context.Context, arg store.ListExpenseSplitsParams) ([]store.ExpenseSplit, error)
+	ListExpenseSplitShares(ctx context.Context, splitIds []uuid.UUID) ([]store.ListExpenseSplitSharesRow, error)
+	DeleteExpenseSplit(ctx context.Context, arg store.DeleteExpenseSplitParams) (int64, error)
+	ListDebtBalances(ctx context.Context, userID uuid.UUID) ([]store.ListDebtBalancesRow, error)
+	GetDebtBalance(ctx context.Context, arg store.GetDebtBalanceParams) (pgtype.Numeric, error)
+	GetTransaction(ctx context.Context, arg store.GetTransactionParams) (store.Transaction, error)
+	GetAccount(ctx context.Context, arg store.GetAccountParams) (store.Account, error)
+	WithTx(tx pgx.Tx) *store.Queries
+}
+
+// Debt tracks what the user and their contacts owe each other: shared
+// expenses split among participants, and settlements that pay the debts
+// back.
+type Debt struct {
+	queries    debtStore
+	pool       *pgxpool.Pool
+	currencies *CurrencyPrecision
+}
+
+func NewDebt(queries *store.Queries, pool *pgxpool.Pool, currencies *CurrencyPrecision) *Debt {
+	return &Debt{queries: queries, pool: pool, currencies: currencies}
+}
+
+func contactToResponse(c store.Contact) dto.ContactResponse {
+	return dto.ContactResponse{
+		ID:        c.ID,
+		Name:      c.Name,
+		Balances:  []dto.DebtBalance{},
+		CreatedAt: c.CreatedAt.Time,
+		UpdatedAt: c.UpdatedAt.Time,
+	}
+}
+
+func (s *Debt) CreateContact(ctx context.Context, userID uuid.UUID, req dto.ContactRequest) (*dto.ContactResponse, error) {
+	c, err := s.queries.CreateContact(ctx, store.CreateContactParams{UserID: userID, Name: strings.TrimSpace(req.Name)})
+	if err != nil {
+		if isDuplicateKey(err) {
+			return nil, ErrContactExists
+		}
+		return nil, err
+	}
+	resp := contactToResponse(c)
+	return &resp, nil
+}
+
+// ListContacts returns the user's contacts with what each owes or is owed.
+func (s *Debt) ListContacts(ctx context.Context, userID uuid.UUID) ([]dto.ContactResponse, error) {
+	contacts, err := s.queries.ListContacts(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	balances, err := s.queries.ListDebtBalances(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	prec, err := s.currencies.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byContact := make(map[uuid.UUID][]dto.DebtBalance)
+	for _, b := range balances {
+		byContact[b.ContactID] = append(byContact[b.ContactID], dto.DebtBalance{
+			Currency: b.Currency,
+			Amount:   prec.Format(b.Balance, b.Currency),
+		})
+	}
+	result := make([]dto.ContactResponse, 0, len(contacts))
+	for _, c := range contacts {
+		resp := contactToResponse(c)
+		if b, ok := byContact[c.ID]; ok {
+			resp.Balances = b
+		}
+		result = append(result, resp)
+	}
+	return result, nil
+}
+
+func (s *Debt) UpdateContact(ctx context.Context, userID, id uuid.UUID, req dto.ContactRequest) (*dto.ContactResponse, error) {
+	c, err := s.queries.UpdateContact(ctx, store.UpdateContactParams{ID: id, UserID: userID, Name: strings.TrimSpace(req.Name)})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrContactNotFound
+		}
+		if isDuplicateKey(err) {
+			return nil, ErrContactExists
+		}
+		return nil, err
+	}
+	resp := contactToResponse(c)
+	return &resp, nil
+}
+
+// DeleteContact removes a contact nobody's splits or settlements refer to.
+func (s *Debt) DeleteContact(ctx context.Context, userID, id uuid.UUID) error {
+	if _, err := s.contact(ctx, userID, id); err != nil {
+		return err
+	}
+	used, err := s.queries.HasContactDebts(ctx, id)
+	if err != nil {
+		return err
+	}
+	if used {
+		return ErrContactInUse
+	}
+	n, err := s.queries.DeleteContact(ctx, store.DeleteContactParams{ID: id, UserID: userID})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrContactNotFound
+	}
+	return nil
+}
+
+// CreateSplit records how an expense is shared. When the user paid, each
+// contact then owes their share; when a contact paid, the user owes that
+// contact their own share.
+func (s *Debt) CreateSplit(ctx context.Context, userID uuid.UUID, req dto.CreateSplitRequest) (*dto.SplitResponse, error) {
+	if (req.TransactionID == nil) == (req.PaidBy == nil) {
+		return nil, ErrSplitPayer
+	}
+	prec, err := s.currencies.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	split := store.CreateExpenseSplitParams{
+		UserID:      userID,
+		Method:      req.Method,
+		Description: req.Description,
+	}
+	var total decimal.Decimal
+	if req.TransactionID != nil {
+		txn, err := s.queries.GetTransaction(ctx, store.GetTransactionParams{ID: *req.TransactionID, UserID: userID})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, ErrNotFound
+			}
+			return nil, err
+		}
+		if txn.Type != "expense" || txn.TransferID.Valid {
+			return nil, ErrSplitTransaction
+		}
+		acct, err := s.queries.GetAccount(ctx, store.GetAccountParams{ID: txn.AccountID, UserID: userID})
+		if err != nil {
+			return nil, err
+		}
+		total = numericToDecimal(txn.Amount)
+		split.TransactionID = pgtype.UUID{Bytes: txn.ID, Valid: true}
+		split.Date = txn.Date
+		split.Currency = acct.Currency
+		if split.Description == "" {
+			split.Description = txn.Description
+		}
+	} else {
+		if _, err := s.contact(ctx, userID, *req.PaidBy); err != nil {
+			return nil, err
+		}
+		currency := strings.ToUpper(req.Currency)
+		amount, err := decimal.NewFromString(req.Amount)
+		if err != nil || !amount.IsPositive() {
+			return nil, ErrInvalidSplit
+		}
+		if _, ok := prec[currency]; !ok {
+			return nil, ErrInvalidSplit
+		}
+		if err := prec.Check(req.Amount, currency); err != nil {
+			return nil, err
+		}
+		date, err := dateFromString(req.Date)
+		if err != nil {
+			return nil, ErrInvalidSplit
+		}
+		total = amount
+		split.PaidBy = uuidToNullable(req.PaidBy)
+		split.Date = date
+		split.Currency = currency
+	}
+	split.Amount = numericFromDecimal(total)
+
+	if err := s.checkParticipants(ctx, userID, req); err != nil {
+		return nil, err
+	}
+	shares, err := splitShares(req.Method, total, req.Participants, prec.Of(split.Currency))
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	q := s.queries.WithTx(tx)
+	var e store.ExpenseSplit
+	e, err = q.CreateExpenseSplit(ctx, split)
+	if err != nil {
+		if isDuplicateKey(err) {
+			err = ErrAlreadySplit
+		}
+		return nil, err
+	}
+	for i, p := range req.Participants {
+		err = q.CreateExpenseSplitShare(ctx, store.CreateExpenseSplitShareParams{
+			SplitID:   e.ID,
+			ContactID: uuidToNullable(p.ContactID),
+			Amount:    numericFromDecimal(shares[i]),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return s.GetSplit(ctx, userID, e.ID)
+}
+
+func (s *Debt) GetSplit(ctx context.Context, userID, id uuid.UUID) (*dto.SplitResponse, error) {
+	e, err := s.queries.GetExpenseSplit(ctx, store.GetExpenseSplitParams{ID: id, UserID: userID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrSplitNotFound
+		}
+		return nil, err
+	}
+	splits, err := s.splitsToResponse(ctx, []store.ExpenseSplit{e})
+	if err != nil {
+		return nil, err
+	}
+	return &splits[0], nil
+}
+
+// ListSplits returns the user's splits, newest first; contactID limits them
+// to the splits that contact took part in or paid.
+func (s *Debt) ListSplits(ctx context.Context, userID uuid.UUID, contactID *uuid.UUID) ([]dto.SplitResponse, error) {
+	splits, err := s.queries.ListExpenseSplits(ctx, store.ListExpenseSplitsParams{UserID: userID, ContactID: uuidToNullable(contactID)})
+	if err != nil {
+		return nil, err
+	}
+	return s.splitsToResponse(ctx, splits)
+}
+
+// DeleteSplit removes a split and the debts it created. The transaction it
+// split stays.
+func (s *Debt) DeleteSplit(ctx context.Context, userID, id uuid.UUID) error {
+	n, err := s.queries.DeleteExpenseSplit(ctx, store.DeleteExpenseSplitParams{ID: id, UserID: userID})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrSplitNotFound
+	}
+	return nil
+}
+
+// Settle records money received from (if the contact owes the user) or paid
+// to (if the user owes the contact) a contact as a transaction on one of
+// the user's accounts. The transaction carries the settlement id as its
+// transfer_id, so income and spending reports leave it out.
+func (s *Debt) Settle(ctx context.Context, userID, contactID uuid.UUID, req dto.SettleRequest) (*dto.SettlementResponse, error) {
+	contact, err := s.contact(ctx, userID, contactID)
+	if err != nil {
+		return nil, err
+	}
+	acct, err := s.queries.GetAccount(ctx, store.GetAccountParams{ID: req.AccountID, UserID: userID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrAccountNotFound
+		}
+		return nil, err
+	}
+	if err := checkArchived(acct, nil); err != nil {
+		return nil, err
+	}
+	date, err := dateFromString(req.Date)
+	if err != nil {
+		return nil, errors.New("invalid date format, use YYYY-MM-DD")
+	}
+	prec, err := s.currencies.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := s.queries.GetDebtBalance(ctx, store.GetDebtBalanceParams{
+		UserID:    userID,
+		ContactID: pgtype.UUID{Bytes: contactID, Valid: true},
+		Currency:  acct.Currency,
+	})
+	if err != nil {
+		return nil, err
+	}
+	balance := numericToDecimal(b)
+	if balance.IsZero() {
+		return nil, ErrNothingToSettle
+	}
+	amount := balance.Abs()
+	if req.Amount != "" {
+		amount, err = decimal.NewFromString(req.Amount)
+		if err != nil || !amount.IsPositive() || amount.GreaterThan(balance.Abs()) {
+			return nil, ErrInvalidSettlement
+		}
+		if err := prec.Check(req.Amount, acct.Currency); err != nil {
+			return nil, err
+		}
+	}
+
+	id := uuid.New()
+	txn := store.CreateTransactionParams{
+		UserID:      userID,
+		AccountID:   acct.ID,
+		Type:        "income",
+		Amount:      numericFromDecimal(amount),
+		Description: req.Description,
+		Date:        date,
+		TransferID:  pgtype.UUID{Bytes: id, Valid: true},
+		CreatedBy:   pgtype.UUID{Bytes: userID, Valid: true},
+	}
+	direction := "received"
+	if balance.IsNegative() {
+		txn.Type = "expense"
+		direction = "paid"
+	}
+	if txn.Description == "" {
+		txn.Description = "Settlement with " + contact.Name
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	q := s.queries.WithTx(tx)
+	var t store.Transaction
+	t, err = createTransaction(ctx, q, userID, txn)
+	if err != nil {
+		return nil, err
+	}
+	_, err = q.CreateDebtSettlement(ctx, store.CreateDebtSettlementParams{
+		ID:            id,
+		UserID:        userID,
+		ContactID:     contactID,
+		TransactionID: t.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return &dto.SettlementResponse{
+		ID:          id,
+		ContactID:   contactID,
+		Direction:   direction,
+		Transaction: *transactionToResponse(t, acct.Currency, prec),
+	}, nil
+}
+
+// Report lists the non-zero balances with each contact and totals them per
+// currency.
+func (s *Debt) Report(ctx context.Context, userID uuid.UUID) (*dto.DebtReportResponse, error) {
+	balances, err := s.queries.ListDebtBalances(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	prec, err := s.currencies.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	type sums struct{ owedToYou, youOwe decimal.Decimal }
+	totals := make(map[string]*sums)
+	resp := &dto.DebtReportResponse{
+		Data:   make([]dto.DebtReportEntry, 0, len(balances)),
+		Totals: []dto.DebtTotal{},
+	}
+	for _, b := range balances {
+		resp.Data = append(resp.Data, dto.DebtReportEntry{
+			ContactID: b.ContactID,
+			Name:      b.Name,
+			Currency:  b.Currency,
+			Balance:   prec.Format(b.Balance, b.Currency),
+		})
+		t, ok := totals[b.Currency]
+		if !ok {
+			t = &sums{}
+			totals[b.Currency] = t
+		}
+		amount := numericToDecimal(b.Balance)
+		if amount.IsPositive() {
+			t.owedToYou = t.owedToYou.Add(amount)
+		} else {
+			t.youOwe = t.youOwe.Sub(amount)
+		}
+	}
+
+	currencies := make([]string, 0, len(totals))
+	for c := range totals {
+		currencies = append(currencies, c)
+	}
+	sort.Strings(currencies)
+	for _, c := range currencies {
+		t := totals[c]
+		resp.Totals = append(resp.Totals, dto.DebtTotal{
+			Currency:  c,
+			OwedToYou: prec.Format(numericFromDecimal(t.owedToYou), c),
+			YouOwe:    prec.Format(numericFromDecimal(t.youOwe), c),
+			Net:       prec.Format(numericFromDecimal(t.owedToYou.Sub(t.youOwe)), c),
+		})
+	}
+	return resp, nil
+}
+
+func (s *Debt) contact(ctx context.Context, userID, id uuid.UUID) (store.Contact, error) {
+	c, err := s.queries.GetContact(ctx, store.GetContactParams{ID: id, UserID: userID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return store.Contact{}, ErrContactNotFound
+		}
+		return store.Contact{}, err
+	}
+	return c, nil
+}
+
+// checkParticipants makes sure the participants are distinct contacts of
+// the user, at least one of them a contact, and that the user takes part
+// in a split a contact paid.
+func (s *Debt) checkParticipants(ctx context.Context, userID uuid.UUID, req dto.CreateSplitRequest) error {
+	seen := make(map[uuid.UUID]bool, len(req.Participants))
+	self, contacts := false, 0
+	for _, p := range req.Participants {
+		if p.ContactID == nil {
+			if self {
+				return ErrSplitParticipants
+			}
+			self = true
+			continue
+		}
+		if seen[*p.ContactID] {
+			return ErrSplitParticipants
+		}
+		seen[*p.ContactID] = true
+		contacts++
+		if _, err := s.contact(ctx, userID, *p.ContactID); err != nil {
+			return err
+		}
+	}
+	if contacts == 0 || (req.PaidBy != nil && !self) {
+		return ErrSplitParticipants
+	}
+	return nil
+}
+
+func (s *Debt) splitsToResponse(ctx context.Context, splits []store.ExpenseSplit) ([]dto.SplitResponse, error) {
+	result := make([]dto.SplitResponse, 0, len(splits))
+	if len(splits) == 0 {
+		return result, nil
+	}
+	prec, err := s.currencies.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uuid.UUID, 0, len(splits))
+	for _, e := range splits {
+		ids = append(ids, e.ID)
+	}
+	rows, err := s.queries.ListExpenseSplitShares(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	shares := make(map[uuid.UUID][]store.ListExpenseSplitSharesRow, len(splits))
+	for _, r := range rows {
+		shares[r.SplitID] = append(shares[r.SplitID], r)
+	}
+
+	for _, e := range splits {
+		resp := dto.SplitResponse{
+			ID:            e.ID,
+			TransactionID: nullableToUUID(e.TransactionID),
+			PaidBy:        nullableToUUID(e.PaidBy),
+			Method:        e.Method,
+			Description:   e.Description,
+			Date:          dateToString(e.Date),
+			Currency:      e.Currency,
+			Amount:        prec.Format(e.Amount, e.Currency),
+			Shares:        make([]dto.SplitShareResponse, 0, len(shares[e.ID])),
+			CreatedAt:     e.CreatedAt.Time,
+		}
+		for _, r := range shares[e.ID] {
+			resp.Shares = append(resp.Shares, dto.SplitShareResponse{
+				ContactID:   nullableToUUID(r.ContactID),
+				ContactName: r.ContactName.String,
+				Amount:      prec.Format(r.Amount, e.Currency),
+			})
+		}
+		result = append(result, resp)
+	}
+	return result, nil
+}
+
+// splitShares works out each participant's share of total. Equal and
+// percentage shares are rounded to the currency's decimals with allocate;
+// exact shares must add up to total.
+func splitShares(method string, total decimal.Decimal, participants []dto.SplitParticipant, decimals int32) ([]decimal.Decimal, error) {
+	weights := make([]decimal.Decimal, len(participants))
+	sum := decimal.Zero
+	for i, p := range participants {
+		if method == SplitEqual {
+			weights[i] = decimal.NewFromInt(1)
+			continue
+		}
+		d, err := decimal.NewFromString(p.Share)
+		if err != nil || d.IsNegative() {
+			return nil, ErrSplitShares
+		}
+		if method == SplitExact && !d.Equal(d.Truncate(decimals)) {
+			return nil, ErrAmountPrecision
+		}
+		weights[i] = d
+		sum = sum.Add(d)
+	}
+
+	switch method {
+	case SplitPercentage:
+		if !sum.Equal(decimal.NewFromInt(100)) {
+			return nil, ErrSplitShares
+		}
+	case SplitExact:
+		if !sum.Equal(total) {
+			return nil, ErrSplitShares
+		}
+		return weights, nil
+	}
+	return allocate(total, weights, decimals), nil
+}
+
+// allocate divides total in proportion to weights, rounded down to decimals
+// places. The units left over from rounding go one each to the parts with
+// the largest remainders, earlier parts first on ties, so the parts add up
+// to total exactly.
+func allocate(total decimal.Decimal, weights []decimal.Decimal, decimals int32) []decimal.Decimal {
+	sum := decimal.Zero
+	for _, w := range weights {
+		sum = sum.Add(w)
+	}
+	parts := make([]decimal.Decimal, len(weights))
+	remainders := make([]decimal.Decimal, len(weights))
+	left := total
+	for i, w := range weights {
+		exact := total.Mul(w).Div(sum)
+		parts[i] = exact.RoundFloor(decimals)
+		remainders[i] = exact.Sub(parts[i])
+		left = left.Sub(parts[i])
+	}
+
+	order := make([]int, len(weights))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int { return remainders[b].Cmp(remainders[a]) })
+	unit := decimal.New(1, -decimals)
+	for k := 0; left.IsPositive(); k++ {
+		i := order[k%len(order)]
+		parts[i] = parts[i].Add(unit)
+		left = left.Sub(unit)
+	}
+	return parts
+}
@@ -0,0 +1,254 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/dto"
+	"github.com/sanches/finance-tracker-cc/backend/internal/store"
+)
+
+type mockDebtStore struct {
+	contacts     map[uuid.UUID]store.Contact
+	transactions map[uuid.UUID]store.Transaction
+	balances     []store.ListDebtBalancesRow
+	balance      pgtype.Numeric
+}
+
+func (m *mockDebtStore) CreateContact(ctx context.Context, arg store.CreateContactParams) (store.Contact, error) {
+	return store.Contact{ID: uuid.New(), UserID: arg.UserID, Name: arg.Name}, nil
+}
+
+func (m *mockDebtStore) GetContact(ctx context.Context, arg store.GetContactParams) (store.Contact, error) {
+	c, ok := m.contacts[arg.ID]
+	if !ok || c.UserID != arg.UserID {
+		return store.Contact{}, pgx.ErrNoRows
+	}
+	return c, nil
+}
+
+func (m *mockDebtStore) ListContacts(ctx context.Context, userID uuid.UUID) ([]store.Contact, error) {
+	return nil, nil
+}
+
+func (m *mockDebtStore) UpdateContact(ctx context.Context, arg store.UpdateContactParams) (store.Contact, error) {
+	return store.Contact{}, pgx.ErrNoRows
+}
+
+func (m *mockDebtStore) DeleteContact(ctx context.Context, arg store.DeleteContactParams) (int64, error) {
+	return 1, nil
+}
+
+func (m *mockDebtStore) HasContactDebts(ctx context.Context, contactID uuid.UUID) (bool, error) {
+	return true, nil
+}
+
+func (m *mockDebtStore) GetExpenseSplit(ctx context.Context, arg store.GetExpenseSplitParams) (store.ExpenseSplit, error) {
+	return store.ExpenseSplit{}, pgx.ErrNoRows
+}
+
+func (m *mockDebtStore) ListExpenseSplits(ctx context.Context, arg store.ListExpenseSplitsParams) ([]store.ExpenseSplit, error) {
+	return nil, nil
+}
+
+func (m *mockDebtStore) ListExpenseSplitShares(ctx context.Context, splitIds []uuid.UUID) ([]store.ListExpenseSplitSharesRow, error) {
+	return nil, nil
+}
+
+func (m *mockDebtStore) DeleteExpenseSplit(ctx context.Context, arg store.DeleteExpenseSplitParams) (int64, error) {
+	return 0, nil
+}
+
+func (m *mockDebtStore) ListDebtBalances(ctx context.Context, userID uuid.UUID) ([]store.ListDebtBalancesRow, error) {
+	return m.balances, nil
+}
+
+func (m *mockDebtStore) GetDebtBalance(ctx context.Context, arg store.GetDebtBalanceParams) (pgtype.Numeric, error) {
+	return m.balance, nil
+}
+
+func (m *mockDebtStore) GetTransaction(ctx context.Context, arg store.GetTransactionParams) (store.Transaction, error) {
+	t, ok := m.transactions[arg.ID]
+	if !ok || t.UserID != arg.UserID {
+		return store.Transaction{}, pgx.ErrNoRows
+	}
+	return t, nil
+}
+
+func (m *mockDebtStore) GetAccount(ctx context.Context, arg store.GetAccountParams) (store.Account, error) {
+	return store.Account{ID: arg.ID, UserID: arg.UserID, Currency: "USD"}, nil
+}
+
+func (m *mockDebtStore) WithTx(tx pgx.Tx) *store.Queries {
+	return nil
+}
+
+func TestAllocate(t *testing.T) {
+	d := decimal.RequireFromString
+	amounts := func(parts []decimal.Decimal, decimals int32) []string {
+		out := make([]string, len(parts))
+		for i, p := range parts {
+			out[i] = p.StringFixed(decimals)
+		}
+		return out
+	}
+	ones := []decimal.Decimal{d("1"), d("1"), d("1")}
+
+	require.Equal(t, []string{"33.34", "33.33", "33.33"}, amounts(allocate(d("100"), ones, 2), 2))
+	require.Equal(t, []string{"34", "33", "33"}, amounts(allocate(d("100"), ones, 0), 0))
+	require.Equal(t, []string{"0.01", "0.00", "0.00"}, amounts(allocate(d("0.01"), ones, 2), 2))
+	// The largest remainder gets the leftover cent, not the first part.
+	require.Equal(t, []string{"3.33", "6.67"}, amounts(allocate(d("10"), []decimal.Decimal{d("33.3"), d("66.7")}, 2), 2))
+	require.Equal(t, []string{"0.00", "10.00"}, amounts(allocate(d("10"), []decimal.Decimal{d("0"), d("100")}, 2), 2))
+}
+
+func TestSplitShares(t *testing.T) {
+	d := decimal.RequireFromString
+	participants := func(shares ...string) []dto.SplitParticipant {
+		out := make([]dto.SplitParticipant, len(shares))
+		for i, s := range shares {
+			out[i] = dto.SplitParticipant{Share: s}
+		}
+		return out
+	}
+
+	t.Run("equal ignores shares", func(t *testing.T) {
+		shares, err := splitShares(SplitEqual, d("50"), participants("x", ""), 2)
+		require.NoError(t, err)
+		require.Equal(t, "25", shares[0].String())
+		require.Equal(t, "25", shares[1].String())
+	})
+
+	t.Run("percentages must add up to 100", func(t *testing.T) {
+		_, err := splitShares(SplitPercentage, d("50"), participants("50", "40"), 2)
+		require.ErrorIs(t, err, ErrSplitShares)
+
+		shares, err := splitShares(SplitPercentage, d("80"), participants("25", "75"), 2)
+		require.NoError(t, err)
+		require.Equal(t, "20", shares[0].String())
+		require.Equal(t, "60", shares[1].String())
+	})
+
+	t.Run("exact shares must add up to the amount", func(t *testing.T) {
+		_, err := splitShares(SplitExact, d("50"), participants("20", "20"), 2)
+		require.ErrorIs(t, err, ErrSplitShares)
+
+		_, err = splitShares(SplitExact, d("50"), participants("20.001", "29.999"), 2)
+		require.ErrorIs(t, err, ErrAmountPrecision)
+
+		_, err = splitShares(SplitExact, d("50"), participants("-10", "60"), 2)
+		require.ErrorIs(t, err, ErrSplitShares)
+
+		shares, err := splitShares(SplitExact, d("50.00000000"), participants("20.50", "29.50"), 2)
+		require.NoError(t, err)
+		require.Equal(t, "20.5", shares[0].String())
+	})
+}
+
+func TestDebtCreateSplit_Rejected(t *testing.T) {
+	userID := uuid.New()
+	ann := store.Contact{ID: uuid.New(), UserID: userID, Name: "Ann"}
+	cara := store.Contact{ID: uuid.New(), UserID: userID, Name: "Cara"}
+	stranger := store.Contact{ID: uuid.New(), UserID: uuid.New(), Name: "Bob"}
+	income := store.Transaction{ID: uuid.New(), UserID: userID, Type: "income", Amount: numericFromString("30")}
+	transfer := store.Transaction{ID: uuid.New(), UserID: userID, Type: "expense", Amount: numericFromString("30"),
+		TransferID: pgtype.UUID{Bytes: uuid.New(), Valid: true}}
+	dinner := store.Transaction{ID: uuid.New(), UserID: userID, Type: "expense", Amount: numericFromString("30")}
+
+	svc := &Debt{
+		queries: &mockDebtStore{
+			contacts:     map[uuid.UUID]store.Contact{ann.ID: ann, cara.ID: cara, stranger.ID: stranger},
+			transactions: map[uuid.UUID]store.Transaction{income.ID: income, transfer.ID: transfer, dinner.ID: dinner},
+		},
+		currencies: testCurrencyPrecision(),
+	}
+	me := dto.SplitParticipant{}
+	withAnn := dto.SplitParticipant{ContactID: &ann.ID}
+	withStranger := dto.SplitParticipant{ContactID: &stranger.ID}
+
+	tests := []struct {
+		name string
+		req  dto.CreateSplitRequest
+		err  error
+	}{
+		{"no payer", dto.CreateSplitRequest{Method: SplitEqual, Participants: []dto.SplitParticipant{me, withAnn}}, ErrSplitPayer},
+		{"both payers", dto.CreateSplitRequest{TransactionID: &dinner.ID, PaidBy: &ann.ID, Method: SplitEqual,
+			Participants: []dto.SplitParticipant{me, withAnn}}, ErrSplitPayer},
+		{"unknown transaction", dto.CreateSplitRequest{TransactionID: &ann.ID, Method: SplitEqual,
+			Participants: []dto.SplitParticipant{me, withAnn}}, ErrNotFound},
+		{"income", dto.CreateSplitRequest{TransactionID: &income.ID, Method: SplitEqual,
+			Participants: []dto.SplitParticipant{me, withAnn}}, ErrSplitTransaction},
+		{"transfer", dto.CreateSplitRequest{TransactionID: &transfer.ID, Method: SplitEqual,
+			Participants: []dto.SplitParticipant{me, withAnn}}, ErrSplitTransaction},
+		{"someone else's contact", dto.CreateSplitRequest{TransactionID: &dinner.ID, Method: SplitEqual,
+			Participants: []dto.SplitParticipant{me, withStranger}}, ErrContactNotFound},
+		{"repeated contact", dto.CreateSplitRequest{TransactionID: &dinner.ID, Method: SplitEqual,
+			Participants: []dto.SplitParticipant{withAnn, withAnn}}, ErrSplitParticipants},
+		{"contact paid without the user", dto.CreateSplitRequest{PaidBy: &ann.ID, Amount: "30", Currency: "usd", Date: "2026-05-01",
+			Method: SplitEqual, Participants: []dto.SplitParticipant{withAnn, {ContactID: &cara.ID}}}, ErrSplitParticipants},
+		{"contact paid without an amount", dto.CreateSplitRequest{PaidBy: &ann.ID, Currency: "USD", Date: "2026-05-01",
+			Method: SplitEqual, Participants: []dto.SplitParticipant{me, withAnn}}, ErrInvalidSplit},
+		{"contact paid in an unknown currency", dto.CreateSplitRequest{PaidBy: &ann.ID, Amount: "30", Currency: "XXX", Date: "2026-05-01",
+			Method: SplitEqual, Participants: []dto.SplitParticipant{me, withAnn}}, ErrInvalidSplit},
+		{"shares off", dto.CreateSplitRequest{TransactionID: &dinner.ID, Method: SplitExact,
+			Participants: []dto.SplitParticipant{{Share: "10"}, {ContactID: &ann.ID, Share: "10"}}}, ErrSplitShares},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := svc.CreateSplit(context.Background(), userID, tc.req)
+			require.ErrorIs(t, err, tc.err)
+		})
+	}
+}
+
+func TestDebtSettle_NothingOwed(t *testing.T) {
+	userID := uuid.New()
+	ann := store.Contact{ID: uuid.New(), UserID: userID, Name: "Ann"}
+	mock := &mockDebtStore{contacts: map[uuid.UUID]store.Contact{ann.ID: ann}, balance: numericFromString("0")}
+	svc := &Debt{queries: mock, currencies: testCurrencyPrecision()}
+	req := dto.SettleRequest{AccountID: uuid.New(), Date: "2026-05-02"}
+
+	_, err := svc.Settle(context.Background(), userID, ann.ID, req)
+	require.ErrorIs(t, err, ErrNothingToSettle)
+
+	mock.balance = numericFromString("-25")
+	req.Amount = "30"
+	_, err = svc.Settle(context.Background(), userID, ann.ID, req)
+	require.ErrorIs(t, err, ErrInvalidSettlement)
+}
+
+func TestDebtReport(t *testing.T) {
+	ann, bob := uuid.New(), uuid.New()
+	svc := &Debt{
+		queries: &mockDebtStore{balances: []store.ListDebtBalancesRow{
+			{ContactID: ann, Name: "Ann", Currency: "USD", Balance: numericFromString("15")},
+			{ContactID: ann, Name: "Ann", Currency: "EUR", Balance: numericFromString("-4.5")},
+			{ContactID: bob, Name: "Bob", Currency: "USD", Balance: numericFromString("-20")},
+		}},
+		currencies: testCurrencyPrecision(),
+	}
+
+	report, err := svc.Report(context.Background(), uuid.New())
+	require.NoError(t, err)
+	require.Len(t, report.Data, 3)
+	require.Equal(t, "-4.50", report.Data[1].Balance)
+	require.Equal(t, []dto.DebtTotal{
+		{Currency: "EUR", OwedToYou: "0.00", YouOwe: "4.50", Net: "-4.50"},
+		{Currency: "USD", OwedToYou: "15.00", YouOwe: "20.00", Net: "-5.00"},
+	}, report.Totals)
+}
+
+func TestDebtDeleteContact_InUse(t *testing.T) {
+	userID := uuid.New()
+	ann := store.Contact{ID: uuid.New(), UserID: userID, Name: "Ann"}
+	svc := &Debt{queries: &mockDebtStore{contacts: map[uuid.UUID]store.Contact{ann.ID: ann}}}
+
+	require.ErrorIs(t, svc.DeleteContact(context.Background(), userID, ann.ID), ErrContactInUse)
+	require.ErrorIs(t, svc.DeleteContact(context.Background(), userID, uuid.New()), ErrContactNotFound)
+}
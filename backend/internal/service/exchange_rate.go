@@ -2,64 +2,199 @@ package service
 
 import (
 	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 
 	"github.com/sanches/finance-tracker-cc/backend/internal/dto"
 	"github.com/sanches/finance-tracker-cc/backend/internal/store"
 )
 
+// rateSourceManual marks rates entered through the API rather than synced
+// from a provider.
+const rateSourceManual = "manual"
+
+// Rate scopes: global rates are shared by every user and only admins may
+// write them; personal rates override global ones for their owner only.
+const (
+	RateScopeGlobal   = "global"
+	RateScopePersonal = "personal"
+)
+
+var ErrAdminRequired = errors.New("admin privileges required")
+
 type exchangeRateStore interface {
-	ListExchangeRates(ctx context.Context) ([]store.ExchangeRate, error)
+	ListExchangeRates(ctx context.Context, userID uuid.UUID) ([]store.ExchangeRate, error)
 	UpsertExchangeRate(ctx context.Context, arg store.UpsertExchangeRateParams) (store.ExchangeRate, error)
+	UpsertUserExchangeRate(ctx context.Context, arg store.UpsertUserExchangeRateParams) (store.ExchangeRate, error)
+	DeleteUserExchangeRate(ctx context.Context, arg store.DeleteUserExchangeRateParams) (int64, error)
+	GetUserByID(ctx context.Context, id uuid.UUID) (store.User, error)
 }
 
 type ExchangeRate struct {
-	queries exchangeRateStore
+	queries    exchangeRateStore
+	rates      *RateResolver
+	currencies *CurrencyPrecision
 }
 
-func NewExchangeRate(queries *store.Queries) *ExchangeRate {
-	return &ExchangeRate{queries: queries}
+func NewExchangeRate(queries *store.Queries, rates *RateResolver, currencies *CurrencyPrecision) *ExchangeRate {
+	return &ExchangeRate{queries: queries, rates: rates, currencies: currencies}
 }
 
-func (s *ExchangeRate) List(ctx context.Context) ([]dto.ExchangeRateResponse, error) {
-	rates, err := s.queries.ListExchangeRates(ctx)
+// List returns global rates and the user's personal overrides.
+func (s *ExchangeRate) List(ctx context.Context, userID uuid.UUID) ([]dto.ExchangeRateResponse, error) {
+	rates, err := s.queries.ListExchangeRates(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
 
 	result := make([]dto.ExchangeRateResponse, 0, len(rates))
 	for _, r := range rates {
-		result = append(result, dto.ExchangeRateResponse{
-			ID:           r.ID,
-			FromCurrency: r.FromCurrency,
-			ToCurrency:   r.ToCurrency,
-			Rate:         numericToString(r.Rate),
-			Date:         dateToString(r.Date),
-		})
+		result = append(result, exchangeRateToResponse(r))
 	}
 	return result, nil
 }
 
-func (s *ExchangeRate) Create(ctx context.Context, req dto.CreateExchangeRateRequest) (*dto.ExchangeRateResponse, error) {
+// Create upserts a rate. Personal is the default scope; writing a global rate
+// requires an admin and returns ErrAdminRequired otherwise.
+func (s *ExchangeRate) Create(ctx context.Context, userID uuid.UUID, req dto.CreateExchangeRateRequest) (*dto.ExchangeRateResponse, error) {
 	date, err := dateFromString(req.Date)
 	if err != nil {
 		return nil, err
 	}
 
-	rate, err := s.queries.UpsertExchangeRate(ctx, store.UpsertExchangeRateParams{
-		FromCurrency: req.FromCurrency,
-		ToCurrency:   req.ToCurrency,
-		Rate:         numericFromString(req.Rate),
-		Date:         date,
-	})
+	var rate store.ExchangeRate
+	if req.Scope == RateScopeGlobal {
+		user, err := s.queries.GetUserByID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !user.IsAdmin {
+			return nil, ErrAdminRequired
+		}
+		rate, err = s.queries.UpsertExchangeRate(ctx, store.UpsertExchangeRateParams{
+			FromCurrency: req.FromCurrency,
+			ToCurrency:   req.ToCurrency,
+			Rate:         numericFromString(req.Rate),
+			Date:         date,
+			Source:       rateSourceManual,
+		})
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		rate, err = s.queries.UpsertUserExchangeRate(ctx, store.UpsertUserExchangeRateParams{
+			UserID:       userID,
+			FromCurrency: req.FromCurrency,
+			ToCurrency:   req.ToCurrency,
+			Rate:         numericFromString(req.Rate),
+			Date:         date,
+			Source:       rateSourceManual,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	resp := exchangeRateToResponse(rate)
+	return &resp, nil
+}
+
+// Delete removes one of the user's personal rates. Global rates are never
+// deleted through the API; they are overwritten by the next sync.
+func (s *ExchangeRate) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	n, err := s.queries.DeleteUserExchangeRate(ctx, store.DeleteUserExchangeRateParams{ID: id, UserID: userID})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func exchangeRateToResponse(r store.ExchangeRate) dto.ExchangeRateResponse {
+	scope := RateScopeGlobal
+	if r.UserID.Valid {
+		scope = RateScopePersonal
+	}
+	return dto.ExchangeRateResponse{
+		ID:           r.ID,
+		FromCurrency: r.FromCurrency,
+		ToCurrency:   r.ToCurrency,
+		Rate:         formatRate(numericToDecimal(r.Rate)),
+		Date:         dateToString(r.Date),
+		Source:       r.Source,
+		Scope:        scope,
+	}
+}
+
+// Convert converts req.Amount between currencies using the rate in effect on
+// req.Date (today if empty), honouring the user's personal rates. Returns ErrRateNotFound if no rate can be
+// resolved.
+func (s *ExchangeRate) Convert(ctx context.Context, userID uuid.UUID, req dto.ConvertRequest) (*dto.ConvertResponse, error) {
+	amount, err := ParseMoney(req.Amount, req.From)
+	if err != nil {
+		return nil, err
+	}
+	date := time.Now().UTC().Truncate(24 * time.Hour)
+	if req.Date != "" {
+		if date, err = time.Parse("2006-01-02", req.Date); err != nil {
+			return nil, err
+		}
+	}
+
+	rate, err := s.rates.Resolve(ctx, userID, req.From, req.To, date)
+	if err != nil {
+		return nil, err
+	}
+	converted, err := amount.Convert(rate)
 	if err != nil {
 		return nil, err
 	}
+	prec, err := s.currencies.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	legs := make([]dto.ConvertRateLeg, 0, len(rate.Legs))
+	for _, leg := range rate.Legs {
+		scope := RateScopeGlobal
+		if leg.Personal {
+			scope = RateScopePersonal
+		}
+		legs = append(legs, dto.ConvertRateLeg{
+			FromCurrency: leg.From,
+			ToCurrency:   leg.To,
+			Rate:         formatRate(leg.Rate),
+			Date:         leg.Date.Format("2006-01-02"),
+			Source:       leg.Source,
+			Scope:        scope,
+			Inverted:     leg.Inverted,
+		})
+	}
+	resp := &dto.ConvertResponse{
+		Amount:          amount.Amount().String(),
+		From:            strings.ToUpper(req.From),
+		To:              strings.ToUpper(req.To),
+		Date:            date.Format("2006-01-02"),
+		ConvertedAmount: converted.Format(prec),
+		Rate:            formatRate(rate.Rate),
+		Method:          rate.Method,
+		Source:          rate.Source(),
+		Via:             rate.Via,
+		Legs:            legs,
+	}
+	if !rate.Date.IsZero() {
+		resp.RateDate = rate.Date.Format("2006-01-02")
+	}
+	return resp, nil
+}
 
-	return &dto.ExchangeRateResponse{
-		ID:           rate.ID,
-		FromCurrency: rate.FromCurrency,
-		ToCurrency:   rate.ToCurrency,
-		Rate:         numericToString(rate.Rate),
-		Date:         dateToString(rate.Date),
-	}, nil
+// formatRate rounds to the 8 decimal places exchange rates are stored with.
+func formatRate(r decimal.Decimal) string {
+	return r.Round(8).String()
 }
@@ -6,25 +6,52 @@ import (
 	"fmt"
 	"log/slog"
 	"strings"
+	"sync/atomic"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgtype"
+
 	"github.com/sanches/finance-tracker-cc/backend/internal/rateapi"
 	"github.com/sanches/finance-tracker-cc/backend/internal/store"
 )
 
+var ErrBackfillInProgress = errors.New("exchange rate backfill already in progress")
+
+// backfillScanLimit caps how many missing (pair, date) rows a single backfill
+// run looks at. Rows beyond it are picked up by the next run.
+const backfillScanLimit = 10000
+
+// backfillMaxAttempts is how many runs may fail to find a rate for a pair on
+// a date before the backfill gives up on it, so that dates the upstream
+// can't serve don't hold back newer gaps.
+const backfillMaxAttempts = 3
+
 type exchangeRateSyncStore interface {
 	ListDistinctAccountCurrencies(ctx context.Context) ([]string, error)
 	ListCurrencies(ctx context.Context) ([]store.Currency, error)
+	ListMissingRateDates(ctx context.Context, arg store.ListMissingRateDatesParams) ([]store.ListMissingRateDatesRow, error)
+	RecordRateMiss(ctx context.Context, arg store.RecordRateMissParams) error
 	UpsertExchangeRate(ctx context.Context, arg store.UpsertExchangeRateParams) (store.ExchangeRate, error)
 }
 
 type ExchangeRateSync struct {
 	queries exchangeRateSyncStore
 	fetcher rateapi.Fetcher
+
+	// backfillDelay is the pause between two upstream requests during a
+	// backfill; backfillMaxFetches caps the number of requests per run.
+	backfillDelay      time.Duration
+	backfillMaxFetches int
+	backfillRunning    atomic.Bool
 }
 
-func NewExchangeRateSync(queries *store.Queries, fetcher rateapi.Fetcher) *ExchangeRateSync {
-	return &ExchangeRateSync{queries: queries, fetcher: fetcher}
+func NewExchangeRateSync(queries *store.Queries, fetcher rateapi.Fetcher, backfillDelay time.Duration, backfillMaxFetches int) *ExchangeRateSync {
+	return &ExchangeRateSync{
+		queries:            queries,
+		fetcher:            fetcher,
+		backfillDelay:      backfillDelay,
+		backfillMaxFetches: backfillMaxFetches,
+	}
 }
 
 func (s *ExchangeRateSync) Sync(ctx context.Context) error {
@@ -37,14 +64,9 @@ func (s *ExchangeRateSync) Sync(ctx context.Context) error {
 		return nil
 	}
 
-	allCurrencies, err := s.queries.ListCurrencies(ctx)
+	known, err := s.knownCurrencies(ctx)
 	if err != nil {
-		return fmt.Errorf("list currencies: %w", err)
-	}
-
-	known := make(map[string]bool, len(allCurrencies))
-	for _, c := range allCurrencies {
-		known[strings.ToUpper(c.Code)] = true
+		return err
 	}
 
 	// Filter account currencies to only those in the currencies table.
@@ -64,36 +86,23 @@ func (s *ExchangeRateSync) Sync(ctx context.Context) error {
 	totalUpserted := 0
 
 	for _, base := range bases {
-		resp, err := s.fetcher.FetchRates(ctx, base)
+		resp, err := s.fetcher.FetchRates(ctx, base, time.Time{})
 		if err != nil {
 			slog.Warn("exchange rate sync: fetch failed", "base", base, "error", err)
 			errs = append(errs, fmt.Errorf("fetch %s: %w", base, err))
 			continue
 		}
 
-		upserted := 0
-		for target, rate := range resp.Rates {
-			targetUpper := strings.ToUpper(target)
-			if targetUpper == strings.ToUpper(base) {
-				continue
-			}
-			if !known[targetUpper] {
-				continue
-			}
-
-			_, err := s.queries.UpsertExchangeRate(ctx, store.UpsertExchangeRateParams{
-				FromCurrency: strings.ToUpper(base),
-				ToCurrency:   targetUpper,
-				Rate:         numericFromString(rate),
-				Date:         today,
-			})
-			if err != nil {
-				slog.Warn("exchange rate sync: upsert failed", "from", base, "to", target, "error", err)
-				errs = append(errs, fmt.Errorf("upsert %s->%s: %w", base, target, err))
-				continue
+		// Rates are stored under the day they were published for, which
+		// lags today on weekends, holidays and before the upstream updates.
+		date := today
+		if resp.Date != "" {
+			if d, err := dateFromString(resp.Date); err == nil {
+				date = d
 			}
-			upserted++
 		}
+		upserted, upsertErrs := s.upsertRates(ctx, base, resp, known, date)
+		errs = append(errs, upsertErrs...)
 
 		slog.Info("exchange rate sync: done", "base", base, "upserted", upserted)
 		totalUpserted += upserted
@@ -102,3 +111,184 @@ func (s *ExchangeRateSync) Sync(ctx context.Context) error {
 	slog.Info("exchange rate sync: complete", "total_upserted", totalUpserted, "errors", len(errs))
 	return errors.Join(errs...)
 }
+
+// BackfillResult summarizes a single backfill run.
+type BackfillResult struct {
+	Fetched  int
+	Upserted int
+	// Remaining is true when the run stopped at its fetch limit with missing
+	// dates left over; running the backfill again continues from there.
+	Remaining bool
+}
+
+// Backfill fetches historical rates for every date that has transactions in
+// an account whose currency differs from its owner's base currency but no
+// stored rate for that pair. One upstream request is made per (currency, date)
+// and every known target currency from the response is stored, so a single
+// request can fill several users' gaps at once.
+//
+// Progress is persisted as it goes and the work list is derived from what is
+// still missing in the database, so an interrupted or rate-limited run is
+// resumed simply by calling Backfill again. Requests are spaced by the
+// configured delay, and a run stops after the configured number of fetches.
+// A pair the upstream has no rate for on a date is recorded as a miss and
+// skipped after backfillMaxAttempts runs.
+func (s *ExchangeRateSync) Backfill(ctx context.Context) (*BackfillResult, error) {
+	if !s.backfillRunning.CompareAndSwap(false, true) {
+		return nil, ErrBackfillInProgress
+	}
+	defer s.backfillRunning.Store(false)
+
+	missing, err := s.queries.ListMissingRateDates(ctx, store.ListMissingRateDatesParams{
+		MaxAttempts: backfillMaxAttempts,
+		Lim:         backfillScanLimit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list missing rate dates: %w", err)
+	}
+	if len(missing) == 0 {
+		slog.Info("exchange rate backfill: nothing to do")
+		return &BackfillResult{}, nil
+	}
+
+	known, err := s.knownCurrencies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Collapse rows to one fetch per (base, date); rows are ordered by date
+	// so the oldest gaps are filled first.
+	type fetchKey struct {
+		base string
+		date pgtype.Date
+	}
+	var keys []fetchKey
+	targets := make(map[fetchKey][]string)
+	for _, m := range missing {
+		base := strings.ToUpper(m.FromCurrency)
+		target := strings.ToUpper(m.ToCurrency)
+		if !known[base] || !known[target] {
+			continue
+		}
+		k := fetchKey{base: base, date: m.Date}
+		if _, ok := targets[k]; !ok {
+			keys = append(keys, k)
+		}
+		targets[k] = append(targets[k], target)
+	}
+
+	result := &BackfillResult{}
+	if s.backfillMaxFetches > 0 && len(keys) > s.backfillMaxFetches {
+		keys = keys[:s.backfillMaxFetches]
+		result.Remaining = true
+	} else if len(missing) == backfillScanLimit {
+		result.Remaining = true
+	}
+
+	var errs []error
+	for i, k := range keys {
+		if i > 0 && s.backfillDelay > 0 {
+			select {
+			case <-time.After(s.backfillDelay):
+			case <-ctx.Done():
+				result.Remaining = true
+				errs = append(errs, ctx.Err())
+				slog.Info("exchange rate backfill: interrupted", "fetched", result.Fetched, "upserted", result.Upserted)
+				return result, errors.Join(errs...)
+			}
+		}
+
+		resp, err := s.fetcher.FetchRates(ctx, k.base, k.date.Time)
+		if err != nil {
+			slog.Warn("exchange rate backfill: fetch failed", "base", k.base, "date", dateToString(k.date), "error", err)
+			errs = append(errs, fmt.Errorf("fetch %s on %s: %w", k.base, dateToString(k.date), err))
+			s.recordMisses(ctx, k.base, targets[k], k.date)
+			continue
+		}
+		result.Fetched++
+
+		upserted, upsertErrs := s.upsertRates(ctx, k.base, resp, known, k.date)
+		errs = append(errs, upsertErrs...)
+		result.Upserted += upserted
+
+		var unanswered []string
+		for _, target := range targets[k] {
+			if _, ok := resp.Rates[strings.ToLower(target)]; !ok {
+				unanswered = append(unanswered, target)
+			}
+		}
+		s.recordMisses(ctx, k.base, unanswered, k.date)
+	}
+
+	slog.Info("exchange rate backfill: complete",
+		"fetched", result.Fetched,
+		"upserted", result.Upserted,
+		"remaining", result.Remaining,
+		"errors", len(errs),
+	)
+	return result, errors.Join(errs...)
+}
+
+// recordMisses notes that no rate from base to each of targets was found on
+// date. A failure to record one only means the date is tried again.
+func (s *ExchangeRateSync) recordMisses(ctx context.Context, base string, targets []string, date pgtype.Date) {
+	for _, target := range targets {
+		err := s.queries.RecordRateMiss(ctx, store.RecordRateMissParams{
+			FromCurrency: base,
+			ToCurrency:   target,
+			Date:         date,
+		})
+		if err != nil {
+			slog.Warn("exchange rate backfill: failed to record miss", "from", base, "to", target, "date", dateToString(date), "error", err)
+		}
+	}
+}
+
+// BackfillInProgress reports whether a Backfill call is currently running.
+func (s *ExchangeRateSync) BackfillInProgress() bool {
+	return s.backfillRunning.Load()
+}
+
+func (s *ExchangeRateSync) knownCurrencies(ctx context.Context) (map[string]bool, error) {
+	allCurrencies, err := s.queries.ListCurrencies(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list currencies: %w", err)
+	}
+
+	known := make(map[string]bool, len(allCurrencies))
+	for _, c := range allCurrencies {
+		known[strings.ToUpper(c.Code)] = true
+	}
+	return known, nil
+}
+
+// upsertRates stores every rate from resp whose target is a known currency
+// under the given date, skipping the base->base identity rate.
+func (s *ExchangeRateSync) upsertRates(ctx context.Context, base string, resp *rateapi.RatesResponse, known map[string]bool, date pgtype.Date) (int, []error) {
+	var errs []error
+	upserted := 0
+	for target, rate := range resp.Rates {
+		targetUpper := strings.ToUpper(target)
+		if targetUpper == strings.ToUpper(base) {
+			continue
+		}
+		if !known[targetUpper] {
+			continue
+		}
+
+		_, err := s.queries.UpsertExchangeRate(ctx, store.UpsertExchangeRateParams{
+			FromCurrency: strings.ToUpper(base),
+			ToCurrency:   targetUpper,
+			Rate:         numericFromString(rate),
+			Date:         date,
+			Source:       resp.SourceFor(target),
+		})
+		if err != nil {
+			slog.Warn("exchange rate sync: upsert failed", "from", base, "to", target, "error", err)
+			errs = append(errs, fmt.Errorf("upsert %s->%s: %w", base, target, err))
+			continue
+		}
+		upserted++
+	}
+	return upserted, errs
+}
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/rateapi"
+	"github.com/sanches/finance-tracker-cc/backend/internal/store"
+)
+
+type mockExchangeRateSyncStore struct {
+	listDistinctAccountCurrenciesFn func(ctx context.Context) ([]string, error)
+	listCurrenciesFn                func(ctx context.Context) ([]store.Currency, error)
+	listMissingRateDatesFn          func(ctx context.Context, arg store.ListMissingRateDatesParams) ([]store.ListMissingRateDatesRow, error)
+	upsertExchangeRateFn            func(ctx context.Context, arg store.UpsertExchangeRateParams) (store.ExchangeRate, error)
+	misses                          []store.RecordRateMissParams
+}
+
+func (m *mockExchangeRateSyncStore) ListDistinctAccountCurrencies(ctx context.Context) ([]string, error) {
+	return m.listDistinctAccountCurrenciesFn(ctx)
+}
+func (m *mockExchangeRateSyncStore) ListCurrencies(ctx context.Context) ([]store.Currency, error) {
+	return m.listCurrenciesFn(ctx)
+}
+func (m *mockExchangeRateSyncStore) ListMissingRateDates(ctx context.Context, arg store.ListMissingRateDatesParams) ([]store.ListMissingRateDatesRow, error) {
+	return m.listMissingRateDatesFn(ctx, arg)
+}
+func (m *mockExchangeRateSyncStore) RecordRateMiss(ctx context.Context, arg store.RecordRateMissParams) error {
+	m.misses = append(m.misses, arg)
+	return nil
+}
+func (m *mockExchangeRateSyncStore) UpsertExchangeRate(ctx context.Context, arg store.UpsertExchangeRateParams) (store.ExchangeRate, error) {
+	return m.upsertExchangeRateFn(ctx, arg)
+}
+
+type fetchCall struct {
+	base string
+	date time.Time
+}
+
+type mockFetcher struct {
+	calls   []fetchCall
+	fetchFn func(base string, date time.Time) (*rateapi.RatesResponse, error)
+}
+
+func (m *mockFetcher) FetchRates(_ context.Context, base string, date time.Time) (*rateapi.RatesResponse, error) {
+	m.calls = append(m.calls, fetchCall{base: base, date: date})
+	return m.fetchFn(base, date)
+}
+
+func mustDate(t *testing.T, s string) time.Time {
+	t.Helper()
+	d, err := time.Parse("2006-01-02", s)
+	require.NoError(t, err)
+	return d
+}
+
+func missingRow(t *testing.T, from, to, date string) store.ListMissingRateDatesRow {
+	d, err := dateFromString(date)
+	require.NoError(t, err)
+	return store.ListMissingRateDatesRow{FromCurrency: from, ToCurrency: to, Date: d}
+}
+
+func newBackfillStore(missing []store.ListMissingRateDatesRow, upserts *[]store.UpsertExchangeRateParams) *mockExchangeRateSyncStore {
+	return &mockExchangeRateSyncStore{
+		listCurrenciesFn: func(ctx context.Context) ([]store.Currency, error) {
+			return []store.Currency{{Code: "USD"}, {Code: "EUR"}, {Code: "AMD"}}, nil
+		},
+		listMissingRateDatesFn: func(ctx context.Context, arg store.ListMissingRateDatesParams) ([]store.ListMissingRateDatesRow, error) {
+			return missing, nil
+		},
+		upsertExchangeRateFn: func(ctx context.Context, arg store.UpsertExchangeRateParams) (store.ExchangeRate, error) {
+			*upserts = append(*upserts, arg)
+			return store.ExchangeRate{}, nil
+		},
+	}
+}
+
+func TestExchangeRateSyncBackfill_OneFetchPerCurrencyAndDate(t *testing.T) {
+	var upserts []store.UpsertExchangeRateParams
+	mockStore := newBackfillStore([]store.ListMissingRateDatesRow{
+		missingRow(t, "AMD", "USD", "2024-05-01"),
+		missingRow(t, "AMD", "EUR", "2024-05-01"), // same fetch as above
+		missingRow(t, "EUR", "USD", "2024-05-01"),
+		missingRow(t, "AMD", "USD", "2024-05-02"),
+	}, &upserts)
+	fetcher := &mockFetcher{fetchFn: func(base string, date time.Time) (*rateapi.RatesResponse, error) {
+		return &rateapi.RatesResponse{Rates: map[string]string{
+			"usd": "0.0026", "eur": "0.0024", "amd": "1", "xyz": "5",
+		}}, nil
+	}}
+
+	svc := &ExchangeRateSync{queries: mockStore, fetcher: fetcher}
+	result, err := svc.Backfill(context.Background())
+
+	require.NoError(t, err)
+	require.Equal(t, []fetchCall{
+		{base: "AMD", date: mustDate(t, "2024-05-01")},
+		{base: "EUR", date: mustDate(t, "2024-05-01")},
+		{base: "AMD", date: mustDate(t, "2024-05-02")},
+	}, fetcher.calls)
+	require.Equal(t, 3, result.Fetched)
+	require.False(t, result.Remaining)
+
+	// Identity and unknown currencies are skipped; everything is stored under
+	// the requested date rather than today.
+	require.Equal(t, 6, result.Upserted)
+	require.Len(t, upserts, 6)
+	for _, u := range upserts {
+		require.NotEqual(t, u.FromCurrency, u.ToCurrency)
+		require.NotEqual(t, "XYZ", u.ToCurrency)
+		require.True(t, u.Date.Time.Before(mustDate(t, "2024-05-03")))
+	}
+}
+
+func TestExchangeRateSyncBackfill_StopsAtMaxFetches(t *testing.T) {
+	var upserts []store.UpsertExchangeRateParams
+	mockStore := newBackfillStore([]store.ListMissingRateDatesRow{
+		missingRow(t, "AMD", "USD", "2024-05-01"),
+		missingRow(t, "AMD", "USD", "2024-05-02"),
+		missingRow(t, "AMD", "USD", "2024-05-03"),
+	}, &upserts)
+	fetcher := &mockFetcher{fetchFn: func(base string, date time.Time) (*rateapi.RatesResponse, error) {
+		return &rateapi.RatesResponse{Rates: map[string]string{"usd": "0.0026"}}, nil
+	}}
+
+	svc := &ExchangeRateSync{queries: mockStore, fetcher: fetcher, backfillMaxFetches: 2}
+	result, err := svc.Backfill(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, fetcher.calls, 2)
+	require.True(t, result.Remaining)
+}
+
+func TestExchangeRateSyncBackfill_ContinuesAfterFetchError(t *testing.T) {
+	var upserts []store.UpsertExchangeRateParams
+	mockStore := newBackfillStore([]store.ListMissingRateDatesRow{
+		missingRow(t, "AMD", "USD", "2024-05-01"),
+		missingRow(t, "AMD", "USD", "2024-05-02"),
+	}, &upserts)
+	fetcher := &mockFetcher{fetchFn: func(base string, date time.Time) (*rateapi.RatesResponse, error) {
+		if date.Day() == 1 {
+			return nil, errors.New("404")
+		}
+		return &rateapi.RatesResponse{Rates: map[string]string{"usd": "0.0026"}}, nil
+	}}
+
+	svc := &ExchangeRateSync{queries: mockStore, fetcher: fetcher}
+	result, err := svc.Backfill(context.Background())
+
+	require.Error(t, err)
+	require.Equal(t, 1, result.Fetched)
+	require.Len(t, upserts, 1)
+	require.Equal(t, "2024-05-02", dateToString(upserts[0].Date))
+	require.Len(t, mockStore.misses, 1)
+	require.Equal(t, "2024-05-01", dateToString(mockStore.misses[0].Date))
+}
+
+func TestExchangeRateSyncBackfill_RecordsUnansweredTargets(t *testing.T) {
+	var upserts []store.UpsertExchangeRateParams
+	mockStore := newBackfillStore([]store.ListMissingRateDatesRow{
+		missingRow(t, "EUR", "USD", "2024-05-01"),
+		missingRow(t, "EUR", "AMD", "2024-05-01"),
+	}, &upserts)
+	var maxAttempts int32
+	listMissing := mockStore.listMissingRateDatesFn
+	mockStore.listMissingRateDatesFn = func(ctx context.Context, arg store.ListMissingRateDatesParams) ([]store.ListMissingRateDatesRow, error) {
+		maxAttempts = arg.MaxAttempts
+		return listMissing(ctx, arg)
+	}
+	fetcher := &mockFetcher{fetchFn: func(base string, date time.Time) (*rateapi.RatesResponse, error) {
+		return &rateapi.RatesResponse{Rates: map[string]string{"usd": "1.08"}}, nil
+	}}
+
+	svc := &ExchangeRateSync{queries: mockStore, fetcher: fetcher}
+	_, err := svc.Backfill(context.Background())
+
+	require.NoError(t, err)
+	require.Equal(t, int32(backfillMaxAttempts), maxAttempts)
+	require.Equal(t, []store.RecordRateMissParams{
+		{FromCurrency: "EUR", ToCurrency: "AMD", Date: missingRow(t, "EUR", "AMD", "2024-05-01").Date},
+	}, mockStore.misses)
+}
+
+func TestExchangeRateSyncBackfill_RejectsConcurrentRun(t *testing.T) {
+	svc := &ExchangeRateSync{}
+	svc.backfillRunning.Store(true)
+
+	_, err := svc.Backfill(context.Background())
+
+	require.ErrorIs(t, err, ErrBackfillInProgress)
+}
+
+func TestExchangeRateSync_StoresUnderPublicationDate(t *testing.T) {
+	var upserts []store.UpsertExchangeRateParams
+	mockStore := newBackfillStore(nil, &upserts)
+	mockStore.listDistinctAccountCurrenciesFn = func(ctx context.Context) ([]string, error) {
+		return []string{"EUR"}, nil
+	}
+	fetcher := &mockFetcher{fetchFn: func(base string, date time.Time) (*rateapi.RatesResponse, error) {
+		return &rateapi.RatesResponse{Date: "2024-05-03", Rates: map[string]string{"usd": "1.07"}}, nil
+	}}
+
+	svc := &ExchangeRateSync{queries: mockStore, fetcher: fetcher}
+	require.NoError(t, svc.Sync(context.Background()))
+	require.Len(t, upserts, 1)
+	require.Equal(t, "2024-05-03", dateToString(upserts[0].Date))
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/dto"
+	"github.com/sanches/finance-tracker-cc/backend/internal/store"
+)
+
+type mockExchangeRateStore struct {
+	listExchangeRatesFn      func(ctx context.Context, userID uuid.UUID) ([]store.ExchangeRate, error)
+	upsertExchangeRateFn     func(ctx context.Context, arg store.UpsertExchangeRateParams) (store.ExchangeRate, error)
+	upsertUserExchangeRateFn func(ctx context.Context, arg store.UpsertUserExchangeRateParams) (store.ExchangeRate, error)
+	deleteUserExchangeRateFn func(ctx context.Context, arg store.DeleteUserExchangeRateParams) (int64, error)
+	getUserByIDFn            func(ctx context.Context, id uuid.UUID) (store.User, error)
+}
+
+func (m *mockExchangeRateStore) ListExchangeRates(ctx context.Context, userID uuid.UUID) ([]store.ExchangeRate, error) {
+	return m.listExchangeRatesFn(ctx, userID)
+}
+func (m *mockExchangeRateStore) UpsertExchangeRate(ctx context.Context, arg store.UpsertExchangeRateParams) (store.ExchangeRate, error) {
+	return m.upsertExchangeRateFn(ctx, arg)
+}
+func (m *mockExchangeRateStore) UpsertUserExchangeRate(ctx context.Context, arg store.UpsertUserExchangeRateParams) (store.ExchangeRate, error) {
+	return m.upsertUserExchangeRateFn(ctx, arg)
+}
+func (m *mockExchangeRateStore) DeleteUserExchangeRate(ctx context.Context, arg store.DeleteUserExchangeRateParams) (int64, error) {
+	return m.deleteUserExchangeRateFn(ctx, arg)
+}
+func (m *mockExchangeRateStore) GetUserByID(ctx context.Context, id uuid.UUID) (store.User, error) {
+	return m.getUserByIDFn(ctx, id)
+}
+
+func TestExchangeRateCreate(t *testing.T) {
+	userID := uuid.New()
+	req := dto.CreateExchangeRateRequest{FromCurrency: "AMD", ToCurrency: "EUR", Rate: "0.0024", Date: "2025-03-14"}
+
+	t.Run("personal by default", func(t *testing.T) {
+		mockStore := &mockExchangeRateStore{
+			upsertUserExchangeRateFn: func(ctx context.Context, arg store.UpsertUserExchangeRateParams) (store.ExchangeRate, error) {
+				require.Equal(t, userID, arg.UserID)
+				require.Equal(t, rateSourceManual, arg.Source)
+				return store.ExchangeRate{FromCurrency: arg.FromCurrency, ToCurrency: arg.ToCurrency, Rate: arg.Rate, Date: arg.Date, Source: arg.Source, UserID: pgtype.UUID{Bytes: userID, Valid: true}}, nil
+			},
+		}
+		svc := &ExchangeRate{queries: mockStore}
+
+		resp, err := svc.Create(context.Background(), userID, req)
+		require.NoError(t, err)
+		require.Equal(t, RateScopePersonal, resp.Scope)
+	})
+
+	t.Run("global requires admin", func(t *testing.T) {
+		mockStore := &mockExchangeRateStore{
+			getUserByIDFn: func(ctx context.Context, id uuid.UUID) (store.User, error) {
+				return store.User{ID: id}, nil
+			},
+		}
+		svc := &ExchangeRate{queries: mockStore}
+
+		global := req
+		global.Scope = RateScopeGlobal
+		_, err := svc.Create(context.Background(), userID, global)
+		require.ErrorIs(t, err, ErrAdminRequired)
+	})
+
+	t.Run("global by admin", func(t *testing.T) {
+		mockStore := &mockExchangeRateStore{
+			getUserByIDFn: func(ctx context.Context, id uuid.UUID) (store.User, error) {
+				return store.User{ID: id, IsAdmin: true}, nil
+			},
+			upsertExchangeRateFn: func(ctx context.Context, arg store.UpsertExchangeRateParams) (store.ExchangeRate, error) {
+				return store.ExchangeRate{FromCurrency: arg.FromCurrency, ToCurrency: arg.ToCurrency, Rate: arg.Rate, Date: arg.Date, Source: arg.Source}, nil
+			},
+		}
+		svc := &ExchangeRate{queries: mockStore}
+
+		global := req
+		global.Scope = RateScopeGlobal
+		resp, err := svc.Create(context.Background(), userID, global)
+		require.NoError(t, err)
+		require.Equal(t, RateScopeGlobal, resp.Scope)
+		require.Equal(t, rateSourceManual, resp.Source)
+	})
+}
+
+func TestExchangeRateList_MarksScope(t *testing.T) {
+	userID := uuid.New()
+	mockStore := &mockExchangeRateStore{
+		listExchangeRatesFn: func(ctx context.Context, id uuid.UUID) ([]store.ExchangeRate, error) {
+			require.Equal(t, userID, id)
+			return []store.ExchangeRate{
+				storedRate(t, "AMD", "EUR", "0.0024", "2025-03-14", "ecb"),
+				personalRate(t, userID, "AMD", "EUR", "0.0023", "2025-03-14"),
+			}, nil
+		},
+	}
+	svc := &ExchangeRate{queries: mockStore}
+
+	rates, err := svc.List(context.Background(), userID)
+	require.NoError(t, err)
+	require.Len(t, rates, 2)
+	require.Equal(t, RateScopeGlobal, rates[0].Scope)
+	require.Equal(t, RateScopePersonal, rates[1].Scope)
+}
+
+func TestExchangeRateDelete_NotFound(t *testing.T) {
+	mockStore := &mockExchangeRateStore{
+		deleteUserExchangeRateFn: func(ctx context.Context, arg store.DeleteUserExchangeRateParams) (int64, error) {
+			return 0, nil
+		},
+	}
+	svc := &ExchangeRate{queries: mockStore}
+
+	err := svc.Delete(context.Background(), uuid.New(), uuid.New())
+	require.ErrorIs(t, err, ErrNotFound)
+}
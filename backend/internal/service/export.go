@@ -31,11 +31,12 @@ type exportStore interface {
 }
 
 type Export struct {
-	queries exportStore
+	queries    exportStore
+	currencies *CurrencyPrecision
 }
 
-func NewExport(queries *store.Queries) *Export {
-	return &Export{queries: queries}
+func NewExport(queries *store.Queries, currencies *CurrencyPrecision) *Export {
+	return &Export{queries: queries, currencies: currencies}
 }
 
 func (s *Export) ExportCSV(ctx context.Context, userID uuid.UUID, dateFrom, dateTo string) ([]byte, error) {
@@ -57,6 +58,11 @@ func (s *Export) ExportCSV(ctx context.Context, userID uuid.UUID, dateFrom, date
 		return nil, fmt.Errorf("failed to query transactions: %w", err)
 	}
 
+	prec, err := s.currencies.Load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load currencies: %w", err)
+	}
+
 	var buf bytes.Buffer
 	w := csv.NewWriter(&buf)
 	w.Comma = ';'
@@ -78,7 +84,7 @@ func (s *Export) ExportCSV(ctx context.Context, userID uuid.UUID, dateFrom, date
 		}
 
 		// Signed amount: negative for expense, positive for income
-		amount := numericToString(row.Amount)
+		amount := prec.Format(row.Amount, row.Currency)
 		if row.Type == "expense" {
 			amount = "-" + amount
 		}
@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/dto"
+	"github.com/sanches/finance-tracker-cc/backend/internal/store"
+)
+
+// fxPosition is a foreign-currency balance together with what it cost in
+// base currency. Reducing the position realizes the difference between the
+// average cost and the rate of the reducing flow.
+type fxPosition struct {
+	units decimal.Decimal
+	cost  decimal.Decimal
+}
+
+func (p *fxPosition) avgRate() decimal.Decimal {
+	if p.units.IsZero() {
+		return decimal.Zero
+	}
+	return p.cost.Div(p.units)
+}
+
+// apply books qty units (negative for outflows) at rate and returns the
+// realized gain. A flow larger than the position closes it and opens a new
+// one in the opposite direction at rate.
+func (p *fxPosition) apply(qty, rate decimal.Decimal) decimal.Decimal {
+	realized := decimal.Zero
+	if !p.units.IsZero() && qty.Sign() != 0 && qty.Sign() != p.units.Sign() {
+		closed := qty
+		basis := qty.Mul(p.avgRate())
+		if qty.Abs().GreaterThanOrEqual(p.units.Abs()) {
+			closed = p.units.Neg()
+			basis = p.cost.Neg()
+		}
+		realized = basis.Sub(closed.Mul(rate))
+		p.units = p.units.Add(closed)
+		p.cost = p.cost.Add(basis)
+		qty = qty.Sub(closed)
+	}
+	p.units = p.units.Add(qty)
+	p.cost = p.cost.Add(qty.Mul(rate))
+	return realized
+}
+
+// FXGainLoss reports, for every account held in a currency other than the
+// user's base currency, how much of the change in its base-currency value over
+// the period came from flows and how much from exchange-rate moves.
+//
+// The opening balance is valued at the rate in effect the day before dateFrom
+// and becomes the starting cost. Each flow is booked at the rate of its date;
+// transfers to or from a base-currency account use the rate stored on the
+// transfer instead, and are listed as conversions. Outflows realize the
+// difference between that rate and the average cost; what remains at dateTo is
+// revalued at the closing rate (unrealized). For every account
+// closing_value - opening_value = net_flows + realized + unrealized.
+func (s *Report) FXGainLoss(ctx context.Context, userID uuid.UUID, dateFrom, dateTo string) (*dto.FXGainLossResponse, error) {
+	df, dt, err := parseDateRange(dateFrom, dateTo)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.queries.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	base := user.BaseCurrency
+
+	prec, err := s.currencies.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	baseDecimals := prec.Of(base)
+
+	accounts, err := s.queries.ListAccounts(ctx, store.ListAccountsParams{UserID: userID, IncludeArchived: true})
+	if err != nil {
+		return nil, err
+	}
+
+	openingRows, err := s.queries.CashFlowAccountOpeningBalances(ctx, store.CashFlowAccountOpeningBalancesParams{
+		DateFrom: df,
+		UserID:   userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	openings := make(map[uuid.UUID]decimal.Decimal, len(openingRows))
+	for _, r := range openingRows {
+		openings[r.AccountID] = numericToDecimal(r.OpeningBalance)
+	}
+
+	flowRows, err := s.queries.ListForeignCurrencyFlows(ctx, store.ListForeignCurrencyFlowsParams{
+		UserID:   userID,
+		DateFrom: df,
+		DateTo:   dt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	flows := make(map[uuid.UUID][]store.ListForeignCurrencyFlowsRow)
+	for _, f := range flowRows {
+		flows[f.AccountID] = append(flows[f.AccountID], f)
+	}
+
+	type rateKey struct {
+		currency string
+		date     time.Time
+	}
+	cache := make(map[rateKey]*ResolvedRate)
+	marketRate := func(currency string, date time.Time) (decimal.Decimal, bool, error) {
+		k := rateKey{currency: currency, date: date}
+		rate, ok := cache[k]
+		if !ok {
+			var err error
+			rate, err = s.rates.Resolve(ctx, userID, currency, base, date)
+			if err != nil && !errors.Is(err, ErrRateNotFound) {
+				return decimal.Zero, false, err
+			}
+			cache[k] = rate
+		}
+		if rate == nil {
+			return decimal.Zero, false, nil
+		}
+		return rate.Rate, true, nil
+	}
+
+	resp := &dto.FXGainLossResponse{
+		DateFrom:              dateToString(df),
+		DateTo:                dateToString(dt),
+		BaseCurrency:          base,
+		Accounts:              []dto.FXGainLossAccount{},
+		Conversions:           []dto.FXConversion{},
+		UnconvertedCurrencies: []string{},
+	}
+	unconverted := make(map[string]bool)
+	markUnconverted := func(currency string) {
+		if !unconverted[currency] {
+			unconverted[currency] = true
+			resp.UnconvertedCurrencies = append(resp.UnconvertedCurrencies, currency)
+		}
+	}
+
+	totalRealized := decimal.Zero
+	totalUnrealized := decimal.Zero
+
+accounts:
+	for _, a := range accounts {
+		if a.Currency == base {
+			continue
+		}
+		opening := openings[a.ID]
+		accountFlows := flows[a.ID]
+		if opening.IsZero() && len(accountFlows) == 0 {
+			continue
+		}
+
+		openingRate, ok, err := marketRate(a.Currency, df.Time.AddDate(0, 0, -1))
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			markUnconverted(a.Currency)
+			continue
+		}
+		closingRate, ok, err := marketRate(a.Currency, dt.Time)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			markUnconverted(a.Currency)
+			continue
+		}
+
+		pos := fxPosition{units: opening, cost: opening.Mul(openingRate)}
+		netFlows := decimal.Zero
+		realized := decimal.Zero
+		var conversions []dto.FXConversion
+
+		for _, f := range accountFlows {
+			qty := numericToDecimal(f.Amount)
+			if f.Type == "expense" {
+				qty = qty.Neg()
+			}
+
+			rate, isConversion := conversionRate(f, base)
+			if !isConversion {
+				rate, ok, err = marketRate(a.Currency, f.Date.Time)
+				if err != nil {
+					return nil, err
+				}
+				if !ok {
+					markUnconverted(a.Currency)
+					continue accounts
+				}
+			}
+
+			costRate := pos.avgRate()
+			gain := pos.apply(qty, rate)
+			netFlows = netFlows.Add(qty.Mul(rate))
+			realized = realized.Add(gain)
+
+			if isConversion {
+				conversions = append(conversions, dto.FXConversion{
+					TransactionID: f.ID,
+					TransferID:    f.TransferID.Bytes,
+					AccountID:     a.ID,
+					Date:          dateToString(f.Date),
+					Type:          f.Type,
+					Amount:        prec.Format(f.Amount, a.Currency),
+					Currency:      a.Currency,
+					BaseAmount:    qty.Abs().Mul(rate).StringFixed(baseDecimals),
+					Rate:          formatRate(rate),
+					CostRate:      formatRate(costRate),
+					Realized:      gain.StringFixed(baseDecimals),
+				})
+			}
+		}
+
+		openingValue := opening.Mul(openingRate)
+		closingValue := pos.units.Mul(closingRate)
+		unrealized := closingValue.Sub(pos.cost)
+
+		resp.Accounts = append(resp.Accounts, dto.FXGainLossAccount{
+			AccountID:      a.ID,
+			AccountName:    a.Name,
+			Currency:       a.Currency,
+			OpeningBalance: opening.StringFixed(prec.Of(a.Currency)),
+			ClosingBalance: pos.units.StringFixed(prec.Of(a.Currency)),
+			OpeningRate:    formatRate(openingRate),
+			ClosingRate:    formatRate(closingRate),
+			OpeningValue:   openingValue.StringFixed(baseDecimals),
+			ClosingValue:   closingValue.StringFixed(baseDecimals),
+			NetFlows:       netFlows.StringFixed(baseDecimals),
+			Realized:       realized.StringFixed(baseDecimals),
+			Unrealized:     unrealized.StringFixed(baseDecimals),
+			GainLoss:       realized.Add(unrealized).StringFixed(baseDecimals),
+		})
+		resp.Conversions = append(resp.Conversions, conversions...)
+		totalRealized = totalRealized.Add(realized)
+		totalUnrealized = totalUnrealized.Add(unrealized)
+	}
+
+	sort.SliceStable(resp.Conversions, func(i, j int) bool {
+		return resp.Conversions[i].Date < resp.Conversions[j].Date
+	})
+
+	resp.TotalRealized = totalRealized.StringFixed(baseDecimals)
+	resp.TotalUnrealized = totalUnrealized.StringFixed(baseDecimals)
+	resp.TotalGainLoss = totalRealized.Add(totalUnrealized).StringFixed(baseDecimals)
+	return resp, nil
+}
+
+// conversionRate returns the base-currency rate a transfer leg was actually
+// executed at when the other leg is in base currency. The stored exchange
+// rate is destination per source, so it is inverted for the receiving leg;
+// when it is missing the rate is derived from the two amounts.
+func conversionRate(f store.ListForeignCurrencyFlowsRow, base string) (decimal.Decimal, bool) {
+	if !f.TransferID.Valid || !f.CounterCurrency.Valid || f.CounterCurrency.String != base {
+		return decimal.Zero, false
+	}
+	if stored := numericToDecimal(f.ExchangeRate); stored.Sign() > 0 {
+		if f.Type == "expense" {
+			return stored, true
+		}
+		return decimal.NewFromInt(1).DivRound(stored, inverseRateScale), true
+	}
+	amount := numericToDecimal(f.Amount)
+	counter := numericToDecimal(f.CounterAmount)
+	if amount.Sign() <= 0 || counter.Sign() <= 0 {
+		return decimal.Zero, false
+	}
+	return counter.DivRound(amount, inverseRateScale), true
+}
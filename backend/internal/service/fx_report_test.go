@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/store"
+)
+
+// mockFXReportStore implements the parts of reportStore used by FXGainLoss;
+// calling any other method panics on the nil embedded interface.
+type mockFXReportStore struct {
+	reportStore
+	user     store.User
+	accounts []store.ListAccountsRow
+	openings []store.CashFlowAccountOpeningBalancesRow
+	flows    []store.ListForeignCurrencyFlowsRow
+}
+
+func (m *mockFXReportStore) GetUserByID(_ context.Context, _ uuid.UUID) (store.User, error) {
+	return m.user, nil
+}
+
+func (m *mockFXReportStore) ListAccounts(_ context.Context, _ store.ListAccountsParams) ([]store.ListAccountsRow, error) {
+	return m.accounts, nil
+}
+
+func (m *mockFXReportStore) CashFlowAccountOpeningBalances(_ context.Context, _ store.CashFlowAccountOpeningBalancesParams) ([]store.CashFlowAccountOpeningBalancesRow, error) {
+	return m.openings, nil
+}
+
+func (m *mockFXReportStore) ListForeignCurrencyFlows(_ context.Context, _ store.ListForeignCurrencyFlowsParams) ([]store.ListForeignCurrencyFlowsRow, error) {
+	return m.flows, nil
+}
+
+func fxFlow(t *testing.T, accountID uuid.UUID, txType, amount, date string) store.ListForeignCurrencyFlowsRow {
+	t.Helper()
+	d, err := dateFromString(date)
+	require.NoError(t, err)
+	return store.ListForeignCurrencyFlowsRow{ID: uuid.New(), AccountID: accountID, Type: txType, Amount: numericFromString(amount), Date: d}
+}
+
+func fxTransferLeg(t *testing.T, accountID uuid.UUID, txType, amount, date, rate, counterCurrency, counterAmount string) store.ListForeignCurrencyFlowsRow {
+	t.Helper()
+	f := fxFlow(t, accountID, txType, amount, date)
+	f.TransferID = pgtype.UUID{Bytes: uuid.New(), Valid: true}
+	if rate != "" {
+		f.ExchangeRate = numericFromString(rate)
+	}
+	f.CounterCurrency = pgtype.Text{String: counterCurrency, Valid: true}
+	f.CounterAmount = numericFromString(counterAmount)
+	return f
+}
+
+func TestReportFXGainLoss(t *testing.T) {
+	usdID, amdID, eurID := uuid.New(), uuid.New(), uuid.New()
+	st := &mockFXReportStore{
+		user: store.User{BaseCurrency: "EUR"},
+		accounts: []store.ListAccountsRow{
+			{ID: amdID, Name: "Cash AMD", Currency: "AMD"},
+			{ID: eurID, Name: "Checking", Currency: "EUR"},
+			{ID: usdID, Name: "Savings USD", Currency: "USD"},
+		},
+		openings: []store.CashFlowAccountOpeningBalancesRow{
+			{AccountID: usdID, Currency: "USD", OpeningBalance: numericFromString("1000")},
+			{AccountID: amdID, Currency: "AMD", OpeningBalance: numericFromString("50000")},
+			{AccountID: eurID, Currency: "EUR", OpeningBalance: numericFromString("3000")},
+		},
+		flows: []store.ListForeignCurrencyFlowsRow{
+			// Bought 500 USD for 400 EUR (EUR->USD 1.25, i.e. 0.80 EUR per USD).
+			fxTransferLeg(t, usdID, "income", "500", "2025-02-10", "1.25", "EUR", "400"),
+			// Sold 600 USD for 570 EUR (USD->EUR 0.95).
+			fxTransferLeg(t, usdID, "expense", "600", "2025-02-20", "0.95", "EUR", "570"),
+			// Spent 100 USD, valued at the market rate of the day.
+			fxFlow(t, usdID, "expense", "100", "2025-02-25"),
+		},
+	}
+	resolver := &RateResolver{queries: &mockRateStore{rates: []store.ExchangeRate{
+		storedRate(t, "USD", "EUR", "0.90", "2025-01-31", "ecb"),
+		storedRate(t, "USD", "EUR", "0.92", "2025-02-15", "ecb"),
+		storedRate(t, "USD", "EUR", "0.95", "2025-02-28", "ecb"),
+	}}}
+	svc := &Report{queries: st, rates: resolver}
+
+	resp, err := svc.FXGainLoss(context.Background(), uuid.New(), "2025-02-01", "2025-02-28")
+	require.NoError(t, err)
+	require.Equal(t, "EUR", resp.BaseCurrency)
+	require.Equal(t, []string{"AMD"}, resp.UnconvertedCurrencies)
+
+	require.Len(t, resp.Accounts, 1)
+	acct := resp.Accounts[0]
+	require.Equal(t, usdID, acct.AccountID)
+	require.Equal(t, "1000.00", acct.OpeningBalance)
+	require.Equal(t, "800.00", acct.ClosingBalance)
+	require.Equal(t, "0.9", acct.OpeningRate)
+	require.Equal(t, "0.95", acct.ClosingRate)
+	require.Equal(t, "900.00", acct.OpeningValue)
+	require.Equal(t, "760.00", acct.ClosingValue)
+	require.Equal(t, "-262.00", acct.NetFlows) // 400 - 570 - 92
+	require.Equal(t, "55.33", acct.Realized)   // 50 on the sale + 5.33 on the expense
+	require.Equal(t, "66.67", acct.Unrealized)
+	require.Equal(t, "122.00", acct.GainLoss)
+
+	require.Len(t, resp.Conversions, 2)
+	bought, sold := resp.Conversions[0], resp.Conversions[1]
+	require.Equal(t, "income", bought.Type)
+	require.Equal(t, "400.00", bought.BaseAmount)
+	require.Equal(t, "0.8", bought.Rate)
+	require.Equal(t, "0.00", bought.Realized)
+	require.Equal(t, "expense", sold.Type)
+	require.Equal(t, "570.00", sold.BaseAmount)
+	require.Equal(t, "0.86666667", sold.CostRate)
+	require.Equal(t, "50.00", sold.Realized)
+
+	require.Equal(t, "55.33", resp.TotalRealized)
+	require.Equal(t, "66.67", resp.TotalUnrealized)
+	require.Equal(t, "122.00", resp.TotalGainLoss)
+}
+
+func TestFXPositionApply(t *testing.T) {
+	d := decimal.RequireFromString
+
+	t.Run("reducing a long position realizes against average cost", func(t *testing.T) {
+		p := fxPosition{units: d("100"), cost: d("90")}
+		require.Equal(t, "2", p.apply(d("-40"), d("0.95")).String())
+		require.Equal(t, "60", p.units.String())
+		require.Equal(t, "54", p.cost.String())
+	})
+
+	t.Run("repaying a short position at a higher rate is a loss", func(t *testing.T) {
+		p := fxPosition{units: d("-100"), cost: d("-90")}
+		require.Equal(t, "-5", p.apply(d("100"), d("0.95")).String())
+		require.True(t, p.units.IsZero())
+		require.True(t, p.cost.IsZero())
+	})
+
+	t.Run("overshooting flips the position at the flow rate", func(t *testing.T) {
+		p := fxPosition{units: d("50"), cost: d("45")}
+		require.Equal(t, "5", p.apply(d("-80"), d("1")).String())
+		require.Equal(t, "-30", p.units.String())
+		require.Equal(t, "-30", p.cost.String())
+	})
+}
+
+func TestConversionRate(t *testing.T) {
+	// Receiving leg: stored EUR->USD rate is inverted.
+	f := fxTransferLeg(t, uuid.New(), "income", "500", "2025-02-01", "1.25", "EUR", "400")
+	rate, ok := conversionRate(f, "EUR")
+	require.True(t, ok)
+	require.Equal(t, "0.8", rate.String())
+
+	// No stored rate: derived from the two amounts.
+	f = fxTransferLeg(t, uuid.New(), "expense", "200", "2025-02-01", "", "EUR", "190")
+	rate, ok = conversionRate(f, "EUR")
+	require.True(t, ok)
+	require.Equal(t, "0.95", rate.String())
+
+	_, ok = conversionRate(f, "USD")
+	require.False(t, ok, "counter leg not in base currency")
+
+	_, ok = conversionRate(fxFlow(t, uuid.New(), "expense", "10", "2025-02-01"), "EUR")
+	require.False(t, ok, "not a transfer")
+}
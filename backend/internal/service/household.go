@@ -0,0 +1,287 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/dto"
+	"github.com/sanches/finance-tracker-cc/backend/internal/store"
+)
+
+// Household roles. Owners manage the household and its members, owners and
+// editors record transactions in its shared accounts, viewers only read.
+// RoleOwner is also the role a user has on their own accounts.
+const (
+	RoleOwner  = "owner"
+	RoleEditor = "editor"
+	RoleViewer = "viewer"
+)
+
+var (
+	ErrHouseholdNotFound = errors.New("household not found")
+	ErrNotHouseholdOwner = errors.New("only household owners can do this")
+	ErrHouseholdReadOnly = errors.New("your household role does not allow this change")
+	ErrInvalidHousehold  = errors.New("household_id must be a household you are an owner or editor of")
+	ErrUserNotFound      = errors.New("user not found")
+	ErrAlreadyMember     = errors.New("user is already a member of this household")
+	ErrMemberNotFound    = errors.New("household member not found")
+	ErrLastOwner         = errors.New("a household needs at least one owner")
+)
+
+// householdReader looks up a household with the caller's role in it.
+type householdReader interface {
+	GetHousehold(ctx context.Context, arg store.GetHouseholdParams) (store.GetHouseholdRow, error)
+}
+
+type householdStore interface {
+	householdReader
+	CreateHousehold(ctx context.Context, name string) (store.Household, error)
+	ListHouseholds(ctx context.Context, userID uuid.UUID) ([]store.ListHouseholdsRow, error)
+	UpdateHousehold(ctx context.Context, arg store.UpdateHouseholdParams) (store.Household, error)
+	DeleteHousehold(ctx context.Context, id uuid.UUID) error
+	AddHouseholdMember(ctx context.Context, arg store.AddHouseholdMemberParams) (store.HouseholdMember, error)
+	ListHouseholdMembers(ctx context.Context, householdID uuid.UUID) ([]store.ListHouseholdMembersRow, error)
+	UpdateHouseholdMemberRole(ctx context.Context, arg store.UpdateHouseholdMemberRoleParams) (store.HouseholdMember, error)
+	DeleteHouseholdMember(ctx context.Context, arg store.DeleteHouseholdMemberParams) (int64, error)
+	GetUserByUsername(ctx context.Context, username string) (store.User, error)
+	WithTx(tx pgx.Tx) *store.Queries
+}
+
+// Household manages households and their members. Accounts and categories
+// are shared with a household through their household_id.
+type Household struct {
+	queries householdStore
+	pool    *pgxpool.Pool
+}
+
+func NewHousehold(queries *store.Queries, pool *pgxpool.Pool) *Household {
+	return &Household{queries: queries, pool: pool}
+}
+
+func householdToResponse(id uuid.UUID, name, role string, createdAt, updatedAt pgtype.Timestamptz) *dto.HouseholdResponse {
+	return &dto.HouseholdResponse{
+		ID:        id,
+		Name:      name,
+		Role:      role,
+		CreatedAt: createdAt.Time,
+		UpdatedAt: updatedAt.Time,
+	}
+}
+
+// Create sets up a household with the user as its owner.
+func (s *Household) Create(ctx context.Context, userID uuid.UUID, req dto.HouseholdRequest) (*dto.HouseholdResponse, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	q := s.queries.WithTx(tx)
+	h, err := q.CreateHousehold(ctx, req.Name)
+	if err != nil {
+		return nil, err
+	}
+	_, err = q.AddHouseholdMember(ctx, store.AddHouseholdMemberParams{HouseholdID: h.ID, UserID: userID, Role: RoleOwner})
+	if err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return s.Get(ctx, userID, h.ID)
+}
+
+// List returns the households the user is a member of.
+func (s *Household) List(ctx context.Context, userID uuid.UUID) ([]dto.HouseholdResponse, error) {
+	rows, err := s.queries.ListHouseholds(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]dto.HouseholdResponse, 0, len(rows))
+	for _, h := range rows {
+		result = append(result, *householdToResponse(h.ID, h.Name, h.Role, h.CreatedAt, h.UpdatedAt))
+	}
+	return result, nil
+}
+
+// Get returns a household of the user with its members.
+func (s *Household) Get(ctx context.Context, userID, id uuid.UUID) (*dto.HouseholdResponse, error) {
+	h, err := s.member(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	members, err := s.queries.ListHouseholdMembers(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	resp := householdToResponse(h.ID, h.Name, h.Role, h.CreatedAt, h.UpdatedAt)
+	resp.Members = make([]dto.HouseholdMemberResponse, 0, len(members))
+	for _, m := range members {
+		resp.Members = append(resp.Members, dto.HouseholdMemberResponse{
+			UserID:      m.UserID,
+			Username:    m.Username,
+			DisplayName: m.DisplayName,
+			Role:        m.Role,
+			JoinedAt:    m.CreatedAt.Time,
+		})
+	}
+	return resp, nil
+}
+
+func (s *Household) Update(ctx context.Context, userID, id uuid.UUID, req dto.HouseholdRequest) (*dto.HouseholdResponse, error) {
+	if _, err := s.owned(ctx, userID, id); err != nil {
+		return nil, err
+	}
+	h, err := s.queries.UpdateHousehold(ctx, store.UpdateHouseholdParams{ID: id, Name: req.Name})
+	if err != nil {
+		return nil, err
+	}
+	return householdToResponse(h.ID, h.Name, RoleOwner, h.CreatedAt, h.UpdatedAt), nil
+}
+
+// Delete removes the household. Its accounts and categories stay with the
+// members who own them and are no longer shared.
+func (s *Household) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	if _, err := s.owned(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.queries.DeleteHousehold(ctx, id)
+}
+
+// AddMember adds an existing user to the household with the given role.
+func (s *Household) AddMember(ctx context.Context, userID, id uuid.UUID, req dto.AddHouseholdMemberRequest) (*dto.HouseholdResponse, error) {
+	if _, err := s.owned(ctx, userID, id); err != nil {
+		return nil, err
+	}
+	user, err := s.queries.GetUserByUsername(ctx, normalizeUsername(req.Username))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	_, err = s.queries.AddHouseholdMember(ctx, store.AddHouseholdMemberParams{HouseholdID: id, UserID: user.ID, Role: req.Role})
+	if err != nil {
+		if isDuplicateKey(err) {
+			return nil, ErrAlreadyMember
+		}
+		return nil, err
+	}
+	return s.Get(ctx, userID, id)
+}
+
+// UpdateMember changes a member's role. The last owner cannot be demoted.
+func (s *Household) UpdateMember(ctx context.Context, userID, id, memberID uuid.UUID, req dto.UpdateHouseholdMemberRequest) (*dto.HouseholdResponse, error) {
+	if _, err := s.owned(ctx, userID, id); err != nil {
+		return nil, err
+	}
+	if req.Role != RoleOwner {
+		if err := s.checkNotLastOwner(ctx, id, memberID); err != nil {
+			return nil, err
+		}
+	}
+	_, err := s.queries.UpdateHouseholdMemberRole(ctx, store.UpdateHouseholdMemberRoleParams{HouseholdID: id, UserID: memberID, Role: req.Role})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrMemberNotFound
+		}
+		return nil, err
+	}
+	return s.Get(ctx, userID, id)
+}
+
+// RemoveMember takes a member out of the household. Owners can remove
+// anyone, other members only themselves. The last owner cannot leave.
+func (s *Household) RemoveMember(ctx context.Context, userID, id, memberID uuid.UUID) error {
+	h, err := s.member(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if memberID != userID && h.Role != RoleOwner {
+		return ErrNotHouseholdOwner
+	}
+	if err := s.checkNotLastOwner(ctx, id, memberID); err != nil {
+		return err
+	}
+	n, err := s.queries.DeleteHouseholdMember(ctx, store.DeleteHouseholdMemberParams{HouseholdID: id, UserID: memberID})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrMemberNotFound
+	}
+	return nil
+}
+
+// member returns the household if the user belongs to it.
+func (s *Household) member(ctx context.Context, userID, id uuid.UUID) (store.GetHouseholdRow, error) {
+	h, err := s.queries.GetHousehold(ctx, store.GetHouseholdParams{ID: id, UserID: userID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return store.GetHouseholdRow{}, ErrHouseholdNotFound
+		}
+		return store.GetHouseholdRow{}, err
+	}
+	return h, nil
+}
+
+// owned returns the household if the user is one of its owners.
+func (s *Household) owned(ctx context.Context, userID, id uuid.UUID) (store.GetHouseholdRow, error) {
+	h, err := s.member(ctx, userID, id)
+	if err != nil {
+		return h, err
+	}
+	if h.Role != RoleOwner {
+		return h, ErrNotHouseholdOwner
+	}
+	return h, nil
+}
+
+// checkNotLastOwner returns ErrLastOwner if memberID is the household's
+// only owner.
+func (s *Household) checkNotLastOwner(ctx context.Context, id, memberID uuid.UUID) error {
+	members, err := s.queries.ListHouseholdMembers(ctx, id)
+	if err != nil {
+		return err
+	}
+	owners, isOwner := 0, false
+	for _, m := range members {
+		if m.Role == RoleOwner {
+			owners++
+			isOwner = isOwner || m.UserID == memberID
+		}
+	}
+	if isOwner && owners == 1 {
+		return ErrLastOwner
+	}
+	return nil
+}
+
+// sharedWith checks that the user may share an account or category with
+// the household and returns the household_id to store. Viewers cannot
+// share; nil means not shared.
+func sharedWith(ctx context.Context, queries householdReader, userID uuid.UUID, householdID *uuid.UUID) (pgtype.UUID, error) {
+	if householdID == nil {
+		return pgtype.UUID{}, nil
+	}
+	h, err := queries.GetHousehold(ctx, store.GetHouseholdParams{ID: *householdID, UserID: userID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return pgtype.UUID{}, ErrInvalidHousehold
+		}
+		return pgtype.UUID{}, err
+	}
+	if h.Role == RoleViewer {
+		return pgtype.UUID{}, ErrInvalidHousehold
+	}
+	return pgtype.UUID{Bytes: *householdID, Valid: true}, nil
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/dto"
+	"github.com/sanches/finance-tracker-cc/backend/internal/store"
+)
+
+type mockHouseholdStore struct {
+	members []store.ListHouseholdMembersRow
+
+	updateHouseholdMemberRoleFn func(ctx context.Context, arg store.UpdateHouseholdMemberRoleParams) (store.HouseholdMember, error)
+	deleteHouseholdMemberFn     func(ctx context.Context, arg store.DeleteHouseholdMemberParams) (int64, error)
+}
+
+func (m *mockHouseholdStore) GetHousehold(ctx context.Context, arg store.GetHouseholdParams) (store.GetHouseholdRow, error) {
+	for _, mem := range m.members {
+		if mem.UserID == arg.UserID {
+			return store.GetHouseholdRow{ID: arg.ID, Name: "Home", Role: mem.Role}, nil
+		}
+	}
+	return store.GetHouseholdRow{}, pgx.ErrNoRows
+}
+
+func (m *mockHouseholdStore) CreateHousehold(ctx context.Context, name string) (store.Household, error) {
+	return store.Household{}, nil
+}
+
+func (m *mockHouseholdStore) ListHouseholds(ctx context.Context, userID uuid.UUID) ([]store.ListHouseholdsRow, error) {
+	return nil, nil
+}
+
+func (m *mockHouseholdStore) UpdateHousehold(ctx context.Context, arg store.UpdateHouseholdParams) (store.Household, error) {
+	return store.Household{ID: arg.ID, Name: arg.Name}, nil
+}
+
+func (m *mockHouseholdStore) DeleteHousehold(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+func (m *mockHouseholdStore) AddHouseholdMember(ctx context.Context, arg store.AddHouseholdMemberParams) (store.HouseholdMember, error) {
+	return store.HouseholdMember{HouseholdID: arg.HouseholdID, UserID: arg.UserID, Role: arg.Role}, nil
+}
+
+func (m *mockHouseholdStore) ListHouseholdMembers(ctx context.Context, householdID uuid.UUID) ([]store.ListHouseholdMembersRow, error) {
+	return m.members, nil
+}
+
+func (m *mockHouseholdStore) UpdateHouseholdMemberRole(ctx context.Context, arg store.UpdateHouseholdMemberRoleParams) (store.HouseholdMember, error) {
+	return m.updateHouseholdMemberRoleFn(ctx, arg)
+}
+
+func (m *mockHouseholdStore) DeleteHouseholdMember(ctx context.Context, arg store.DeleteHouseholdMemberParams) (int64, error) {
+	return m.deleteHouseholdMemberFn(ctx, arg)
+}
+
+func (m *mockHouseholdStore) GetUserByUsername(ctx context.Context, username string) (store.User, error) {
+	return store.User{ID: uuid.New(), Username: username}, nil
+}
+
+func (m *mockHouseholdStore) WithTx(tx pgx.Tx) *store.Queries {
+	return nil
+}
+
+func TestHousehold_Roles(t *testing.T) {
+	ownerID := uuid.New()
+	editorID := uuid.New()
+	viewerID := uuid.New()
+	householdID := uuid.New()
+
+	newSvc := func() *Household {
+		return &Household{queries: &mockHouseholdStore{
+			members: []store.ListHouseholdMembersRow{
+				{UserID: ownerID, Role: RoleOwner},
+				{UserID: editorID, Role: RoleEditor},
+				{UserID: viewerID, Role: RoleViewer},
+			},
+			updateHouseholdMemberRoleFn: func(ctx context.Context, arg store.UpdateHouseholdMemberRoleParams) (store.HouseholdMember, error) {
+				return store.HouseholdMember{HouseholdID: arg.HouseholdID, UserID: arg.UserID, Role: arg.Role}, nil
+			},
+			deleteHouseholdMemberFn: func(ctx context.Context, arg store.DeleteHouseholdMemberParams) (int64, error) {
+				return 1, nil
+			},
+		}}
+	}
+
+	t.Run("only owners add members", func(t *testing.T) {
+		_, err := newSvc().AddMember(context.Background(), editorID, householdID, dto.AddHouseholdMemberRequest{Username: "ann", Role: RoleViewer})
+		require.ErrorIs(t, err, ErrNotHouseholdOwner)
+	})
+
+	t.Run("non-members do not see the household", func(t *testing.T) {
+		_, err := newSvc().Get(context.Background(), uuid.New(), householdID)
+		require.ErrorIs(t, err, ErrHouseholdNotFound)
+	})
+
+	t.Run("last owner cannot be demoted", func(t *testing.T) {
+		_, err := newSvc().UpdateMember(context.Background(), ownerID, householdID, ownerID, dto.UpdateHouseholdMemberRequest{Role: RoleEditor})
+		require.ErrorIs(t, err, ErrLastOwner)
+	})
+
+	t.Run("last owner cannot leave", func(t *testing.T) {
+		err := newSvc().RemoveMember(context.Background(), ownerID, householdID, ownerID)
+		require.ErrorIs(t, err, ErrLastOwner)
+	})
+
+	t.Run("members cannot remove others", func(t *testing.T) {
+		err := newSvc().RemoveMember(context.Background(), editorID, householdID, viewerID)
+		require.ErrorIs(t, err, ErrNotHouseholdOwner)
+	})
+
+	t.Run("members can leave", func(t *testing.T) {
+		require.NoError(t, newSvc().RemoveMember(context.Background(), viewerID, householdID, viewerID))
+	})
+
+	t.Run("viewers cannot share into the household", func(t *testing.T) {
+		svc := newSvc()
+		_, err := sharedWith(context.Background(), svc.queries, viewerID, &householdID)
+		require.ErrorIs(t, err, ErrInvalidHousehold)
+		shared, err := sharedWith(context.Background(), svc.queries, editorID, &householdID)
+		require.NoError(t, err)
+		require.True(t, shared.Valid)
+	})
+}
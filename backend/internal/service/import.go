@@ -9,7 +9,9 @@ import (
 	"strings"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/sanches/finance-tracker-cc/backend/internal/dto"
 	"github.com/sanches/finance-tracker-cc/backend/internal/store"
@@ -17,14 +19,19 @@ import (
 
 type importStore interface {
 	BulkCreateTransactions(ctx context.Context, arg []store.BulkCreateTransactionsParams) (int64, error)
+	BulkCreateTransactionVersions(ctx context.Context, arg []store.BulkCreateTransactionVersionsParams) (int64, error)
+	GetAccount(ctx context.Context, arg store.GetAccountParams) (store.Account, error)
+	WithTx(tx pgx.Tx) *store.Queries
 }
 
 type Import struct {
-	queries importStore
+	queries    importStore
+	pool       *pgxpool.Pool
+	currencies *CurrencyPrecision
 }
 
-func NewImport(queries *store.Queries) *Import {
-	return &Import{queries: queries}
+func NewImport(queries *store.Queries, pool *pgxpool.Pool, currencies *CurrencyPrecision) *Import {
+	return &Import{queries: queries, pool: pool, currencies: currencies}
 }
 
 func (s *Import) ParseCSV(r io.Reader) (*dto.CSVUploadResponse, error) {
@@ -69,10 +76,32 @@ func (s *Import) ParseCSV(r io.Reader) (*dto.CSVUploadResponse, error) {
 	}, nil
 }
 
-func (s *Import) ConfirmImport(ctx context.Context, userID uuid.UUID, req dto.CSVConfirmRequest) (int, error) {
+// ConfirmImport imports the mapped rows into the account. Rows with an
+// invalid date or amount, or an amount finer than the account's currency
+// allows, are skipped and reported in FailedRows.
+func (s *Import) ConfirmImport(ctx context.Context, userID uuid.UUID, req dto.CSVConfirmRequest) (*dto.CSVConfirmResponse, error) {
+	acct, err := s.queries.GetAccount(ctx, store.GetAccountParams{ID: req.AccountID, UserID: userID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrAccountNotFound
+		}
+		return nil, err
+	}
+	if acct.ArchivedOn.Valid {
+		return nil, ErrAccountArchived
+	}
+	prec, err := s.currencies.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	var params []store.BulkCreateTransactionsParams
+	resp := &dto.CSVConfirmResponse{FailedRows: []dto.CSVFailedRow{}}
+	fail := func(i int, msg string) {
+		resp.FailedRows = append(resp.FailedRows, dto.CSVFailedRow{RowNumber: i + 1, Error: msg})
+	}
 
-	for _, row := range req.Rows {
+	for i, row := range req.Rows {
 		dateStr := row.Values[req.Mapping.Date]
 		amountStr := row.Values[req.Mapping.Amount]
 		description := ""
@@ -82,11 +111,17 @@ func (s *Import) ConfirmImport(ctx context.Context, userID uuid.UUID, req dto.CS
 
 		date, err := dateFromString(dateStr)
 		if err != nil {
+			fail(i, "invalid date format, use YYYY-MM-DD")
 			continue
 		}
 
 		absStr, isNegative, err := parseAmount(amountStr)
 		if err != nil {
+			fail(i, err.Error())
+			continue
+		}
+		if err := prec.Check(absStr, acct.Currency); err != nil {
+			fail(i, err.Error())
 			continue
 		}
 
@@ -101,6 +136,7 @@ func (s *Import) ConfirmImport(ctx context.Context, userID uuid.UUID, req dto.CS
 		}
 
 		params = append(params, store.BulkCreateTransactionsParams{
+			ID:          uuid.New(),
 			UserID:      userID,
 			AccountID:   req.AccountID,
 			Type:        txnType,
@@ -112,15 +148,63 @@ func (s *Import) ConfirmImport(ctx context.Context, userID uuid.UUID, req dto.CS
 	}
 
 	if len(params) == 0 {
-		return 0, errors.New("no valid transactions found in CSV")
+		if len(resp.FailedRows) > 0 {
+			return resp, nil
+		}
+		return nil, errors.New("no valid transactions found in CSV")
 	}
 
-	count, err := s.queries.BulkCreateTransactions(ctx, params)
+	count, err := s.insert(ctx, userID, params)
+	if err != nil {
+		return nil, err
+	}
+	resp.Imported = int(count)
+	return resp, nil
+}
+
+// insert adds the imported transactions and their create versions in one
+// database transaction. Without a pool, as in unit tests, it runs on
+// s.queries directly.
+func (s *Import) insert(ctx context.Context, userID uuid.UUID, params []store.BulkCreateTransactionsParams) (int64, error) {
+	q := s.queries
+	var tx pgx.Tx
+	if s.pool != nil {
+		var err error
+		tx, err = s.pool.Begin(ctx)
+		if err != nil {
+			return 0, err
+		}
+		defer func() { _ = tx.Rollback(ctx) }()
+		q = s.queries.WithTx(tx)
+	}
+
+	count, err := q.BulkCreateTransactions(ctx, params)
 	if err != nil {
 		return 0, err
 	}
+	txns := make([]store.Transaction, len(params))
+	for i, p := range params {
+		txns[i] = store.Transaction{
+			ID:          p.ID,
+			AccountID:   p.AccountID,
+			CategoryID:  p.CategoryID,
+			Type:        p.Type,
+			Amount:      p.Amount,
+			Description: p.Description,
+			Date:        p.Date,
+			Status:      TransactionStatusPending,
+		}
+	}
+	if err := recordCreated(ctx, q, userID, txns); err != nil {
+		return 0, err
+	}
 
-	return int(count), nil
+	if tx != nil {
+		if err := tx.Commit(ctx); err != nil {
+			return 0, err
+		}
+	}
+	return count, nil
 }
 
 func parseAmount(s string) (string, bool, error) {
@@ -28,15 +28,17 @@ type importFullStore interface {
 	GetSubcategoryByNameAndType(ctx context.Context, arg store.GetSubcategoryByNameAndTypeParams) (store.Category, error)
 	CreateCategory(ctx context.Context, arg store.CreateCategoryParams) (store.Category, error)
 	BulkCreateTransactionsFull(ctx context.Context, arg []store.BulkCreateTransactionsFullParams) (int64, error)
+	GetUserByID(ctx context.Context, id uuid.UUID) (store.User, error)
 }
 
 type ImportFull struct {
-	queries importFullStore
-	pool    *pgxpool.Pool
+	queries    importFullStore
+	pool       *pgxpool.Pool
+	currencies *CurrencyPrecision
 }
 
-func NewImportFull(queries *store.Queries, pool *pgxpool.Pool) *ImportFull {
-	return &ImportFull{queries: queries, pool: pool}
+func NewImportFull(queries *store.Queries, pool *pgxpool.Pool, currencies *CurrencyPrecision) *ImportFull {
+	return &ImportFull{queries: queries, pool: pool, currencies: currencies}
 }
 
 // parsedRow holds a fully parsed CSV row ready for DB insertion.
@@ -66,7 +68,20 @@ type transactionInsertRow struct {
 	param     store.BulkCreateTransactionsFullParams
 }
 
+// Import creates accounts, categories and transactions from the rows.
+// Currencies are shared by the whole instance, so only admins may create
+// new ones; anyone else gets ErrAdminRequired.
 func (s *ImportFull) Import(ctx context.Context, userID uuid.UUID, req dto.FullImportRequest) (*dto.FullImportResponse, error) {
+	if len(req.NewCurrencies) > 0 {
+		user, err := s.queries.GetUserByID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !user.IsAdmin {
+			return nil, ErrAdminRequired
+		}
+	}
+
 	// Load all currencies for resolution
 	allCurrencies, err := s.queries.ListCurrencies(ctx)
 	if err != nil {
@@ -90,16 +105,17 @@ func (s *ImportFull) Import(ctx context.Context, userID uuid.UUID, req dto.FullI
 
 	// Step 1: Create new currencies
 	for _, nc := range req.NewCurrencies {
-		_, createErr := q.CreateCurrency(ctx, store.CreateCurrencyParams{
-			Code:   nc.Code,
-			Name:   nc.Name,
-			Symbol: nc.Symbol,
+		created, createErr := q.CreateCurrency(ctx, store.CreateCurrencyParams{
+			Code:     nc.Code,
+			Name:     nc.Name,
+			Symbol:   nc.Symbol,
+			Decimals: decimalsOrDefault(nc.Decimals),
 		})
 		if createErr != nil {
 			return nil, fmt.Errorf("failed to create currency %s: %w", nc.Code, createErr)
 		}
 		resp.CurrenciesCreated = append(resp.CurrenciesCreated, nc.Code)
-		allCurrencies = append(allCurrencies, store.Currency{Code: nc.Code, Name: nc.Name, Symbol: nc.Symbol})
+		allCurrencies = append(allCurrencies, created)
 	}
 
 	// Step 2: Parse all rows
@@ -239,6 +255,7 @@ func (s *ImportFull) Import(ctx context.Context, userID uuid.UUID, req dto.FullI
 			rowNumber: row.rowNumber,
 			data:      parsedRowToDTO(&row),
 			param: store.BulkCreateTransactionsFullParams{
+				ID:           uuid.New(),
 				UserID:       userID,
 				AccountID:    accountCache[row.account].ID,
 				CategoryID:   catID,
@@ -270,6 +287,7 @@ func (s *ImportFull) Import(ctx context.Context, userID uuid.UUID, req dto.FullI
 			rowNumber: pair.source.rowNumber,
 			data:      parsedRowToDTO(pair.source),
 			param: store.BulkCreateTransactionsFullParams{
+				ID:           uuid.New(),
 				UserID:       userID,
 				AccountID:    sourceAcct.ID,
 				CategoryID:   pgtype.UUID{Valid: false},
@@ -287,6 +305,7 @@ func (s *ImportFull) Import(ctx context.Context, userID uuid.UUID, req dto.FullI
 			rowNumber: pair.dest.rowNumber,
 			data:      parsedRowToDTO(pair.dest),
 			param: store.BulkCreateTransactionsFullParams{
+				ID:           uuid.New(),
 				UserID:       userID,
 				AccountID:    destAcct.ID,
 				CategoryID:   pgtype.UUID{Valid: false},
@@ -334,6 +353,24 @@ func (s *ImportFull) Import(ctx context.Context, userID uuid.UUID, req dto.FullI
 			}
 			return nil, fmt.Errorf("batch insert failed at offset %d: %w", i, batchErr)
 		}
+		txns := make([]store.Transaction, len(params))
+		for j, p := range params {
+			txns[j] = store.Transaction{
+				ID:           p.ID,
+				AccountID:    p.AccountID,
+				CategoryID:   p.CategoryID,
+				Type:         p.Type,
+				Amount:       p.Amount,
+				Description:  p.Description,
+				Date:         p.Date,
+				Status:       TransactionStatusPending,
+				TransferID:   p.TransferID,
+				ExchangeRate: p.ExchangeRate,
+			}
+		}
+		if historyErr := recordCreated(ctx, q, userID, txns); historyErr != nil {
+			return nil, fmt.Errorf("failed to record history at offset %d: %w", i, historyErr)
+		}
 		resp.Imported += int(count)
 	}
 
@@ -341,6 +378,9 @@ func (s *ImportFull) Import(ctx context.Context, userID uuid.UUID, req dto.FullI
 		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 	committed = true
+	if len(resp.CurrenciesCreated) > 0 {
+		s.currencies.Invalidate()
+	}
 
 	return resp, nil
 }
@@ -387,6 +427,9 @@ func (s *ImportFull) parseRow(
 	if err != nil {
 		return nil, fmt.Errorf("unresolved currency %q: %w", row.Currency, err)
 	}
+	if err := precisionsFromCurrencies(currencies).Check(absStr, currCode); err != nil {
+		return nil, fmt.Errorf("invalid amount %q: %w", row.Total, err)
+	}
 
 	// Determine type from amount sign
 	txnType := "income"
@@ -467,7 +510,7 @@ func computeTransferRate(srcAbs, dstAbs string) pgtype.Numeric {
 	if srcErr != nil || dstErr != nil || srcAmt.IsZero() {
 		return pgtype.Numeric{Valid: false}
 	}
-	return numericFromString(dstAmt.DivRound(srcAmt, 8).String())
+	return numericFromDecimal(dstAmt.DivRound(srcAmt, 8))
 }
 
 // isZeroDecimal reports whether a canonical decimal string (no sign, matching
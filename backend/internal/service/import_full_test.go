@@ -69,8 +69,11 @@ func TestImportAmountSumRoundTrip(t *testing.T) {
 	if err != nil {
 		t.Fatalf("parse 0.2: %v", err)
 	}
-	sum := numericAdd(numericFromString(a), numericFromString(b))
-	if got := numericToString(sum); got != "0.30" {
+	sum, err := MoneyFromNumeric(numericFromString(a), "USD").Add(MoneyFromNumeric(numericFromString(b), "USD"))
+	if err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if got := numericToString(sum.Numeric()); got != "0.30" {
 		t.Errorf("0.1 + 0.2 through import path = %q, want 0.30", got)
 	}
 }
@@ -110,12 +113,12 @@ func TestComputeTransferRate(t *testing.T) {
 
 func TestComputeTransferRatePrecision(t *testing.T) {
 	// DivRound(_, 8) should preserve 8 decimal places internally, even though
-	// numericToString truncates to 2 for display.
+	// amounts are displayed with their currency's precision.
 	got := computeTransferRate("3", "1")
 	if !got.Valid {
 		t.Fatalf("expected valid rate")
 	}
-	f := numericToBigFloat(got).Text('f', 8)
+	f := numericToDecimal(got).StringFixed(8)
 	if f != "0.33333333" {
 		t.Errorf("1/3 rate = %s, want 0.33333333", f)
 	}
@@ -1,12 +1,42 @@
 package service
 
 import (
+	"context"
 	"strings"
 	"testing"
 
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/stretchr/testify/require"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/dto"
+	"github.com/sanches/finance-tracker-cc/backend/internal/store"
 )
 
+type mockImportStore struct {
+	account  store.Account
+	inserted []store.BulkCreateTransactionsParams
+	versions []store.BulkCreateTransactionVersionsParams
+}
+
+func (m *mockImportStore) BulkCreateTransactions(_ context.Context, arg []store.BulkCreateTransactionsParams) (int64, error) {
+	m.inserted = append(m.inserted, arg...)
+	return int64(len(arg)), nil
+}
+
+func (m *mockImportStore) BulkCreateTransactionVersions(_ context.Context, arg []store.BulkCreateTransactionVersionsParams) (int64, error) {
+	m.versions = append(m.versions, arg...)
+	return int64(len(arg)), nil
+}
+
+func (m *mockImportStore) GetAccount(_ context.Context, arg store.GetAccountParams) (store.Account, error) {
+	return m.account, nil
+}
+
+func (m *mockImportStore) WithTx(_ pgx.Tx) *store.Queries {
+	return nil
+}
+
 func TestParseCSV(t *testing.T) {
 	svc := &Import{}
 
@@ -86,3 +116,43 @@ func TestParseAmount(t *testing.T) {
 		})
 	}
 }
+
+func TestConfirmImport_ReportsFailedRows(t *testing.T) {
+	ms := &mockImportStore{account: store.Account{ID: uuid.New(), Currency: "JPY"}}
+	svc := &Import{queries: ms, currencies: testCurrencyPrecision()}
+
+	row := func(date, amount string) dto.CSVPreviewRow {
+		return dto.CSVPreviewRow{Values: map[string]string{"date": date, "amount": amount}}
+	}
+	req := dto.CSVConfirmRequest{
+		AccountID: ms.account.ID,
+		Mapping:   dto.CSVColumnMapping{Date: "date", Amount: "amount"},
+		Rows: []dto.CSVPreviewRow{
+			row("2024-01-01", "-1500"),
+			row("2024-01-02", "-12.50"),
+			row("01/03/2024", "100"),
+			row("2024-01-04", "abc"),
+		},
+	}
+
+	userID := uuid.New()
+	resp, err := svc.ConfirmImport(context.Background(), userID, req)
+	require.NoError(t, err)
+	require.Equal(t, 1, resp.Imported)
+	require.Len(t, ms.inserted, 1)
+	require.Len(t, ms.versions, 1, "imported transactions get a create version")
+	require.Equal(t, ms.inserted[0].ID, ms.versions[0].TransactionID)
+	require.Equal(t, userID, ms.versions[0].ActorID)
+	require.Equal(t, HistoryCreate, ms.versions[0].Action)
+	require.Len(t, resp.FailedRows, 3)
+	require.Equal(t, 2, resp.FailedRows[0].RowNumber)
+	require.Equal(t, ErrAmountPrecision.Error(), resp.FailedRows[0].Error, "yen have no minor unit")
+	require.Equal(t, 3, resp.FailedRows[1].RowNumber)
+	require.Equal(t, 4, resp.FailedRows[2].RowNumber)
+
+	req.Rows = req.Rows[1:2]
+	resp, err = svc.ConfirmImport(context.Background(), uuid.New(), req)
+	require.NoError(t, err, "rows that all fail are reported, not an error")
+	require.Zero(t, resp.Imported)
+	require.Len(t, resp.FailedRows, 1)
+}
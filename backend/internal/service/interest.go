@@ -0,0 +1,503 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/dto"
+	"github.com/sanches/finance-tracker-cc/backend/internal/store"
+)
+
+var (
+	ErrNotDeposit           = errors.New("account is not a deposit account")
+	ErrInterestNotSetUp     = errors.New("account has no interest terms")
+	ErrInvalidInterest      = errors.New("annual_rate must be a percentage between 0 and 100")
+	ErrInvalidPayoutAccount = errors.New("payout_account_id must be one of your accounts in the same currency")
+)
+
+const accountTypeDeposit = "deposit"
+
+// InterestDescription is the description of the income transactions
+// created by interest accrual.
+const InterestDescription = "Interest"
+
+const (
+	CompoundingMonthly   = "monthly"
+	CompoundingQuarterly = "quarterly"
+)
+
+type interestStore interface {
+	GetAccount(ctx context.Context, arg store.GetAccountParams) (store.Account, error)
+	GetCategory(ctx context.Context, arg store.GetCategoryParams) (store.Category, error)
+	GetAccountInterest(ctx context.Context, arg store.GetAccountInterestParams) (store.AccountInterest, error)
+	UpsertAccountInterest(ctx context.Context, arg store.UpsertAccountInterestParams) (store.AccountInterest, error)
+	DeleteAccountInterest(ctx context.Context, arg store.DeleteAccountInterestParams) (int64, error)
+	ListAccountInterest(ctx context.Context) ([]store.AccountInterest, error)
+	GetInterestAccruedThrough(ctx context.Context, accountID uuid.UUID) (pgtype.Date, error)
+	ListInterestAccruals(ctx context.Context, arg store.ListInterestAccrualsParams) ([]store.InterestAccrual, error)
+	balanceReader
+	WithTx(tx pgx.Tx) *store.Queries
+}
+
+// balanceReader reads an account's daily balances, inside or outside a DB
+// transaction.
+type balanceReader interface {
+	GetAccountBalanceAt(ctx context.Context, arg store.GetAccountBalanceAtParams) (pgtype.Numeric, error)
+	BalanceHistory(ctx context.Context, arg store.BalanceHistoryParams) ([]store.BalanceHistoryRow, error)
+}
+
+// Interest accrues interest on deposit accounts. Each day earns the annual
+// rate over the days of its year on the day's closing balance (nothing on a
+// negative one); the sum is credited at the end of every compounding period
+// as an income transaction on the payout account, which defaults to the
+// account itself so that interest compounds.
+type Interest struct {
+	queries    interestStore
+	pool       *pgxpool.Pool
+	currencies *CurrencyPrecision
+}
+
+func NewInterest(queries *store.Queries, pool *pgxpool.Pool, currencies *CurrencyPrecision) *Interest {
+	return &Interest{queries: queries, pool: pool, currencies: currencies}
+}
+
+func (s *Interest) Update(ctx context.Context, userID, accountID uuid.UUID, req dto.UpdateInterestRequest) (*dto.InterestResponse, error) {
+	acct, err := s.account(ctx, userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+	rate, err := decimal.NewFromString(req.AnnualRate)
+	if err != nil || rate.IsNegative() || rate.GreaterThan(decimal.NewFromInt(100)) {
+		return nil, ErrInvalidInterest
+	}
+	start, err := dateFromString(req.StartDate)
+	if err != nil {
+		return nil, ErrInvalidInterest
+	}
+
+	payoutID := req.PayoutAccountID
+	if payoutID != nil && *payoutID == acct.ID {
+		payoutID = nil
+	}
+	if payoutID != nil {
+		payout, err := s.queries.GetAccount(ctx, store.GetAccountParams{ID: *payoutID, UserID: userID})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, ErrInvalidPayoutAccount
+			}
+			return nil, err
+		}
+		if payout.Currency != acct.Currency {
+			return nil, ErrInvalidPayoutAccount
+		}
+		if err := checkArchived(payout, nil); err != nil {
+			return nil, err
+		}
+	}
+	if req.CategoryID != nil {
+		cat, err := s.queries.GetCategory(ctx, store.GetCategoryParams{ID: *req.CategoryID, UserID: userID})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, ErrInvalidIncomeCategory
+			}
+			return nil, err
+		}
+		if cat.Type != "income" {
+			return nil, ErrInvalidIncomeCategory
+		}
+	}
+
+	terms, err := s.queries.UpsertAccountInterest(ctx, store.UpsertAccountInterestParams{
+		AccountID:       acct.ID,
+		UserID:          userID,
+		AnnualRate:      numericFromDecimal(rate),
+		Compounding:     req.Compounding,
+		StartDate:       start,
+		PayoutAccountID: uuidToNullable(payoutID),
+		CategoryID:      uuidToNullable(req.CategoryID),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.toResponse(ctx, acct, terms, time.Now())
+}
+
+// Get returns the interest terms with what the open period has earned so
+// far.
+func (s *Interest) Get(ctx context.Context, userID, accountID uuid.UUID) (*dto.InterestResponse, error) {
+	acct, terms, err := s.get(ctx, userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+	return s.toResponse(ctx, acct, terms, time.Now())
+}
+
+// Delete stops interest accrual. Credited periods and their transactions
+// are kept; setting terms again resumes after the last credited period.
+func (s *Interest) Delete(ctx context.Context, userID, accountID uuid.UUID) error {
+	n, err := s.queries.DeleteAccountInterest(ctx, store.DeleteAccountInterestParams{AccountID: accountID, UserID: userID})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrInterestNotSetUp
+	}
+	return nil
+}
+
+// ListAccruals returns the credited periods of an account, newest first.
+func (s *Interest) ListAccruals(ctx context.Context, userID, accountID uuid.UUID) ([]dto.InterestAccrualResponse, error) {
+	acct, err := s.account(ctx, userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+	accruals, err := s.queries.ListInterestAccruals(ctx, store.ListInterestAccrualsParams{AccountID: acct.ID, UserID: userID})
+	if err != nil {
+		return nil, err
+	}
+	prec, err := s.currencies.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.InterestAccrualResponse, 0, len(accruals))
+	for _, a := range accruals {
+		result = append(result, interestAccrualToResponse(a, prec.Of(acct.Currency)))
+	}
+	return result, nil
+}
+
+// Accrue credits the periods of one account that have ended, and returns
+// them.
+func (s *Interest) Accrue(ctx context.Context, userID, accountID uuid.UUID) ([]dto.InterestAccrualResponse, error) {
+	acct, terms, err := s.get(ctx, userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkArchived(acct, nil); err != nil {
+		return nil, err
+	}
+	prec, err := s.currencies.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	accruals, err := s.accrue(ctx, acct, terms, prec, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	result := make([]dto.InterestAccrualResponse, 0, len(accruals))
+	for _, a := range accruals {
+		result = append(result, interestAccrualToResponse(a, prec.Of(acct.Currency)))
+	}
+	return result, nil
+}
+
+// AccrueAll credits the ended periods of every account with interest terms
+// that is not archived. It is the scheduled job; a failing account is
+// logged and skipped.
+func (s *Interest) AccrueAll(ctx context.Context) error {
+	all, err := s.queries.ListAccountInterest(ctx)
+	if err != nil {
+		return fmt.Errorf("list interest terms: %w", err)
+	}
+	prec, err := s.currencies.Load(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var errs []error
+	credited := 0
+	for _, terms := range all {
+		acct, err := s.queries.GetAccount(ctx, store.GetAccountParams{ID: terms.AccountID, UserID: terms.UserID})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("account %s: %w", terms.AccountID, err))
+			continue
+		}
+		accruals, err := s.accrue(ctx, acct, terms, prec, now)
+		if err != nil {
+			slog.Error("interest accrual failed", "error", err, "account_id", acct.ID)
+			errs = append(errs, fmt.Errorf("account %s: %w", acct.ID, err))
+			continue
+		}
+		credited += len(accruals)
+	}
+	slog.Info("interest accrual complete", "accounts", len(all), "periods", credited)
+	return errors.Join(errs...)
+}
+
+// accrue credits every period from the day after the last credited one (or
+// the start date) that ended before today, oldest first, in one DB
+// transaction. Interest credited to the account itself counts in the
+// balances of the periods after it.
+func (s *Interest) accrue(ctx context.Context, acct store.Account, terms store.AccountInterest, prec Precisions, now time.Time) ([]store.InterestAccrual, error) {
+	from, err := s.accrueFrom(ctx, terms)
+	if err != nil {
+		return nil, err
+	}
+	periods := interestPeriods(from, terms.Compounding, startOfDay(now))
+	if len(periods) == 0 {
+		return nil, nil
+	}
+
+	payoutID := acct.ID
+	if terms.PayoutAccountID.Valid {
+		payout, err := s.queries.GetAccount(ctx, store.GetAccountParams{ID: terms.PayoutAccountID.Bytes, UserID: acct.UserID})
+		if err != nil {
+			return nil, err
+		}
+		if err := checkArchived(payout, nil); err != nil {
+			return nil, err
+		}
+		payoutID = payout.ID
+	}
+	decimals := prec.Of(acct.Currency)
+	rate := numericToDecimal(terms.AnnualRate)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	q := s.queries.WithTx(tx)
+	accruals := make([]store.InterestAccrual, 0, len(periods))
+	for _, p := range periods {
+		var amount decimal.Decimal
+		amount, err = periodInterest(ctx, q, acct, p, rate)
+		if err != nil {
+			return nil, err
+		}
+		amount = amount.Round(decimals)
+
+		var txnID pgtype.UUID
+		if amount.IsPositive() {
+			var t store.Transaction
+			t, err = createTransaction(ctx, q, acct.UserID, store.CreateTransactionParams{
+				UserID:      acct.UserID,
+				AccountID:   payoutID,
+				CategoryID:  terms.CategoryID,
+				Type:        "income",
+				Amount:      numericFromDecimal(amount),
+				Description: InterestDescription,
+				Date:        pgtype.Date{Time: p.end, Valid: true},
+			})
+			if err != nil {
+				return nil, err
+			}
+			txnID = pgtype.UUID{Bytes: t.ID, Valid: true}
+		}
+		var a store.InterestAccrual
+		a, err = q.CreateInterestAccrual(ctx, store.CreateInterestAccrualParams{
+			AccountID:     acct.ID,
+			UserID:        acct.UserID,
+			PeriodStart:   pgtype.Date{Time: p.start, Valid: true},
+			PeriodEnd:     pgtype.Date{Time: p.end, Valid: true},
+			Amount:        numericFromDecimal(amount),
+			TransactionID: txnID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		accruals = append(accruals, a)
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return accruals, nil
+}
+
+// accrueFrom is the first day not yet covered by a credited period.
+func (s *Interest) accrueFrom(ctx context.Context, terms store.AccountInterest) (time.Time, error) {
+	from := terms.StartDate.Time
+	through, err := s.queries.GetInterestAccruedThrough(ctx, terms.AccountID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if through.Valid && !through.Time.Before(from) {
+		from = through.Time.AddDate(0, 0, 1)
+	}
+	return from, nil
+}
+
+func (s *Interest) account(ctx context.Context, userID, accountID uuid.UUID) (store.Account, error) {
+	acct, err := s.queries.GetAccount(ctx, store.GetAccountParams{ID: accountID, UserID: userID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return store.Account{}, ErrAccountNotFound
+		}
+		return store.Account{}, err
+	}
+	if acct.Type != accountTypeDeposit {
+		return store.Account{}, ErrNotDeposit
+	}
+	return acct, nil
+}
+
+func (s *Interest) get(ctx context.Context, userID, accountID uuid.UUID) (store.Account, store.AccountInterest, error) {
+	acct, err := s.account(ctx, userID, accountID)
+	if err != nil {
+		return store.Account{}, store.AccountInterest{}, err
+	}
+	terms, err := s.queries.GetAccountInterest(ctx, store.GetAccountInterestParams{AccountID: accountID, UserID: userID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return store.Account{}, store.AccountInterest{}, ErrInterestNotSetUp
+		}
+		return store.Account{}, store.AccountInterest{}, err
+	}
+	return acct, terms, nil
+}
+
+func (s *Interest) toResponse(ctx context.Context, acct store.Account, terms store.AccountInterest, now time.Time) (*dto.InterestResponse, error) {
+	prec, err := s.currencies.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	through, err := s.queries.GetInterestAccruedThrough(ctx, acct.ID)
+	if err != nil {
+		return nil, err
+	}
+	from, err := s.accrueFrom(ctx, terms)
+	if err != nil {
+		return nil, err
+	}
+
+	decimals := prec.Of(acct.Currency)
+	rate := numericToDecimal(terms.AnnualRate)
+	today := startOfDay(now)
+	// The open period runs from the first uncredited day; its interest so
+	// far covers the days up to yesterday.
+	open := interestPeriod{start: from, end: periodEnd(from, terms.Compounding)}
+	for open.end.Before(today) {
+		open = interestPeriod{start: open.end.AddDate(0, 0, 1), end: periodEnd(open.end.AddDate(0, 0, 1), terms.Compounding)}
+	}
+	accrued := decimal.Zero
+	if yesterday := today.AddDate(0, 0, -1); !yesterday.Before(open.start) {
+		accrued, err = periodInterest(ctx, s.queries, acct, interestPeriod{start: open.start, end: yesterday}, rate)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	resp := &dto.InterestResponse{
+		AccountID:       acct.ID,
+		Currency:        acct.Currency,
+		AnnualRate:      rate.String(),
+		Compounding:     terms.Compounding,
+		StartDate:       dateToString(terms.StartDate),
+		PayoutAccountID: nullableToUUID(terms.PayoutAccountID),
+		CategoryID:      nullableToUUID(terms.CategoryID),
+		NextCreditDate:  open.end.Format("2006-01-02"),
+		AccruedToDate:   accrued.StringFixed(decimals),
+	}
+	if through.Valid {
+		d := dateToString(through)
+		resp.AccruedThrough = &d
+	}
+	return resp, nil
+}
+
+type interestPeriod struct {
+	start, end time.Time
+}
+
+// periodEnd is the last day of the compounding period containing d:
+// calendar months or calendar quarters.
+func periodEnd(d time.Time, compounding string) time.Time {
+	month := d.Month()
+	if compounding == CompoundingQuarterly {
+		month = (month-1)/3*3 + 3
+	}
+	return time.Date(d.Year(), month+1, 0, 0, 0, 0, 0, time.UTC)
+}
+
+// interestPeriods splits the days from from onwards into compounding
+// periods and returns those that ended before today. The first one may be
+// partial.
+func interestPeriods(from time.Time, compounding string, today time.Time) []interestPeriod {
+	var periods []interestPeriod
+	start := from
+	for {
+		end := periodEnd(start, compounding)
+		if !end.Before(today) {
+			return periods
+		}
+		periods = append(periods, interestPeriod{start: start, end: end})
+		start = end.AddDate(0, 0, 1)
+	}
+}
+
+// periodInterest is the unrounded interest an account earns over p.
+func periodInterest(ctx context.Context, q balanceReader, acct store.Account, p interestPeriod, annualRate decimal.Decimal) (decimal.Decimal, error) {
+	opening, err := q.GetAccountBalanceAt(ctx, store.GetAccountBalanceAtParams{
+		Date:      pgtype.Date{Time: p.start.AddDate(0, 0, -1), Valid: true},
+		AccountID: acct.ID,
+		UserID:    acct.UserID,
+	})
+	if err != nil {
+		return decimal.Zero, err
+	}
+	history, err := q.BalanceHistory(ctx, store.BalanceHistoryParams{
+		AccountID: acct.ID,
+		UserID:    acct.UserID,
+		DateFrom:  pgtype.Date{Time: p.start, Valid: true},
+		DateTo:    pgtype.Date{Time: p.end, Valid: true},
+	})
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return dailyInterest(numericToDecimal(opening), history, p, annualRate), nil
+}
+
+// dailyInterest sums, for each day of p, the closing balance times the
+// annual rate over the number of days in that year. history holds the
+// closing balance of the days that had transactions; other days keep the
+// previous balance, starting from opening.
+func dailyInterest(opening decimal.Decimal, history []store.BalanceHistoryRow, p interestPeriod, annualRate decimal.Decimal) decimal.Decimal {
+	rate := annualRate.Div(decimal.NewFromInt(100))
+	balance := opening
+	total := decimal.Zero
+	i := 0
+	for d := p.start; !d.After(p.end); d = d.AddDate(0, 0, 1) {
+		for i < len(history) && !history[i].Date.Time.After(d) {
+			balance = numericToDecimal(history[i].Balance)
+			i++
+		}
+		if balance.IsPositive() {
+			total = total.Add(balance.Mul(rate).Div(decimal.NewFromInt(int64(daysInYear(d.Year())))))
+		}
+	}
+	return total
+}
+
+func daysInYear(year int) int {
+	return time.Date(year, 12, 31, 0, 0, 0, 0, time.UTC).YearDay()
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func interestAccrualToResponse(a store.InterestAccrual, decimals int32) dto.InterestAccrualResponse {
+	return dto.InterestAccrualResponse{
+		PeriodStart:   dateToString(a.PeriodStart),
+		PeriodEnd:     dateToString(a.PeriodEnd),
+		Amount:        numericToDecimal(a.Amount).StringFixed(decimals),
+		TransactionID: nullableToUUID(a.TransactionID),
+	}
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/dto"
+	"github.com/sanches/finance-tracker-cc/backend/internal/store"
+)
+
+type mockInterestStore struct {
+	getAccountFn                func(ctx context.Context, arg store.GetAccountParams) (store.Account, error)
+	getCategoryFn               func(ctx context.Context, arg store.GetCategoryParams) (store.Category, error)
+	getAccountInterestFn        func(ctx context.Context, arg store.GetAccountInterestParams) (store.AccountInterest, error)
+	upsertAccountInterestFn     func(ctx context.Context, arg store.UpsertAccountInterestParams) (store.AccountInterest, error)
+	deleteAccountInterestFn     func(ctx context.Context, arg store.DeleteAccountInterestParams) (int64, error)
+	listAccountInterestFn       func(ctx context.Context) ([]store.AccountInterest, error)
+	getInterestAccruedThroughFn func(ctx context.Context, accountID uuid.UUID) (pgtype.Date, error)
+	listInterestAccrualsFn      func(ctx context.Context, arg store.ListInterestAccrualsParams) ([]store.InterestAccrual, error)
+	getAccountBalanceAtFn       func(ctx context.Context, arg store.GetAccountBalanceAtParams) (pgtype.Numeric, error)
+	balanceHistoryFn            func(ctx context.Context, arg store.BalanceHistoryParams) ([]store.BalanceHistoryRow, error)
+}
+
+func (m *mockInterestStore) GetAccount(ctx context.Context, arg store.GetAccountParams) (store.Account, error) {
+	return m.getAccountFn(ctx, arg)
+}
+
+func (m *mockInterestStore) GetCategory(ctx context.Context, arg store.GetCategoryParams) (store.Category, error) {
+	return m.getCategoryFn(ctx, arg)
+}
+
+func (m *mockInterestStore) GetAccountInterest(ctx context.Context, arg store.GetAccountInterestParams) (store.AccountInterest, error) {
+	return m.getAccountInterestFn(ctx, arg)
+}
+
+func (m *mockInterestStore) UpsertAccountInterest(ctx context.Context, arg store.UpsertAccountInterestParams) (store.AccountInterest, error) {
+	return m.upsertAccountInterestFn(ctx, arg)
+}
+
+func (m *mockInterestStore) DeleteAccountInterest(ctx context.Context, arg store.DeleteAccountInterestParams) (int64, error) {
+	return m.deleteAccountInterestFn(ctx, arg)
+}
+
+func (m *mockInterestStore) ListAccountInterest(ctx context.Context) ([]store.AccountInterest, error) {
+	return m.listAccountInterestFn(ctx)
+}
+
+func (m *mockInterestStore) GetInterestAccruedThrough(ctx context.Context, accountID uuid.UUID) (pgtype.Date, error) {
+	return m.getInterestAccruedThroughFn(ctx, accountID)
+}
+
+func (m *mockInterestStore) ListInterestAccruals(ctx context.Context, arg store.ListInterestAccrualsParams) ([]store.InterestAccrual, error) {
+	return m.listInterestAccrualsFn(ctx, arg)
+}
+
+func (m *mockInterestStore) GetAccountBalanceAt(ctx context.Context, arg store.GetAccountBalanceAtParams) (pgtype.Numeric, error) {
+	return m.getAccountBalanceAtFn(ctx, arg)
+}
+
+func (m *mockInterestStore) BalanceHistory(ctx context.Context, arg store.BalanceHistoryParams) ([]store.BalanceHistoryRow, error) {
+	return m.balanceHistoryFn(ctx, arg)
+}
+
+func (m *mockInterestStore) WithTx(tx pgx.Tx) *store.Queries {
+	return nil
+}
+
+func TestInterestPeriods(t *testing.T) {
+	t.Run("monthly, partial first period", func(t *testing.T) {
+		periods := interestPeriods(day("2026-01-15"), CompoundingMonthly, day("2026-03-31"))
+		require.Equal(t, []interestPeriod{
+			{start: day("2026-01-15"), end: day("2026-01-31")},
+			{start: day("2026-02-01"), end: day("2026-02-28")},
+		}, periods)
+	})
+
+	t.Run("quarterly", func(t *testing.T) {
+		periods := interestPeriods(day("2026-02-10"), CompoundingQuarterly, day("2026-10-01"))
+		require.Equal(t, []interestPeriod{
+			{start: day("2026-02-10"), end: day("2026-03-31")},
+			{start: day("2026-04-01"), end: day("2026-06-30")},
+			{start: day("2026-07-01"), end: day("2026-09-30")},
+		}, periods)
+	})
+
+	t.Run("open period is not credited", func(t *testing.T) {
+		require.Empty(t, interestPeriods(day("2026-03-01"), CompoundingMonthly, day("2026-03-31")))
+	})
+}
+
+func TestDailyInterest(t *testing.T) {
+	rate := decimal.NewFromInt(5)
+
+	t.Run("constant balance over a month", func(t *testing.T) {
+		p := interestPeriod{start: day("2026-01-01"), end: day("2026-01-31")}
+		got := dailyInterest(decimal.NewFromInt(3650), nil, p, rate)
+		// 3650 * 5% / 365 = 0.5 a day
+		require.Equal(t, "15.5", got.String())
+	})
+
+	t.Run("balance changes and negative days", func(t *testing.T) {
+		p := interestPeriod{start: day("2026-01-01"), end: day("2026-01-10")}
+		history := []store.BalanceHistoryRow{
+			{Date: pgtype.Date{Time: day("2026-01-04"), Valid: true}, Balance: numericFromString("-100")},
+			{Date: pgtype.Date{Time: day("2026-01-08"), Valid: true}, Balance: numericFromString("7300")},
+		}
+		got := dailyInterest(decimal.NewFromInt(3650), history, p, rate)
+		// 3 days at 0.5, 4 days at nothing, 3 days at 1
+		require.Equal(t, "4.5", got.String())
+	})
+
+	t.Run("leap year", func(t *testing.T) {
+		p := interestPeriod{start: day("2028-02-29"), end: day("2028-02-29")}
+		got := dailyInterest(decimal.NewFromInt(3660), nil, p, rate)
+		require.Equal(t, "0.5", got.String())
+	})
+}
+
+func TestInterestUpdate_Validation(t *testing.T) {
+	userID := uuid.New()
+	accountID := uuid.New()
+	otherID := uuid.New()
+	accounts := map[uuid.UUID]store.Account{
+		accountID: {ID: accountID, UserID: userID, Type: "deposit", Currency: "EUR"},
+		otherID:   {ID: otherID, UserID: userID, Type: "deposit", Currency: "USD"},
+	}
+	mock := &mockInterestStore{
+		getAccountFn: func(ctx context.Context, arg store.GetAccountParams) (store.Account, error) {
+			acct, ok := accounts[arg.ID]
+			if !ok {
+				return store.Account{}, pgx.ErrNoRows
+			}
+			return acct, nil
+		},
+		getCategoryFn: func(ctx context.Context, arg store.GetCategoryParams) (store.Category, error) {
+			return store.Category{ID: arg.ID, Type: "expense"}, nil
+		},
+	}
+	svc := &Interest{queries: mock, currencies: testCurrencyPrecision()}
+	ctx := context.Background()
+	valid := dto.UpdateInterestRequest{AnnualRate: "3.5", Compounding: CompoundingMonthly, StartDate: "2026-01-01"}
+
+	for _, rate := range []string{"-1", "100.01", "abc"} {
+		req := valid
+		req.AnnualRate = rate
+		_, err := svc.Update(ctx, userID, accountID, req)
+		require.ErrorIs(t, err, ErrInvalidInterest, rate)
+	}
+
+	req := valid
+	req.PayoutAccountID = &otherID
+	_, err := svc.Update(ctx, userID, accountID, req)
+	require.ErrorIs(t, err, ErrInvalidPayoutAccount)
+
+	missing := uuid.New()
+	req.PayoutAccountID = &missing
+	_, err = svc.Update(ctx, userID, accountID, req)
+	require.ErrorIs(t, err, ErrInvalidPayoutAccount)
+
+	category := uuid.New()
+	req = valid
+	req.CategoryID = &category
+	_, err = svc.Update(ctx, userID, accountID, req)
+	require.ErrorIs(t, err, ErrInvalidIncomeCategory)
+
+	accounts[accountID] = store.Account{ID: accountID, UserID: userID, Type: "cash", Currency: "EUR"}
+	_, err = svc.Update(ctx, userID, accountID, valid)
+	require.ErrorIs(t, err, ErrNotDeposit)
+}
+
+func TestInterestDelete_NotSetUp(t *testing.T) {
+	svc := &Interest{queries: &mockInterestStore{
+		deleteAccountInterestFn: func(ctx context.Context, arg store.DeleteAccountInterestParams) (int64, error) {
+			return 0, nil
+		},
+	}}
+
+	err := svc.Delete(context.Background(), uuid.New(), uuid.New())
+	require.ErrorIs(t, err, ErrInterestNotSetUp)
+}
@@ -0,0 +1,513 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/dto"
+	"github.com/sanches/finance-tracker-cc/backend/internal/store"
+)
+
+var (
+	ErrNotInvestment         = errors.New("account is not an investment account")
+	ErrTradeNotFound         = errors.New("trade not found")
+	ErrInvalidTrade          = errors.New("buys and sells need a positive quantity and price and a non-negative fee, dividends a positive amount")
+	ErrTradeCurrency         = errors.New("security currency does not match the account currency")
+	ErrInsufficientShares    = errors.New("not enough shares held to sell")
+	ErrInvalidIncomeCategory = errors.New("category_id must be one of your income categories")
+)
+
+const accountTypeInvestment = "investment"
+
+const (
+	TradeTypeBuy      = "buy"
+	TradeTypeSell     = "sell"
+	TradeTypeDividend = "dividend"
+)
+
+// quantityDecimals is the scale of trade quantities and prices in the
+// database.
+const quantityDecimals = 8
+
+type investmentStore interface {
+	GetAccount(ctx context.Context, arg store.GetAccountParams) (store.Account, error)
+	GetCategory(ctx context.Context, arg store.GetCategoryParams) (store.Category, error)
+	GetSecurity(ctx context.Context, arg store.GetSecurityParams) (store.Security, error)
+	ListSecurities(ctx context.Context, userID uuid.UUID) ([]store.ListSecuritiesRow, error)
+	GetTrade(ctx context.Context, arg store.GetTradeParams) (store.Trade, error)
+	ListTrades(ctx context.Context, arg store.ListTradesParams) ([]store.ListTradesRow, error)
+	GetTransaction(ctx context.Context, arg store.GetTransactionParams) (store.Transaction, error)
+	DeleteTransaction(ctx context.Context, arg store.DeleteTransactionParams) error
+	WithTx(tx pgx.Tx) *store.Queries
+}
+
+// Investment records trades on investment accounts and derives holdings
+// from them. Every trade has a cash transaction on the account: a buy is an
+// expense of quantity × price + fee, a sell an income of quantity × price −
+// fee, both carrying the trade id as transfer_id so that reports see a move
+// between cash and securities; a dividend is plain income. Sells consume
+// the oldest lots first (FIFO), and a lot's cost basis is what its buy paid.
+type Investment struct {
+	queries    investmentStore
+	pool       *pgxpool.Pool
+	currencies *CurrencyPrecision
+}
+
+func NewInvestment(queries *store.Queries, pool *pgxpool.Pool, currencies *CurrencyPrecision) *Investment {
+	return &Investment{queries: queries, pool: pool, currencies: currencies}
+}
+
+func (s *Investment) CreateTrade(ctx context.Context, userID uuid.UUID, req dto.CreateTradeRequest) (*dto.TradeResponse, error) {
+	acct, err := s.account(ctx, userID, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkArchived(acct, nil); err != nil {
+		return nil, err
+	}
+	sec, err := s.queries.GetSecurity(ctx, store.GetSecurityParams{ID: req.SecurityID, UserID: userID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrSecurityNotFound
+		}
+		return nil, err
+	}
+	if sec.Currency != acct.Currency {
+		return nil, ErrTradeCurrency
+	}
+	date, err := dateFromString(req.Date)
+	if err != nil {
+		return nil, ErrInvalidTrade
+	}
+	prec, err := s.currencies.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	decimals := prec.Of(acct.Currency)
+
+	id := uuid.New()
+	trade := store.ListTradesRow{
+		ID:         id,
+		UserID:     userID,
+		AccountID:  acct.ID,
+		SecurityID: sec.ID,
+		Type:       req.Type,
+		Date:       date,
+		Symbol:     sec.Symbol,
+	}
+	txn := store.CreateTransactionParams{
+		UserID:    userID,
+		AccountID: acct.ID,
+		Date:      date,
+	}
+
+	if req.Type == TradeTypeDividend {
+		amount, err := parsePositiveDecimal(req.Amount)
+		if err != nil || !amount.IsPositive() {
+			return nil, ErrInvalidTrade
+		}
+		if err := prec.Check(req.Amount, acct.Currency); err != nil {
+			return nil, err
+		}
+		if req.CategoryID != nil {
+			cat, err := s.queries.GetCategory(ctx, store.GetCategoryParams{ID: *req.CategoryID, UserID: userID})
+			if err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					return nil, ErrInvalidIncomeCategory
+				}
+				return nil, err
+			}
+			if cat.Type != "income" {
+				return nil, ErrInvalidIncomeCategory
+			}
+		}
+		trade.Quantity = numericFromDecimal(decimal.Zero)
+		trade.Price = numericFromDecimal(decimal.Zero)
+		trade.Fee = numericFromDecimal(decimal.Zero)
+		trade.Amount = numericFromDecimal(amount)
+		txn.Type = "income"
+		txn.CategoryID = uuidToNullable(req.CategoryID)
+		txn.Description = "Dividend " + sec.Symbol
+	} else {
+		quantity, price, fee, err := parseTradeTerms(req)
+		if err != nil {
+			return nil, err
+		}
+		if err := prec.Check(req.Fee, acct.Currency); err != nil {
+			return nil, err
+		}
+		gross := quantity.Mul(price).Round(decimals)
+		amount := gross.Add(fee)
+		txn.Type = "expense"
+		txn.Description = fmt.Sprintf("Buy %s %s", quantity.String(), sec.Symbol)
+		if req.Type == TradeTypeSell {
+			amount = gross.Sub(fee)
+			if !amount.IsPositive() {
+				return nil, ErrInvalidTrade
+			}
+			txn.Type = "income"
+			txn.Description = fmt.Sprintf("Sell %s %s", quantity.String(), sec.Symbol)
+		}
+		trade.Quantity = numericFromDecimal(quantity)
+		trade.Price = numericFromDecimal(price)
+		trade.Fee = numericFromDecimal(fee)
+		trade.Amount = numericFromDecimal(amount)
+
+		if req.Type == TradeTypeSell {
+			trades, err := s.queries.ListTrades(ctx, store.ListTradesParams{AccountID: acct.ID, UserID: userID})
+			if err != nil {
+				return nil, err
+			}
+			if _, err := replayTrades(insertTrade(trades, trade), decimals); err != nil {
+				return nil, err
+			}
+		}
+	}
+	txn.Amount = trade.Amount
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	q := s.queries.WithTx(tx)
+	var t store.Transaction
+	t, err = createTransaction(ctx, q, userID, txn)
+	if err != nil {
+		return nil, err
+	}
+	_, err = q.CreateTrade(ctx, store.CreateTradeParams{
+		ID:            id,
+		UserID:        userID,
+		AccountID:     acct.ID,
+		SecurityID:    sec.ID,
+		Type:          trade.Type,
+		Date:          date,
+		Quantity:      trade.Quantity,
+		Price:         trade.Price,
+		Fee:           trade.Fee,
+		TransactionID: t.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	trade.TransactionID = t.ID
+	resp := tradeToResponse(trade, decimals)
+	return &resp, nil
+}
+
+// ListTrades returns the trades of an investment account, oldest first.
+func (s *Investment) ListTrades(ctx context.Context, userID, accountID uuid.UUID) ([]dto.TradeResponse, error) {
+	acct, err := s.account(ctx, userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+	trades, err := s.queries.ListTrades(ctx, store.ListTradesParams{AccountID: acct.ID, UserID: userID})
+	if err != nil {
+		return nil, err
+	}
+	prec, err := s.currencies.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	decimals := prec.Of(acct.Currency)
+
+	result := make([]dto.TradeResponse, 0, len(trades))
+	for _, t := range trades {
+		result = append(result, tradeToResponse(t, decimals))
+	}
+	return result, nil
+}
+
+// DeleteTrade deletes a trade with its cash transaction. A buy whose shares
+// have since been sold cannot be deleted.
+func (s *Investment) DeleteTrade(ctx context.Context, userID, tradeID uuid.UUID) error {
+	trade, err := s.queries.GetTrade(ctx, store.GetTradeParams{ID: tradeID, UserID: userID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrTradeNotFound
+		}
+		return err
+	}
+	acct, err := s.account(ctx, userID, trade.AccountID)
+	if err != nil {
+		return err
+	}
+	if err := checkArchived(acct, nil); err != nil {
+		return err
+	}
+	txn, err := s.queries.GetTransaction(ctx, store.GetTransactionParams{ID: trade.TransactionID, UserID: userID})
+	if err != nil {
+		return err
+	}
+	if txn.Status == TransactionStatusReconciled {
+		return ErrTransactionReconciled
+	}
+
+	if trade.Type == TradeTypeBuy {
+		trades, err := s.queries.ListTrades(ctx, store.ListTradesParams{AccountID: acct.ID, UserID: userID})
+		if err != nil {
+			return err
+		}
+		rest := make([]store.ListTradesRow, 0, len(trades))
+		for _, t := range trades {
+			if t.ID != trade.ID {
+				rest = append(rest, t)
+			}
+		}
+		prec, err := s.currencies.Load(ctx)
+		if err != nil {
+			return err
+		}
+		if _, err := replayTrades(rest, prec.Of(acct.Currency)); err != nil {
+			return err
+		}
+	}
+
+	// The trade goes with its transaction.
+	return s.queries.DeleteTransaction(ctx, store.DeleteTransactionParams{ID: trade.TransactionID, UserID: userID})
+}
+
+// Holdings values every security the account has traded at its latest
+// quote: the remaining FIFO lots with their cost basis, realized gains of
+// past sells and dividends received.
+func (s *Investment) Holdings(ctx context.Context, userID, accountID uuid.UUID) (*dto.HoldingsResponse, error) {
+	acct, err := s.account(ctx, userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+	trades, err := s.queries.ListTrades(ctx, store.ListTradesParams{AccountID: acct.ID, UserID: userID})
+	if err != nil {
+		return nil, err
+	}
+	securities, err := s.queries.ListSecurities(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	prec, err := s.currencies.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	decimals := prec.Of(acct.Currency)
+
+	positions, err := replayTrades(trades, decimals)
+	if err != nil {
+		return nil, err
+	}
+	quotes := make(map[uuid.UUID]store.ListSecuritiesRow, len(securities))
+	for _, sec := range securities {
+		quotes[sec.ID] = sec
+	}
+
+	total := decimal.Zero
+	holdings := make([]dto.HoldingResponse, 0, len(positions))
+	for _, p := range positions {
+		sec := quotes[p.securityID]
+		quantity := p.quantity()
+		cost := p.cost()
+		h := dto.HoldingResponse{
+			SecurityID:   p.securityID,
+			Symbol:       p.symbol,
+			Name:         sec.Name,
+			Quantity:     quantity.String(),
+			CostBasis:    cost.StringFixed(decimals),
+			AverageCost:  decimal.Zero.StringFixed(decimals),
+			RealizedGain: p.realized.StringFixed(decimals),
+			Dividends:    p.dividends.StringFixed(decimals),
+			Lots:         make([]dto.LotResponse, 0, len(p.lots)),
+		}
+		if quantity.IsPositive() {
+			h.AverageCost = cost.Div(quantity).StringFixed(decimals)
+		}
+		value := decimal.Zero
+		if sec.Price.Valid {
+			price := numericToDecimal(sec.Price)
+			priceStr := price.String()
+			date := dateToString(sec.PriceDate)
+			h.Price = &priceStr
+			h.PriceDate = &date
+			value = quantity.Mul(price)
+		}
+		total = total.Add(value)
+		h.MarketValue = value.StringFixed(decimals)
+		h.UnrealizedGain = value.Round(decimals).Sub(cost).StringFixed(decimals)
+		for _, l := range p.lots {
+			h.Lots = append(h.Lots, dto.LotResponse{
+				TradeID:  l.tradeID,
+				Date:     dateToString(l.date),
+				Quantity: l.quantity.String(),
+				Cost:     l.cost.StringFixed(decimals),
+			})
+		}
+		holdings = append(holdings, h)
+	}
+
+	cash := numericToDecimal(acct.Balance)
+	return &dto.HoldingsResponse{
+		AccountID:   acct.ID,
+		Currency:    acct.Currency,
+		Cash:        cash.StringFixed(decimals),
+		MarketValue: total.StringFixed(decimals),
+		Balance:     cash.Add(total).StringFixed(decimals),
+		Holdings:    holdings,
+	}, nil
+}
+
+func (s *Investment) account(ctx context.Context, userID, accountID uuid.UUID) (store.Account, error) {
+	acct, err := s.queries.GetAccount(ctx, store.GetAccountParams{ID: accountID, UserID: userID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return store.Account{}, ErrAccountNotFound
+		}
+		return store.Account{}, err
+	}
+	if acct.Type != accountTypeInvestment {
+		return store.Account{}, ErrNotInvestment
+	}
+	return acct, nil
+}
+
+// parseTradeTerms parses the quantity, price and fee of a buy or sell.
+func parseTradeTerms(req dto.CreateTradeRequest) (quantity, price, fee decimal.Decimal, err error) {
+	quantity, err = parsePositiveDecimal(req.Quantity)
+	if err != nil || !quantity.IsPositive() || !quantity.Equal(quantity.Truncate(quantityDecimals)) {
+		return quantity, price, fee, ErrInvalidTrade
+	}
+	price, err = parsePositiveDecimal(req.Price)
+	if err != nil || !price.IsPositive() || !price.Equal(price.Truncate(quantityDecimals)) {
+		return quantity, price, fee, ErrInvalidTrade
+	}
+	fee, err = parsePositiveDecimal(req.Fee)
+	if err != nil {
+		return quantity, price, fee, ErrInvalidTrade
+	}
+	return quantity, price, fee, nil
+}
+
+// insertTrade places a new trade after the existing trades of its date,
+// which is where ListTrades will return it.
+func insertTrade(trades []store.ListTradesRow, t store.ListTradesRow) []store.ListTradesRow {
+	i := len(trades)
+	for i > 0 && trades[i-1].Date.Time.After(t.Date.Time) {
+		i--
+	}
+	out := make([]store.ListTradesRow, 0, len(trades)+1)
+	out = append(out, trades[:i]...)
+	out = append(out, t)
+	return append(out, trades[i:]...)
+}
+
+type lot struct {
+	tradeID  uuid.UUID
+	date     pgtype.Date
+	quantity decimal.Decimal
+	cost     decimal.Decimal
+}
+
+type position struct {
+	securityID uuid.UUID
+	symbol     string
+	lots       []lot
+	realized   decimal.Decimal
+	dividends  decimal.Decimal
+}
+
+func (p *position) quantity() decimal.Decimal {
+	q := decimal.Zero
+	for _, l := range p.lots {
+		q = q.Add(l.quantity)
+	}
+	return q
+}
+
+func (p *position) cost() decimal.Decimal {
+	c := decimal.Zero
+	for _, l := range p.lots {
+		c = c.Add(l.cost)
+	}
+	return c
+}
+
+// replayTrades applies trades in order and returns one position per
+// security, in order of first trade. Each buy opens a lot costing its cash
+// amount; a sell takes shares from the oldest lots, a partly sold lot giving
+// up its cost in proportion, and realizes its proceeds minus the cost taken.
+// It returns ErrInsufficientShares if a sell exceeds the shares held.
+func replayTrades(trades []store.ListTradesRow, decimals int32) ([]*position, error) {
+	var positions []*position
+	bySecurity := make(map[uuid.UUID]*position)
+	for _, t := range trades {
+		p, ok := bySecurity[t.SecurityID]
+		if !ok {
+			p = &position{securityID: t.SecurityID, symbol: t.Symbol}
+			bySecurity[t.SecurityID] = p
+			positions = append(positions, p)
+		}
+		amount := numericToDecimal(t.Amount)
+		switch t.Type {
+		case TradeTypeBuy:
+			p.lots = append(p.lots, lot{
+				tradeID:  t.ID,
+				date:     t.Date,
+				quantity: numericToDecimal(t.Quantity),
+				cost:     amount,
+			})
+		case TradeTypeSell:
+			remaining := numericToDecimal(t.Quantity)
+			taken := decimal.Zero
+			for remaining.IsPositive() && len(p.lots) > 0 {
+				l := &p.lots[0]
+				if remaining.GreaterThanOrEqual(l.quantity) {
+					remaining = remaining.Sub(l.quantity)
+					taken = taken.Add(l.cost)
+					p.lots = p.lots[1:]
+					continue
+				}
+				cost := l.cost.Mul(remaining).Div(l.quantity).Round(decimals)
+				l.quantity = l.quantity.Sub(remaining)
+				l.cost = l.cost.Sub(cost)
+				taken = taken.Add(cost)
+				remaining = decimal.Zero
+			}
+			if remaining.IsPositive() {
+				return nil, ErrInsufficientShares
+			}
+			p.realized = p.realized.Add(amount.Sub(taken))
+		case TradeTypeDividend:
+			p.dividends = p.dividends.Add(amount)
+		}
+	}
+	return positions, nil
+}
+
+func tradeToResponse(t store.ListTradesRow, decimals int32) dto.TradeResponse {
+	return dto.TradeResponse{
+		ID:            t.ID,
+		AccountID:     t.AccountID,
+		SecurityID:    t.SecurityID,
+		Symbol:        t.Symbol,
+		Type:          t.Type,
+		Date:          dateToString(t.Date),
+		Quantity:      numericToDecimal(t.Quantity).String(),
+		Price:         numericToDecimal(t.Price).String(),
+		Fee:           numericToDecimal(t.Fee).StringFixed(decimals),
+		Amount:        numericToDecimal(t.Amount).StringFixed(decimals),
+		TransactionID: t.TransactionID,
+	}
+}
@@ -0,0 +1,317 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/dto"
+	"github.com/sanches/finance-tracker-cc/backend/internal/store"
+)
+
+type mockInvestmentStore struct {
+	getAccountFn        func(ctx context.Context, arg store.GetAccountParams) (store.Account, error)
+	getCategoryFn       func(ctx context.Context, arg store.GetCategoryParams) (store.Category, error)
+	getSecurityFn       func(ctx context.Context, arg store.GetSecurityParams) (store.Security, error)
+	listSecuritiesFn    func(ctx context.Context, userID uuid.UUID) ([]store.ListSecuritiesRow, error)
+	getTradeFn          func(ctx context.Context, arg store.GetTradeParams) (store.Trade, error)
+	listTradesFn        func(ctx context.Context, arg store.ListTradesParams) ([]store.ListTradesRow, error)
+	getTransactionFn    func(ctx context.Context, arg store.GetTransactionParams) (store.Transaction, error)
+	deleteTransactionFn func(ctx context.Context, arg store.DeleteTransactionParams) error
+}
+
+func (m *mockInvestmentStore) GetAccount(ctx context.Context, arg store.GetAccountParams) (store.Account, error) {
+	return m.getAccountFn(ctx, arg)
+}
+
+func (m *mockInvestmentStore) GetCategory(ctx context.Context, arg store.GetCategoryParams) (store.Category, error) {
+	return m.getCategoryFn(ctx, arg)
+}
+
+func (m *mockInvestmentStore) GetSecurity(ctx context.Context, arg store.GetSecurityParams) (store.Security, error) {
+	return m.getSecurityFn(ctx, arg)
+}
+
+func (m *mockInvestmentStore) ListSecurities(ctx context.Context, userID uuid.UUID) ([]store.ListSecuritiesRow, error) {
+	return m.listSecuritiesFn(ctx, userID)
+}
+
+func (m *mockInvestmentStore) GetTrade(ctx context.Context, arg store.GetTradeParams) (store.Trade, error) {
+	return m.getTradeFn(ctx, arg)
+}
+
+func (m *mockInvestmentStore) ListTrades(ctx context.Context, arg store.ListTradesParams) ([]store.ListTradesRow, error) {
+	return m.listTradesFn(ctx, arg)
+}
+
+func (m *mockInvestmentStore) GetTransaction(ctx context.Context, arg store.GetTransactionParams) (store.Transaction, error) {
+	return m.getTransactionFn(ctx, arg)
+}
+
+func (m *mockInvestmentStore) DeleteTransaction(ctx context.Context, arg store.DeleteTransactionParams) error {
+	return m.deleteTransactionFn(ctx, arg)
+}
+
+func (m *mockInvestmentStore) WithTx(tx pgx.Tx) *store.Queries {
+	return nil
+}
+
+func testTrade(securityID uuid.UUID, typ, date, quantity, amount string) store.ListTradesRow {
+	return store.ListTradesRow{
+		ID:         uuid.New(),
+		SecurityID: securityID,
+		Symbol:     "VWCE",
+		Type:       typ,
+		Date:       pgtype.Date{Time: day(date), Valid: true},
+		Quantity:   numericFromString(quantity),
+		Amount:     numericFromString(amount),
+	}
+}
+
+func TestReplayTrades(t *testing.T) {
+	sec := uuid.New()
+
+	t.Run("sells consume the oldest lots first", func(t *testing.T) {
+		positions, err := replayTrades([]store.ListTradesRow{
+			testTrade(sec, TradeTypeBuy, "2026-01-10", "10", "1001.00"),
+			testTrade(sec, TradeTypeBuy, "2026-02-10", "10", "1201.00"),
+			testTrade(sec, TradeTypeSell, "2026-03-10", "15", "1949.00"),
+			testTrade(sec, TradeTypeDividend, "2026-03-31", "0", "12.34"),
+		}, 2)
+		require.NoError(t, err)
+		require.Len(t, positions, 1)
+
+		p := positions[0]
+		require.Len(t, p.lots, 1)
+		require.Equal(t, "5", p.lots[0].quantity.String())
+		require.Equal(t, "600.50", p.lots[0].cost.StringFixed(2))
+		require.Equal(t, "5", p.quantity().String())
+		// 1949 − (1001 + 600.50)
+		require.Equal(t, "347.50", p.realized.StringFixed(2))
+		require.Equal(t, "12.34", p.dividends.StringFixed(2))
+	})
+
+	t.Run("partial sells keep the cost whole", func(t *testing.T) {
+		positions, err := replayTrades([]store.ListTradesRow{
+			testTrade(sec, TradeTypeBuy, "2026-01-10", "3", "100.00"),
+			testTrade(sec, TradeTypeSell, "2026-02-10", "1", "40.00"),
+			testTrade(sec, TradeTypeSell, "2026-03-10", "1", "40.00"),
+			testTrade(sec, TradeTypeSell, "2026-04-10", "1", "40.00"),
+		}, 2)
+		require.NoError(t, err)
+		require.Empty(t, positions[0].lots)
+		require.Equal(t, "20.00", positions[0].realized.StringFixed(2))
+	})
+
+	t.Run("selling more than held fails", func(t *testing.T) {
+		_, err := replayTrades([]store.ListTradesRow{
+			testTrade(sec, TradeTypeBuy, "2026-01-10", "10", "1000"),
+			testTrade(sec, TradeTypeSell, "2026-02-10", "10.5", "1100"),
+		}, 2)
+		require.ErrorIs(t, err, ErrInsufficientShares)
+	})
+
+	t.Run("lots are per security", func(t *testing.T) {
+		other := uuid.New()
+		_, err := replayTrades([]store.ListTradesRow{
+			testTrade(sec, TradeTypeBuy, "2026-01-10", "10", "1000"),
+			testTrade(other, TradeTypeSell, "2026-02-10", "1", "100"),
+		}, 2)
+		require.ErrorIs(t, err, ErrInsufficientShares)
+	})
+}
+
+func TestInsertTrade(t *testing.T) {
+	sec := uuid.New()
+	trades := []store.ListTradesRow{
+		testTrade(sec, TradeTypeBuy, "2026-01-10", "1", "1"),
+		testTrade(sec, TradeTypeBuy, "2026-02-10", "1", "1"),
+		testTrade(sec, TradeTypeBuy, "2026-03-10", "1", "1"),
+	}
+
+	sell := testTrade(sec, TradeTypeSell, "2026-02-10", "1", "1")
+	out := insertTrade(trades, sell)
+	require.Len(t, out, 4)
+	require.Equal(t, sell.ID, out[2].ID) // after the existing trade of the same day
+	require.Len(t, trades, 3)
+
+	early := testTrade(sec, TradeTypeSell, "2025-12-31", "1", "1")
+	require.Equal(t, early.ID, insertTrade(trades, early)[0].ID)
+}
+
+func TestInvestmentCreateTrade_Validation(t *testing.T) {
+	userID := uuid.New()
+	accountID := uuid.New()
+	securityID := uuid.New()
+
+	newSvc := func(acctType, secCurrency string, trades []store.ListTradesRow) *Investment {
+		return &Investment{queries: &mockInvestmentStore{
+			getAccountFn: func(ctx context.Context, arg store.GetAccountParams) (store.Account, error) {
+				return store.Account{ID: accountID, UserID: userID, Type: acctType, Currency: "USD"}, nil
+			},
+			getSecurityFn: func(ctx context.Context, arg store.GetSecurityParams) (store.Security, error) {
+				return store.Security{ID: securityID, Symbol: "VWCE", Currency: secCurrency}, nil
+			},
+			getCategoryFn: func(ctx context.Context, arg store.GetCategoryParams) (store.Category, error) {
+				return store.Category{ID: arg.ID, Type: "expense"}, nil
+			},
+			listTradesFn: func(ctx context.Context, arg store.ListTradesParams) ([]store.ListTradesRow, error) {
+				return trades, nil
+			},
+		}, currencies: testCurrencyPrecision()}
+	}
+	req := func(typ, quantity, price, fee string) dto.CreateTradeRequest {
+		return dto.CreateTradeRequest{
+			AccountID:  accountID,
+			SecurityID: securityID,
+			Type:       typ,
+			Date:       "2026-03-01",
+			Quantity:   quantity,
+			Price:      price,
+			Fee:        fee,
+		}
+	}
+	ctx := context.Background()
+
+	_, err := newSvc("deposit", "USD", nil).CreateTrade(ctx, userID, req(TradeTypeBuy, "1", "100", ""))
+	require.ErrorIs(t, err, ErrNotInvestment)
+
+	_, err = newSvc("investment", "EUR", nil).CreateTrade(ctx, userID, req(TradeTypeBuy, "1", "100", ""))
+	require.ErrorIs(t, err, ErrTradeCurrency)
+
+	svc := newSvc("investment", "USD", []store.ListTradesRow{
+		testTrade(securityID, TradeTypeBuy, "2026-01-10", "10", "1000"),
+		testTrade(securityID, TradeTypeSell, "2026-04-10", "6", "700"),
+	})
+	for _, r := range []dto.CreateTradeRequest{
+		req(TradeTypeBuy, "0", "100", ""),
+		req(TradeTypeBuy, "1", "-100", ""),
+		req(TradeTypeBuy, "0.123456789", "100", ""),
+		req(TradeTypeBuy, "1", "100", "-1"),
+		req(TradeTypeSell, "1", "1", "5"), // fee eats the proceeds
+		req(TradeTypeDividend, "", "", ""),
+	} {
+		_, err = svc.CreateTrade(ctx, userID, r)
+		require.ErrorIs(t, err, ErrInvalidTrade)
+	}
+
+	_, err = svc.CreateTrade(ctx, userID, req(TradeTypeBuy, "1", "100", "0.001"))
+	require.ErrorIs(t, err, ErrAmountPrecision)
+
+	// 10 held, but 6 of them are sold in April: only 4 can go in March.
+	_, err = svc.CreateTrade(ctx, userID, req(TradeTypeSell, "5", "110", ""))
+	require.ErrorIs(t, err, ErrInsufficientShares)
+
+	dividend := req(TradeTypeDividend, "", "", "")
+	dividend.Amount = "12.50"
+	dividend.CategoryID = &securityID
+	_, err = svc.CreateTrade(ctx, userID, dividend)
+	require.ErrorIs(t, err, ErrInvalidIncomeCategory)
+}
+
+func TestInvestmentDeleteTrade_SoldBuy(t *testing.T) {
+	userID := uuid.New()
+	accountID := uuid.New()
+	securityID := uuid.New()
+	buy := testTrade(securityID, TradeTypeBuy, "2026-01-10", "10", "1000")
+	sell := testTrade(securityID, TradeTypeSell, "2026-02-10", "5", "600")
+
+	deleted := false
+	svc := &Investment{queries: &mockInvestmentStore{
+		getTradeFn: func(ctx context.Context, arg store.GetTradeParams) (store.Trade, error) {
+			for _, t := range []store.ListTradesRow{buy, sell} {
+				if t.ID == arg.ID {
+					return store.Trade{ID: t.ID, AccountID: accountID, Type: t.Type, TransactionID: t.ID}, nil
+				}
+			}
+			return store.Trade{}, pgx.ErrNoRows
+		},
+		getAccountFn: func(ctx context.Context, arg store.GetAccountParams) (store.Account, error) {
+			return store.Account{ID: accountID, UserID: userID, Type: accountTypeInvestment, Currency: "USD"}, nil
+		},
+		getTransactionFn: func(ctx context.Context, arg store.GetTransactionParams) (store.Transaction, error) {
+			return store.Transaction{ID: arg.ID, Status: TransactionStatusPending}, nil
+		},
+		listTradesFn: func(ctx context.Context, arg store.ListTradesParams) ([]store.ListTradesRow, error) {
+			return []store.ListTradesRow{buy, sell}, nil
+		},
+		deleteTransactionFn: func(ctx context.Context, arg store.DeleteTransactionParams) error {
+			deleted = true
+			return nil
+		},
+	}, currencies: testCurrencyPrecision()}
+	ctx := context.Background()
+
+	require.ErrorIs(t, svc.DeleteTrade(ctx, userID, buy.ID), ErrInsufficientShares)
+	require.False(t, deleted)
+
+	require.NoError(t, svc.DeleteTrade(ctx, userID, sell.ID))
+	require.True(t, deleted)
+
+	require.ErrorIs(t, svc.DeleteTrade(ctx, userID, uuid.New()), ErrTradeNotFound)
+}
+
+func TestInvestmentHoldings(t *testing.T) {
+	userID := uuid.New()
+	accountID := uuid.New()
+	vwce := uuid.New()
+	aapl := uuid.New()
+
+	svc := &Investment{queries: &mockInvestmentStore{
+		getAccountFn: func(ctx context.Context, arg store.GetAccountParams) (store.Account, error) {
+			return store.Account{
+				ID:       accountID,
+				UserID:   userID,
+				Type:     accountTypeInvestment,
+				Currency: "USD",
+				Balance:  numericFromString("500.00"),
+			}, nil
+		},
+		listTradesFn: func(ctx context.Context, arg store.ListTradesParams) ([]store.ListTradesRow, error) {
+			sold := testTrade(aapl, TradeTypeBuy, "2026-01-05", "2", "300.00")
+			sold.Symbol = "AAPL"
+			sale := testTrade(aapl, TradeTypeSell, "2026-02-05", "2", "350.00")
+			sale.Symbol = "AAPL"
+			return []store.ListTradesRow{
+				testTrade(vwce, TradeTypeBuy, "2026-01-10", "10", "1001.00"),
+				sold,
+				testTrade(vwce, TradeTypeBuy, "2026-02-10", "5", "551.00"),
+				sale,
+			}, nil
+		},
+		listSecuritiesFn: func(ctx context.Context, uid uuid.UUID) ([]store.ListSecuritiesRow, error) {
+			return []store.ListSecuritiesRow{
+				{ID: vwce, Symbol: "VWCE", Name: "Vanguard FTSE All-World", Price: numericFromString("120.125"), PriceDate: pgtype.Date{Time: day("2026-03-01"), Valid: true}},
+				{ID: aapl, Symbol: "AAPL", Name: "Apple", Price: numericFromString("180"), PriceDate: pgtype.Date{Time: day("2026-02-05"), Valid: true}},
+			}, nil
+		},
+	}, currencies: testCurrencyPrecision()}
+
+	resp, err := svc.Holdings(context.Background(), userID, accountID)
+	require.NoError(t, err)
+	require.Len(t, resp.Holdings, 2)
+
+	h := resp.Holdings[0]
+	require.Equal(t, "VWCE", h.Symbol)
+	require.Equal(t, "15", h.Quantity)
+	require.Equal(t, "1552.00", h.CostBasis)
+	require.Equal(t, "103.47", h.AverageCost)
+	require.Equal(t, "1801.88", h.MarketValue) // 15 × 120.125 = 1801.875
+	require.Equal(t, "249.88", h.UnrealizedGain)
+	require.Len(t, h.Lots, 2)
+	require.Equal(t, "2026-03-01", *h.PriceDate)
+
+	closed := resp.Holdings[1]
+	require.Equal(t, "0", closed.Quantity)
+	require.Equal(t, "0.00", closed.MarketValue)
+	require.Equal(t, "50.00", closed.RealizedGain)
+	require.Empty(t, closed.Lots)
+
+	require.Equal(t, "500.00", resp.Cash)
+	require.Equal(t, "1801.88", resp.MarketValue)
+	require.Equal(t, "2301.88", resp.Balance)
+}
@@ -0,0 +1,411 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/dto"
+	"github.com/sanches/finance-tracker-cc/backend/internal/store"
+)
+
+var (
+	ErrNotLoan                 = errors.New("account is not a loan")
+	ErrLoanNotSetUp            = errors.New("loan has no terms")
+	ErrInvalidLoan             = errors.New("principal must be a positive number and annual_rate a non-negative percentage")
+	ErrInvalidInterestCategory = errors.New("interest_category_id must be one of your expense categories")
+)
+
+const accountTypeLoan = "loan"
+
+// LoanInterestDescription is the description of the interest transactions
+// created by Loan.SplitPayments.
+const LoanInterestDescription = "Loan interest"
+
+// maxLoanMonths bounds payoff projections for loans whose payment barely
+// covers the interest.
+const maxLoanMonths = 1200
+
+type loanStore interface {
+	GetAccount(ctx context.Context, arg store.GetAccountParams) (store.Account, error)
+	GetCategory(ctx context.Context, arg store.GetCategoryParams) (store.Category, error)
+	GetLoan(ctx context.Context, arg store.GetLoanParams) (store.Loan, error)
+	UpsertLoan(ctx context.Context, arg store.UpsertLoanParams) (store.Loan, error)
+	GetLoanPaymentTotals(ctx context.Context, arg store.GetLoanPaymentTotalsParams) (store.GetLoanPaymentTotalsRow, error)
+	WithTx(tx pgx.Tx) *store.Queries
+}
+
+// Loan tracks the terms of loan accounts. The account balance is minus the
+// remaining principal: payments are transfers into the account, and
+// SplitPayments books the interest share of each one back out as an expense
+// so that only the principal share reduces the debt.
+type Loan struct {
+	queries    loanStore
+	pool       *pgxpool.Pool
+	currencies *CurrencyPrecision
+}
+
+func NewLoan(queries *store.Queries, pool *pgxpool.Pool, currencies *CurrencyPrecision) *Loan {
+	return &Loan{queries: queries, pool: pool, currencies: currencies}
+}
+
+func (s *Loan) Update(ctx context.Context, userID, accountID uuid.UUID, req dto.UpdateLoanRequest) (*dto.LoanResponse, error) {
+	acct, err := s.account(ctx, userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+	prec, err := s.currencies.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	principal, err := parsePositiveDecimal(req.Principal)
+	if err != nil || !principal.IsPositive() {
+		return nil, ErrInvalidLoan
+	}
+	rate, err := parsePositiveDecimal(req.AnnualRate)
+	if err != nil || rate.GreaterThan(decimal.NewFromInt(100)) {
+		return nil, ErrInvalidLoan
+	}
+	if err := prec.Check(req.Principal, acct.Currency); err != nil {
+		return nil, err
+	}
+	start, err := dateFromString(req.StartDate)
+	if err != nil {
+		return nil, ErrInvalidLoan
+	}
+	if req.InterestCategoryID != nil {
+		cat, err := s.queries.GetCategory(ctx, store.GetCategoryParams{ID: *req.InterestCategoryID, UserID: userID})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, ErrInvalidInterestCategory
+			}
+			return nil, err
+		}
+		if cat.Type != "expense" {
+			return nil, ErrInvalidInterestCategory
+		}
+	}
+
+	loan, err := s.queries.UpsertLoan(ctx, store.UpsertLoanParams{
+		AccountID:          acct.ID,
+		UserID:             userID,
+		Principal:          numericFromDecimal(principal),
+		AnnualRate:         numericFromDecimal(rate),
+		TermMonths:         int32(req.TermMonths),
+		StartDate:          start,
+		PaymentDay:         int16(req.PaymentDay),
+		InterestCategoryID: uuidToNullable(req.InterestCategoryID),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.toResponse(ctx, acct, loan, prec, time.Now())
+}
+
+// Get returns the loan terms with the remaining principal, what the split
+// payments have paid so far and a payoff projection at the scheduled monthly
+// payment.
+func (s *Loan) Get(ctx context.Context, userID, accountID uuid.UUID) (*dto.LoanResponse, error) {
+	acct, loan, err := s.get(ctx, userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+	prec, err := s.currencies.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.toResponse(ctx, acct, loan, prec, time.Now())
+}
+
+// Schedule returns the amortization schedule of the original loan terms.
+func (s *Loan) Schedule(ctx context.Context, userID, accountID uuid.UUID) ([]dto.LoanScheduleItem, error) {
+	acct, loan, err := s.get(ctx, userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+	prec, err := s.currencies.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	decimals := prec.Of(acct.Currency)
+	principal := numericToDecimal(loan.Principal)
+	rate := numericToDecimal(loan.AnnualRate)
+	n := int(loan.TermMonths)
+	payment := monthlyPayment(principal, rate, n, decimals)
+	first := firstPaymentDate(loan.StartDate.Time, int(loan.PaymentDay))
+
+	rows := amortize(principal, rate, payment, n, decimals)
+	result := make([]dto.LoanScheduleItem, 0, len(rows))
+	for i, r := range rows {
+		result = append(result, dto.LoanScheduleItem{
+			Number:    i + 1,
+			Date:      dayInMonth(first.Year(), first.Month()+time.Month(i), int(loan.PaymentDay)).Format("2006-01-02"),
+			Payment:   r.payment.StringFixed(decimals),
+			Principal: r.principal.StringFixed(decimals),
+			Interest:  r.interest.StringFixed(decimals),
+			Remaining: r.remaining.StringFixed(decimals),
+		})
+	}
+	return result, nil
+}
+
+// SplitPayments splits every transfer into the loan account that has not
+// been split yet. The interest share is one month's interest on what was
+// owed the day before the payment; it is booked as an expense on the loan
+// account in the loan's interest category, dated like the payment.
+func (s *Loan) SplitPayments(ctx context.Context, userID, accountID uuid.UUID) ([]dto.LoanPaymentSplit, error) {
+	acct, loan, err := s.get(ctx, userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkArchived(acct, nil); err != nil {
+		return nil, err
+	}
+	prec, err := s.currencies.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	decimals := prec.Of(acct.Currency)
+	rate := numericToDecimal(loan.AnnualRate)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	q := s.queries.WithTx(tx)
+	payments, err := q.ListUnsplitLoanPayments(ctx, store.ListUnsplitLoanPaymentsParams{AccountID: acct.ID, UserID: userID})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.LoanPaymentSplit, 0, len(payments))
+	for _, p := range payments {
+		var before pgtype.Numeric
+		before, err = q.GetAccountBalanceAt(ctx, store.GetAccountBalanceAtParams{
+			Date:      pgtype.Date{Time: p.Date.Time.AddDate(0, 0, -1), Valid: true},
+			AccountID: acct.ID,
+			UserID:    userID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		amount := numericToDecimal(p.Amount)
+		principal, interest := splitPayment(numericToDecimal(before).Neg(), amount, rate, decimals)
+
+		split := dto.LoanPaymentSplit{
+			PaymentID: p.ID,
+			Date:      dateToString(p.Date),
+			Amount:    amount.StringFixed(decimals),
+			Principal: principal.StringFixed(decimals),
+			Interest:  interest.StringFixed(decimals),
+		}
+		var interestID pgtype.UUID
+		if interest.IsPositive() {
+			var t store.Transaction
+			t, err = createTransaction(ctx, q, userID, store.CreateTransactionParams{
+				UserID:      userID,
+				AccountID:   acct.ID,
+				CategoryID:  loan.InterestCategoryID,
+				Type:        "expense",
+				Amount:      numericFromDecimal(interest),
+				Description: LoanInterestDescription,
+				Date:        p.Date,
+			})
+			if err != nil {
+				return nil, err
+			}
+			interestID = pgtype.UUID{Bytes: t.ID, Valid: true}
+			split.InterestID = &t.ID
+		}
+		_, err = q.CreateLoanPayment(ctx, store.CreateLoanPaymentParams{
+			PaymentID:  p.ID,
+			AccountID:  acct.ID,
+			UserID:     userID,
+			InterestID: interestID,
+			Principal:  numericFromDecimal(principal),
+			Interest:   numericFromDecimal(interest),
+		})
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, split)
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *Loan) account(ctx context.Context, userID, accountID uuid.UUID) (store.Account, error) {
+	acct, err := s.queries.GetAccount(ctx, store.GetAccountParams{ID: accountID, UserID: userID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return store.Account{}, ErrAccountNotFound
+		}
+		return store.Account{}, err
+	}
+	if acct.Type != accountTypeLoan {
+		return store.Account{}, ErrNotLoan
+	}
+	return acct, nil
+}
+
+func (s *Loan) get(ctx context.Context, userID, accountID uuid.UUID) (store.Account, store.Loan, error) {
+	acct, err := s.account(ctx, userID, accountID)
+	if err != nil {
+		return store.Account{}, store.Loan{}, err
+	}
+	loan, err := s.queries.GetLoan(ctx, store.GetLoanParams{AccountID: accountID, UserID: userID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return store.Account{}, store.Loan{}, ErrLoanNotSetUp
+		}
+		return store.Account{}, store.Loan{}, err
+	}
+	return acct, loan, nil
+}
+
+func (s *Loan) toResponse(ctx context.Context, acct store.Account, loan store.Loan, prec Precisions, today time.Time) (*dto.LoanResponse, error) {
+	paid, err := s.queries.GetLoanPaymentTotals(ctx, store.GetLoanPaymentTotalsParams{AccountID: acct.ID, UserID: acct.UserID})
+	if err != nil {
+		return nil, err
+	}
+
+	decimals := prec.Of(acct.Currency)
+	principal := numericToDecimal(loan.Principal)
+	rate := numericToDecimal(loan.AnnualRate)
+	payment := monthlyPayment(principal, rate, int(loan.TermMonths), decimals)
+	remaining := decimal.Max(numericToDecimal(acct.Balance).Neg(), decimal.Zero)
+
+	resp := &dto.LoanResponse{
+		AccountID:          acct.ID,
+		Currency:           acct.Currency,
+		Principal:          principal.StringFixed(decimals),
+		AnnualRate:         rate.String(),
+		TermMonths:         int(loan.TermMonths),
+		StartDate:          dateToString(loan.StartDate),
+		PaymentDay:         int(loan.PaymentDay),
+		InterestCategoryID: nullableToUUID(loan.InterestCategoryID),
+		MonthlyPayment:     payment.StringFixed(decimals),
+		RemainingPrincipal: remaining.StringFixed(decimals),
+		PrincipalPaid:      numericToDecimal(paid.PrincipalPaid).StringFixed(decimals),
+		InterestPaid:       numericToDecimal(paid.InterestPaid).StringFixed(decimals),
+		PaymentsSplit:      int(paid.Count),
+		ProjectedInterest:  decimal.Zero.StringFixed(decimals),
+	}
+	if !remaining.IsPositive() {
+		return resp, nil
+	}
+
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+	next := nextPaymentDate(loan.StartDate.Time, today, int(loan.PaymentDay))
+	resp.NextPaymentDate = next.Format("2006-01-02")
+
+	rows := amortize(remaining, rate, payment, maxLoanMonths, decimals)
+	if rows[len(rows)-1].remaining.IsPositive() {
+		// The payment does not cover the interest; the loan never pays off.
+		return resp, nil
+	}
+	interest := decimal.Zero
+	for _, r := range rows {
+		interest = interest.Add(r.interest)
+	}
+	resp.RemainingPayments = len(rows)
+	resp.PayoffDate = dayInMonth(next.Year(), next.Month()+time.Month(len(rows)-1), int(loan.PaymentDay)).Format("2006-01-02")
+	resp.ProjectedInterest = interest.StringFixed(decimals)
+	return resp, nil
+}
+
+// monthlyRate converts an annual percentage rate to a monthly fraction.
+func monthlyRate(annualRate decimal.Decimal) decimal.Decimal {
+	return annualRate.Div(decimal.NewFromInt(1200))
+}
+
+// monthlyPayment is the fixed annuity payment P·r / (1 − (1+r)^−n) that pays
+// off principal in n months, or P/n for an interest-free loan, rounded up to
+// the currency's precision so that the last payment is never the largest.
+func monthlyPayment(principal, annualRate decimal.Decimal, n int, decimals int32) decimal.Decimal {
+	months := decimal.NewFromInt(int64(n))
+	if !annualRate.IsPositive() {
+		return principal.Div(months).RoundCeil(decimals)
+	}
+	r := monthlyRate(annualRate)
+	f := decimal.NewFromInt(1).Add(r).Pow(months)
+	return principal.Mul(r).Mul(f).Div(f.Sub(decimal.NewFromInt(1))).RoundCeil(decimals)
+}
+
+type amortizationRow struct {
+	payment   decimal.Decimal
+	principal decimal.Decimal
+	interest  decimal.Decimal
+	remaining decimal.Decimal
+}
+
+// amortize applies up to n monthly payments to balance. A payment never
+// exceeds what is left, and the n-th payment also clears whatever rounding
+// left over. It stops early if the payment does not cover the interest.
+func amortize(balance, annualRate, payment decimal.Decimal, n int, decimals int32) []amortizationRow {
+	r := monthlyRate(annualRate)
+	var rows []amortizationRow
+	for k := 1; k <= n && balance.IsPositive(); k++ {
+		interest := balance.Mul(r).Round(decimals)
+		principal := payment.Sub(interest)
+		if !principal.IsPositive() {
+			rows = append(rows, amortizationRow{payment: decimal.Zero, principal: decimal.Zero, interest: interest, remaining: balance})
+			break
+		}
+		if k == n || principal.GreaterThan(balance) {
+			principal = balance
+		}
+		balance = balance.Sub(principal)
+		rows = append(rows, amortizationRow{
+			payment:   principal.Add(interest),
+			principal: principal,
+			interest:  interest,
+			remaining: balance,
+		})
+	}
+	return rows
+}
+
+// splitPayment divides a payment into principal and interest, where interest
+// is one month's interest on owed, never more than the payment itself.
+func splitPayment(owed, amount, annualRate decimal.Decimal, decimals int32) (principal, interest decimal.Decimal) {
+	interest = decimal.Zero
+	if owed.IsPositive() {
+		interest = decimal.Min(owed.Mul(monthlyRate(annualRate)).Round(decimals), amount)
+	}
+	return amount.Sub(interest), interest
+}
+
+// firstPaymentDate is payment day in the month after the loan starts.
+func firstPaymentDate(start time.Time, paymentDay int) time.Time {
+	return dayInMonth(start.Year(), start.Month()+1, paymentDay)
+}
+
+// nextPaymentDate is the first payment day on or after today, but never
+// before the first payment.
+func nextPaymentDate(start, today time.Time, paymentDay int) time.Time {
+	next := dayInMonth(today.Year(), today.Month(), paymentDay)
+	if next.Before(today) {
+		next = dayInMonth(today.Year(), today.Month()+1, paymentDay)
+	}
+	if first := firstPaymentDate(start, paymentDay); next.Before(first) {
+		return first
+	}
+	return next
+}
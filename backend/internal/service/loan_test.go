@@ -0,0 +1,276 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/dto"
+	"github.com/sanches/finance-tracker-cc/backend/internal/store"
+)
+
+type mockLoanStore struct {
+	getAccountFn           func(ctx context.Context, arg store.GetAccountParams) (store.Account, error)
+	getCategoryFn          func(ctx context.Context, arg store.GetCategoryParams) (store.Category, error)
+	getLoanFn              func(ctx context.Context, arg store.GetLoanParams) (store.Loan, error)
+	upsertLoanFn           func(ctx context.Context, arg store.UpsertLoanParams) (store.Loan, error)
+	getLoanPaymentTotalsFn func(ctx context.Context, arg store.GetLoanPaymentTotalsParams) (store.GetLoanPaymentTotalsRow, error)
+}
+
+func (m *mockLoanStore) GetAccount(ctx context.Context, arg store.GetAccountParams) (store.Account, error) {
+	return m.getAccountFn(ctx, arg)
+}
+
+func (m *mockLoanStore) GetCategory(ctx context.Context, arg store.GetCategoryParams) (store.Category, error) {
+	return m.getCategoryFn(ctx, arg)
+}
+
+func (m *mockLoanStore) GetLoan(ctx context.Context, arg store.GetLoanParams) (store.Loan, error) {
+	return m.getLoanFn(ctx, arg)
+}
+
+func (m *mockLoanStore) UpsertLoan(ctx context.Context, arg store.UpsertLoanParams) (store.Loan, error) {
+	return m.upsertLoanFn(ctx, arg)
+}
+
+func (m *mockLoanStore) GetLoanPaymentTotals(ctx context.Context, arg store.GetLoanPaymentTotalsParams) (store.GetLoanPaymentTotalsRow, error) {
+	return m.getLoanPaymentTotalsFn(ctx, arg)
+}
+
+func (m *mockLoanStore) WithTx(tx pgx.Tx) *store.Queries {
+	return nil
+}
+
+func TestMonthlyPayment(t *testing.T) {
+	d := decimal.RequireFromString
+	require.Equal(t, "599.56", monthlyPayment(d("100000"), d("6"), 360, 2).StringFixed(2))
+	require.Equal(t, "1887.13", monthlyPayment(d("100000"), d("5"), 60, 2).StringFixed(2))
+	require.Equal(t, "333.34", monthlyPayment(d("1000"), d("0"), 3, 2).StringFixed(2))
+	require.Equal(t, "8792", monthlyPayment(d("100000"), d("10"), 12, 0).StringFixed(0))
+}
+
+func TestAmortize(t *testing.T) {
+	d := decimal.RequireFromString
+
+	t.Run("pays off over the term", func(t *testing.T) {
+		rows := amortize(d("100000"), d("6"), d("599.56"), 360, 2)
+		require.Len(t, rows, 360)
+		require.Equal(t, "500.00", rows[0].interest.StringFixed(2))
+		require.Equal(t, "99.56", rows[0].principal.StringFixed(2))
+		require.Equal(t, "99900.44", rows[0].remaining.StringFixed(2))
+		require.True(t, rows[359].remaining.IsZero())
+
+		total := decimal.Zero
+		for _, r := range rows {
+			total = total.Add(r.principal)
+			require.Equal(t, r.payment, r.principal.Add(r.interest))
+		}
+		require.Equal(t, "100000.00", total.StringFixed(2))
+	})
+
+	t.Run("last payment takes the rounding", func(t *testing.T) {
+		rows := amortize(d("1000"), d("0"), d("333.34"), 3, 2)
+		require.Len(t, rows, 3)
+		require.Equal(t, "333.32", rows[2].payment.StringFixed(2))
+		require.True(t, rows[2].remaining.IsZero())
+	})
+
+	t.Run("stops early when ahead of schedule", func(t *testing.T) {
+		rows := amortize(d("1000"), d("12"), d("599.56"), 360, 2)
+		require.Len(t, rows, 2)
+		require.Equal(t, "410.44", rows[1].principal.StringFixed(2))
+		require.True(t, rows[1].remaining.IsZero())
+	})
+
+	t.Run("payment below interest never pays off", func(t *testing.T) {
+		rows := amortize(d("100000"), d("12"), d("500"), maxLoanMonths, 2)
+		require.Len(t, rows, 1)
+		require.True(t, rows[0].remaining.IsPositive())
+	})
+}
+
+func TestSplitPayment(t *testing.T) {
+	d := decimal.RequireFromString
+	tests := []struct {
+		name                string
+		owed, amount, rate  string
+		principal, interest string
+	}{
+		{"one month of interest", "10000", "500", "12", "400.00", "100.00"},
+		{"rounded to the currency", "12345.67", "300", "4.5", "253.70", "46.30"},
+		{"capped at the payment", "100000", "500", "12", "0.00", "500.00"},
+		{"nothing owed", "0", "500", "12", "500.00", "0.00"},
+		{"interest-free", "10000", "500", "0", "500.00", "0.00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, interest := splitPayment(d(tt.owed), d(tt.amount), d(tt.rate), 2)
+			require.Equal(t, tt.principal, principal.StringFixed(2))
+			require.Equal(t, tt.interest, interest.StringFixed(2))
+		})
+	}
+}
+
+func TestNextPaymentDate(t *testing.T) {
+	require.Equal(t, day("2026-02-25"), firstPaymentDate(day("2026-01-10"), 25))
+	require.Equal(t, day("2026-02-28"), firstPaymentDate(day("2026-01-31"), 31), "clamped to month end")
+
+	start := day("2025-06-01")
+	require.Equal(t, day("2026-03-15"), nextPaymentDate(start, day("2026-03-10"), 15))
+	require.Equal(t, day("2026-03-15"), nextPaymentDate(start, day("2026-03-15"), 15), "due today")
+	require.Equal(t, day("2026-04-15"), nextPaymentDate(start, day("2026-03-16"), 15))
+	require.Equal(t, day("2026-07-15"), nextPaymentDate(day("2026-06-20"), day("2026-03-16"), 15), "not before the first payment")
+}
+
+func TestLoanUpdate(t *testing.T) {
+	userID := uuid.New()
+	accountID := uuid.New()
+	categoryID := uuid.New()
+
+	newMock := func(accountType, categoryType string) *mockLoanStore {
+		return &mockLoanStore{
+			getAccountFn: func(ctx context.Context, arg store.GetAccountParams) (store.Account, error) {
+				return store.Account{ID: accountID, UserID: userID, Type: accountType, Currency: "USD", Balance: numericFromString("-100000")}, nil
+			},
+			getCategoryFn: func(ctx context.Context, arg store.GetCategoryParams) (store.Category, error) {
+				if arg.ID != categoryID {
+					return store.Category{}, pgx.ErrNoRows
+				}
+				return store.Category{ID: categoryID, UserID: userID, Type: categoryType}, nil
+			},
+			upsertLoanFn: func(ctx context.Context, arg store.UpsertLoanParams) (store.Loan, error) {
+				return store.Loan{
+					AccountID:          arg.AccountID,
+					UserID:             arg.UserID,
+					Principal:          arg.Principal,
+					AnnualRate:         arg.AnnualRate,
+					TermMonths:         arg.TermMonths,
+					StartDate:          arg.StartDate,
+					PaymentDay:         arg.PaymentDay,
+					InterestCategoryID: arg.InterestCategoryID,
+				}, nil
+			},
+			getLoanPaymentTotalsFn: func(ctx context.Context, arg store.GetLoanPaymentTotalsParams) (store.GetLoanPaymentTotalsRow, error) {
+				return store.GetLoanPaymentTotalsRow{PrincipalPaid: numericFromString("0"), InterestPaid: numericFromString("0")}, nil
+			},
+		}
+	}
+	req := dto.UpdateLoanRequest{
+		Principal:          "100000",
+		AnnualRate:         "6",
+		TermMonths:         360,
+		StartDate:          "2026-01-10",
+		PaymentDay:         1,
+		InterestCategoryID: &categoryID,
+	}
+
+	t.Run("saves terms", func(t *testing.T) {
+		svc := &Loan{queries: newMock("loan", "expense"), currencies: testCurrencyPrecision()}
+		resp, err := svc.Update(context.Background(), userID, accountID, req)
+		require.NoError(t, err)
+		require.Equal(t, "100000.00", resp.Principal)
+		require.Equal(t, "599.56", resp.MonthlyPayment)
+		require.Equal(t, "100000.00", resp.RemainingPrincipal)
+		require.Equal(t, &categoryID, resp.InterestCategoryID)
+		require.Equal(t, 360, resp.RemainingPayments)
+	})
+
+	t.Run("not a loan", func(t *testing.T) {
+		svc := &Loan{queries: newMock("deposit", "expense"), currencies: testCurrencyPrecision()}
+		_, err := svc.Update(context.Background(), userID, accountID, req)
+		require.ErrorIs(t, err, ErrNotLoan)
+	})
+
+	t.Run("income category", func(t *testing.T) {
+		svc := &Loan{queries: newMock("loan", "income"), currencies: testCurrencyPrecision()}
+		_, err := svc.Update(context.Background(), userID, accountID, req)
+		require.ErrorIs(t, err, ErrInvalidInterestCategory)
+	})
+
+	t.Run("unknown category", func(t *testing.T) {
+		svc := &Loan{queries: newMock("loan", "expense"), currencies: testCurrencyPrecision()}
+		other := uuid.New()
+		bad := req
+		bad.InterestCategoryID = &other
+		_, err := svc.Update(context.Background(), userID, accountID, bad)
+		require.ErrorIs(t, err, ErrInvalidInterestCategory)
+	})
+
+	t.Run("invalid terms", func(t *testing.T) {
+		svc := &Loan{queries: newMock("loan", "expense"), currencies: testCurrencyPrecision()}
+		for _, mutate := range []func(*dto.UpdateLoanRequest){
+			func(r *dto.UpdateLoanRequest) { r.Principal = "0" },
+			func(r *dto.UpdateLoanRequest) { r.Principal = "-5" },
+			func(r *dto.UpdateLoanRequest) { r.AnnualRate = "abc" },
+			func(r *dto.UpdateLoanRequest) { r.AnnualRate = "101" },
+		} {
+			bad := req
+			mutate(&bad)
+			_, err := svc.Update(context.Background(), userID, accountID, bad)
+			require.ErrorIs(t, err, ErrInvalidLoan)
+		}
+	})
+}
+
+func TestLoanProjection(t *testing.T) {
+	userID := uuid.New()
+	accountID := uuid.New()
+	loan := store.Loan{
+		AccountID:  accountID,
+		UserID:     userID,
+		Principal:  numericFromString("10000"),
+		AnnualRate: numericFromString("12"),
+		TermMonths: 12,
+		StartDate:  pgtype.Date{Time: day("2026-01-05"), Valid: true},
+		PaymentDay: 15,
+	}
+	svc := &Loan{queries: &mockLoanStore{
+		getLoanPaymentTotalsFn: func(ctx context.Context, arg store.GetLoanPaymentTotalsParams) (store.GetLoanPaymentTotalsRow, error) {
+			return store.GetLoanPaymentTotalsRow{
+				PrincipalPaid: numericFromString("2000"),
+				InterestPaid:  numericFromString("350.25"),
+				Count:         3,
+			}, nil
+		},
+	}}
+	acct := func(balance string) store.Account {
+		return store.Account{ID: accountID, UserID: userID, Type: "loan", Currency: "USD", Balance: numericFromString(balance)}
+	}
+	prec, err := testCurrencyPrecision().Load(context.Background())
+	require.NoError(t, err)
+
+	t.Run("on schedule", func(t *testing.T) {
+		resp, err := svc.toResponse(context.Background(), acct("-10000"), loan, prec, day("2026-01-20"))
+		require.NoError(t, err)
+		require.Equal(t, "888.49", resp.MonthlyPayment)
+		require.Equal(t, "2026-02-15", resp.NextPaymentDate)
+		require.Equal(t, 12, resp.RemainingPayments)
+		require.Equal(t, "2027-01-15", resp.PayoffDate)
+		require.Equal(t, "2000.00", resp.PrincipalPaid)
+		require.Equal(t, "350.25", resp.InterestPaid)
+		require.Equal(t, 3, resp.PaymentsSplit)
+	})
+
+	t.Run("ahead of schedule", func(t *testing.T) {
+		resp, err := svc.toResponse(context.Background(), acct("-1500"), loan, prec, day("2026-03-20"))
+		require.NoError(t, err)
+		require.Equal(t, "1500.00", resp.RemainingPrincipal)
+		require.Equal(t, 2, resp.RemainingPayments)
+		require.Equal(t, "2026-05-15", resp.PayoffDate)
+	})
+
+	t.Run("paid off", func(t *testing.T) {
+		resp, err := svc.toResponse(context.Background(), acct("0"), loan, prec, time.Now())
+		require.NoError(t, err)
+		require.Equal(t, "0.00", resp.RemainingPrincipal)
+		require.Zero(t, resp.RemainingPayments)
+		require.Empty(t, resp.NextPaymentDate)
+		require.Empty(t, resp.PayoffDate)
+	})
+}
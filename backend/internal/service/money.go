@@ -0,0 +1,89 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
+)
+
+var ErrCurrencyMismatch = errors.New("currency mismatch")
+
+// Money is an exact decimal amount in a currency. Arithmetic between two
+// Money values only succeeds when their currencies match; moving an amount
+// into another currency goes through Convert with an explicit rate.
+type Money struct {
+	amount   decimal.Decimal
+	currency string
+}
+
+func NewMoney(amount decimal.Decimal, currency string) Money {
+	return Money{amount: amount, currency: strings.ToUpper(currency)}
+}
+
+// MoneyFromNumeric wraps a stored amount; an invalid (NULL) numeric is zero.
+func MoneyFromNumeric(n pgtype.Numeric, currency string) Money {
+	return NewMoney(numericToDecimal(n), currency)
+}
+
+// ParseMoney parses a decimal string such as "1234.56".
+func ParseMoney(s, currency string) (Money, error) {
+	d, err := decimal.NewFromString(s)
+	if err != nil {
+		return Money{}, err
+	}
+	return NewMoney(d, currency), nil
+}
+
+func (m Money) Amount() decimal.Decimal { return m.amount }
+
+func (m Money) Currency() string { return m.currency }
+
+func (m Money) IsZero() bool { return m.amount.IsZero() }
+
+func (m Money) Sign() int { return m.amount.Sign() }
+
+func (m Money) Neg() Money { return Money{amount: m.amount.Neg(), currency: m.currency} }
+
+// Add returns m + o, or ErrCurrencyMismatch if o is in another currency.
+func (m Money) Add(o Money) (Money, error) {
+	if err := m.sameCurrency(o); err != nil {
+		return Money{}, err
+	}
+	return Money{amount: m.amount.Add(o.amount), currency: m.currency}, nil
+}
+
+// Sub returns m - o, or ErrCurrencyMismatch if o is in another currency.
+func (m Money) Sub(o Money) (Money, error) {
+	if err := m.sameCurrency(o); err != nil {
+		return Money{}, err
+	}
+	return Money{amount: m.amount.Sub(o.amount), currency: m.currency}, nil
+}
+
+// Convert expresses m in rate.To. The rate must be quoted from m's currency.
+func (m Money) Convert(rate *ResolvedRate) (Money, error) {
+	if !strings.EqualFold(rate.From, m.currency) {
+		return Money{}, fmt.Errorf("%w: converting %s with a %s->%s rate", ErrCurrencyMismatch, m.currency, rate.From, rate.To)
+	}
+	return NewMoney(m.amount.Mul(rate.Rate), rate.To), nil
+}
+
+// Numeric converts to the store representation without rounding.
+func (m Money) Numeric() pgtype.Numeric {
+	return numericFromDecimal(m.amount)
+}
+
+// Format renders the amount with exactly the currency's decimal places.
+func (m Money) Format(prec Precisions) string {
+	return m.amount.StringFixed(prec.Of(m.currency))
+}
+
+func (m Money) sameCurrency(o Money) error {
+	if m.currency != o.currency {
+		return fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.currency, o.currency)
+	}
+	return nil
+}
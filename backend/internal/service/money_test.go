@@ -0,0 +1,70 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMoneyArithmetic(t *testing.T) {
+	usd := func(s string) Money {
+		m, err := ParseMoney(s, "usd")
+		require.NoError(t, err)
+		return m
+	}
+
+	t.Run("add", func(t *testing.T) {
+		sum, err := usd("100.50").Add(usd("50.25"))
+		require.NoError(t, err)
+		require.Equal(t, "150.75", sum.Amount().String())
+		require.Equal(t, "USD", sum.Currency())
+
+		sum, err = usd("100.00").Add(usd("-30.00"))
+		require.NoError(t, err)
+		require.Equal(t, "70.00", sum.Format(nil))
+	})
+
+	t.Run("sub", func(t *testing.T) {
+		diff, err := usd("100.00").Sub(usd("30.50"))
+		require.NoError(t, err)
+		require.Equal(t, "69.50", diff.Format(nil))
+
+		diff, err = usd("30.00").Sub(usd("100.00"))
+		require.NoError(t, err)
+		require.Equal(t, "-70.00", diff.Format(nil))
+	})
+
+	t.Run("large values stay exact", func(t *testing.T) {
+		sum, err := usd("999999999999.99999999").Add(usd("0.00000001"))
+		require.NoError(t, err)
+		require.Equal(t, "1000000000000", sum.Amount().String())
+	})
+
+	t.Run("mixing currencies fails", func(t *testing.T) {
+		eur := NewMoney(decimal.NewFromInt(5), "EUR")
+		_, err := usd("1").Add(eur)
+		require.ErrorIs(t, err, ErrCurrencyMismatch)
+		_, err = usd("1").Sub(eur)
+		require.ErrorIs(t, err, ErrCurrencyMismatch)
+	})
+
+	t.Run("numeric round trip", func(t *testing.T) {
+		m := MoneyFromNumeric(numericFromString("1234.5678"), "KWD")
+		require.Equal(t, "1234.5678", numericToDecimal(m.Numeric()).String())
+		require.True(t, MoneyFromNumeric(numericFromString(""), "USD").IsZero())
+	})
+}
+
+func TestMoneyConvert(t *testing.T) {
+	rate := &ResolvedRate{From: "USD", To: "EUR", Rate: decimal.RequireFromString("0.92")}
+	amount := NewMoney(decimal.RequireFromString("100"), "usd")
+
+	got, err := amount.Convert(rate)
+	require.NoError(t, err)
+	require.Equal(t, "EUR", got.Currency())
+	require.Equal(t, "92.00", got.Format(nil))
+
+	_, err = NewMoney(decimal.NewFromInt(1), "GBP").Convert(rate)
+	require.ErrorIs(t, err, ErrCurrencyMismatch)
+}
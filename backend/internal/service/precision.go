@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/store"
+)
+
+var ErrAmountPrecision = errors.New("amount has more decimal places than the currency allows")
+
+// defaultCurrencyDecimals is used for currencies that are not in the
+// currencies table and wherever no precision lookup is configured.
+const defaultCurrencyDecimals = 2
+
+type currencyPrecisionStore interface {
+	ListCurrencies(ctx context.Context) ([]store.Currency, error)
+}
+
+// CurrencyPrecision loads the number of minor-unit digits of each currency
+// (0 for JPY, 3 for KWD, 2 for most others) from the currencies table. The
+// table is read once and cached until Invalidate is called after a currency
+// is added.
+type CurrencyPrecision struct {
+	queries currencyPrecisionStore
+
+	mu     sync.Mutex
+	cached Precisions
+}
+
+func NewCurrencyPrecision(queries *store.Queries) *CurrencyPrecision {
+	return &CurrencyPrecision{queries: queries}
+}
+
+// Load returns a snapshot of all currency precisions. A nil CurrencyPrecision
+// yields an empty snapshot, so every currency gets the default.
+func (p *CurrencyPrecision) Load(ctx context.Context) (Precisions, error) {
+	if p == nil {
+		return nil, nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.cached != nil {
+		return p.cached, nil
+	}
+	currencies, err := p.queries.ListCurrencies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p.cached = precisionsFromCurrencies(currencies)
+	return p.cached, nil
+}
+
+// Invalidate drops the cached precisions so the next Load reads the table
+// again. It is safe to call on a nil CurrencyPrecision.
+func (p *CurrencyPrecision) Invalidate() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	p.cached = nil
+	p.mu.Unlock()
+}
+
+// Precisions maps a currency code to its number of decimal places.
+type Precisions map[string]int32
+
+func precisionsFromCurrencies(currencies []store.Currency) Precisions {
+	out := make(Precisions, len(currencies))
+	for _, c := range currencies {
+		out[strings.ToUpper(c.Code)] = int32(c.Decimals)
+	}
+	return out
+}
+
+// Of returns the decimals of code, or defaultCurrencyDecimals if unknown.
+func (p Precisions) Of(code string) int32 {
+	if d, ok := p[strings.ToUpper(code)]; ok {
+		return d
+	}
+	return defaultCurrencyDecimals
+}
+
+// Format formats an amount in currency code with exactly its decimals.
+func (p Precisions) Format(n pgtype.Numeric, code string) string {
+	return formatAmount(n, p.Of(code))
+}
+
+// Check returns ErrAmountPrecision if the decimal string s has more
+// significant decimal places than currency code allows. Trailing zeros are
+// fine: "100.00" is a valid JPY amount. Empty and unparseable strings are left
+// to the caller's own validation.
+func (p Precisions) Check(s, code string) error {
+	if s == "" {
+		return nil
+	}
+	d, err := decimal.NewFromString(s)
+	if err != nil {
+		return nil
+	}
+	if !d.Equal(d.Truncate(p.Of(code))) {
+		return ErrAmountPrecision
+	}
+	return nil
+}
+
+func formatAmount(n pgtype.Numeric, decimals int32) string {
+	if !n.Valid {
+		return "0"
+	}
+	return numericToDecimal(n).StringFixed(decimals)
+}
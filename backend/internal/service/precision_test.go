@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/store"
+)
+
+type mockCurrencyPrecisionStore struct {
+	currencies []store.Currency
+	calls      int
+}
+
+func (m *mockCurrencyPrecisionStore) ListCurrencies(_ context.Context) ([]store.Currency, error) {
+	m.calls++
+	return m.currencies, nil
+}
+
+// testCurrencyPrecision knows JPY (0 decimals), KWD (3) and a few 2-decimal
+// currencies.
+func testCurrencyPrecision() *CurrencyPrecision {
+	return &CurrencyPrecision{queries: &mockCurrencyPrecisionStore{currencies: []store.Currency{
+		{Code: "EUR", Decimals: 2},
+		{Code: "JPY", Decimals: 0},
+		{Code: "KWD", Decimals: 3},
+		{Code: "USD", Decimals: 2},
+	}}}
+}
+
+func TestPrecisions(t *testing.T) {
+	prec, err := testCurrencyPrecision().Load(context.Background())
+	require.NoError(t, err)
+
+	t.Run("of", func(t *testing.T) {
+		require.Equal(t, int32(0), prec.Of("JPY"))
+		require.Equal(t, int32(3), prec.Of("kwd"))
+		require.Equal(t, int32(defaultCurrencyDecimals), prec.Of("XYZ"), "unknown currency")
+	})
+
+	t.Run("format", func(t *testing.T) {
+		require.Equal(t, "1500", prec.Format(numericFromString("1500.00"), "JPY"))
+		require.Equal(t, "12.345", prec.Format(numericFromString("12.345"), "KWD"))
+		require.Equal(t, "12.30", prec.Format(numericFromString("12.3"), "USD"))
+		require.Equal(t, "0", prec.Format(pgtype.Numeric{}, "USD"))
+	})
+
+	t.Run("check", func(t *testing.T) {
+		require.NoError(t, prec.Check("1500", "JPY"))
+		require.NoError(t, prec.Check("1500.00", "JPY"), "trailing zeros are fine")
+		require.ErrorIs(t, prec.Check("1500.5", "JPY"), ErrAmountPrecision)
+		require.NoError(t, prec.Check("1.125", "KWD"))
+		require.ErrorIs(t, prec.Check("1.1255", "KWD"), ErrAmountPrecision)
+		require.ErrorIs(t, prec.Check("1.125", "USD"), ErrAmountPrecision)
+		require.NoError(t, prec.Check("", "USD"))
+	})
+
+	t.Run("nil loader uses the default", func(t *testing.T) {
+		var p *CurrencyPrecision
+		prec, err := p.Load(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, int32(defaultCurrencyDecimals), prec.Of("JPY"))
+	})
+}
+
+func TestCurrencyPrecision_Cache(t *testing.T) {
+	ms := &mockCurrencyPrecisionStore{currencies: []store.Currency{{Code: "USD", Decimals: 2}}}
+	p := &CurrencyPrecision{queries: ms}
+
+	_, err := p.Load(context.Background())
+	require.NoError(t, err)
+	_, err = p.Load(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, ms.calls, "the table is read once")
+
+	ms.currencies = append(ms.currencies, store.Currency{Code: "BHD", Decimals: 3})
+	p.Invalidate()
+	prec, err := p.Load(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, ms.calls)
+	require.Equal(t, int32(3), prec.Of("BHD"), "a new currency is seen after Invalidate")
+}
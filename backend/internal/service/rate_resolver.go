@@ -0,0 +1,249 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/store"
+)
+
+var ErrRateNotFound = errors.New("no exchange rate available for this currency pair")
+
+// How a resolved rate was obtained.
+const (
+	RateMethodIdentity     = "identity"     // same currency, rate 1
+	RateMethodDirect       = "direct"       // stored FROM->TO rate
+	RateMethodInverse      = "inverse"      // 1 / stored TO->FROM rate
+	RateMethodTriangulated = "triangulated" // FROM->pivot * pivot->TO
+)
+
+// inverseRateScale is the number of decimal places kept when inverting a
+// stored rate. Stored rates have 8; the extra digits keep triangulated
+// products from compounding rounding errors.
+const inverseRateScale = 12
+
+type rateResolverStore interface {
+	GetRateOnOrBefore(ctx context.Context, arg store.GetRateOnOrBeforeParams) (store.ExchangeRate, error)
+}
+
+// RateLeg is one stored rate used to build a resolved rate.
+type RateLeg struct {
+	From     string
+	To       string
+	Rate     decimal.Decimal
+	Date     time.Time
+	Source   string
+	Personal bool // the user's own rate rather than a global one
+	Inverted bool // the stored rate was To->From
+}
+
+// ResolvedRate is the rate to convert From into To on a given date.
+type ResolvedRate struct {
+	From   string
+	To     string
+	Rate   decimal.Decimal
+	Date   time.Time // publication date of the oldest leg; zero for identity
+	Method string
+	Via    string // pivot currency, for triangulated rates
+	Legs   []RateLeg
+}
+
+// Source lists the providers behind the rate, e.g. "ecb" or "ecb+cbr".
+func (r *ResolvedRate) Source() string {
+	var sources []string
+	for _, leg := range r.Legs {
+		seen := false
+		for _, s := range sources {
+			if s == leg.Source {
+				seen = true
+				break
+			}
+		}
+		if !seen {
+			sources = append(sources, leg.Source)
+		}
+	}
+	return strings.Join(sources, "+")
+}
+
+// RateResolver finds the exchange rate between two currencies on a date using
+// the stored rates: the most recent rate on or before the date, inverting
+// reversed pairs, and triangulating through pivot currencies when neither
+// direction is stored. Rates are resolved for a user: their personal rates are
+// considered alongside global ones and win over them, however recent.
+type RateResolver struct {
+	queries rateResolverStore
+	pivots  []string
+}
+
+func NewRateResolver(queries *store.Queries, pivots []string) *RateResolver {
+	return &RateResolver{queries: queries, pivots: normalizePivots(pivots)}
+}
+
+// Total sums balances in base currency at the rates in effect on date.
+// Currencies without a resolvable rate are left out of the total and
+// returned so the client can flag the figure as incomplete.
+func (r *RateResolver) Total(ctx context.Context, userID uuid.UUID, balances []Money, base string, date time.Time) (Money, []string, error) {
+	total := NewMoney(decimal.Zero, base)
+	unconverted := []string{}
+	rates := make(map[string]*ResolvedRate)
+	for _, balance := range balances {
+		currency := balance.Currency()
+		rate, ok := rates[currency]
+		if !ok {
+			var err error
+			rate, err = r.Resolve(ctx, userID, currency, base, date)
+			if err != nil && !errors.Is(err, ErrRateNotFound) {
+				return Money{}, nil, err
+			}
+			rates[currency] = rate
+			if rate == nil {
+				unconverted = append(unconverted, currency)
+			}
+		}
+		if rate == nil {
+			continue
+		}
+		converted, err := balance.Convert(rate)
+		if err != nil {
+			return Money{}, nil, err
+		}
+		if total, err = total.Add(converted); err != nil {
+			return Money{}, nil, err
+		}
+	}
+	return total, unconverted, nil
+}
+
+func normalizePivots(pivots []string) []string {
+	out := make([]string, 0, len(pivots))
+	for _, p := range pivots {
+		if p = strings.ToUpper(strings.TrimSpace(p)); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// Resolve returns the rate from -> to on date as seen by userID (uuid.Nil for
+// global rates only). A direct or inverse rate is preferred over
+// triangulation; among triangulations, the one whose oldest leg is most recent
+// wins, with ties going to the earlier pivot in the list.
+func (r *RateResolver) Resolve(ctx context.Context, userID uuid.UUID, from, to string, date time.Time) (*ResolvedRate, error) {
+	from = strings.ToUpper(from)
+	to = strings.ToUpper(to)
+
+	if from == to {
+		return &ResolvedRate{From: from, To: to, Rate: decimal.NewFromInt(1), Method: RateMethodIdentity}, nil
+	}
+
+	leg, err := r.pair(ctx, userID, from, to, date)
+	if err != nil {
+		return nil, err
+	}
+	if leg != nil {
+		method := RateMethodDirect
+		if leg.Inverted {
+			method = RateMethodInverse
+		}
+		return &ResolvedRate{From: from, To: to, Rate: leg.Rate, Date: leg.Date, Method: method, Legs: []RateLeg{*leg}}, nil
+	}
+
+	var best *ResolvedRate
+	for _, pivot := range r.pivots {
+		if pivot == from || pivot == to {
+			continue
+		}
+		first, err := r.pair(ctx, userID, from, pivot, date)
+		if err != nil {
+			return nil, err
+		}
+		if first == nil {
+			continue
+		}
+		second, err := r.pair(ctx, userID, pivot, to, date)
+		if err != nil {
+			return nil, err
+		}
+		if second == nil {
+			continue
+		}
+
+		legDate := first.Date
+		if second.Date.Before(legDate) {
+			legDate = second.Date
+		}
+		if best != nil && !legDate.After(best.Date) {
+			continue
+		}
+		best = &ResolvedRate{
+			From:   from,
+			To:     to,
+			Rate:   first.Rate.Mul(second.Rate),
+			Date:   legDate,
+			Method: RateMethodTriangulated,
+			Via:    pivot,
+			Legs:   []RateLeg{*first, *second},
+		}
+	}
+	if best == nil {
+		return nil, ErrRateNotFound
+	}
+	return best, nil
+}
+
+// pair looks up the most recent stored rate for from -> to on or before date,
+// in either direction. The newer of the two wins; on the same date a personal
+// rate beats a global one, and then the direct rate is preferred. Returns nil
+// when neither direction is stored.
+func (r *RateResolver) pair(ctx context.Context, userID uuid.UUID, from, to string, date time.Time) (*RateLeg, error) {
+	direct, err := r.lookup(ctx, userID, from, to, date)
+	if err != nil {
+		return nil, err
+	}
+	reverse, err := r.lookup(ctx, userID, to, from, date)
+	if err != nil {
+		return nil, err
+	}
+
+	if reverse != nil && (direct == nil || reverse.Date.After(direct.Date) ||
+		reverse.Date.Equal(direct.Date) && reverse.Personal && !direct.Personal) {
+		return &RateLeg{
+			From:     from,
+			To:       to,
+			Rate:     decimal.NewFromInt(1).DivRound(reverse.Rate, inverseRateScale),
+			Date:     reverse.Date,
+			Source:   reverse.Source,
+			Personal: reverse.Personal,
+			Inverted: true,
+		}, nil
+	}
+	return direct, nil
+}
+
+func (r *RateResolver) lookup(ctx context.Context, userID uuid.UUID, from, to string, date time.Time) (*RateLeg, error) {
+	rate, err := r.queries.GetRateOnOrBefore(ctx, store.GetRateOnOrBeforeParams{
+		FromCurrency: from,
+		ToCurrency:   to,
+		Date:         pgtype.Date{Time: date, Valid: true},
+		UserID:       userID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	value := numericToDecimal(rate.Rate)
+	if value.Sign() <= 0 {
+		return nil, nil
+	}
+	return &RateLeg{From: from, To: to, Rate: value, Date: rate.Date.Time, Source: rate.Source, Personal: rate.UserID.Valid}, nil
+}
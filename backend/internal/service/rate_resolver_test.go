@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/dto"
+	"github.com/sanches/finance-tracker-cc/backend/internal/store"
+)
+
+// mockRateStore serves GetRateOnOrBefore from an in-memory list of rates,
+// following the query's ordering: newest first, then personal before global.
+type mockRateStore struct {
+	rates []store.ExchangeRate
+}
+
+func (m *mockRateStore) GetRateOnOrBefore(_ context.Context, arg store.GetRateOnOrBeforeParams) (store.ExchangeRate, error) {
+	var best *store.ExchangeRate
+	for i, r := range m.rates {
+		if r.FromCurrency != arg.FromCurrency || r.ToCurrency != arg.ToCurrency || r.Date.Time.After(arg.Date.Time) {
+			continue
+		}
+		if r.UserID.Valid && uuid.UUID(r.UserID.Bytes) != arg.UserID {
+			continue
+		}
+		if best == nil || r.Date.Time.After(best.Date.Time) ||
+			r.Date.Time.Equal(best.Date.Time) && r.UserID.Valid && !best.UserID.Valid {
+			best = &m.rates[i]
+		}
+	}
+	if best == nil {
+		return store.ExchangeRate{}, pgx.ErrNoRows
+	}
+	return *best, nil
+}
+
+func storedRate(t *testing.T, from, to, rate, date, source string) store.ExchangeRate {
+	t.Helper()
+	d, err := dateFromString(date)
+	require.NoError(t, err)
+	return store.ExchangeRate{FromCurrency: from, ToCurrency: to, Rate: numericFromString(rate), Date: d, Source: source}
+}
+
+func personalRate(t *testing.T, userID uuid.UUID, from, to, rate, date string) store.ExchangeRate {
+	t.Helper()
+	r := storedRate(t, from, to, rate, date, rateSourceManual)
+	r.UserID = pgtype.UUID{Bytes: userID, Valid: true}
+	return r
+}
+
+func TestRateResolverResolve(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("identity", func(t *testing.T) {
+		r := &RateResolver{queries: &mockRateStore{}}
+		res, err := r.Resolve(ctx, uuid.Nil, "eur", "EUR", mustDate(t, "2025-03-14"))
+		require.NoError(t, err)
+		require.Equal(t, RateMethodIdentity, res.Method)
+		require.Equal(t, "1", res.Rate.String())
+		require.True(t, res.Date.IsZero())
+	})
+
+	t.Run("direct uses nearest rate on or before date", func(t *testing.T) {
+		r := &RateResolver{queries: &mockRateStore{rates: []store.ExchangeRate{
+			storedRate(t, "AMD", "EUR", "0.00230000", "2025-03-10", "ecb"),
+			storedRate(t, "AMD", "EUR", "0.00240000", "2025-03-13", "ecb"),
+			storedRate(t, "AMD", "EUR", "0.00250000", "2025-03-15", "ecb"),
+		}}}
+		res, err := r.Resolve(ctx, uuid.Nil, "AMD", "EUR", mustDate(t, "2025-03-14"))
+		require.NoError(t, err)
+		require.Equal(t, RateMethodDirect, res.Method)
+		require.Equal(t, "0.0024", res.Rate.String())
+		require.Equal(t, mustDate(t, "2025-03-13"), res.Date)
+		require.Equal(t, "ecb", res.Source())
+	})
+
+	t.Run("inverse when only reversed pair is stored", func(t *testing.T) {
+		r := &RateResolver{queries: &mockRateStore{rates: []store.ExchangeRate{
+			storedRate(t, "EUR", "AMD", "400", "2025-03-14", "cbr"),
+		}}}
+		res, err := r.Resolve(ctx, uuid.Nil, "AMD", "EUR", mustDate(t, "2025-03-14"))
+		require.NoError(t, err)
+		require.Equal(t, RateMethodInverse, res.Method)
+		require.Equal(t, "0.0025", res.Rate.String())
+		require.True(t, res.Legs[0].Inverted)
+	})
+
+	t.Run("newer inverse wins over older direct", func(t *testing.T) {
+		r := &RateResolver{queries: &mockRateStore{rates: []store.ExchangeRate{
+			storedRate(t, "AMD", "EUR", "0.0023", "2025-01-01", "manual"),
+			storedRate(t, "EUR", "AMD", "400", "2025-03-01", "cbr"),
+		}}}
+		res, err := r.Resolve(ctx, uuid.Nil, "AMD", "EUR", mustDate(t, "2025-03-14"))
+		require.NoError(t, err)
+		require.Equal(t, RateMethodInverse, res.Method)
+	})
+
+	t.Run("same date prefers direct", func(t *testing.T) {
+		r := &RateResolver{queries: &mockRateStore{rates: []store.ExchangeRate{
+			storedRate(t, "AMD", "EUR", "0.0023", "2025-03-14", "manual"),
+			storedRate(t, "EUR", "AMD", "400", "2025-03-14", "cbr"),
+		}}}
+		res, err := r.Resolve(ctx, uuid.Nil, "AMD", "EUR", mustDate(t, "2025-03-14"))
+		require.NoError(t, err)
+		require.Equal(t, RateMethodDirect, res.Method)
+		require.Equal(t, "0.0023", res.Rate.String())
+	})
+
+	t.Run("triangulates through pivot", func(t *testing.T) {
+		r := &RateResolver{pivots: []string{"USD", "RUB"}, queries: &mockRateStore{rates: []store.ExchangeRate{
+			storedRate(t, "AMD", "RUB", "0.22", "2025-03-14", "cbr"),
+			storedRate(t, "EUR", "RUB", "95", "2025-03-13", "cbr"),
+		}}}
+		res, err := r.Resolve(ctx, uuid.Nil, "AMD", "EUR", mustDate(t, "2025-03-14"))
+		require.NoError(t, err)
+		require.Equal(t, RateMethodTriangulated, res.Method)
+		require.Equal(t, "RUB", res.Via)
+		require.Equal(t, "0.00231579", formatRate(res.Rate)) // 0.22 / 95
+		require.Equal(t, mustDate(t, "2025-03-13"), res.Date, "oldest leg date")
+		require.Len(t, res.Legs, 2)
+		require.False(t, res.Legs[0].Inverted)
+		require.True(t, res.Legs[1].Inverted)
+		require.Equal(t, "cbr", res.Source())
+	})
+
+	t.Run("triangulation prefers most recent pivot", func(t *testing.T) {
+		r := &RateResolver{pivots: []string{"USD", "EUR"}, queries: &mockRateStore{rates: []store.ExchangeRate{
+			storedRate(t, "AMD", "USD", "0.0026", "2025-01-01", "fawazahmed0"),
+			storedRate(t, "USD", "GEL", "2.8", "2025-03-14", "fawazahmed0"),
+			storedRate(t, "AMD", "EUR", "0.0024", "2025-03-14", "ecb"),
+			storedRate(t, "EUR", "GEL", "3.0", "2025-03-12", "ecb"),
+		}}}
+		res, err := r.Resolve(ctx, uuid.Nil, "AMD", "GEL", mustDate(t, "2025-03-14"))
+		require.NoError(t, err)
+		require.Equal(t, "EUR", res.Via)
+		require.Equal(t, "0.0072", res.Rate.String())
+	})
+
+	t.Run("mixed sources are joined", func(t *testing.T) {
+		r := &RateResolver{pivots: []string{"EUR"}, queries: &mockRateStore{rates: []store.ExchangeRate{
+			storedRate(t, "AMD", "EUR", "0.0024", "2025-03-14", "ecb"),
+			storedRate(t, "EUR", "RUB", "95", "2025-03-14", "cbr"),
+		}}}
+		res, err := r.Resolve(ctx, uuid.Nil, "AMD", "RUB", mustDate(t, "2025-03-14"))
+		require.NoError(t, err)
+		require.Equal(t, "ecb+cbr", res.Source())
+	})
+
+	t.Run("personal rate wins over global on the same date", func(t *testing.T) {
+		userID := uuid.New()
+		r := &RateResolver{queries: &mockRateStore{rates: []store.ExchangeRate{
+			storedRate(t, "AMD", "EUR", "0.0024", "2025-03-14", "ecb"),
+			personalRate(t, userID, "AMD", "EUR", "0.0023", "2025-03-14"),
+		}}}
+		res, err := r.Resolve(ctx, userID, "AMD", "EUR", mustDate(t, "2025-03-14"))
+		require.NoError(t, err)
+		require.Equal(t, "0.0023", res.Rate.String())
+		require.True(t, res.Legs[0].Personal)
+
+		// Without the user the global rate applies.
+		res, err = r.Resolve(ctx, uuid.Nil, "AMD", "EUR", mustDate(t, "2025-03-14"))
+		require.NoError(t, err)
+		require.Equal(t, "0.0024", res.Rate.String())
+	})
+
+	t.Run("newer global rate replaces an older personal rate", func(t *testing.T) {
+		userID := uuid.New()
+		r := &RateResolver{queries: &mockRateStore{rates: []store.ExchangeRate{
+			personalRate(t, userID, "AMD", "EUR", "0.0023", "2022-06-01"),
+			storedRate(t, "AMD", "EUR", "0.0024", "2025-03-14", "ecb"),
+		}}}
+		res, err := r.Resolve(ctx, userID, "AMD", "EUR", mustDate(t, "2025-03-14"))
+		require.NoError(t, err)
+		require.Equal(t, "0.0024", res.Rate.String())
+		require.False(t, res.Legs[0].Personal)
+
+		// On the override's own date it still applies.
+		res, err = r.Resolve(ctx, userID, "AMD", "EUR", mustDate(t, "2022-06-01"))
+		require.NoError(t, err)
+		require.Equal(t, "0.0023", res.Rate.String())
+	})
+
+	t.Run("personal rate in reverse wins over a global rate on the same date", func(t *testing.T) {
+		userID := uuid.New()
+		r := &RateResolver{queries: &mockRateStore{rates: []store.ExchangeRate{
+			storedRate(t, "AMD", "EUR", "0.0024", "2025-03-14", "ecb"),
+			personalRate(t, userID, "EUR", "AMD", "400", "2025-03-14"),
+		}}}
+		res, err := r.Resolve(ctx, userID, "AMD", "EUR", mustDate(t, "2025-03-14"))
+		require.NoError(t, err)
+		require.True(t, res.Legs[0].Personal)
+		require.True(t, res.Legs[0].Inverted)
+	})
+
+	t.Run("other users' personal rates are ignored", func(t *testing.T) {
+		r := &RateResolver{queries: &mockRateStore{rates: []store.ExchangeRate{
+			personalRate(t, uuid.New(), "AMD", "EUR", "0.0023", "2025-03-14"),
+		}}}
+		_, err := r.Resolve(ctx, uuid.New(), "AMD", "EUR", mustDate(t, "2025-03-14"))
+		require.ErrorIs(t, err, ErrRateNotFound)
+	})
+
+	t.Run("no rate", func(t *testing.T) {
+		r := &RateResolver{pivots: []string{"USD"}, queries: &mockRateStore{rates: []store.ExchangeRate{
+			storedRate(t, "AMD", "EUR", "0.0024", "2025-03-15", "ecb"),
+		}}}
+		_, err := r.Resolve(ctx, uuid.Nil, "AMD", "EUR", mustDate(t, "2025-03-14"))
+		require.ErrorIs(t, err, ErrRateNotFound)
+	})
+}
+
+func TestExchangeRateConvert(t *testing.T) {
+	resolver := &RateResolver{queries: &mockRateStore{rates: []store.ExchangeRate{
+		storedRate(t, "EUR", "AMD", "416.5", "2025-03-14", "ecb"),
+	}}}
+	svc := &ExchangeRate{rates: resolver}
+
+	resp, err := svc.Convert(context.Background(), uuid.Nil, dto.ConvertRequest{Amount: "1000", From: "amd", To: "eur", Date: "2025-03-16"})
+	require.NoError(t, err)
+	require.Equal(t, "AMD", resp.From)
+	require.Equal(t, "EUR", resp.To)
+	require.Equal(t, "2025-03-16", resp.Date)
+	require.Equal(t, "2.40", resp.ConvertedAmount)
+	require.Equal(t, "0.00240096", resp.Rate)
+	require.Equal(t, "2025-03-14", resp.RateDate)
+	require.Equal(t, RateMethodInverse, resp.Method)
+	require.Equal(t, "ecb", resp.Source)
+	require.Len(t, resp.Legs, 1)
+
+	_, err = svc.Convert(context.Background(), uuid.Nil, dto.ConvertRequest{Amount: "1", From: "AMD", To: "EUR", Date: "2025-03-13"})
+	require.ErrorIs(t, err, ErrRateNotFound)
+
+	// Today is the default date.
+	resp, err = svc.Convert(context.Background(), uuid.Nil, dto.ConvertRequest{Amount: "5", From: "USD", To: "usd"})
+	require.NoError(t, err)
+	require.Equal(t, time.Now().UTC().Format("2006-01-02"), resp.Date)
+	require.Equal(t, "5.00", resp.ConvertedAmount)
+	require.Empty(t, resp.RateDate)
+}
@@ -0,0 +1,270 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/dto"
+	"github.com/sanches/finance-tracker-cc/backend/internal/store"
+)
+
+var (
+	ErrReconciliationInProgress = errors.New("account already has a reconciliation in progress")
+	ErrReconciliationFinished   = errors.New("reconciliation is already finished")
+	ErrReconciliationUnbalanced = errors.New("cleared balance does not match the statement balance")
+	ErrInvalidStatement         = errors.New("statement_date must be YYYY-MM-DD and statement_balance a decimal number")
+)
+
+const (
+	ReconciliationInProgress = "in_progress"
+	ReconciliationFinished   = "finished"
+)
+
+type reconciliationStore interface {
+	CreateReconciliation(ctx context.Context, arg store.CreateReconciliationParams) (store.Reconciliation, error)
+	GetReconciliation(ctx context.Context, arg store.GetReconciliationParams) (store.Reconciliation, error)
+	ListReconciliations(ctx context.Context, arg store.ListReconciliationsParams) ([]store.Reconciliation, error)
+	DeleteReconciliation(ctx context.Context, arg store.DeleteReconciliationParams) (int64, error)
+	GetClearedBalance(ctx context.Context, arg store.GetClearedBalanceParams) (store.GetClearedBalanceRow, error)
+	GetAccount(ctx context.Context, arg store.GetAccountParams) (store.Account, error)
+	WithTx(tx pgx.Tx) *store.Queries
+}
+
+// Reconciliation matches an account against a bank statement. The user
+// starts one with the statement date and closing balance, marks transactions
+// cleared until the cleared balance equals the statement, and finishes it,
+// which locks the cleared transactions as reconciled.
+type Reconciliation struct {
+	queries    reconciliationStore
+	pool       *pgxpool.Pool
+	currencies *CurrencyPrecision
+}
+
+func NewReconciliation(queries *store.Queries, pool *pgxpool.Pool, currencies *CurrencyPrecision) *Reconciliation {
+	return &Reconciliation{queries: queries, pool: pool, currencies: currencies}
+}
+
+func (s *Reconciliation) Start(ctx context.Context, userID uuid.UUID, req dto.StartReconciliationRequest) (*dto.ReconciliationResponse, error) {
+	date, err := dateFromString(req.StatementDate)
+	if err != nil {
+		return nil, ErrInvalidStatement
+	}
+	balance := numericFromString(req.StatementBalance)
+	if !balance.Valid {
+		return nil, ErrInvalidStatement
+	}
+	acct, err := s.account(ctx, userID, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	prec, err := s.currencies.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := prec.Check(req.StatementBalance, acct.Currency); err != nil {
+		return nil, err
+	}
+
+	rec, err := s.queries.CreateReconciliation(ctx, store.CreateReconciliationParams{
+		UserID:           userID,
+		AccountID:        acct.ID,
+		StatementDate:    date,
+		StatementBalance: balance,
+	})
+	if err != nil {
+		if isDuplicateKey(err) {
+			return nil, ErrReconciliationInProgress
+		}
+		return nil, err
+	}
+	return s.toResponse(ctx, rec, acct.Currency, prec)
+}
+
+func (s *Reconciliation) Get(ctx context.Context, userID, id uuid.UUID) (*dto.ReconciliationResponse, error) {
+	rec, err := s.get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	acct, err := s.account(ctx, userID, rec.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	prec, err := s.currencies.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.toResponse(ctx, rec, acct.Currency, prec)
+}
+
+func (s *Reconciliation) List(ctx context.Context, userID, accountID uuid.UUID) ([]dto.ReconciliationResponse, error) {
+	acct, err := s.account(ctx, userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+	recs, err := s.queries.ListReconciliations(ctx, store.ListReconciliationsParams{AccountID: accountID, UserID: userID})
+	if err != nil {
+		return nil, err
+	}
+	prec, err := s.currencies.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.ReconciliationResponse, 0, len(recs))
+	for _, rec := range recs {
+		resp, err := s.toResponse(ctx, rec, acct.Currency, prec)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *resp)
+	}
+	return result, nil
+}
+
+// Finish locks every cleared transaction up to the statement date as
+// reconciled. It fails with ErrReconciliationUnbalanced unless the cleared
+// balance equals the statement balance exactly.
+func (s *Reconciliation) Finish(ctx context.Context, userID, id uuid.UUID) (*dto.ReconciliationResponse, error) {
+	rec, err := s.get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if rec.FinishedAt.Valid {
+		return nil, ErrReconciliationFinished
+	}
+	cleared, err := s.queries.GetClearedBalance(ctx, store.GetClearedBalanceParams{
+		StatementDate: rec.StatementDate,
+		AccountID:     rec.AccountID,
+		UserID:        userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !numericToDecimal(rec.StatementBalance).Equal(numericToDecimal(cleared.ClearedBalance)) {
+		return nil, ErrReconciliationUnbalanced
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	q := s.queries.WithTx(tx)
+	n, err := q.MarkTransactionsReconciled(ctx, store.MarkTransactionsReconciledParams{
+		AccountID:     rec.AccountID,
+		UserID:        userID,
+		StatementDate: rec.StatementDate,
+	})
+	if err != nil {
+		return nil, err
+	}
+	rec, err = q.FinishReconciliation(ctx, store.FinishReconciliationParams{
+		ID:              id,
+		UserID:          userID,
+		ReconciledCount: int32(n),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = ErrReconciliationFinished
+		}
+		return nil, err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	acct, err := s.account(ctx, userID, rec.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	prec, err := s.currencies.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.toResponse(ctx, rec, acct.Currency, prec)
+}
+
+// Cancel discards a reconciliation in progress. Transactions keep their
+// cleared status.
+func (s *Reconciliation) Cancel(ctx context.Context, userID, id uuid.UUID) error {
+	n, err := s.queries.DeleteReconciliation(ctx, store.DeleteReconciliationParams{ID: id, UserID: userID})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		if _, err := s.get(ctx, userID, id); err != nil {
+			return err
+		}
+		return ErrReconciliationFinished
+	}
+	return nil
+}
+
+func (s *Reconciliation) get(ctx context.Context, userID, id uuid.UUID) (store.Reconciliation, error) {
+	rec, err := s.queries.GetReconciliation(ctx, store.GetReconciliationParams{ID: id, UserID: userID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return store.Reconciliation{}, ErrNotFound
+		}
+		return store.Reconciliation{}, err
+	}
+	return rec, nil
+}
+
+func (s *Reconciliation) account(ctx context.Context, userID, accountID uuid.UUID) (store.Account, error) {
+	acct, err := s.queries.GetAccount(ctx, store.GetAccountParams{ID: accountID, UserID: userID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return store.Account{}, ErrAccountNotFound
+		}
+		return store.Account{}, err
+	}
+	return acct, nil
+}
+
+// toResponse adds the cleared balance and the difference still to explain
+// for a reconciliation in progress.
+func (s *Reconciliation) toResponse(ctx context.Context, rec store.Reconciliation, currency string, prec Precisions) (*dto.ReconciliationResponse, error) {
+	resp := &dto.ReconciliationResponse{
+		ID:               rec.ID,
+		AccountID:        rec.AccountID,
+		Currency:         currency,
+		StatementDate:    dateToString(rec.StatementDate),
+		StatementBalance: prec.Format(rec.StatementBalance, currency),
+		Status:           ReconciliationInProgress,
+		ReconciledCount:  int(rec.ReconciledCount),
+		CreatedAt:        rec.CreatedAt.Time,
+	}
+	if rec.FinishedAt.Valid {
+		resp.Status = ReconciliationFinished
+		resp.FinishedAt = &rec.FinishedAt.Time
+		return resp, nil
+	}
+
+	cleared, err := s.queries.GetClearedBalance(ctx, store.GetClearedBalanceParams{
+		StatementDate: rec.StatementDate,
+		AccountID:     rec.AccountID,
+		UserID:        rec.UserID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	statement := MoneyFromNumeric(rec.StatementBalance, currency)
+	difference, err := statement.Sub(MoneyFromNumeric(cleared.ClearedBalance, currency))
+	if err != nil {
+		return nil, err
+	}
+	resp.ClearedBalance = prec.Format(cleared.ClearedBalance, currency)
+	resp.ClearedCount = int(cleared.ClearedCount)
+	resp.Difference = difference.Format(prec)
+	return resp, nil
+}
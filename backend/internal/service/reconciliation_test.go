@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/dto"
+	"github.com/sanches/finance-tracker-cc/backend/internal/store"
+)
+
+type mockReconciliationStore struct {
+	createReconciliationFn func(ctx context.Context, arg store.CreateReconciliationParams) (store.Reconciliation, error)
+	getReconciliationFn    func(ctx context.Context, arg store.GetReconciliationParams) (store.Reconciliation, error)
+	listReconciliationsFn  func(ctx context.Context, arg store.ListReconciliationsParams) ([]store.Reconciliation, error)
+	deleteReconciliationFn func(ctx context.Context, arg store.DeleteReconciliationParams) (int64, error)
+	getClearedBalanceFn    func(ctx context.Context, arg store.GetClearedBalanceParams) (store.GetClearedBalanceRow, error)
+	getAccountFn           func(ctx context.Context, arg store.GetAccountParams) (store.Account, error)
+}
+
+func (m *mockReconciliationStore) CreateReconciliation(ctx context.Context, arg store.CreateReconciliationParams) (store.Reconciliation, error) {
+	return m.createReconciliationFn(ctx, arg)
+}
+
+func (m *mockReconciliationStore) GetReconciliation(ctx context.Context, arg store.GetReconciliationParams) (store.Reconciliation, error) {
+	return m.getReconciliationFn(ctx, arg)
+}
+
+func (m *mockReconciliationStore) ListReconciliations(ctx context.Context, arg store.ListReconciliationsParams) ([]store.Reconciliation, error) {
+	return m.listReconciliationsFn(ctx, arg)
+}
+
+func (m *mockReconciliationStore) DeleteReconciliation(ctx context.Context, arg store.DeleteReconciliationParams) (int64, error) {
+	return m.deleteReconciliationFn(ctx, arg)
+}
+
+func (m *mockReconciliationStore) GetClearedBalance(ctx context.Context, arg store.GetClearedBalanceParams) (store.GetClearedBalanceRow, error) {
+	return m.getClearedBalanceFn(ctx, arg)
+}
+
+func (m *mockReconciliationStore) GetAccount(ctx context.Context, arg store.GetAccountParams) (store.Account, error) {
+	return m.getAccountFn(ctx, arg)
+}
+
+func (m *mockReconciliationStore) WithTx(tx pgx.Tx) *store.Queries {
+	return nil
+}
+
+func TestReconciliationStart(t *testing.T) {
+	userID := uuid.New()
+	accountID := uuid.New()
+
+	usdAccount := func(ctx context.Context, arg store.GetAccountParams) (store.Account, error) {
+		return store.Account{ID: accountID, UserID: userID, Currency: "USD"}, nil
+	}
+
+	t.Run("invalid statement", func(t *testing.T) {
+		svc := &Reconciliation{queries: &mockReconciliationStore{getAccountFn: usdAccount}}
+		_, err := svc.Start(context.Background(), userID, dto.StartReconciliationRequest{
+			AccountID:        accountID,
+			StatementDate:    "31/01/2025",
+			StatementBalance: "100",
+		})
+		require.ErrorIs(t, err, ErrInvalidStatement)
+
+		_, err = svc.Start(context.Background(), userID, dto.StartReconciliationRequest{
+			AccountID:        accountID,
+			StatementDate:    "2025-01-31",
+			StatementBalance: "abc",
+		})
+		require.ErrorIs(t, err, ErrInvalidStatement)
+	})
+
+	t.Run("precision", func(t *testing.T) {
+		svc := &Reconciliation{queries: &mockReconciliationStore{getAccountFn: usdAccount}, currencies: testCurrencyPrecision()}
+		_, err := svc.Start(context.Background(), userID, dto.StartReconciliationRequest{
+			AccountID:        accountID,
+			StatementDate:    "2025-01-31",
+			StatementBalance: "100.005",
+		})
+		require.ErrorIs(t, err, ErrAmountPrecision)
+	})
+
+	t.Run("already in progress", func(t *testing.T) {
+		svc := &Reconciliation{queries: &mockReconciliationStore{
+			getAccountFn: usdAccount,
+			createReconciliationFn: func(ctx context.Context, arg store.CreateReconciliationParams) (store.Reconciliation, error) {
+				return store.Reconciliation{}, &pgconn.PgError{Code: "23505"}
+			},
+		}}
+		_, err := svc.Start(context.Background(), userID, dto.StartReconciliationRequest{
+			AccountID:        accountID,
+			StatementDate:    "2025-01-31",
+			StatementBalance: "100",
+		})
+		require.ErrorIs(t, err, ErrReconciliationInProgress)
+	})
+}
+
+func TestReconciliationGet_Difference(t *testing.T) {
+	userID := uuid.New()
+	accountID := uuid.New()
+	recID := uuid.New()
+
+	svc := &Reconciliation{queries: &mockReconciliationStore{
+		getReconciliationFn: func(ctx context.Context, arg store.GetReconciliationParams) (store.Reconciliation, error) {
+			return store.Reconciliation{
+				ID:               recID,
+				UserID:           userID,
+				AccountID:        accountID,
+				StatementDate:    pgtype.Date{Time: time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC), Valid: true},
+				StatementBalance: numericFromString("1250.5"),
+			}, nil
+		},
+		getAccountFn: func(ctx context.Context, arg store.GetAccountParams) (store.Account, error) {
+			return store.Account{ID: accountID, UserID: userID, Currency: "USD"}, nil
+		},
+		getClearedBalanceFn: func(ctx context.Context, arg store.GetClearedBalanceParams) (store.GetClearedBalanceRow, error) {
+			return store.GetClearedBalanceRow{ClearedBalance: numericFromString("1200"), ClearedCount: 7}, nil
+		},
+	}, currencies: testCurrencyPrecision()}
+
+	resp, err := svc.Get(context.Background(), userID, recID)
+	require.NoError(t, err)
+	require.Equal(t, ReconciliationInProgress, resp.Status)
+	require.Equal(t, "2025-01-31", resp.StatementDate)
+	require.Equal(t, "1250.50", resp.StatementBalance)
+	require.Equal(t, "1200.00", resp.ClearedBalance)
+	require.Equal(t, "50.50", resp.Difference)
+	require.Equal(t, 7, resp.ClearedCount)
+}
+
+func TestReconciliationFinish(t *testing.T) {
+	userID := uuid.New()
+	recID := uuid.New()
+
+	t.Run("unbalanced", func(t *testing.T) {
+		svc := &Reconciliation{queries: &mockReconciliationStore{
+			getReconciliationFn: func(ctx context.Context, arg store.GetReconciliationParams) (store.Reconciliation, error) {
+				return store.Reconciliation{ID: recID, UserID: userID, StatementBalance: numericFromString("100")}, nil
+			},
+			getClearedBalanceFn: func(ctx context.Context, arg store.GetClearedBalanceParams) (store.GetClearedBalanceRow, error) {
+				return store.GetClearedBalanceRow{ClearedBalance: numericFromString("99.99")}, nil
+			},
+		}}
+		_, err := svc.Finish(context.Background(), userID, recID)
+		require.ErrorIs(t, err, ErrReconciliationUnbalanced)
+	})
+
+	t.Run("already finished", func(t *testing.T) {
+		svc := &Reconciliation{queries: &mockReconciliationStore{
+			getReconciliationFn: func(ctx context.Context, arg store.GetReconciliationParams) (store.Reconciliation, error) {
+				return store.Reconciliation{ID: recID, UserID: userID, FinishedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true}}, nil
+			},
+		}}
+		_, err := svc.Finish(context.Background(), userID, recID)
+		require.ErrorIs(t, err, ErrReconciliationFinished)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		svc := &Reconciliation{queries: &mockReconciliationStore{
+			getReconciliationFn: func(ctx context.Context, arg store.GetReconciliationParams) (store.Reconciliation, error) {
+				return store.Reconciliation{}, pgx.ErrNoRows
+			},
+		}}
+		_, err := svc.Finish(context.Background(), userID, recID)
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestReconciliationCancel(t *testing.T) {
+	userID := uuid.New()
+	recID := uuid.New()
+
+	t.Run("finished", func(t *testing.T) {
+		svc := &Reconciliation{queries: &mockReconciliationStore{
+			deleteReconciliationFn: func(ctx context.Context, arg store.DeleteReconciliationParams) (int64, error) {
+				return 0, nil
+			},
+			getReconciliationFn: func(ctx context.Context, arg store.GetReconciliationParams) (store.Reconciliation, error) {
+				return store.Reconciliation{ID: recID, FinishedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true}}, nil
+			},
+		}}
+		err := svc.Cancel(context.Background(), userID, recID)
+		require.ErrorIs(t, err, ErrReconciliationFinished)
+	})
+
+	t.Run("in progress", func(t *testing.T) {
+		svc := &Reconciliation{queries: &mockReconciliationStore{
+			deleteReconciliationFn: func(ctx context.Context, arg store.DeleteReconciliationParams) (int64, error) {
+				return 1, nil
+			},
+		}}
+		require.NoError(t, svc.Cancel(context.Background(), userID, recID))
+	})
+}
@@ -2,9 +2,11 @@ package service
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 
 	"github.com/sanches/finance-tracker-cc/backend/internal/dto"
@@ -16,20 +18,39 @@ type reportStore interface {
 	MonthlyIncomeExpense(ctx context.Context, arg store.MonthlyIncomeExpenseParams) ([]store.MonthlyIncomeExpenseRow, error)
 	BalanceHistory(ctx context.Context, arg store.BalanceHistoryParams) ([]store.BalanceHistoryRow, error)
 	DashboardSummary(ctx context.Context, arg store.DashboardSummaryParams) (store.DashboardSummaryRow, error)
-	ListAccounts(ctx context.Context, userID uuid.UUID) ([]store.ListAccountsRow, error)
-	GetAccountTransactionSums(ctx context.Context, accountID uuid.UUID) (store.GetAccountTransactionSumsRow, error)
+	ListAccounts(ctx context.Context, arg store.ListAccountsParams) ([]store.ListAccountsRow, error)
 	ListTransactionYears(ctx context.Context, userID uuid.UUID) ([]int32, error)
 	CashFlowCategoryMonthly(ctx context.Context, arg store.CashFlowCategoryMonthlyParams) ([]store.CashFlowCategoryMonthlyRow, error)
 	CashFlowAccountOpeningBalances(ctx context.Context, arg store.CashFlowAccountOpeningBalancesParams) ([]store.CashFlowAccountOpeningBalancesRow, error)
 	CashFlowAccountMonthlyChanges(ctx context.Context, arg store.CashFlowAccountMonthlyChangesParams) ([]store.CashFlowAccountMonthlyChangesRow, error)
+	ListForeignCurrencyFlows(ctx context.Context, arg store.ListForeignCurrencyFlowsParams) ([]store.ListForeignCurrencyFlowsRow, error)
+	GetUserByID(ctx context.Context, id uuid.UUID) (store.User, error)
+	GetAccessibleAccount(ctx context.Context, arg store.GetAccessibleAccountParams) (store.GetAccessibleAccountRow, error)
+	ListAccountGroups(ctx context.Context, userID uuid.UUID) ([]store.AccountGroup, error)
 }
 
 type Report struct {
-	queries reportStore
+	queries    reportStore
+	rates      *RateResolver
+	currencies *CurrencyPrecision
 }
 
-func NewReport(queries *store.Queries) *Report {
-	return &Report{queries: queries}
+func NewReport(queries *store.Queries, rates *RateResolver, currencies *CurrencyPrecision) *Report {
+	return &Report{queries: queries, rates: rates, currencies: currencies}
+}
+
+// basePrecision returns the decimals of the user's base currency, used for
+// totals that add up amounts across currencies.
+func (s *Report) basePrecision(ctx context.Context, userID uuid.UUID) (int32, error) {
+	user, err := s.queries.GetUserByID(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	prec, err := s.currencies.Load(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return prec.Of(user.BaseCurrency), nil
 }
 
 func parseDateRange(dateFrom, dateTo string) (pgtype.Date, pgtype.Date, error) {
@@ -59,13 +80,18 @@ func (s *Report) Spending(ctx context.Context, userID uuid.UUID, dateFrom, dateT
 		return nil, err
 	}
 
+	decimals, err := s.basePrecision(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
 	result := make([]dto.SpendingByCategoryItem, 0, len(rows))
 	for _, r := range rows {
 		result = append(result, dto.SpendingByCategoryItem{
 			CategoryID:   r.CategoryID,
 			CategoryName: r.CategoryName,
 			ParentID:     nullableToUUID(r.ParentID),
-			Total:        numericToString(r.Total),
+			Total:        formatAmount(r.Total, decimals),
 		})
 	}
 	return result, nil
@@ -86,26 +112,42 @@ func (s *Report) IncomeExpense(ctx context.Context, userID uuid.UUID, dateFrom,
 		return nil, err
 	}
 
+	decimals, err := s.basePrecision(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
 	result := make([]dto.MonthlyIncomeExpenseItem, 0, len(rows))
 	for _, r := range rows {
 		result = append(result, dto.MonthlyIncomeExpenseItem{
 			Month:   dateToString(r.Month),
-			Income:  numericToString(r.Income),
-			Expense: numericToString(r.Expense),
+			Income:  formatAmount(r.Income, decimals),
+			Expense: formatAmount(r.Expense, decimals),
 		})
 	}
 	return result, nil
 }
 
+// BalanceHistory returns the daily balance of an account the user owns or
+// shares through a household.
 func (s *Report) BalanceHistory(ctx context.Context, userID, accountID uuid.UUID, dateFrom, dateTo string) ([]dto.BalanceHistoryItem, error) {
 	df, dt, err := parseDateRange(dateFrom, dateTo)
 	if err != nil {
 		return nil, err
 	}
 
+	row, err := s.queries.GetAccessibleAccount(ctx, store.GetAccessibleAccountParams{ID: accountID, UserID: userID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return []dto.BalanceHistoryItem{}, nil
+		}
+		return nil, err
+	}
+	acct := row.Account
+
 	rows, err := s.queries.BalanceHistory(ctx, store.BalanceHistoryParams{
 		AccountID: accountID,
-		UserID:    userID,
+		UserID:    acct.UserID,
 		DateFrom:  df,
 		DateTo:    dt,
 	})
@@ -113,11 +155,17 @@ func (s *Report) BalanceHistory(ctx context.Context, userID, accountID uuid.UUID
 		return nil, err
 	}
 
+	prec, err := s.currencies.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	decimals := prec.Of(acct.Currency)
+
 	result := make([]dto.BalanceHistoryItem, 0, len(rows))
 	for _, r := range rows {
 		result = append(result, dto.BalanceHistoryItem{
 			Date:    dateToString(r.Date),
-			Balance: numericToString(r.Balance),
+			Balance: formatAmount(r.Balance, decimals),
 		})
 	}
 	return result, nil
@@ -138,27 +186,68 @@ func (s *Report) Summary(ctx context.Context, userID uuid.UUID, dateFrom, dateTo
 		return nil, err
 	}
 
-	accounts, err := s.queries.ListAccounts(ctx, userID)
+	accounts, err := s.queries.ListAccounts(ctx, store.ListAccountsParams{UserID: userID, IncludeArchived: true})
 	if err != nil {
 		return nil, err
 	}
 
+	user, err := s.queries.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	prec, err := s.currencies.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	baseDecimals := prec.Of(user.BaseCurrency)
+
 	acctResponses := make([]dto.AccountResponse, 0, len(accounts))
+	balances := make([]Money, 0, len(accounts))
 	for _, a := range accounts {
-		sums, err := s.queries.GetAccountTransactionSums(ctx, a.ID)
+		// Archived accounts are hidden from the dashboard, but whatever they
+		// still hold counts towards net worth.
+		if !a.ArchivedOn.Valid {
+			acctResponses = append(acctResponses, listAccountToResponse(a, prec))
+		}
+		balance, err := MoneyFromNumeric(a.Balance, a.Currency).Add(MoneyFromNumeric(a.MarketValue, a.Currency))
 		if err != nil {
-			continue
+			return nil, err
 		}
-		acctResponses = append(acctResponses, listAccountToResponse(a, sums))
+		balances = append(balances, balance)
 	}
 
-	netIncome := numericSub(summary.TotalIncome, summary.TotalExpense)
+	// Net worth is what the accounts hold now, whatever the date range, so it
+	// is converted at today's rates rather than at date_to's.
+	now := time.Now()
+	netWorth, unconverted, err := s.rates.Total(ctx, userID, balances, user.BaseCurrency, now)
+	if err != nil {
+		return nil, err
+	}
+
+	groups, err := s.queries.ListAccountGroups(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	groupTotal := []dto.AccountGroupTotal{}
+	if len(groups) > 0 {
+		groupTotal, err = groupTotals(ctx, s.rates, userID, groups, accounts, user.BaseCurrency, now, prec)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	netIncome := numericToDecimal(summary.TotalIncome).Sub(numericToDecimal(summary.TotalExpense))
 
 	return &dto.SummaryResponse{
-		TotalIncome:  numericToString(summary.TotalIncome),
-		TotalExpense: numericToString(summary.TotalExpense),
-		NetIncome:    numericToString(netIncome),
-		Accounts:     acctResponses,
+		TotalIncome:           formatAmount(summary.TotalIncome, baseDecimals),
+		TotalExpense:          formatAmount(summary.TotalExpense, baseDecimals),
+		NetIncome:             netIncome.StringFixed(baseDecimals),
+		BaseCurrency:          user.BaseCurrency,
+		NetWorth:              netWorth.Format(prec),
+		UnconvertedCurrencies: unconverted,
+		Groups:                groupTotal,
+		Accounts:              acctResponses,
 	}, nil
 }
 
@@ -204,6 +293,11 @@ func (s *Report) CashFlow(ctx context.Context, userID uuid.UUID, year int) (*dto
 		return nil, err
 	}
 
+	prec, err := s.currencies.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	categoryItems := make([]dto.CashFlowCategoryItem, 0, len(categoryRows))
 	for _, r := range categoryRows {
 		categoryItems = append(categoryItems, dto.CashFlowCategoryItem{
@@ -211,7 +305,7 @@ func (s *Report) CashFlow(ctx context.Context, userID uuid.UUID, year int) (*dto
 			Type:       r.Type,
 			Month:      dateToString(r.Month),
 			Currency:   r.Currency,
-			Amount:     numericToString(r.Amount),
+			Amount:     prec.Format(r.Amount, r.Currency),
 		})
 	}
 
@@ -220,7 +314,7 @@ func (s *Report) CashFlow(ctx context.Context, userID uuid.UUID, year int) (*dto
 		openingItems = append(openingItems, dto.CashFlowAccountOpening{
 			AccountID:      r.AccountID,
 			Currency:       r.Currency,
-			OpeningBalance: numericToString(r.OpeningBalance),
+			OpeningBalance: prec.Format(r.OpeningBalance, r.Currency),
 		})
 	}
 
@@ -230,7 +324,7 @@ func (s *Report) CashFlow(ctx context.Context, userID uuid.UUID, year int) (*dto
 			AccountID: r.AccountID,
 			Currency:  r.Currency,
 			Month:     dateToString(r.Month),
-			NetChange: numericToString(r.NetChange),
+			NetChange: prec.Format(r.NetChange, r.Currency),
 		})
 	}
 
@@ -0,0 +1,287 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/dto"
+	"github.com/sanches/finance-tracker-cc/backend/internal/priceapi"
+	"github.com/sanches/finance-tracker-cc/backend/internal/store"
+)
+
+var (
+	ErrSecurityExists    = errors.New("security with this symbol already exists")
+	ErrSecurityNotFound  = errors.New("security not found")
+	ErrSecurityInUse     = errors.New("security has trades")
+	ErrInvalidSecurity   = errors.New("currency must be a known currency code")
+	ErrInvalidPrice      = errors.New("price must be a positive number")
+	ErrPriceSyncDisabled = errors.New("price sync is disabled; set PRICE_PROVIDER")
+)
+
+// priceSourceManual is the source of prices entered through SetPrice.
+const priceSourceManual = "manual"
+
+type securityStore interface {
+	CreateSecurity(ctx context.Context, arg store.CreateSecurityParams) (store.Security, error)
+	ListSecurities(ctx context.Context, userID uuid.UUID) ([]store.ListSecuritiesRow, error)
+	GetSecurity(ctx context.Context, arg store.GetSecurityParams) (store.Security, error)
+	UpdateSecurity(ctx context.Context, arg store.UpdateSecurityParams) (store.Security, error)
+	DeleteSecurity(ctx context.Context, arg store.DeleteSecurityParams) (int64, error)
+	HasSecurityTrades(ctx context.Context, securityID uuid.UUID) (bool, error)
+	ListSecurityPrices(ctx context.Context, arg store.ListSecurityPricesParams) ([]store.SecurityPrice, error)
+	UpsertSecurityPrice(ctx context.Context, arg store.UpsertSecurityPriceParams) (store.SecurityPrice, error)
+}
+
+// Security manages the securities a user trades and their price history.
+// Prices are entered by hand or pulled from provider; a nil provider
+// disables SyncPrices.
+type Security struct {
+	queries    securityStore
+	provider   priceapi.Provider
+	currencies *CurrencyPrecision
+}
+
+func NewSecurity(queries *store.Queries, provider priceapi.Provider, currencies *CurrencyPrecision) *Security {
+	return &Security{queries: queries, provider: provider, currencies: currencies}
+}
+
+func (s *Security) Create(ctx context.Context, userID uuid.UUID, req dto.CreateSecurityRequest) (*dto.SecurityResponse, error) {
+	prec, err := s.currencies.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	currency := strings.ToUpper(req.Currency)
+	if _, ok := prec[currency]; !ok {
+		return nil, ErrInvalidSecurity
+	}
+
+	sec, err := s.queries.CreateSecurity(ctx, store.CreateSecurityParams{
+		UserID:   userID,
+		Symbol:   normalizeSymbol(req.Symbol),
+		Name:     req.Name,
+		Currency: currency,
+	})
+	if err != nil {
+		if isDuplicateKey(err) {
+			return nil, ErrSecurityExists
+		}
+		return nil, err
+	}
+	return &dto.SecurityResponse{
+		ID:        sec.ID,
+		Symbol:    sec.Symbol,
+		Name:      sec.Name,
+		Currency:  sec.Currency,
+		CreatedAt: sec.CreatedAt.Time,
+	}, nil
+}
+
+// List returns the user's securities with their latest known price.
+func (s *Security) List(ctx context.Context, userID uuid.UUID) ([]dto.SecurityResponse, error) {
+	securities, err := s.queries.ListSecurities(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]dto.SecurityResponse, 0, len(securities))
+	for _, sec := range securities {
+		result = append(result, securityToResponse(sec))
+	}
+	return result, nil
+}
+
+func (s *Security) Update(ctx context.Context, userID, id uuid.UUID, req dto.UpdateSecurityRequest) (*dto.SecurityResponse, error) {
+	sec, err := s.queries.UpdateSecurity(ctx, store.UpdateSecurityParams{
+		ID:     id,
+		UserID: userID,
+		Symbol: normalizeSymbol(req.Symbol),
+		Name:   req.Name,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrSecurityNotFound
+		}
+		if isDuplicateKey(err) {
+			return nil, ErrSecurityExists
+		}
+		return nil, err
+	}
+	return &dto.SecurityResponse{
+		ID:        sec.ID,
+		Symbol:    sec.Symbol,
+		Name:      sec.Name,
+		Currency:  sec.Currency,
+		CreatedAt: sec.CreatedAt.Time,
+	}, nil
+}
+
+// Delete removes a security and its prices. Securities that still have
+// trades are kept, since the trades' cash transactions refer to them.
+func (s *Security) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	if _, err := s.get(ctx, userID, id); err != nil {
+		return err
+	}
+	used, err := s.queries.HasSecurityTrades(ctx, id)
+	if err != nil {
+		return err
+	}
+	if used {
+		return ErrSecurityInUse
+	}
+	n, err := s.queries.DeleteSecurity(ctx, store.DeleteSecurityParams{ID: id, UserID: userID})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrSecurityNotFound
+	}
+	return nil
+}
+
+// ListPrices returns the stored prices of a security, newest first.
+func (s *Security) ListPrices(ctx context.Context, userID, id uuid.UUID) ([]dto.SecurityPriceResponse, error) {
+	if _, err := s.get(ctx, userID, id); err != nil {
+		return nil, err
+	}
+	prices, err := s.queries.ListSecurityPrices(ctx, store.ListSecurityPricesParams{SecurityID: id, UserID: userID})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]dto.SecurityPriceResponse, 0, len(prices))
+	for _, p := range prices {
+		result = append(result, securityPriceToResponse(p))
+	}
+	return result, nil
+}
+
+// SetPrice records the price of a security on a date, replacing whatever
+// was stored for that date.
+func (s *Security) SetPrice(ctx context.Context, userID, id uuid.UUID, req dto.SetSecurityPriceRequest) (*dto.SecurityPriceResponse, error) {
+	if _, err := s.get(ctx, userID, id); err != nil {
+		return nil, err
+	}
+	price, err := decimal.NewFromString(req.Price)
+	if err != nil || !price.IsPositive() {
+		return nil, ErrInvalidPrice
+	}
+	date, err := dateFromString(req.Date)
+	if err != nil {
+		return nil, err
+	}
+	p, err := s.queries.UpsertSecurityPrice(ctx, store.UpsertSecurityPriceParams{
+		SecurityID: id,
+		Date:       date,
+		Price:      numericFromDecimal(price),
+		Source:     priceSourceManual,
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp := securityPriceToResponse(p)
+	return &resp, nil
+}
+
+// SyncPrices fetches the latest price of each of the user's securities from
+// the configured provider and stores it under the provider's name. Symbols
+// the provider does not know are reported as missing.
+func (s *Security) SyncPrices(ctx context.Context, userID uuid.UUID) (*dto.PriceSyncResponse, error) {
+	if s.provider == nil {
+		return nil, ErrPriceSyncDisabled
+	}
+	securities, err := s.queries.ListSecurities(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	resp := &dto.PriceSyncResponse{Missing: []string{}}
+	if len(securities) == 0 {
+		return resp, nil
+	}
+
+	ids := make(map[string]uuid.UUID, len(securities))
+	symbols := make([]string, 0, len(securities))
+	for _, sec := range securities {
+		ids[sec.Symbol] = sec.ID
+		symbols = append(symbols, sec.Symbol)
+	}
+	quotes, err := s.provider.FetchPrices(ctx, symbols)
+	if err != nil {
+		return nil, fmt.Errorf("fetch prices from %s: %w", s.provider.Name(), err)
+	}
+
+	found := make(map[string]bool, len(quotes))
+	for _, q := range quotes {
+		id, ok := ids[normalizeSymbol(q.Symbol)]
+		if !ok {
+			continue
+		}
+		price, err := decimal.NewFromString(q.Price)
+		if err != nil || !price.IsPositive() {
+			continue
+		}
+		date, err := dateFromString(q.Date)
+		if err != nil {
+			continue
+		}
+		if _, err := s.queries.UpsertSecurityPrice(ctx, store.UpsertSecurityPriceParams{
+			SecurityID: id,
+			Date:       date,
+			Price:      numericFromDecimal(price),
+			Source:     s.provider.Name(),
+		}); err != nil {
+			return nil, err
+		}
+		found[normalizeSymbol(q.Symbol)] = true
+		resp.Updated++
+	}
+	for _, sym := range symbols {
+		if !found[sym] {
+			resp.Missing = append(resp.Missing, sym)
+		}
+	}
+	return resp, nil
+}
+
+func (s *Security) get(ctx context.Context, userID, id uuid.UUID) (store.Security, error) {
+	sec, err := s.queries.GetSecurity(ctx, store.GetSecurityParams{ID: id, UserID: userID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return store.Security{}, ErrSecurityNotFound
+		}
+		return store.Security{}, err
+	}
+	return sec, nil
+}
+
+func normalizeSymbol(symbol string) string {
+	return strings.ToUpper(strings.TrimSpace(symbol))
+}
+
+func securityToResponse(sec store.ListSecuritiesRow) dto.SecurityResponse {
+	resp := dto.SecurityResponse{
+		ID:        sec.ID,
+		Symbol:    sec.Symbol,
+		Name:      sec.Name,
+		Currency:  sec.Currency,
+		CreatedAt: sec.CreatedAt.Time,
+	}
+	if sec.Price.Valid {
+		price := numericToDecimal(sec.Price).String()
+		date := dateToString(sec.PriceDate)
+		resp.Price = &price
+		resp.PriceDate = &date
+	}
+	return resp
+}
+
+func securityPriceToResponse(p store.SecurityPrice) dto.SecurityPriceResponse {
+	return dto.SecurityPriceResponse{
+		Date:   dateToString(p.Date),
+		Price:  numericToDecimal(p.Price).String(),
+		Source: p.Source,
+	}
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/dto"
+	"github.com/sanches/finance-tracker-cc/backend/internal/priceapi"
+	"github.com/sanches/finance-tracker-cc/backend/internal/store"
+)
+
+type mockSecurityStore struct {
+	createSecurityFn      func(ctx context.Context, arg store.CreateSecurityParams) (store.Security, error)
+	listSecuritiesFn      func(ctx context.Context, userID uuid.UUID) ([]store.ListSecuritiesRow, error)
+	getSecurityFn         func(ctx context.Context, arg store.GetSecurityParams) (store.Security, error)
+	updateSecurityFn      func(ctx context.Context, arg store.UpdateSecurityParams) (store.Security, error)
+	deleteSecurityFn      func(ctx context.Context, arg store.DeleteSecurityParams) (int64, error)
+	hasSecurityTradesFn   func(ctx context.Context, securityID uuid.UUID) (bool, error)
+	listSecurityPricesFn  func(ctx context.Context, arg store.ListSecurityPricesParams) ([]store.SecurityPrice, error)
+	upsertSecurityPriceFn func(ctx context.Context, arg store.UpsertSecurityPriceParams) (store.SecurityPrice, error)
+}
+
+func (m *mockSecurityStore) CreateSecurity(ctx context.Context, arg store.CreateSecurityParams) (store.Security, error) {
+	return m.createSecurityFn(ctx, arg)
+}
+
+func (m *mockSecurityStore) ListSecurities(ctx context.Context, userID uuid.UUID) ([]store.ListSecuritiesRow, error) {
+	return m.listSecuritiesFn(ctx, userID)
+}
+
+func (m *mockSecurityStore) GetSecurity(ctx context.Context, arg store.GetSecurityParams) (store.Security, error) {
+	return m.getSecurityFn(ctx, arg)
+}
+
+func (m *mockSecurityStore) UpdateSecurity(ctx context.Context, arg store.UpdateSecurityParams) (store.Security, error) {
+	return m.updateSecurityFn(ctx, arg)
+}
+
+func (m *mockSecurityStore) DeleteSecurity(ctx context.Context, arg store.DeleteSecurityParams) (int64, error) {
+	return m.deleteSecurityFn(ctx, arg)
+}
+
+func (m *mockSecurityStore) HasSecurityTrades(ctx context.Context, securityID uuid.UUID) (bool, error) {
+	return m.hasSecurityTradesFn(ctx, securityID)
+}
+
+func (m *mockSecurityStore) ListSecurityPrices(ctx context.Context, arg store.ListSecurityPricesParams) ([]store.SecurityPrice, error) {
+	return m.listSecurityPricesFn(ctx, arg)
+}
+
+func (m *mockSecurityStore) UpsertSecurityPrice(ctx context.Context, arg store.UpsertSecurityPriceParams) (store.SecurityPrice, error) {
+	return m.upsertSecurityPriceFn(ctx, arg)
+}
+
+type fakePriceProvider struct {
+	quotes []priceapi.Quote
+}
+
+func (f *fakePriceProvider) Name() string { return "fake" }
+
+func (f *fakePriceProvider) FetchPrices(ctx context.Context, symbols []string) ([]priceapi.Quote, error) {
+	return f.quotes, nil
+}
+
+func TestSecurityCreate(t *testing.T) {
+	var captured store.CreateSecurityParams
+	svc := &Security{queries: &mockSecurityStore{
+		createSecurityFn: func(ctx context.Context, arg store.CreateSecurityParams) (store.Security, error) {
+			captured = arg
+			return store.Security{ID: uuid.New(), Symbol: arg.Symbol, Name: arg.Name, Currency: arg.Currency}, nil
+		},
+	}, currencies: testCurrencyPrecision()}
+	ctx := context.Background()
+
+	resp, err := svc.Create(ctx, uuid.New(), dto.CreateSecurityRequest{Symbol: " vwce.de ", Name: "Vanguard FTSE All-World", Currency: "eur"})
+	require.NoError(t, err)
+	require.Equal(t, "VWCE.DE", captured.Symbol)
+	require.Equal(t, "EUR", resp.Currency)
+
+	_, err = svc.Create(ctx, uuid.New(), dto.CreateSecurityRequest{Symbol: "X", Name: "X", Currency: "XXX"})
+	require.ErrorIs(t, err, ErrInvalidSecurity)
+}
+
+func TestSecurityDelete_InUse(t *testing.T) {
+	id := uuid.New()
+	svc := &Security{queries: &mockSecurityStore{
+		getSecurityFn: func(ctx context.Context, arg store.GetSecurityParams) (store.Security, error) {
+			return store.Security{ID: id}, nil
+		},
+		hasSecurityTradesFn: func(ctx context.Context, securityID uuid.UUID) (bool, error) {
+			return true, nil
+		},
+	}}
+
+	err := svc.Delete(context.Background(), uuid.New(), id)
+	require.ErrorIs(t, err, ErrSecurityInUse)
+}
+
+func TestSecuritySetPrice_Invalid(t *testing.T) {
+	svc := &Security{queries: &mockSecurityStore{
+		getSecurityFn: func(ctx context.Context, arg store.GetSecurityParams) (store.Security, error) {
+			return store.Security{ID: arg.ID}, nil
+		},
+	}}
+
+	for _, price := range []string{"0", "-1", "abc"} {
+		_, err := svc.SetPrice(context.Background(), uuid.New(), uuid.New(), dto.SetSecurityPriceRequest{Date: "2026-03-01", Price: price})
+		require.ErrorIs(t, err, ErrInvalidPrice)
+	}
+}
+
+func TestSecuritySyncPrices(t *testing.T) {
+	userID := uuid.New()
+	vwce := uuid.New()
+	aapl := uuid.New()
+
+	var upserts []store.UpsertSecurityPriceParams
+	mock := &mockSecurityStore{
+		listSecuritiesFn: func(ctx context.Context, uid uuid.UUID) ([]store.ListSecuritiesRow, error) {
+			return []store.ListSecuritiesRow{
+				{ID: vwce, Symbol: "VWCE.DE"},
+				{ID: aapl, Symbol: "AAPL.US"},
+			}, nil
+		},
+		upsertSecurityPriceFn: func(ctx context.Context, arg store.UpsertSecurityPriceParams) (store.SecurityPrice, error) {
+			upserts = append(upserts, arg)
+			return store.SecurityPrice{SecurityID: arg.SecurityID, Date: arg.Date, Price: arg.Price, Source: arg.Source}, nil
+		},
+	}
+
+	t.Run("disabled without a provider", func(t *testing.T) {
+		svc := &Security{queries: mock}
+		_, err := svc.SyncPrices(context.Background(), userID)
+		require.ErrorIs(t, err, ErrPriceSyncDisabled)
+	})
+
+	t.Run("stores quotes under the provider name", func(t *testing.T) {
+		svc := &Security{queries: mock, provider: &fakePriceProvider{quotes: []priceapi.Quote{
+			{Symbol: "vwce.de", Date: "2026-03-02", Price: "120.14"},
+			{Symbol: "UNKNOWN", Date: "2026-03-02", Price: "1"},
+		}}}
+		resp, err := svc.SyncPrices(context.Background(), userID)
+		require.NoError(t, err)
+		require.Equal(t, 1, resp.Updated)
+		require.Equal(t, []string{"AAPL.US"}, resp.Missing)
+
+		require.Len(t, upserts, 1)
+		require.Equal(t, vwce, upserts[0].SecurityID)
+		require.Equal(t, "fake", upserts[0].Source)
+		require.Equal(t, pgtype.Date{Time: day("2026-03-02"), Valid: true}, upserts[0].Date)
+		require.Equal(t, "120.14", numericToDecimal(upserts[0].Price).String())
+	})
+}
@@ -3,12 +3,14 @@ package service
 import (
 	"context"
 	"errors"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
 
 	"github.com/sanches/finance-tracker-cc/backend/internal/dto"
 	"github.com/sanches/finance-tracker-cc/backend/internal/store"
@@ -17,42 +19,64 @@ import (
 type transactionStore interface {
 	CreateTransaction(ctx context.Context, arg store.CreateTransactionParams) (store.Transaction, error)
 	GetTransaction(ctx context.Context, arg store.GetTransactionParams) (store.Transaction, error)
+	GetAccessibleTransaction(ctx context.Context, arg store.GetAccessibleTransactionParams) (store.GetAccessibleTransactionRow, error)
 	ListTransactions(ctx context.Context, arg store.ListTransactionsParams) ([]store.Transaction, error)
 	CountTransactions(ctx context.Context, arg store.CountTransactionsParams) (int64, error)
 	UpdateTransaction(ctx context.Context, arg store.UpdateTransactionParams) (store.Transaction, error)
-	DeleteTransaction(ctx context.Context, arg store.DeleteTransactionParams) error
-	DeleteTransactionByTransferID(ctx context.Context, arg store.DeleteTransactionByTransferIDParams) error
+	TrashTransaction(ctx context.Context, arg store.TrashTransactionParams) error
+	TrashTransfer(ctx context.Context, arg store.TrashTransferParams) error
 	GetTransactionsByTransferID(ctx context.Context, arg store.GetTransactionsByTransferIDParams) ([]store.Transaction, error)
 	UpdateTransferTransaction(ctx context.Context, arg store.UpdateTransferTransactionParams) (store.Transaction, error)
+	SetTransactionStatus(ctx context.Context, arg store.SetTransactionStatusParams) (store.Transaction, error)
 	GetAccount(ctx context.Context, arg store.GetAccountParams) (store.Account, error)
-	ListAccounts(ctx context.Context, userID uuid.UUID) ([]store.ListAccountsRow, error)
+	GetAccessibleAccount(ctx context.Context, arg store.GetAccessibleAccountParams) (store.GetAccessibleAccountRow, error)
+	ListAccounts(ctx context.Context, arg store.ListAccountsParams) ([]store.ListAccountsRow, error)
 	ListTransactionDescriptions(ctx context.Context, arg store.ListTransactionDescriptionsParams) ([]string, error)
+	GetTrashedTransaction(ctx context.Context, arg store.GetTrashedTransactionParams) (store.GetTrashedTransactionRow, error)
+	CreateTransactionVersion(ctx context.Context, arg store.CreateTransactionVersionParams) error
+	ListTransactionVersions(ctx context.Context, arg store.ListTransactionVersionsParams) ([]store.ListTransactionVersionsRow, error)
+	IsTradeTransaction(ctx context.Context, transactionID uuid.UUID) (bool, error)
+	IsLoanPayment(ctx context.Context, paymentID uuid.UUID) (bool, error)
 	WithTx(tx pgx.Tx) *store.Queries
 }
 
 type Transaction struct {
-	queries transactionStore
-	pool    *pgxpool.Pool
+	queries    transactionStore
+	pool       *pgxpool.Pool
+	rates      *RateResolver
+	currencies *CurrencyPrecision
 }
 
-func NewTransaction(queries *store.Queries, pool *pgxpool.Pool) *Transaction {
-	return &Transaction{queries: queries, pool: pool}
+func NewTransaction(queries *store.Queries, pool *pgxpool.Pool, rates *RateResolver, currencies *CurrencyPrecision) *Transaction {
+	return &Transaction{queries: queries, pool: pool, rates: rates, currencies: currencies}
 }
 
+// Create records a transaction in one of the user's accounts or in an
+// account shared with them as a household editor. The transaction belongs
+// to the account's owner; created_by records the user.
 func (s *Transaction) Create(ctx context.Context, userID uuid.UUID, req dto.CreateTransactionRequest) (*dto.TransactionResponse, error) {
 	date, err := dateFromString(req.Date)
 	if err != nil {
 		return nil, errors.New("invalid date format, use YYYY-MM-DD")
 	}
+	acct, err := s.checkAccount(ctx, userID, req.AccountID, req.Amount)
+	if err != nil {
+		return nil, err
+	}
 
-	txn, err := s.queries.CreateTransaction(ctx, store.CreateTransactionParams{
-		UserID:      userID,
-		AccountID:   req.AccountID,
-		CategoryID:  uuidToNullable(req.CategoryID),
-		Type:        req.Type,
-		Amount:      numericFromString(req.Amount),
-		Description: req.Description,
-		Date:        date,
+	var txn store.Transaction
+	err = s.inTx(ctx, func(q transactionStore) error {
+		txn, err = createTransaction(ctx, q, userID, store.CreateTransactionParams{
+			UserID:      acct.UserID,
+			AccountID:   req.AccountID,
+			CategoryID:  uuidToNullable(req.CategoryID),
+			Type:        req.Type,
+			Amount:      numericFromString(req.Amount),
+			Description: req.Description,
+			Date:        date,
+			CreatedBy:   pgtype.UUID{Bytes: userID, Valid: true},
+		})
+		return err
 	})
 	if err != nil {
 		return nil, err
@@ -67,66 +91,54 @@ func (s *Transaction) CreateTransfer(ctx context.Context, userID uuid.UUID, req
 		return nil, errors.New("invalid date format, use YYYY-MM-DD")
 	}
 
-	transferID := uuid.New()
-	amount := numericFromString(req.Amount)
-	toAmount := numericFromString(req.ToAmount)
-	if !toAmount.Valid {
-		toAmount = amount
+	amounts, err := s.transferAmounts(ctx, userID, req.FromAccountID, req.ToAccountID, req.Amount, req.ToAmount, req.ExchangeRate, date, nil)
+	if err != nil {
+		return nil, err
 	}
-	exchangeRate := numericFromString(req.ExchangeRate)
+
+	transferID := uuid.New()
 
 	description := req.Description
 	if description == "" {
 		description = "Transfer"
 	}
 
-	tx, err := s.pool.Begin(ctx)
-	if err != nil {
-		return nil, err
-	}
-	defer func() {
+	var srcTxn, dstTxn store.Transaction
+	err = s.inTx(ctx, func(q transactionStore) error {
+		// Source: expense from source account
+		srcTxn, err = createTransaction(ctx, q, userID, store.CreateTransactionParams{
+			UserID:       userID,
+			AccountID:    req.FromAccountID,
+			Type:         "expense",
+			Amount:       amounts.amount,
+			Description:  description,
+			Date:         date,
+			TransferID:   pgtype.UUID{Bytes: transferID, Valid: true},
+			ExchangeRate: amounts.exchangeRate,
+			CreatedBy:    pgtype.UUID{Bytes: userID, Valid: true},
+		})
 		if err != nil {
-			_ = tx.Rollback(ctx)
+			return err
 		}
-	}()
-
-	q := s.queries.WithTx(tx)
-
-	// Source: expense from source account
-	srcTxn, err := q.CreateTransaction(ctx, store.CreateTransactionParams{
-		UserID:       userID,
-		AccountID:    req.FromAccountID,
-		Type:         "expense",
-		Amount:       amount,
-		Description:  description,
-		Date:         date,
-		TransferID:   pgtype.UUID{Bytes: transferID, Valid: true},
-		ExchangeRate: exchangeRate,
-	})
-	if err != nil {
-		return nil, err
-	}
 
-	// Destination: income to destination account
-	dstTxn, err := q.CreateTransaction(ctx, store.CreateTransactionParams{
-		UserID:       userID,
-		AccountID:    req.ToAccountID,
-		Type:         "income",
-		Amount:       toAmount,
-		Description:  description,
-		Date:         date,
-		TransferID:   pgtype.UUID{Bytes: transferID, Valid: true},
-		ExchangeRate: exchangeRate,
+		// Destination: income to destination account
+		dstTxn, err = createTransaction(ctx, q, userID, store.CreateTransactionParams{
+			UserID:       userID,
+			AccountID:    req.ToAccountID,
+			Type:         "income",
+			Amount:       amounts.toAmount,
+			Description:  description,
+			Date:         date,
+			TransferID:   pgtype.UUID{Bytes: transferID, Valid: true},
+			ExchangeRate: amounts.exchangeRate,
+			CreatedBy:    pgtype.UUID{Bytes: userID, Valid: true},
+		})
+		return err
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	err = tx.Commit(ctx)
-	if err != nil {
-		return nil, err
-	}
-
 	return []dto.TransactionResponse{
 		*s.toResponse(ctx, srcTxn),
 		*s.toResponse(ctx, dstTxn),
@@ -154,6 +166,10 @@ func (s *Transaction) List(ctx context.Context, userID uuid.UUID, params ListTra
 	if categoryIDs == nil {
 		categoryIDs = []uuid.UUID{}
 	}
+	groupIDs := params.GroupIDs
+	if groupIDs == nil {
+		groupIDs = []uuid.UUID{}
+	}
 	var txnType pgtype.Text
 	if params.Type != "" {
 		txnType = pgtype.Text{String: params.Type, Valid: true}
@@ -162,6 +178,10 @@ func (s *Transaction) List(ctx context.Context, userID uuid.UUID, params ListTra
 	if params.Description != "" {
 		description = pgtype.Text{String: params.Description, Valid: true}
 	}
+	var status pgtype.Text
+	if params.Status != "" {
+		status = pgtype.Text{String: params.Status, Valid: true}
+	}
 	var dateFrom, dateTo pgtype.Date
 	monthStart, monthEnd := currentMonthBounds()
 	if params.DateFrom != "" {
@@ -189,6 +209,8 @@ func (s *Transaction) List(ctx context.Context, userID uuid.UUID, params ListTra
 		DateFrom:    dateFrom,
 		DateTo:      dateTo,
 		Description: description,
+		Status:      status,
+		GroupIds:    groupIDs,
 		Off:         offset,
 		Lim:         int32(params.PerPage),
 	}
@@ -206,12 +228,14 @@ func (s *Transaction) List(ctx context.Context, userID uuid.UUID, params ListTra
 		DateFrom:    dateFrom,
 		DateTo:      dateTo,
 		Description: description,
+		Status:      status,
+		GroupIds:    groupIDs,
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	accounts, err := s.queries.ListAccounts(ctx, userID)
+	accounts, err := s.queries.ListAccounts(ctx, store.ListAccountsParams{UserID: userID, IncludeArchived: true})
 	if err != nil {
 		return nil, err
 	}
@@ -221,9 +245,14 @@ func (s *Transaction) List(ctx context.Context, userID uuid.UUID, params ListTra
 		accountCurrencies[account.ID] = account.Currency
 	}
 
+	prec, err := s.currencies.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	result := make([]dto.TransactionResponse, 0, len(txns))
 	for _, t := range txns {
-		result = append(result, *s.toResponseWithCurrency(t, accountCurrencies[t.AccountID]))
+		result = append(result, *transactionToResponse(t, accountCurrencies[t.AccountID], prec))
 	}
 
 	return &dto.PaginatedResponse{
@@ -237,14 +266,14 @@ func (s *Transaction) List(ctx context.Context, userID uuid.UUID, params ListTra
 }
 
 func (s *Transaction) Get(ctx context.Context, userID, txnID uuid.UUID) (*dto.TransactionResponse, error) {
-	txn, err := s.queries.GetTransaction(ctx, store.GetTransactionParams{ID: txnID, UserID: userID})
+	row, err := s.queries.GetAccessibleTransaction(ctx, store.GetAccessibleTransactionParams{ID: txnID, UserID: userID})
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrNotFound
 		}
 		return nil, err
 	}
-	return s.toResponse(ctx, txn), nil
+	return s.toResponse(ctx, row.Transaction), nil
 }
 
 func (s *Transaction) Update(ctx context.Context, userID, txnID uuid.UUID, req dto.UpdateTransactionRequest) (*dto.TransactionResponse, error) {
@@ -252,16 +281,44 @@ func (s *Transaction) Update(ctx context.Context, userID, txnID uuid.UUID, req d
 	if err != nil {
 		return nil, errors.New("invalid date format, use YYYY-MM-DD")
 	}
-
-	txn, err := s.queries.UpdateTransaction(ctx, store.UpdateTransactionParams{
-		ID:          txnID,
-		AccountID:   req.AccountID,
-		CategoryID:  uuidToNullable(req.CategoryID),
-		Type:        req.Type,
-		Amount:      numericFromString(req.Amount),
-		Description: req.Description,
-		Date:        date,
-		UserID:      userID,
+	current, err := s.writableTransaction(ctx, userID, txnID)
+	if err != nil {
+		return nil, err
+	}
+	if current.Status == TransactionStatusReconciled {
+		return nil, ErrTransactionReconciled
+	}
+	isTrade, err := s.queries.IsTradeTransaction(ctx, txnID)
+	if err != nil {
+		return nil, err
+	}
+	if isTrade {
+		return nil, ErrTradeTransaction
+	}
+	acct, err := s.checkAccount(ctx, userID, req.AccountID, req.Amount, current.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	if acct.UserID != current.UserID {
+		return nil, ErrOtherOwnerAccount
+	}
+
+	var txn store.Transaction
+	err = s.inTx(ctx, func(q transactionStore) error {
+		txn, err = q.UpdateTransaction(ctx, store.UpdateTransactionParams{
+			ID:          txnID,
+			AccountID:   req.AccountID,
+			CategoryID:  uuidToNullable(req.CategoryID),
+			Type:        req.Type,
+			Amount:      numericFromString(req.Amount),
+			Description: req.Description,
+			Date:        date,
+			UserID:      current.UserID,
+		})
+		if err != nil {
+			return err
+		}
+		return recordVersion(ctx, q, userID, HistoryUpdate, &current, txn)
 	})
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -272,7 +329,176 @@ func (s *Transaction) Update(ctx context.Context, userID, txnID uuid.UUID, req d
 	return s.toResponse(ctx, txn), nil
 }
 
-var ErrNotATransfer = errors.New("transaction is not a transfer")
+// SetStatus marks a transaction pending or cleared. Reconciled transactions
+// are locked; only finishing a reconciliation sets that status.
+func (s *Transaction) SetStatus(ctx context.Context, userID, txnID uuid.UUID, status string) (*dto.TransactionResponse, error) {
+	current, err := s.writableTransaction(ctx, userID, txnID)
+	if err != nil {
+		return nil, err
+	}
+	if current.Status == TransactionStatusReconciled {
+		return nil, ErrTransactionReconciled
+	}
+
+	var txn store.Transaction
+	err = s.inTx(ctx, func(q transactionStore) error {
+		txn, err = q.SetTransactionStatus(ctx, store.SetTransactionStatusParams{
+			ID:     txnID,
+			UserID: current.UserID,
+			Status: status,
+		})
+		if err != nil {
+			return err
+		}
+		return recordVersion(ctx, q, userID, HistoryUpdate, &current, txn)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.toResponse(ctx, txn), nil
+}
+
+const (
+	TransactionStatusPending    = "pending"
+	TransactionStatusCleared    = "cleared"
+	TransactionStatusReconciled = "reconciled"
+)
+
+var (
+	ErrTransactionReconciled = errors.New("transaction is reconciled and can no longer be changed")
+	ErrOtherOwnerAccount     = errors.New("a transaction can only move between accounts of the same owner")
+	ErrTradeTransaction      = errors.New("transaction belongs to a trade; change or delete the trade instead")
+	ErrLoanPaymentSplit      = errors.New("transfer is a split loan payment; only its description can change")
+)
+
+var (
+	ErrNotATransfer            = errors.New("transaction is not a transfer")
+	ErrTransferAccountNotFound = errors.New("transfer account not found")
+	ErrInvalidTransferAmount   = errors.New("amount, to_amount and exchange_rate must be positive numbers")
+	ErrTransferRateRequired    = errors.New("no exchange rate available for the transfer date; provide to_amount or exchange_rate")
+	ErrTransferAmountMismatch  = errors.New("to_amount does not match amount * exchange_rate")
+)
+
+// transferAmountTolerance is the relative difference allowed between to_amount
+// and amount * exchange_rate when a client sends all three. It absorbs the
+// rounding of a rate the client derived from the two amounts.
+var transferAmountTolerance = decimal.New(1, -3) // 0.1%
+
+type transferAmounts struct {
+	amount       pgtype.Numeric
+	toAmount     pgtype.Numeric
+	exchangeRate pgtype.Numeric
+}
+
+// transferAmounts works out both legs of a transfer. Between accounts in the
+// same currency the destination gets to_amount if given, else amount. Across
+// currencies, whichever of to_amount and exchange_rate is missing is derived
+// from the other; if both are missing the rate for the transfer date is looked
+// up. Both given must agree within transferAmountTolerance.
+func (s *Transaction) transferAmounts(ctx context.Context, userID, fromAccountID, toAccountID uuid.UUID, amountStr, toAmountStr, rateStr string, date pgtype.Date, current []uuid.UUID) (*transferAmounts, error) {
+	fromAcct, err := s.queries.GetAccount(ctx, store.GetAccountParams{ID: fromAccountID, UserID: userID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrTransferAccountNotFound
+		}
+		return nil, err
+	}
+	toAcct, err := s.queries.GetAccount(ctx, store.GetAccountParams{ID: toAccountID, UserID: userID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrTransferAccountNotFound
+		}
+		return nil, err
+	}
+	if err := checkArchived(fromAcct, current); err != nil {
+		return nil, err
+	}
+	if err := checkArchived(toAcct, current); err != nil {
+		return nil, err
+	}
+
+	prec, err := s.currencies.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := prec.Check(amountStr, fromAcct.Currency); err != nil {
+		return nil, err
+	}
+	if err := prec.Check(toAmountStr, toAcct.Currency); err != nil {
+		return nil, err
+	}
+	toDecimals := prec.Of(toAcct.Currency)
+
+	amount, err := parsePositiveDecimal(amountStr)
+	if err != nil || amount.IsZero() {
+		return nil, ErrInvalidTransferAmount
+	}
+	toAmount, err := parsePositiveDecimal(toAmountStr)
+	if err != nil {
+		return nil, ErrInvalidTransferAmount
+	}
+	rate, err := parsePositiveDecimal(rateStr)
+	if err != nil {
+		return nil, ErrInvalidTransferAmount
+	}
+
+	if fromAcct.Currency == toAcct.Currency {
+		result := &transferAmounts{
+			amount:       numericFromString(amountStr),
+			toAmount:     numericFromString(toAmountStr),
+			exchangeRate: numericFromString(rateStr),
+		}
+		if toAmount.IsZero() {
+			result.toAmount = result.amount
+		}
+		return result, nil
+	}
+
+	switch {
+	case !toAmount.IsZero() && !rate.IsZero():
+		diff := amount.Mul(rate).Sub(toAmount).Abs()
+		allowed := decimal.Max(decimal.New(1, -toDecimals), toAmount.Mul(transferAmountTolerance))
+		if diff.GreaterThan(allowed) {
+			return nil, ErrTransferAmountMismatch
+		}
+	case !toAmount.IsZero():
+		rate = toAmount.DivRound(amount, 8)
+	case !rate.IsZero():
+		toAmount = amount.Mul(rate).Round(toDecimals)
+	default:
+		resolved, err := s.rates.Resolve(ctx, userID, fromAcct.Currency, toAcct.Currency, date.Time)
+		if err != nil {
+			if errors.Is(err, ErrRateNotFound) {
+				return nil, ErrTransferRateRequired
+			}
+			return nil, err
+		}
+		rate = resolved.Rate.Round(8)
+		toAmount = amount.Mul(rate).Round(toDecimals)
+	}
+
+	return &transferAmounts{
+		amount:       numericFromDecimal(amount),
+		toAmount:     numericFromDecimal(toAmount),
+		exchangeRate: numericFromDecimal(rate),
+	}, nil
+}
+
+// parsePositiveDecimal parses an optional decimal string: empty yields zero,
+// anything negative or unparseable is an error.
+func parsePositiveDecimal(s string) (decimal.Decimal, error) {
+	if s == "" {
+		return decimal.Zero, nil
+	}
+	d, err := decimal.NewFromString(s)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	if d.IsNegative() {
+		return decimal.Zero, ErrInvalidTransferAmount
+	}
+	return d, nil
+}
 
 func (s *Transaction) UpdateTransfer(ctx context.Context, userID, txnID uuid.UUID, req dto.UpdateTransferRequest) ([]dto.TransactionResponse, error) {
 	date, err := dateFromString(req.Date)
@@ -304,6 +530,9 @@ func (s *Transaction) UpdateTransfer(ctx context.Context, userID, txnID uuid.UUI
 	if len(legs) != 2 {
 		return nil, errors.New("transfer is corrupted: expected 2 transactions")
 	}
+	if anyReconciled(legs) {
+		return nil, ErrTransactionReconciled
+	}
 
 	// Identify source (expense) and destination (income)
 	var srcIdx, dstIdx int
@@ -313,61 +542,69 @@ func (s *Transaction) UpdateTransfer(ctx context.Context, userID, txnID uuid.UUI
 		srcIdx, dstIdx = 1, 0
 	}
 
-	amount := numericFromString(req.Amount)
-	toAmount := numericFromString(req.ToAmount)
-	if !toAmount.Valid {
-		toAmount = amount
+	current := []uuid.UUID{legs[0].AccountID, legs[1].AccountID}
+	amounts, err := s.transferAmounts(ctx, userID, req.FromAccountID, req.ToAccountID, req.Amount, req.ToAmount, req.ExchangeRate, date, current)
+	if err != nil {
+		return nil, err
 	}
-	exchangeRate := numericFromString(req.ExchangeRate)
 
 	description := req.Description
 	if description == "" {
 		description = "Transfer"
 	}
 
-	tx, err := s.pool.Begin(ctx)
-	if err != nil {
-		return nil, err
-	}
-	defer func() {
+	// A split loan payment keeps the principal and interest it was split
+	// into, so those must not drift from the transfer.
+	moved := req.FromAccountID != legs[srcIdx].AccountID || req.ToAccountID != legs[dstIdx].AccountID ||
+		!date.Time.Equal(legs[dstIdx].Date.Time) ||
+		!numericToDecimal(amounts.amount).Equal(numericToDecimal(legs[srcIdx].Amount)) ||
+		!numericToDecimal(amounts.toAmount).Equal(numericToDecimal(legs[dstIdx].Amount))
+
+	var srcTxn, dstTxn store.Transaction
+	err = s.inTx(ctx, func(q transactionStore) error {
+		if moved {
+			split, err := q.IsLoanPayment(ctx, legs[dstIdx].ID)
+			if err != nil {
+				return err
+			}
+			if split {
+				return ErrLoanPaymentSplit
+			}
+		}
+		srcTxn, err = q.UpdateTransferTransaction(ctx, store.UpdateTransferTransactionParams{
+			ID:           legs[srcIdx].ID,
+			AccountID:    req.FromAccountID,
+			Amount:       amounts.amount,
+			Description:  description,
+			Date:         date,
+			ExchangeRate: amounts.exchangeRate,
+			UserID:       userID,
+		})
 		if err != nil {
-			_ = tx.Rollback(ctx)
+			return err
 		}
-	}()
-
-	q := s.queries.WithTx(tx)
-
-	srcTxn, err := q.UpdateTransferTransaction(ctx, store.UpdateTransferTransactionParams{
-		ID:           legs[srcIdx].ID,
-		AccountID:    req.FromAccountID,
-		Amount:       amount,
-		Description:  description,
-		Date:         date,
-		ExchangeRate: exchangeRate,
-		UserID:       userID,
-	})
-	if err != nil {
-		return nil, err
-	}
 
-	dstTxn, err := q.UpdateTransferTransaction(ctx, store.UpdateTransferTransactionParams{
-		ID:           legs[dstIdx].ID,
-		AccountID:    req.ToAccountID,
-		Amount:       toAmount,
-		Description:  description,
-		Date:         date,
-		ExchangeRate: exchangeRate,
-		UserID:       userID,
+		dstTxn, err = q.UpdateTransferTransaction(ctx, store.UpdateTransferTransactionParams{
+			ID:           legs[dstIdx].ID,
+			AccountID:    req.ToAccountID,
+			Amount:       amounts.toAmount,
+			Description:  description,
+			Date:         date,
+			ExchangeRate: amounts.exchangeRate,
+			UserID:       userID,
+		})
+		if err != nil {
+			return err
+		}
+		if err := recordVersion(ctx, q, userID, HistoryUpdate, &legs[srcIdx], srcTxn); err != nil {
+			return err
+		}
+		return recordVersion(ctx, q, userID, HistoryUpdate, &legs[dstIdx], dstTxn)
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	err = tx.Commit(ctx)
-	if err != nil {
-		return nil, err
-	}
-
 	return []dto.TransactionResponse{
 		*s.toResponse(ctx, srcTxn),
 		*s.toResponse(ctx, dstTxn),
@@ -397,7 +634,7 @@ func (s *Transaction) GetTransfer(ctx context.Context, userID, txnID uuid.UUID)
 		return nil, errors.New("transfer is corrupted: expected 2 transactions")
 	}
 
-	accounts, err := s.queries.ListAccounts(ctx, userID)
+	accounts, err := s.queries.ListAccounts(ctx, store.ListAccountsParams{UserID: userID, IncludeArchived: true})
 	if err != nil {
 		return nil, err
 	}
@@ -406,31 +643,122 @@ func (s *Transaction) GetTransfer(ctx context.Context, userID, txnID uuid.UUID)
 		accountCurrencies[a.ID] = a.Currency
 	}
 
+	prec, err := s.currencies.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	result := make([]dto.TransactionResponse, 0, len(legs))
 	for _, t := range legs {
-		result = append(result, *s.toResponseWithCurrency(t, accountCurrencies[t.AccountID]))
+		result = append(result, *transactionToResponse(t, accountCurrencies[t.AccountID], prec))
 	}
 	return result, nil
 }
 
+// Delete moves the transaction to the trash, from where Trash.RestoreTransaction
+// brings it back.
 func (s *Transaction) Delete(ctx context.Context, userID, txnID uuid.UUID) error {
-	// If this is a transfer, delete the linked transaction too
-	txn, err := s.queries.GetTransaction(ctx, store.GetTransactionParams{ID: txnID, UserID: userID})
+	txn, err := s.writableTransaction(ctx, userID, txnID)
+	if err != nil {
+		return err
+	}
+	// Holdings skip trashed transactions, so trashing a trade's cash alone
+	// would quietly drop the trade.
+	isTrade, err := s.queries.IsTradeTransaction(ctx, txnID)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return ErrNotFound
-		}
 		return err
 	}
+	if isTrade {
+		return ErrTradeTransaction
+	}
 
+	// If this is a transfer, delete the linked transaction too. Transfers
+	// stay with the owner of both accounts.
 	if txn.TransferID.Valid {
-		return s.queries.DeleteTransactionByTransferID(ctx, store.DeleteTransactionByTransferIDParams{
+		if txn.UserID != userID {
+			return ErrHouseholdReadOnly
+		}
+		legs, err := s.queries.GetTransactionsByTransferID(ctx, store.GetTransactionsByTransferIDParams{
 			TransferID: txn.TransferID,
 			UserID:     userID,
 		})
+		if err != nil {
+			return err
+		}
+		if anyReconciled(legs) {
+			return ErrTransactionReconciled
+		}
+		return s.inTx(ctx, func(q transactionStore) error {
+			if err := q.TrashTransfer(ctx, store.TrashTransferParams{
+				UserID:     userID,
+				TransferID: txn.TransferID,
+			}); err != nil {
+				return err
+			}
+			for _, l := range legs {
+				if err := recordVersion(ctx, q, userID, HistoryDelete, nil, l); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+	if txn.Status == TransactionStatusReconciled {
+		return ErrTransactionReconciled
+	}
+
+	return s.inTx(ctx, func(q transactionStore) error {
+		if err := q.TrashTransaction(ctx, store.TrashTransactionParams{ID: txnID, UserID: txn.UserID}); err != nil {
+			return err
+		}
+		return recordVersion(ctx, q, userID, HistoryDelete, nil, txn)
+	})
+}
+
+// inTx runs fn with q bound to one database transaction, so that a change
+// and its history are written together, and commits if fn succeeds.
+// Without a pool, as in unit tests, fn runs on s.queries directly.
+func (s *Transaction) inTx(ctx context.Context, fn func(q transactionStore) error) error {
+	if s.pool == nil {
+		return fn(s.queries)
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	if err := fn(s.queries.WithTx(tx)); err != nil {
+		return err
 	}
+	return tx.Commit(ctx)
+}
+
+// writableTransaction returns a transaction the user may change: their own,
+// or one in an account shared with them as a household owner or editor.
+func (s *Transaction) writableTransaction(ctx context.Context, userID, txnID uuid.UUID) (store.Transaction, error) {
+	row, err := s.queries.GetAccessibleTransaction(ctx, store.GetAccessibleTransactionParams{ID: txnID, UserID: userID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return store.Transaction{}, ErrNotFound
+		}
+		return store.Transaction{}, err
+	}
+	if row.Role == RoleViewer {
+		return store.Transaction{}, ErrHouseholdReadOnly
+	}
+	return row.Transaction, nil
+}
 
-	return s.queries.DeleteTransaction(ctx, store.DeleteTransactionParams{ID: txnID, UserID: userID})
+// anyReconciled reports whether a transfer has a leg that is locked by a
+// finished reconciliation.
+func anyReconciled(legs []store.Transaction) bool {
+	for _, l := range legs {
+		if l.Status == TransactionStatusReconciled {
+			return true
+		}
+	}
+	return false
 }
 
 func (s *Transaction) ListDescriptions(ctx context.Context, userID uuid.UUID, search string) ([]string, error) {
@@ -447,6 +775,41 @@ func (s *Transaction) ListDescriptions(ctx context.Context, userID uuid.UUID, se
 	return descriptions, nil
 }
 
+// checkAccount returns the account a transaction goes into. It returns
+// ErrHouseholdReadOnly if the account is shared with the user as a viewer,
+// ErrAmountPrecision if amount has more decimal places than the currency of
+// the account allows, and ErrAccountArchived if the account is archived and
+// not among the accounts the transaction already uses.
+func (s *Transaction) checkAccount(ctx context.Context, userID, accountID uuid.UUID, amount string, current ...uuid.UUID) (store.Account, error) {
+	row, err := s.queries.GetAccessibleAccount(ctx, store.GetAccessibleAccountParams{ID: accountID, UserID: userID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return store.Account{}, ErrAccountNotFound
+		}
+		return store.Account{}, err
+	}
+	if row.Role == RoleViewer {
+		return store.Account{}, ErrHouseholdReadOnly
+	}
+	if err := checkArchived(row.Account, current); err != nil {
+		return store.Account{}, err
+	}
+	prec, err := s.currencies.Load(ctx)
+	if err != nil {
+		return store.Account{}, err
+	}
+	return row.Account, prec.Check(amount, row.Account.Currency)
+}
+
+// checkArchived rejects an archived account unless it is in current, so
+// existing transactions can still be edited in place.
+func checkArchived(acct store.Account, current []uuid.UUID) error {
+	if !acct.ArchivedOn.Valid || slices.Contains(current, acct.ID) {
+		return nil
+	}
+	return ErrAccountArchived
+}
+
 func (s *Transaction) toResponse(ctx context.Context, t store.Transaction) *dto.TransactionResponse {
 	// Look up currency from account
 	currency := ""
@@ -454,27 +817,35 @@ func (s *Transaction) toResponse(ctx context.Context, t store.Transaction) *dto.
 	if err == nil {
 		currency = acct.Currency
 	}
+	// A failed lookup falls back to the default precision.
+	prec, _ := s.currencies.Load(ctx)
 
-	return s.toResponseWithCurrency(t, currency)
+	return transactionToResponse(t, currency, prec)
 }
 
-func (s *Transaction) toResponseWithCurrency(t store.Transaction, currency string) *dto.TransactionResponse {
+func transactionToResponse(t store.Transaction, currency string, prec Precisions) *dto.TransactionResponse {
 	resp := &dto.TransactionResponse{
 		ID:          t.ID,
 		AccountID:   t.AccountID,
 		CategoryID:  nullableToUUID(t.CategoryID),
 		Type:        t.Type,
-		Amount:      numericToString(t.Amount),
+		Amount:      prec.Format(t.Amount, currency),
 		Currency:    currency,
 		Description: t.Description,
 		Date:        dateToString(t.Date),
 		TransferID:  nullableToUUID(t.TransferID),
+		Status:      t.Status,
+		CreatedBy:   t.UserID,
 		CreatedAt:   t.CreatedAt.Time,
 		UpdatedAt:   t.UpdatedAt.Time,
+		DeletedAt:   deletedAt(t.DeletedAt),
 	}
 
+	if t.CreatedBy.Valid {
+		resp.CreatedBy = t.CreatedBy.Bytes
+	}
 	if t.ExchangeRate.Valid {
-		rate := numericToString(t.ExchangeRate)
+		rate := formatRate(numericToDecimal(t.ExchangeRate))
 		resp.ExchangeRate = &rate
 	}
 
@@ -492,10 +863,12 @@ func currentMonthBounds() (pgtype.Date, pgtype.Date) {
 type ListTransactionsParams struct {
 	AccountIDs  []uuid.UUID
 	CategoryIDs []uuid.UUID
+	GroupIDs    []uuid.UUID // accounts in any of these groups
 	Type        string
 	DateFrom    string
 	DateTo      string
 	Description string
+	Status      string
 	Page        int
 	PerPage     int
 }
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/dto"
+	"github.com/sanches/finance-tracker-cc/backend/internal/store"
+)
+
+// Transaction history actions.
+const (
+	HistoryCreate  = "create"
+	HistoryUpdate  = "update"
+	HistoryDelete  = "delete"
+	HistoryRestore = "restore"
+)
+
+// historyFields are the fields a version diffs, in the order its changes
+// are listed.
+var historyFields = []string{"account_id", "category_id", "type", "amount", "description", "date", "status", "exchange_rate"}
+
+type versionStore interface {
+	CreateTransactionVersion(ctx context.Context, arg store.CreateTransactionVersionParams) error
+}
+
+type transactionCreator interface {
+	versionStore
+	CreateTransaction(ctx context.Context, arg store.CreateTransactionParams) (store.Transaction, error)
+}
+
+type bulkVersionStore interface {
+	BulkCreateTransactionVersions(ctx context.Context, arg []store.BulkCreateTransactionVersionsParams) (int64, error)
+}
+
+// History returns the changes made to a transaction, oldest first; for a
+// transfer it covers both legs. Anyone who can see the transaction can see
+// its history, and its owner still can once it is in the trash.
+func (s *Transaction) History(ctx context.Context, userID, txnID uuid.UUID) ([]dto.TransactionVersionResponse, error) {
+	var txn store.Transaction
+	row, err := s.queries.GetAccessibleTransaction(ctx, store.GetAccessibleTransactionParams{ID: txnID, UserID: userID})
+	switch {
+	case err == nil:
+		txn = row.Transaction
+	case errors.Is(err, pgx.ErrNoRows):
+		trashed, err := s.queries.GetTrashedTransaction(ctx, store.GetTrashedTransactionParams{ID: txnID, UserID: userID})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, ErrNotFound
+			}
+			return nil, err
+		}
+		txn = trashed.Transaction
+	default:
+		return nil, err
+	}
+
+	versions, err := s.queries.ListTransactionVersions(ctx, store.ListTransactionVersionsParams{
+		TransactionID: txn.ID,
+		TransferID:    txn.TransferID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.TransactionVersionResponse, 0, len(versions))
+	for _, v := range versions {
+		changes := []dto.FieldChange{}
+		if err := json.Unmarshal(v.Changes, &changes); err != nil {
+			return nil, err
+		}
+		result = append(result, dto.TransactionVersionResponse{
+			ID:            v.ID,
+			TransactionID: v.TransactionID,
+			Action:        v.Action,
+			ActorID:       v.ActorID,
+			ActorUsername: v.ActorUsername,
+			Changes:       changes,
+			CreatedAt:     v.CreatedAt.Time,
+		})
+	}
+	return result, nil
+}
+
+// recordVersion adds a change to the history of after. before is nil on
+// create; an update that changed nothing is not recorded. q should be bound
+// to the database transaction making the change, so that a failed write
+// aborts the change with it.
+func recordVersion(ctx context.Context, q versionStore, actorID uuid.UUID, action string, before *store.Transaction, after store.Transaction) error {
+	changes := []dto.FieldChange{}
+	if action != HistoryDelete {
+		changes = diffTransactions(before, after)
+		if len(changes) == 0 {
+			return nil
+		}
+	}
+	data, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+	return q.CreateTransactionVersion(ctx, store.CreateTransactionVersionParams{
+		TransactionID: after.ID,
+		ActorID:       actorID,
+		Action:        action,
+		Changes:       data,
+	})
+}
+
+// createTransaction inserts a transaction along with its create version.
+// Every service that creates transactions goes through it, so none is left
+// without history.
+func createTransaction(ctx context.Context, q transactionCreator, actorID uuid.UUID, arg store.CreateTransactionParams) (store.Transaction, error) {
+	t, err := q.CreateTransaction(ctx, arg)
+	if err != nil {
+		return store.Transaction{}, err
+	}
+	if err := recordVersion(ctx, q, actorID, HistoryCreate, nil, t); err != nil {
+		return store.Transaction{}, err
+	}
+	return t, nil
+}
+
+// recordCreated adds the create versions of transactions inserted in bulk
+// by an import.
+func recordCreated(ctx context.Context, q bulkVersionStore, actorID uuid.UUID, txns []store.Transaction) error {
+	params := make([]store.BulkCreateTransactionVersionsParams, 0, len(txns))
+	for _, t := range txns {
+		data, err := json.Marshal(diffTransactions(nil, t))
+		if err != nil {
+			return err
+		}
+		params = append(params, store.BulkCreateTransactionVersionsParams{
+			TransactionID: t.ID,
+			ActorID:       actorID,
+			Action:        HistoryCreate,
+			Changes:       data,
+		})
+	}
+	_, err := q.BulkCreateTransactionVersions(ctx, params)
+	return err
+}
+
+// diffTransactions lists the tracked fields that differ between before and
+// after. With no before, every field that is set counts as changed.
+func diffTransactions(before *store.Transaction, after store.Transaction) []dto.FieldChange {
+	old := map[string]*string{}
+	if before != nil {
+		old = historyValues(*before)
+	}
+	cur := historyValues(after)
+
+	var changes []dto.FieldChange
+	for _, f := range historyFields {
+		o, n := old[f], cur[f]
+		if o == nil && n == nil || o != nil && n != nil && *o == *n {
+			continue
+		}
+		changes = append(changes, dto.FieldChange{Field: f, Old: o, New: n})
+	}
+	return changes
+}
+
+// historyValues formats the tracked fields of t. Amounts and rates keep all
+// their digits so that no change is rounded away; an unset category or
+// exchange rate is left out.
+func historyValues(t store.Transaction) map[string]*string {
+	str := func(s string) *string { return &s }
+	v := map[string]*string{
+		"account_id":  str(t.AccountID.String()),
+		"type":        str(t.Type),
+		"amount":      str(numericToDecimal(t.Amount).String()),
+		"description": str(t.Description),
+		"date":        str(dateToString(t.Date)),
+		"status":      str(t.Status),
+	}
+	if id := nullableToUUID(t.CategoryID); id != nil {
+		v["category_id"] = str(id.String())
+	}
+	if t.ExchangeRate.Valid {
+		v["exchange_rate"] = str(numericToDecimal(t.ExchangeRate).String())
+	}
+	return v
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/dto"
+	"github.com/sanches/finance-tracker-cc/backend/internal/store"
+)
+
+func TestDiffTransactions(t *testing.T) {
+	categoryID := uuid.New()
+	before := store.Transaction{
+		ID:          uuid.New(),
+		AccountID:   uuid.New(),
+		Type:        "expense",
+		Amount:      numericFromString("10.00"),
+		Description: "Groceries",
+		Status:      TransactionStatusPending,
+	}
+
+	created := diffTransactions(nil, before)
+	require.Len(t, created, 6, "every field that is set")
+	require.Equal(t, "account_id", created[0].Field)
+	require.Nil(t, created[0].Old)
+
+	after := before
+	after.Amount = numericFromString("12.5")
+	after.CategoryID = pgtype.UUID{Bytes: categoryID, Valid: true}
+	changes := diffTransactions(&before, after)
+	require.Len(t, changes, 2)
+	require.Equal(t, "category_id", changes[0].Field)
+	require.Nil(t, changes[0].Old)
+	require.Equal(t, categoryID.String(), *changes[0].New)
+	require.Equal(t, "amount", changes[1].Field)
+	require.Equal(t, "10", *changes[1].Old)
+	require.Equal(t, "12.5", *changes[1].New)
+
+	require.Empty(t, diffTransactions(&before, before))
+}
+
+func TestTransactionUpdate_RecordsVersion(t *testing.T) {
+	userID := uuid.New()
+	memberID := uuid.New()
+	txnID := uuid.New()
+	accountID := uuid.New()
+	current := store.Transaction{
+		ID:        txnID,
+		UserID:    userID,
+		AccountID: accountID,
+		Type:      "expense",
+		Amount:    numericFromString("10"),
+		Status:    TransactionStatusPending,
+	}
+
+	var recorded []store.CreateTransactionVersionParams
+	mock := &mockTransactionStore{
+		getAccessibleTransactionFn: func(ctx context.Context, arg store.GetAccessibleTransactionParams) (store.GetAccessibleTransactionRow, error) {
+			return store.GetAccessibleTransactionRow{Transaction: current, Role: RoleEditor}, nil
+		},
+		getAccessibleAccountFn: func(ctx context.Context, arg store.GetAccessibleAccountParams) (store.GetAccessibleAccountRow, error) {
+			return store.GetAccessibleAccountRow{Account: store.Account{ID: arg.ID, UserID: userID, Currency: "USD"}, Role: RoleEditor}, nil
+		},
+		getAccountFn: func(ctx context.Context, arg store.GetAccountParams) (store.Account, error) {
+			return store.Account{ID: arg.ID, UserID: userID, Currency: "USD"}, nil
+		},
+		updateTransactionFn: func(ctx context.Context, arg store.UpdateTransactionParams) (store.Transaction, error) {
+			updated := current
+			updated.Amount = arg.Amount
+			updated.Date = arg.Date
+			return updated, nil
+		},
+		createTransactionVersionFn: func(ctx context.Context, arg store.CreateTransactionVersionParams) error {
+			recorded = append(recorded, arg)
+			return nil
+		},
+	}
+	svc := &Transaction{queries: mock}
+
+	req := dto.UpdateTransactionRequest{AccountID: accountID, Type: "expense", Amount: "25", Date: "2025-07-01"}
+	_, err := svc.Update(context.Background(), memberID, txnID, req)
+	require.NoError(t, err)
+	require.Len(t, recorded, 1)
+	require.Equal(t, memberID, recorded[0].ActorID, "the member who made the change")
+	require.Equal(t, HistoryUpdate, recorded[0].Action)
+
+	var changes []dto.FieldChange
+	require.NoError(t, json.Unmarshal(recorded[0].Changes, &changes))
+	require.Len(t, changes, 2)
+	require.Equal(t, "amount", changes[0].Field)
+	require.Equal(t, "25", *changes[0].New)
+	require.Equal(t, "date", changes[1].Field)
+
+	// Saving without a change adds nothing to the history.
+	current.Amount = numericFromString("25")
+	current.Date, _ = dateFromString("2025-07-01")
+	_, err = svc.Update(context.Background(), memberID, txnID, req)
+	require.NoError(t, err)
+	require.Len(t, recorded, 1)
+
+	// A change whose version can't be written fails along with it.
+	current.Amount = numericFromString("10")
+	mock.createTransactionVersionFn = func(ctx context.Context, arg store.CreateTransactionVersionParams) error {
+		return errors.New("connection reset")
+	}
+	_, err = svc.Update(context.Background(), memberID, txnID, req)
+	require.Error(t, err)
+}
+
+func TestTransactionHistory(t *testing.T) {
+	userID := uuid.New()
+	txnID := uuid.New()
+	transferID := pgtype.UUID{Bytes: uuid.New(), Valid: true}
+
+	var listed store.ListTransactionVersionsParams
+	mock := &mockTransactionStore{
+		getAccessibleTransactionFn: func(ctx context.Context, arg store.GetAccessibleTransactionParams) (store.GetAccessibleTransactionRow, error) {
+			return store.GetAccessibleTransactionRow{}, pgx.ErrNoRows
+		},
+		getTrashedTransactionFn: func(ctx context.Context, arg store.GetTrashedTransactionParams) (store.GetTrashedTransactionRow, error) {
+			return store.GetTrashedTransactionRow{Transaction: store.Transaction{ID: arg.ID, TransferID: transferID}}, nil
+		},
+		listTransactionVersionsFn: func(ctx context.Context, arg store.ListTransactionVersionsParams) ([]store.ListTransactionVersionsRow, error) {
+			listed = arg
+			return []store.ListTransactionVersionsRow{
+				{TransactionID: txnID, ActorID: userID, ActorUsername: "alice", Action: HistoryCreate, Changes: []byte(`[{"field":"amount","old":null,"new":"10"}]`)},
+				{TransactionID: txnID, ActorID: userID, ActorUsername: "alice", Action: HistoryDelete, Changes: []byte(`[]`)},
+			}, nil
+		},
+	}
+	svc := &Transaction{queries: mock}
+
+	history, err := svc.History(context.Background(), userID, txnID)
+	require.NoError(t, err, "a trashed transaction keeps its history")
+	require.Equal(t, transferID, listed.TransferID)
+	require.Len(t, history, 2)
+	require.Equal(t, "alice", history[0].ActorUsername)
+	require.Equal(t, "10", *history[0].Changes[0].New)
+	require.NotNil(t, history[1].Changes)
+
+	mock.getTrashedTransactionFn = func(ctx context.Context, arg store.GetTrashedTransactionParams) (store.GetTrashedTransactionRow, error) {
+		return store.GetTrashedTransactionRow{}, pgx.ErrNoRows
+	}
+	_, err = svc.History(context.Background(), userID, txnID)
+	require.ErrorIs(t, err, ErrNotFound)
+}
@@ -15,19 +15,27 @@ import (
 )
 
 type mockTransactionStore struct {
-	createTransactionFn             func(ctx context.Context, arg store.CreateTransactionParams) (store.Transaction, error)
-	getTransactionFn                func(ctx context.Context, arg store.GetTransactionParams) (store.Transaction, error)
-	listTransactionsFn              func(ctx context.Context, arg store.ListTransactionsParams) ([]store.Transaction, error)
-	countTransactionsFn             func(ctx context.Context, arg store.CountTransactionsParams) (int64, error)
-	updateTransactionFn             func(ctx context.Context, arg store.UpdateTransactionParams) (store.Transaction, error)
-	deleteTransactionFn             func(ctx context.Context, arg store.DeleteTransactionParams) error
-	deleteTransactionByTransferIDFn func(ctx context.Context, arg store.DeleteTransactionByTransferIDParams) error
-	getTransactionsByTransferIDFn   func(ctx context.Context, arg store.GetTransactionsByTransferIDParams) ([]store.Transaction, error)
-	updateTransferTransactionFn     func(ctx context.Context, arg store.UpdateTransferTransactionParams) (store.Transaction, error)
-	getAccountFn                    func(ctx context.Context, arg store.GetAccountParams) (store.Account, error)
-	listAccountsFn                  func(ctx context.Context, userID uuid.UUID) ([]store.ListAccountsRow, error)
-	listTransactionDescriptionsFn   func(ctx context.Context, arg store.ListTransactionDescriptionsParams) ([]string, error)
-	withTxFn                        func(tx pgx.Tx) *store.Queries
+	createTransactionFn           func(ctx context.Context, arg store.CreateTransactionParams) (store.Transaction, error)
+	getTransactionFn              func(ctx context.Context, arg store.GetTransactionParams) (store.Transaction, error)
+	getAccessibleTransactionFn    func(ctx context.Context, arg store.GetAccessibleTransactionParams) (store.GetAccessibleTransactionRow, error)
+	listTransactionsFn            func(ctx context.Context, arg store.ListTransactionsParams) ([]store.Transaction, error)
+	countTransactionsFn           func(ctx context.Context, arg store.CountTransactionsParams) (int64, error)
+	updateTransactionFn           func(ctx context.Context, arg store.UpdateTransactionParams) (store.Transaction, error)
+	trashTransactionFn            func(ctx context.Context, arg store.TrashTransactionParams) error
+	trashTransferFn               func(ctx context.Context, arg store.TrashTransferParams) error
+	getTransactionsByTransferIDFn func(ctx context.Context, arg store.GetTransactionsByTransferIDParams) ([]store.Transaction, error)
+	updateTransferTransactionFn   func(ctx context.Context, arg store.UpdateTransferTransactionParams) (store.Transaction, error)
+	setTransactionStatusFn        func(ctx context.Context, arg store.SetTransactionStatusParams) (store.Transaction, error)
+	getAccountFn                  func(ctx context.Context, arg store.GetAccountParams) (store.Account, error)
+	getAccessibleAccountFn        func(ctx context.Context, arg store.GetAccessibleAccountParams) (store.GetAccessibleAccountRow, error)
+	listAccountsFn                func(ctx context.Context, arg store.ListAccountsParams) ([]store.ListAccountsRow, error)
+	listTransactionDescriptionsFn func(ctx context.Context, arg store.ListTransactionDescriptionsParams) ([]string, error)
+	getTrashedTransactionFn       func(ctx context.Context, arg store.GetTrashedTransactionParams) (store.GetTrashedTransactionRow, error)
+	createTransactionVersionFn    func(ctx context.Context, arg store.CreateTransactionVersionParams) error
+	listTransactionVersionsFn     func(ctx context.Context, arg store.ListTransactionVersionsParams) ([]store.ListTransactionVersionsRow, error)
+	isTradeTransactionFn          func(ctx context.Context, transactionID uuid.UUID) (bool, error)
+	isLoanPaymentFn               func(ctx context.Context, paymentID uuid.UUID) (bool, error)
+	withTxFn                      func(tx pgx.Tx) *store.Queries
 }
 
 func (m *mockTransactionStore) CreateTransaction(ctx context.Context, arg store.CreateTransactionParams) (store.Transaction, error) {
@@ -36,6 +44,13 @@ func (m *mockTransactionStore) CreateTransaction(ctx context.Context, arg store.
 func (m *mockTransactionStore) GetTransaction(ctx context.Context, arg store.GetTransactionParams) (store.Transaction, error) {
 	return m.getTransactionFn(ctx, arg)
 }
+func (m *mockTransactionStore) GetAccessibleTransaction(ctx context.Context, arg store.GetAccessibleTransactionParams) (store.GetAccessibleTransactionRow, error) {
+	if m.getAccessibleTransactionFn != nil {
+		return m.getAccessibleTransactionFn(ctx, arg)
+	}
+	txn, err := m.getTransactionFn(ctx, store.GetTransactionParams{ID: arg.ID, UserID: arg.UserID})
+	return store.GetAccessibleTransactionRow{Transaction: txn, Role: RoleOwner}, err
+}
 func (m *mockTransactionStore) ListTransactions(ctx context.Context, arg store.ListTransactionsParams) ([]store.Transaction, error) {
 	return m.listTransactionsFn(ctx, arg)
 }
@@ -45,17 +60,24 @@ func (m *mockTransactionStore) CountTransactions(ctx context.Context, arg store.
 func (m *mockTransactionStore) UpdateTransaction(ctx context.Context, arg store.UpdateTransactionParams) (store.Transaction, error) {
 	return m.updateTransactionFn(ctx, arg)
 }
-func (m *mockTransactionStore) DeleteTransaction(ctx context.Context, arg store.DeleteTransactionParams) error {
-	return m.deleteTransactionFn(ctx, arg)
+func (m *mockTransactionStore) TrashTransaction(ctx context.Context, arg store.TrashTransactionParams) error {
+	return m.trashTransactionFn(ctx, arg)
 }
-func (m *mockTransactionStore) DeleteTransactionByTransferID(ctx context.Context, arg store.DeleteTransactionByTransferIDParams) error {
-	return m.deleteTransactionByTransferIDFn(ctx, arg)
+func (m *mockTransactionStore) TrashTransfer(ctx context.Context, arg store.TrashTransferParams) error {
+	return m.trashTransferFn(ctx, arg)
 }
 func (m *mockTransactionStore) GetAccount(ctx context.Context, arg store.GetAccountParams) (store.Account, error) {
 	return m.getAccountFn(ctx, arg)
 }
-func (m *mockTransactionStore) ListAccounts(ctx context.Context, userID uuid.UUID) ([]store.ListAccountsRow, error) {
-	return m.listAccountsFn(ctx, userID)
+func (m *mockTransactionStore) GetAccessibleAccount(ctx context.Context, arg store.GetAccessibleAccountParams) (store.GetAccessibleAccountRow, error) {
+	if m.getAccessibleAccountFn != nil {
+		return m.getAccessibleAccountFn(ctx, arg)
+	}
+	acct, err := m.getAccountFn(ctx, store.GetAccountParams{ID: arg.ID, UserID: arg.UserID})
+	return store.GetAccessibleAccountRow{Account: acct, Role: RoleOwner}, err
+}
+func (m *mockTransactionStore) ListAccounts(ctx context.Context, arg store.ListAccountsParams) ([]store.ListAccountsRow, error) {
+	return m.listAccountsFn(ctx, arg)
 }
 func (m *mockTransactionStore) GetTransactionsByTransferID(ctx context.Context, arg store.GetTransactionsByTransferIDParams) ([]store.Transaction, error) {
 	return m.getTransactionsByTransferIDFn(ctx, arg)
@@ -63,9 +85,36 @@ func (m *mockTransactionStore) GetTransactionsByTransferID(ctx context.Context,
 func (m *mockTransactionStore) UpdateTransferTransaction(ctx context.Context, arg store.UpdateTransferTransactionParams) (store.Transaction, error) {
 	return m.updateTransferTransactionFn(ctx, arg)
 }
+func (m *mockTransactionStore) SetTransactionStatus(ctx context.Context, arg store.SetTransactionStatusParams) (store.Transaction, error) {
+	return m.setTransactionStatusFn(ctx, arg)
+}
 func (m *mockTransactionStore) ListTransactionDescriptions(ctx context.Context, arg store.ListTransactionDescriptionsParams) ([]string, error) {
 	return m.listTransactionDescriptionsFn(ctx, arg)
 }
+func (m *mockTransactionStore) GetTrashedTransaction(ctx context.Context, arg store.GetTrashedTransactionParams) (store.GetTrashedTransactionRow, error) {
+	return m.getTrashedTransactionFn(ctx, arg)
+}
+func (m *mockTransactionStore) CreateTransactionVersion(ctx context.Context, arg store.CreateTransactionVersionParams) error {
+	if m.createTransactionVersionFn != nil {
+		return m.createTransactionVersionFn(ctx, arg)
+	}
+	return nil
+}
+func (m *mockTransactionStore) ListTransactionVersions(ctx context.Context, arg store.ListTransactionVersionsParams) ([]store.ListTransactionVersionsRow, error) {
+	return m.listTransactionVersionsFn(ctx, arg)
+}
+func (m *mockTransactionStore) IsTradeTransaction(ctx context.Context, transactionID uuid.UUID) (bool, error) {
+	if m.isTradeTransactionFn != nil {
+		return m.isTradeTransactionFn(ctx, transactionID)
+	}
+	return false, nil
+}
+func (m *mockTransactionStore) IsLoanPayment(ctx context.Context, paymentID uuid.UUID) (bool, error) {
+	if m.isLoanPaymentFn != nil {
+		return m.isLoanPaymentFn(ctx, paymentID)
+	}
+	return false, nil
+}
 func (m *mockTransactionStore) WithTx(tx pgx.Tx) *store.Queries {
 	if m.withTxFn != nil {
 		return m.withTxFn(tx)
@@ -106,8 +155,8 @@ func TestTransactionDelete_WithTransfer(t *testing.T) {
 	txnID := uuid.New()
 	transferID := uuid.New()
 
-	deleteByTransferCalled := false
-	deleteCalled := false
+	trashTransferCalled := false
+	trashCalled := false
 
 	mock := &mockTransactionStore{
 		getTransactionFn: func(ctx context.Context, arg store.GetTransactionParams) (store.Transaction, error) {
@@ -117,13 +166,19 @@ func TestTransactionDelete_WithTransfer(t *testing.T) {
 				TransferID: pgtype.UUID{Bytes: transferID, Valid: true},
 			}, nil
 		},
-		deleteTransactionByTransferIDFn: func(ctx context.Context, arg store.DeleteTransactionByTransferIDParams) error {
-			deleteByTransferCalled = true
+		getTransactionsByTransferIDFn: func(ctx context.Context, arg store.GetTransactionsByTransferIDParams) ([]store.Transaction, error) {
+			return []store.Transaction{
+				{ID: txnID, Type: "expense", Status: TransactionStatusCleared},
+				{ID: uuid.New(), Type: "income", Status: TransactionStatusPending},
+			}, nil
+		},
+		trashTransferFn: func(ctx context.Context, arg store.TrashTransferParams) error {
+			trashTransferCalled = true
 			require.Equal(t, transferID, uuid.UUID(arg.TransferID.Bytes))
 			return nil
 		},
-		deleteTransactionFn: func(ctx context.Context, arg store.DeleteTransactionParams) error {
-			deleteCalled = true
+		trashTransactionFn: func(ctx context.Context, arg store.TrashTransactionParams) error {
+			trashCalled = true
 			return nil
 		},
 	}
@@ -132,16 +187,16 @@ func TestTransactionDelete_WithTransfer(t *testing.T) {
 	err := svc.Delete(context.Background(), userID, txnID)
 
 	require.NoError(t, err)
-	require.True(t, deleteByTransferCalled, "DeleteTransactionByTransferID should be called")
-	require.False(t, deleteCalled, "DeleteTransaction should not be called for transfer transactions")
+	require.True(t, trashTransferCalled, "TrashTransfer should be called")
+	require.False(t, trashCalled, "TrashTransaction should not be called for transfer transactions")
 }
 
 func TestTransactionDelete_NoTransfer(t *testing.T) {
 	userID := uuid.New()
 	txnID := uuid.New()
 
-	deleteByTransferCalled := false
-	deleteCalled := false
+	trashTransferCalled := false
+	trashCalled := false
 
 	mock := &mockTransactionStore{
 		getTransactionFn: func(ctx context.Context, arg store.GetTransactionParams) (store.Transaction, error) {
@@ -151,12 +206,12 @@ func TestTransactionDelete_NoTransfer(t *testing.T) {
 				TransferID: pgtype.UUID{Valid: false},
 			}, nil
 		},
-		deleteTransactionByTransferIDFn: func(ctx context.Context, arg store.DeleteTransactionByTransferIDParams) error {
-			deleteByTransferCalled = true
+		trashTransferFn: func(ctx context.Context, arg store.TrashTransferParams) error {
+			trashTransferCalled = true
 			return nil
 		},
-		deleteTransactionFn: func(ctx context.Context, arg store.DeleteTransactionParams) error {
-			deleteCalled = true
+		trashTransactionFn: func(ctx context.Context, arg store.TrashTransactionParams) error {
+			trashCalled = true
 			return nil
 		},
 	}
@@ -165,8 +220,8 @@ func TestTransactionDelete_NoTransfer(t *testing.T) {
 	err := svc.Delete(context.Background(), userID, txnID)
 
 	require.NoError(t, err)
-	require.False(t, deleteByTransferCalled, "DeleteTransactionByTransferID should NOT be called")
-	require.True(t, deleteCalled, "DeleteTransaction should be called")
+	require.False(t, trashTransferCalled, "TrashTransfer should NOT be called")
+	require.True(t, trashCalled, "TrashTransaction should be called")
 }
 
 func TestTransactionList_PaginationDefaults(t *testing.T) {
@@ -181,7 +236,7 @@ func TestTransactionList_PaginationDefaults(t *testing.T) {
 		countTransactionsFn: func(ctx context.Context, arg store.CountTransactionsParams) (int64, error) {
 			return 0, nil
 		},
-		listAccountsFn: func(ctx context.Context, userID uuid.UUID) ([]store.ListAccountsRow, error) {
+		listAccountsFn: func(ctx context.Context, arg store.ListAccountsParams) ([]store.ListAccountsRow, error) {
 			return []store.ListAccountsRow{}, nil
 		},
 	}
@@ -222,7 +277,7 @@ func TestTransactionList_InvalidDateFilters_DefaultsToCurrentMonth(t *testing.T)
 		countTransactionsFn: func(ctx context.Context, arg store.CountTransactionsParams) (int64, error) {
 			return 0, nil
 		},
-		listAccountsFn: func(ctx context.Context, userID uuid.UUID) ([]store.ListAccountsRow, error) {
+		listAccountsFn: func(ctx context.Context, arg store.ListAccountsParams) ([]store.ListAccountsRow, error) {
 			return []store.ListAccountsRow{}, nil
 		},
 	}
@@ -264,7 +319,7 @@ func TestTransactionList_InvalidDateFilter_PreservesValidCounterpart(t *testing.
 			countTransactionsFn: func(ctx context.Context, arg store.CountTransactionsParams) (int64, error) {
 				return 0, nil
 			},
-			listAccountsFn: func(ctx context.Context, userID uuid.UUID) ([]store.ListAccountsRow, error) {
+			listAccountsFn: func(ctx context.Context, arg store.ListAccountsParams) ([]store.ListAccountsRow, error) {
 				return []store.ListAccountsRow{}, nil
 			},
 		}
@@ -295,7 +350,7 @@ func TestTransactionList_InvalidDateFilter_PreservesValidCounterpart(t *testing.
 			countTransactionsFn: func(ctx context.Context, arg store.CountTransactionsParams) (int64, error) {
 				return 0, nil
 			},
-			listAccountsFn: func(ctx context.Context, userID uuid.UUID) ([]store.ListAccountsRow, error) {
+			listAccountsFn: func(ctx context.Context, arg store.ListAccountsParams) ([]store.ListAccountsRow, error) {
 				return []store.ListAccountsRow{}, nil
 			},
 		}
@@ -336,7 +391,7 @@ func TestTransactionList_UsesListAccountsForCurrency(t *testing.T) {
 		countTransactionsFn: func(ctx context.Context, arg store.CountTransactionsParams) (int64, error) {
 			return 1, nil
 		},
-		listAccountsFn: func(ctx context.Context, userID uuid.UUID) ([]store.ListAccountsRow, error) {
+		listAccountsFn: func(ctx context.Context, arg store.ListAccountsParams) ([]store.ListAccountsRow, error) {
 			return []store.ListAccountsRow{{ID: accountID, UserID: userID, Currency: "USD"}}, nil
 		},
 		getAccountFn: func(ctx context.Context, arg store.GetAccountParams) (store.Account, error) {
@@ -384,12 +439,12 @@ func TestTransactionDelete_NotFound(t *testing.T) {
 		getTransactionFn: func(ctx context.Context, arg store.GetTransactionParams) (store.Transaction, error) {
 			return store.Transaction{}, pgx.ErrNoRows
 		},
-		deleteTransactionByTransferIDFn: func(ctx context.Context, arg store.DeleteTransactionByTransferIDParams) error {
-			t.Fatalf("DeleteTransactionByTransferID should not be called when transaction is missing")
+		trashTransferFn: func(ctx context.Context, arg store.TrashTransferParams) error {
+			t.Fatalf("TrashTransfer should not be called when transaction is missing")
 			return nil
 		},
-		deleteTransactionFn: func(ctx context.Context, arg store.DeleteTransactionParams) error {
-			t.Fatalf("DeleteTransaction should not be called when transaction is missing")
+		trashTransactionFn: func(ctx context.Context, arg store.TrashTransactionParams) error {
+			t.Fatalf("TrashTransaction should not be called when transaction is missing")
 			return nil
 		},
 	}
@@ -424,3 +479,406 @@ func TestUpdateTransfer_NotATransfer(t *testing.T) {
 
 	require.ErrorIs(t, err, ErrNotATransfer)
 }
+
+func newTransferTestService(t *testing.T, fromCurrency, toCurrency string, rates ...store.ExchangeRate) (*Transaction, uuid.UUID, uuid.UUID) {
+	t.Helper()
+	fromID, toID := uuid.New(), uuid.New()
+	mock := &mockTransactionStore{
+		getAccountFn: func(ctx context.Context, arg store.GetAccountParams) (store.Account, error) {
+			switch arg.ID {
+			case fromID:
+				return store.Account{ID: fromID, Currency: fromCurrency}, nil
+			case toID:
+				return store.Account{ID: toID, Currency: toCurrency}, nil
+			}
+			return store.Account{}, pgx.ErrNoRows
+		},
+	}
+	return &Transaction{
+		queries:    mock,
+		rates:      &RateResolver{queries: &mockRateStore{rates: rates}},
+		currencies: testCurrencyPrecision(),
+	}, fromID, toID
+}
+
+func TestTransaction_ReconciledIsLocked(t *testing.T) {
+	userID := uuid.New()
+	txnID := uuid.New()
+	transferID := uuid.New()
+
+	reconciled := func(ctx context.Context, arg store.GetTransactionParams) (store.Transaction, error) {
+		return store.Transaction{ID: txnID, UserID: userID, Status: TransactionStatusReconciled}, nil
+	}
+
+	t.Run("update", func(t *testing.T) {
+		svc := &Transaction{queries: &mockTransactionStore{getTransactionFn: reconciled}}
+		_, err := svc.Update(context.Background(), userID, txnID, dto.UpdateTransactionRequest{
+			AccountID: uuid.New(),
+			Type:      "expense",
+			Amount:    "10",
+			Date:      "2025-01-15",
+		})
+		require.ErrorIs(t, err, ErrTransactionReconciled)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		svc := &Transaction{queries: &mockTransactionStore{getTransactionFn: reconciled}}
+		err := svc.Delete(context.Background(), userID, txnID)
+		require.ErrorIs(t, err, ErrTransactionReconciled)
+	})
+
+	t.Run("status change", func(t *testing.T) {
+		svc := &Transaction{queries: &mockTransactionStore{getTransactionFn: reconciled}}
+		_, err := svc.SetStatus(context.Background(), userID, txnID, TransactionStatusPending)
+		require.ErrorIs(t, err, ErrTransactionReconciled)
+	})
+
+	t.Run("delete transfer with one reconciled leg", func(t *testing.T) {
+		deleted := false
+		svc := &Transaction{queries: &mockTransactionStore{
+			getTransactionFn: func(ctx context.Context, arg store.GetTransactionParams) (store.Transaction, error) {
+				return store.Transaction{ID: txnID, UserID: userID, TransferID: pgtype.UUID{Bytes: transferID, Valid: true}}, nil
+			},
+			getTransactionsByTransferIDFn: func(ctx context.Context, arg store.GetTransactionsByTransferIDParams) ([]store.Transaction, error) {
+				return []store.Transaction{
+					{ID: txnID, Type: "expense", Status: TransactionStatusPending},
+					{ID: uuid.New(), Type: "income", Status: TransactionStatusReconciled},
+				}, nil
+			},
+			trashTransferFn: func(ctx context.Context, arg store.TrashTransferParams) error {
+				deleted = true
+				return nil
+			},
+		}}
+		err := svc.Delete(context.Background(), userID, txnID)
+		require.ErrorIs(t, err, ErrTransactionReconciled)
+		require.False(t, deleted)
+	})
+}
+
+func TestTransactionUpdate_TradeCash(t *testing.T) {
+	userID := uuid.New()
+	txnID := uuid.New()
+	updated := false
+	svc := &Transaction{queries: &mockTransactionStore{
+		getTransactionFn: func(ctx context.Context, arg store.GetTransactionParams) (store.Transaction, error) {
+			return store.Transaction{ID: txnID, UserID: userID, Status: TransactionStatusPending}, nil
+		},
+		isTradeTransactionFn: func(ctx context.Context, transactionID uuid.UUID) (bool, error) {
+			return transactionID == txnID, nil
+		},
+		updateTransactionFn: func(ctx context.Context, arg store.UpdateTransactionParams) (store.Transaction, error) {
+			updated = true
+			return store.Transaction{}, nil
+		},
+	}}
+
+	_, err := svc.Update(context.Background(), userID, txnID, dto.UpdateTransactionRequest{
+		AccountID: uuid.New(),
+		Type:      "expense",
+		Amount:    "10",
+		Date:      "2025-01-15",
+	})
+	require.ErrorIs(t, err, ErrTradeTransaction)
+	require.False(t, updated, "the cash side stays in step with its trade")
+}
+
+func TestTransactionDelete_TradeCash(t *testing.T) {
+	userID := uuid.New()
+	txnID := uuid.New()
+	trashed := false
+	svc := &Transaction{queries: &mockTransactionStore{
+		getTransactionFn: func(ctx context.Context, arg store.GetTransactionParams) (store.Transaction, error) {
+			return store.Transaction{ID: txnID, UserID: userID, Status: TransactionStatusPending}, nil
+		},
+		isTradeTransactionFn: func(ctx context.Context, transactionID uuid.UUID) (bool, error) {
+			return transactionID == txnID, nil
+		},
+		trashTransactionFn: func(ctx context.Context, arg store.TrashTransactionParams) error {
+			trashed = true
+			return nil
+		},
+	}}
+
+	err := svc.Delete(context.Background(), userID, txnID)
+	require.ErrorIs(t, err, ErrTradeTransaction)
+	require.False(t, trashed, "trashing the cash alone would drop the trade from holdings")
+}
+
+func TestUpdateTransfer_SplitLoanPayment(t *testing.T) {
+	userID := uuid.New()
+	transferID := pgtype.UUID{Bytes: uuid.New(), Valid: true}
+	srcID, paymentID := uuid.New(), uuid.New()
+	date, _ := dateFromString("2025-03-14")
+
+	newService := func(updated *int) (*Transaction, dto.UpdateTransferRequest) {
+		svc, from, to := newTransferTestService(t, "EUR", "EUR")
+		legs := []store.Transaction{
+			{ID: srcID, AccountID: from, Type: "expense", Amount: numericFromString("500.00"), Date: date, TransferID: transferID, Status: TransactionStatusPending},
+			{ID: paymentID, AccountID: to, Type: "income", Amount: numericFromString("500.00"), Date: date, TransferID: transferID, Status: TransactionStatusPending},
+		}
+		mock := svc.queries.(*mockTransactionStore)
+		mock.getTransactionFn = func(ctx context.Context, arg store.GetTransactionParams) (store.Transaction, error) {
+			return legs[0], nil
+		}
+		mock.getTransactionsByTransferIDFn = func(ctx context.Context, arg store.GetTransactionsByTransferIDParams) ([]store.Transaction, error) {
+			return legs, nil
+		}
+		mock.isLoanPaymentFn = func(ctx context.Context, id uuid.UUID) (bool, error) {
+			return id == paymentID, nil
+		}
+		mock.updateTransferTransactionFn = func(ctx context.Context, arg store.UpdateTransferTransactionParams) (store.Transaction, error) {
+			*updated++
+			return store.Transaction{ID: arg.ID, AccountID: arg.AccountID, Amount: arg.Amount, Description: arg.Description, Date: arg.Date}, nil
+		}
+		return svc, dto.UpdateTransferRequest{FromAccountID: from, ToAccountID: to, Amount: "500", Date: "2025-03-14"}
+	}
+
+	t.Run("amount change is rejected", func(t *testing.T) {
+		updated := 0
+		svc, req := newService(&updated)
+		req.Amount = "450"
+		_, err := svc.UpdateTransfer(context.Background(), userID, srcID, req)
+		require.ErrorIs(t, err, ErrLoanPaymentSplit)
+		require.Zero(t, updated, "the stored split would no longer add up to the payment")
+	})
+
+	t.Run("date change is rejected", func(t *testing.T) {
+		updated := 0
+		svc, req := newService(&updated)
+		req.Date = "2025-04-14"
+		_, err := svc.UpdateTransfer(context.Background(), userID, srcID, req)
+		require.ErrorIs(t, err, ErrLoanPaymentSplit)
+		require.Zero(t, updated)
+	})
+
+	t.Run("description change is allowed", func(t *testing.T) {
+		updated := 0
+		svc, req := newService(&updated)
+		req.Description = "March payment"
+		_, err := svc.UpdateTransfer(context.Background(), userID, srcID, req)
+		require.NoError(t, err)
+		require.Equal(t, 2, updated)
+	})
+}
+
+func TestTransactionSetStatus(t *testing.T) {
+	userID := uuid.New()
+	txnID := uuid.New()
+	accountID := uuid.New()
+
+	var captured store.SetTransactionStatusParams
+	mock := &mockTransactionStore{
+		getTransactionFn: func(ctx context.Context, arg store.GetTransactionParams) (store.Transaction, error) {
+			return store.Transaction{ID: txnID, UserID: userID, AccountID: accountID, Status: TransactionStatusPending}, nil
+		},
+		setTransactionStatusFn: func(ctx context.Context, arg store.SetTransactionStatusParams) (store.Transaction, error) {
+			captured = arg
+			return store.Transaction{ID: txnID, UserID: userID, AccountID: accountID, Status: arg.Status, Amount: numericFromString("12.5")}, nil
+		},
+		getAccountFn: func(ctx context.Context, arg store.GetAccountParams) (store.Account, error) {
+			return store.Account{ID: accountID, Currency: "USD"}, nil
+		},
+	}
+
+	svc := &Transaction{queries: mock}
+	resp, err := svc.SetStatus(context.Background(), userID, txnID, TransactionStatusCleared)
+	require.NoError(t, err)
+	require.Equal(t, TransactionStatusCleared, captured.Status)
+	require.Equal(t, TransactionStatusCleared, resp.Status)
+	require.Equal(t, "12.50", resp.Amount)
+}
+
+func TestTransaction_ArchivedAccount(t *testing.T) {
+	userID := uuid.New()
+	archivedID := uuid.New()
+	openID := uuid.New()
+	txnID := uuid.New()
+
+	getAccount := func(ctx context.Context, arg store.GetAccountParams) (store.Account, error) {
+		acct := store.Account{ID: arg.ID, UserID: userID, Currency: "USD"}
+		if arg.ID == archivedID {
+			acct.ArchivedOn = pgtype.Date{Time: time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC), Valid: true}
+		}
+		return acct, nil
+	}
+
+	t.Run("create is rejected", func(t *testing.T) {
+		svc := &Transaction{queries: &mockTransactionStore{getAccountFn: getAccount}}
+		_, err := svc.Create(context.Background(), userID, dto.CreateTransactionRequest{
+			AccountID: archivedID,
+			Type:      "expense",
+			Amount:    "10",
+			Date:      "2025-07-01",
+		})
+		require.ErrorIs(t, err, ErrAccountArchived)
+	})
+
+	t.Run("transfer into it is rejected", func(t *testing.T) {
+		svc := &Transaction{queries: &mockTransactionStore{getAccountFn: getAccount}}
+		_, err := svc.CreateTransfer(context.Background(), userID, dto.CreateTransferRequest{
+			FromAccountID: openID,
+			ToAccountID:   archivedID,
+			Amount:        "10",
+			Date:          "2025-07-01",
+		})
+		require.ErrorIs(t, err, ErrAccountArchived)
+	})
+
+	t.Run("moving a transaction into it is rejected", func(t *testing.T) {
+		svc := &Transaction{queries: &mockTransactionStore{
+			getAccountFn: getAccount,
+			getTransactionFn: func(ctx context.Context, arg store.GetTransactionParams) (store.Transaction, error) {
+				return store.Transaction{ID: txnID, UserID: userID, AccountID: openID}, nil
+			},
+		}}
+		_, err := svc.Update(context.Background(), userID, txnID, dto.UpdateTransactionRequest{
+			AccountID: archivedID,
+			Type:      "expense",
+			Amount:    "10",
+			Date:      "2025-05-01",
+		})
+		require.ErrorIs(t, err, ErrAccountArchived)
+	})
+
+	t.Run("existing transactions stay editable", func(t *testing.T) {
+		svc := &Transaction{queries: &mockTransactionStore{
+			getAccountFn: getAccount,
+			getTransactionFn: func(ctx context.Context, arg store.GetTransactionParams) (store.Transaction, error) {
+				return store.Transaction{ID: txnID, UserID: userID, AccountID: archivedID}, nil
+			},
+			updateTransactionFn: func(ctx context.Context, arg store.UpdateTransactionParams) (store.Transaction, error) {
+				return store.Transaction{ID: txnID, UserID: userID, AccountID: arg.AccountID, Amount: arg.Amount}, nil
+			},
+		}}
+		resp, err := svc.Update(context.Background(), userID, txnID, dto.UpdateTransactionRequest{
+			AccountID: archivedID,
+			Type:      "expense",
+			Amount:    "12",
+			Date:      "2025-05-01",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "12.00", resp.Amount)
+	})
+}
+
+func TestTransferAmounts(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	date, _ := dateFromString("2025-03-14")
+
+	t.Run("same currency defaults to_amount to amount", func(t *testing.T) {
+		svc, from, to := newTransferTestService(t, "EUR", "EUR")
+		got, err := svc.transferAmounts(ctx, userID, from, to, "100.00", "", "", date, nil)
+		require.NoError(t, err)
+		require.Equal(t, "100.00", numericToString(got.toAmount))
+		require.False(t, got.exchangeRate.Valid)
+	})
+
+	t.Run("looks up rate when neither to_amount nor rate given", func(t *testing.T) {
+		svc, from, to := newTransferTestService(t, "EUR", "AMD",
+			storedRate(t, "EUR", "AMD", "416.5", "2025-03-13", "ecb"))
+		got, err := svc.transferAmounts(ctx, userID, from, to, "100", "", "", date, nil)
+		require.NoError(t, err)
+		require.Equal(t, "41650.00", numericToString(got.toAmount))
+		require.Equal(t, "416.5", numericToDecimal(got.exchangeRate).String())
+	})
+
+	t.Run("rejects cross-currency transfer without a rate", func(t *testing.T) {
+		svc, from, to := newTransferTestService(t, "EUR", "AMD")
+		_, err := svc.transferAmounts(ctx, userID, from, to, "100", "", "", date, nil)
+		require.ErrorIs(t, err, ErrTransferRateRequired)
+	})
+
+	t.Run("derives to_amount from rate", func(t *testing.T) {
+		svc, from, to := newTransferTestService(t, "USD", "EUR")
+		got, err := svc.transferAmounts(ctx, userID, from, to, "100", "", "0.92", date, nil)
+		require.NoError(t, err)
+		require.Equal(t, "92.00", numericToString(got.toAmount))
+	})
+
+	t.Run("derives rate from to_amount", func(t *testing.T) {
+		svc, from, to := newTransferTestService(t, "USD", "EUR")
+		got, err := svc.transferAmounts(ctx, userID, from, to, "300", "275", "", date, nil)
+		require.NoError(t, err)
+		require.Equal(t, "0.91666667", numericToDecimal(got.exchangeRate).String())
+	})
+
+	t.Run("accepts consistent amounts within rounding", func(t *testing.T) {
+		svc, from, to := newTransferTestService(t, "USD", "EUR")
+		_, err := svc.transferAmounts(ctx, userID, from, to, "300", "275", "0.9167", date, nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("rejects inconsistent amounts", func(t *testing.T) {
+		svc, from, to := newTransferTestService(t, "USD", "EUR")
+		_, err := svc.transferAmounts(ctx, userID, from, to, "300", "275", "0.95", date, nil)
+		require.ErrorIs(t, err, ErrTransferAmountMismatch)
+	})
+
+	t.Run("rejects invalid amounts", func(t *testing.T) {
+		svc, from, to := newTransferTestService(t, "USD", "EUR")
+		_, err := svc.transferAmounts(ctx, userID, from, to, "-5", "", "0.9", date, nil)
+		require.ErrorIs(t, err, ErrInvalidTransferAmount)
+		_, err = svc.transferAmounts(ctx, userID, from, to, "abc", "", "0.9", date, nil)
+		require.ErrorIs(t, err, ErrInvalidTransferAmount)
+	})
+
+	t.Run("rounds to_amount to the destination currency's precision", func(t *testing.T) {
+		svc, from, to := newTransferTestService(t, "USD", "JPY")
+		got, err := svc.transferAmounts(ctx, userID, from, to, "10.05", "", "149.456", date, nil)
+		require.NoError(t, err)
+		require.Equal(t, "1502", numericToDecimal(got.toAmount).String())
+	})
+
+	t.Run("rejects amounts too precise for the currency", func(t *testing.T) {
+		svc, from, to := newTransferTestService(t, "USD", "KWD")
+		_, err := svc.transferAmounts(ctx, userID, from, to, "100.001", "", "0.31", date, nil)
+		require.ErrorIs(t, err, ErrAmountPrecision)
+		_, err = svc.transferAmounts(ctx, userID, from, to, "100", "30.7512", "", date, nil)
+		require.ErrorIs(t, err, ErrAmountPrecision)
+	})
+
+	t.Run("unknown account", func(t *testing.T) {
+		svc, from, _ := newTransferTestService(t, "USD", "EUR")
+		_, err := svc.transferAmounts(ctx, userID, from, uuid.New(), "100", "", "", date, nil)
+		require.ErrorIs(t, err, ErrTransferAccountNotFound)
+	})
+}
+
+func TestTransactionCreate_HouseholdRoles(t *testing.T) {
+	ownerID := uuid.New()
+	memberID := uuid.New()
+	accountID := uuid.New()
+
+	sharedAs := func(role string) func(ctx context.Context, arg store.GetAccessibleAccountParams) (store.GetAccessibleAccountRow, error) {
+		return func(ctx context.Context, arg store.GetAccessibleAccountParams) (store.GetAccessibleAccountRow, error) {
+			return store.GetAccessibleAccountRow{Account: store.Account{ID: arg.ID, UserID: ownerID, Currency: "USD"}, Role: role}, nil
+		}
+	}
+	req := dto.CreateTransactionRequest{AccountID: accountID, Type: "expense", Amount: "10", Date: "2025-07-01"}
+
+	t.Run("viewer is rejected", func(t *testing.T) {
+		svc := &Transaction{queries: &mockTransactionStore{getAccessibleAccountFn: sharedAs(RoleViewer)}}
+		_, err := svc.Create(context.Background(), memberID, req)
+		require.ErrorIs(t, err, ErrHouseholdReadOnly)
+	})
+
+	t.Run("editor records it for the owner", func(t *testing.T) {
+		var got store.CreateTransactionParams
+		svc := &Transaction{queries: &mockTransactionStore{
+			getAccessibleAccountFn: sharedAs(RoleEditor),
+			getAccountFn: func(ctx context.Context, arg store.GetAccountParams) (store.Account, error) {
+				return store.Account{ID: arg.ID, UserID: ownerID, Currency: "USD"}, nil
+			},
+			createTransactionFn: func(ctx context.Context, arg store.CreateTransactionParams) (store.Transaction, error) {
+				got = arg
+				return store.Transaction{ID: uuid.New(), UserID: arg.UserID, AccountID: arg.AccountID, Amount: arg.Amount, CreatedBy: arg.CreatedBy}, nil
+			},
+		}}
+		resp, err := svc.Create(context.Background(), memberID, req)
+		require.NoError(t, err)
+		require.Equal(t, ownerID, got.UserID)
+		require.Equal(t, memberID, resp.CreatedBy)
+	})
+}
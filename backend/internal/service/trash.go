@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/dto"
+	"github.com/sanches/finance-tracker-cc/backend/internal/store"
+)
+
+var ErrRestoreBlocked = errors.New("its account or category is in the trash, restore that first")
+
+type trashStore interface {
+	ListTrashedAccounts(ctx context.Context, userID uuid.UUID) ([]store.Account, error)
+	ListTrashedCategories(ctx context.Context, userID uuid.UUID) ([]store.Category, error)
+	ListTrashedTransactions(ctx context.Context, userID uuid.UUID) ([]store.ListTrashedTransactionsRow, error)
+	RestoreAccount(ctx context.Context, arg store.RestoreAccountParams) (store.Account, error)
+	GetTrashedCategory(ctx context.Context, arg store.GetTrashedCategoryParams) (store.GetTrashedCategoryRow, error)
+	RestoreCategory(ctx context.Context, arg store.RestoreCategoryParams) (store.Category, error)
+	GetTrashedTransaction(ctx context.Context, arg store.GetTrashedTransactionParams) (store.GetTrashedTransactionRow, error)
+	RestoreTransaction(ctx context.Context, arg store.RestoreTransactionParams) (int64, error)
+	PurgeTransactions(ctx context.Context, deletedAt pgtype.Timestamptz) (int64, error)
+	PurgeAccounts(ctx context.Context, deletedAt pgtype.Timestamptz) (int64, error)
+	PurgeCategories(ctx context.Context, deletedAt pgtype.Timestamptz) (int64, error)
+}
+
+// Trash lists and restores the accounts, categories and transactions a user
+// deleted. Deleted items stay in the trash for the retention period, after
+// which Purge deletes them for good.
+type Trash struct {
+	queries    trashStore
+	currencies *CurrencyPrecision
+	retention  time.Duration
+}
+
+// NewTrash keeps deleted items for retention; zero keeps them forever.
+func NewTrash(queries *store.Queries, currencies *CurrencyPrecision, retention time.Duration) *Trash {
+	return &Trash{queries: queries, currencies: currencies, retention: retention}
+}
+
+func (s *Trash) List(ctx context.Context, userID uuid.UUID) (*dto.TrashResponse, error) {
+	prec, err := s.currencies.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	accounts, err := s.queries.ListTrashedAccounts(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	categories, err := s.queries.ListTrashedCategories(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	txns, err := s.queries.ListTrashedTransactions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.TrashResponse{
+		Accounts:     make([]dto.AccountResponse, 0, len(accounts)),
+		Categories:   make([]dto.CategoryResponse, 0, len(categories)),
+		Transactions: make([]dto.TransactionResponse, 0, len(txns)),
+	}
+	for _, a := range accounts {
+		resp.Accounts = append(resp.Accounts, accountToResponse(a, prec))
+	}
+	for _, c := range categories {
+		resp.Categories = append(resp.Categories, *catToResponse(c))
+	}
+	for _, t := range txns {
+		resp.Transactions = append(resp.Transactions, *transactionToResponse(t.Transaction, t.Currency, prec))
+	}
+	return resp, nil
+}
+
+// RestoreAccount brings back an account and the transactions deleted with
+// it. It fails with ErrAccountExists when a new account took its name.
+func (s *Trash) RestoreAccount(ctx context.Context, userID, accountID uuid.UUID) error {
+	_, err := s.queries.RestoreAccount(ctx, store.RestoreAccountParams{ID: accountID, UserID: userID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		if isDuplicateKey(err) {
+			return ErrAccountExists
+		}
+		return err
+	}
+	return nil
+}
+
+// RestoreCategory brings back a category whose parent is not in the trash.
+// It fails with ErrCategoryExists when a new category took its name.
+func (s *Trash) RestoreCategory(ctx context.Context, userID, categoryID uuid.UUID) error {
+	row, err := s.queries.GetTrashedCategory(ctx, store.GetTrashedCategoryParams{ID: categoryID, UserID: userID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		return err
+	}
+	if row.ParentTrashed {
+		return ErrRestoreBlocked
+	}
+
+	if _, err := s.queries.RestoreCategory(ctx, store.RestoreCategoryParams{ID: categoryID, UserID: userID}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		if isDuplicateKey(err) {
+			return ErrCategoryExists
+		}
+		return err
+	}
+	return nil
+}
+
+// RestoreTransaction brings back a transaction together with the other leg
+// of its transfer and the interest split off a loan payment. Their accounts
+// and categories must not be in the trash.
+func (s *Trash) RestoreTransaction(ctx context.Context, userID, txnID uuid.UUID) error {
+	row, err := s.queries.GetTrashedTransaction(ctx, store.GetTrashedTransactionParams{ID: txnID, UserID: userID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		return err
+	}
+	if !row.Restorable {
+		return ErrRestoreBlocked
+	}
+
+	n, err := s.queries.RestoreTransaction(ctx, store.RestoreTransactionParams{ID: txnID, UserID: userID})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Purge deletes for good whatever has been in the trash longer than the
+// retention period. It runs daily. Transactions go first so that their
+// categories are no longer referenced.
+func (s *Trash) Purge(ctx context.Context) error {
+	if s.retention <= 0 {
+		return nil
+	}
+	cutoff := pgtype.Timestamptz{Time: time.Now().Add(-s.retention), Valid: true}
+
+	txns, err := s.queries.PurgeTransactions(ctx, cutoff)
+	if err != nil {
+		return err
+	}
+	accounts, err := s.queries.PurgeAccounts(ctx, cutoff)
+	if err != nil {
+		return err
+	}
+	categories, err := s.queries.PurgeCategories(ctx, cutoff)
+	if err != nil {
+		return err
+	}
+	if txns+accounts+categories > 0 {
+		slog.Info("purged trash", "transactions", txns, "accounts", accounts, "categories", categories)
+	}
+	return nil
+}
+
+// deletedAt is when an item went to the trash, nil for live ones.
+func deletedAt(t pgtype.Timestamptz) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/store"
+)
+
+type mockTrashStore struct {
+	trashedCategory    store.GetTrashedCategoryRow
+	trashedTransaction store.GetTrashedTransactionRow
+	getErr             error
+	restoreAccountErr  error
+	restored           bool
+	purged             []string
+	purgeCutoff        pgtype.Timestamptz
+}
+
+func (m *mockTrashStore) ListTrashedAccounts(_ context.Context, _ uuid.UUID) ([]store.Account, error) {
+	return nil, nil
+}
+
+func (m *mockTrashStore) ListTrashedCategories(_ context.Context, _ uuid.UUID) ([]store.Category, error) {
+	return nil, nil
+}
+
+func (m *mockTrashStore) ListTrashedTransactions(_ context.Context, _ uuid.UUID) ([]store.ListTrashedTransactionsRow, error) {
+	return nil, nil
+}
+
+func (m *mockTrashStore) RestoreAccount(_ context.Context, _ store.RestoreAccountParams) (store.Account, error) {
+	m.restored = m.restoreAccountErr == nil
+	return store.Account{}, m.restoreAccountErr
+}
+
+func (m *mockTrashStore) GetTrashedCategory(_ context.Context, _ store.GetTrashedCategoryParams) (store.GetTrashedCategoryRow, error) {
+	return m.trashedCategory, m.getErr
+}
+
+func (m *mockTrashStore) RestoreCategory(_ context.Context, _ store.RestoreCategoryParams) (store.Category, error) {
+	m.restored = true
+	return store.Category{}, nil
+}
+
+func (m *mockTrashStore) GetTrashedTransaction(_ context.Context, _ store.GetTrashedTransactionParams) (store.GetTrashedTransactionRow, error) {
+	return m.trashedTransaction, m.getErr
+}
+
+func (m *mockTrashStore) RestoreTransaction(_ context.Context, _ store.RestoreTransactionParams) (int64, error) {
+	m.restored = true
+	return 2, nil
+}
+
+func (m *mockTrashStore) PurgeTransactions(_ context.Context, cutoff pgtype.Timestamptz) (int64, error) {
+	m.purged = append(m.purged, "transactions")
+	m.purgeCutoff = cutoff
+	return 0, nil
+}
+
+func (m *mockTrashStore) PurgeAccounts(_ context.Context, _ pgtype.Timestamptz) (int64, error) {
+	m.purged = append(m.purged, "accounts")
+	return 0, nil
+}
+
+func (m *mockTrashStore) PurgeCategories(_ context.Context, _ pgtype.Timestamptz) (int64, error) {
+	m.purged = append(m.purged, "categories")
+	return 0, nil
+}
+
+func TestTrashRestoreAccount(t *testing.T) {
+	ctx := context.Background()
+
+	mock := &mockTrashStore{restoreAccountErr: pgx.ErrNoRows}
+	svc := &Trash{queries: mock}
+	require.ErrorIs(t, svc.RestoreAccount(ctx, uuid.New(), uuid.New()), ErrNotFound)
+
+	mock.restoreAccountErr = errors.New("duplicate key value violates unique constraint (23505)")
+	require.ErrorIs(t, svc.RestoreAccount(ctx, uuid.New(), uuid.New()), ErrAccountExists)
+
+	mock.restoreAccountErr = nil
+	require.NoError(t, svc.RestoreAccount(ctx, uuid.New(), uuid.New()))
+	require.True(t, mock.restored)
+}
+
+func TestTrashRestoreCategory_ParentTrashed(t *testing.T) {
+	mock := &mockTrashStore{trashedCategory: store.GetTrashedCategoryRow{ParentTrashed: true}}
+	svc := &Trash{queries: mock}
+
+	err := svc.RestoreCategory(context.Background(), uuid.New(), uuid.New())
+	require.ErrorIs(t, err, ErrRestoreBlocked)
+	require.False(t, mock.restored)
+}
+
+func TestTrashRestoreTransaction(t *testing.T) {
+	ctx := context.Background()
+
+	mock := &mockTrashStore{getErr: pgx.ErrNoRows}
+	svc := &Trash{queries: mock}
+	require.ErrorIs(t, svc.RestoreTransaction(ctx, uuid.New(), uuid.New()), ErrNotFound)
+
+	mock.getErr = nil
+	require.ErrorIs(t, svc.RestoreTransaction(ctx, uuid.New(), uuid.New()), ErrRestoreBlocked)
+	require.False(t, mock.restored)
+
+	mock.trashedTransaction.Restorable = true
+	require.NoError(t, svc.RestoreTransaction(ctx, uuid.New(), uuid.New()))
+	require.True(t, mock.restored)
+}
+
+func TestTrashPurge(t *testing.T) {
+	ctx := context.Background()
+
+	mock := &mockTrashStore{}
+	require.NoError(t, (&Trash{queries: mock}).Purge(ctx))
+	require.Empty(t, mock.purged, "zero retention keeps the trash forever")
+
+	before := time.Now()
+	require.NoError(t, (&Trash{queries: mock, retention: 30 * 24 * time.Hour}).Purge(ctx))
+	require.Equal(t, []string{"transactions", "accounts", "categories"}, mock.purged)
+	require.WithinDuration(t, before.Add(-30*24*time.Hour), mock.purgeCutoff.Time, time.Minute)
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"golang.org/x/crypto/bcrypt"
 
@@ -21,6 +22,7 @@ type userStore interface {
 	DeleteAllUserAccounts(ctx context.Context, userID uuid.UUID) error
 	DeleteAllUserCategories(ctx context.Context, userID uuid.UUID) error
 	CreateDefaultCategories(ctx context.Context, userID uuid.UUID) error
+	ListLoginAttempts(ctx context.Context, arg store.ListLoginAttemptsParams) ([]store.LoginAttempt, error)
 	WithTx(tx pgx.Tx) *store.Queries
 }
 
@@ -55,6 +57,7 @@ func (s *User) Update(ctx context.Context, userID uuid.UUID, req dto.UpdateUserR
 		Username:     user.Username,
 		DisplayName:  user.DisplayName,
 		BaseCurrency: user.BaseCurrency,
+		IsAdmin:      user.IsAdmin,
 		CreatedAt:    user.CreatedAt.Time,
 	}, nil
 }
@@ -83,6 +86,33 @@ func (s *User) ChangePassword(ctx context.Context, userID uuid.UUID, req dto.Cha
 	})
 }
 
+// loginHistoryLimit is how many of the latest login attempts LoginHistory
+// returns.
+const loginHistoryLimit = 50
+
+// LoginHistory returns the user's latest login attempts, newest first, so
+// they can spot logins they don't recognise.
+func (s *User) LoginHistory(ctx context.Context, userID uuid.UUID) ([]dto.LoginAttemptResponse, error) {
+	attempts, err := s.queries.ListLoginAttempts(ctx, store.ListLoginAttemptsParams{
+		UserID: pgtype.UUID{Bytes: userID, Valid: true},
+		Limit:  loginHistoryLimit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.LoginAttemptResponse, 0, len(attempts))
+	for _, a := range attempts {
+		result = append(result, dto.LoginAttemptResponse{
+			IP:        a.Ip,
+			UserAgent: a.UserAgent,
+			Outcome:   a.Outcome,
+			CreatedAt: a.CreatedAt.Time,
+		})
+	}
+	return result, nil
+}
+
 func (s *User) Reset(ctx context.Context, userID uuid.UUID) error {
 	tx, err := s.pool.Begin(ctx)
 	if err != nil {
@@ -95,12 +125,27 @@ func (s *User) Reset(ctx context.Context, userID uuid.UUID) error {
 	if err := q.DeleteAllUserTransactions(ctx, userID); err != nil {
 		return err
 	}
+	if err := q.DeleteAllUserExpenseSplits(ctx, userID); err != nil {
+		return err
+	}
+	if err := q.DeleteAllUserContacts(ctx, userID); err != nil {
+		return err
+	}
 	if err := q.DeleteAllUserAccounts(ctx, userID); err != nil {
 		return err
 	}
+	if err := q.DeleteAllUserAccountGroups(ctx, userID); err != nil {
+		return err
+	}
+	if err := q.DeleteAllUserSecurities(ctx, userID); err != nil {
+		return err
+	}
 	if err := q.DeleteAllUserCategories(ctx, userID); err != nil {
 		return err
 	}
+	if err := q.DeleteAllUserExchangeRates(ctx, userID); err != nil {
+		return err
+	}
 	if err := q.CreateDefaultCategories(ctx, userID); err != nil {
 		return err
 	}
@@ -18,6 +18,7 @@ type mockUserStore struct {
 	getUserErr     error
 	updatePwErr    error
 	updatePwCalled bool
+	loginAttempts  []store.LoginAttempt
 }
 
 func (m *mockUserStore) GetUserByID(_ context.Context, _ uuid.UUID) (store.User, error) {
@@ -29,6 +30,10 @@ func (m *mockUserStore) UpdateUserPassword(_ context.Context, arg store.UpdateUs
 	return m.updatePwErr
 }
 
+func (m *mockUserStore) ListLoginAttempts(_ context.Context, _ store.ListLoginAttemptsParams) ([]store.LoginAttempt, error) {
+	return m.loginAttempts, nil
+}
+
 func (m *mockUserStore) UpdateUser(_ context.Context, _ store.UpdateUserParams) (store.User, error) {
 	return store.User{}, nil
 }
@@ -82,3 +87,27 @@ func TestChangePassword_WrongCurrentPassword(t *testing.T) {
 		t.Fatal("UpdateUserPassword should not have been called")
 	}
 }
+
+func TestLoginHistory(t *testing.T) {
+	mock := &mockUserStore{loginAttempts: []store.LoginAttempt{
+		{Ip: "192.0.2.1", UserAgent: "curl/8.0", Outcome: "failure"},
+	}}
+	svc := service.NewUserWithStore(mock)
+
+	history, err := svc.LoginHistory(context.Background(), uuid.New())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(history) != 1 || history[0].IP != "192.0.2.1" || history[0].Outcome != "failure" {
+		t.Fatalf("unexpected history %+v", history)
+	}
+
+	mock.loginAttempts = nil
+	history, err = svc.LoginHistory(context.Background(), uuid.New())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if history == nil || len(history) != 0 {
+		t.Fatalf("expected an empty, non-nil history, got %#v", history)
+	}
+}
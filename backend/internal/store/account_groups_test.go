@@ -0,0 +1,96 @@
+//go:build integration
+
+package store_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/store"
+)
+
+func TestAccountGroups_Order(t *testing.T) {
+	queries, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	suffix := uuid.New().String()[:8]
+	user, err := queries.CreateUser(ctx, store.CreateUserParams{
+		Username:     "testuser_" + suffix,
+		PasswordHash: "hashedpassword",
+		DisplayName:  "Test User",
+		BaseCurrency: "USD",
+		InviteCode:   "test-invite",
+	})
+	require.NoError(t, err)
+
+	armenia, err := queries.CreateAccountGroup(ctx, store.CreateAccountGroupParams{UserID: user.ID, Name: "Armenia"})
+	require.NoError(t, err)
+	emergency, err := queries.CreateAccountGroup(ctx, store.CreateAccountGroupParams{UserID: user.ID, Name: "Emergency fund"})
+	require.NoError(t, err)
+	require.Equal(t, int32(0), armenia.SortOrder)
+	require.Equal(t, int32(1), emergency.SortOrder)
+
+	account := func(name string, group store.AccountGroup) store.Account {
+		var groupID pgtype.UUID
+		if group.ID != uuid.Nil {
+			groupID = pgtype.UUID{Bytes: group.ID, Valid: true}
+		}
+		a, err := queries.CreateAccount(ctx, store.CreateAccountParams{
+			UserID:         user.ID,
+			Name:           name,
+			Type:           "deposit",
+			Currency:       "USD",
+			InitialBalance: numericFromInt(0),
+			GroupID:        groupID,
+		})
+		require.NoError(t, err)
+		return a
+	}
+	account("Wallet", store.AccountGroup{})
+	ameria := account("Ameria", armenia)
+	inecobank := account("Inecobank", armenia)
+	reserve := account("Reserve", emergency)
+	require.Equal(t, int32(3), reserve.SortOrder)
+
+	names := func() []string {
+		accounts, err := queries.ListAccounts(ctx, store.ListAccountsParams{UserID: user.ID})
+		require.NoError(t, err)
+		var out []string
+		for _, a := range accounts {
+			out = append(out, a.Name)
+		}
+		return out
+	}
+	require.Equal(t, []string{"Ameria", "Inecobank", "Reserve", "Wallet"}, names())
+
+	n, err := queries.ReorderAccountGroups(ctx, store.ReorderAccountGroupsParams{GroupIds: []uuid.UUID{emergency.ID, armenia.ID}, UserID: user.ID})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), n)
+	n, err = queries.ReorderAccounts(ctx, store.ReorderAccountsParams{AccountIds: []uuid.UUID{inecobank.ID, ameria.ID}, UserID: user.ID})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), n)
+	require.Equal(t, []string{"Reserve", "Inecobank", "Ameria", "Wallet"}, names())
+
+	// Duplicates or foreign ids change nothing.
+	n, err = queries.ReorderAccounts(ctx, store.ReorderAccountsParams{AccountIds: []uuid.UUID{ameria.ID, ameria.ID}, UserID: user.ID})
+	require.NoError(t, err)
+	require.Zero(t, n)
+	n, err = queries.ReorderAccounts(ctx, store.ReorderAccountsParams{AccountIds: []uuid.UUID{ameria.ID, uuid.New()}, UserID: user.ID})
+	require.NoError(t, err)
+	require.Zero(t, n)
+
+	// Deleting a group ungroups its accounts.
+	n, err = queries.DeleteAccountGroup(ctx, store.DeleteAccountGroupParams{ID: emergency.ID, UserID: user.ID})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), n)
+	got, err := queries.GetAccount(ctx, store.GetAccountParams{ID: reserve.ID, UserID: user.ID})
+	require.NoError(t, err)
+	require.False(t, got.GroupID.Valid)
+	require.Equal(t, []string{"Inecobank", "Ameria", "Wallet", "Reserve"}, names())
+}
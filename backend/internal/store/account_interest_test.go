@@ -0,0 +1,88 @@
+//go:build integration
+
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/store"
+)
+
+func TestAccountInterest_Accruals(t *testing.T) {
+	queries, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	suffix := uuid.New().String()[:8]
+	user, err := queries.CreateUser(ctx, store.CreateUserParams{
+		Username:     "testuser_" + suffix,
+		PasswordHash: "hashedpassword",
+		DisplayName:  "Test User",
+		BaseCurrency: "EUR",
+		InviteCode:   "test-invite",
+	})
+	require.NoError(t, err)
+
+	savings, err := queries.CreateAccount(ctx, store.CreateAccountParams{
+		UserID:         user.ID,
+		Name:           "Savings",
+		Type:           "deposit",
+		Currency:       "EUR",
+		InitialBalance: numericFromInt(10000),
+	})
+	require.NoError(t, err)
+
+	_, err = queries.UpsertAccountInterest(ctx, store.UpsertAccountInterestParams{
+		AccountID:   savings.ID,
+		UserID:      user.ID,
+		AnnualRate:  numericFromInt(3),
+		Compounding: "monthly",
+		StartDate:   pgtype.Date{Time: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+	})
+	require.NoError(t, err)
+
+	all, err := queries.ListAccountInterest(ctx)
+	require.NoError(t, err)
+	found := false
+	for _, terms := range all {
+		found = found || terms.AccountID == savings.ID
+	}
+	require.True(t, found)
+
+	through, err := queries.GetInterestAccruedThrough(ctx, savings.ID)
+	require.NoError(t, err)
+	require.False(t, through.Valid)
+
+	january := store.CreateInterestAccrualParams{
+		AccountID:   savings.ID,
+		UserID:      user.ID,
+		PeriodStart: pgtype.Date{Time: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+		PeriodEnd:   pgtype.Date{Time: time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC), Valid: true},
+		Amount:      numericFromInt(25),
+	}
+	_, err = queries.CreateInterestAccrual(ctx, january)
+	require.NoError(t, err)
+
+	through, err = queries.GetInterestAccruedThrough(ctx, savings.ID)
+	require.NoError(t, err)
+	require.Equal(t, january.PeriodEnd.Time, through.Time)
+
+	// A period is credited only once.
+	_, err = queries.CreateInterestAccrual(ctx, january)
+	require.Error(t, err)
+
+	// Removing the terms keeps the credited periods.
+	n, err := queries.DeleteAccountInterest(ctx, store.DeleteAccountInterestParams{AccountID: savings.ID, UserID: user.ID})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), n)
+	accruals, err := queries.ListInterestAccruals(ctx, store.ListInterestAccrualsParams{AccountID: savings.ID, UserID: user.ID})
+	require.NoError(t, err)
+	require.Len(t, accruals, 1)
+}
@@ -13,9 +13,9 @@ import (
 )
 
 const createAccount = `-- name: CreateAccount :one
-INSERT INTO accounts (user_id, name, type, currency, initial_balance)
-VALUES ($1, $2, $3, $4, $5)
-RETURNING id, user_id, name, type, currency, initial_balance, created_at, updated_at
+INSERT INTO accounts (user_id, name, type, currency, initial_balance, group_id, household_id, sort_order)
+VALUES ($1, $2, $3, $4, $5, $6, $7, (SELECT COALESCE(MAX(sort_order) + 1, 0) FROM accounts WHERE user_id = $1))
+RETURNING id, user_id, name, type, currency, initial_balance, created_at, updated_at, balance, archived_on, group_id, sort_order, household_id, deleted_at
 `
 
 type CreateAccountParams struct {
@@ -24,8 +24,11 @@ type CreateAccountParams struct {
 	Type           string         `json:"type"`
 	Currency       string         `json:"currency"`
 	InitialBalance pgtype.Numeric `json:"initial_balance"`
+	GroupID        pgtype.UUID    `json:"group_id"`
+	HouseholdID    pgtype.UUID    `json:"household_id"`
 }
 
+// New accounts go last in their user's order.
 func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error) {
 	row := q.db.QueryRow(ctx, createAccount,
 		arg.UserID,
@@ -33,6 +36,8 @@ func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (A
 		arg.Type,
 		arg.Currency,
 		arg.InitialBalance,
+		arg.GroupID,
+		arg.HouseholdID,
 	)
 	var i Account
 	err := row.Scan(
@@ -44,35 +49,69 @@ func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (A
 		&i.InitialBalance,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Balance,
+		&i.ArchivedOn,
+		&i.GroupID,
+		&i.SortOrder,
+		&i.HouseholdID,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
-const deleteAccount = `-- name: DeleteAccount :exec
-DELETE FROM accounts WHERE id = $1 AND user_id = $2
+const deleteAllUserAccounts = `-- name: DeleteAllUserAccounts :exec
+DELETE FROM accounts WHERE user_id = $1
+`
+
+func (q *Queries) DeleteAllUserAccounts(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteAllUserAccounts, userID)
+	return err
+}
+
+const getAccessibleAccount = `-- name: GetAccessibleAccount :one
+SELECT a.id, a.user_id, a.name, a.type, a.currency, a.initial_balance, a.created_at, a.updated_at, a.balance, a.archived_on, a.group_id, a.sort_order, a.household_id, a.deleted_at, aa.role
+FROM accounts a
+JOIN account_access aa ON aa.account_id = a.id
+WHERE a.id = $1 AND aa.user_id = $2
 `
 
-type DeleteAccountParams struct {
+type GetAccessibleAccountParams struct {
 	ID     uuid.UUID `json:"id"`
 	UserID uuid.UUID `json:"user_id"`
 }
 
-func (q *Queries) DeleteAccount(ctx context.Context, arg DeleteAccountParams) error {
-	_, err := q.db.Exec(ctx, deleteAccount, arg.ID, arg.UserID)
-	return err
+type GetAccessibleAccountRow struct {
+	Account Account `json:"account"`
+	Role    string  `json:"role"`
 }
 
-const deleteAllUserAccounts = `-- name: DeleteAllUserAccounts :exec
-DELETE FROM accounts WHERE user_id = $1
-`
-
-func (q *Queries) DeleteAllUserAccounts(ctx context.Context, userID uuid.UUID) error {
-	_, err := q.db.Exec(ctx, deleteAllUserAccounts, userID)
-	return err
+// Returns an account the user owns or shares through a household, with the
+// user's role on it.
+func (q *Queries) GetAccessibleAccount(ctx context.Context, arg GetAccessibleAccountParams) (GetAccessibleAccountRow, error) {
+	row := q.db.QueryRow(ctx, getAccessibleAccount, arg.ID, arg.UserID)
+	var i GetAccessibleAccountRow
+	err := row.Scan(
+		&i.Account.ID,
+		&i.Account.UserID,
+		&i.Account.Name,
+		&i.Account.Type,
+		&i.Account.Currency,
+		&i.Account.InitialBalance,
+		&i.Account.CreatedAt,
+		&i.Account.UpdatedAt,
+		&i.Account.Balance,
+		&i.Account.ArchivedOn,
+		&i.Account.GroupID,
+		&i.Account.SortOrder,
+		&i.Account.HouseholdID,
+		&i.Account.DeletedAt,
+		&i.Role,
+	)
+	return i, err
 }
 
 const getAccount = `-- name: GetAccount :one
-SELECT id, user_id, name, type, currency, initial_balance, created_at, updated_at FROM accounts WHERE id = $1 AND user_id = $2
+SELECT id, user_id, name, type, currency, initial_balance, created_at, updated_at, balance, archived_on, group_id, sort_order, household_id, deleted_at FROM accounts WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
 `
 
 type GetAccountParams struct {
@@ -92,12 +131,18 @@ func (q *Queries) GetAccount(ctx context.Context, arg GetAccountParams) (Account
 		&i.InitialBalance,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Balance,
+		&i.ArchivedOn,
+		&i.GroupID,
+		&i.SortOrder,
+		&i.HouseholdID,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
 const getAccountByName = `-- name: GetAccountByName :one
-SELECT id, user_id, name, type, currency, initial_balance, created_at, updated_at FROM accounts WHERE user_id = $1 AND name = $2
+SELECT id, user_id, name, type, currency, initial_balance, created_at, updated_at, balance, archived_on, group_id, sort_order, household_id, deleted_at FROM accounts WHERE user_id = $1 AND name = $2 AND deleted_at IS NULL
 `
 
 type GetAccountByNameParams struct {
@@ -117,44 +162,67 @@ func (q *Queries) GetAccountByName(ctx context.Context, arg GetAccountByNamePara
 		&i.InitialBalance,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Balance,
+		&i.ArchivedOn,
+		&i.GroupID,
+		&i.SortOrder,
+		&i.HouseholdID,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
-const getAccountTransactionSums = `-- name: GetAccountTransactionSums :one
-SELECT
-    COALESCE(SUM(CASE WHEN type = 'income' THEN amount ELSE 0 END), 0)::DECIMAL(15,2) AS total_income,
-    COALESCE(SUM(CASE WHEN type = 'expense' THEN amount ELSE 0 END), 0)::DECIMAL(15,2) AS total_expense
-FROM transactions
-WHERE account_id = $1
+const getAccountMarketValue = `-- name: GetAccountMarketValue :one
+SELECT COALESCE(SUM(h.quantity * q.price), 0)::DECIMAL(24,8) AS market_value
+FROM holdings h
+JOIN security_quotes q ON q.security_id = h.security_id
+WHERE h.account_id = $1 AND h.user_id = $2
 `
 
-type GetAccountTransactionSumsRow struct {
-	TotalIncome  pgtype.Numeric `json:"total_income"`
-	TotalExpense pgtype.Numeric `json:"total_expense"`
+type GetAccountMarketValueParams struct {
+	AccountID uuid.UUID `json:"account_id"`
+	UserID    uuid.UUID `json:"user_id"`
 }
 
-func (q *Queries) GetAccountTransactionSums(ctx context.Context, accountID uuid.UUID) (GetAccountTransactionSumsRow, error) {
-	row := q.db.QueryRow(ctx, getAccountTransactionSums, accountID)
-	var i GetAccountTransactionSumsRow
-	err := row.Scan(&i.TotalIncome, &i.TotalExpense)
-	return i, err
+func (q *Queries) GetAccountMarketValue(ctx context.Context, arg GetAccountMarketValueParams) (pgtype.Numeric, error) {
+	row := q.db.QueryRow(ctx, getAccountMarketValue, arg.AccountID, arg.UserID)
+	var market_value pgtype.Numeric
+	err := row.Scan(&market_value)
+	return market_value, err
 }
 
 const listAccounts = `-- name: ListAccounts :many
-SELECT a.id, a.user_id, a.name, a.type, a.currency, a.initial_balance, a.created_at, a.updated_at,
-  COALESCE(COUNT(t.id), 0)::INTEGER AS recent_tx_count
+SELECT a.id, a.user_id, a.name, a.type, a.currency, a.initial_balance, a.created_at, a.updated_at, a.balance, a.archived_on,
+  (CASE WHEN a.user_id = $1 THEN a.group_id END)::UUID AS group_id,
+  a.sort_order, a.household_id,
+  COALESCE(COUNT(t.id), 0)::INTEGER AS recent_tx_count,
+  COALESCE((
+    SELECT SUM(h.quantity * q.price)
+    FROM holdings h
+    JOIN security_quotes q ON q.security_id = h.security_id
+    WHERE h.account_id = a.id
+  ), 0)::DECIMAL(24,8) AS market_value,
+  aa.role
 FROM accounts a
+JOIN account_access aa ON aa.account_id = a.id AND aa.user_id = $1
+LEFT JOIN account_groups g ON g.id = a.group_id AND g.user_id = $1
 LEFT JOIN transactions t
   ON t.account_id = a.id
   AND t.type = 'expense'
   AND t.transfer_id IS NULL
+  AND NOT EXISTS (SELECT 1 FROM trades tr WHERE tr.transaction_id = t.id AND tr.type <> 'dividend')
   AND t.date >= CURRENT_DATE - INTERVAL '30 days'
-WHERE a.user_id = $1
-GROUP BY a.id
-ORDER BY name
+  AND t.deleted_at IS NULL
+WHERE ($2::boolean OR a.archived_on IS NULL)
+GROUP BY a.id, g.id, aa.role
+ORDER BY g.sort_order NULLS LAST, g.name, a.sort_order, a.name
 `
 
+type ListAccountsParams struct {
+	UserID          uuid.UUID `json:"user_id"`
+	IncludeArchived bool      `json:"include_archived"`
+}
+
 type ListAccountsRow struct {
 	ID             uuid.UUID          `json:"id"`
 	UserID         uuid.UUID          `json:"user_id"`
@@ -164,11 +232,24 @@ type ListAccountsRow struct {
 	InitialBalance pgtype.Numeric     `json:"initial_balance"`
 	CreatedAt      pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+	Balance        pgtype.Numeric     `json:"balance"`
+	ArchivedOn     pgtype.Date        `json:"archived_on"`
+	GroupID        pgtype.UUID        `json:"group_id"`
+	SortOrder      int32              `json:"sort_order"`
+	HouseholdID    pgtype.UUID        `json:"household_id"`
 	RecentTxCount  int32              `json:"recent_tx_count"`
+	MarketValue    pgtype.Numeric     `json:"market_value"`
+	Role           string             `json:"role"`
 }
 
-func (q *Queries) ListAccounts(ctx context.Context, userID uuid.UUID) ([]ListAccountsRow, error) {
-	rows, err := q.db.Query(ctx, listAccounts, userID)
+// Accounts the user can see, their own and those shared with them through a
+// household, come group by group in the groups' order, ungrouped ones last,
+// each in the accounts' own order. Groups are the user's own, so shared
+// accounts of other members are ungrouped. market_value is what the
+// account's securities are worth at their latest quotes; it is zero for
+// accounts without trades. role is the user's access to the account.
+func (q *Queries) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]ListAccountsRow, error) {
+	rows, err := q.db.Query(ctx, listAccounts, arg.UserID, arg.IncludeArchived)
 	if err != nil {
 		return nil, err
 	}
@@ -185,7 +266,14 @@ func (q *Queries) ListAccounts(ctx context.Context, userID uuid.UUID) ([]ListAcc
 			&i.InitialBalance,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Balance,
+			&i.ArchivedOn,
+			&i.GroupID,
+			&i.SortOrder,
+			&i.HouseholdID,
 			&i.RecentTxCount,
+			&i.MarketValue,
+			&i.Role,
 		); err != nil {
 			return nil, err
 		}
@@ -198,7 +286,7 @@ func (q *Queries) ListAccounts(ctx context.Context, userID uuid.UUID) ([]ListAcc
 }
 
 const listDistinctAccountCurrencies = `-- name: ListDistinctAccountCurrencies :many
-SELECT DISTINCT currency FROM accounts ORDER BY currency
+SELECT DISTINCT currency FROM accounts WHERE deleted_at IS NULL ORDER BY currency
 `
 
 // Intentionally not scoped to a user: exchange rates are global, and the sync
@@ -223,11 +311,285 @@ func (q *Queries) ListDistinctAccountCurrencies(ctx context.Context) ([]string,
 	return items, nil
 }
 
+const listTrashedAccounts = `-- name: ListTrashedAccounts :many
+SELECT id, user_id, name, type, currency, initial_balance, created_at, updated_at, balance, archived_on, group_id, sort_order, household_id, deleted_at FROM accounts
+WHERE user_id = $1 AND deleted_at IS NOT NULL
+ORDER BY deleted_at DESC, name
+`
+
+func (q *Queries) ListTrashedAccounts(ctx context.Context, userID uuid.UUID) ([]Account, error) {
+	rows, err := q.db.Query(ctx, listTrashedAccounts, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Account{}
+	for rows.Next() {
+		var i Account
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.Type,
+			&i.Currency,
+			&i.InitialBalance,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Balance,
+			&i.ArchivedOn,
+			&i.GroupID,
+			&i.SortOrder,
+			&i.HouseholdID,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const purgeAccounts = `-- name: PurgeAccounts :execrows
+DELETE FROM accounts WHERE deleted_at < $1
+`
+
+// Hard-deletes accounts trashed before the cutoff, cascading to whatever
+// still references them.
+func (q *Queries) PurgeAccounts(ctx context.Context, deletedAt pgtype.Timestamptz) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeAccounts, deletedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const reconcileAccountBalances = `-- name: ReconcileAccountBalances :many
+WITH computed AS (
+    SELECT a.id,
+        a.balance AS stored_balance,
+        a.initial_balance + COALESCE(SUM(CASE WHEN t.type = 'income' THEN t.amount ELSE -t.amount END), 0) AS computed_balance
+    FROM accounts a
+    LEFT JOIN transactions t ON t.account_id = a.id AND t.deleted_at IS NULL
+    WHERE a.user_id = $1 AND a.deleted_at IS NULL
+    GROUP BY a.id
+)
+UPDATE accounts a
+SET balance = c.computed_balance
+FROM computed c
+WHERE a.id = c.id AND a.balance <> c.computed_balance
+RETURNING a.id, a.name, a.currency,
+    c.stored_balance::DECIMAL(24,8) AS stored_balance,
+    a.balance
+`
+
+type ReconcileAccountBalancesRow struct {
+	ID            uuid.UUID      `json:"id"`
+	Name          string         `json:"name"`
+	Currency      string         `json:"currency"`
+	StoredBalance pgtype.Numeric `json:"stored_balance"`
+	Balance       pgtype.Numeric `json:"balance"`
+}
+
+// Recomputes the materialized balance of each of the user's accounts from its
+// transactions and fixes the ones that drifted, returning them with the
+// balance they had before.
+func (q *Queries) ReconcileAccountBalances(ctx context.Context, userID uuid.UUID) ([]ReconcileAccountBalancesRow, error) {
+	rows, err := q.db.Query(ctx, reconcileAccountBalances, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ReconcileAccountBalancesRow{}
+	for rows.Next() {
+		var i ReconcileAccountBalancesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Currency,
+			&i.StoredBalance,
+			&i.Balance,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const reorderAccounts = `-- name: ReorderAccounts :execrows
+UPDATE accounts a
+SET sort_order = o.position - 1, updated_at = now()
+FROM unnest($1::UUID[]) WITH ORDINALITY AS o(id, position)
+WHERE a.id = o.id AND a.user_id = $2 AND a.deleted_at IS NULL
+  AND (SELECT COUNT(*) FROM accounts WHERE user_id = $2 AND id = ANY($1) AND deleted_at IS NULL) = cardinality($1)
+`
+
+type ReorderAccountsParams struct {
+	AccountIds []uuid.UUID `json:"account_ids"`
+	UserID     uuid.UUID   `json:"user_id"`
+}
+
+// Sets each account's sort_order to its position in account_ids. Nothing
+// is updated unless the ids are distinct accounts of the user.
+func (q *Queries) ReorderAccounts(ctx context.Context, arg ReorderAccountsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, reorderAccounts, arg.AccountIds, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const restoreAccount = `-- name: RestoreAccount :one
+WITH account AS (
+    SELECT id, deleted_at FROM accounts
+    WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL
+), restored_transactions AS (
+    UPDATE transactions t SET deleted_at = (
+        SELECT oa.deleted_at FROM transactions o
+        JOIN accounts oa ON oa.id = o.account_id
+        WHERE oa.id <> account.id AND oa.deleted_at IS NOT NULL
+            AND (o.id = t.id OR o.transfer_id = t.transfer_id)
+        LIMIT 1
+    )
+    FROM account
+    WHERE t.user_id = $2 AND t.deleted_at = account.deleted_at
+    RETURNING t.id, t.deleted_at
+), versions AS (
+    INSERT INTO transaction_versions (transaction_id, actor_id, action)
+    SELECT id, $2, 'restore' FROM restored_transactions WHERE deleted_at IS NULL
+), restored AS (
+    UPDATE accounts a SET deleted_at = NULL
+    FROM account
+    WHERE a.id = account.id
+    RETURNING a.id, a.user_id, a.name, a.type, a.currency, a.initial_balance, a.created_at, a.updated_at, a.balance, a.archived_on, a.group_id, a.sort_order, a.household_id, a.deleted_at
+)
+SELECT id, user_id, name, type, currency, initial_balance, created_at, updated_at, balance, archived_on, group_id, sort_order, household_id, deleted_at FROM restored
+`
+
+type RestoreAccountParams struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+// Brings a trashed account back with the transactions trashed along with it.
+// A transfer whose other account has since been trashed too stays in the
+// trash with that account, to come back when it does. Each transaction
+// restored gets a restore version by the user.
+func (q *Queries) RestoreAccount(ctx context.Context, arg RestoreAccountParams) (Account, error) {
+	row := q.db.QueryRow(ctx, restoreAccount, arg.ID, arg.UserID)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.Type,
+		&i.Currency,
+		&i.InitialBalance,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Balance,
+		&i.ArchivedOn,
+		&i.GroupID,
+		&i.SortOrder,
+		&i.HouseholdID,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const setAccountArchived = `-- name: SetAccountArchived :one
+UPDATE accounts
+SET archived_on = $3, updated_at = now()
+WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+RETURNING id, user_id, name, type, currency, initial_balance, created_at, updated_at, balance, archived_on, group_id, sort_order, household_id, deleted_at
+`
+
+type SetAccountArchivedParams struct {
+	ID         uuid.UUID   `json:"id"`
+	UserID     uuid.UUID   `json:"user_id"`
+	ArchivedOn pgtype.Date `json:"archived_on"`
+}
+
+// Archives the account on the given date, or unarchives it when the date is
+// NULL.
+func (q *Queries) SetAccountArchived(ctx context.Context, arg SetAccountArchivedParams) (Account, error) {
+	row := q.db.QueryRow(ctx, setAccountArchived, arg.ID, arg.UserID, arg.ArchivedOn)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.Type,
+		&i.Currency,
+		&i.InitialBalance,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Balance,
+		&i.ArchivedOn,
+		&i.GroupID,
+		&i.SortOrder,
+		&i.HouseholdID,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const trashAccount = `-- name: TrashAccount :one
+WITH account AS (
+    UPDATE accounts SET deleted_at = now()
+    WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+    RETURNING id, deleted_at
+), transfers AS (
+    SELECT t.transfer_id FROM transactions t
+    JOIN account ON account.id = t.account_id
+    WHERE t.transfer_id IS NOT NULL AND t.deleted_at IS NULL
+), trashed AS (
+    UPDATE transactions t SET deleted_at = account.deleted_at
+    FROM account
+    WHERE t.deleted_at IS NULL
+        AND (t.account_id = account.id
+            OR t.transfer_id IN (SELECT transfer_id FROM transfers)
+            OR t.id IN (
+                SELECT lp.interest_id FROM loan_payments lp
+                JOIN transactions p ON p.id = lp.payment_id
+                WHERE p.transfer_id IN (SELECT transfer_id FROM transfers)
+            ))
+    RETURNING t.id
+), versions AS (
+    INSERT INTO transaction_versions (transaction_id, actor_id, action)
+    SELECT id, $2, 'delete' FROM trashed
+)
+SELECT deleted_at FROM account
+`
+
+type TrashAccountParams struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+// Moves the account and its transactions to the trash, all stamped with the
+// same deleted_at so RestoreAccount brings back exactly these. Transfers go
+// whole, with their other leg and a loan payment's interest, so no account
+// is left with half of one. Transactions trashed earlier keep their own
+// deleted_at. Each transaction trashed here gets a delete version by the user.
+func (q *Queries) TrashAccount(ctx context.Context, arg TrashAccountParams) (pgtype.Timestamptz, error) {
+	row := q.db.QueryRow(ctx, trashAccount, arg.ID, arg.UserID)
+	var deleted_at pgtype.Timestamptz
+	err := row.Scan(&deleted_at)
+	return deleted_at, err
+}
+
 const updateAccount = `-- name: UpdateAccount :one
 UPDATE accounts
-SET name = $2, type = $3, initial_balance = $4, updated_at = now()
-WHERE id = $1 AND user_id = $5
-RETURNING id, user_id, name, type, currency, initial_balance, created_at, updated_at
+SET name = $2, type = $3, initial_balance = $4, group_id = $5, household_id = $6, updated_at = now()
+WHERE id = $1 AND user_id = $7 AND deleted_at IS NULL
+RETURNING id, user_id, name, type, currency, initial_balance, created_at, updated_at, balance, archived_on, group_id, sort_order, household_id, deleted_at
 `
 
 type UpdateAccountParams struct {
@@ -235,6 +597,8 @@ type UpdateAccountParams struct {
 	Name           string         `json:"name"`
 	Type           string         `json:"type"`
 	InitialBalance pgtype.Numeric `json:"initial_balance"`
+	GroupID        pgtype.UUID    `json:"group_id"`
+	HouseholdID    pgtype.UUID    `json:"household_id"`
 	UserID         uuid.UUID      `json:"user_id"`
 }
 
@@ -244,6 +608,8 @@ func (q *Queries) UpdateAccount(ctx context.Context, arg UpdateAccountParams) (A
 		arg.Name,
 		arg.Type,
 		arg.InitialBalance,
+		arg.GroupID,
+		arg.HouseholdID,
 		arg.UserID,
 	)
 	var i Account
@@ -256,6 +622,12 @@ func (q *Queries) UpdateAccount(ctx context.Context, arg UpdateAccountParams) (A
 		&i.InitialBalance,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Balance,
+		&i.ArchivedOn,
+		&i.GroupID,
+		&i.SortOrder,
+		&i.HouseholdID,
+		&i.DeletedAt,
 	)
 	return i, err
 }
@@ -0,0 +1,360 @@
+//go:build integration
+
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/store"
+)
+
+func TestAccountBalance_MaintainedByTriggers(t *testing.T) {
+	queries, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	suffix := uuid.New().String()[:8]
+	user, err := queries.CreateUser(ctx, store.CreateUserParams{
+		Username:     "testuser_" + suffix,
+		PasswordHash: "hashedpassword",
+		DisplayName:  "Test User",
+		BaseCurrency: "USD",
+		InviteCode:   "test-invite",
+	})
+	require.NoError(t, err)
+
+	checking, err := queries.CreateAccount(ctx, store.CreateAccountParams{
+		UserID:         user.ID,
+		Name:           "Checking",
+		Type:           "deposit",
+		Currency:       "USD",
+		InitialBalance: numericFromInt(1000),
+	})
+	require.NoError(t, err)
+	savings, err := queries.CreateAccount(ctx, store.CreateAccountParams{
+		UserID:         user.ID,
+		Name:           "Savings",
+		Type:           "deposit",
+		Currency:       "USD",
+		InitialBalance: numericFromInt(0),
+	})
+	require.NoError(t, err)
+
+	balance := func(id uuid.UUID) int64 {
+		t.Helper()
+		a, err := queries.GetAccount(ctx, store.GetAccountParams{ID: id, UserID: user.ID})
+		require.NoError(t, err)
+		v, err := a.Balance.Int64Value()
+		require.NoError(t, err)
+		return v.Int64
+	}
+	require.Equal(t, int64(1000), balance(checking.ID))
+
+	txDate := pgtype.Date{Time: time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC), Valid: true}
+	tx, err := queries.CreateTransaction(ctx, store.CreateTransactionParams{
+		UserID:    user.ID,
+		AccountID: checking.ID,
+		Type:      "expense",
+		Amount:    numericFromInt(100),
+		Date:      txDate,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(900), balance(checking.ID))
+
+	// Moving the transaction and turning it into income updates both accounts.
+	_, err = queries.UpdateTransaction(ctx, store.UpdateTransactionParams{
+		ID:        tx.ID,
+		AccountID: savings.ID,
+		Type:      "income",
+		Amount:    numericFromInt(40),
+		Date:      txDate,
+		UserID:    user.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1000), balance(checking.ID))
+	require.Equal(t, int64(40), balance(savings.ID))
+
+	_, err = queries.UpdateAccount(ctx, store.UpdateAccountParams{
+		ID:             savings.ID,
+		Name:           "Savings",
+		Type:           "deposit",
+		InitialBalance: numericFromInt(10),
+		UserID:         user.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(50), balance(savings.ID))
+
+	err = queries.DeleteTransaction(ctx, store.DeleteTransactionParams{ID: tx.ID, UserID: user.ID})
+	require.NoError(t, err)
+	require.Equal(t, int64(10), balance(savings.ID))
+
+	drifted, err := queries.ReconcileAccountBalances(ctx, user.ID)
+	require.NoError(t, err)
+	require.Empty(t, drifted)
+}
+
+func TestListAccounts_HidesArchived(t *testing.T) {
+	queries, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	suffix := uuid.New().String()[:8]
+	user, err := queries.CreateUser(ctx, store.CreateUserParams{
+		Username:     "testuser_" + suffix,
+		PasswordHash: "hashedpassword",
+		DisplayName:  "Test User",
+		BaseCurrency: "USD",
+		InviteCode:   "test-invite",
+	})
+	require.NoError(t, err)
+
+	for _, name := range []string{"Checking", "Old Bank"} {
+		_, err := queries.CreateAccount(ctx, store.CreateAccountParams{
+			UserID:         user.ID,
+			Name:           name,
+			Type:           "deposit",
+			Currency:       "USD",
+			InitialBalance: numericFromInt(0),
+		})
+		require.NoError(t, err)
+	}
+	old, err := queries.GetAccountByName(ctx, store.GetAccountByNameParams{UserID: user.ID, Name: "Old Bank"})
+	require.NoError(t, err)
+
+	archived, err := queries.SetAccountArchived(ctx, store.SetAccountArchivedParams{
+		ID:         old.ID,
+		UserID:     user.ID,
+		ArchivedOn: pgtype.Date{Time: time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC), Valid: true},
+	})
+	require.NoError(t, err)
+	require.True(t, archived.ArchivedOn.Valid)
+
+	open, err := queries.ListAccounts(ctx, store.ListAccountsParams{UserID: user.ID})
+	require.NoError(t, err)
+	require.Len(t, open, 1)
+	require.Equal(t, "Checking", open[0].Name)
+
+	all, err := queries.ListAccounts(ctx, store.ListAccountsParams{UserID: user.ID, IncludeArchived: true})
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+
+	_, err = queries.SetAccountArchived(ctx, store.SetAccountArchivedParams{ID: old.ID, UserID: user.ID})
+	require.NoError(t, err)
+	open, err = queries.ListAccounts(ctx, store.ListAccountsParams{UserID: user.ID})
+	require.NoError(t, err)
+	require.Len(t, open, 2)
+}
+
+func TestTrashAccount_RestoresItsTransactions(t *testing.T) {
+	queries, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	suffix := uuid.New().String()[:8]
+	user, err := queries.CreateUser(ctx, store.CreateUserParams{
+		Username:     "testuser_" + suffix,
+		PasswordHash: "hashedpassword",
+		DisplayName:  "Test User",
+		BaseCurrency: "USD",
+		InviteCode:   "test-invite",
+	})
+	require.NoError(t, err)
+
+	checking, err := queries.CreateAccount(ctx, store.CreateAccountParams{
+		UserID:         user.ID,
+		Name:           "Checking",
+		Type:           "deposit",
+		Currency:       "USD",
+		InitialBalance: numericFromInt(1000),
+	})
+	require.NoError(t, err)
+
+	txDate := pgtype.Date{Time: time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC), Valid: true}
+	create := func(amount int64) store.Transaction {
+		t.Helper()
+		tx, err := queries.CreateTransaction(ctx, store.CreateTransactionParams{
+			UserID:    user.ID,
+			AccountID: checking.ID,
+			Type:      "expense",
+			Amount:    numericFromInt(amount),
+			Date:      txDate,
+		})
+		require.NoError(t, err)
+		return tx
+	}
+	balance := func() int64 {
+		t.Helper()
+		a, err := queries.GetAccount(ctx, store.GetAccountParams{ID: checking.ID, UserID: user.ID})
+		require.NoError(t, err)
+		v, err := a.Balance.Int64Value()
+		require.NoError(t, err)
+		return v.Int64
+	}
+
+	// A transaction trashed on its own stays in the trash when the account
+	// comes back.
+	earlier := create(100)
+	later := create(50)
+	_, err = queries.CreateBalanceAssertion(ctx, store.CreateBalanceAssertionParams{
+		UserID:    user.ID,
+		AccountID: checking.ID,
+		Date:      txDate,
+		Balance:   numericFromInt(850),
+	})
+	require.NoError(t, err)
+	require.NoError(t, queries.TrashTransaction(ctx, store.TrashTransactionParams{ID: earlier.ID, UserID: user.ID}))
+	require.Equal(t, int64(950), balance())
+
+	_, err = queries.TrashAccount(ctx, store.TrashAccountParams{ID: checking.ID, UserID: user.ID})
+	require.NoError(t, err)
+	_, err = queries.GetAccount(ctx, store.GetAccountParams{ID: checking.ID, UserID: user.ID})
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+
+	trashed, err := queries.ListTrashedTransactions(ctx, user.ID)
+	require.NoError(t, err)
+	require.Len(t, trashed, 1)
+	require.Equal(t, earlier.ID, trashed[0].Transaction.ID)
+	versions, err := queries.ListTransactionVersions(ctx, store.ListTransactionVersionsParams{TransactionID: later.ID})
+	require.NoError(t, err)
+	require.Len(t, versions, 1, "trashing the account records the delete")
+	require.Equal(t, "delete", versions[0].Action)
+	require.Equal(t, user.ID, versions[0].ActorID)
+	versions, err = queries.ListTransactionVersions(ctx, store.ListTransactionVersionsParams{TransactionID: earlier.ID})
+	require.NoError(t, err)
+	require.Empty(t, versions, "a transaction already in the trash isn't deleted again")
+	assertions, err := queries.ListBalanceAssertions(ctx, store.ListBalanceAssertionsParams{UserID: user.ID})
+	require.NoError(t, err)
+	require.Empty(t, assertions, "a trashed account's assertions are neither listed nor checked")
+
+	// The name is free while the account is in the trash, and restoring
+	// conflicts with the new account until that one is gone.
+	replacement, err := queries.CreateAccount(ctx, store.CreateAccountParams{
+		UserID:         user.ID,
+		Name:           "Checking",
+		Type:           "deposit",
+		Currency:       "USD",
+		InitialBalance: numericFromInt(0),
+	})
+	require.NoError(t, err)
+	_, err = queries.RestoreAccount(ctx, store.RestoreAccountParams{ID: checking.ID, UserID: user.ID})
+	require.Error(t, err)
+	_, err = queries.TrashAccount(ctx, store.TrashAccountParams{ID: replacement.ID, UserID: user.ID})
+	require.NoError(t, err)
+
+	restored, err := queries.RestoreAccount(ctx, store.RestoreAccountParams{ID: checking.ID, UserID: user.ID})
+	require.NoError(t, err)
+	require.False(t, restored.DeletedAt.Valid)
+	require.Equal(t, int64(950), balance())
+	assertions, err = queries.ListBalanceAssertions(ctx, store.ListBalanceAssertionsParams{UserID: user.ID})
+	require.NoError(t, err)
+	require.Len(t, assertions, 1)
+
+	row, err := queries.GetTrashedTransaction(ctx, store.GetTrashedTransactionParams{ID: earlier.ID, UserID: user.ID})
+	require.NoError(t, err)
+	require.True(t, row.Restorable)
+	n, err := queries.RestoreTransaction(ctx, store.RestoreTransactionParams{ID: earlier.ID, UserID: user.ID})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), n)
+	require.Equal(t, int64(850), balance())
+
+	purged, err := queries.PurgeAccounts(ctx, pgtype.Timestamptz{Time: time.Now().Add(time.Hour), Valid: true})
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, purged, int64(1))
+	_, err = queries.RestoreAccount(ctx, store.RestoreAccountParams{ID: replacement.ID, UserID: user.ID})
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+}
+
+func TestTrashAccount_TakesWholeTransfers(t *testing.T) {
+	queries, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	suffix := uuid.New().String()[:8]
+	user, err := queries.CreateUser(ctx, store.CreateUserParams{
+		Username:     "testuser_" + suffix,
+		PasswordHash: "hashedpassword",
+		DisplayName:  "Test User",
+		BaseCurrency: "USD",
+		InviteCode:   "test-invite",
+	})
+	require.NoError(t, err)
+
+	account := func(name string) store.Account {
+		t.Helper()
+		a, err := queries.CreateAccount(ctx, store.CreateAccountParams{
+			UserID:         user.ID,
+			Name:           name,
+			Type:           "deposit",
+			Currency:       "USD",
+			InitialBalance: numericFromInt(1000),
+		})
+		require.NoError(t, err)
+		return a
+	}
+	checking, savings := account("Checking"), account("Savings")
+
+	txDate := pgtype.Date{Time: time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC), Valid: true}
+	transferID := pgtype.UUID{Bytes: uuid.New(), Valid: true}
+	for _, leg := range []struct {
+		account uuid.UUID
+		typ     string
+	}{{checking.ID, "expense"}, {savings.ID, "income"}} {
+		_, err := queries.CreateTransaction(ctx, store.CreateTransactionParams{
+			UserID:     user.ID,
+			AccountID:  leg.account,
+			Type:       leg.typ,
+			Amount:     numericFromInt(200),
+			Date:       txDate,
+			TransferID: transferID,
+		})
+		require.NoError(t, err)
+	}
+	balance := func(id uuid.UUID) int64 {
+		t.Helper()
+		a, err := queries.GetAccount(ctx, store.GetAccountParams{ID: id, UserID: user.ID})
+		require.NoError(t, err)
+		v, err := a.Balance.Int64Value()
+		require.NoError(t, err)
+		return v.Int64
+	}
+	require.Equal(t, int64(1200), balance(savings.ID))
+
+	// Trashing checking takes the savings leg too, and neither leg is listed
+	// on its own: both come back with the account.
+	_, err = queries.TrashAccount(ctx, store.TrashAccountParams{ID: checking.ID, UserID: user.ID})
+	require.NoError(t, err)
+	require.Equal(t, int64(1000), balance(savings.ID))
+	trashed, err := queries.ListTrashedTransactions(ctx, user.ID)
+	require.NoError(t, err)
+	require.Empty(t, trashed)
+
+	_, err = queries.RestoreAccount(ctx, store.RestoreAccountParams{ID: checking.ID, UserID: user.ID})
+	require.NoError(t, err)
+	require.Equal(t, int64(800), balance(checking.ID))
+	require.Equal(t, int64(1200), balance(savings.ID))
+
+	// With savings trashed in between, restoring checking leaves the transfer
+	// in the trash until savings comes back.
+	_, err = queries.TrashAccount(ctx, store.TrashAccountParams{ID: checking.ID, UserID: user.ID})
+	require.NoError(t, err)
+	_, err = queries.TrashAccount(ctx, store.TrashAccountParams{ID: savings.ID, UserID: user.ID})
+	require.NoError(t, err)
+	_, err = queries.RestoreAccount(ctx, store.RestoreAccountParams{ID: checking.ID, UserID: user.ID})
+	require.NoError(t, err)
+	require.Equal(t, int64(1000), balance(checking.ID))
+	_, err = queries.RestoreAccount(ctx, store.RestoreAccountParams{ID: savings.ID, UserID: user.ID})
+	require.NoError(t, err)
+	require.Equal(t, int64(800), balance(checking.ID))
+	require.Equal(t, int64(1200), balance(savings.ID))
+}
@@ -0,0 +1,68 @@
+//go:build integration
+
+package store_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/store"
+)
+
+func TestAuditLog(t *testing.T) {
+	queries, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	user, err := queries.CreateUser(ctx, store.CreateUserParams{
+		InviteCode:   "test-invite",
+		Username:     "audit_" + uuid.New().String()[:8],
+		PasswordHash: "hashedpassword",
+		DisplayName:  "Audit",
+		BaseCurrency: "USD",
+	})
+	require.NoError(t, err)
+
+	for _, action := range []string{"auth.login", "account.delete", "account.delete"} {
+		require.NoError(t, queries.CreateAuditEntry(ctx, store.CreateAuditEntryParams{
+			UserID:    user.ID,
+			ActorID:   user.ID,
+			Action:    action,
+			Ip:        "192.0.2.1",
+			UserAgent: "test-agent",
+			Metadata:  []byte(`{"id":"x"}`),
+		}))
+	}
+
+	all, err := queries.ListAuditEntries(ctx, store.ListAuditEntriesParams{UserID: user.ID, Lim: 10})
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	require.Equal(t, "account.delete", all[0].Action)
+	require.JSONEq(t, `{"id":"x"}`, string(all[0].Metadata))
+
+	deletes := pgtype.Text{String: "account.delete", Valid: true}
+	n, err := queries.CountAuditEntries(ctx, store.CountAuditEntriesParams{UserID: user.ID, Action: deletes})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), n)
+
+	// Entries are append-only.
+	pool, err := pgxpool.New(ctx, os.Getenv("DATABASE_URL"))
+	require.NoError(t, err)
+	defer pool.Close()
+	_, err = pool.Exec(ctx, "UPDATE audit_log SET action = 'x' WHERE user_id = $1", user.ID)
+	require.ErrorContains(t, err, "append-only")
+
+	_, err = queries.PruneAuditLog(ctx, pgtype.Timestamptz{Time: time.Now().Add(time.Minute), Valid: true})
+	require.NoError(t, err)
+	n, err = queries.CountAuditEntries(ctx, store.CountAuditEntriesParams{UserID: user.ID})
+	require.NoError(t, err)
+	require.Zero(t, n)
+}
@@ -13,16 +13,17 @@ import (
 )
 
 const createCategory = `-- name: CreateCategory :one
-INSERT INTO categories (user_id, parent_id, name, type)
-VALUES ($1, $2, $3, $4)
-RETURNING id, user_id, parent_id, name, type, created_at
+INSERT INTO categories (user_id, parent_id, name, type, household_id)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, user_id, parent_id, name, type, created_at, household_id, deleted_at
 `
 
 type CreateCategoryParams struct {
-	UserID   uuid.UUID   `json:"user_id"`
-	ParentID pgtype.UUID `json:"parent_id"`
-	Name     string      `json:"name"`
-	Type     string      `json:"type"`
+	UserID      uuid.UUID   `json:"user_id"`
+	ParentID    pgtype.UUID `json:"parent_id"`
+	Name        string      `json:"name"`
+	Type        string      `json:"type"`
+	HouseholdID pgtype.UUID `json:"household_id"`
 }
 
 func (q *Queries) CreateCategory(ctx context.Context, arg CreateCategoryParams) (Category, error) {
@@ -31,6 +32,7 @@ func (q *Queries) CreateCategory(ctx context.Context, arg CreateCategoryParams)
 		arg.ParentID,
 		arg.Name,
 		arg.Type,
+		arg.HouseholdID,
 	)
 	var i Category
 	err := row.Scan(
@@ -40,6 +42,8 @@ func (q *Queries) CreateCategory(ctx context.Context, arg CreateCategoryParams)
 		&i.Name,
 		&i.Type,
 		&i.CreatedAt,
+		&i.HouseholdID,
+		&i.DeletedAt,
 	)
 	return i, err
 }
@@ -67,22 +71,8 @@ func (q *Queries) DeleteAllUserCategories(ctx context.Context, userID uuid.UUID)
 	return err
 }
 
-const deleteCategory = `-- name: DeleteCategory :exec
-DELETE FROM categories WHERE id = $1 AND user_id = $2
-`
-
-type DeleteCategoryParams struct {
-	ID     uuid.UUID `json:"id"`
-	UserID uuid.UUID `json:"user_id"`
-}
-
-func (q *Queries) DeleteCategory(ctx context.Context, arg DeleteCategoryParams) error {
-	_, err := q.db.Exec(ctx, deleteCategory, arg.ID, arg.UserID)
-	return err
-}
-
 const getCategory = `-- name: GetCategory :one
-SELECT id, user_id, parent_id, name, type, created_at FROM categories WHERE id = $1 AND user_id = $2
+SELECT id, user_id, parent_id, name, type, created_at, household_id, deleted_at FROM categories WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
 `
 
 type GetCategoryParams struct {
@@ -100,12 +90,14 @@ func (q *Queries) GetCategory(ctx context.Context, arg GetCategoryParams) (Categ
 		&i.Name,
 		&i.Type,
 		&i.CreatedAt,
+		&i.HouseholdID,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
 const getCategoryByNameAndType = `-- name: GetCategoryByNameAndType :one
-SELECT id, user_id, parent_id, name, type, created_at FROM categories WHERE user_id = $1 AND name = $2 AND type = $3 AND parent_id IS NULL
+SELECT id, user_id, parent_id, name, type, created_at, household_id, deleted_at FROM categories WHERE user_id = $1 AND name = $2 AND type = $3 AND parent_id IS NULL AND deleted_at IS NULL
 `
 
 type GetCategoryByNameAndTypeParams struct {
@@ -124,12 +116,14 @@ func (q *Queries) GetCategoryByNameAndType(ctx context.Context, arg GetCategoryB
 		&i.Name,
 		&i.Type,
 		&i.CreatedAt,
+		&i.HouseholdID,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
 const getSubcategoryByNameAndType = `-- name: GetSubcategoryByNameAndType :one
-SELECT id, user_id, parent_id, name, type, created_at FROM categories WHERE user_id = $1 AND name = $2 AND type = $3 AND parent_id = $4
+SELECT id, user_id, parent_id, name, type, created_at, household_id, deleted_at FROM categories WHERE user_id = $1 AND name = $2 AND type = $3 AND parent_id = $4 AND deleted_at IS NULL
 `
 
 type GetSubcategoryByNameAndTypeParams struct {
@@ -154,12 +148,50 @@ func (q *Queries) GetSubcategoryByNameAndType(ctx context.Context, arg GetSubcat
 		&i.Name,
 		&i.Type,
 		&i.CreatedAt,
+		&i.HouseholdID,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const getTrashedCategory = `-- name: GetTrashedCategory :one
+SELECT c.id, c.user_id, c.parent_id, c.name, c.type, c.created_at, c.household_id, c.deleted_at, COALESCE(p.deleted_at IS NOT NULL, false)::BOOLEAN AS parent_trashed
+FROM categories c
+LEFT JOIN categories p ON p.id = c.parent_id
+WHERE c.id = $1 AND c.user_id = $2 AND c.deleted_at IS NOT NULL
+`
+
+type GetTrashedCategoryParams struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+type GetTrashedCategoryRow struct {
+	Category      Category `json:"category"`
+	ParentTrashed bool     `json:"parent_trashed"`
+}
+
+// A trashed category with whether its parent is in the trash too, in which
+// case the parent has to be restored first.
+func (q *Queries) GetTrashedCategory(ctx context.Context, arg GetTrashedCategoryParams) (GetTrashedCategoryRow, error) {
+	row := q.db.QueryRow(ctx, getTrashedCategory, arg.ID, arg.UserID)
+	var i GetTrashedCategoryRow
+	err := row.Scan(
+		&i.Category.ID,
+		&i.Category.UserID,
+		&i.Category.ParentID,
+		&i.Category.Name,
+		&i.Category.Type,
+		&i.Category.CreatedAt,
+		&i.Category.HouseholdID,
+		&i.Category.DeletedAt,
+		&i.ParentTrashed,
 	)
 	return i, err
 }
 
 const hasCategoryTransactions = `-- name: HasCategoryTransactions :one
-SELECT EXISTS(SELECT 1 FROM transactions WHERE category_id = $1) AS has_transactions
+SELECT EXISTS(SELECT 1 FROM transactions WHERE category_id = $1 AND deleted_at IS NULL) AS has_transactions
 `
 
 func (q *Queries) HasCategoryTransactions(ctx context.Context, categoryID pgtype.UUID) (bool, error) {
@@ -170,7 +202,7 @@ func (q *Queries) HasCategoryTransactions(ctx context.Context, categoryID pgtype
 }
 
 const hasChildCategories = `-- name: HasChildCategories :one
-SELECT EXISTS(SELECT 1 FROM categories WHERE parent_id = $1) AS has_children
+SELECT EXISTS(SELECT 1 FROM categories WHERE parent_id = $1 AND deleted_at IS NULL) AS has_children
 `
 
 func (q *Queries) HasChildCategories(ctx context.Context, parentID pgtype.UUID) (bool, error) {
@@ -181,7 +213,7 @@ func (q *Queries) HasChildCategories(ctx context.Context, parentID pgtype.UUID)
 }
 
 const listCategories = `-- name: ListCategories :many
-SELECT c.id, c.user_id, c.parent_id, c.name, c.type, c.created_at,
+SELECT c.id, c.user_id, c.parent_id, c.name, c.type, c.created_at, c.household_id, c.deleted_at,
   COALESCE(COUNT(t.id), 0)::INTEGER AS recent_tx_count
 FROM categories c
 LEFT JOIN transactions t
@@ -189,7 +221,10 @@ LEFT JOIN transactions t
   AND t.type = 'expense'
   AND t.transfer_id IS NULL
   AND t.date >= CURRENT_DATE - INTERVAL '30 days'
-WHERE c.user_id = $1
+  AND t.deleted_at IS NULL
+WHERE (c.user_id = $1
+  OR c.household_id IN (SELECT household_id FROM household_members WHERE user_id = $1))
+  AND c.deleted_at IS NULL
 GROUP BY c.id
 ORDER BY c.type, c.name
 `
@@ -201,9 +236,12 @@ type ListCategoriesRow struct {
 	Name          string             `json:"name"`
 	Type          string             `json:"type"`
 	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	HouseholdID   pgtype.UUID        `json:"household_id"`
+	DeletedAt     pgtype.Timestamptz `json:"deleted_at"`
 	RecentTxCount int32              `json:"recent_tx_count"`
 }
 
+// The user's own categories and those shared with their households.
 func (q *Queries) ListCategories(ctx context.Context, userID uuid.UUID) ([]ListCategoriesRow, error) {
 	rows, err := q.db.Query(ctx, listCategories, userID)
 	if err != nil {
@@ -220,6 +258,8 @@ func (q *Queries) ListCategories(ctx context.Context, userID uuid.UUID) ([]ListC
 			&i.Name,
 			&i.Type,
 			&i.CreatedAt,
+			&i.HouseholdID,
+			&i.DeletedAt,
 			&i.RecentTxCount,
 		); err != nil {
 			return nil, err
@@ -232,18 +272,113 @@ func (q *Queries) ListCategories(ctx context.Context, userID uuid.UUID) ([]ListC
 	return items, nil
 }
 
+const listTrashedCategories = `-- name: ListTrashedCategories :many
+SELECT id, user_id, parent_id, name, type, created_at, household_id, deleted_at FROM categories
+WHERE user_id = $1 AND deleted_at IS NOT NULL
+ORDER BY deleted_at DESC, name
+`
+
+func (q *Queries) ListTrashedCategories(ctx context.Context, userID uuid.UUID) ([]Category, error) {
+	rows, err := q.db.Query(ctx, listTrashedCategories, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Category{}
+	for rows.Next() {
+		var i Category
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.ParentID,
+			&i.Name,
+			&i.Type,
+			&i.CreatedAt,
+			&i.HouseholdID,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const purgeCategories = `-- name: PurgeCategories :execrows
+DELETE FROM categories c
+WHERE c.deleted_at < $1
+    AND NOT EXISTS (SELECT 1 FROM transactions t WHERE t.category_id = c.id)
+    AND NOT EXISTS (SELECT 1 FROM categories s WHERE s.parent_id = c.id)
+`
+
+// Hard-deletes categories trashed before the cutoff. A category still used
+// by a transaction or subcategory waits until those are purged.
+func (q *Queries) PurgeCategories(ctx context.Context, deletedAt pgtype.Timestamptz) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeCategories, deletedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const restoreCategory = `-- name: RestoreCategory :one
+UPDATE categories SET deleted_at = NULL
+WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL
+RETURNING id, user_id, parent_id, name, type, created_at, household_id, deleted_at
+`
+
+type RestoreCategoryParams struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) RestoreCategory(ctx context.Context, arg RestoreCategoryParams) (Category, error) {
+	row := q.db.QueryRow(ctx, restoreCategory, arg.ID, arg.UserID)
+	var i Category
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.ParentID,
+		&i.Name,
+		&i.Type,
+		&i.CreatedAt,
+		&i.HouseholdID,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const trashCategory = `-- name: TrashCategory :exec
+UPDATE categories SET deleted_at = now()
+WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+`
+
+type TrashCategoryParams struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) TrashCategory(ctx context.Context, arg TrashCategoryParams) error {
+	_, err := q.db.Exec(ctx, trashCategory, arg.ID, arg.UserID)
+	return err
+}
+
 const updateCategory = `-- name: UpdateCategory :one
 UPDATE categories
-SET name = $2, parent_id = $3
-WHERE id = $1 AND user_id = $4
-RETURNING id, user_id, parent_id, name, type, created_at
+SET name = $2, parent_id = $3, household_id = $4
+WHERE id = $1 AND user_id = $5 AND deleted_at IS NULL
+RETURNING id, user_id, parent_id, name, type, created_at, household_id, deleted_at
 `
 
 type UpdateCategoryParams struct {
-	ID       uuid.UUID   `json:"id"`
-	Name     string      `json:"name"`
-	ParentID pgtype.UUID `json:"parent_id"`
-	UserID   uuid.UUID   `json:"user_id"`
+	ID          uuid.UUID   `json:"id"`
+	Name        string      `json:"name"`
+	ParentID    pgtype.UUID `json:"parent_id"`
+	HouseholdID pgtype.UUID `json:"household_id"`
+	UserID      uuid.UUID   `json:"user_id"`
 }
 
 func (q *Queries) UpdateCategory(ctx context.Context, arg UpdateCategoryParams) (Category, error) {
@@ -251,6 +386,7 @@ func (q *Queries) UpdateCategory(ctx context.Context, arg UpdateCategoryParams)
 		arg.ID,
 		arg.Name,
 		arg.ParentID,
+		arg.HouseholdID,
 		arg.UserID,
 	)
 	var i Category
@@ -261,6 +397,8 @@ func (q *Queries) UpdateCategory(ctx context.Context, arg UpdateCategoryParams)
 		&i.Name,
 		&i.Type,
 		&i.CreatedAt,
+		&i.HouseholdID,
+		&i.DeletedAt,
 	)
 	return i, err
 }
@@ -29,6 +29,7 @@ func (r *iteratorForBulkCreateTransactions) Next() bool {
 
 func (r iteratorForBulkCreateTransactions) Values() ([]interface{}, error) {
 	return []interface{}{
+		r.rows[0].ID,
 		r.rows[0].UserID,
 		r.rows[0].AccountID,
 		r.rows[0].CategoryID,
@@ -44,7 +45,7 @@ func (r iteratorForBulkCreateTransactions) Err() error {
 }
 
 func (q *Queries) BulkCreateTransactions(ctx context.Context, arg []BulkCreateTransactionsParams) (int64, error) {
-	return q.db.CopyFrom(ctx, []string{"transactions"}, []string{"user_id", "account_id", "category_id", "type", "amount", "description", "date"}, &iteratorForBulkCreateTransactions{rows: arg})
+	return q.db.CopyFrom(ctx, []string{"transactions"}, []string{"id", "user_id", "account_id", "category_id", "type", "amount", "description", "date"}, &iteratorForBulkCreateTransactions{rows: arg})
 }
 
 // iteratorForBulkCreateTransactionsFull implements pgx.CopyFromSource.
@@ -67,6 +68,7 @@ func (r *iteratorForBulkCreateTransactionsFull) Next() bool {
 
 func (r iteratorForBulkCreateTransactionsFull) Values() ([]interface{}, error) {
 	return []interface{}{
+		r.rows[0].ID,
 		r.rows[0].UserID,
 		r.rows[0].AccountID,
 		r.rows[0].CategoryID,
@@ -84,5 +86,40 @@ func (r iteratorForBulkCreateTransactionsFull) Err() error {
 }
 
 func (q *Queries) BulkCreateTransactionsFull(ctx context.Context, arg []BulkCreateTransactionsFullParams) (int64, error) {
-	return q.db.CopyFrom(ctx, []string{"transactions"}, []string{"user_id", "account_id", "category_id", "type", "amount", "description", "date", "transfer_id", "exchange_rate"}, &iteratorForBulkCreateTransactionsFull{rows: arg})
+	return q.db.CopyFrom(ctx, []string{"transactions"}, []string{"id", "user_id", "account_id", "category_id", "type", "amount", "description", "date", "transfer_id", "exchange_rate"}, &iteratorForBulkCreateTransactionsFull{rows: arg})
+}
+
+// iteratorForBulkCreateTransactionVersions implements pgx.CopyFromSource.
+type iteratorForBulkCreateTransactionVersions struct {
+	rows                 []BulkCreateTransactionVersionsParams
+	skippedFirstNextCall bool
+}
+
+func (r *iteratorForBulkCreateTransactionVersions) Next() bool {
+	if len(r.rows) == 0 {
+		return false
+	}
+	if !r.skippedFirstNextCall {
+		r.skippedFirstNextCall = true
+		return true
+	}
+	r.rows = r.rows[1:]
+	return len(r.rows) > 0
+}
+
+func (r iteratorForBulkCreateTransactionVersions) Values() ([]interface{}, error) {
+	return []interface{}{
+		r.rows[0].TransactionID,
+		r.rows[0].ActorID,
+		r.rows[0].Action,
+		r.rows[0].Changes,
+	}, nil
+}
+
+func (r iteratorForBulkCreateTransactionVersions) Err() error {
+	return nil
+}
+
+func (q *Queries) BulkCreateTransactionVersions(ctx context.Context, arg []BulkCreateTransactionVersionsParams) (int64, error) {
+	return q.db.CopyFrom(ctx, []string{"transaction_versions"}, []string{"transaction_id", "actor_id", "action", "changes"}, &iteratorForBulkCreateTransactionVersions{rows: arg})
 }
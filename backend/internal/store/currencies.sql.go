@@ -10,46 +10,67 @@ import (
 )
 
 const createCurrency = `-- name: CreateCurrency :one
-INSERT INTO currencies (code, name, symbol) VALUES ($1, $2, $3) RETURNING code, name, symbol
+INSERT INTO currencies (code, name, symbol, decimals) VALUES ($1, $2, $3, $4) RETURNING code, name, symbol, decimals
 `
 
 type CreateCurrencyParams struct {
-	Code   string `json:"code"`
-	Name   string `json:"name"`
-	Symbol string `json:"symbol"`
+	Code     string `json:"code"`
+	Name     string `json:"name"`
+	Symbol   string `json:"symbol"`
+	Decimals int16  `json:"decimals"`
 }
 
 func (q *Queries) CreateCurrency(ctx context.Context, arg CreateCurrencyParams) (Currency, error) {
-	row := q.db.QueryRow(ctx, createCurrency, arg.Code, arg.Name, arg.Symbol)
+	row := q.db.QueryRow(ctx, createCurrency,
+		arg.Code,
+		arg.Name,
+		arg.Symbol,
+		arg.Decimals,
+	)
 	var i Currency
-	err := row.Scan(&i.Code, &i.Name, &i.Symbol)
+	err := row.Scan(
+		&i.Code,
+		&i.Name,
+		&i.Symbol,
+		&i.Decimals,
+	)
 	return i, err
 }
 
 const getCurrency = `-- name: GetCurrency :one
-SELECT code, name, symbol FROM currencies WHERE code = $1
+SELECT code, name, symbol, decimals FROM currencies WHERE code = $1
 `
 
 func (q *Queries) GetCurrency(ctx context.Context, code string) (Currency, error) {
 	row := q.db.QueryRow(ctx, getCurrency, code)
 	var i Currency
-	err := row.Scan(&i.Code, &i.Name, &i.Symbol)
+	err := row.Scan(
+		&i.Code,
+		&i.Name,
+		&i.Symbol,
+		&i.Decimals,
+	)
 	return i, err
 }
 
 const getCurrencyBySymbol = `-- name: GetCurrencyBySymbol :one
-SELECT code, name, symbol FROM currencies WHERE symbol = $1
+SELECT code, name, symbol, decimals FROM currencies WHERE symbol = $1
 `
 
 func (q *Queries) GetCurrencyBySymbol(ctx context.Context, symbol string) (Currency, error) {
 	row := q.db.QueryRow(ctx, getCurrencyBySymbol, symbol)
 	var i Currency
-	err := row.Scan(&i.Code, &i.Name, &i.Symbol)
+	err := row.Scan(
+		&i.Code,
+		&i.Name,
+		&i.Symbol,
+		&i.Decimals,
+	)
 	return i, err
 }
 
 const listCurrencies = `-- name: ListCurrencies :many
-SELECT code, name, symbol FROM currencies ORDER BY code
+SELECT code, name, symbol, decimals FROM currencies ORDER BY code
 `
 
 func (q *Queries) ListCurrencies(ctx context.Context) ([]Currency, error) {
@@ -61,7 +82,12 @@ func (q *Queries) ListCurrencies(ctx context.Context) ([]Currency, error) {
 	items := []Currency{}
 	for rows.Next() {
 		var i Currency
-		if err := rows.Scan(&i.Code, &i.Name, &i.Symbol); err != nil {
+		if err := rows.Scan(
+			&i.Code,
+			&i.Name,
+			&i.Symbol,
+			&i.Decimals,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -73,7 +99,7 @@ func (q *Queries) ListCurrencies(ctx context.Context) ([]Currency, error) {
 }
 
 const updateCurrency = `-- name: UpdateCurrency :one
-UPDATE currencies SET name = $2 WHERE code = $1 RETURNING code, name, symbol
+UPDATE currencies SET name = $2 WHERE code = $1 RETURNING code, name, symbol, decimals
 `
 
 type UpdateCurrencyParams struct {
@@ -84,6 +110,11 @@ type UpdateCurrencyParams struct {
 func (q *Queries) UpdateCurrency(ctx context.Context, arg UpdateCurrencyParams) (Currency, error) {
 	row := q.db.QueryRow(ctx, updateCurrency, arg.Code, arg.Name)
 	var i Currency
-	err := row.Scan(&i.Code, &i.Name, &i.Symbol)
+	err := row.Scan(
+		&i.Code,
+		&i.Name,
+		&i.Symbol,
+		&i.Decimals,
+	)
 	return i, err
 }
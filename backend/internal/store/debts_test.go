@@ -0,0 +1,125 @@
+//go:build integration
+
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/store"
+)
+
+func TestDebts_Balances(t *testing.T) {
+	queries, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	suffix := uuid.New().String()[:8]
+	user, err := queries.CreateUser(ctx, store.CreateUserParams{
+		Username:     "testuser_" + suffix,
+		PasswordHash: "hashedpassword",
+		DisplayName:  "Test User",
+		BaseCurrency: "USD",
+		InviteCode:   "test-invite",
+	})
+	require.NoError(t, err)
+	account, err := queries.CreateAccount(ctx, store.CreateAccountParams{
+		UserID:         user.ID,
+		Name:           "Wallet",
+		Type:           "cash",
+		Currency:       "USD",
+		InitialBalance: numericFromInt(0),
+	})
+	require.NoError(t, err)
+	ann, err := queries.CreateContact(ctx, store.CreateContactParams{UserID: user.ID, Name: "Ann"})
+	require.NoError(t, err)
+
+	date := pgtype.Date{Time: time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC), Valid: true}
+	share := func(splitID uuid.UUID, contactID pgtype.UUID, amount int64) {
+		err := queries.CreateExpenseSplitShare(ctx, store.CreateExpenseSplitShareParams{SplitID: splitID, ContactID: contactID, Amount: numericFromInt(amount)})
+		require.NoError(t, err)
+	}
+	me := pgtype.UUID{}
+	annID := pgtype.UUID{Bytes: ann.ID, Valid: true}
+
+	// The user paid 90 for dinner; Ann owes her 30.
+	dinner, err := queries.CreateTransaction(ctx, store.CreateTransactionParams{
+		UserID:    user.ID,
+		AccountID: account.ID,
+		Type:      "expense",
+		Amount:    numericFromInt(90),
+		Date:      date,
+	})
+	require.NoError(t, err)
+	split, err := queries.CreateExpenseSplit(ctx, store.CreateExpenseSplitParams{
+		UserID:        user.ID,
+		TransactionID: pgtype.UUID{Bytes: dinner.ID, Valid: true},
+		Method:        "exact",
+		Date:          date,
+		Currency:      "USD",
+		Amount:        numericFromInt(90),
+	})
+	require.NoError(t, err)
+	share(split.ID, me, 60)
+	share(split.ID, annID, 30)
+
+	// Ann paid 40 for the taxi; the user owes her 20.
+	taxi, err := queries.CreateExpenseSplit(ctx, store.CreateExpenseSplitParams{
+		UserID:   user.ID,
+		PaidBy:   annID,
+		Method:   "equal",
+		Date:     date,
+		Currency: "USD",
+		Amount:   numericFromInt(40),
+	})
+	require.NoError(t, err)
+	share(taxi.ID, me, 20)
+	share(taxi.ID, annID, 20)
+
+	balance := func() int64 {
+		t.Helper()
+		b, err := queries.GetDebtBalance(ctx, store.GetDebtBalanceParams{UserID: user.ID, ContactID: annID, Currency: "USD"})
+		require.NoError(t, err)
+		v, err := b.Int64Value()
+		require.NoError(t, err)
+		return v.Int64
+	}
+	require.Equal(t, int64(10), balance())
+
+	balances, err := queries.ListDebtBalances(ctx, user.ID)
+	require.NoError(t, err)
+	require.Len(t, balances, 1)
+	require.Equal(t, ann.ID, balances[0].ContactID)
+
+	// Ann pays the 10 back; nothing is owed any more.
+	settlementID := uuid.New()
+	repayment, err := queries.CreateTransaction(ctx, store.CreateTransactionParams{
+		UserID:     user.ID,
+		AccountID:  account.ID,
+		Type:       "income",
+		Amount:     numericFromInt(10),
+		Date:       date,
+		TransferID: pgtype.UUID{Bytes: settlementID, Valid: true},
+	})
+	require.NoError(t, err)
+	_, err = queries.CreateDebtSettlement(ctx, store.CreateDebtSettlementParams{ID: settlementID, UserID: user.ID, ContactID: ann.ID, TransactionID: repayment.ID})
+	require.NoError(t, err)
+	require.Equal(t, int64(0), balance())
+	balances, err = queries.ListDebtBalances(ctx, user.ID)
+	require.NoError(t, err)
+	require.Empty(t, balances)
+
+	hasDebts, err := queries.HasContactDebts(ctx, ann.ID)
+	require.NoError(t, err)
+	require.True(t, hasDebts)
+
+	// Deleting the dinner deletes its split, so the user owes Ann again.
+	require.NoError(t, queries.DeleteTransaction(ctx, store.DeleteTransactionParams{ID: dinner.ID, UserID: user.ID}))
+	require.Equal(t, int64(-30), balance())
+}
@@ -8,12 +8,51 @@ package store
 import (
 	"context"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const deleteAllUserExchangeRates = `-- name: DeleteAllUserExchangeRates :exec
+DELETE FROM exchange_rates WHERE user_id = $1::uuid
+`
+
+func (q *Queries) DeleteAllUserExchangeRates(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteAllUserExchangeRates, userID)
+	return err
+}
+
+const deleteGlobalExchangeRate = `-- name: DeleteGlobalExchangeRate :execrows
+DELETE FROM exchange_rates WHERE id = $1 AND user_id IS NULL
+`
+
+func (q *Queries) DeleteGlobalExchangeRate(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteGlobalExchangeRate, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteUserExchangeRate = `-- name: DeleteUserExchangeRate :execrows
+DELETE FROM exchange_rates WHERE id = $1 AND user_id = $2::uuid
+`
+
+type DeleteUserExchangeRateParams struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) DeleteUserExchangeRate(ctx context.Context, arg DeleteUserExchangeRateParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteUserExchangeRate, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const getLatestRate = `-- name: GetLatestRate :one
-SELECT id, from_currency, to_currency, rate, date FROM exchange_rates
-WHERE from_currency = $1 AND to_currency = $2
+SELECT id, from_currency, to_currency, rate, date, source, user_id FROM exchange_rates
+WHERE from_currency = $1 AND to_currency = $2 AND user_id IS NULL
 ORDER BY date DESC
 LIMIT 1
 `
@@ -32,16 +71,59 @@ func (q *Queries) GetLatestRate(ctx context.Context, arg GetLatestRateParams) (E
 		&i.ToCurrency,
 		&i.Rate,
 		&i.Date,
+		&i.Source,
+		&i.UserID,
+	)
+	return i, err
+}
+
+const getRateOnOrBefore = `-- name: GetRateOnOrBefore :one
+SELECT id, from_currency, to_currency, rate, date, source, user_id FROM exchange_rates
+WHERE from_currency = $1 AND to_currency = $2 AND date <= $3
+    AND (user_id IS NULL OR user_id = $4::uuid)
+ORDER BY date DESC, user_id IS NULL
+LIMIT 1
+`
+
+type GetRateOnOrBeforeParams struct {
+	FromCurrency string      `json:"from_currency"`
+	ToCurrency   string      `json:"to_currency"`
+	Date         pgtype.Date `json:"date"`
+	UserID       uuid.UUID   `json:"user_id"`
+}
+
+// A personal rate wins over a global rate on the same date.
+// The latest rate on or before the date; on the same date the user's
+// personal rate wins over the global one.
+func (q *Queries) GetRateOnOrBefore(ctx context.Context, arg GetRateOnOrBeforeParams) (ExchangeRate, error) {
+	row := q.db.QueryRow(ctx, getRateOnOrBefore,
+		arg.FromCurrency,
+		arg.ToCurrency,
+		arg.Date,
+		arg.UserID,
+	)
+	var i ExchangeRate
+	err := row.Scan(
+		&i.ID,
+		&i.FromCurrency,
+		&i.ToCurrency,
+		&i.Rate,
+		&i.Date,
+		&i.Source,
+		&i.UserID,
 	)
 	return i, err
 }
 
 const listExchangeRates = `-- name: ListExchangeRates :many
-SELECT id, from_currency, to_currency, rate, date FROM exchange_rates ORDER BY date DESC, from_currency, to_currency
+SELECT id, from_currency, to_currency, rate, date, source, user_id FROM exchange_rates
+WHERE user_id IS NULL OR user_id = $1::uuid
+ORDER BY date DESC, from_currency, to_currency, user_id NULLS FIRST
 `
 
-func (q *Queries) ListExchangeRates(ctx context.Context) ([]ExchangeRate, error) {
-	rows, err := q.db.Query(ctx, listExchangeRates)
+// Global rates plus the user's personal overrides.
+func (q *Queries) ListExchangeRates(ctx context.Context, userID uuid.UUID) ([]ExchangeRate, error) {
+	rows, err := q.db.Query(ctx, listExchangeRates, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -55,6 +137,8 @@ func (q *Queries) ListExchangeRates(ctx context.Context) ([]ExchangeRate, error)
 			&i.ToCurrency,
 			&i.Rate,
 			&i.Date,
+			&i.Source,
+			&i.UserID,
 		); err != nil {
 			return nil, err
 		}
@@ -66,12 +150,90 @@ func (q *Queries) ListExchangeRates(ctx context.Context) ([]ExchangeRate, error)
 	return items, nil
 }
 
-const upsertExchangeRate = `-- name: UpsertExchangeRate :one
-INSERT INTO exchange_rates (from_currency, to_currency, rate, date)
-VALUES ($1, $2, $3, $4)
+const listMissingRateDates = `-- name: ListMissingRateDates :many
+SELECT DISTINCT a.currency AS from_currency, u.base_currency AS to_currency, t.date
+FROM transactions t
+JOIN accounts a ON a.id = t.account_id
+JOIN users u ON u.id = a.user_id
+WHERE a.currency <> u.base_currency
+    AND t.deleted_at IS NULL
+    AND NOT EXISTS (
+        SELECT 1 FROM exchange_rates er
+        WHERE er.from_currency = a.currency
+            AND er.to_currency = u.base_currency
+            AND er.date = t.date
+            AND er.user_id IS NULL
+    )
+    AND NOT EXISTS (
+        SELECT 1 FROM exchange_rate_misses m
+        WHERE m.from_currency = a.currency
+            AND m.to_currency = u.base_currency
+            AND m.date = t.date
+            AND m.attempts >= $1
+    )
+ORDER BY t.date, from_currency, to_currency
+LIMIT $2
+`
+
+type ListMissingRateDatesParams struct {
+	MaxAttempts int32 `json:"max_attempts"`
+	Lim         int32 `json:"lim"`
+}
+
+type ListMissingRateDatesRow struct {
+	FromCurrency string      `json:"from_currency"`
+	ToCurrency   string      `json:"to_currency"`
+	Date         pgtype.Date `json:"date"`
+}
+
+// Intentionally not scoped to a user: backfilled rates are global. Returns
+// every (account currency -> owner's base currency, date) combination that has
+// transactions but no stored global rate, oldest first. Combinations the
+// backfill already failed to find max_attempts times are left out.
+func (q *Queries) ListMissingRateDates(ctx context.Context, arg ListMissingRateDatesParams) ([]ListMissingRateDatesRow, error) {
+	rows, err := q.db.Query(ctx, listMissingRateDates, arg.MaxAttempts, arg.Lim)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListMissingRateDatesRow{}
+	for rows.Next() {
+		var i ListMissingRateDatesRow
+		if err := rows.Scan(&i.FromCurrency, &i.ToCurrency, &i.Date); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const recordRateMiss = `-- name: RecordRateMiss :exec
+INSERT INTO exchange_rate_misses (from_currency, to_currency, date)
+VALUES ($1, $2, $3)
 ON CONFLICT (from_currency, to_currency, date)
-DO UPDATE SET rate = EXCLUDED.rate
-RETURNING id, from_currency, to_currency, rate, date
+DO UPDATE SET attempts = exchange_rate_misses.attempts + 1, last_attempt_at = now()
+`
+
+type RecordRateMissParams struct {
+	FromCurrency string      `json:"from_currency"`
+	ToCurrency   string      `json:"to_currency"`
+	Date         pgtype.Date `json:"date"`
+}
+
+func (q *Queries) RecordRateMiss(ctx context.Context, arg RecordRateMissParams) error {
+	_, err := q.db.Exec(ctx, recordRateMiss, arg.FromCurrency, arg.ToCurrency, arg.Date)
+	return err
+}
+
+const upsertExchangeRate = `-- name: UpsertExchangeRate :one
+INSERT INTO exchange_rates (from_currency, to_currency, rate, date, source)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (from_currency, to_currency, date) WHERE user_id IS NULL
+DO UPDATE SET rate = EXCLUDED.rate, source = EXCLUDED.source
+RETURNING id, from_currency, to_currency, rate, date, source, user_id
 `
 
 type UpsertExchangeRateParams struct {
@@ -79,6 +241,7 @@ type UpsertExchangeRateParams struct {
 	ToCurrency   string         `json:"to_currency"`
 	Rate         pgtype.Numeric `json:"rate"`
 	Date         pgtype.Date    `json:"date"`
+	Source       string         `json:"source"`
 }
 
 func (q *Queries) UpsertExchangeRate(ctx context.Context, arg UpsertExchangeRateParams) (ExchangeRate, error) {
@@ -87,6 +250,46 @@ func (q *Queries) UpsertExchangeRate(ctx context.Context, arg UpsertExchangeRate
 		arg.ToCurrency,
 		arg.Rate,
 		arg.Date,
+		arg.Source,
+	)
+	var i ExchangeRate
+	err := row.Scan(
+		&i.ID,
+		&i.FromCurrency,
+		&i.ToCurrency,
+		&i.Rate,
+		&i.Date,
+		&i.Source,
+		&i.UserID,
+	)
+	return i, err
+}
+
+const upsertUserExchangeRate = `-- name: UpsertUserExchangeRate :one
+INSERT INTO exchange_rates (user_id, from_currency, to_currency, rate, date, source)
+VALUES ($1::uuid, $2, $3, $4, $5, $6)
+ON CONFLICT (user_id, from_currency, to_currency, date) WHERE user_id IS NOT NULL
+DO UPDATE SET rate = EXCLUDED.rate, source = EXCLUDED.source
+RETURNING id, from_currency, to_currency, rate, date, source, user_id
+`
+
+type UpsertUserExchangeRateParams struct {
+	UserID       uuid.UUID      `json:"user_id"`
+	FromCurrency string         `json:"from_currency"`
+	ToCurrency   string         `json:"to_currency"`
+	Rate         pgtype.Numeric `json:"rate"`
+	Date         pgtype.Date    `json:"date"`
+	Source       string         `json:"source"`
+}
+
+func (q *Queries) UpsertUserExchangeRate(ctx context.Context, arg UpsertUserExchangeRateParams) (ExchangeRate, error) {
+	row := q.db.QueryRow(ctx, upsertUserExchangeRate,
+		arg.UserID,
+		arg.FromCurrency,
+		arg.ToCurrency,
+		arg.Rate,
+		arg.Date,
+		arg.Source,
 	)
 	var i ExchangeRate
 	err := row.Scan(
@@ -95,6 +298,8 @@ func (q *Queries) UpsertExchangeRate(ctx context.Context, arg UpsertExchangeRate
 		&i.ToCurrency,
 		&i.Rate,
 		&i.Date,
+		&i.Source,
+		&i.UserID,
 	)
 	return i, err
 }
@@ -34,10 +34,12 @@ LEFT JOIN LATERAL (
     WHERE t2.transfer_id = t.transfer_id
         AND t2.id != t.id
         AND t.transfer_id IS NOT NULL
+        AND t2.deleted_at IS NULL
     LIMIT 1
 ) t2 ON true
 LEFT JOIN accounts ta ON t2.account_id = ta.id
 WHERE t.user_id = $1
+    AND t.deleted_at IS NULL
     AND t.date >= $2
     AND t.date <= $3
 ORDER BY t.date, t.created_at
@@ -0,0 +1,107 @@
+//go:build integration
+
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/store"
+)
+
+func TestHouseholds_SharedAccounts(t *testing.T) {
+	queries, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	suffix := uuid.New().String()[:8]
+	user := func(name string) store.User {
+		u, err := queries.CreateUser(ctx, store.CreateUserParams{
+			Username:     name + "_" + suffix,
+			PasswordHash: "hashedpassword",
+			DisplayName:  name,
+			BaseCurrency: "USD",
+			InviteCode:   "test-invite",
+		})
+		require.NoError(t, err)
+		return u
+	}
+	owner := user("owner")
+	member := user("member")
+	outsider := user("outsider")
+
+	household, err := queries.CreateHousehold(ctx, "Home")
+	require.NoError(t, err)
+	_, err = queries.AddHouseholdMember(ctx, store.AddHouseholdMemberParams{HouseholdID: household.ID, UserID: owner.ID, Role: "owner"})
+	require.NoError(t, err)
+	_, err = queries.AddHouseholdMember(ctx, store.AddHouseholdMemberParams{HouseholdID: household.ID, UserID: member.ID, Role: "editor"})
+	require.NoError(t, err)
+
+	group, err := queries.CreateAccountGroup(ctx, store.CreateAccountGroupParams{UserID: owner.ID, Name: "Family"})
+	require.NoError(t, err)
+	shared, err := queries.CreateAccount(ctx, store.CreateAccountParams{
+		UserID:         owner.ID,
+		Name:           "Joint",
+		Type:           "checking",
+		Currency:       "USD",
+		InitialBalance: numericFromInt(0),
+		GroupID:        pgtype.UUID{Bytes: group.ID, Valid: true},
+		HouseholdID:    pgtype.UUID{Bytes: household.ID, Valid: true},
+	})
+	require.NoError(t, err)
+	_, err = queries.CreateAccount(ctx, store.CreateAccountParams{
+		UserID:         owner.ID,
+		Name:           "Private",
+		Type:           "checking",
+		Currency:       "USD",
+		InitialBalance: numericFromInt(0),
+	})
+	require.NoError(t, err)
+
+	// The owner sees both accounts, the member only the shared one, ungrouped.
+	accounts, err := queries.ListAccounts(ctx, store.ListAccountsParams{UserID: owner.ID})
+	require.NoError(t, err)
+	require.Len(t, accounts, 2)
+	accounts, err = queries.ListAccounts(ctx, store.ListAccountsParams{UserID: member.ID})
+	require.NoError(t, err)
+	require.Len(t, accounts, 1)
+	require.Equal(t, shared.ID, accounts[0].ID)
+	require.Equal(t, "editor", accounts[0].Role)
+	require.False(t, accounts[0].GroupID.Valid)
+
+	// A transaction the member records belongs to the owner and shows for both.
+	txn, err := queries.CreateTransaction(ctx, store.CreateTransactionParams{
+		UserID:    owner.ID,
+		AccountID: shared.ID,
+		Type:      "expense",
+		Amount:    numericFromInt(25),
+		Date:      pgtype.Date{Time: time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC), Valid: true},
+		CreatedBy: pgtype.UUID{Bytes: member.ID, Valid: true},
+	})
+	require.NoError(t, err)
+	for _, u := range []store.User{owner, member} {
+		txns, err := queries.ListTransactions(ctx, store.ListTransactionsParams{UserID: u.ID, Lim: 10})
+		require.NoError(t, err)
+		require.Len(t, txns, 1)
+		require.Equal(t, txn.ID, txns[0].ID)
+	}
+
+	row, err := queries.GetAccessibleTransaction(ctx, store.GetAccessibleTransactionParams{ID: txn.ID, UserID: member.ID})
+	require.NoError(t, err)
+	require.Equal(t, "editor", row.Role)
+	_, err = queries.GetAccessibleTransaction(ctx, store.GetAccessibleTransactionParams{ID: txn.ID, UserID: outsider.ID})
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+
+	// Deleting the household unshares the account.
+	require.NoError(t, queries.DeleteHousehold(ctx, household.ID))
+	accounts, err = queries.ListAccounts(ctx, store.ListAccountsParams{UserID: member.ID})
+	require.NoError(t, err)
+	require.Empty(t, accounts)
+}
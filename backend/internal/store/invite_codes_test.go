@@ -0,0 +1,82 @@
+//go:build integration
+
+package store_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/store"
+)
+
+func TestCreateUser_RedeemsInviteCode(t *testing.T) {
+	queries, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	suffix := uuid.New().String()[:8]
+	register := func(name, code string) (store.User, error) {
+		return queries.CreateUser(ctx, store.CreateUserParams{
+			InviteCode:   code,
+			Username:     name + "_" + suffix,
+			PasswordHash: "hashedpassword",
+			DisplayName:  name,
+			BaseCurrency: "USD",
+		})
+	}
+
+	once, err := queries.CreateInviteCode(ctx, store.CreateInviteCodeParams{
+		Code:    "once_" + suffix,
+		MaxUses: pgtype.Int4{Int32: 1, Valid: true},
+	})
+	require.NoError(t, err)
+
+	ann, err := register("ann", once.Code)
+	require.NoError(t, err)
+	require.Equal(t, once.Code, ann.InviteCode.String)
+
+	_, err = register("bob", once.Code)
+	require.ErrorIs(t, err, pgx.ErrNoRows, "a used-up code can't be redeemed")
+
+	// A duplicate username fails the whole statement, so the use isn't counted.
+	twice, err := queries.CreateInviteCode(ctx, store.CreateInviteCodeParams{
+		Code:    "twice_" + suffix,
+		MaxUses: pgtype.Int4{Int32: 2, Valid: true},
+	})
+	require.NoError(t, err)
+	_, err = register("ann", twice.Code)
+	var pgErr *pgconn.PgError
+	require.True(t, errors.As(err, &pgErr) && pgErr.Code == "23505")
+	_, err = register("cara", twice.Code)
+	require.NoError(t, err)
+	_, err = register("dan", twice.Code)
+	require.NoError(t, err)
+
+	expired, err := queries.CreateInviteCode(ctx, store.CreateInviteCodeParams{
+		Code:      "expired_" + suffix,
+		ExpiresAt: pgtype.Timestamptz{Time: time.Now().Add(-time.Minute), Valid: true},
+	})
+	require.NoError(t, err)
+	_, err = register("eve", expired.Code)
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+
+	revoked, err := queries.CreateInviteCode(ctx, store.CreateInviteCodeParams{Code: "revoked_" + suffix})
+	require.NoError(t, err)
+	_, err = queries.RevokeInviteCode(ctx, revoked.Code)
+	require.NoError(t, err)
+	_, err = register("fay", revoked.Code)
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+
+	redemptions, err := queries.ListInviteRedemptions(ctx, []string{once.Code, twice.Code})
+	require.NoError(t, err)
+	require.Len(t, redemptions, 3)
+}
@@ -0,0 +1,134 @@
+//go:build integration
+
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/store"
+)
+
+func TestLoanPayments_SplitAndDelete(t *testing.T) {
+	queries, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	suffix := uuid.New().String()[:8]
+	user, err := queries.CreateUser(ctx, store.CreateUserParams{
+		Username:     "testuser_" + suffix,
+		PasswordHash: "hashedpassword",
+		DisplayName:  "Test User",
+		BaseCurrency: "USD",
+		InviteCode:   "test-invite",
+	})
+	require.NoError(t, err)
+
+	checking, err := queries.CreateAccount(ctx, store.CreateAccountParams{
+		UserID:         user.ID,
+		Name:           "Checking",
+		Type:           "deposit",
+		Currency:       "USD",
+		InitialBalance: numericFromInt(5000),
+	})
+	require.NoError(t, err)
+	mortgage, err := queries.CreateAccount(ctx, store.CreateAccountParams{
+		UserID:         user.ID,
+		Name:           "Mortgage",
+		Type:           "loan",
+		Currency:       "USD",
+		InitialBalance: numericFromInt(-10000),
+	})
+	require.NoError(t, err)
+
+	date := pgtype.Date{Time: time.Date(2026, 2, 15, 0, 0, 0, 0, time.UTC), Valid: true}
+	transferID := pgtype.UUID{Bytes: uuid.New(), Valid: true}
+	_, err = queries.CreateTransaction(ctx, store.CreateTransactionParams{
+		UserID:      user.ID,
+		AccountID:   checking.ID,
+		Type:        "expense",
+		Amount:      numericFromInt(500),
+		Description: "Mortgage payment",
+		Date:        date,
+		TransferID:  transferID,
+	})
+	require.NoError(t, err)
+	payment, err := queries.CreateTransaction(ctx, store.CreateTransactionParams{
+		UserID:      user.ID,
+		AccountID:   mortgage.ID,
+		Type:        "income",
+		Amount:      numericFromInt(500),
+		Description: "Mortgage payment",
+		Date:        date,
+		TransferID:  transferID,
+	})
+	require.NoError(t, err)
+
+	unsplit, err := queries.ListUnsplitLoanPayments(ctx, store.ListUnsplitLoanPaymentsParams{AccountID: mortgage.ID, UserID: user.ID})
+	require.NoError(t, err)
+	require.Len(t, unsplit, 1)
+	require.Equal(t, payment.ID, unsplit[0].ID)
+
+	interest, err := queries.CreateTransaction(ctx, store.CreateTransactionParams{
+		UserID:      user.ID,
+		AccountID:   mortgage.ID,
+		Type:        "expense",
+		Amount:      numericFromInt(100),
+		Description: "Loan interest",
+		Date:        date,
+	})
+	require.NoError(t, err)
+	_, err = queries.CreateLoanPayment(ctx, store.CreateLoanPaymentParams{
+		PaymentID:  payment.ID,
+		AccountID:  mortgage.ID,
+		UserID:     user.ID,
+		InterestID: pgtype.UUID{Bytes: interest.ID, Valid: true},
+		Principal:  numericFromInt(400),
+		Interest:   numericFromInt(100),
+	})
+	require.NoError(t, err)
+
+	unsplit, err = queries.ListUnsplitLoanPayments(ctx, store.ListUnsplitLoanPaymentsParams{AccountID: mortgage.ID, UserID: user.ID})
+	require.NoError(t, err)
+	require.Empty(t, unsplit)
+
+	totals, err := queries.GetLoanPaymentTotals(ctx, store.GetLoanPaymentTotalsParams{AccountID: mortgage.ID, UserID: user.ID})
+	require.NoError(t, err)
+	require.Equal(t, int32(1), totals.Count)
+
+	balance := func() int64 {
+		t.Helper()
+		a, err := queries.GetAccount(ctx, store.GetAccountParams{ID: mortgage.ID, UserID: user.ID})
+		require.NoError(t, err)
+		v, err := a.Balance.Int64Value()
+		require.NoError(t, err)
+		return v.Int64
+	}
+	require.Equal(t, int64(-9600), balance())
+
+	// Deleting the payment takes its interest transaction with it.
+	err = queries.TrashTransfer(ctx, store.TrashTransferParams{UserID: user.ID, TransferID: transferID})
+	require.NoError(t, err)
+	_, err = queries.GetTransaction(ctx, store.GetTransactionParams{ID: interest.ID, UserID: user.ID})
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+
+	require.Equal(t, int64(-10000), balance())
+	totals, err = queries.GetLoanPaymentTotals(ctx, store.GetLoanPaymentTotalsParams{AccountID: mortgage.ID, UserID: user.ID})
+	require.NoError(t, err)
+	require.Equal(t, int32(0), totals.Count)
+
+	// Restoring it brings the interest back too.
+	n, err := queries.RestoreTransaction(ctx, store.RestoreTransactionParams{ID: payment.ID, UserID: user.ID})
+	require.NoError(t, err)
+	require.Equal(t, int64(3), n)
+	_, err = queries.GetTransaction(ctx, store.GetTransactionParams{ID: interest.ID, UserID: user.ID})
+	require.NoError(t, err)
+	require.Equal(t, int64(-9600), balance())
+}
@@ -0,0 +1,76 @@
+//go:build integration
+
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/store"
+)
+
+func TestLoginAttempts(t *testing.T) {
+	queries, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	user, err := queries.CreateUser(ctx, store.CreateUserParams{
+		InviteCode:   "test-invite",
+		Username:     "login_" + uuid.New().String()[:8],
+		PasswordHash: "hashedpassword",
+		DisplayName:  "Login",
+		BaseCurrency: "USD",
+	})
+	require.NoError(t, err)
+	userID := pgtype.UUID{Bytes: user.ID, Valid: true}
+
+	record := func(outcome string) {
+		t.Helper()
+		require.NoError(t, queries.RecordLoginAttempt(ctx, store.RecordLoginAttemptParams{
+			Username:  user.Username,
+			UserID:    userID,
+			Ip:        "192.0.2.1",
+			UserAgent: "test-agent",
+			Outcome:   outcome,
+		}))
+	}
+	failures := func(since time.Time) store.GetLoginFailuresRow {
+		t.Helper()
+		row, err := queries.GetLoginFailures(ctx, store.GetLoginFailuresParams{
+			Username: user.Username,
+			Since:    pgtype.Timestamptz{Time: since, Valid: true},
+		})
+		require.NoError(t, err)
+		return row
+	}
+	dayAgo := time.Now().Add(-24 * time.Hour)
+
+	require.Equal(t, int64(0), failures(dayAgo).Failures)
+	require.False(t, failures(dayAgo).LastFailure.Valid)
+
+	record("failure")
+	record("failure")
+	record("locked")
+	got := failures(dayAgo)
+	require.Equal(t, int64(2), got.Failures, "locked attempts aren't failures")
+	require.True(t, got.LastFailure.Valid)
+	require.Equal(t, int64(0), failures(time.Now().Add(time.Minute)).Failures)
+
+	// A successful login resets the count.
+	record("success")
+	require.Equal(t, int64(0), failures(dayAgo).Failures)
+	record("failure")
+	require.Equal(t, int64(1), failures(dayAgo).Failures)
+
+	history, err := queries.ListLoginAttempts(ctx, store.ListLoginAttemptsParams{UserID: userID, Limit: 3})
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+	require.Equal(t, "failure", history[0].Outcome)
+	require.Equal(t, "success", history[1].Outcome)
+}
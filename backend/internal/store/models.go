@@ -18,21 +18,111 @@ type Account struct {
 	InitialBalance pgtype.Numeric     `json:"initial_balance"`
 	CreatedAt      pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+	Balance        pgtype.Numeric     `json:"balance"`
+	ArchivedOn     pgtype.Date        `json:"archived_on"`
+	GroupID        pgtype.UUID        `json:"group_id"`
+	SortOrder      int32              `json:"sort_order"`
+	HouseholdID    pgtype.UUID        `json:"household_id"`
+	DeletedAt      pgtype.Timestamptz `json:"deleted_at"`
+}
+
+type AccountAccess struct {
+	AccountID uuid.UUID `json:"account_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	Role      string    `json:"role"`
+}
+
+type AccountGroup struct {
+	ID        uuid.UUID          `json:"id"`
+	UserID    uuid.UUID          `json:"user_id"`
+	Name      string             `json:"name"`
+	SortOrder int32              `json:"sort_order"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+}
+
+type AccountInterest struct {
+	AccountID       uuid.UUID          `json:"account_id"`
+	UserID          uuid.UUID          `json:"user_id"`
+	AnnualRate      pgtype.Numeric     `json:"annual_rate"`
+	Compounding     string             `json:"compounding"`
+	StartDate       pgtype.Date        `json:"start_date"`
+	PayoutAccountID pgtype.UUID        `json:"payout_account_id"`
+	CategoryID      pgtype.UUID        `json:"category_id"`
+	UpdatedAt       pgtype.Timestamptz `json:"updated_at"`
+}
+
+type AuditLog struct {
+	ID        uuid.UUID          `json:"id"`
+	UserID    uuid.UUID          `json:"user_id"`
+	ActorID   uuid.UUID          `json:"actor_id"`
+	Action    string             `json:"action"`
+	Ip        string             `json:"ip"`
+	UserAgent string             `json:"user_agent"`
+	Metadata  []byte             `json:"metadata"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type BalanceAssertion struct {
+	ID        uuid.UUID          `json:"id"`
+	UserID    uuid.UUID          `json:"user_id"`
+	AccountID uuid.UUID          `json:"account_id"`
+	Date      pgtype.Date        `json:"date"`
+	Balance   pgtype.Numeric     `json:"balance"`
+	Note      string             `json:"note"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
 }
 
 type Category struct {
+	ID          uuid.UUID          `json:"id"`
+	UserID      uuid.UUID          `json:"user_id"`
+	ParentID    pgtype.UUID        `json:"parent_id"`
+	Name        string             `json:"name"`
+	Type        string             `json:"type"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	HouseholdID pgtype.UUID        `json:"household_id"`
+	DeletedAt   pgtype.Timestamptz `json:"deleted_at"`
+}
+
+type Contact struct {
 	ID        uuid.UUID          `json:"id"`
 	UserID    uuid.UUID          `json:"user_id"`
-	ParentID  pgtype.UUID        `json:"parent_id"`
 	Name      string             `json:"name"`
-	Type      string             `json:"type"`
 	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+}
+
+type CreditCard struct {
+	AccountID         uuid.UUID          `json:"account_id"`
+	UserID            uuid.UUID          `json:"user_id"`
+	CreditLimit       pgtype.Numeric     `json:"credit_limit"`
+	StatementDay      int16              `json:"statement_day"`
+	PaymentDueDay     int16              `json:"payment_due_day"`
+	MinPaymentPercent pgtype.Numeric     `json:"min_payment_percent"`
+	MinPaymentAmount  pgtype.Numeric     `json:"min_payment_amount"`
+	UpdatedAt         pgtype.Timestamptz `json:"updated_at"`
 }
 
 type Currency struct {
-	Code   string `json:"code"`
-	Name   string `json:"name"`
-	Symbol string `json:"symbol"`
+	Code     string `json:"code"`
+	Name     string `json:"name"`
+	Symbol   string `json:"symbol"`
+	Decimals int16  `json:"decimals"`
+}
+
+type DebtEntry struct {
+	UserID    uuid.UUID      `json:"user_id"`
+	ContactID pgtype.UUID    `json:"contact_id"`
+	Currency  string         `json:"currency"`
+	Amount    pgtype.Numeric `json:"amount"`
+}
+
+type DebtSettlement struct {
+	ID            uuid.UUID          `json:"id"`
+	UserID        uuid.UUID          `json:"user_id"`
+	ContactID     uuid.UUID          `json:"contact_id"`
+	TransactionID uuid.UUID          `json:"transaction_id"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
 }
 
 type ExchangeRate struct {
@@ -41,6 +131,147 @@ type ExchangeRate struct {
 	ToCurrency   string         `json:"to_currency"`
 	Rate         pgtype.Numeric `json:"rate"`
 	Date         pgtype.Date    `json:"date"`
+	Source       string         `json:"source"`
+	UserID       pgtype.UUID    `json:"user_id"`
+}
+
+type ExpenseSplit struct {
+	ID            uuid.UUID          `json:"id"`
+	UserID        uuid.UUID          `json:"user_id"`
+	TransactionID pgtype.UUID        `json:"transaction_id"`
+	PaidBy        pgtype.UUID        `json:"paid_by"`
+	Method        string             `json:"method"`
+	Description   string             `json:"description"`
+	Date          pgtype.Date        `json:"date"`
+	Currency      string             `json:"currency"`
+	Amount        pgtype.Numeric     `json:"amount"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+}
+
+type ExpenseSplitShare struct {
+	SplitID   uuid.UUID      `json:"split_id"`
+	ContactID pgtype.UUID    `json:"contact_id"`
+	Amount    pgtype.Numeric `json:"amount"`
+}
+
+type Holding struct {
+	UserID     uuid.UUID      `json:"user_id"`
+	AccountID  uuid.UUID      `json:"account_id"`
+	SecurityID uuid.UUID      `json:"security_id"`
+	Quantity   pgtype.Numeric `json:"quantity"`
+}
+
+type Household struct {
+	ID        uuid.UUID          `json:"id"`
+	Name      string             `json:"name"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+}
+
+type HouseholdMember struct {
+	HouseholdID uuid.UUID          `json:"household_id"`
+	UserID      uuid.UUID          `json:"user_id"`
+	Role        string             `json:"role"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+}
+
+type InterestAccrual struct {
+	AccountID     uuid.UUID          `json:"account_id"`
+	UserID        uuid.UUID          `json:"user_id"`
+	PeriodStart   pgtype.Date        `json:"period_start"`
+	PeriodEnd     pgtype.Date        `json:"period_end"`
+	Amount        pgtype.Numeric     `json:"amount"`
+	TransactionID pgtype.UUID        `json:"transaction_id"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+}
+
+type InviteCode struct {
+	Code      string             `json:"code"`
+	CreatedBy pgtype.UUID        `json:"created_by"`
+	MaxUses   pgtype.Int4        `json:"max_uses"`
+	Uses      int32              `json:"uses"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+	RevokedAt pgtype.Timestamptz `json:"revoked_at"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type Loan struct {
+	AccountID          uuid.UUID          `json:"account_id"`
+	UserID             uuid.UUID          `json:"user_id"`
+	Principal          pgtype.Numeric     `json:"principal"`
+	AnnualRate         pgtype.Numeric     `json:"annual_rate"`
+	TermMonths         int32              `json:"term_months"`
+	StartDate          pgtype.Date        `json:"start_date"`
+	PaymentDay         int16              `json:"payment_day"`
+	InterestCategoryID pgtype.UUID        `json:"interest_category_id"`
+	UpdatedAt          pgtype.Timestamptz `json:"updated_at"`
+}
+
+type LoanPayment struct {
+	PaymentID  uuid.UUID          `json:"payment_id"`
+	AccountID  uuid.UUID          `json:"account_id"`
+	UserID     uuid.UUID          `json:"user_id"`
+	InterestID pgtype.UUID        `json:"interest_id"`
+	Principal  pgtype.Numeric     `json:"principal"`
+	Interest   pgtype.Numeric     `json:"interest"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+}
+
+type LoginAttempt struct {
+	ID        uuid.UUID          `json:"id"`
+	Username  string             `json:"username"`
+	UserID    pgtype.UUID        `json:"user_id"`
+	Ip        string             `json:"ip"`
+	UserAgent string             `json:"user_agent"`
+	Outcome   string             `json:"outcome"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type Reconciliation struct {
+	ID               uuid.UUID          `json:"id"`
+	UserID           uuid.UUID          `json:"user_id"`
+	AccountID        uuid.UUID          `json:"account_id"`
+	StatementDate    pgtype.Date        `json:"statement_date"`
+	StatementBalance pgtype.Numeric     `json:"statement_balance"`
+	ReconciledCount  int32              `json:"reconciled_count"`
+	FinishedAt       pgtype.Timestamptz `json:"finished_at"`
+	CreatedAt        pgtype.Timestamptz `json:"created_at"`
+}
+
+type Security struct {
+	ID        uuid.UUID          `json:"id"`
+	UserID    uuid.UUID          `json:"user_id"`
+	Symbol    string             `json:"symbol"`
+	Name      string             `json:"name"`
+	Currency  string             `json:"currency"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type SecurityPrice struct {
+	SecurityID uuid.UUID      `json:"security_id"`
+	Date       pgtype.Date    `json:"date"`
+	Price      pgtype.Numeric `json:"price"`
+	Source     string         `json:"source"`
+}
+
+type SecurityQuote struct {
+	SecurityID uuid.UUID      `json:"security_id"`
+	Date       pgtype.Date    `json:"date"`
+	Price      pgtype.Numeric `json:"price"`
+}
+
+type Trade struct {
+	ID            uuid.UUID          `json:"id"`
+	UserID        uuid.UUID          `json:"user_id"`
+	AccountID     uuid.UUID          `json:"account_id"`
+	SecurityID    uuid.UUID          `json:"security_id"`
+	Type          string             `json:"type"`
+	Date          pgtype.Date        `json:"date"`
+	Quantity      pgtype.Numeric     `json:"quantity"`
+	Price         pgtype.Numeric     `json:"price"`
+	Fee           pgtype.Numeric     `json:"fee"`
+	TransactionID uuid.UUID          `json:"transaction_id"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
 }
 
 type Transaction struct {
@@ -56,6 +287,18 @@ type Transaction struct {
 	ExchangeRate pgtype.Numeric     `json:"exchange_rate"`
 	CreatedAt    pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
+	Status       string             `json:"status"`
+	CreatedBy    pgtype.UUID        `json:"created_by"`
+	DeletedAt    pgtype.Timestamptz `json:"deleted_at"`
+}
+
+type TransactionVersion struct {
+	ID            uuid.UUID          `json:"id"`
+	TransactionID uuid.UUID          `json:"transaction_id"`
+	ActorID       uuid.UUID          `json:"actor_id"`
+	Action        string             `json:"action"`
+	Changes       []byte             `json:"changes"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
 }
 
 type User struct {
@@ -67,4 +310,6 @@ type User struct {
 	CreatedAt    pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
 	InviteCode   pgtype.Text        `json:"invite_code"`
+	IsAdmin      bool               `json:"is_admin"`
+	DisabledAt   pgtype.Timestamptz `json:"disabled_at"`
 }
@@ -0,0 +1,136 @@
+//go:build integration
+
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/store"
+)
+
+func TestTrades_MarketValue(t *testing.T) {
+	queries, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	suffix := uuid.New().String()[:8]
+	user, err := queries.CreateUser(ctx, store.CreateUserParams{
+		Username:     "testuser_" + suffix,
+		PasswordHash: "hashedpassword",
+		DisplayName:  "Test User",
+		BaseCurrency: "USD",
+		InviteCode:   "test-invite",
+	})
+	require.NoError(t, err)
+
+	brokerage, err := queries.CreateAccount(ctx, store.CreateAccountParams{
+		UserID:         user.ID,
+		Name:           "Brokerage",
+		Type:           "investment",
+		Currency:       "USD",
+		InitialBalance: numericFromInt(5000),
+	})
+	require.NoError(t, err)
+	sec, err := queries.CreateSecurity(ctx, store.CreateSecurityParams{
+		UserID:   user.ID,
+		Symbol:   "AAPL.US",
+		Name:     "Apple",
+		Currency: "USD",
+	})
+	require.NoError(t, err)
+
+	trade := func(typ string, date time.Time, quantity, price, amount int64) store.Trade {
+		txType := "expense"
+		if typ == "sell" {
+			txType = "income"
+		}
+		txn, err := queries.CreateTransaction(ctx, store.CreateTransactionParams{
+			UserID:      user.ID,
+			AccountID:   brokerage.ID,
+			Type:        txType,
+			Amount:      numericFromInt(amount),
+			Description: typ + " AAPL.US",
+			Date:        pgtype.Date{Time: date, Valid: true},
+		})
+		require.NoError(t, err)
+		tr, err := queries.CreateTrade(ctx, store.CreateTradeParams{
+			ID:            uuid.New(),
+			UserID:        user.ID,
+			AccountID:     brokerage.ID,
+			SecurityID:    sec.ID,
+			Type:          typ,
+			Date:          pgtype.Date{Time: date, Valid: true},
+			Quantity:      numericFromInt(quantity),
+			Price:         numericFromInt(price),
+			Fee:           numericFromInt(0),
+			TransactionID: txn.ID,
+		})
+		require.NoError(t, err)
+		return tr
+	}
+	marketValue := func() int64 {
+		mv, err := queries.GetAccountMarketValue(ctx, store.GetAccountMarketValueParams{AccountID: brokerage.ID, UserID: user.ID})
+		require.NoError(t, err)
+		v, err := mv.Int64Value()
+		require.NoError(t, err)
+		return v.Int64
+	}
+
+	require.Equal(t, int64(0), marketValue())
+
+	// Without stored prices the latest trade price is the quote.
+	trade("buy", time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC), 10, 150, 1500)
+	sell := trade("sell", time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC), 4, 180, 720)
+	require.Equal(t, int64(6*180), marketValue())
+
+	_, err = queries.UpsertSecurityPrice(ctx, store.UpsertSecurityPriceParams{
+		SecurityID: sec.ID,
+		Date:       pgtype.Date{Time: time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC), Valid: true},
+		Price:      numericFromInt(200),
+		Source:     "manual",
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(6*200), marketValue())
+
+	accounts, err := queries.ListAccounts(ctx, store.ListAccountsParams{UserID: user.ID})
+	require.NoError(t, err)
+	require.Len(t, accounts, 1)
+	listed, err := accounts[0].MarketValue.Int64Value()
+	require.NoError(t, err)
+	require.Equal(t, int64(1200), listed.Int64)
+
+	// Trade cash moves money into securities; it is neither income nor
+	// expense.
+	summary, err := queries.DashboardSummary(ctx, store.DashboardSummaryParams{
+		UserID:   user.ID,
+		DateFrom: pgtype.Date{Time: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+		DateTo:   pgtype.Date{Time: time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC), Valid: true},
+	})
+	require.NoError(t, err)
+	for _, total := range []pgtype.Numeric{summary.TotalIncome, summary.TotalExpense} {
+		v, err := total.Int64Value()
+		require.NoError(t, err)
+		require.Equal(t, int64(0), v.Int64)
+	}
+	isTrade, err := queries.IsTradeTransaction(ctx, sell.TransactionID)
+	require.NoError(t, err)
+	require.True(t, isTrade)
+
+	has, err := queries.HasSecurityTrades(ctx, sec.ID)
+	require.NoError(t, err)
+	require.True(t, has)
+
+	// Deleting the cash transaction deletes the trade.
+	require.NoError(t, queries.DeleteTransaction(ctx, store.DeleteTransactionParams{ID: sell.TransactionID, UserID: user.ID}))
+	trades, err := queries.ListTrades(ctx, store.ListTradesParams{AccountID: brokerage.ID, UserID: user.ID})
+	require.NoError(t, err)
+	require.Len(t, trades, 1)
+	require.Equal(t, int64(10*200), marketValue())
+}
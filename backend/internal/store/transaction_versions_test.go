@@ -0,0 +1,140 @@
+//go:build integration
+
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sanches/finance-tracker-cc/backend/internal/store"
+)
+
+func TestTransactionVersions_CoverTransferLegs(t *testing.T) {
+	queries, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	user, err := queries.CreateUser(ctx, store.CreateUserParams{
+		InviteCode:   "test-invite",
+		Username:     "history_" + uuid.New().String()[:8],
+		PasswordHash: "hashedpassword",
+		DisplayName:  "History",
+		BaseCurrency: "USD",
+	})
+	require.NoError(t, err)
+	checking, err := queries.CreateAccount(ctx, store.CreateAccountParams{
+		UserID:   user.ID,
+		Name:     "Checking",
+		Type:     "deposit",
+		Currency: "USD",
+	})
+	require.NoError(t, err)
+	savings, err := queries.CreateAccount(ctx, store.CreateAccountParams{
+		UserID:   user.ID,
+		Name:     "Savings",
+		Type:     "deposit",
+		Currency: "USD",
+	})
+	require.NoError(t, err)
+
+	date := pgtype.Date{Time: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), Valid: true}
+	transferID := pgtype.UUID{Bytes: uuid.New(), Valid: true}
+	var legs []store.Transaction
+	for _, leg := range []struct {
+		account uuid.UUID
+		typ     string
+	}{{checking.ID, "expense"}, {savings.ID, "income"}} {
+		txn, err := queries.CreateTransaction(ctx, store.CreateTransactionParams{
+			UserID:      user.ID,
+			AccountID:   leg.account,
+			Type:        leg.typ,
+			Amount:      numericFromInt(100),
+			Description: "Transfer",
+			Date:        date,
+			TransferID:  transferID,
+		})
+		require.NoError(t, err)
+		require.NoError(t, queries.CreateTransactionVersion(ctx, store.CreateTransactionVersionParams{
+			TransactionID: txn.ID,
+			ActorID:       user.ID,
+			Action:        "create",
+			Changes:       []byte(`[{"field":"amount","old":null,"new":"100"}]`),
+		}))
+		legs = append(legs, txn)
+	}
+
+	versions, err := queries.ListTransactionVersions(ctx, store.ListTransactionVersionsParams{
+		TransactionID: legs[1].ID,
+		TransferID:    transferID,
+	})
+	require.NoError(t, err)
+	require.Len(t, versions, 2, "a transfer's history covers both legs")
+	require.Equal(t, user.Username, versions[0].ActorUsername)
+	require.JSONEq(t, `[{"field":"amount","old":null,"new":"100"}]`, string(versions[0].Changes))
+
+	versions, err = queries.ListTransactionVersions(ctx, store.ListTransactionVersionsParams{TransactionID: legs[0].ID})
+	require.NoError(t, err)
+	require.Len(t, versions, 1)
+	require.Equal(t, legs[0].ID, versions[0].TransactionID)
+}
+
+func TestTransactionVersions_RecordTrashAndRestore(t *testing.T) {
+	queries, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	user, err := queries.CreateUser(ctx, store.CreateUserParams{
+		InviteCode:   "test-invite",
+		Username:     "history_" + uuid.New().String()[:8],
+		PasswordHash: "hashedpassword",
+		DisplayName:  "History",
+		BaseCurrency: "USD",
+	})
+	require.NoError(t, err)
+	checking, err := queries.CreateAccount(ctx, store.CreateAccountParams{
+		UserID:   user.ID,
+		Name:     "Checking",
+		Type:     "deposit",
+		Currency: "USD",
+	})
+	require.NoError(t, err)
+	txn, err := queries.CreateTransaction(ctx, store.CreateTransactionParams{
+		UserID:    user.ID,
+		AccountID: checking.ID,
+		Type:      "expense",
+		Amount:    numericFromInt(100),
+		Date:      pgtype.Date{Time: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+	})
+	require.NoError(t, err)
+
+	actions := func() []string {
+		t.Helper()
+		versions, err := queries.ListTransactionVersions(ctx, store.ListTransactionVersionsParams{TransactionID: txn.ID})
+		require.NoError(t, err)
+		var got []string
+		for _, v := range versions {
+			require.Equal(t, user.ID, v.ActorID)
+			got = append(got, v.Action)
+		}
+		return got
+	}
+
+	_, err = queries.TrashAccount(ctx, store.TrashAccountParams{ID: checking.ID, UserID: user.ID})
+	require.NoError(t, err)
+	_, err = queries.RestoreAccount(ctx, store.RestoreAccountParams{ID: checking.ID, UserID: user.ID})
+	require.NoError(t, err)
+	require.Equal(t, []string{"delete", "restore"}, actions(), "a live transaction's history doesn't end in a delete")
+
+	require.NoError(t, queries.TrashTransaction(ctx, store.TrashTransactionParams{ID: txn.ID, UserID: user.ID}))
+	n, err := queries.RestoreTransaction(ctx, store.RestoreTransactionParams{ID: txn.ID, UserID: user.ID})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), n)
+	require.Equal(t, []string{"delete", "restore", "restore"}, actions())
+}
@@ -16,10 +16,11 @@ const balanceHistory = `-- name: BalanceHistory :many
 WITH daily AS (
     SELECT
         t.date,
-        SUM(CASE WHEN t.type = 'income' THEN t.amount ELSE -t.amount END)::DECIMAL(15,2) AS daily_change
+        SUM(CASE WHEN t.type = 'income' THEN t.amount ELSE -t.amount END)::DECIMAL(24,8) AS daily_change
     FROM transactions t
     WHERE t.account_id = $1
         AND t.user_id = $2
+        AND t.deleted_at IS NULL
         AND t.date >= $3
         AND t.date <= $4
     GROUP BY t.date
@@ -27,19 +28,20 @@ WITH daily AS (
 start_balance AS (
     SELECT
         a.initial_balance
-        + COALESCE(SUM(CASE WHEN t.type = 'income' THEN t.amount ELSE -t.amount END), 0)::DECIMAL(15,2) AS balance
+        + COALESCE(SUM(CASE WHEN t.type = 'income' THEN t.amount ELSE -t.amount END), 0)::DECIMAL(24,8) AS balance
     FROM accounts a
     LEFT JOIN transactions t
         ON t.account_id = a.id
         AND t.user_id = a.user_id
         AND t.date < $3
+        AND t.deleted_at IS NULL
     WHERE a.id = $1
         AND a.user_id = $2
     GROUP BY a.initial_balance
 )
 SELECT
     d.date,
-    (sb.balance + SUM(d.daily_change) OVER (ORDER BY d.date))::DECIMAL(15,2) AS balance
+    (sb.balance + SUM(d.daily_change) OVER (ORDER BY d.date))::DECIMAL(24,8) AS balance
 FROM daily d
 CROSS JOIN start_balance sb
 ORDER BY d.date
@@ -83,6 +85,7 @@ func (q *Queries) BalanceHistory(ctx context.Context, arg BalanceHistoryParams)
 }
 
 type BulkCreateTransactionsParams struct {
+	ID          uuid.UUID      `json:"id"`
 	UserID      uuid.UUID      `json:"user_id"`
 	AccountID   uuid.UUID      `json:"account_id"`
 	CategoryID  pgtype.UUID    `json:"category_id"`
@@ -93,6 +96,7 @@ type BulkCreateTransactionsParams struct {
 }
 
 type BulkCreateTransactionsFullParams struct {
+	ID           uuid.UUID      `json:"id"`
 	UserID       uuid.UUID      `json:"user_id"`
 	AccountID    uuid.UUID      `json:"account_id"`
 	CategoryID   pgtype.UUID    `json:"category_id"`
@@ -111,10 +115,11 @@ SELECT
     date_trunc('month', t.date)::DATE AS month,
     COALESCE(SUM(
         CASE WHEN t.type = 'income' THEN t.amount ELSE -t.amount END
-    ), 0)::DECIMAL(15,2) AS net_change
+    ), 0)::DECIMAL(24,8) AS net_change
 FROM transactions t
 JOIN accounts a ON a.id = t.account_id
-WHERE t.user_id = $1
+WHERE t.account_id IN (SELECT account_id FROM account_access WHERE user_id = $1)
+    AND t.deleted_at IS NULL
     AND t.date >= $2
     AND t.date <= $3
 GROUP BY t.account_id, a.currency, date_trunc('month', t.date)
@@ -164,13 +169,14 @@ SELECT
     a.currency,
     (a.initial_balance + COALESCE(SUM(
         CASE WHEN t.type = 'income' THEN t.amount ELSE -t.amount END
-    ), 0))::DECIMAL(15,2) AS opening_balance
+    ), 0))::DECIMAL(24,8) AS opening_balance
 FROM accounts a
 LEFT JOIN transactions t
     ON t.account_id = a.id
     AND t.user_id = a.user_id
     AND t.date < $1
-WHERE a.user_id = $2
+    AND t.deleted_at IS NULL
+WHERE a.id IN (SELECT account_id FROM account_access WHERE user_id = $2)
 GROUP BY a.id, a.currency, a.initial_balance
 `
 
@@ -211,13 +217,15 @@ SELECT
     t.type,
     date_trunc('month', t.date)::DATE AS month,
     a.currency,
-    COALESCE(SUM(t.amount), 0)::DECIMAL(15,2) AS amount
+    COALESCE(SUM(t.amount), 0)::DECIMAL(24,8) AS amount
 FROM transactions t
 JOIN accounts a ON a.id = t.account_id
-WHERE t.user_id = $1
+WHERE t.account_id IN (SELECT account_id FROM account_access WHERE user_id = $1)
+    AND t.deleted_at IS NULL
     AND t.date >= $2
     AND t.date <= $3
     AND t.transfer_id IS NULL
+    AND NOT EXISTS (SELECT 1 FROM trades tr WHERE tr.transaction_id = t.id AND tr.type <> 'dividend')
 GROUP BY t.category_id, t.type, date_trunc('month', t.date), a.currency
 `
 
@@ -270,13 +278,16 @@ WITH RECURSIVE expanded_categories AS (
     INNER JOIN expanded_categories ec ON c.parent_id = ec.id
 )
 SELECT COUNT(*) FROM transactions t
-WHERE t.user_id = $1
+WHERE t.account_id IN (SELECT account_id FROM account_access WHERE user_id = $1)
+    AND t.deleted_at IS NULL
     AND (cardinality($2::UUID[]) = 0 OR t.account_id = ANY($2))
     AND (cardinality($3::UUID[]) = 0 OR t.category_id IN (SELECT id FROM expanded_categories))
     AND ($4::VARCHAR IS NULL OR t.type = $4)
     AND ($5::DATE IS NULL OR t.date >= $5)
     AND ($6::DATE IS NULL OR t.date <= $6)
     AND ($7::TEXT IS NULL OR t.description ILIKE '%' || $7 || '%')
+    AND ($8::VARCHAR IS NULL OR t.status = $8)
+    AND (cardinality($9::UUID[]) = 0 OR t.account_id IN (SELECT id FROM accounts WHERE group_id = ANY($9)))
 `
 
 type CountTransactionsParams struct {
@@ -287,6 +298,8 @@ type CountTransactionsParams struct {
 	DateFrom    pgtype.Date `json:"date_from"`
 	DateTo      pgtype.Date `json:"date_to"`
 	Description pgtype.Text `json:"description"`
+	Status      pgtype.Text `json:"status"`
+	GroupIds    []uuid.UUID `json:"group_ids"`
 }
 
 func (q *Queries) CountTransactions(ctx context.Context, arg CountTransactionsParams) (int64, error) {
@@ -298,6 +311,8 @@ func (q *Queries) CountTransactions(ctx context.Context, arg CountTransactionsPa
 		arg.DateFrom,
 		arg.DateTo,
 		arg.Description,
+		arg.Status,
+		arg.GroupIds,
 	)
 	var count int64
 	err := row.Scan(&count)
@@ -305,9 +320,9 @@ func (q *Queries) CountTransactions(ctx context.Context, arg CountTransactionsPa
 }
 
 const createTransaction = `-- name: CreateTransaction :one
-INSERT INTO transactions (user_id, account_id, category_id, type, amount, description, date, transfer_id, exchange_rate)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
-RETURNING id, user_id, account_id, category_id, type, amount, description, date, transfer_id, exchange_rate, created_at, updated_at
+INSERT INTO transactions (user_id, account_id, category_id, type, amount, description, date, transfer_id, exchange_rate, created_by)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING id, user_id, account_id, category_id, type, amount, description, date, transfer_id, exchange_rate, created_at, updated_at, status, created_by, deleted_at
 `
 
 type CreateTransactionParams struct {
@@ -320,6 +335,7 @@ type CreateTransactionParams struct {
 	Date         pgtype.Date    `json:"date"`
 	TransferID   pgtype.UUID    `json:"transfer_id"`
 	ExchangeRate pgtype.Numeric `json:"exchange_rate"`
+	CreatedBy    pgtype.UUID    `json:"created_by"`
 }
 
 func (q *Queries) CreateTransaction(ctx context.Context, arg CreateTransactionParams) (Transaction, error) {
@@ -333,6 +349,7 @@ func (q *Queries) CreateTransaction(ctx context.Context, arg CreateTransactionPa
 		arg.Date,
 		arg.TransferID,
 		arg.ExchangeRate,
+		arg.CreatedBy,
 	)
 	var i Transaction
 	err := row.Scan(
@@ -348,16 +365,21 @@ func (q *Queries) CreateTransaction(ctx context.Context, arg CreateTransactionPa
 		&i.ExchangeRate,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Status,
+		&i.CreatedBy,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
 const dashboardSummary = `-- name: DashboardSummary :one
 SELECT
-    COALESCE(SUM(CASE WHEN type = 'income' AND transfer_id IS NULL THEN amount ELSE 0 END), 0)::DECIMAL(15,2) AS total_income,
-    COALESCE(SUM(CASE WHEN type = 'expense' AND transfer_id IS NULL THEN amount ELSE 0 END), 0)::DECIMAL(15,2) AS total_expense
+    COALESCE(SUM(CASE WHEN type = 'income' AND transfer_id IS NULL THEN amount ELSE 0 END), 0)::DECIMAL(24,8) AS total_income,
+    COALESCE(SUM(CASE WHEN type = 'expense' AND transfer_id IS NULL THEN amount ELSE 0 END), 0)::DECIMAL(24,8) AS total_expense
 FROM transactions
-WHERE user_id = $1
+WHERE account_id IN (SELECT account_id FROM account_access WHERE user_id = $1)
+    AND deleted_at IS NULL
+    AND NOT EXISTS (SELECT 1 FROM trades tr WHERE tr.transaction_id = transactions.id AND tr.type <> 'dividend')
     AND date >= $2
     AND date <= $3
 `
@@ -398,27 +420,58 @@ type DeleteTransactionParams struct {
 	UserID uuid.UUID `json:"user_id"`
 }
 
+// Deletes for good, skipping the trash: for trades, which own their
+// transaction.
 func (q *Queries) DeleteTransaction(ctx context.Context, arg DeleteTransactionParams) error {
 	_, err := q.db.Exec(ctx, deleteTransaction, arg.ID, arg.UserID)
 	return err
 }
 
-const deleteTransactionByTransferID = `-- name: DeleteTransactionByTransferID :exec
-DELETE FROM transactions WHERE transfer_id = $1 AND user_id = $2
+const getAccessibleTransaction = `-- name: GetAccessibleTransaction :one
+SELECT t.id, t.user_id, t.account_id, t.category_id, t.type, t.amount, t.description, t.date, t.transfer_id, t.exchange_rate, t.created_at, t.updated_at, t.status, t.created_by, t.deleted_at, aa.role
+FROM transactions t
+JOIN account_access aa ON aa.account_id = t.account_id
+WHERE t.id = $1 AND aa.user_id = $2 AND t.deleted_at IS NULL
 `
 
-type DeleteTransactionByTransferIDParams struct {
-	TransferID pgtype.UUID `json:"transfer_id"`
-	UserID     uuid.UUID   `json:"user_id"`
+type GetAccessibleTransactionParams struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
 }
 
-func (q *Queries) DeleteTransactionByTransferID(ctx context.Context, arg DeleteTransactionByTransferIDParams) error {
-	_, err := q.db.Exec(ctx, deleteTransactionByTransferID, arg.TransferID, arg.UserID)
-	return err
+type GetAccessibleTransactionRow struct {
+	Transaction Transaction `json:"transaction"`
+	Role        string      `json:"role"`
+}
+
+// Returns a transaction in an account the user owns or shares through a
+// household, with the user's role on that account.
+func (q *Queries) GetAccessibleTransaction(ctx context.Context, arg GetAccessibleTransactionParams) (GetAccessibleTransactionRow, error) {
+	row := q.db.QueryRow(ctx, getAccessibleTransaction, arg.ID, arg.UserID)
+	var i GetAccessibleTransactionRow
+	err := row.Scan(
+		&i.Transaction.ID,
+		&i.Transaction.UserID,
+		&i.Transaction.AccountID,
+		&i.Transaction.CategoryID,
+		&i.Transaction.Type,
+		&i.Transaction.Amount,
+		&i.Transaction.Description,
+		&i.Transaction.Date,
+		&i.Transaction.TransferID,
+		&i.Transaction.ExchangeRate,
+		&i.Transaction.CreatedAt,
+		&i.Transaction.UpdatedAt,
+		&i.Transaction.Status,
+		&i.Transaction.CreatedBy,
+		&i.Transaction.DeletedAt,
+		&i.Role,
+	)
+	return i, err
 }
 
 const getTransaction = `-- name: GetTransaction :one
-SELECT id, user_id, account_id, category_id, type, amount, description, date, transfer_id, exchange_rate, created_at, updated_at FROM transactions WHERE id = $1 AND user_id = $2
+SELECT id, user_id, account_id, category_id, type, amount, description, date, transfer_id, exchange_rate, created_at, updated_at, status, created_by, deleted_at FROM transactions WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
 `
 
 type GetTransactionParams struct {
@@ -442,12 +495,15 @@ func (q *Queries) GetTransaction(ctx context.Context, arg GetTransactionParams)
 		&i.ExchangeRate,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Status,
+		&i.CreatedBy,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
 const getTransactionsByTransferID = `-- name: GetTransactionsByTransferID :many
-SELECT id, user_id, account_id, category_id, type, amount, description, date, transfer_id, exchange_rate, created_at, updated_at FROM transactions WHERE transfer_id = $1 AND user_id = $2
+SELECT id, user_id, account_id, category_id, type, amount, description, date, transfer_id, exchange_rate, created_at, updated_at, status, created_by, deleted_at FROM transactions WHERE transfer_id = $1 AND user_id = $2 AND deleted_at IS NULL
 `
 
 type GetTransactionsByTransferIDParams struct {
@@ -477,6 +533,136 @@ func (q *Queries) GetTransactionsByTransferID(ctx context.Context, arg GetTransa
 			&i.ExchangeRate,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Status,
+			&i.CreatedBy,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTrashedTransaction = `-- name: GetTrashedTransaction :one
+SELECT t.id, t.user_id, t.account_id, t.category_id, t.type, t.amount, t.description, t.date, t.transfer_id, t.exchange_rate, t.created_at, t.updated_at, t.status, t.created_by, t.deleted_at, NOT EXISTS (
+    SELECT 1 FROM transactions o
+    JOIN accounts a ON a.id = o.account_id
+    LEFT JOIN categories c ON c.id = o.category_id
+    WHERE o.user_id = t.user_id AND o.deleted_at = t.deleted_at
+        AND (o.id = t.id OR o.transfer_id = t.transfer_id)
+        AND (a.deleted_at IS NOT NULL OR c.deleted_at IS NOT NULL)
+) AND NOT EXISTS (
+    SELECT 1 FROM accounts ta WHERE ta.user_id = t.user_id AND ta.deleted_at = t.deleted_at
+) AS restorable
+FROM transactions t
+WHERE t.id = $1 AND t.user_id = $2 AND t.deleted_at IS NOT NULL
+`
+
+type GetTrashedTransactionParams struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+type GetTrashedTransactionRow struct {
+	Transaction Transaction `json:"transaction"`
+	Restorable  bool        `json:"restorable"`
+}
+
+// A trashed transaction with whether it can be restored on its own: it must
+// not have gone with an account, and the account and category of each
+// transaction trashed with it must not be in the trash.
+func (q *Queries) GetTrashedTransaction(ctx context.Context, arg GetTrashedTransactionParams) (GetTrashedTransactionRow, error) {
+	row := q.db.QueryRow(ctx, getTrashedTransaction, arg.ID, arg.UserID)
+	var i GetTrashedTransactionRow
+	err := row.Scan(
+		&i.Transaction.ID,
+		&i.Transaction.UserID,
+		&i.Transaction.AccountID,
+		&i.Transaction.CategoryID,
+		&i.Transaction.Type,
+		&i.Transaction.Amount,
+		&i.Transaction.Description,
+		&i.Transaction.Date,
+		&i.Transaction.TransferID,
+		&i.Transaction.ExchangeRate,
+		&i.Transaction.CreatedAt,
+		&i.Transaction.UpdatedAt,
+		&i.Transaction.Status,
+		&i.Transaction.CreatedBy,
+		&i.Transaction.DeletedAt,
+		&i.Restorable,
+	)
+	return i, err
+}
+
+const listForeignCurrencyFlows = `-- name: ListForeignCurrencyFlows :many
+SELECT
+    t.id,
+    t.account_id,
+    t.type,
+    t.amount,
+    t.date,
+    t.transfer_id,
+    t.exchange_rate,
+    ca.currency AS counter_currency,
+    c.amount AS counter_amount
+FROM transactions t
+JOIN accounts a ON a.id = t.account_id
+JOIN users u ON u.id = $1
+LEFT JOIN transactions c ON c.transfer_id = t.transfer_id AND c.id <> t.id AND c.deleted_at IS NULL
+LEFT JOIN accounts ca ON ca.id = c.account_id
+WHERE t.account_id IN (SELECT account_id FROM account_access WHERE user_id = $1)
+    AND t.deleted_at IS NULL
+    AND t.date >= $2
+    AND t.date <= $3
+    AND a.currency <> u.base_currency
+ORDER BY t.date, t.created_at, t.id
+`
+
+type ListForeignCurrencyFlowsParams struct {
+	UserID   uuid.UUID   `json:"user_id"`
+	DateFrom pgtype.Date `json:"date_from"`
+	DateTo   pgtype.Date `json:"date_to"`
+}
+
+type ListForeignCurrencyFlowsRow struct {
+	ID              uuid.UUID      `json:"id"`
+	AccountID       uuid.UUID      `json:"account_id"`
+	Type            string         `json:"type"`
+	Amount          pgtype.Numeric `json:"amount"`
+	Date            pgtype.Date    `json:"date"`
+	TransferID      pgtype.UUID    `json:"transfer_id"`
+	ExchangeRate    pgtype.Numeric `json:"exchange_rate"`
+	CounterCurrency pgtype.Text    `json:"counter_currency"`
+	CounterAmount   pgtype.Numeric `json:"counter_amount"`
+}
+
+// Transactions in the period on accounts the user can see whose currency
+// differs from the user's base currency, oldest first, with the other leg of
+// transfers.
+func (q *Queries) ListForeignCurrencyFlows(ctx context.Context, arg ListForeignCurrencyFlowsParams) ([]ListForeignCurrencyFlowsRow, error) {
+	rows, err := q.db.Query(ctx, listForeignCurrencyFlows, arg.UserID, arg.DateFrom, arg.DateTo)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListForeignCurrencyFlowsRow{}
+	for rows.Next() {
+		var i ListForeignCurrencyFlowsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.Type,
+			&i.Amount,
+			&i.Date,
+			&i.TransferID,
+			&i.ExchangeRate,
+			&i.CounterCurrency,
+			&i.CounterAmount,
 		); err != nil {
 			return nil, err
 		}
@@ -493,6 +679,7 @@ SELECT description
 FROM transactions
 WHERE user_id = $1
   AND description <> ''
+  AND deleted_at IS NULL
   AND description ILIKE '%' || $2::TEXT || '%'
 GROUP BY description
 ORDER BY MAX(date) DESC, MAX(created_at) DESC
@@ -527,7 +714,8 @@ func (q *Queries) ListTransactionDescriptions(ctx context.Context, arg ListTrans
 const listTransactionYears = `-- name: ListTransactionYears :many
 SELECT DISTINCT EXTRACT(YEAR FROM date)::INT AS year
 FROM transactions
-WHERE user_id = $1
+WHERE account_id IN (SELECT account_id FROM account_access WHERE user_id = $1)
+    AND deleted_at IS NULL
 ORDER BY year DESC
 `
 
@@ -559,16 +747,19 @@ WITH RECURSIVE expanded_categories AS (
     SELECT c.id FROM categories c
     INNER JOIN expanded_categories ec ON c.parent_id = ec.id
 )
-SELECT t.id, t.user_id, t.account_id, t.category_id, t.type, t.amount, t.description, t.date, t.transfer_id, t.exchange_rate, t.created_at, t.updated_at FROM transactions t
-WHERE t.user_id = $1
+SELECT t.id, t.user_id, t.account_id, t.category_id, t.type, t.amount, t.description, t.date, t.transfer_id, t.exchange_rate, t.created_at, t.updated_at, t.status, t.created_by, t.deleted_at FROM transactions t
+WHERE t.account_id IN (SELECT account_id FROM account_access WHERE user_id = $1)
+    AND t.deleted_at IS NULL
     AND (cardinality($2::UUID[]) = 0 OR t.account_id = ANY($2))
     AND (cardinality($3::UUID[]) = 0 OR t.category_id IN (SELECT id FROM expanded_categories))
     AND ($4::VARCHAR IS NULL OR t.type = $4)
     AND ($5::DATE IS NULL OR t.date >= $5)
     AND ($6::DATE IS NULL OR t.date <= $6)
     AND ($7::TEXT IS NULL OR t.description ILIKE '%' || $7 || '%')
+    AND ($8::VARCHAR IS NULL OR t.status = $8)
+    AND (cardinality($9::UUID[]) = 0 OR t.account_id IN (SELECT id FROM accounts WHERE group_id = ANY($9)))
 ORDER BY t.date DESC, t.created_at DESC
-LIMIT $9 OFFSET $8
+LIMIT $11 OFFSET $10
 `
 
 type ListTransactionsParams struct {
@@ -579,6 +770,8 @@ type ListTransactionsParams struct {
 	DateFrom    pgtype.Date `json:"date_from"`
 	DateTo      pgtype.Date `json:"date_to"`
 	Description pgtype.Text `json:"description"`
+	Status      pgtype.Text `json:"status"`
+	GroupIds    []uuid.UUID `json:"group_ids"`
 	Off         int32       `json:"off"`
 	Lim         int32       `json:"lim"`
 }
@@ -592,6 +785,8 @@ func (q *Queries) ListTransactions(ctx context.Context, arg ListTransactionsPara
 		arg.DateFrom,
 		arg.DateTo,
 		arg.Description,
+		arg.Status,
+		arg.GroupIds,
 		arg.Off,
 		arg.Lim,
 	)
@@ -615,6 +810,62 @@ func (q *Queries) ListTransactions(ctx context.Context, arg ListTransactionsPara
 			&i.ExchangeRate,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Status,
+			&i.CreatedBy,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTrashedTransactions = `-- name: ListTrashedTransactions :many
+SELECT t.id, t.user_id, t.account_id, t.category_id, t.type, t.amount, t.description, t.date, t.transfer_id, t.exchange_rate, t.created_at, t.updated_at, t.status, t.created_by, t.deleted_at, a.currency
+FROM transactions t
+JOIN accounts a ON a.id = t.account_id
+WHERE t.user_id = $1 AND t.deleted_at IS NOT NULL
+    AND NOT EXISTS (SELECT 1 FROM accounts ta WHERE ta.user_id = t.user_id AND ta.deleted_at = t.deleted_at)
+ORDER BY t.deleted_at DESC, t.date DESC
+`
+
+type ListTrashedTransactionsRow struct {
+	Transaction Transaction `json:"transaction"`
+	Currency    string      `json:"currency"`
+}
+
+// The user's trashed transactions, most recently deleted first. Those
+// trashed along with an account are left out: they come back with it.
+func (q *Queries) ListTrashedTransactions(ctx context.Context, userID uuid.UUID) ([]ListTrashedTransactionsRow, error) {
+	rows, err := q.db.Query(ctx, listTrashedTransactions, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListTrashedTransactionsRow{}
+	for rows.Next() {
+		var i ListTrashedTransactionsRow
+		if err := rows.Scan(
+			&i.Transaction.ID,
+			&i.Transaction.UserID,
+			&i.Transaction.AccountID,
+			&i.Transaction.CategoryID,
+			&i.Transaction.Type,
+			&i.Transaction.Amount,
+			&i.Transaction.Description,
+			&i.Transaction.Date,
+			&i.Transaction.TransferID,
+			&i.Transaction.ExchangeRate,
+			&i.Transaction.CreatedAt,
+			&i.Transaction.UpdatedAt,
+			&i.Transaction.Status,
+			&i.Transaction.CreatedBy,
+			&i.Transaction.DeletedAt,
+			&i.Currency,
 		); err != nil {
 			return nil, err
 		}
@@ -626,13 +877,40 @@ func (q *Queries) ListTransactions(ctx context.Context, arg ListTransactionsPara
 	return items, nil
 }
 
+const markTransactionsReconciled = `-- name: MarkTransactionsReconciled :execrows
+UPDATE transactions
+SET status = 'reconciled', updated_at = now()
+WHERE account_id = $1
+    AND user_id = $2
+    AND status = 'cleared'
+    AND deleted_at IS NULL
+    AND date <= $3
+`
+
+type MarkTransactionsReconciledParams struct {
+	AccountID     uuid.UUID   `json:"account_id"`
+	UserID        uuid.UUID   `json:"user_id"`
+	StatementDate pgtype.Date `json:"statement_date"`
+}
+
+// Locks the cleared transactions a finished reconciliation covered.
+func (q *Queries) MarkTransactionsReconciled(ctx context.Context, arg MarkTransactionsReconciledParams) (int64, error) {
+	result, err := q.db.Exec(ctx, markTransactionsReconciled, arg.AccountID, arg.UserID, arg.StatementDate)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const monthlyIncomeExpense = `-- name: MonthlyIncomeExpense :many
 SELECT
     date_trunc('month', date)::DATE AS month,
-    COALESCE(SUM(CASE WHEN type = 'income' AND transfer_id IS NULL THEN amount ELSE 0 END), 0)::DECIMAL(15,2) AS income,
-    COALESCE(SUM(CASE WHEN type = 'expense' AND transfer_id IS NULL THEN amount ELSE 0 END), 0)::DECIMAL(15,2) AS expense
+    COALESCE(SUM(CASE WHEN type = 'income' AND transfer_id IS NULL THEN amount ELSE 0 END), 0)::DECIMAL(24,8) AS income,
+    COALESCE(SUM(CASE WHEN type = 'expense' AND transfer_id IS NULL THEN amount ELSE 0 END), 0)::DECIMAL(24,8) AS expense
 FROM transactions
-WHERE user_id = $1
+WHERE account_id IN (SELECT account_id FROM account_access WHERE user_id = $1)
+    AND deleted_at IS NULL
+    AND NOT EXISTS (SELECT 1 FROM trades tr WHERE tr.transaction_id = transactions.id AND tr.type <> 'dividend')
     AND date >= $2
     AND date <= $3
 GROUP BY date_trunc('month', date)
@@ -671,26 +949,112 @@ func (q *Queries) MonthlyIncomeExpense(ctx context.Context, arg MonthlyIncomeExp
 	return items, nil
 }
 
+const purgeTransactions = `-- name: PurgeTransactions :execrows
+DELETE FROM transactions WHERE deleted_at < $1
+`
+
+// Hard-deletes transactions trashed before the cutoff.
+func (q *Queries) PurgeTransactions(ctx context.Context, deletedAt pgtype.Timestamptz) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeTransactions, deletedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const restoreTransaction = `-- name: RestoreTransaction :execrows
+WITH restored AS (
+    UPDATE transactions t SET deleted_at = NULL
+    FROM transactions r
+    WHERE r.id = $1 AND r.user_id = $2 AND r.deleted_at IS NOT NULL
+        AND t.user_id = r.user_id AND t.deleted_at = r.deleted_at
+        AND (t.id = r.id
+            OR t.transfer_id = r.transfer_id
+            OR t.id IN (
+                SELECT lp.interest_id FROM loan_payments lp
+                JOIN transactions p ON p.id = lp.payment_id
+                WHERE p.transfer_id = r.transfer_id
+            ))
+    RETURNING t.id
+)
+INSERT INTO transaction_versions (transaction_id, actor_id, action)
+SELECT id, $2, 'restore' FROM restored
+`
+
+type RestoreTransactionParams struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+// Brings a trashed transaction back together with what was trashed with it:
+// the other leg of a transfer and the interest split off a loan payment. Each
+// gets a restore version by the user; the count is of those restored.
+func (q *Queries) RestoreTransaction(ctx context.Context, arg RestoreTransactionParams) (int64, error) {
+	result, err := q.db.Exec(ctx, restoreTransaction, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const setTransactionStatus = `-- name: SetTransactionStatus :one
+UPDATE transactions
+SET status = $3, updated_at = now()
+WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+RETURNING id, user_id, account_id, category_id, type, amount, description, date, transfer_id, exchange_rate, created_at, updated_at, status, created_by, deleted_at
+`
+
+type SetTransactionStatusParams struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+	Status string    `json:"status"`
+}
+
+func (q *Queries) SetTransactionStatus(ctx context.Context, arg SetTransactionStatusParams) (Transaction, error) {
+	row := q.db.QueryRow(ctx, setTransactionStatus, arg.ID, arg.UserID, arg.Status)
+	var i Transaction
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.AccountID,
+		&i.CategoryID,
+		&i.Type,
+		&i.Amount,
+		&i.Description,
+		&i.Date,
+		&i.TransferID,
+		&i.ExchangeRate,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.CreatedBy,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
 const spendingByCategory = `-- name: SpendingByCategory :many
 WITH raw_spending AS (
     SELECT
         c.id AS category_id,
         c.name AS category_name,
         c.parent_id,
-        COALESCE(SUM(t.amount), 0)::DECIMAL(15,2) AS total
+        COALESCE(SUM(t.amount), 0)::DECIMAL(24,8) AS total
     FROM transactions t
     JOIN categories c ON t.category_id = c.id
-    WHERE t.user_id = $1
+    WHERE t.account_id IN (SELECT account_id FROM account_access WHERE user_id = $1)
+        AND t.deleted_at IS NULL
         AND t.type = 'expense'
         AND t.date >= $2
         AND t.date <= $3
         AND t.transfer_id IS NULL
+        AND NOT EXISTS (SELECT 1 FROM trades tr WHERE tr.transaction_id = t.id AND tr.type <> 'dividend')
     GROUP BY c.id, c.name, c.parent_id
 ),
 parent_rollup AS (
     SELECT
         COALESCE(rs.parent_id, rs.category_id) AS category_id,
-        SUM(rs.total)::DECIMAL(15,2) AS total
+        SUM(rs.total)::DECIMAL(24,8) AS total
     FROM raw_spending rs
     GROUP BY COALESCE(rs.parent_id, rs.category_id)
 )
@@ -753,11 +1117,49 @@ func (q *Queries) SpendingByCategory(ctx context.Context, arg SpendingByCategory
 	return items, nil
 }
 
+const trashTransaction = `-- name: TrashTransaction :exec
+UPDATE transactions SET deleted_at = now()
+WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+`
+
+type TrashTransactionParams struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) TrashTransaction(ctx context.Context, arg TrashTransactionParams) error {
+	_, err := q.db.Exec(ctx, trashTransaction, arg.ID, arg.UserID)
+	return err
+}
+
+const trashTransfer = `-- name: TrashTransfer :exec
+UPDATE transactions SET deleted_at = now()
+WHERE user_id = $1 AND deleted_at IS NULL
+    AND (transfer_id = $2
+        OR id IN (
+            SELECT lp.interest_id FROM loan_payments lp
+            JOIN transactions p ON p.id = lp.payment_id
+            WHERE p.transfer_id = $2
+        ))
+`
+
+type TrashTransferParams struct {
+	UserID     uuid.UUID   `json:"user_id"`
+	TransferID pgtype.UUID `json:"transfer_id"`
+}
+
+// Moves both legs of a transfer to the trash, along with the interest
+// transaction split off a loan payment.
+func (q *Queries) TrashTransfer(ctx context.Context, arg TrashTransferParams) error {
+	_, err := q.db.Exec(ctx, trashTransfer, arg.UserID, arg.TransferID)
+	return err
+}
+
 const updateTransaction = `-- name: UpdateTransaction :one
 UPDATE transactions
 SET account_id = $2, category_id = $3, type = $4, amount = $5, description = $6, date = $7, updated_at = now()
-WHERE id = $1 AND user_id = $8
-RETURNING id, user_id, account_id, category_id, type, amount, description, date, transfer_id, exchange_rate, created_at, updated_at
+WHERE id = $1 AND user_id = $8 AND deleted_at IS NULL
+RETURNING id, user_id, account_id, category_id, type, amount, description, date, transfer_id, exchange_rate, created_at, updated_at, status, created_by, deleted_at
 `
 
 type UpdateTransactionParams struct {
@@ -796,6 +1198,9 @@ func (q *Queries) UpdateTransaction(ctx context.Context, arg UpdateTransactionPa
 		&i.ExchangeRate,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Status,
+		&i.CreatedBy,
+		&i.DeletedAt,
 	)
 	return i, err
 }
@@ -803,8 +1208,8 @@ func (q *Queries) UpdateTransaction(ctx context.Context, arg UpdateTransactionPa
 const updateTransferTransaction = `-- name: UpdateTransferTransaction :one
 UPDATE transactions
 SET account_id = $2, amount = $3, description = $4, date = $5, exchange_rate = $6, updated_at = now()
-WHERE id = $1 AND user_id = $7
-RETURNING id, user_id, account_id, category_id, type, amount, description, date, transfer_id, exchange_rate, created_at, updated_at
+WHERE id = $1 AND user_id = $7 AND deleted_at IS NULL
+RETURNING id, user_id, account_id, category_id, type, amount, description, date, transfer_id, exchange_rate, created_at, updated_at, status, created_by, deleted_at
 `
 
 type UpdateTransferTransactionParams struct {
@@ -841,6 +1246,9 @@ func (q *Queries) UpdateTransferTransaction(ctx context.Context, arg UpdateTrans
 		&i.ExchangeRate,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Status,
+		&i.CreatedBy,
+		&i.DeletedAt,
 	)
 	return i, err
 }
@@ -30,6 +30,7 @@ func setupTestDB(t *testing.T) (*store.Queries, func()) {
 	require.NoError(t, err)
 
 	queries := store.New(pool)
+	require.NoError(t, queries.EnsureInviteCode(ctx, "test-invite"))
 	cleanup := func() {
 		pool.Close()
 	}
@@ -58,7 +59,7 @@ func TestListTransactions_IncludesChildCategories(t *testing.T) {
 		PasswordHash: "hashedpassword",
 		DisplayName:  "Test User",
 		BaseCurrency: "USD",
-		InviteCode:   pgtype.Text{String: "test-invite", Valid: true},
+		InviteCode:   "test-invite",
 	})
 	require.NoError(t, err)
 
@@ -13,26 +13,39 @@ import (
 )
 
 const createUser = `-- name: CreateUser :one
+WITH invite AS (
+    UPDATE invite_codes
+    SET uses = uses + 1
+    WHERE code = $1
+        AND revoked_at IS NULL
+        AND (expires_at IS NULL OR expires_at > now())
+        AND (max_uses IS NULL OR uses < max_uses)
+    RETURNING code
+)
 INSERT INTO users (username, password_hash, display_name, base_currency, invite_code)
-VALUES ($1, $2, $3, $4, $5)
-RETURNING id, username, password_hash, display_name, base_currency, created_at, updated_at, invite_code
+SELECT $2, $3, $4, $5, invite.code
+FROM invite
+RETURNING id, username, password_hash, display_name, base_currency, created_at, updated_at, invite_code, is_admin, disabled_at
 `
 
 type CreateUserParams struct {
-	Username     string      `json:"username"`
-	PasswordHash string      `json:"password_hash"`
-	DisplayName  string      `json:"display_name"`
-	BaseCurrency string      `json:"base_currency"`
-	InviteCode   pgtype.Text `json:"invite_code"`
+	InviteCode   string `json:"invite_code"`
+	Username     string `json:"username"`
+	PasswordHash string `json:"password_hash"`
+	DisplayName  string `json:"display_name"`
+	BaseCurrency string `json:"base_currency"`
 }
 
+// Redeems the invite code and creates the user in one statement. No row
+// comes back when the code is unknown, revoked, expired or used up; a
+// duplicate username fails the whole statement, so the use isn't counted.
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
 	row := q.db.QueryRow(ctx, createUser,
+		arg.InviteCode,
 		arg.Username,
 		arg.PasswordHash,
 		arg.DisplayName,
 		arg.BaseCurrency,
-		arg.InviteCode,
 	)
 	var i User
 	err := row.Scan(
@@ -44,12 +57,14 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.InviteCode,
+		&i.IsAdmin,
+		&i.DisabledAt,
 	)
 	return i, err
 }
 
 const getUserByID = `-- name: GetUserByID :one
-SELECT id, username, password_hash, display_name, base_currency, created_at, updated_at, invite_code FROM users WHERE id = $1
+SELECT id, username, password_hash, display_name, base_currency, created_at, updated_at, invite_code, is_admin, disabled_at FROM users WHERE id = $1
 `
 
 func (q *Queries) GetUserByID(ctx context.Context, id uuid.UUID) (User, error) {
@@ -64,12 +79,14 @@ func (q *Queries) GetUserByID(ctx context.Context, id uuid.UUID) (User, error) {
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.InviteCode,
+		&i.IsAdmin,
+		&i.DisabledAt,
 	)
 	return i, err
 }
 
 const getUserByUsername = `-- name: GetUserByUsername :one
-SELECT id, username, password_hash, display_name, base_currency, created_at, updated_at, invite_code FROM users WHERE username = $1
+SELECT id, username, password_hash, display_name, base_currency, created_at, updated_at, invite_code, is_admin, disabled_at FROM users WHERE username = $1
 `
 
 func (q *Queries) GetUserByUsername(ctx context.Context, username string) (User, error) {
@@ -84,6 +101,103 @@ func (q *Queries) GetUserByUsername(ctx context.Context, username string) (User,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.InviteCode,
+		&i.IsAdmin,
+		&i.DisabledAt,
+	)
+	return i, err
+}
+
+const listUsers = `-- name: ListUsers :many
+SELECT u.id, u.username, u.display_name, u.base_currency, u.is_admin, u.disabled_at, u.created_at,
+    (SELECT COUNT(*) FROM accounts a WHERE a.user_id = u.id) AS account_count,
+    (SELECT COUNT(*) FROM transactions t WHERE t.user_id = u.id) AS transaction_count
+FROM users u
+ORDER BY u.created_at, u.id
+`
+
+type ListUsersRow struct {
+	ID               uuid.UUID          `json:"id"`
+	Username         string             `json:"username"`
+	DisplayName      string             `json:"display_name"`
+	BaseCurrency     string             `json:"base_currency"`
+	IsAdmin          bool               `json:"is_admin"`
+	DisabledAt       pgtype.Timestamptz `json:"disabled_at"`
+	CreatedAt        pgtype.Timestamptz `json:"created_at"`
+	AccountCount     int64              `json:"account_count"`
+	TransactionCount int64              `json:"transaction_count"`
+}
+
+// Every user on the instance with how much data they have, oldest first.
+func (q *Queries) ListUsers(ctx context.Context) ([]ListUsersRow, error) {
+	rows, err := q.db.Query(ctx, listUsers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListUsersRow
+	for rows.Next() {
+		var i ListUsersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Username,
+			&i.DisplayName,
+			&i.BaseCurrency,
+			&i.IsAdmin,
+			&i.DisabledAt,
+			&i.CreatedAt,
+			&i.AccountCount,
+			&i.TransactionCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const promoteAdmins = `-- name: PromoteAdmins :execrows
+UPDATE users SET is_admin = true, updated_at = now()
+WHERE username = ANY($1::TEXT[]) AND NOT is_admin
+`
+
+func (q *Queries) PromoteAdmins(ctx context.Context, usernames []string) (int64, error) {
+	result, err := q.db.Exec(ctx, promoteAdmins, usernames)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const setUserDisabled = `-- name: SetUserDisabled :one
+UPDATE users
+SET disabled_at = CASE WHEN $2::boolean THEN COALESCE(disabled_at, now()) END, updated_at = now()
+WHERE id = $1
+RETURNING id, username, password_hash, display_name, base_currency, created_at, updated_at, invite_code, is_admin, disabled_at
+`
+
+type SetUserDisabledParams struct {
+	ID       uuid.UUID `json:"id"`
+	Disabled bool      `json:"disabled"`
+}
+
+// Disabling keeps the original disabled_at; enabling clears it.
+func (q *Queries) SetUserDisabled(ctx context.Context, arg SetUserDisabledParams) (User, error) {
+	row := q.db.QueryRow(ctx, setUserDisabled, arg.ID, arg.Disabled)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.PasswordHash,
+		&i.DisplayName,
+		&i.BaseCurrency,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.InviteCode,
+		&i.IsAdmin,
+		&i.DisabledAt,
 	)
 	return i, err
 }
@@ -92,7 +206,7 @@ const updateUser = `-- name: UpdateUser :one
 UPDATE users
 SET display_name = $2, base_currency = $3, updated_at = now()
 WHERE id = $1
-RETURNING id, username, password_hash, display_name, base_currency, created_at, updated_at, invite_code
+RETURNING id, username, password_hash, display_name, base_currency, created_at, updated_at, invite_code, is_admin, disabled_at
 `
 
 type UpdateUserParams struct {
@@ -113,6 +227,8 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.InviteCode,
+		&i.IsAdmin,
+		&i.DisabledAt,
 	)
 	return i, err
 }
@@ -0,0 +1,3 @@
+-- Rows that predate provider tracking can't be attributed reliably.
+ALTER TABLE exchange_rates ADD COLUMN source VARCHAR(20) NOT NULL DEFAULT 'unknown';
+ALTER TABLE exchange_rates ALTER COLUMN source SET DEFAULT 'manual';
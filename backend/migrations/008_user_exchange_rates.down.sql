@@ -0,0 +1,7 @@
+DELETE FROM exchange_rates WHERE user_id IS NOT NULL;
+DROP INDEX IF EXISTS exchange_rates_user_key;
+DROP INDEX IF EXISTS exchange_rates_global_key;
+ALTER TABLE exchange_rates ADD CONSTRAINT exchange_rates_from_currency_to_currency_date_key UNIQUE (from_currency, to_currency, date);
+ALTER TABLE exchange_rates DROP COLUMN IF EXISTS user_id;
+
+ALTER TABLE users DROP COLUMN IF EXISTS is_admin;
@@ -0,0 +1,14 @@
+-- Admins may write global exchange rates. The earliest registered user (the
+-- instance owner) becomes the first admin; promote others with
+-- UPDATE users SET is_admin = true WHERE username = '...'.
+ALTER TABLE users ADD COLUMN is_admin BOOLEAN NOT NULL DEFAULT false;
+UPDATE users SET is_admin = true
+WHERE id = (SELECT id FROM users ORDER BY created_at, id LIMIT 1);
+
+-- Rates with a user_id are personal overrides visible only to that user;
+-- NULL means a global rate shared by everyone.
+ALTER TABLE exchange_rates ADD COLUMN user_id UUID REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE exchange_rates DROP CONSTRAINT exchange_rates_from_currency_to_currency_date_key;
+CREATE UNIQUE INDEX exchange_rates_global_key ON exchange_rates(from_currency, to_currency, date) WHERE user_id IS NULL;
+CREATE UNIQUE INDEX exchange_rates_user_key ON exchange_rates(user_id, from_currency, to_currency, date) WHERE user_id IS NOT NULL;
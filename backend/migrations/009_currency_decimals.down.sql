@@ -0,0 +1,5 @@
+-- Rounds amounts with more than two decimals.
+ALTER TABLE transactions ALTER COLUMN amount TYPE DECIMAL(15,2);
+ALTER TABLE accounts ALTER COLUMN initial_balance TYPE DECIMAL(15,2);
+
+ALTER TABLE currencies DROP COLUMN decimals;
@@ -0,0 +1,11 @@
+-- Number of minor-unit digits per currency (ISO 4217 exponent). Amounts are
+-- formatted and validated with the precision of their account's currency.
+ALTER TABLE currencies ADD COLUMN decimals SMALLINT NOT NULL DEFAULT 2
+    CHECK (decimals BETWEEN 0 AND 8);
+
+UPDATE currencies SET decimals = 0 WHERE code IN ('JPY', 'KRW', 'VND', 'CLP', 'ISK', 'PYG', 'UGX', 'XAF', 'XOF');
+UPDATE currencies SET decimals = 3 WHERE code IN ('KWD', 'BHD', 'OMR', 'JOD', 'TND', 'LYD', 'IQD');
+
+-- Wide enough for 3-decimal fiat and 8-decimal crypto amounts.
+ALTER TABLE accounts ALTER COLUMN initial_balance TYPE DECIMAL(24,8);
+ALTER TABLE transactions ALTER COLUMN amount TYPE DECIMAL(24,8);
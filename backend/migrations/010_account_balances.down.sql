@@ -0,0 +1,6 @@
+DROP TRIGGER transactions_account_balance ON transactions;
+DROP FUNCTION transactions_sync_account_balance();
+DROP TRIGGER accounts_initial_balance ON accounts;
+DROP FUNCTION accounts_sync_initial_balance();
+
+ALTER TABLE accounts DROP COLUMN balance;
@@ -0,0 +1,49 @@
+-- Materialized account balance: initial_balance + income - expense, kept up
+-- to date by triggers so listing accounts needs no per-account aggregate.
+ALTER TABLE accounts ADD COLUMN balance DECIMAL(24,8) NOT NULL DEFAULT 0;
+
+UPDATE accounts a
+SET balance = a.initial_balance + COALESCE((
+    SELECT SUM(CASE WHEN t.type = 'income' THEN t.amount ELSE -t.amount END)
+    FROM transactions t
+    WHERE t.account_id = a.id
+), 0);
+
+-- A new account starts at its initial balance; changing the initial balance
+-- shifts the balance by the same amount.
+CREATE FUNCTION accounts_sync_initial_balance() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        NEW.balance := NEW.initial_balance;
+    ELSE
+        NEW.balance := OLD.balance + NEW.initial_balance - OLD.initial_balance;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER accounts_initial_balance
+BEFORE INSERT OR UPDATE OF initial_balance ON accounts
+FOR EACH ROW EXECUTE FUNCTION accounts_sync_initial_balance();
+
+-- Reverses the old row and applies the new one, which also covers moving a
+-- transaction between accounts.
+CREATE FUNCTION transactions_sync_account_balance() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        UPDATE accounts
+        SET balance = balance - CASE WHEN OLD.type = 'income' THEN OLD.amount ELSE -OLD.amount END
+        WHERE id = OLD.account_id;
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        UPDATE accounts
+        SET balance = balance + CASE WHEN NEW.type = 'income' THEN NEW.amount ELSE -NEW.amount END
+        WHERE id = NEW.account_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER transactions_account_balance
+AFTER INSERT OR UPDATE OF account_id, type, amount OR DELETE ON transactions
+FOR EACH ROW EXECUTE FUNCTION transactions_sync_account_balance();
@@ -0,0 +1,4 @@
+DROP TABLE reconciliations;
+
+DROP INDEX idx_transactions_account_status;
+ALTER TABLE transactions DROP COLUMN status;
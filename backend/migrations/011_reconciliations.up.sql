@@ -0,0 +1,23 @@
+-- pending: entered but not yet seen on a statement; cleared: matched against
+-- the bank; reconciled: part of a finished reconciliation and locked.
+ALTER TABLE transactions ADD COLUMN status VARCHAR(10) NOT NULL DEFAULT 'pending'
+    CHECK (status IN ('pending', 'cleared', 'reconciled'));
+
+CREATE INDEX idx_transactions_account_status ON transactions(account_id, status);
+
+-- A reconciliation compares an account's cleared balance with a bank
+-- statement. finished_at is NULL while it is in progress; an account has at
+-- most one in progress.
+CREATE TABLE reconciliations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    statement_date DATE NOT NULL,
+    statement_balance DECIMAL(20,8) NOT NULL,
+    reconciled_count INTEGER NOT NULL DEFAULT 0,
+    finished_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX reconciliations_in_progress_key ON reconciliations(account_id) WHERE finished_at IS NULL;
+CREATE INDEX idx_reconciliations_account ON reconciliations(account_id, statement_date DESC);
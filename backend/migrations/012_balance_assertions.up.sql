@@ -0,0 +1,15 @@
+-- A balance assertion records what an account held at the end of a day, e.g.
+-- the closing balance on a bank statement. Checking compares it with
+-- initial_balance plus every transaction dated on or before that day.
+CREATE TABLE balance_assertions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    balance DECIMAL(20,8) NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (account_id, date)
+);
+
+CREATE INDEX idx_balance_assertions_user ON balance_assertions(user_id);
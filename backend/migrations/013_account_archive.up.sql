@@ -0,0 +1,3 @@
+-- An archived account is closed: it keeps its history for reports and
+-- exports but is hidden from default lists and takes no new transactions.
+ALTER TABLE accounts ADD COLUMN archived_on DATE;
@@ -0,0 +1,13 @@
+-- Statement cycle settings for credit_card accounts. A statement closes on
+-- statement_day every month (the month's last day if it is shorter) and is
+-- due on the first payment_due_day after closing.
+CREATE TABLE credit_cards (
+    account_id UUID PRIMARY KEY REFERENCES accounts(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    credit_limit DECIMAL(20,8),
+    statement_day SMALLINT NOT NULL CHECK (statement_day BETWEEN 1 AND 31),
+    payment_due_day SMALLINT NOT NULL CHECK (payment_due_day BETWEEN 1 AND 31),
+    min_payment_percent DECIMAL(5,2) NOT NULL DEFAULT 2,
+    min_payment_amount DECIMAL(20,8) NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
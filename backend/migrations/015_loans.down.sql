@@ -0,0 +1,7 @@
+DROP TRIGGER loan_payments_delete_interest ON loan_payments;
+DROP FUNCTION loan_payments_delete_interest();
+DROP TABLE loan_payments;
+DROP TABLE loans;
+ALTER TABLE accounts DROP CONSTRAINT accounts_type_check;
+UPDATE accounts SET type = 'other' WHERE type = 'loan';
+ALTER TABLE accounts ADD CONSTRAINT accounts_type_check CHECK (type IN ('deposit', 'cash', 'credit_card', 'debit_card', 'other'));
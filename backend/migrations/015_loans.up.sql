@@ -0,0 +1,43 @@
+ALTER TABLE accounts DROP CONSTRAINT accounts_type_check;
+ALTER TABLE accounts ADD CONSTRAINT accounts_type_check CHECK (type IN ('deposit', 'cash', 'credit_card', 'debit_card', 'loan', 'other'));
+
+-- Terms of a loan account. The account balance is minus the remaining
+-- principal: payments arrive as transfers into the account and their
+-- interest share is booked back out as an expense.
+CREATE TABLE loans (
+    account_id UUID PRIMARY KEY REFERENCES accounts(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    principal DECIMAL(20,8) NOT NULL CHECK (principal > 0),
+    annual_rate DECIMAL(7,4) NOT NULL CHECK (annual_rate >= 0),
+    term_months INTEGER NOT NULL CHECK (term_months > 0),
+    start_date DATE NOT NULL,
+    payment_day SMALLINT NOT NULL CHECK (payment_day BETWEEN 1 AND 31),
+    interest_category_id UUID REFERENCES categories(id) ON DELETE SET NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- One row per payment transfer that has been split into principal and
+-- interest. Deleting the payment deletes the split and its interest
+-- transaction.
+CREATE TABLE loan_payments (
+    payment_id UUID PRIMARY KEY REFERENCES transactions(id) ON DELETE CASCADE,
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    interest_id UUID REFERENCES transactions(id) ON DELETE SET NULL,
+    principal DECIMAL(20,8) NOT NULL,
+    interest DECIMAL(20,8) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_loan_payments_account ON loan_payments(account_id);
+
+CREATE FUNCTION loan_payments_delete_interest() RETURNS trigger AS $$
+BEGIN
+    DELETE FROM transactions WHERE id = OLD.interest_id;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER loan_payments_delete_interest
+AFTER DELETE ON loan_payments
+FOR EACH ROW EXECUTE FUNCTION loan_payments_delete_interest();
@@ -0,0 +1,9 @@
+DROP VIEW holdings;
+DROP VIEW security_quotes;
+DELETE FROM transactions WHERE id IN (SELECT transaction_id FROM trades);
+DROP TABLE trades;
+DROP TABLE security_prices;
+DROP TABLE securities;
+ALTER TABLE accounts DROP CONSTRAINT accounts_type_check;
+UPDATE accounts SET type = 'other' WHERE type = 'investment';
+ALTER TABLE accounts ADD CONSTRAINT accounts_type_check CHECK (type IN ('deposit', 'cash', 'credit_card', 'debit_card', 'loan', 'other'));
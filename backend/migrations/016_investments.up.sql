@@ -0,0 +1,62 @@
+ALTER TABLE accounts DROP CONSTRAINT accounts_type_check;
+ALTER TABLE accounts ADD CONSTRAINT accounts_type_check CHECK (type IN ('deposit', 'cash', 'credit_card', 'debit_card', 'loan', 'investment', 'other'));
+
+-- Stocks, funds and other securities a user holds. Prices are quoted in the
+-- security's currency; symbol is the price provider's ticker.
+CREATE TABLE securities (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    symbol VARCHAR(32) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    currency VARCHAR(3) NOT NULL REFERENCES currencies(code),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (user_id, symbol)
+);
+
+CREATE TABLE security_prices (
+    security_id UUID NOT NULL REFERENCES securities(id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    price DECIMAL(20,8) NOT NULL CHECK (price > 0),
+    source VARCHAR(32) NOT NULL DEFAULT 'manual',
+    PRIMARY KEY (security_id, date)
+);
+
+-- Buys, sells and dividends on investment accounts. Each has a cash
+-- transaction on the account: buys and sells carry the trade id as their
+-- transfer_id, so reports treat them as moves between cash and holdings,
+-- while dividends are plain income. Deleting the transaction deletes the
+-- trade.
+CREATE TABLE trades (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    security_id UUID NOT NULL REFERENCES securities(id),
+    type VARCHAR(10) NOT NULL CHECK (type IN ('buy', 'sell', 'dividend')),
+    date DATE NOT NULL,
+    quantity DECIMAL(20,8) NOT NULL DEFAULT 0 CHECK (quantity >= 0),
+    price DECIMAL(20,8) NOT NULL DEFAULT 0 CHECK (price >= 0),
+    fee DECIMAL(20,8) NOT NULL DEFAULT 0 CHECK (fee >= 0),
+    transaction_id UUID NOT NULL UNIQUE REFERENCES transactions(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_trades_account ON trades(account_id, date);
+CREATE INDEX idx_trades_security ON trades(security_id);
+
+-- Latest known price of each security: the newest price row, else the
+-- price of the newest buy or sell.
+CREATE VIEW security_quotes AS
+SELECT DISTINCT ON (security_id) security_id, date, price
+FROM (
+    SELECT security_id, date, price, 1 AS rank FROM security_prices
+    UNION ALL
+    SELECT security_id, date, price, 2 AS rank FROM trades WHERE type <> 'dividend'
+) q
+ORDER BY security_id, rank, date DESC;
+
+-- Quantity of each security held per account.
+CREATE VIEW holdings AS
+SELECT user_id, account_id, security_id,
+    SUM(CASE type WHEN 'buy' THEN quantity WHEN 'sell' THEN -quantity ELSE 0 END) AS quantity
+FROM trades
+GROUP BY user_id, account_id, security_id;
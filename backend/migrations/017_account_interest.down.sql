@@ -0,0 +1,2 @@
+DROP TABLE interest_accruals;
+DROP TABLE account_interest;
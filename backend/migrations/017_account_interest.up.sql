@@ -0,0 +1,27 @@
+-- Interest terms of a savings or deposit account. Interest accrues on the
+-- daily closing balance and is credited at the end of each compounding
+-- period, to the account itself or to payout_account_id.
+CREATE TABLE account_interest (
+    account_id UUID PRIMARY KEY REFERENCES accounts(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    annual_rate DECIMAL(7,4) NOT NULL CHECK (annual_rate >= 0),
+    compounding VARCHAR(10) NOT NULL CHECK (compounding IN ('monthly', 'quarterly')),
+    start_date DATE NOT NULL,
+    payout_account_id UUID REFERENCES accounts(id) ON DELETE SET NULL,
+    category_id UUID REFERENCES categories(id) ON DELETE SET NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- One row per credited period, also for periods that earned nothing, so a
+-- period is never credited twice. Deleting the interest transaction keeps
+-- the row.
+CREATE TABLE interest_accruals (
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    period_start DATE NOT NULL,
+    period_end DATE NOT NULL,
+    amount DECIMAL(20,8) NOT NULL,
+    transaction_id UUID REFERENCES transactions(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (account_id, period_end)
+);
@@ -0,0 +1,3 @@
+ALTER TABLE accounts DROP COLUMN sort_order;
+ALTER TABLE accounts DROP COLUMN group_id;
+DROP TABLE account_groups;
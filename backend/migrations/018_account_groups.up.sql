@@ -0,0 +1,20 @@
+-- User-defined groups of accounts (e.g. per country or purpose). Groups and
+-- the accounts in them are listed by sort_order.
+CREATE TABLE account_groups (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (user_id, name)
+);
+
+ALTER TABLE accounts ADD COLUMN group_id UUID REFERENCES account_groups(id) ON DELETE SET NULL;
+ALTER TABLE accounts ADD COLUMN sort_order INTEGER NOT NULL DEFAULT 0;
+
+-- Keep the alphabetical order existing accounts were listed in.
+UPDATE accounts a
+SET sort_order = o.n
+FROM (SELECT id, ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY name) - 1 AS n FROM accounts) o
+WHERE a.id = o.id;
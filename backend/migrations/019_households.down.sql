@@ -0,0 +1,6 @@
+DROP VIEW account_access;
+ALTER TABLE transactions DROP COLUMN created_by;
+ALTER TABLE categories DROP COLUMN household_id;
+ALTER TABLE accounts DROP COLUMN household_id;
+DROP TABLE household_members;
+DROP TABLE households;
@@ -0,0 +1,38 @@
+-- Households let several users share accounts and categories. Shared rows
+-- stay owned by the user who created them (user_id); members get access
+-- according to their role: owners manage the household and its members,
+-- editors record transactions in its accounts, viewers only read.
+CREATE TABLE households (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE household_members (
+    household_id UUID NOT NULL REFERENCES households(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(10) NOT NULL CHECK (role IN ('owner', 'editor', 'viewer')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (household_id, user_id)
+);
+
+CREATE INDEX idx_household_members_user ON household_members(user_id);
+
+ALTER TABLE accounts ADD COLUMN household_id UUID REFERENCES households(id) ON DELETE SET NULL;
+ALTER TABLE categories ADD COLUMN household_id UUID REFERENCES households(id) ON DELETE SET NULL;
+
+-- The member who recorded the transaction. NULL for rows written before
+-- households existed and for imports; those were created by user_id.
+ALTER TABLE transactions ADD COLUMN created_by UUID REFERENCES users(id) ON DELETE SET NULL;
+
+-- Who can see each account and with which role: its owner, and the members
+-- of the household it is shared with.
+CREATE VIEW account_access AS
+SELECT a.id AS account_id, a.user_id, 'owner'::VARCHAR(10) AS role
+FROM accounts a
+UNION ALL
+SELECT a.id, m.user_id, m.role
+FROM accounts a
+JOIN household_members m ON m.household_id = a.household_id
+WHERE m.user_id <> a.user_id;
@@ -0,0 +1,5 @@
+DROP VIEW debt_entries;
+DROP TABLE debt_settlements;
+DROP TABLE expense_split_shares;
+DROP TABLE expense_splits;
+DROP TABLE contacts;
@@ -0,0 +1,77 @@
+-- People the user shares expenses with. Contacts are not users of the app;
+-- debts with them are tracked on the user's side only.
+CREATE TABLE contacts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (user_id, name)
+);
+
+-- A shared expense split among the user and contacts. Either the user paid
+-- it with one of their transactions (transaction_id) or a contact paid it
+-- (paid_by). Amount and currency are copied from the transaction when the
+-- split is made.
+CREATE TABLE expense_splits (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    transaction_id UUID UNIQUE REFERENCES transactions(id) ON DELETE CASCADE,
+    paid_by UUID REFERENCES contacts(id),
+    method VARCHAR(10) NOT NULL CHECK (method IN ('equal', 'percentage', 'exact')),
+    description TEXT NOT NULL DEFAULT '',
+    date DATE NOT NULL,
+    currency VARCHAR(3) NOT NULL REFERENCES currencies(code),
+    amount DECIMAL(20,8) NOT NULL CHECK (amount > 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK ((transaction_id IS NULL) <> (paid_by IS NULL))
+);
+
+CREATE INDEX idx_expense_splits_user ON expense_splits(user_id, date);
+
+-- Each participant's share of a split; a NULL contact_id is the user's own
+-- share. Shares add up to the split's amount.
+CREATE TABLE expense_split_shares (
+    split_id UUID NOT NULL REFERENCES expense_splits(id) ON DELETE CASCADE,
+    contact_id UUID REFERENCES contacts(id),
+    amount DECIMAL(20,8) NOT NULL CHECK (amount >= 0),
+    UNIQUE NULLS NOT DISTINCT (split_id, contact_id)
+);
+
+CREATE INDEX idx_expense_split_shares_contact ON expense_split_shares(contact_id);
+
+-- Money paid to or received from a contact to settle debts. The cash
+-- transaction carries the settlement id as its transfer_id, so income and
+-- spending reports skip it; deleting the transaction deletes the
+-- settlement.
+CREATE TABLE debt_settlements (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    contact_id UUID NOT NULL REFERENCES contacts(id),
+    transaction_id UUID NOT NULL UNIQUE REFERENCES transactions(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_debt_settlements_contact ON debt_settlements(contact_id);
+
+-- What each contact owes the user, per currency: a positive amount is owed
+-- to the user, a negative one by the user. Contacts owe their shares of
+-- expenses the user paid; the user owes their own share of expenses a
+-- contact paid. Money received from a contact lowers the balance, money
+-- paid to them raises it.
+CREATE VIEW debt_entries AS
+SELECT e.user_id, s.contact_id, e.currency, s.amount
+FROM expense_splits e
+JOIN expense_split_shares s ON s.split_id = e.id
+WHERE e.paid_by IS NULL AND s.contact_id IS NOT NULL
+UNION ALL
+SELECT e.user_id, e.paid_by, e.currency, -s.amount
+FROM expense_splits e
+JOIN expense_split_shares s ON s.split_id = e.id
+WHERE e.paid_by IS NOT NULL AND s.contact_id IS NULL
+UNION ALL
+SELECT d.user_id, d.contact_id, a.currency,
+    CASE t.type WHEN 'income' THEN -t.amount ELSE t.amount END
+FROM debt_settlements d
+JOIN transactions t ON t.id = d.transaction_id
+JOIN accounts a ON a.id = t.account_id;
@@ -0,0 +1,3 @@
+-- Admins can disable an account instead of deleting it: the user can no
+-- longer log in or refresh a session, and their data is kept.
+ALTER TABLE users ADD COLUMN disabled_at TIMESTAMPTZ;
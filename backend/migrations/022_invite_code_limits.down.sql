@@ -0,0 +1,2 @@
+ALTER TABLE users DROP CONSTRAINT users_invite_code_fkey;
+DROP TABLE invite_codes;
@@ -0,0 +1,21 @@
+-- Invite codes move from the INVITE_CODES setting into the database so
+-- admins can limit, expire and revoke them. users.invite_code records the
+-- code each user registered with.
+CREATE TABLE invite_codes (
+    code VARCHAR(100) PRIMARY KEY,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    max_uses INT CHECK (max_uses > 0), -- NULL = unlimited
+    uses INT NOT NULL DEFAULT 0,
+    expires_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Codes already redeemed stay valid; startup adds the rest of INVITE_CODES.
+INSERT INTO invite_codes (code, uses)
+SELECT invite_code, COUNT(*) FROM users
+WHERE invite_code IS NOT NULL
+GROUP BY invite_code;
+
+ALTER TABLE users ADD CONSTRAINT users_invite_code_fkey
+    FOREIGN KEY (invite_code) REFERENCES invite_codes(code) ON DELETE SET NULL;
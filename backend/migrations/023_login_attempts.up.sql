@@ -0,0 +1,15 @@
+-- Every login attempt, kept per username so failures against one account
+-- are throttled however many addresses they come from. user_id is NULL
+-- when the username doesn't exist.
+CREATE TABLE login_attempts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    username VARCHAR(50) NOT NULL,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    ip VARCHAR(45) NOT NULL,
+    user_agent TEXT NOT NULL,
+    outcome VARCHAR(10) NOT NULL CHECK (outcome IN ('success', 'failure', 'locked', 'disabled')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_login_attempts_username ON login_attempts(username, created_at);
+CREATE INDEX idx_login_attempts_user ON login_attempts(user_id, created_at);
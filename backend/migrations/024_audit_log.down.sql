@@ -0,0 +1,3 @@
+DROP TRIGGER audit_log_append_only ON audit_log;
+DROP FUNCTION audit_log_reject_update();
+DROP TABLE audit_log;
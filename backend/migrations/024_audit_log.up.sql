@@ -0,0 +1,27 @@
+-- Security-relevant actions, kept per user. actor_id is who performed the
+-- action: the user themselves, or an admin acting on their account. It is
+-- not a foreign key so no cascade ever rewrites an entry.
+CREATE TABLE audit_log (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    actor_id UUID NOT NULL,
+    action VARCHAR(50) NOT NULL,
+    ip VARCHAR(45) NOT NULL,
+    user_agent TEXT NOT NULL,
+    metadata JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_audit_log_user ON audit_log(user_id, created_at);
+CREATE INDEX idx_audit_log_created ON audit_log(created_at);
+
+-- Entries are append-only: they can be pruned by age, never changed.
+CREATE FUNCTION audit_log_reject_update() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_append_only
+BEFORE UPDATE ON audit_log
+FOR EACH ROW EXECUTE FUNCTION audit_log_reject_update();
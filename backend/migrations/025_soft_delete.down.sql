@@ -0,0 +1,78 @@
+DELETE FROM transactions WHERE deleted_at IS NOT NULL;
+DELETE FROM accounts WHERE deleted_at IS NOT NULL;
+DELETE FROM categories WHERE deleted_at IS NOT NULL;
+
+CREATE OR REPLACE VIEW debt_entries AS
+SELECT e.user_id, s.contact_id, e.currency, s.amount
+FROM expense_splits e
+JOIN expense_split_shares s ON s.split_id = e.id
+WHERE e.paid_by IS NULL AND s.contact_id IS NOT NULL
+UNION ALL
+SELECT e.user_id, e.paid_by, e.currency, -s.amount
+FROM expense_splits e
+JOIN expense_split_shares s ON s.split_id = e.id
+WHERE e.paid_by IS NOT NULL AND s.contact_id IS NULL
+UNION ALL
+SELECT d.user_id, d.contact_id, a.currency,
+    CASE t.type WHEN 'income' THEN -t.amount ELSE t.amount END
+FROM debt_settlements d
+JOIN transactions t ON t.id = d.transaction_id
+JOIN accounts a ON a.id = t.account_id;
+
+CREATE OR REPLACE VIEW holdings AS
+SELECT user_id, account_id, security_id,
+    SUM(CASE type WHEN 'buy' THEN quantity WHEN 'sell' THEN -quantity ELSE 0 END) AS quantity
+FROM trades
+GROUP BY user_id, account_id, security_id;
+
+CREATE OR REPLACE VIEW security_quotes AS
+SELECT DISTINCT ON (security_id) security_id, date, price
+FROM (
+    SELECT security_id, date, price, 1 AS rank FROM security_prices
+    UNION ALL
+    SELECT security_id, date, price, 2 AS rank FROM trades WHERE type <> 'dividend'
+) q
+ORDER BY security_id, rank, date DESC;
+
+CREATE OR REPLACE VIEW account_access AS
+SELECT a.id AS account_id, a.user_id, 'owner'::VARCHAR(10) AS role
+FROM accounts a
+UNION ALL
+SELECT a.id, m.user_id, m.role
+FROM accounts a
+JOIN household_members m ON m.household_id = a.household_id
+WHERE m.user_id <> a.user_id;
+
+DROP TRIGGER transactions_account_balance ON transactions;
+CREATE TRIGGER transactions_account_balance
+AFTER INSERT OR UPDATE OF account_id, type, amount OR DELETE ON transactions
+FOR EACH ROW EXECUTE FUNCTION transactions_sync_account_balance();
+
+CREATE OR REPLACE FUNCTION transactions_sync_account_balance() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        UPDATE accounts
+        SET balance = balance - CASE WHEN OLD.type = 'income' THEN OLD.amount ELSE -OLD.amount END
+        WHERE id = OLD.account_id;
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        UPDATE accounts
+        SET balance = balance + CASE WHEN NEW.type = 'income' THEN NEW.amount ELSE -NEW.amount END
+        WHERE id = NEW.account_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP INDEX categories_user_id_name_type_top_level;
+DROP INDEX categories_user_id_parent_id_name_type;
+CREATE UNIQUE INDEX categories_user_id_name_type_top_level ON categories (user_id, name, type) WHERE parent_id IS NULL;
+CREATE UNIQUE INDEX categories_user_id_parent_id_name_type ON categories (user_id, parent_id, name, type) WHERE parent_id IS NOT NULL;
+
+DROP INDEX accounts_user_id_name;
+ALTER TABLE accounts ADD CONSTRAINT accounts_user_id_name_key UNIQUE (user_id, name);
+
+DROP INDEX idx_transactions_deleted;
+ALTER TABLE categories DROP COLUMN deleted_at;
+ALTER TABLE accounts DROP COLUMN deleted_at;
+ALTER TABLE transactions DROP COLUMN deleted_at;
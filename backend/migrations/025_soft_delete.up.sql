@@ -0,0 +1,94 @@
+-- Deleted transactions, accounts and categories go to the trash: deleted_at
+-- is set and every query skips the row until it is restored or a daily job
+-- purges it. Deleting an account trashes its transactions with the same
+-- deleted_at, and restoring it brings back exactly those.
+ALTER TABLE transactions ADD COLUMN deleted_at TIMESTAMPTZ;
+ALTER TABLE accounts ADD COLUMN deleted_at TIMESTAMPTZ;
+ALTER TABLE categories ADD COLUMN deleted_at TIMESTAMPTZ;
+
+CREATE INDEX idx_transactions_deleted ON transactions(user_id, deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- Names only need to be unique among live rows, so a trashed account or
+-- category doesn't block creating a new one with the same name.
+ALTER TABLE accounts DROP CONSTRAINT accounts_user_id_name_key;
+CREATE UNIQUE INDEX accounts_user_id_name ON accounts (user_id, name) WHERE deleted_at IS NULL;
+
+DROP INDEX categories_user_id_name_type_top_level;
+DROP INDEX categories_user_id_parent_id_name_type;
+CREATE UNIQUE INDEX categories_user_id_name_type_top_level ON categories (user_id, name, type) WHERE parent_id IS NULL AND deleted_at IS NULL;
+CREATE UNIQUE INDEX categories_user_id_parent_id_name_type ON categories (user_id, parent_id, name, type) WHERE parent_id IS NOT NULL AND deleted_at IS NULL;
+
+-- Trashed transactions don't count towards the balance: trashing one
+-- reverses it and restoring applies it again.
+CREATE OR REPLACE FUNCTION transactions_sync_account_balance() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') AND OLD.deleted_at IS NULL THEN
+        UPDATE accounts
+        SET balance = balance - CASE WHEN OLD.type = 'income' THEN OLD.amount ELSE -OLD.amount END
+        WHERE id = OLD.account_id;
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') AND NEW.deleted_at IS NULL THEN
+        UPDATE accounts
+        SET balance = balance + CASE WHEN NEW.type = 'income' THEN NEW.amount ELSE -NEW.amount END
+        WHERE id = NEW.account_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER transactions_account_balance ON transactions;
+CREATE TRIGGER transactions_account_balance
+AFTER INSERT OR UPDATE OF account_id, type, amount, deleted_at OR DELETE ON transactions
+FOR EACH ROW EXECUTE FUNCTION transactions_sync_account_balance();
+
+CREATE OR REPLACE VIEW account_access AS
+SELECT a.id AS account_id, a.user_id, 'owner'::VARCHAR(10) AS role
+FROM accounts a
+WHERE a.deleted_at IS NULL
+UNION ALL
+SELECT a.id, m.user_id, m.role
+FROM accounts a
+JOIN household_members m ON m.household_id = a.household_id
+WHERE m.user_id <> a.user_id AND a.deleted_at IS NULL;
+
+-- Trades whose transaction is in the trash neither move holdings nor quote
+-- a price.
+CREATE OR REPLACE VIEW security_quotes AS
+SELECT DISTINCT ON (security_id) security_id, date, price
+FROM (
+    SELECT security_id, date, price, 1 AS rank FROM security_prices
+    UNION ALL
+    SELECT tr.security_id, tr.date, tr.price, 2 AS rank
+    FROM trades tr
+    JOIN transactions t ON t.id = tr.transaction_id
+    WHERE tr.type <> 'dividend' AND t.deleted_at IS NULL
+) q
+ORDER BY security_id, rank, date DESC;
+
+CREATE OR REPLACE VIEW holdings AS
+SELECT tr.user_id, tr.account_id, tr.security_id,
+    SUM(CASE tr.type WHEN 'buy' THEN tr.quantity WHEN 'sell' THEN -tr.quantity ELSE 0 END) AS quantity
+FROM trades tr
+JOIN transactions t ON t.id = tr.transaction_id
+WHERE t.deleted_at IS NULL
+GROUP BY tr.user_id, tr.account_id, tr.security_id;
+
+-- Splits and settlements whose transaction is in the trash don't count.
+CREATE OR REPLACE VIEW debt_entries AS
+SELECT e.user_id, s.contact_id, e.currency, s.amount
+FROM expense_splits e
+JOIN expense_split_shares s ON s.split_id = e.id
+JOIN transactions t ON t.id = e.transaction_id
+WHERE e.paid_by IS NULL AND s.contact_id IS NOT NULL AND t.deleted_at IS NULL
+UNION ALL
+SELECT e.user_id, e.paid_by, e.currency, -s.amount
+FROM expense_splits e
+JOIN expense_split_shares s ON s.split_id = e.id
+WHERE e.paid_by IS NOT NULL AND s.contact_id IS NULL
+UNION ALL
+SELECT d.user_id, d.contact_id, a.currency,
+    CASE t.type WHEN 'income' THEN -t.amount ELSE t.amount END
+FROM debt_settlements d
+JOIN transactions t ON t.id = d.transaction_id
+JOIN accounts a ON a.id = t.account_id
+WHERE t.deleted_at IS NULL;
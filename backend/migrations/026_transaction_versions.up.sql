@@ -0,0 +1,15 @@
+-- Change history of transactions, written by the transaction service on
+-- create, update and delete. changes holds the field-level diff as a list of
+-- {field, old, new}. actor_id is who made the change: the owner or a
+-- household member. It is not a foreign key so the history keeps naming a
+-- member who has since left.
+CREATE TABLE transaction_versions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    transaction_id UUID NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    actor_id UUID NOT NULL,
+    action VARCHAR(10) NOT NULL CHECK (action IN ('create', 'update', 'delete')),
+    changes JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_transaction_versions_transaction ON transaction_versions(transaction_id, created_at);
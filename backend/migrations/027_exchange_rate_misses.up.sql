@@ -0,0 +1,13 @@
+-- Backfill attempts that found no rate for a pair on a date: the fetch
+-- failed, or the upstream's answer didn't include the target currency (a
+-- date before its history starts, a currency it doesn't quote). Once a pair
+-- and date have failed often enough, ListMissingRateDates stops returning
+-- them so the backfill moves on to newer gaps.
+CREATE TABLE exchange_rate_misses (
+    from_currency VARCHAR(3) NOT NULL REFERENCES currencies(code) ON DELETE CASCADE,
+    to_currency VARCHAR(3) NOT NULL REFERENCES currencies(code) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    attempts INT NOT NULL DEFAULT 1,
+    last_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (from_currency, to_currency, date)
+);
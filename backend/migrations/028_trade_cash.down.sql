@@ -0,0 +1,4 @@
+UPDATE transactions t
+SET transfer_id = tr.id
+FROM trades tr
+WHERE tr.transaction_id = t.id AND tr.type <> 'dividend';
@@ -0,0 +1,6 @@
+-- Trade cash transactions are identified through trades.transaction_id; a
+-- transfer_id on them made them look like transfers with a missing leg.
+UPDATE transactions t
+SET transfer_id = NULL
+FROM trades tr
+WHERE tr.transaction_id = t.id;
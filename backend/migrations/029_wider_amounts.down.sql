@@ -0,0 +1,75 @@
+DROP VIEW debt_entries;
+DROP VIEW holdings;
+DROP VIEW security_quotes;
+
+ALTER TABLE accounts
+    ALTER COLUMN initial_balance TYPE DECIMAL(20,8),
+    ALTER COLUMN balance TYPE DECIMAL(20,8);
+ALTER TABLE transactions
+    ALTER COLUMN amount TYPE DECIMAL(20,8);
+ALTER TABLE reconciliations
+    ALTER COLUMN statement_balance TYPE DECIMAL(20,8);
+ALTER TABLE balance_assertions
+    ALTER COLUMN balance TYPE DECIMAL(20,8);
+ALTER TABLE credit_cards
+    ALTER COLUMN credit_limit TYPE DECIMAL(20,8),
+    ALTER COLUMN min_payment_amount TYPE DECIMAL(20,8);
+ALTER TABLE loans
+    ALTER COLUMN principal TYPE DECIMAL(20,8);
+ALTER TABLE loan_payments
+    ALTER COLUMN principal TYPE DECIMAL(20,8),
+    ALTER COLUMN interest TYPE DECIMAL(20,8);
+ALTER TABLE security_prices
+    ALTER COLUMN price TYPE DECIMAL(20,8);
+ALTER TABLE trades
+    ALTER COLUMN quantity TYPE DECIMAL(20,8),
+    ALTER COLUMN price TYPE DECIMAL(20,8),
+    ALTER COLUMN fee TYPE DECIMAL(20,8);
+ALTER TABLE interest_accruals
+    ALTER COLUMN amount TYPE DECIMAL(20,8);
+ALTER TABLE expense_splits
+    ALTER COLUMN amount TYPE DECIMAL(20,8);
+ALTER TABLE expense_split_shares
+    ALTER COLUMN amount TYPE DECIMAL(20,8);
+
+-- Trades whose transaction is in the trash neither move holdings nor quote
+-- a price.
+CREATE VIEW security_quotes AS
+SELECT DISTINCT ON (security_id) security_id, date, price
+FROM (
+    SELECT security_id, date, price, 1 AS rank FROM security_prices
+    UNION ALL
+    SELECT tr.security_id, tr.date, tr.price, 2 AS rank
+    FROM trades tr
+    JOIN transactions t ON t.id = tr.transaction_id
+    WHERE tr.type <> 'dividend' AND t.deleted_at IS NULL
+) q
+ORDER BY security_id, rank, date DESC;
+
+CREATE VIEW holdings AS
+SELECT tr.user_id, tr.account_id, tr.security_id,
+    SUM(CASE tr.type WHEN 'buy' THEN tr.quantity WHEN 'sell' THEN -tr.quantity ELSE 0 END) AS quantity
+FROM trades tr
+JOIN transactions t ON t.id = tr.transaction_id
+WHERE t.deleted_at IS NULL
+GROUP BY tr.user_id, tr.account_id, tr.security_id;
+
+-- Splits and settlements whose transaction is in the trash don't count.
+CREATE VIEW debt_entries AS
+SELECT e.user_id, s.contact_id, e.currency, s.amount
+FROM expense_splits e
+JOIN expense_split_shares s ON s.split_id = e.id
+JOIN transactions t ON t.id = e.transaction_id
+WHERE e.paid_by IS NULL AND s.contact_id IS NOT NULL AND t.deleted_at IS NULL
+UNION ALL
+SELECT e.user_id, e.paid_by, e.currency, -s.amount
+FROM expense_splits e
+JOIN expense_split_shares s ON s.split_id = e.id
+WHERE e.paid_by IS NOT NULL AND s.contact_id IS NULL
+UNION ALL
+SELECT d.user_id, d.contact_id, a.currency,
+    CASE t.type WHEN 'income' THEN -t.amount ELSE t.amount END
+FROM debt_settlements d
+JOIN transactions t ON t.id = d.transaction_id
+JOIN accounts a ON a.id = t.account_id
+WHERE t.deleted_at IS NULL;
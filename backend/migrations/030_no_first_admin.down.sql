@@ -0,0 +1,2 @@
+UPDATE users SET is_admin = true
+WHERE id = (SELECT id FROM users ORDER BY created_at, id LIMIT 1);
@@ -0,0 +1,5 @@
+-- Admins come only from ADMIN_USERNAMES, which promotes them on every
+-- startup. Migration 008 made the earliest registered user an admin; revoke
+-- that, and list them in ADMIN_USERNAMES to keep them one.
+UPDATE users SET is_admin = false
+WHERE id = (SELECT id FROM users ORDER BY created_at, id LIMIT 1);
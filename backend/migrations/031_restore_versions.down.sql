@@ -0,0 +1,4 @@
+DELETE FROM transaction_versions WHERE action = 'restore';
+ALTER TABLE transaction_versions DROP CONSTRAINT transaction_versions_action_check;
+ALTER TABLE transaction_versions ADD CONSTRAINT transaction_versions_action_check
+    CHECK (action IN ('create', 'update', 'delete'));
@@ -0,0 +1,5 @@
+-- Restoring a transaction from the trash is recorded too, so the history of
+-- a live transaction doesn't end in a delete.
+ALTER TABLE transaction_versions DROP CONSTRAINT transaction_versions_action_check;
+ALTER TABLE transaction_versions ADD CONSTRAINT transaction_versions_action_check
+    CHECK (action IN ('create', 'update', 'delete', 'restore'));
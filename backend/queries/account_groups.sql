@@ -0,0 +1,33 @@
+-- name: CreateAccountGroup :one
+-- New groups go last in their user's order.
+INSERT INTO account_groups (user_id, name, sort_order)
+VALUES ($1, $2, (SELECT COALESCE(MAX(sort_order) + 1, 0) FROM account_groups WHERE user_id = $1))
+RETURNING *;
+
+-- name: GetAccountGroup :one
+SELECT * FROM account_groups WHERE id = $1 AND user_id = $2;
+
+-- name: ListAccountGroups :many
+SELECT * FROM account_groups WHERE user_id = $1 ORDER BY sort_order, name;
+
+-- name: UpdateAccountGroup :one
+UPDATE account_groups
+SET name = $3, updated_at = now()
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: DeleteAccountGroup :execrows
+-- The group's accounts become ungrouped.
+DELETE FROM account_groups WHERE id = $1 AND user_id = $2;
+
+-- name: DeleteAllUserAccountGroups :exec
+DELETE FROM account_groups WHERE user_id = $1;
+
+-- name: ReorderAccountGroups :execrows
+-- Sets each group's sort_order to its position in group_ids. Nothing is
+-- updated unless the ids are distinct groups of the user.
+UPDATE account_groups g
+SET sort_order = o.position - 1, updated_at = now()
+FROM unnest(@group_ids::UUID[]) WITH ORDINALITY AS o(id, position)
+WHERE g.id = o.id AND g.user_id = @user_id
+  AND (SELECT COUNT(*) FROM account_groups WHERE user_id = @user_id AND id = ANY(@group_ids)) = cardinality(@group_ids);
@@ -0,0 +1,38 @@
+-- name: GetAccountInterest :one
+SELECT * FROM account_interest WHERE account_id = $1 AND user_id = $2;
+
+-- name: UpsertAccountInterest :one
+INSERT INTO account_interest (account_id, user_id, annual_rate, compounding, start_date, payout_account_id, category_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (account_id) DO UPDATE
+SET annual_rate = EXCLUDED.annual_rate,
+    compounding = EXCLUDED.compounding,
+    start_date = EXCLUDED.start_date,
+    payout_account_id = EXCLUDED.payout_account_id,
+    category_id = EXCLUDED.category_id,
+    updated_at = now()
+RETURNING *;
+
+-- name: DeleteAccountInterest :execrows
+DELETE FROM account_interest WHERE account_id = $1 AND user_id = $2;
+
+-- name: ListAccountInterest :many
+-- Interest terms of every account that is not archived, across all users.
+SELECT ai.* FROM account_interest ai
+JOIN accounts a ON a.id = ai.account_id
+WHERE a.archived_on IS NULL AND a.deleted_at IS NULL
+ORDER BY ai.account_id;
+
+-- name: GetInterestAccruedThrough :one
+-- End of the last credited period, NULL if none.
+SELECT MAX(period_end)::DATE AS accrued_through FROM interest_accruals WHERE account_id = $1;
+
+-- name: CreateInterestAccrual :one
+INSERT INTO interest_accruals (account_id, user_id, period_start, period_end, amount, transaction_id)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: ListInterestAccruals :many
+SELECT * FROM interest_accruals
+WHERE account_id = $1 AND user_id = $2
+ORDER BY period_end DESC;
@@ -1,42 +1,176 @@
 -- name: CreateAccount :one
-INSERT INTO accounts (user_id, name, type, currency, initial_balance)
-VALUES ($1, $2, $3, $4, $5)
+-- New accounts go last in their user's order.
+INSERT INTO accounts (user_id, name, type, currency, initial_balance, group_id, household_id, sort_order)
+VALUES ($1, $2, $3, $4, $5, $6, $7, (SELECT COALESCE(MAX(sort_order) + 1, 0) FROM accounts WHERE user_id = $1))
 RETURNING *;
 
+-- name: GetAccessibleAccount :one
+-- Returns an account the user owns or shares through a household, with the
+-- user's role on it.
+SELECT sqlc.embed(a), aa.role
+FROM accounts a
+JOIN account_access aa ON aa.account_id = a.id
+WHERE a.id = @id AND aa.user_id = @user_id;
+
 -- name: GetAccount :one
-SELECT * FROM accounts WHERE id = $1 AND user_id = $2;
+SELECT * FROM accounts WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL;
 
 -- name: ListAccounts :many
-SELECT a.*,
-  COALESCE(COUNT(t.id), 0)::INTEGER AS recent_tx_count
+-- Accounts the user can see, their own and those shared with them through a
+-- household, come group by group in the groups' order, ungrouped ones last,
+-- each in the accounts' own order. Groups are the user's own, so shared
+-- accounts of other members are ungrouped. market_value is what the
+-- account's securities are worth at their latest quotes; it is zero for
+-- accounts without trades. role is the user's access to the account.
+SELECT a.id, a.user_id, a.name, a.type, a.currency, a.initial_balance, a.created_at, a.updated_at, a.balance, a.archived_on,
+  (CASE WHEN a.user_id = $1 THEN a.group_id END)::UUID AS group_id,
+  a.sort_order, a.household_id,
+  COALESCE(COUNT(t.id), 0)::INTEGER AS recent_tx_count,
+  COALESCE((
+    SELECT SUM(h.quantity * q.price)
+    FROM holdings h
+    JOIN security_quotes q ON q.security_id = h.security_id
+    WHERE h.account_id = a.id
+  ), 0)::DECIMAL(24,8) AS market_value,
+  aa.role
 FROM accounts a
+JOIN account_access aa ON aa.account_id = a.id AND aa.user_id = $1
+LEFT JOIN account_groups g ON g.id = a.group_id AND g.user_id = $1
 LEFT JOIN transactions t
   ON t.account_id = a.id
   AND t.type = 'expense'
   AND t.transfer_id IS NULL
+  AND NOT EXISTS (SELECT 1 FROM trades tr WHERE tr.transaction_id = t.id AND tr.type <> 'dividend')
   AND t.date >= CURRENT_DATE - INTERVAL '30 days'
-WHERE a.user_id = $1
-GROUP BY a.id
-ORDER BY name;
+  AND t.deleted_at IS NULL
+WHERE (sqlc.arg(include_archived)::boolean OR a.archived_on IS NULL)
+GROUP BY a.id, g.id, aa.role
+ORDER BY g.sort_order NULLS LAST, g.name, a.sort_order, a.name;
+
+-- name: GetAccountMarketValue :one
+SELECT COALESCE(SUM(h.quantity * q.price), 0)::DECIMAL(24,8) AS market_value
+FROM holdings h
+JOIN security_quotes q ON q.security_id = h.security_id
+WHERE h.account_id = $1 AND h.user_id = $2;
 
 -- name: UpdateAccount :one
 UPDATE accounts
-SET name = $2, type = $3, initial_balance = $4, updated_at = now()
-WHERE id = $1 AND user_id = sqlc.arg(user_id)
+SET name = $2, type = $3, initial_balance = $4, group_id = sqlc.narg(group_id), household_id = sqlc.narg(household_id), updated_at = now()
+WHERE id = $1 AND user_id = sqlc.arg(user_id) AND deleted_at IS NULL
+RETURNING *;
+
+-- name: ReorderAccounts :execrows
+-- Sets each account's sort_order to its position in account_ids. Nothing
+-- is updated unless the ids are distinct accounts of the user.
+UPDATE accounts a
+SET sort_order = o.position - 1, updated_at = now()
+FROM unnest(@account_ids::UUID[]) WITH ORDINALITY AS o(id, position)
+WHERE a.id = o.id AND a.user_id = @user_id AND a.deleted_at IS NULL
+  AND (SELECT COUNT(*) FROM accounts WHERE user_id = @user_id AND id = ANY(@account_ids) AND deleted_at IS NULL) = cardinality(@account_ids);
+
+-- name: SetAccountArchived :one
+-- Archives the account on the given date, or unarchives it when the date is
+-- NULL.
+UPDATE accounts
+SET archived_on = sqlc.narg(archived_on), updated_at = now()
+WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
 RETURNING *;
 
--- name: DeleteAccount :exec
-DELETE FROM accounts WHERE id = $1 AND user_id = $2;
+-- name: TrashAccount :one
+-- Moves the account and its transactions to the trash, all stamped with the
+-- same deleted_at so RestoreAccount brings back exactly these. Transfers go
+-- whole, with their other leg and a loan payment's interest, so no account
+-- is left with half of one. Transactions trashed earlier keep their own
+-- deleted_at. Each transaction trashed here gets a delete version by the user.
+WITH account AS (
+    UPDATE accounts SET deleted_at = now()
+    WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+    RETURNING id, deleted_at
+), transfers AS (
+    SELECT t.transfer_id FROM transactions t
+    JOIN account ON account.id = t.account_id
+    WHERE t.transfer_id IS NOT NULL AND t.deleted_at IS NULL
+), trashed AS (
+    UPDATE transactions t SET deleted_at = account.deleted_at
+    FROM account
+    WHERE t.deleted_at IS NULL
+        AND (t.account_id = account.id
+            OR t.transfer_id IN (SELECT transfer_id FROM transfers)
+            OR t.id IN (
+                SELECT lp.interest_id FROM loan_payments lp
+                JOIN transactions p ON p.id = lp.payment_id
+                WHERE p.transfer_id IN (SELECT transfer_id FROM transfers)
+            ))
+    RETURNING t.id
+), versions AS (
+    INSERT INTO transaction_versions (transaction_id, actor_id, action)
+    SELECT id, $2, 'delete' FROM trashed
+)
+SELECT deleted_at FROM account;
+
+-- name: ListTrashedAccounts :many
+SELECT * FROM accounts
+WHERE user_id = $1 AND deleted_at IS NOT NULL
+ORDER BY deleted_at DESC, name;
+
+-- name: RestoreAccount :one
+-- Brings a trashed account back with the transactions trashed along with it.
+-- A transfer whose other account has since been trashed too stays in the
+-- trash with that account, to come back when it does. Each transaction
+-- restored gets a restore version by the user.
+WITH account AS (
+    SELECT id, deleted_at FROM accounts
+    WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL
+), restored_transactions AS (
+    UPDATE transactions t SET deleted_at = (
+        SELECT oa.deleted_at FROM transactions o
+        JOIN accounts oa ON oa.id = o.account_id
+        WHERE oa.id <> account.id AND oa.deleted_at IS NOT NULL
+            AND (o.id = t.id OR o.transfer_id = t.transfer_id)
+        LIMIT 1
+    )
+    FROM account
+    WHERE t.user_id = $2 AND t.deleted_at = account.deleted_at
+    RETURNING t.id, t.deleted_at
+), versions AS (
+    INSERT INTO transaction_versions (transaction_id, actor_id, action)
+    SELECT id, $2, 'restore' FROM restored_transactions WHERE deleted_at IS NULL
+), restored AS (
+    UPDATE accounts a SET deleted_at = NULL
+    FROM account
+    WHERE a.id = account.id
+    RETURNING a.*
+)
+SELECT * FROM restored;
+
+-- name: PurgeAccounts :execrows
+-- Hard-deletes accounts trashed before the cutoff, cascading to whatever
+-- still references them.
+DELETE FROM accounts WHERE deleted_at < $1;
 
 -- name: GetAccountByName :one
-SELECT * FROM accounts WHERE user_id = $1 AND name = $2;
+SELECT * FROM accounts WHERE user_id = $1 AND name = $2 AND deleted_at IS NULL;
 
--- name: GetAccountTransactionSums :one
-SELECT
-    COALESCE(SUM(CASE WHEN type = 'income' THEN amount ELSE 0 END), 0)::DECIMAL(15,2) AS total_income,
-    COALESCE(SUM(CASE WHEN type = 'expense' THEN amount ELSE 0 END), 0)::DECIMAL(15,2) AS total_expense
-FROM transactions
-WHERE account_id = $1;
+-- name: ReconcileAccountBalances :many
+-- Recomputes the materialized balance of each of the user's accounts from its
+-- transactions and fixes the ones that drifted, returning them with the
+-- balance they had before.
+WITH computed AS (
+    SELECT a.id,
+        a.balance AS stored_balance,
+        a.initial_balance + COALESCE(SUM(CASE WHEN t.type = 'income' THEN t.amount ELSE -t.amount END), 0) AS computed_balance
+    FROM accounts a
+    LEFT JOIN transactions t ON t.account_id = a.id AND t.deleted_at IS NULL
+    WHERE a.user_id = $1 AND a.deleted_at IS NULL
+    GROUP BY a.id
+)
+UPDATE accounts a
+SET balance = c.computed_balance
+FROM computed c
+WHERE a.id = c.id AND a.balance <> c.computed_balance
+RETURNING a.id, a.name, a.currency,
+    c.stored_balance::DECIMAL(24,8) AS stored_balance,
+    a.balance;
 
 -- name: DeleteAllUserAccounts :exec
 DELETE FROM accounts WHERE user_id = $1;
@@ -44,4 +178,4 @@ DELETE FROM accounts WHERE user_id = $1;
 -- name: ListDistinctAccountCurrencies :many
 -- Intentionally not scoped to a user: exchange rates are global, and the sync
 -- needs all currencies in use across all accounts.
-SELECT DISTINCT currency FROM accounts ORDER BY currency;
+SELECT DISTINCT currency FROM accounts WHERE deleted_at IS NULL ORDER BY currency;
@@ -0,0 +1,11 @@
+-- name: GetInstanceStats :one
+-- Instance-wide counts for administrators.
+SELECT
+    (SELECT COUNT(*) FROM users) AS users,
+    (SELECT COUNT(*) FROM users WHERE disabled_at IS NOT NULL) AS disabled_users,
+    (SELECT COUNT(*) FROM users WHERE is_admin) AS admins,
+    (SELECT COUNT(*) FROM accounts) AS accounts,
+    (SELECT COUNT(*) FROM transactions) AS transactions,
+    (SELECT COUNT(*) FROM currencies) AS currencies,
+    (SELECT COUNT(*) FROM exchange_rates WHERE user_id IS NULL) AS global_exchange_rates,
+    (SELECT MAX(date) FROM exchange_rates WHERE user_id IS NULL)::DATE AS latest_rate_date;
@@ -0,0 +1,19 @@
+-- name: CreateAuditEntry :exec
+INSERT INTO audit_log (user_id, actor_id, action, ip, user_agent, metadata)
+VALUES ($1, $2, $3, $4, $5, $6);
+
+-- name: ListAuditEntries :many
+SELECT * FROM audit_log
+WHERE user_id = @user_id
+    AND (sqlc.narg('action')::VARCHAR IS NULL OR action = sqlc.narg('action'))
+ORDER BY created_at DESC
+LIMIT @lim OFFSET @off;
+
+-- name: CountAuditEntries :one
+SELECT COUNT(*) FROM audit_log
+WHERE user_id = @user_id
+    AND (sqlc.narg('action')::VARCHAR IS NULL OR action = sqlc.narg('action'));
+
+-- name: PruneAuditLog :execrows
+-- Deletes entries older than the retention period.
+DELETE FROM audit_log WHERE created_at < $1;
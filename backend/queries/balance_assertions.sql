@@ -0,0 +1,40 @@
+-- name: CreateBalanceAssertion :one
+INSERT INTO balance_assertions (user_id, account_id, date, balance, note)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetBalanceAssertion :one
+SELECT * FROM balance_assertions WHERE id = $1 AND user_id = $2;
+
+-- name: ListBalanceAssertions :many
+-- Every assertion next to the balance its account actually had at the end of
+-- the asserted day.
+SELECT
+    ba.*,
+    a.currency,
+    (a.initial_balance + COALESCE((
+        SELECT SUM(CASE WHEN t.type = 'income' THEN t.amount ELSE -t.amount END)
+        FROM transactions t
+        WHERE t.account_id = ba.account_id AND t.date <= ba.date AND t.deleted_at IS NULL
+    ), 0))::DECIMAL(24,8) AS computed_balance
+FROM balance_assertions ba
+JOIN accounts a ON a.id = ba.account_id
+WHERE ba.user_id = @user_id
+    AND a.deleted_at IS NULL
+    AND (sqlc.narg('account_id')::uuid IS NULL OR ba.account_id = sqlc.narg('account_id'))
+ORDER BY a.name, ba.date;
+
+-- name: DeleteBalanceAssertion :execrows
+DELETE FROM balance_assertions WHERE id = $1 AND user_id = $2;
+
+-- name: GetAccountBalanceAt :one
+-- Account balance at the end of the given day.
+SELECT
+    (a.initial_balance + COALESCE(SUM(CASE WHEN t.type = 'income' THEN t.amount ELSE -t.amount END), 0))::DECIMAL(24,8) AS balance
+FROM accounts a
+LEFT JOIN transactions t
+    ON t.account_id = a.id
+    AND t.date <= @date
+    AND t.deleted_at IS NULL
+WHERE a.id = @account_id AND a.user_id = @user_id
+GROUP BY a.id, a.initial_balance;
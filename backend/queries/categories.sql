@@ -1,9 +1,10 @@
 -- name: CreateCategory :one
-INSERT INTO categories (user_id, parent_id, name, type)
-VALUES ($1, $2, $3, $4)
+INSERT INTO categories (user_id, parent_id, name, type, household_id)
+VALUES ($1, $2, $3, $4, $5)
 RETURNING *;
 
 -- name: ListCategories :many
+-- The user's own categories and those shared with their households.
 SELECT c.*,
   COALESCE(COUNT(t.id), 0)::INTEGER AS recent_tx_count
 FROM categories c
@@ -12,33 +13,63 @@ LEFT JOIN transactions t
   AND t.type = 'expense'
   AND t.transfer_id IS NULL
   AND t.date >= CURRENT_DATE - INTERVAL '30 days'
-WHERE c.user_id = $1
+  AND t.deleted_at IS NULL
+WHERE (c.user_id = $1
+  OR c.household_id IN (SELECT household_id FROM household_members WHERE user_id = $1))
+  AND c.deleted_at IS NULL
 GROUP BY c.id
 ORDER BY c.type, c.name;
 
 -- name: GetCategory :one
-SELECT * FROM categories WHERE id = $1 AND user_id = $2;
+SELECT * FROM categories WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL;
 
 -- name: UpdateCategory :one
 UPDATE categories
-SET name = $2, parent_id = $3
-WHERE id = $1 AND user_id = sqlc.arg(user_id)
+SET name = $2, parent_id = $3, household_id = sqlc.narg(household_id)
+WHERE id = $1 AND user_id = sqlc.arg(user_id) AND deleted_at IS NULL
 RETURNING *;
 
--- name: DeleteCategory :exec
-DELETE FROM categories WHERE id = $1 AND user_id = $2;
+-- name: TrashCategory :exec
+UPDATE categories SET deleted_at = now()
+WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL;
+
+-- name: ListTrashedCategories :many
+SELECT * FROM categories
+WHERE user_id = $1 AND deleted_at IS NOT NULL
+ORDER BY deleted_at DESC, name;
+
+-- name: GetTrashedCategory :one
+-- A trashed category with whether its parent is in the trash too, in which
+-- case the parent has to be restored first.
+SELECT sqlc.embed(c), COALESCE(p.deleted_at IS NOT NULL, false)::BOOLEAN AS parent_trashed
+FROM categories c
+LEFT JOIN categories p ON p.id = c.parent_id
+WHERE c.id = $1 AND c.user_id = $2 AND c.deleted_at IS NOT NULL;
+
+-- name: RestoreCategory :one
+UPDATE categories SET deleted_at = NULL
+WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL
+RETURNING *;
+
+-- name: PurgeCategories :execrows
+-- Hard-deletes categories trashed before the cutoff. A category still used
+-- by a transaction or subcategory waits until those are purged.
+DELETE FROM categories c
+WHERE c.deleted_at < $1
+    AND NOT EXISTS (SELECT 1 FROM transactions t WHERE t.category_id = c.id)
+    AND NOT EXISTS (SELECT 1 FROM categories s WHERE s.parent_id = c.id);
 
 -- name: HasChildCategories :one
-SELECT EXISTS(SELECT 1 FROM categories WHERE parent_id = $1) AS has_children;
+SELECT EXISTS(SELECT 1 FROM categories WHERE parent_id = $1 AND deleted_at IS NULL) AS has_children;
 
 -- name: HasCategoryTransactions :one
-SELECT EXISTS(SELECT 1 FROM transactions WHERE category_id = $1) AS has_transactions;
+SELECT EXISTS(SELECT 1 FROM transactions WHERE category_id = $1 AND deleted_at IS NULL) AS has_transactions;
 
 -- name: GetCategoryByNameAndType :one
-SELECT * FROM categories WHERE user_id = $1 AND name = $2 AND type = $3 AND parent_id IS NULL;
+SELECT * FROM categories WHERE user_id = $1 AND name = $2 AND type = $3 AND parent_id IS NULL AND deleted_at IS NULL;
 
 -- name: GetSubcategoryByNameAndType :one
-SELECT * FROM categories WHERE user_id = $1 AND name = $2 AND type = $3 AND parent_id = $4;
+SELECT * FROM categories WHERE user_id = $1 AND name = $2 AND type = $3 AND parent_id = $4 AND deleted_at IS NULL;
 
 -- name: CreateDefaultCategories :exec
 INSERT INTO categories (user_id, name, type) VALUES
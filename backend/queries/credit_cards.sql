@@ -0,0 +1,28 @@
+-- name: GetCreditCard :one
+SELECT * FROM credit_cards WHERE account_id = $1 AND user_id = $2;
+
+-- name: UpsertCreditCard :one
+INSERT INTO credit_cards (account_id, user_id, credit_limit, statement_day, payment_due_day, min_payment_percent, min_payment_amount)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (account_id) DO UPDATE
+SET credit_limit = EXCLUDED.credit_limit,
+    statement_day = EXCLUDED.statement_day,
+    payment_due_day = EXCLUDED.payment_due_day,
+    min_payment_percent = EXCLUDED.min_payment_percent,
+    min_payment_amount = EXCLUDED.min_payment_amount,
+    updated_at = now()
+RETURNING *;
+
+-- name: GetCardPayments :one
+-- Transfers into the card dated after date_from, up to and including date_to.
+SELECT
+    COALESCE(SUM(amount), 0)::DECIMAL(24,8) AS total,
+    COUNT(*)::INTEGER AS count
+FROM transactions
+WHERE account_id = @account_id
+    AND user_id = @user_id
+    AND type = 'income'
+    AND deleted_at IS NULL
+    AND transfer_id IS NOT NULL
+    AND date > @date_from
+    AND date <= @date_to;
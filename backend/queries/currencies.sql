@@ -8,7 +8,7 @@ SELECT * FROM currencies WHERE code = $1;
 SELECT * FROM currencies WHERE symbol = $1;
 
 -- name: CreateCurrency :one
-INSERT INTO currencies (code, name, symbol) VALUES ($1, $2, $3) RETURNING *;
+INSERT INTO currencies (code, name, symbol, decimals) VALUES ($1, $2, $3, $4) RETURNING *;
 
 -- name: UpdateCurrency :one
 UPDATE currencies SET name = $2 WHERE code = $1 RETURNING *;
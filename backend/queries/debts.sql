@@ -0,0 +1,88 @@
+-- name: CreateContact :one
+INSERT INTO contacts (user_id, name)
+VALUES ($1, $2)
+RETURNING *;
+
+-- name: GetContact :one
+SELECT * FROM contacts WHERE id = $1 AND user_id = $2;
+
+-- name: ListContacts :many
+SELECT * FROM contacts WHERE user_id = $1 ORDER BY name;
+
+-- name: UpdateContact :one
+UPDATE contacts
+SET name = $3, updated_at = now()
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: DeleteContact :execrows
+DELETE FROM contacts WHERE id = $1 AND user_id = $2;
+
+-- name: HasContactDebts :one
+-- Whether any split or settlement refers to the contact.
+SELECT EXISTS(SELECT 1 FROM expense_split_shares WHERE contact_id = @contact_id)
+    OR EXISTS(SELECT 1 FROM expense_splits WHERE paid_by = @contact_id)
+    OR EXISTS(SELECT 1 FROM debt_settlements WHERE contact_id = @contact_id) AS has_debts;
+
+-- name: DeleteAllUserContacts :exec
+DELETE FROM contacts WHERE user_id = $1;
+
+-- name: CreateExpenseSplit :one
+INSERT INTO expense_splits (user_id, transaction_id, paid_by, method, description, date, currency, amount)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: CreateExpenseSplitShare :exec
+INSERT INTO expense_split_shares (split_id, contact_id, amount)
+VALUES ($1, $2, $3);
+
+-- name: GetExpenseSplit :one
+SELECT * FROM expense_splits WHERE id = $1 AND user_id = $2
+    AND NOT EXISTS(SELECT 1 FROM transactions t WHERE t.id = transaction_id AND t.deleted_at IS NOT NULL);
+
+-- name: ListExpenseSplits :many
+-- The user's splits, newest first, optionally only those a contact took
+-- part in or paid.
+SELECT * FROM expense_splits e
+WHERE e.user_id = $1
+    AND NOT EXISTS(SELECT 1 FROM transactions t WHERE t.id = e.transaction_id AND t.deleted_at IS NOT NULL)
+    AND (sqlc.narg('contact_id')::uuid IS NULL
+        OR e.paid_by = sqlc.narg('contact_id')
+        OR EXISTS(SELECT 1 FROM expense_split_shares s WHERE s.split_id = e.id AND s.contact_id = sqlc.narg('contact_id')))
+ORDER BY e.date DESC, e.created_at DESC;
+
+-- name: ListExpenseSplitShares :many
+-- Shares of the given splits with the contacts' names; the user's own
+-- share comes first.
+SELECT s.split_id, s.contact_id, s.amount, c.name AS contact_name
+FROM expense_split_shares s
+LEFT JOIN contacts c ON c.id = s.contact_id
+WHERE s.split_id = ANY(@split_ids::UUID[])
+ORDER BY s.split_id, s.contact_id IS NOT NULL, c.name;
+
+-- name: DeleteExpenseSplit :execrows
+DELETE FROM expense_splits WHERE id = $1 AND user_id = $2;
+
+-- name: DeleteAllUserExpenseSplits :exec
+DELETE FROM expense_splits WHERE user_id = $1;
+
+-- name: CreateDebtSettlement :one
+INSERT INTO debt_settlements (id, user_id, contact_id, transaction_id)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: ListDebtBalances :many
+-- Non-zero balance with each contact per currency; positive means the
+-- contact owes the user.
+SELECT c.id AS contact_id, c.name, d.currency, SUM(d.amount)::DECIMAL(24,8) AS balance
+FROM debt_entries d
+JOIN contacts c ON c.id = d.contact_id
+WHERE d.user_id = $1
+GROUP BY c.id, c.name, d.currency
+HAVING SUM(d.amount) <> 0
+ORDER BY c.name, d.currency;
+
+-- name: GetDebtBalance :one
+SELECT COALESCE(SUM(amount), 0)::DECIMAL(24,8) AS balance
+FROM debt_entries
+WHERE user_id = $1 AND contact_id = $2 AND currency = $3;
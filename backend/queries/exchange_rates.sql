@@ -1,15 +1,77 @@
 -- name: ListExchangeRates :many
-SELECT * FROM exchange_rates ORDER BY date DESC, from_currency, to_currency;
+-- Global rates plus the user's personal overrides.
+SELECT * FROM exchange_rates
+WHERE user_id IS NULL OR user_id = @user_id::uuid
+ORDER BY date DESC, from_currency, to_currency, user_id NULLS FIRST;
 
 -- name: GetLatestRate :one
 SELECT * FROM exchange_rates
-WHERE from_currency = $1 AND to_currency = $2
+WHERE from_currency = $1 AND to_currency = $2 AND user_id IS NULL
 ORDER BY date DESC
 LIMIT 1;
 
+-- name: GetRateOnOrBefore :one
+-- The latest rate on or before the date; on the same date the user's
+-- personal rate wins over the global one.
+SELECT * FROM exchange_rates
+WHERE from_currency = @from_currency AND to_currency = @to_currency AND date <= @date
+    AND (user_id IS NULL OR user_id = @user_id::uuid)
+ORDER BY date DESC, user_id IS NULL
+LIMIT 1;
+
 -- name: UpsertExchangeRate :one
-INSERT INTO exchange_rates (from_currency, to_currency, rate, date)
-VALUES ($1, $2, $3, $4)
-ON CONFLICT (from_currency, to_currency, date)
-DO UPDATE SET rate = EXCLUDED.rate
+INSERT INTO exchange_rates (from_currency, to_currency, rate, date, source)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (from_currency, to_currency, date) WHERE user_id IS NULL
+DO UPDATE SET rate = EXCLUDED.rate, source = EXCLUDED.source
+RETURNING *;
+
+-- name: UpsertUserExchangeRate :one
+INSERT INTO exchange_rates (user_id, from_currency, to_currency, rate, date, source)
+VALUES (@user_id::uuid, @from_currency, @to_currency, @rate, @date, @source)
+ON CONFLICT (user_id, from_currency, to_currency, date) WHERE user_id IS NOT NULL
+DO UPDATE SET rate = EXCLUDED.rate, source = EXCLUDED.source
 RETURNING *;
+
+-- name: DeleteUserExchangeRate :execrows
+DELETE FROM exchange_rates WHERE id = @id AND user_id = @user_id::uuid;
+
+-- name: DeleteGlobalExchangeRate :execrows
+DELETE FROM exchange_rates WHERE id = $1 AND user_id IS NULL;
+
+-- name: DeleteAllUserExchangeRates :exec
+DELETE FROM exchange_rates WHERE user_id = @user_id::uuid;
+
+-- name: ListMissingRateDates :many
+-- Intentionally not scoped to a user: backfilled rates are global. Returns
+-- every (account currency -> owner's base currency, date) combination that has
+-- transactions but no stored global rate, oldest first. Combinations the
+-- backfill already failed to find max_attempts times are left out.
+SELECT DISTINCT a.currency AS from_currency, u.base_currency AS to_currency, t.date
+FROM transactions t
+JOIN accounts a ON a.id = t.account_id
+JOIN users u ON u.id = a.user_id
+WHERE a.currency <> u.base_currency
+    AND t.deleted_at IS NULL
+    AND NOT EXISTS (
+        SELECT 1 FROM exchange_rates er
+        WHERE er.from_currency = a.currency
+            AND er.to_currency = u.base_currency
+            AND er.date = t.date
+            AND er.user_id IS NULL
+    )
+    AND NOT EXISTS (
+        SELECT 1 FROM exchange_rate_misses m
+        WHERE m.from_currency = a.currency
+            AND m.to_currency = u.base_currency
+            AND m.date = t.date
+            AND m.attempts >= @max_attempts
+    )
+ORDER BY t.date, from_currency, to_currency
+LIMIT @lim;
+
+-- name: RecordRateMiss :exec
+INSERT INTO exchange_rate_misses (from_currency, to_currency, date)
+VALUES ($1, $2, $3)
+ON CONFLICT (from_currency, to_currency, date)
+DO UPDATE SET attempts = exchange_rate_misses.attempts + 1, last_attempt_at = now();
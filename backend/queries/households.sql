@@ -0,0 +1,49 @@
+-- name: CreateHousehold :one
+INSERT INTO households (name)
+VALUES ($1)
+RETURNING *;
+
+-- name: GetHousehold :one
+-- Returns a household the user is a member of, with their role in it.
+SELECT h.id, h.name, h.created_at, h.updated_at, m.role
+FROM households h
+JOIN household_members m ON m.household_id = h.id
+WHERE h.id = $1 AND m.user_id = $2;
+
+-- name: ListHouseholds :many
+SELECT h.id, h.name, h.created_at, h.updated_at, m.role
+FROM households h
+JOIN household_members m ON m.household_id = h.id
+WHERE m.user_id = $1
+ORDER BY h.name;
+
+-- name: UpdateHousehold :one
+UPDATE households
+SET name = $2, updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteHousehold :exec
+-- Shared accounts and categories stay with their owners, unshared.
+DELETE FROM households WHERE id = $1;
+
+-- name: AddHouseholdMember :one
+INSERT INTO household_members (household_id, user_id, role)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: ListHouseholdMembers :many
+SELECT m.household_id, m.user_id, m.role, m.created_at, u.username, u.display_name
+FROM household_members m
+JOIN users u ON u.id = m.user_id
+WHERE m.household_id = $1
+ORDER BY m.created_at, u.username;
+
+-- name: UpdateHouseholdMemberRole :one
+UPDATE household_members
+SET role = $3
+WHERE household_id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: DeleteHouseholdMember :execrows
+DELETE FROM household_members WHERE household_id = $1 AND user_id = $2;
@@ -0,0 +1,26 @@
+-- name: CreateInviteCode :one
+INSERT INTO invite_codes (code, created_by, max_uses, expires_at)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: EnsureInviteCode :exec
+-- Adds a code from the INVITE_CODES setting; a code already in the table,
+-- possibly revoked, is left alone.
+INSERT INTO invite_codes (code) VALUES ($1)
+ON CONFLICT (code) DO NOTHING;
+
+-- name: ListInviteCodes :many
+SELECT * FROM invite_codes ORDER BY created_at DESC, code;
+
+-- name: ListInviteRedemptions :many
+-- Users who registered with the given codes, oldest first.
+SELECT invite_code, id, username, created_at
+FROM users
+WHERE invite_code = ANY(@codes::TEXT[])
+ORDER BY created_at, id;
+
+-- name: RevokeInviteCode :one
+UPDATE invite_codes
+SET revoked_at = COALESCE(revoked_at, now())
+WHERE code = $1
+RETURNING *;
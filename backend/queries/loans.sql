@@ -0,0 +1,44 @@
+-- name: GetLoan :one
+SELECT * FROM loans WHERE account_id = $1 AND user_id = $2;
+
+-- name: UpsertLoan :one
+INSERT INTO loans (account_id, user_id, principal, annual_rate, term_months, start_date, payment_day, interest_category_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+ON CONFLICT (account_id) DO UPDATE
+SET principal = EXCLUDED.principal,
+    annual_rate = EXCLUDED.annual_rate,
+    term_months = EXCLUDED.term_months,
+    start_date = EXCLUDED.start_date,
+    payment_day = EXCLUDED.payment_day,
+    interest_category_id = EXCLUDED.interest_category_id,
+    updated_at = now()
+RETURNING *;
+
+-- name: IsLoanPayment :one
+-- Whether the transaction is a loan payment that has been split.
+SELECT EXISTS (SELECT 1 FROM loan_payments WHERE payment_id = $1)::BOOLEAN AS is_loan_payment;
+
+-- name: ListUnsplitLoanPayments :many
+-- Transfers into the loan account that have not been split yet, oldest first.
+SELECT t.* FROM transactions t
+WHERE t.account_id = @account_id
+    AND t.user_id = @user_id
+    AND t.type = 'income'
+    AND t.deleted_at IS NULL
+    AND t.transfer_id IS NOT NULL
+    AND NOT EXISTS (SELECT 1 FROM loan_payments lp WHERE lp.payment_id = t.id)
+ORDER BY t.date, t.created_at;
+
+-- name: CreateLoanPayment :one
+INSERT INTO loan_payments (payment_id, account_id, user_id, interest_id, principal, interest)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetLoanPaymentTotals :one
+SELECT
+    COALESCE(SUM(lp.principal), 0)::DECIMAL(24,8) AS principal_paid,
+    COALESCE(SUM(lp.interest), 0)::DECIMAL(24,8) AS interest_paid,
+    COUNT(*)::INTEGER AS count
+FROM loan_payments lp
+JOIN transactions t ON t.id = lp.payment_id
+WHERE lp.account_id = $1 AND lp.user_id = $2 AND t.deleted_at IS NULL;
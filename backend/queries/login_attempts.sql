@@ -0,0 +1,26 @@
+-- name: RecordLoginAttempt :exec
+INSERT INTO login_attempts (username, user_id, ip, user_agent, outcome)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: GetLoginFailures :one
+-- Failed attempts on the username after @since and after its last
+-- successful login, with the time of the latest one.
+SELECT COUNT(*) AS failures, MAX(created_at)::TIMESTAMPTZ AS last_failure
+FROM login_attempts
+WHERE username = @username AND outcome = 'failure' AND created_at > @since
+    AND created_at > COALESCE((SELECT MAX(s.created_at) FROM login_attempts s
+                               WHERE s.username = @username AND s.outcome = 'success'), '-infinity');
+
+-- name: LockLoginUsername :exec
+-- Serializes login attempts on a username until the transaction ends.
+SELECT pg_advisory_xact_lock(hashtext(@username));
+
+-- name: ListLoginAttempts :many
+SELECT * FROM login_attempts
+WHERE user_id = $1
+ORDER BY created_at DESC
+LIMIT $2;
+
+-- name: PruneLoginAttempts :execrows
+-- Deletes attempts older than the retention period.
+DELETE FROM login_attempts WHERE created_at < $1;
@@ -0,0 +1,36 @@
+-- name: CreateReconciliation :one
+INSERT INTO reconciliations (user_id, account_id, statement_date, statement_balance)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetReconciliation :one
+SELECT * FROM reconciliations WHERE id = $1 AND user_id = $2;
+
+-- name: ListReconciliations :many
+SELECT * FROM reconciliations
+WHERE account_id = $1 AND user_id = $2
+ORDER BY statement_date DESC, created_at DESC;
+
+-- name: FinishReconciliation :one
+UPDATE reconciliations
+SET finished_at = now(), reconciled_count = $3
+WHERE id = $1 AND user_id = $2 AND finished_at IS NULL
+RETURNING *;
+
+-- name: DeleteReconciliation :execrows
+DELETE FROM reconciliations WHERE id = $1 AND user_id = $2 AND finished_at IS NULL;
+
+-- name: GetClearedBalance :one
+-- Initial balance plus every cleared or reconciled transaction up to the
+-- statement date: what the bank statement should show.
+SELECT
+    (a.initial_balance + COALESCE(SUM(CASE WHEN t.type = 'income' THEN t.amount ELSE -t.amount END), 0))::DECIMAL(24,8) AS cleared_balance,
+    COUNT(t.id)::INTEGER AS cleared_count
+FROM accounts a
+LEFT JOIN transactions t
+    ON t.account_id = a.id
+    AND t.status IN ('cleared', 'reconciled')
+    AND t.date <= @statement_date
+    AND t.deleted_at IS NULL
+WHERE a.id = @account_id AND a.user_id = @user_id
+GROUP BY a.id, a.initial_balance;
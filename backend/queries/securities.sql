@@ -0,0 +1,43 @@
+-- name: CreateSecurity :one
+INSERT INTO securities (user_id, symbol, name, currency)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetSecurity :one
+SELECT * FROM securities WHERE id = $1 AND user_id = $2;
+
+-- name: ListSecurities :many
+-- Securities with their latest quote, if any.
+SELECT s.*, q.date AS price_date, q.price
+FROM securities s
+LEFT JOIN security_quotes q ON q.security_id = s.id
+WHERE s.user_id = $1
+ORDER BY s.symbol;
+
+-- name: UpdateSecurity :one
+UPDATE securities
+SET symbol = $3, name = $4
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: DeleteSecurity :execrows
+DELETE FROM securities WHERE id = $1 AND user_id = $2;
+
+-- name: DeleteAllUserSecurities :exec
+DELETE FROM securities WHERE user_id = $1;
+
+-- name: HasSecurityTrades :one
+SELECT EXISTS(SELECT 1 FROM trades WHERE security_id = $1) AS has_trades;
+
+-- name: ListSecurityPrices :many
+SELECT p.* FROM security_prices p
+JOIN securities s ON s.id = p.security_id
+WHERE p.security_id = $1 AND s.user_id = $2
+ORDER BY p.date DESC;
+
+-- name: UpsertSecurityPrice :one
+INSERT INTO security_prices (security_id, date, price, source)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (security_id, date) DO UPDATE
+SET price = EXCLUDED.price, source = EXCLUDED.source
+RETURNING *;
@@ -0,0 +1,21 @@
+-- name: CreateTrade :one
+INSERT INTO trades (id, user_id, account_id, security_id, type, date, quantity, price, fee, transaction_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING *;
+
+-- name: GetTrade :one
+SELECT * FROM trades WHERE id = $1 AND user_id = $2;
+
+-- name: IsTradeTransaction :one
+-- Whether the transaction is the cash side of a trade.
+SELECT EXISTS (SELECT 1 FROM trades WHERE transaction_id = $1)::BOOLEAN AS is_trade;
+
+-- name: ListTrades :many
+-- Trades of an account in the order they happened, with the cash amount of
+-- their transaction.
+SELECT tr.*, s.symbol, tx.amount
+FROM trades tr
+JOIN securities s ON s.id = tr.security_id
+JOIN transactions tx ON tx.id = tr.transaction_id
+WHERE tr.account_id = $1 AND tr.user_id = $2 AND tx.deleted_at IS NULL
+ORDER BY tr.date, tr.created_at;
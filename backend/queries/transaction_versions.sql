@@ -0,0 +1,17 @@
+-- name: BulkCreateTransactionVersions :copyfrom
+INSERT INTO transaction_versions (transaction_id, actor_id, action, changes)
+VALUES ($1, $2, $3, $4);
+
+-- name: CreateTransactionVersion :exec
+INSERT INTO transaction_versions (transaction_id, actor_id, action, changes)
+VALUES ($1, $2, $3, $4);
+
+-- name: ListTransactionVersions :many
+-- The history of a transaction and, for a transfer, of its other leg,
+-- oldest first, with the username of whoever made each change.
+SELECT v.id, v.transaction_id, v.actor_id, COALESCE(u.username, '')::VARCHAR AS actor_username, v.action, v.changes, v.created_at
+FROM transaction_versions v
+LEFT JOIN users u ON u.id = v.actor_id
+WHERE v.transaction_id = @transaction_id
+    OR v.transaction_id IN (SELECT t.id FROM transactions t WHERE t.transfer_id = sqlc.narg('transfer_id'))
+ORDER BY v.created_at, v.transaction_id;
@@ -1,10 +1,18 @@
 -- name: CreateTransaction :one
-INSERT INTO transactions (user_id, account_id, category_id, type, amount, description, date, transfer_id, exchange_rate)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+INSERT INTO transactions (user_id, account_id, category_id, type, amount, description, date, transfer_id, exchange_rate, created_by)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 RETURNING *;
 
+-- name: GetAccessibleTransaction :one
+-- Returns a transaction in an account the user owns or shares through a
+-- household, with the user's role on that account.
+SELECT sqlc.embed(t), aa.role
+FROM transactions t
+JOIN account_access aa ON aa.account_id = t.account_id
+WHERE t.id = @id AND aa.user_id = @user_id AND t.deleted_at IS NULL;
+
 -- name: GetTransaction :one
-SELECT * FROM transactions WHERE id = $1 AND user_id = $2;
+SELECT * FROM transactions WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL;
 
 -- name: ListTransactions :many
 WITH RECURSIVE expanded_categories AS (
@@ -14,14 +22,17 @@ WITH RECURSIVE expanded_categories AS (
     SELECT c.id FROM categories c
     INNER JOIN expanded_categories ec ON c.parent_id = ec.id
 )
-SELECT t.id, t.user_id, t.account_id, t.category_id, t.type, t.amount, t.description, t.date, t.transfer_id, t.exchange_rate, t.created_at, t.updated_at FROM transactions t
-WHERE t.user_id = @user_id
+SELECT t.id, t.user_id, t.account_id, t.category_id, t.type, t.amount, t.description, t.date, t.transfer_id, t.exchange_rate, t.created_at, t.updated_at, t.status, t.created_by, t.deleted_at FROM transactions t
+WHERE t.account_id IN (SELECT account_id FROM account_access WHERE user_id = @user_id)
+    AND t.deleted_at IS NULL
     AND (cardinality(@account_ids::UUID[]) = 0 OR t.account_id = ANY(@account_ids))
     AND (cardinality(@category_ids::UUID[]) = 0 OR t.category_id IN (SELECT id FROM expanded_categories))
     AND (sqlc.narg('type')::VARCHAR IS NULL OR t.type = sqlc.narg('type'))
     AND (sqlc.narg('date_from')::DATE IS NULL OR t.date >= sqlc.narg('date_from'))
     AND (sqlc.narg('date_to')::DATE IS NULL OR t.date <= sqlc.narg('date_to'))
     AND (sqlc.narg('description')::TEXT IS NULL OR t.description ILIKE '%' || sqlc.narg('description') || '%')
+    AND (sqlc.narg('status')::VARCHAR IS NULL OR t.status = sqlc.narg('status'))
+    AND (cardinality(@group_ids::UUID[]) = 0 OR t.account_id IN (SELECT id FROM accounts WHERE group_id = ANY(@group_ids)))
 ORDER BY t.date DESC, t.created_at DESC
 LIMIT @lim OFFSET @off;
 
@@ -34,25 +45,95 @@ WITH RECURSIVE expanded_categories AS (
     INNER JOIN expanded_categories ec ON c.parent_id = ec.id
 )
 SELECT COUNT(*) FROM transactions t
-WHERE t.user_id = @user_id
+WHERE t.account_id IN (SELECT account_id FROM account_access WHERE user_id = @user_id)
+    AND t.deleted_at IS NULL
     AND (cardinality(@account_ids::UUID[]) = 0 OR t.account_id = ANY(@account_ids))
     AND (cardinality(@category_ids::UUID[]) = 0 OR t.category_id IN (SELECT id FROM expanded_categories))
     AND (sqlc.narg('type')::VARCHAR IS NULL OR t.type = sqlc.narg('type'))
     AND (sqlc.narg('date_from')::DATE IS NULL OR t.date >= sqlc.narg('date_from'))
     AND (sqlc.narg('date_to')::DATE IS NULL OR t.date <= sqlc.narg('date_to'))
-    AND (sqlc.narg('description')::TEXT IS NULL OR t.description ILIKE '%' || sqlc.narg('description') || '%');
+    AND (sqlc.narg('description')::TEXT IS NULL OR t.description ILIKE '%' || sqlc.narg('description') || '%')
+    AND (sqlc.narg('status')::VARCHAR IS NULL OR t.status = sqlc.narg('status'))
+    AND (cardinality(@group_ids::UUID[]) = 0 OR t.account_id IN (SELECT id FROM accounts WHERE group_id = ANY(@group_ids)));
 
 -- name: UpdateTransaction :one
 UPDATE transactions
 SET account_id = $2, category_id = $3, type = $4, amount = $5, description = $6, date = $7, updated_at = now()
-WHERE id = $1 AND user_id = sqlc.arg(user_id)
+WHERE id = $1 AND user_id = sqlc.arg(user_id) AND deleted_at IS NULL
 RETURNING *;
 
 -- name: DeleteTransaction :exec
+-- Deletes for good, skipping the trash: for trades, which own their
+-- transaction.
 DELETE FROM transactions WHERE id = $1 AND user_id = $2;
 
--- name: DeleteTransactionByTransferID :exec
-DELETE FROM transactions WHERE transfer_id = $1 AND user_id = $2;
+-- name: TrashTransaction :exec
+UPDATE transactions SET deleted_at = now()
+WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL;
+
+-- name: TrashTransfer :exec
+-- Moves both legs of a transfer to the trash, along with the interest
+-- transaction split off a loan payment.
+UPDATE transactions SET deleted_at = now()
+WHERE user_id = @user_id AND deleted_at IS NULL
+    AND (transfer_id = @transfer_id
+        OR id IN (
+            SELECT lp.interest_id FROM loan_payments lp
+            JOIN transactions p ON p.id = lp.payment_id
+            WHERE p.transfer_id = @transfer_id
+        ));
+
+-- name: ListTrashedTransactions :many
+-- The user's trashed transactions, most recently deleted first. Those
+-- trashed along with an account are left out: they come back with it.
+SELECT sqlc.embed(t), a.currency
+FROM transactions t
+JOIN accounts a ON a.id = t.account_id
+WHERE t.user_id = @user_id AND t.deleted_at IS NOT NULL
+    AND NOT EXISTS (SELECT 1 FROM accounts ta WHERE ta.user_id = t.user_id AND ta.deleted_at = t.deleted_at)
+ORDER BY t.deleted_at DESC, t.date DESC;
+
+-- name: GetTrashedTransaction :one
+-- A trashed transaction with whether it can be restored on its own: it must
+-- not have gone with an account, and the account and category of each
+-- transaction trashed with it must not be in the trash.
+SELECT sqlc.embed(t), NOT EXISTS (
+    SELECT 1 FROM transactions o
+    JOIN accounts a ON a.id = o.account_id
+    LEFT JOIN categories c ON c.id = o.category_id
+    WHERE o.user_id = t.user_id AND o.deleted_at = t.deleted_at
+        AND (o.id = t.id OR o.transfer_id = t.transfer_id)
+        AND (a.deleted_at IS NOT NULL OR c.deleted_at IS NOT NULL)
+) AND NOT EXISTS (
+    SELECT 1 FROM accounts ta WHERE ta.user_id = t.user_id AND ta.deleted_at = t.deleted_at
+) AS restorable
+FROM transactions t
+WHERE t.id = @id AND t.user_id = @user_id AND t.deleted_at IS NOT NULL;
+
+-- name: RestoreTransaction :execrows
+-- Brings a trashed transaction back together with what was trashed with it:
+-- the other leg of a transfer and the interest split off a loan payment. Each
+-- gets a restore version by the user; the count is of those restored.
+WITH restored AS (
+    UPDATE transactions t SET deleted_at = NULL
+    FROM transactions r
+    WHERE r.id = @id AND r.user_id = @user_id AND r.deleted_at IS NOT NULL
+        AND t.user_id = r.user_id AND t.deleted_at = r.deleted_at
+        AND (t.id = r.id
+            OR t.transfer_id = r.transfer_id
+            OR t.id IN (
+                SELECT lp.interest_id FROM loan_payments lp
+                JOIN transactions p ON p.id = lp.payment_id
+                WHERE p.transfer_id = r.transfer_id
+            ))
+    RETURNING t.id
+)
+INSERT INTO transaction_versions (transaction_id, actor_id, action)
+SELECT id, @user_id, 'restore' FROM restored;
+
+-- name: PurgeTransactions :execrows
+-- Hard-deletes transactions trashed before the cutoff.
+DELETE FROM transactions WHERE deleted_at < $1;
 
 -- name: SpendingByCategory :many
 WITH raw_spending AS (
@@ -60,20 +141,22 @@ WITH raw_spending AS (
         c.id AS category_id,
         c.name AS category_name,
         c.parent_id,
-        COALESCE(SUM(t.amount), 0)::DECIMAL(15,2) AS total
+        COALESCE(SUM(t.amount), 0)::DECIMAL(24,8) AS total
     FROM transactions t
     JOIN categories c ON t.category_id = c.id
-    WHERE t.user_id = @user_id
+    WHERE t.account_id IN (SELECT account_id FROM account_access WHERE user_id = @user_id)
+        AND t.deleted_at IS NULL
         AND t.type = 'expense'
         AND t.date >= @date_from
         AND t.date <= @date_to
         AND t.transfer_id IS NULL
+        AND NOT EXISTS (SELECT 1 FROM trades tr WHERE tr.transaction_id = t.id AND tr.type <> 'dividend')
     GROUP BY c.id, c.name, c.parent_id
 ),
 parent_rollup AS (
     SELECT
         COALESCE(rs.parent_id, rs.category_id) AS category_id,
-        SUM(rs.total)::DECIMAL(15,2) AS total
+        SUM(rs.total)::DECIMAL(24,8) AS total
     FROM raw_spending rs
     GROUP BY COALESCE(rs.parent_id, rs.category_id)
 )
@@ -100,10 +183,12 @@ ORDER BY parent_id NULLS FIRST, total DESC;
 -- name: MonthlyIncomeExpense :many
 SELECT
     date_trunc('month', date)::DATE AS month,
-    COALESCE(SUM(CASE WHEN type = 'income' AND transfer_id IS NULL THEN amount ELSE 0 END), 0)::DECIMAL(15,2) AS income,
-    COALESCE(SUM(CASE WHEN type = 'expense' AND transfer_id IS NULL THEN amount ELSE 0 END), 0)::DECIMAL(15,2) AS expense
+    COALESCE(SUM(CASE WHEN type = 'income' AND transfer_id IS NULL THEN amount ELSE 0 END), 0)::DECIMAL(24,8) AS income,
+    COALESCE(SUM(CASE WHEN type = 'expense' AND transfer_id IS NULL THEN amount ELSE 0 END), 0)::DECIMAL(24,8) AS expense
 FROM transactions
-WHERE user_id = @user_id
+WHERE account_id IN (SELECT account_id FROM account_access WHERE user_id = @user_id)
+    AND deleted_at IS NULL
+    AND NOT EXISTS (SELECT 1 FROM trades tr WHERE tr.transaction_id = transactions.id AND tr.type <> 'dividend')
     AND date >= @date_from
     AND date <= @date_to
 GROUP BY date_trunc('month', date)
@@ -111,10 +196,12 @@ ORDER BY month;
 
 -- name: DashboardSummary :one
 SELECT
-    COALESCE(SUM(CASE WHEN type = 'income' AND transfer_id IS NULL THEN amount ELSE 0 END), 0)::DECIMAL(15,2) AS total_income,
-    COALESCE(SUM(CASE WHEN type = 'expense' AND transfer_id IS NULL THEN amount ELSE 0 END), 0)::DECIMAL(15,2) AS total_expense
+    COALESCE(SUM(CASE WHEN type = 'income' AND transfer_id IS NULL THEN amount ELSE 0 END), 0)::DECIMAL(24,8) AS total_income,
+    COALESCE(SUM(CASE WHEN type = 'expense' AND transfer_id IS NULL THEN amount ELSE 0 END), 0)::DECIMAL(24,8) AS total_expense
 FROM transactions
-WHERE user_id = @user_id
+WHERE account_id IN (SELECT account_id FROM account_access WHERE user_id = @user_id)
+    AND deleted_at IS NULL
+    AND NOT EXISTS (SELECT 1 FROM trades tr WHERE tr.transaction_id = transactions.id AND tr.type <> 'dividend')
     AND date >= @date_from
     AND date <= @date_to;
 
@@ -122,10 +209,11 @@ WHERE user_id = @user_id
 WITH daily AS (
     SELECT
         t.date,
-        SUM(CASE WHEN t.type = 'income' THEN t.amount ELSE -t.amount END)::DECIMAL(15,2) AS daily_change
+        SUM(CASE WHEN t.type = 'income' THEN t.amount ELSE -t.amount END)::DECIMAL(24,8) AS daily_change
     FROM transactions t
     WHERE t.account_id = @account_id
         AND t.user_id = @user_id
+        AND t.deleted_at IS NULL
         AND t.date >= @date_from
         AND t.date <= @date_to
     GROUP BY t.date
@@ -133,19 +221,20 @@ WITH daily AS (
 start_balance AS (
     SELECT
         a.initial_balance
-        + COALESCE(SUM(CASE WHEN t.type = 'income' THEN t.amount ELSE -t.amount END), 0)::DECIMAL(15,2) AS balance
+        + COALESCE(SUM(CASE WHEN t.type = 'income' THEN t.amount ELSE -t.amount END), 0)::DECIMAL(24,8) AS balance
     FROM accounts a
     LEFT JOIN transactions t
         ON t.account_id = a.id
         AND t.user_id = a.user_id
         AND t.date < @date_from
+        AND t.deleted_at IS NULL
     WHERE a.id = @account_id
         AND a.user_id = @user_id
     GROUP BY a.initial_balance
 )
 SELECT
     d.date,
-    (sb.balance + SUM(d.daily_change) OVER (ORDER BY d.date))::DECIMAL(15,2) AS balance
+    (sb.balance + SUM(d.daily_change) OVER (ORDER BY d.date))::DECIMAL(24,8) AS balance
 FROM daily d
 CROSS JOIN start_balance sb
 ORDER BY d.date;
@@ -153,7 +242,8 @@ ORDER BY d.date;
 -- name: ListTransactionYears :many
 SELECT DISTINCT EXTRACT(YEAR FROM date)::INT AS year
 FROM transactions
-WHERE user_id = @user_id
+WHERE account_id IN (SELECT account_id FROM account_access WHERE user_id = @user_id)
+    AND deleted_at IS NULL
 ORDER BY year DESC;
 
 -- name: CashFlowCategoryMonthly :many
@@ -162,13 +252,15 @@ SELECT
     t.type,
     date_trunc('month', t.date)::DATE AS month,
     a.currency,
-    COALESCE(SUM(t.amount), 0)::DECIMAL(15,2) AS amount
+    COALESCE(SUM(t.amount), 0)::DECIMAL(24,8) AS amount
 FROM transactions t
 JOIN accounts a ON a.id = t.account_id
-WHERE t.user_id = @user_id
+WHERE t.account_id IN (SELECT account_id FROM account_access WHERE user_id = @user_id)
+    AND t.deleted_at IS NULL
     AND t.date >= @date_from
     AND t.date <= @date_to
     AND t.transfer_id IS NULL
+    AND NOT EXISTS (SELECT 1 FROM trades tr WHERE tr.transaction_id = t.id AND tr.type <> 'dividend')
 GROUP BY t.category_id, t.type, date_trunc('month', t.date), a.currency;
 
 -- name: CashFlowAccountOpeningBalances :many
@@ -177,13 +269,14 @@ SELECT
     a.currency,
     (a.initial_balance + COALESCE(SUM(
         CASE WHEN t.type = 'income' THEN t.amount ELSE -t.amount END
-    ), 0))::DECIMAL(15,2) AS opening_balance
+    ), 0))::DECIMAL(24,8) AS opening_balance
 FROM accounts a
 LEFT JOIN transactions t
     ON t.account_id = a.id
     AND t.user_id = a.user_id
     AND t.date < @date_from
-WHERE a.user_id = @user_id
+    AND t.deleted_at IS NULL
+WHERE a.id IN (SELECT account_id FROM account_access WHERE user_id = @user_id)
 GROUP BY a.id, a.currency, a.initial_balance;
 
 -- name: CashFlowAccountMonthlyChanges :many
@@ -193,30 +286,57 @@ SELECT
     date_trunc('month', t.date)::DATE AS month,
     COALESCE(SUM(
         CASE WHEN t.type = 'income' THEN t.amount ELSE -t.amount END
-    ), 0)::DECIMAL(15,2) AS net_change
+    ), 0)::DECIMAL(24,8) AS net_change
 FROM transactions t
 JOIN accounts a ON a.id = t.account_id
-WHERE t.user_id = @user_id
+WHERE t.account_id IN (SELECT account_id FROM account_access WHERE user_id = @user_id)
+    AND t.deleted_at IS NULL
     AND t.date >= @date_from
     AND t.date <= @date_to
 GROUP BY t.account_id, a.currency, date_trunc('month', t.date);
 
+-- name: ListForeignCurrencyFlows :many
+-- Transactions in the period on accounts the user can see whose currency
+-- differs from the user's base currency, oldest first, with the other leg of
+-- transfers.
+SELECT
+    t.id,
+    t.account_id,
+    t.type,
+    t.amount,
+    t.date,
+    t.transfer_id,
+    t.exchange_rate,
+    ca.currency AS counter_currency,
+    c.amount AS counter_amount
+FROM transactions t
+JOIN accounts a ON a.id = t.account_id
+JOIN users u ON u.id = @user_id
+LEFT JOIN transactions c ON c.transfer_id = t.transfer_id AND c.id <> t.id AND c.deleted_at IS NULL
+LEFT JOIN accounts ca ON ca.id = c.account_id
+WHERE t.account_id IN (SELECT account_id FROM account_access WHERE user_id = @user_id)
+    AND t.deleted_at IS NULL
+    AND t.date >= @date_from
+    AND t.date <= @date_to
+    AND a.currency <> u.base_currency
+ORDER BY t.date, t.created_at, t.id;
+
 -- name: GetTransactionsByTransferID :many
-SELECT * FROM transactions WHERE transfer_id = $1 AND user_id = $2;
+SELECT * FROM transactions WHERE transfer_id = $1 AND user_id = $2 AND deleted_at IS NULL;
 
 -- name: UpdateTransferTransaction :one
 UPDATE transactions
 SET account_id = $2, amount = $3, description = $4, date = $5, exchange_rate = $6, updated_at = now()
-WHERE id = $1 AND user_id = $7
+WHERE id = $1 AND user_id = $7 AND deleted_at IS NULL
 RETURNING *;
 
 -- name: BulkCreateTransactions :copyfrom
-INSERT INTO transactions (user_id, account_id, category_id, type, amount, description, date)
-VALUES ($1, $2, $3, $4, $5, $6, $7);
+INSERT INTO transactions (id, user_id, account_id, category_id, type, amount, description, date)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
 
 -- name: BulkCreateTransactionsFull :copyfrom
-INSERT INTO transactions (user_id, account_id, category_id, type, amount, description, date, transfer_id, exchange_rate)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);
+INSERT INTO transactions (id, user_id, account_id, category_id, type, amount, description, date, transfer_id, exchange_rate)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10);
 
 -- name: DeleteAllUserTransactions :exec
 DELETE FROM transactions WHERE user_id = $1;
@@ -226,7 +346,24 @@ SELECT description
 FROM transactions
 WHERE user_id = @user_id
   AND description <> ''
+  AND deleted_at IS NULL
   AND description ILIKE '%' || @search::TEXT || '%'
 GROUP BY description
 ORDER BY MAX(date) DESC, MAX(created_at) DESC
 LIMIT 15;
+
+-- name: SetTransactionStatus :one
+UPDATE transactions
+SET status = $3, updated_at = now()
+WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: MarkTransactionsReconciled :execrows
+-- Locks the cleared transactions a finished reconciliation covered.
+UPDATE transactions
+SET status = 'reconciled', updated_at = now()
+WHERE account_id = @account_id
+    AND user_id = @user_id
+    AND status = 'cleared'
+    AND deleted_at IS NULL
+    AND date <= @statement_date;
@@ -1,6 +1,19 @@
 -- name: CreateUser :one
+-- Redeems the invite code and creates the user in one statement. No row
+-- comes back when the code is unknown, revoked, expired or used up; a
+-- duplicate username fails the whole statement, so the use isn't counted.
+WITH invite AS (
+    UPDATE invite_codes
+    SET uses = uses + 1
+    WHERE code = @invite_code
+        AND revoked_at IS NULL
+        AND (expires_at IS NULL OR expires_at > now())
+        AND (max_uses IS NULL OR uses < max_uses)
+    RETURNING code
+)
 INSERT INTO users (username, password_hash, display_name, base_currency, invite_code)
-VALUES ($1, $2, $3, $4, $5)
+SELECT @username, @password_hash, @display_name, @base_currency, invite.code
+FROM invite
 RETURNING *;
 
 -- name: GetUserByUsername :one
@@ -9,6 +22,25 @@ SELECT * FROM users WHERE username = $1;
 -- name: GetUserByID :one
 SELECT * FROM users WHERE id = $1;
 
+-- name: ListUsers :many
+-- Every user on the instance with how much data they have, oldest first.
+SELECT u.id, u.username, u.display_name, u.base_currency, u.is_admin, u.disabled_at, u.created_at,
+    (SELECT COUNT(*) FROM accounts a WHERE a.user_id = u.id) AS account_count,
+    (SELECT COUNT(*) FROM transactions t WHERE t.user_id = u.id) AS transaction_count
+FROM users u
+ORDER BY u.created_at, u.id;
+
+-- name: SetUserDisabled :one
+-- Disabling keeps the original disabled_at; enabling clears it.
+UPDATE users
+SET disabled_at = CASE WHEN @disabled::boolean THEN COALESCE(disabled_at, now()) END, updated_at = now()
+WHERE id = @id
+RETURNING *;
+
+-- name: PromoteAdmins :execrows
+UPDATE users SET is_admin = true, updated_at = now()
+WHERE username = ANY(@usernames::TEXT[]) AND NOT is_admin;
+
 -- name: UpdateUser :one
 UPDATE users
 SET display_name = $2, base_currency = $3, updated_at = now()